@@ -0,0 +1,7 @@
+-- +goose Up
+CREATE INDEX idx_tuple_store_ulid ON tuple (store, ulid) LOCK = NONE;
+CREATE INDEX idx_tuple_store_condition ON tuple (store, condition_name, ulid) LOCK = NONE;
+
+-- +goose Down
+DROP INDEX idx_tuple_store_condition ON tuple LOCK = NONE;
+DROP INDEX idx_tuple_store_ulid ON tuple LOCK = NONE;
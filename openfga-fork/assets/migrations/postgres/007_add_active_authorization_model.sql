@@ -0,0 +1,10 @@
+-- +goose Up
+CREATE TABLE active_authorization_model (
+	store TEXT NOT NULL,
+	authorization_model_id TEXT NOT NULL,
+	updated_at TIMESTAMPTZ NOT NULL,
+	PRIMARY KEY (store)
+);
+
+-- +goose Down
+DROP TABLE active_authorization_model;
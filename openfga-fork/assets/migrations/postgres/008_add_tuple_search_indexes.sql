@@ -0,0 +1,8 @@
+-- +goose NO TRANSACTION
+-- +goose Up
+CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_tuple_store_ulid ON tuple (store, ulid);
+CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_tuple_store_condition ON tuple (store, condition_name, ulid);
+
+-- +goose Down
+DROP INDEX CONCURRENTLY IF EXISTS idx_tuple_store_condition;
+DROP INDEX CONCURRENTLY IF EXISTS idx_tuple_store_ulid;
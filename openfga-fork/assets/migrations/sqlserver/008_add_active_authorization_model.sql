@@ -0,0 +1,10 @@
+-- +goose Up
+CREATE TABLE active_authorization_model (
+    store CHAR(26) NOT NULL,
+    authorization_model_id CHAR(26) NOT NULL,
+    updated_at DATETIME2 NOT NULL,
+    CONSTRAINT PK_active_authorization_model PRIMARY KEY (store)
+);
+
+-- +goose Down
+DROP TABLE active_authorization_model;
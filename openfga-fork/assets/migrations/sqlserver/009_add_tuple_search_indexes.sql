@@ -0,0 +1,7 @@
+-- +goose Up
+CREATE INDEX idx_tuple_store_ulid ON tuple (store, ulid);
+CREATE INDEX idx_tuple_store_condition ON tuple (store, condition_name, ulid);
+
+-- +goose Down
+DROP INDEX idx_tuple_store_condition ON tuple;
+DROP INDEX idx_tuple_store_ulid ON tuple;
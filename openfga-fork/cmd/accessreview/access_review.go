@@ -0,0 +1,110 @@
+// Package accessreview contains the command to report the permissions gained and lost in a store over a period of time.
+package accessreview
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	apiURLFlag     = "api-url"
+	apiTokenFlag   = "api-token"
+	storeIDFlag    = "store-id"
+	modelIDFlag    = "model-id"
+	objectTypeFlag = "object-type"
+	relationFlag   = "relation"
+	fromFlag       = "from"
+	toFlag         = "to"
+	formatFlag     = "format"
+
+	errorTrailer = "Openfga-Error"
+)
+
+func NewAccessReviewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "access-review",
+		Short: "Report which users gained or lost access to the objects of a type between two points in time.",
+		Long: "Report the effective (user, relation, object) permissions gained and lost between two positions\n" +
+			"of the changelog of a store, as CSV or JSON Lines. Positions are changelog ULIDs or RFC 3339 timestamps.\n" +
+			"The report is computed by a running OpenFGA server, so it is subject to the Check, ListObjects and\n" +
+			"ListUsers deadlines and to the maximum number of changelog entries configured on that server.",
+		RunE: runAccessReview,
+		Args: cobra.NoArgs,
+	}
+
+	flags := cmd.Flags()
+	flags.String(apiURLFlag, "http://localhost:8080", "the URL of the OpenFGA HTTP API")
+	flags.String(apiTokenFlag, "", "the bearer token used to authenticate to the OpenFGA HTTP API")
+	flags.String(storeIDFlag, "", "the id of the store")
+	flags.String(modelIDFlag, "", "the id of the authorization model. If empty, the active model of the store is used")
+	flags.String(objectTypeFlag, "", "the type of the reviewed objects")
+	flags.StringSlice(relationFlag, nil, "the reviewed relations. If empty, all relations of the object type are reviewed")
+	flags.String(fromFlag, "", "the start of the review, as a changelog ULID or an RFC 3339 timestamp")
+	flags.String(toFlag, "", "the end of the review, as a changelog ULID or an RFC 3339 timestamp. If empty, the review ends now")
+	flags.String(formatFlag, "csv", "the output format, 'csv' or 'jsonl'")
+
+	// NOTE: if you add a new flag here, update the function below, too
+
+	cmd.PreRun = bindRunFlagsFunc(flags)
+
+	return cmd
+}
+
+func runAccessReview(cmd *cobra.Command, _ []string) error {
+	storeID := viper.GetString(storeIDFlag)
+	if storeID == "" {
+		return fmt.Errorf("missing '--%s'", storeIDFlag)
+	}
+	if viper.GetString(fromFlag) == "" {
+		return fmt.Errorf("missing '--%s'", fromFlag)
+	}
+
+	params := url.Values{
+		"object_type": {viper.GetString(objectTypeFlag)},
+		"relation":    viper.GetStringSlice(relationFlag),
+		"from":        {viper.GetString(fromFlag)},
+		"format":      {viper.GetString(formatFlag)},
+	}
+	if to := viper.GetString(toFlag); to != "" {
+		params.Set("to", to)
+	}
+	if modelID := viper.GetString(modelIDFlag); modelID != "" {
+		params.Set("authorization_model_id", modelID)
+	}
+
+	endpoint := strings.TrimSuffix(viper.GetString(apiURLFlag), "/") + "/stores/" + url.PathEscape(storeID) + "/access-review"
+	req, err := http.NewRequest(http.MethodGet, endpoint+"?"+params.Encode(), nil)
+	if err != nil {
+		return err
+	}
+	if token := viper.GetString(apiTokenFlag); token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to request the access review: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("failed to request the access review: %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	if _, err := io.Copy(cmd.OutOrStdout(), resp.Body); err != nil {
+		return fmt.Errorf("failed to read the access review: %w", err)
+	}
+
+	// The trailer is only available once the body has been read.
+	if msg := resp.Trailer.Get(errorTrailer); msg != "" {
+		return fmt.Errorf("the access review is incomplete: %s", msg)
+	}
+	return nil
+}
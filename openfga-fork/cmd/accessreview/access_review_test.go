@@ -0,0 +1,64 @@
+package accessreview
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccessReviewCommand(t *testing.T) {
+	run := func(t *testing.T, handler http.HandlerFunc, args ...string) (string, error) {
+		server := httptest.NewServer(handler)
+		t.Cleanup(server.Close)
+
+		cmd := NewAccessReviewCommand()
+		out := &bytes.Buffer{}
+		cmd.SetOut(out)
+		cmd.SetArgs(append([]string{
+			"--api-url", server.URL, "--api-token", "secret", "--store-id", "store", "--object-type", "document",
+			"--from", "2024-01-01T00:00:00Z", "--to", "2024-04-01T00:00:00Z",
+		}, args...))
+		err := cmd.Execute()
+		return out.String(), err
+	}
+
+	t.Run("writes_report", func(t *testing.T) {
+		out, err := run(t, func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, "/stores/store/access-review", r.URL.Path)
+			require.Equal(t, "document", r.URL.Query().Get("object_type"))
+			require.Equal(t, "2024-01-01T00:00:00Z", r.URL.Query().Get("from"))
+			require.Equal(t, "2024-04-01T00:00:00Z", r.URL.Query().Get("to"))
+			require.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+
+			_, _ = w.Write([]byte("change,user,relation,object\ngained,user:anne,viewer,document:1\n"))
+		})
+		require.NoError(t, err)
+		require.Equal(t, "change,user,relation,object\ngained,user:anne,viewer,document:1\n", out)
+	})
+
+	t.Run("reports_incomplete_report", func(t *testing.T) {
+		_, err := run(t, func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Add("Trailer", errorTrailer)
+			_, _ = w.Write([]byte("change,user,relation,object\n"))
+			w.Header().Set(errorTrailer, "deadline exceeded")
+		})
+		require.ErrorContains(t, err, "the access review is incomplete: deadline exceeded")
+	})
+
+	t.Run("reports_error_response", func(t *testing.T) {
+		_, err := run(t, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"code":"validation_error","message":"from must be before to"}`))
+		})
+		require.ErrorContains(t, err, "400 Bad Request")
+		require.ErrorContains(t, err, "from must be before to")
+	})
+
+	t.Run("requires_from", func(t *testing.T) {
+		_, err := run(t, func(http.ResponseWriter, *http.Request) {}, "--from", "")
+		require.ErrorContains(t, err, "missing '--from'")
+	})
+}
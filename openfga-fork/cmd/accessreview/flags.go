@@ -0,0 +1,24 @@
+package accessreview
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/openfga/openfga/cmd/util"
+)
+
+// bindRunFlags binds the cobra cmd flags to the equivalent config value being managed
+// by viper. This bridges the config between cobra flags and viper flags.
+func bindRunFlagsFunc(flags *pflag.FlagSet) func(*cobra.Command, []string) {
+	return func(cmd *cobra.Command, args []string) {
+		util.MustBindPFlag(apiURLFlag, flags.Lookup(apiURLFlag))
+		util.MustBindPFlag(apiTokenFlag, flags.Lookup(apiTokenFlag))
+		util.MustBindPFlag(storeIDFlag, flags.Lookup(storeIDFlag))
+		util.MustBindPFlag(modelIDFlag, flags.Lookup(modelIDFlag))
+		util.MustBindPFlag(objectTypeFlag, flags.Lookup(objectTypeFlag))
+		util.MustBindPFlag(relationFlag, flags.Lookup(relationFlag))
+		util.MustBindPFlag(fromFlag, flags.Lookup(fromFlag))
+		util.MustBindPFlag(toFlag, flags.Lookup(toFlag))
+		util.MustBindPFlag(formatFlag, flags.Lookup(formatFlag))
+	}
+}
@@ -0,0 +1,130 @@
+// Package activemodel contains the command to inspect and change the authorization model a store is pinned to.
+package activemodel
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/openfga/openfga/cmd/util"
+	"github.com/openfga/openfga/pkg/server/commands"
+	"github.com/openfga/openfga/pkg/storage"
+)
+
+const (
+	datastoreEngineFlag = "datastore-engine"
+	datastoreURIFlag    = "datastore-uri"
+	storeIDFlag         = "store-id"
+	modelIDFlag         = "model-id"
+)
+
+func NewActiveModelCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "active-model",
+		Short: "Manage the authorization model used by a store when requests omit the model ID.",
+		Long: "Read, pin, unpin or roll back the authorization model used by a store when requests omit the model ID.\n" +
+			"Unless a store is pinned, its latest authorization model is used.",
+		Args: cobra.NoArgs,
+	}
+
+	flags := cmd.PersistentFlags()
+	flags.String(datastoreEngineFlag, "", "the datastore engine")
+	flags.String(datastoreURIFlag, "", "the connection uri to the datastore")
+	flags.String(storeIDFlag, "", "the id of the store")
+
+	// NOTE: if you add a new flag here, update the function below, too
+
+	cmd.PersistentPreRun = bindRunFlagsFunc(flags)
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "get",
+		Short: "Print the authorization model used by the store and whether the store is pinned to it.",
+		Args:  cobra.NoArgs,
+		RunE: runWithDatastore(func(ctx context.Context, db storage.OpenFGADatastore, storeID string) (*commands.ActiveAuthorizationModelResponse, error) {
+			req := &commands.ReadActiveAuthorizationModelRequest{StoreID: storeID}
+			if err := req.Validate(); err != nil {
+				return nil, err
+			}
+			return commands.NewReadActiveAuthorizationModelQuery(db).Execute(ctx, req)
+		}),
+	})
+
+	setCmd := &cobra.Command{
+		Use:   "set",
+		Short: "Pin the store to an existing authorization model.",
+		Args:  cobra.NoArgs,
+		RunE: runWithDatastore(func(ctx context.Context, db storage.OpenFGADatastore, storeID string) (*commands.ActiveAuthorizationModelResponse, error) {
+			modelID := viper.GetString(modelIDFlag)
+			if modelID == "" {
+				return nil, fmt.Errorf("missing '--%s'", modelIDFlag)
+			}
+			return writeActiveModel(ctx, db, storeID, modelID)
+		}),
+	}
+	setCmd.Flags().String(modelIDFlag, "", "the id of the authorization model to pin the store to")
+	setCmd.PreRun = func(cmd *cobra.Command, _ []string) {
+		util.MustBindPFlag(modelIDFlag, cmd.Flags().Lookup(modelIDFlag))
+	}
+	cmd.AddCommand(setCmd)
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "unset",
+		Short: "Remove the pin so that the store uses its latest authorization model again.",
+		Args:  cobra.NoArgs,
+		RunE: runWithDatastore(func(ctx context.Context, db storage.OpenFGADatastore, storeID string) (*commands.ActiveAuthorizationModelResponse, error) {
+			return writeActiveModel(ctx, db, storeID, "")
+		}),
+	})
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "rollback",
+		Short: "Pin the store to the authorization model written before the one it currently uses.",
+		Args:  cobra.NoArgs,
+		RunE: runWithDatastore(func(ctx context.Context, db storage.OpenFGADatastore, storeID string) (*commands.ActiveAuthorizationModelResponse, error) {
+			req := &commands.RollbackActiveAuthorizationModelRequest{StoreID: storeID}
+			if err := req.Validate(); err != nil {
+				return nil, err
+			}
+			return commands.NewRollbackActiveAuthorizationModelCommand(db).Execute(ctx, req)
+		}),
+	})
+
+	return cmd
+}
+
+func writeActiveModel(ctx context.Context, db storage.OpenFGADatastore, storeID, modelID string) (*commands.ActiveAuthorizationModelResponse, error) {
+	req := &commands.WriteActiveAuthorizationModelRequest{StoreID: storeID, AuthorizationModelID: modelID}
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	return commands.NewWriteActiveAuthorizationModelCommand(db).Execute(ctx, req)
+}
+
+type activeModelFunc func(ctx context.Context, db storage.OpenFGADatastore, storeID string) (*commands.ActiveAuthorizationModelResponse, error)
+
+// runWithDatastore opens the datastore configured by the flags, runs fn against it and prints the result as JSON.
+func runWithDatastore(fn activeModelFunc) func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, _ []string) error {
+		db, err := util.OpenDatastore(viper.GetString(datastoreEngineFlag), viper.GetString(datastoreURIFlag))
+		if err != nil {
+			return err
+		}
+		defer db.Close()
+
+		res, err := fn(context.Background(), db, viper.GetString(storeIDFlag))
+		if err != nil {
+			return err
+		}
+
+		marshalled, err := json.MarshalIndent(res, "", "    ")
+		if err != nil {
+			return fmt.Errorf("error marshalling the active authorization model: %w", err)
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), string(marshalled))
+
+		return nil
+	}
+}
@@ -0,0 +1,84 @@
+package activemodel
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/oklog/ulid/v2"
+	"github.com/stretchr/testify/require"
+
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+	parser "github.com/openfga/language/pkg/go/transformer"
+
+	"github.com/openfga/openfga/cmd/util"
+	"github.com/openfga/openfga/pkg/server/commands"
+	"github.com/openfga/openfga/pkg/typesystem"
+)
+
+func TestActiveModelCommand(t *testing.T) {
+	_, ds, uri := util.MustBootstrapDatastore(t, "sqlite")
+
+	ctx := context.Background()
+	storeID := ulid.Make().String()
+	_, err := ds.CreateStore(ctx, &openfgav1.Store{Id: storeID, Name: "store"})
+	require.NoError(t, err)
+
+	modelIDs := []string{ulid.Make().String(), ulid.Make().String()}
+	for _, modelID := range modelIDs {
+		err := ds.WriteAuthorizationModel(ctx, storeID, &openfgav1.AuthorizationModel{
+			Id:            modelID,
+			SchemaVersion: typesystem.SchemaVersion1_1,
+			TypeDefinitions: parser.MustTransformDSLToProto(`
+				model
+					schema 1.1
+				type user`).GetTypeDefinitions(),
+		})
+		require.NoError(t, err)
+	}
+
+	run := func(t *testing.T, args ...string) *commands.ActiveAuthorizationModelResponse {
+		cmd := NewActiveModelCommand()
+		out := &bytes.Buffer{}
+		cmd.SetOut(out)
+		cmd.SetArgs(append(args, "--datastore-engine", "sqlite", "--datastore-uri", uri, "--store-id", storeID))
+		require.NoError(t, cmd.Execute())
+
+		var res commands.ActiveAuthorizationModelResponse
+		require.NoError(t, json.Unmarshal(out.Bytes(), &res))
+		return &res
+	}
+
+	require.Equal(t, &commands.ActiveAuthorizationModelResponse{AuthorizationModelID: modelIDs[1]}, run(t, "get"))
+	require.Equal(t, &commands.ActiveAuthorizationModelResponse{AuthorizationModelID: modelIDs[0], Pinned: true}, run(t, "rollback"))
+	require.Equal(t, &commands.ActiveAuthorizationModelResponse{AuthorizationModelID: modelIDs[0], Pinned: true}, run(t, "get"))
+	require.Equal(t, &commands.ActiveAuthorizationModelResponse{AuthorizationModelID: modelIDs[1]}, run(t, "unset"))
+	require.Equal(t, &commands.ActiveAuthorizationModelResponse{AuthorizationModelID: modelIDs[0], Pinned: true}, run(t, "set", "--model-id", modelIDs[0]))
+}
+
+func TestActiveModelCommandErrors(t *testing.T) {
+	for _, tc := range []struct {
+		name          string
+		args          []string
+		errorExpected string
+	}{
+		{
+			name:          "unsupported_engine",
+			args:          []string{"get", "--datastore-engine", "memory"},
+			errorExpected: "storage engine 'memory' is unsupported",
+		},
+		{
+			name:          "missing_engine",
+			args:          []string{"get", "--datastore-engine", ""},
+			errorExpected: "missing datastore engine type",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			cmd := NewActiveModelCommand()
+			cmd.SetArgs(tc.args)
+			err := cmd.Execute()
+			require.ErrorContains(t, err, tc.errorExpected)
+		})
+	}
+}
@@ -5,8 +5,12 @@ import (
 	"os"
 
 	"github.com/openfga/openfga/cmd"
+	"github.com/openfga/openfga/cmd/accessreview"
+	"github.com/openfga/openfga/cmd/activemodel"
 	"github.com/openfga/openfga/cmd/migrate"
+	"github.com/openfga/openfga/cmd/permissionmatrix"
 	"github.com/openfga/openfga/cmd/run"
+	"github.com/openfga/openfga/cmd/storestats"
 	"github.com/openfga/openfga/cmd/validatemodels"
 )
 
@@ -22,6 +26,18 @@ func main() {
 	validateModelsCmd := validatemodels.NewValidateCommand()
 	rootCmd.AddCommand(validateModelsCmd)
 
+	activeModelCmd := activemodel.NewActiveModelCommand()
+	rootCmd.AddCommand(activeModelCmd)
+
+	permissionMatrixCmd := permissionmatrix.NewPermissionMatrixCommand()
+	rootCmd.AddCommand(permissionMatrixCmd)
+
+	accessReviewCmd := accessreview.NewAccessReviewCommand()
+	rootCmd.AddCommand(accessReviewCmd)
+
+	storeStatsCmd := storestats.NewStoreStatsCommand()
+	rootCmd.AddCommand(storeStatsCmd)
+
 	versionCmd := cmd.NewVersionCommand()
 	rootCmd.AddCommand(versionCmd)
 
@@ -0,0 +1,26 @@
+package permissionmatrix
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/openfga/openfga/cmd/util"
+)
+
+// bindRunFlags binds the cobra cmd flags to the equivalent config value being managed
+// by viper. This bridges the config between cobra flags and viper flags.
+func bindRunFlagsFunc(flags *pflag.FlagSet) func(*cobra.Command, []string) {
+	return func(cmd *cobra.Command, args []string) {
+		util.MustBindPFlag(apiURLFlag, flags.Lookup(apiURLFlag))
+		util.MustBindPFlag(apiTokenFlag, flags.Lookup(apiTokenFlag))
+		util.MustBindPFlag(storeIDFlag, flags.Lookup(storeIDFlag))
+		util.MustBindPFlag(modelIDFlag, flags.Lookup(modelIDFlag))
+		util.MustBindPFlag(objectTypeFlag, flags.Lookup(objectTypeFlag))
+		util.MustBindPFlag(relationFlag, flags.Lookup(relationFlag))
+		util.MustBindPFlag(userFilterFlag, flags.Lookup(userFilterFlag))
+		util.MustBindPFlag(formatFlag, flags.Lookup(formatFlag))
+		util.MustBindPFlag(pageSizeFlag, flags.Lookup(pageSizeFlag))
+		util.MustBindPFlag(maxRowsFlag, flags.Lookup(maxRowsFlag))
+		util.MustBindPFlag(consistencyFlag, flags.Lookup(consistencyFlag))
+	}
+}
@@ -0,0 +1,206 @@
+// Package permissionmatrix contains the command to export the effective permission matrix of a store.
+package permissionmatrix
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	apiURLFlag      = "api-url"
+	apiTokenFlag    = "api-token"
+	storeIDFlag     = "store-id"
+	modelIDFlag     = "model-id"
+	objectTypeFlag  = "object-type"
+	relationFlag    = "relation"
+	userFilterFlag  = "user-filter"
+	formatFlag      = "format"
+	pageSizeFlag    = "page-size"
+	maxRowsFlag     = "max-rows"
+	consistencyFlag = "consistency"
+
+	continuationTokenTrailer = "Openfga-Continuation-Token"
+	errorTrailer             = "Openfga-Error"
+	truncatedTrailer         = "Openfga-Truncated"
+)
+
+func NewPermissionMatrixCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "permission-matrix",
+		Short: "Export which users have which relations with the objects of a store.",
+		Long: "Export the effective user × object × relation matrix of a store as CSV or JSON Lines.\n" +
+			"The report is computed by a running OpenFGA server, so it is subject to the ListUsers deadline,\n" +
+			"result limit and throttling configured on that server. Pages are fetched until the matrix is\n" +
+			"complete or --max-rows rows have been written. A warning is written to stderr when the export\n" +
+			"stopped at --max-rows, or when the server truncated the users of some cells.",
+		RunE: runPermissionMatrix,
+		Args: cobra.NoArgs,
+	}
+
+	flags := cmd.Flags()
+	flags.String(apiURLFlag, "http://localhost:8080", "the URL of the OpenFGA HTTP API")
+	flags.String(apiTokenFlag, "", "the bearer token used to authenticate to the OpenFGA HTTP API")
+	flags.String(storeIDFlag, "", "the id of the store")
+	flags.String(modelIDFlag, "", "the id of the authorization model. If empty, the active model of the store is used")
+	flags.StringSlice(objectTypeFlag, nil, "the object types in the matrix")
+	flags.StringSlice(relationFlag, nil, "the relations in the matrix. If empty, all relations of each object type are used")
+	flags.StringSlice(userFilterFlag, nil, "the user types in the matrix, as 'type' or 'type#relation'. If empty, all types are used")
+	flags.String(formatFlag, "csv", "the output format, 'csv' or 'jsonl'")
+	flags.Int32(pageSizeFlag, 0, "the number of (object, relation) cells evaluated per request. If 0, the server default is used")
+	flags.Int(maxRowsFlag, 100000, "the maximum number of rows to write. If 0, all rows are written")
+	flags.String(consistencyFlag, "", "the consistency preference, 'MINIMIZE_LATENCY' or 'HIGHER_CONSISTENCY'")
+
+	// NOTE: if you add a new flag here, update the function below, too
+
+	cmd.PreRun = bindRunFlagsFunc(flags)
+
+	return cmd
+}
+
+func runPermissionMatrix(cmd *cobra.Command, _ []string) error {
+	storeID := viper.GetString(storeIDFlag)
+	if storeID == "" {
+		return fmt.Errorf("missing '--%s'", storeIDFlag)
+	}
+
+	format := viper.GetString(formatFlag)
+	params := url.Values{
+		"object_type": viper.GetStringSlice(objectTypeFlag),
+		"relation":    viper.GetStringSlice(relationFlag),
+		"user_filter": viper.GetStringSlice(userFilterFlag),
+		"format":      {format},
+	}
+	if modelID := viper.GetString(modelIDFlag); modelID != "" {
+		params.Set("authorization_model_id", modelID)
+	}
+	if pageSize := viper.GetInt32(pageSizeFlag); pageSize != 0 {
+		params.Set("page_size", fmt.Sprint(pageSize))
+	}
+	if consistency := viper.GetString(consistencyFlag); consistency != "" {
+		params.Set("consistency", consistency)
+	}
+
+	endpoint := strings.TrimSuffix(viper.GetString(apiURLFlag), "/") + "/stores/" + url.PathEscape(storeID) + "/permission-matrix"
+	e := &exporter{
+		client:   http.DefaultClient,
+		endpoint: endpoint,
+		token:    viper.GetString(apiTokenFlag),
+		out:      cmd.OutOrStdout(),
+		errOut:   cmd.ErrOrStderr(),
+		csv:      format == "csv",
+		maxRows:  viper.GetInt(maxRowsFlag),
+	}
+	return e.export(params)
+}
+
+type exporter struct {
+	client   *http.Client
+	endpoint string
+	token    string
+	out      io.Writer
+	errOut   io.Writer
+	csv      bool
+	maxRows  int
+	rows     int
+	// cut is true when the matrix has more rows than maxRows.
+	cut bool
+	// truncated is true when the server truncated the users of some cells.
+	truncated bool
+}
+
+// export fetches pages until the matrix is complete or maxRows rows have been written, and warns
+// when the rows written are not the whole matrix.
+func (e *exporter) export(params url.Values) error {
+	for page := 0; ; page++ {
+		continuationToken, err := e.exportPage(params, page == 0)
+		if err != nil {
+			return err
+		}
+		if continuationToken == "" || e.cut {
+			break
+		}
+		params.Set("continuation_token", continuationToken)
+	}
+
+	if e.cut {
+		fmt.Fprintf(e.errOut, "warning: the export stopped at --%s=%d rows, before the end of the permission matrix\n", maxRowsFlag, e.maxRows)
+	}
+	if e.truncated {
+		fmt.Fprintln(e.errOut, "warning: the users of some cells are missing, the server truncated their ListUsers queries")
+	}
+	return nil
+}
+
+func (e *exporter) limitReached() bool {
+	return e.maxRows > 0 && e.rows >= e.maxRows
+}
+
+func (e *exporter) exportPage(params url.Values, firstPage bool) (string, error) {
+	req, err := http.NewRequest(http.MethodGet, e.endpoint+"?"+params.Encode(), nil)
+	if err != nil {
+		return "", err
+	}
+	if e.token != "" {
+		req.Header.Set("Authorization", "Bearer "+e.token)
+	}
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to request the permission matrix: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("failed to request the permission matrix: %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	reader := bufio.NewReader(resp.Body)
+	header := e.csv
+	for {
+		line, err := reader.ReadString('\n')
+		if line != "" {
+			// Every page starts with the CSV header; only the first one is written.
+			if header {
+				header = false
+				if !firstPage {
+					continue
+				}
+			} else {
+				if e.limitReached() {
+					e.cut = true
+					return "", nil
+				}
+				e.rows++
+			}
+			if _, err := io.WriteString(e.out, line); err != nil {
+				return "", err
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return "", fmt.Errorf("failed to read the permission matrix: %w", err)
+		}
+	}
+
+	if msg := resp.Trailer.Get(errorTrailer); msg != "" {
+		return "", fmt.Errorf("the permission matrix is incomplete: %s", msg)
+	}
+	e.truncated = e.truncated || resp.Trailer.Get(truncatedTrailer) == "true"
+
+	continuationToken := resp.Trailer.Get(continuationTokenTrailer)
+	if continuationToken != "" && e.limitReached() {
+		e.cut = true
+	}
+	return continuationToken, nil
+}
@@ -0,0 +1,115 @@
+package permissionmatrix
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// newMatrixServer serves two CSV pages of two rows each. The second page is truncated if truncateSecondPage is set.
+func newMatrixServer(t *testing.T, failSecondPage, truncateSecondPage bool) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/stores/store/permission-matrix", r.URL.Path)
+		require.Equal(t, []string{"document"}, r.URL.Query()["object_type"])
+		require.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+
+		w.Header().Add("Trailer", continuationTokenTrailer)
+		w.Header().Add("Trailer", errorTrailer)
+		w.Header().Add("Trailer", truncatedTrailer)
+		w.Header().Set("Content-Type", "text/csv")
+		_, _ = w.Write([]byte("user,relation,object\n"))
+
+		if r.URL.Query().Get("continuation_token") == "" {
+			_, _ = w.Write([]byte("user:anne,viewer,document:1\nuser:bob,viewer,document:1\n"))
+			w.Header().Set(continuationTokenTrailer, "next")
+			return
+		}
+
+		require.Equal(t, "next", r.URL.Query().Get("continuation_token"))
+		_, _ = w.Write([]byte("user:anne,viewer,document:2\n"))
+		if failSecondPage {
+			w.Header().Set(errorTrailer, "deadline exceeded")
+			return
+		}
+		_, _ = w.Write([]byte("user:carl,viewer,document:2\n"))
+		if truncateSecondPage {
+			w.Header().Set(truncatedTrailer, "true")
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestPermissionMatrixCommand(t *testing.T) {
+	// run returns what the command writes to stdout and to stderr.
+	run := func(t *testing.T, server *httptest.Server, args ...string) (string, string, error) {
+		cmd := NewPermissionMatrixCommand()
+		out, errOut := &bytes.Buffer{}, &bytes.Buffer{}
+		cmd.SetOut(out)
+		cmd.SetErr(errOut)
+		cmd.SetArgs(append([]string{
+			"--api-url", server.URL, "--api-token", "secret", "--store-id", "store", "--object-type", "document",
+		}, args...))
+		err := cmd.Execute()
+		return out.String(), errOut.String(), err
+	}
+
+	t.Run("fetches_all_pages", func(t *testing.T) {
+		out, warnings, err := run(t, newMatrixServer(t, false, false))
+		require.NoError(t, err)
+		require.Equal(t, "user,relation,object\n"+
+			"user:anne,viewer,document:1\n"+
+			"user:bob,viewer,document:1\n"+
+			"user:anne,viewer,document:2\n"+
+			"user:carl,viewer,document:2\n", out)
+		require.Empty(t, warnings)
+	})
+
+	t.Run("stops_at_max_rows", func(t *testing.T) {
+		out, warnings, err := run(t, newMatrixServer(t, false, false), "--max-rows", "3")
+		require.NoError(t, err)
+		require.Equal(t, "user,relation,object\n"+
+			"user:anne,viewer,document:1\n"+
+			"user:bob,viewer,document:1\n"+
+			"user:anne,viewer,document:2\n", out)
+		require.Equal(t, "warning: the export stopped at --max-rows=3 rows, before the end of the permission matrix\n", warnings)
+	})
+
+	t.Run("max_rows_of_whole_matrix", func(t *testing.T) {
+		_, warnings, err := run(t, newMatrixServer(t, false, false), "--max-rows", "4")
+		require.NoError(t, err)
+		require.Empty(t, warnings)
+	})
+
+	t.Run("reports_truncated_page", func(t *testing.T) {
+		out, warnings, err := run(t, newMatrixServer(t, false, true))
+		require.NoError(t, err)
+		require.Contains(t, out, "user:carl,viewer,document:2\n")
+		require.Equal(t, "warning: the users of some cells are missing, the server truncated their ListUsers queries\n", warnings)
+	})
+
+	t.Run("reports_incomplete_page", func(t *testing.T) {
+		_, _, err := run(t, newMatrixServer(t, true, false))
+		require.ErrorContains(t, err, "the permission matrix is incomplete: deadline exceeded")
+	})
+
+	t.Run("reports_error_response", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"code":"validation_error","message":"at least one object type is required"}`))
+		}))
+		t.Cleanup(server.Close)
+
+		_, _, err := run(t, server)
+		require.ErrorContains(t, err, "400 Bad Request")
+		require.ErrorContains(t, err, "at least one object type is required")
+	})
+
+	t.Run("requires_store_id", func(t *testing.T) {
+		_, _, err := run(t, newMatrixServer(t, false, false), "--store-id", "")
+		require.ErrorContains(t, err, "missing '--store-id'")
+	})
+}
@@ -25,6 +25,120 @@ func bindRunFlagsFunc(flags *pflag.FlagSet) func(*cobra.Command, []string) {
 
 		command.MarkFlagsRequiredTogether("access-control-enabled", "access-control-store-id", "access-control-model-id")
 
+		util.MustBindPFlag("conditionContext.requestTimeKey", flags.Lookup("condition-context-request-time-key"))
+		util.MustBindEnv("conditionContext.requestTimeKey", "OPENFGA_CONDITION_CONTEXT_REQUEST_TIME_KEY")
+
+		util.MustBindPFlag("conditionContext.requestTimeGranularity", flags.Lookup("condition-context-request-time-granularity"))
+		util.MustBindEnv("conditionContext.requestTimeGranularity", "OPENFGA_CONDITION_CONTEXT_REQUEST_TIME_GRANULARITY")
+
+		util.MustBindPFlag("conditionContext.peerIPKey", flags.Lookup("condition-context-peer-ip-key"))
+		util.MustBindEnv("conditionContext.peerIPKey", "OPENFGA_CONDITION_CONTEXT_PEER_IP_KEY")
+
+		util.MustBindPFlag("conditionContext.claimKeys", flags.Lookup("condition-context-claim-keys"))
+		util.MustBindEnv("conditionContext.claimKeys", "OPENFGA_CONDITION_CONTEXT_CLAIM_KEYS")
+
+		util.MustBindPFlag("rateLimit.enabled", flags.Lookup("rate-limit-enabled"))
+		util.MustBindEnv("rateLimit.enabled", "OPENFGA_RATE_LIMIT_ENABLED")
+
+		util.MustBindPFlag("rateLimit.requestsPerSecond", flags.Lookup("rate-limit-requests-per-second"))
+		util.MustBindEnv("rateLimit.requestsPerSecond", "OPENFGA_RATE_LIMIT_REQUESTS_PER_SECOND")
+
+		util.MustBindPFlag("rateLimit.burst", flags.Lookup("rate-limit-burst"))
+		util.MustBindEnv("rateLimit.burst", "OPENFGA_RATE_LIMIT_BURST")
+
+		util.MustBindPFlag("rateLimit.methodLimits", flags.Lookup("rate-limit-method-limits"))
+		util.MustBindEnv("rateLimit.methodLimits", "OPENFGA_RATE_LIMIT_METHOD_LIMITS")
+
+		util.MustBindPFlag("rateLimit.backend", flags.Lookup("rate-limit-backend"))
+		util.MustBindEnv("rateLimit.backend", "OPENFGA_RATE_LIMIT_BACKEND")
+
+		util.MustBindPFlag("rateLimit.redis.addr", flags.Lookup("rate-limit-redis-addr"))
+		util.MustBindEnv("rateLimit.redis.addr", "OPENFGA_RATE_LIMIT_REDIS_ADDR")
+
+		util.MustBindPFlag("rateLimit.redis.password", flags.Lookup("rate-limit-redis-password"))
+		util.MustBindEnv("rateLimit.redis.password", "OPENFGA_RATE_LIMIT_REDIS_PASSWORD")
+
+		util.MustBindPFlag("rateLimit.redis.db", flags.Lookup("rate-limit-redis-db"))
+		util.MustBindEnv("rateLimit.redis.db", "OPENFGA_RATE_LIMIT_REDIS_DB")
+
+		util.MustBindPFlag("rateLimit.redis.timeout", flags.Lookup("rate-limit-redis-timeout"))
+		util.MustBindEnv("rateLimit.redis.timeout", "OPENFGA_RATE_LIMIT_REDIS_TIMEOUT")
+
+		util.MustBindPFlag("decisionLog.enabled", flags.Lookup("decision-log-enabled"))
+		util.MustBindEnv("decisionLog.enabled", "OPENFGA_DECISION_LOG_ENABLED")
+
+		util.MustBindPFlag("decisionLog.sink", flags.Lookup("decision-log-sink"))
+		util.MustBindEnv("decisionLog.sink", "OPENFGA_DECISION_LOG_SINK")
+
+		util.MustBindPFlag("decisionLog.file.path", flags.Lookup("decision-log-file-path"))
+		util.MustBindEnv("decisionLog.file.path", "OPENFGA_DECISION_LOG_FILE_PATH")
+
+		util.MustBindPFlag("decisionLog.file.maxSizeMB", flags.Lookup("decision-log-file-max-size-mb"))
+		util.MustBindEnv("decisionLog.file.maxSizeMB", "OPENFGA_DECISION_LOG_FILE_MAX_SIZE_MB")
+
+		util.MustBindPFlag("decisionLog.file.maxBackups", flags.Lookup("decision-log-file-max-backups"))
+		util.MustBindEnv("decisionLog.file.maxBackups", "OPENFGA_DECISION_LOG_FILE_MAX_BACKUPS")
+
+		util.MustBindPFlag("decisionLog.otlp.endpoint", flags.Lookup("decision-log-otlp-endpoint"))
+		util.MustBindEnv("decisionLog.otlp.endpoint", "OPENFGA_DECISION_LOG_OTLP_ENDPOINT")
+
+		util.MustBindPFlag("decisionLog.otlp.tls.enabled", flags.Lookup("decision-log-otlp-tls-enabled"))
+		util.MustBindEnv("decisionLog.otlp.tls.enabled", "OPENFGA_DECISION_LOG_OTLP_TLS_ENABLED")
+
+		util.MustBindPFlag("decisionLog.sampleRate", flags.Lookup("decision-log-sample-rate"))
+		util.MustBindEnv("decisionLog.sampleRate", "OPENFGA_DECISION_LOG_SAMPLE_RATE")
+
+		util.MustBindPFlag("decisionLog.storeSampleRates", flags.Lookup("decision-log-store-sample-rates"))
+		util.MustBindEnv("decisionLog.storeSampleRates", "OPENFGA_DECISION_LOG_STORE_SAMPLE_RATES")
+
+		util.MustBindPFlag("decisionLog.redactFields", flags.Lookup("decision-log-redact-fields"))
+		util.MustBindEnv("decisionLog.redactFields", "OPENFGA_DECISION_LOG_REDACT_FIELDS")
+
+		util.MustBindPFlag("slowLog.enabled", flags.Lookup("slow-log-enabled"))
+		util.MustBindEnv("slowLog.enabled", "OPENFGA_SLOW_LOG_ENABLED")
+
+		util.MustBindPFlag("slowLog.threshold", flags.Lookup("slow-log-threshold"))
+		util.MustBindEnv("slowLog.threshold", "OPENFGA_SLOW_LOG_THRESHOLD")
+
+		util.MustBindPFlag("slowLog.methodThresholds", flags.Lookup("slow-log-method-thresholds"))
+		util.MustBindEnv("slowLog.methodThresholds", "OPENFGA_SLOW_LOG_METHOD_THRESHOLDS")
+
+		util.MustBindPFlag("slowLog.capacity", flags.Lookup("slow-log-capacity"))
+		util.MustBindEnv("slowLog.capacity", "OPENFGA_SLOW_LOG_CAPACITY")
+
+		util.MustBindPFlag("loadShedding.enabled", flags.Lookup("load-shedding-enabled"))
+		util.MustBindEnv("loadShedding.enabled", "OPENFGA_LOAD_SHEDDING_ENABLED")
+
+		util.MustBindPFlag("loadShedding.defaultClass", flags.Lookup("load-shedding-default-class"))
+		util.MustBindEnv("loadShedding.defaultClass", "OPENFGA_LOAD_SHEDDING_DEFAULT_CLASS")
+
+		util.MustBindPFlag("loadShedding.methodClasses", flags.Lookup("load-shedding-method-classes"))
+		util.MustBindEnv("loadShedding.methodClasses", "OPENFGA_LOAD_SHEDDING_METHOD_CLASSES")
+
+		util.MustBindPFlag("loadShedding.clientClasses", flags.Lookup("load-shedding-client-classes"))
+		util.MustBindEnv("loadShedding.clientClasses", "OPENFGA_LOAD_SHEDDING_CLIENT_CLASSES")
+
+		util.MustBindPFlag("loadShedding.interactiveMaxInFlight", flags.Lookup("load-shedding-interactive-max-in-flight"))
+		util.MustBindEnv("loadShedding.interactiveMaxInFlight", "OPENFGA_LOAD_SHEDDING_INTERACTIVE_MAX_IN_FLIGHT")
+
+		util.MustBindPFlag("loadShedding.defaultMaxInFlight", flags.Lookup("load-shedding-default-max-in-flight"))
+		util.MustBindEnv("loadShedding.defaultMaxInFlight", "OPENFGA_LOAD_SHEDDING_DEFAULT_MAX_IN_FLIGHT")
+
+		util.MustBindPFlag("loadShedding.backgroundMaxInFlight", flags.Lookup("load-shedding-background-max-in-flight"))
+		util.MustBindEnv("loadShedding.backgroundMaxInFlight", "OPENFGA_LOAD_SHEDDING_BACKGROUND_MAX_IN_FLIGHT")
+
+		util.MustBindPFlag("loadShedding.datastoreLatencyThreshold", flags.Lookup("load-shedding-datastore-latency-threshold"))
+		util.MustBindEnv("loadShedding.datastoreLatencyThreshold", "OPENFGA_LOAD_SHEDDING_DATASTORE_LATENCY_THRESHOLD")
+
+		util.MustBindPFlag("loadShedding.queueTimeout", flags.Lookup("load-shedding-queue-timeout"))
+		util.MustBindEnv("loadShedding.queueTimeout", "OPENFGA_LOAD_SHEDDING_QUEUE_TIMEOUT")
+
+		util.MustBindPFlag("loadShedding.queueFrequency", flags.Lookup("load-shedding-queue-frequency"))
+		util.MustBindEnv("loadShedding.queueFrequency", "OPENFGA_LOAD_SHEDDING_QUEUE_FREQUENCY")
+
+		util.MustBindPFlag("loadShedding.retryAfter", flags.Lookup("load-shedding-retry-after"))
+		util.MustBindEnv("loadShedding.retryAfter", "OPENFGA_LOAD_SHEDDING_RETRY_AFTER")
+
 		util.MustBindPFlag("grpc.addr", flags.Lookup("grpc-addr"))
 		util.MustBindEnv("grpc.addr", "OPENFGA_GRPC_ADDR")
 
@@ -125,6 +239,21 @@ func bindRunFlagsFunc(flags *pflag.FlagSet) func(*cobra.Command, []string) {
 		util.MustBindPFlag("datastore.metrics.enabled", flags.Lookup("datastore-metrics-enabled"))
 		util.MustBindEnv("datastore.metrics.enabled", "OPENFGA_DATASTORE_METRICS_ENABLED")
 
+		util.MustBindPFlag("datastore.adaptiveConcurrency.enabled", flags.Lookup("datastore-adaptive-concurrency-enabled"))
+		util.MustBindEnv("datastore.adaptiveConcurrency.enabled", "OPENFGA_DATASTORE_ADAPTIVE_CONCURRENCY_ENABLED")
+
+		util.MustBindPFlag("datastore.adaptiveConcurrency.minLimit", flags.Lookup("datastore-adaptive-concurrency-min-limit"))
+		util.MustBindEnv("datastore.adaptiveConcurrency.minLimit", "OPENFGA_DATASTORE_ADAPTIVE_CONCURRENCY_MIN_LIMIT")
+
+		util.MustBindPFlag("datastore.adaptiveConcurrency.maxLimit", flags.Lookup("datastore-adaptive-concurrency-max-limit"))
+		util.MustBindEnv("datastore.adaptiveConcurrency.maxLimit", "OPENFGA_DATASTORE_ADAPTIVE_CONCURRENCY_MAX_LIMIT")
+
+		util.MustBindPFlag("datastore.adaptiveConcurrency.latencyThreshold", flags.Lookup("datastore-adaptive-concurrency-latency-threshold"))
+		util.MustBindEnv("datastore.adaptiveConcurrency.latencyThreshold", "OPENFGA_DATASTORE_ADAPTIVE_CONCURRENCY_LATENCY_THRESHOLD")
+
+		util.MustBindPFlag("datastore.adaptiveConcurrency.backoffRatio", flags.Lookup("datastore-adaptive-concurrency-backoff-ratio"))
+		util.MustBindEnv("datastore.adaptiveConcurrency.backoffRatio", "OPENFGA_DATASTORE_ADAPTIVE_CONCURRENCY_BACKOFF_RATIO")
+
 		util.MustBindPFlag("playground.enabled", flags.Lookup("playground-enabled"))
 		util.MustBindEnv("playground.enabled", "OPENFGA_PLAYGROUND_ENABLED")
 
@@ -137,6 +266,15 @@ func bindRunFlagsFunc(flags *pflag.FlagSet) func(*cobra.Command, []string) {
 		util.MustBindPFlag("profiler.addr", flags.Lookup("profiler-addr"))
 		util.MustBindEnv("profiler.addr", "OPENFGA_PROFILER_ADDRESS")
 
+		util.MustBindPFlag("admin.enabled", flags.Lookup("admin-enabled"))
+		util.MustBindEnv("admin.enabled", "OPENFGA_ADMIN_ENABLED")
+
+		util.MustBindPFlag("admin.addr", flags.Lookup("admin-addr"))
+		util.MustBindEnv("admin.addr", "OPENFGA_ADMIN_ADDR")
+
+		util.MustBindPFlag("admin.keys", flags.Lookup("admin-keys"))
+		util.MustBindEnv("admin.keys", "OPENFGA_ADMIN_KEYS")
+
 		util.MustBindPFlag("log.format", flags.Lookup("log-format"))
 		util.MustBindEnv("log.format", "OPENFGA_LOG_FORMAT")
 
@@ -170,6 +308,39 @@ func bindRunFlagsFunc(flags *pflag.FlagSet) func(*cobra.Command, []string) {
 		util.MustBindPFlag("metrics.enableRPCHistograms", flags.Lookup("metrics-enable-rpc-histograms"))
 		util.MustBindEnv("metrics.enableRPCHistograms", "OPENFGA_METRICS_ENABLE_RPC_HISTOGRAMS")
 
+		util.MustBindPFlag("metrics.otlp.enabled", flags.Lookup("metrics-otlp-enabled"))
+		util.MustBindEnv("metrics.otlp.enabled", "OPENFGA_METRICS_OTLP_ENABLED")
+
+		util.MustBindPFlag("metrics.otlp.endpoint", flags.Lookup("metrics-otlp-endpoint"))
+		util.MustBindEnv("metrics.otlp.endpoint", "OPENFGA_METRICS_OTLP_ENDPOINT")
+
+		util.MustBindPFlag("metrics.otlp.protocol", flags.Lookup("metrics-otlp-protocol"))
+		util.MustBindEnv("metrics.otlp.protocol", "OPENFGA_METRICS_OTLP_PROTOCOL")
+
+		util.MustBindPFlag("metrics.otlp.exportInterval", flags.Lookup("metrics-otlp-export-interval"))
+		util.MustBindEnv("metrics.otlp.exportInterval", "OPENFGA_METRICS_OTLP_EXPORT_INTERVAL")
+
+		util.MustBindPFlag("metrics.otlp.tls.enabled", flags.Lookup("metrics-otlp-tls-enabled"))
+		util.MustBindEnv("metrics.otlp.tls.enabled", "OPENFGA_METRICS_OTLP_TLS_ENABLED")
+
+		util.MustBindPFlag("metrics.storeLabels.enabled", flags.Lookup("metrics-store-labels-enabled"))
+		util.MustBindEnv("metrics.storeLabels.enabled", "OPENFGA_METRICS_STORE_LABELS_ENABLED")
+
+		util.MustBindPFlag("metrics.storeLabels.allowedStores", flags.Lookup("metrics-store-labels-allowed-stores"))
+		util.MustBindEnv("metrics.storeLabels.allowedStores", "OPENFGA_METRICS_STORE_LABELS_ALLOWED_STORES")
+
+		util.MustBindPFlag("metrics.storeLabels.maxStores", flags.Lookup("metrics-store-labels-max-stores"))
+		util.MustBindEnv("metrics.storeLabels.maxStores", "OPENFGA_METRICS_STORE_LABELS_MAX_STORES")
+
+		util.MustBindPFlag("metrics.storeLabels.modelLabelsEnabled", flags.Lookup("metrics-store-labels-model-labels-enabled"))
+		util.MustBindEnv("metrics.storeLabels.modelLabelsEnabled", "OPENFGA_METRICS_STORE_LABELS_MODEL_LABELS_ENABLED")
+
+		util.MustBindPFlag("metrics.storeLabels.maxModels", flags.Lookup("metrics-store-labels-max-models"))
+		util.MustBindEnv("metrics.storeLabels.maxModels", "OPENFGA_METRICS_STORE_LABELS_MAX_MODELS")
+
+		util.MustBindPFlag("metrics.storeLabels.rankingInterval", flags.Lookup("metrics-store-labels-ranking-interval"))
+		util.MustBindEnv("metrics.storeLabels.rankingInterval", "OPENFGA_METRICS_STORE_LABELS_RANKING_INTERVAL")
+
 		util.MustBindPFlag("maxChecksPerBatchCheck", flags.Lookup("max-checks-per-batch-check"))
 		util.MustBindEnv("maxChecksPerBatchCheck", "OPENFGA_MAX_CHECKS_PER_BATCH_CHECK")
 
@@ -197,6 +368,9 @@ func bindRunFlagsFunc(flags *pflag.FlagSet) func(*cobra.Command, []string) {
 		util.MustBindPFlag("maxConditionEvaluationCost", flags.Lookup("max-condition-evaluation-cost"))
 		util.MustBindEnv("maxConditionEvaluationCost", "OPENFGA_MAX_CONDITION_EVALUATION_COST", "OPENFGA_MAXCONDITIONEVALUATIONCOST")
 
+		util.MustBindPFlag("conditionExtensionsEnabled", flags.Lookup("condition-extensions-enabled"))
+		util.MustBindEnv("conditionExtensionsEnabled", "OPENFGA_CONDITION_EXTENSIONS_ENABLED")
+
 		util.MustBindPFlag("changelogHorizonOffset", flags.Lookup("changelog-horizon-offset"))
 		util.MustBindEnv("changelogHorizonOffset", "OPENFGA_CHANGELOG_HORIZON_OFFSET", "OPENFGA_CHANGELOGHORIZONOFFSET")
 
@@ -218,9 +392,36 @@ func bindRunFlagsFunc(flags *pflag.FlagSet) func(*cobra.Command, []string) {
 		util.MustBindPFlag("listUsersMaxResults", flags.Lookup("listUsers-max-results"))
 		util.MustBindEnv("listUsersMaxResults", "OPENFGA_LIST_USERS_MAX_RESULTS", "OPENFGA_LISTUSERSMAXRESULTS")
 
+		util.MustBindPFlag("permissionMatrixMaxObjects", flags.Lookup("permissionMatrix-max-objects"))
+		util.MustBindEnv("permissionMatrixMaxObjects", "OPENFGA_PERMISSION_MATRIX_MAX_OBJECTS", "OPENFGA_PERMISSIONMATRIXMAXOBJECTS")
+
+		util.MustBindPFlag("accessReviewMaxChanges", flags.Lookup("accessReview-max-changes"))
+		util.MustBindEnv("accessReviewMaxChanges", "OPENFGA_ACCESS_REVIEW_MAX_CHANGES", "OPENFGA_ACCESSREVIEWMAXCHANGES")
+
+		util.MustBindPFlag("storeStatsCacheTTL", flags.Lookup("storeStats-cache-ttl"))
+		util.MustBindEnv("storeStatsCacheTTL", "OPENFGA_STORE_STATS_CACHE_TTL", "OPENFGA_STORESTATSCACHETTL")
+
 		util.MustBindPFlag("checkCache.limit", flags.Lookup("check-cache-limit"))
 		util.MustBindEnv("checkCache.limit", "OPENFGA_CHECK_CACHE_LIMIT")
 
+		util.MustBindPFlag("checkCache.backend", flags.Lookup("check-cache-backend"))
+		util.MustBindEnv("checkCache.backend", "OPENFGA_CHECK_CACHE_BACKEND")
+
+		util.MustBindPFlag("checkCache.redis.addr", flags.Lookup("check-cache-redis-addr"))
+		util.MustBindEnv("checkCache.redis.addr", "OPENFGA_CHECK_CACHE_REDIS_ADDR")
+
+		util.MustBindPFlag("checkCache.redis.password", flags.Lookup("check-cache-redis-password"))
+		util.MustBindEnv("checkCache.redis.password", "OPENFGA_CHECK_CACHE_REDIS_PASSWORD")
+
+		util.MustBindPFlag("checkCache.redis.db", flags.Lookup("check-cache-redis-db"))
+		util.MustBindEnv("checkCache.redis.db", "OPENFGA_CHECK_CACHE_REDIS_DB")
+
+		util.MustBindPFlag("checkCache.redis.localTTL", flags.Lookup("check-cache-redis-local-ttl"))
+		util.MustBindEnv("checkCache.redis.localTTL", "OPENFGA_CHECK_CACHE_REDIS_LOCAL_TTL")
+
+		util.MustBindPFlag("checkCache.redis.timeout", flags.Lookup("check-cache-redis-timeout"))
+		util.MustBindEnv("checkCache.redis.timeout", "OPENFGA_CHECK_CACHE_REDIS_TIMEOUT")
+
 		// The below configuration is deprecated in favour of OPENFGA_CHECK_CACHE_LIMIT
 		util.MustBindPFlag("cache.limit", flags.Lookup("check-query-cache-limit"))
 		util.MustBindEnv("cache.limit", "OPENFGA_CHECK_QUERY_CACHE_LIMIT")
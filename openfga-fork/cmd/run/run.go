@@ -4,6 +4,7 @@ package run
 import (
 	"context"
 	"crypto/tls"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"html/template"
@@ -51,29 +52,38 @@ import (
 	"github.com/openfga/openfga/internal/authn/oidc"
 	"github.com/openfga/openfga/internal/authn/presharedkey"
 	"github.com/openfga/openfga/internal/build"
+	"github.com/openfga/openfga/internal/condition/contextprovider"
 	authnmw "github.com/openfga/openfga/internal/middleware/authn"
 	"github.com/openfga/openfga/internal/planner"
+	"github.com/openfga/openfga/internal/utils/apimethod"
+	"github.com/openfga/openfga/pkg/decisionlog"
 	"github.com/openfga/openfga/pkg/encoder"
 	"github.com/openfga/openfga/pkg/gateway"
 	"github.com/openfga/openfga/pkg/logger"
 	"github.com/openfga/openfga/pkg/middleware"
 	httpmiddleware "github.com/openfga/openfga/pkg/middleware/http"
+	"github.com/openfga/openfga/pkg/middleware/loadshed"
 	"github.com/openfga/openfga/pkg/middleware/logging"
+	"github.com/openfga/openfga/pkg/middleware/ratelimit"
 	"github.com/openfga/openfga/pkg/middleware/recovery"
 	"github.com/openfga/openfga/pkg/middleware/requestid"
 	"github.com/openfga/openfga/pkg/middleware/storeid"
+	"github.com/openfga/openfga/pkg/middleware/storemetrics"
 	"github.com/openfga/openfga/pkg/middleware/validator"
 	"github.com/openfga/openfga/pkg/server"
 	serverconfig "github.com/openfga/openfga/pkg/server/config"
 	serverErrors "github.com/openfga/openfga/pkg/server/errors"
 	"github.com/openfga/openfga/pkg/server/health"
+	"github.com/openfga/openfga/pkg/slowlog"
 	"github.com/openfga/openfga/pkg/storage"
 	"github.com/openfga/openfga/pkg/storage/memory"
 	"github.com/openfga/openfga/pkg/storage/mysql"
 	"github.com/openfga/openfga/pkg/storage/postgres"
+	"github.com/openfga/openfga/pkg/storage/rediscache"
 	"github.com/openfga/openfga/pkg/storage/sqlcommon"
 	"github.com/openfga/openfga/pkg/storage/sqlite"
 	"github.com/openfga/openfga/pkg/storage/sqlserver"
+	"github.com/openfga/openfga/pkg/storage/storagewrappers"
 	"github.com/openfga/openfga/pkg/telemetry"
 )
 
@@ -104,6 +114,82 @@ func NewRunCommand() *cobra.Command {
 
 	cmd.MarkFlagsRequiredTogether("access-control-enabled", "access-control-store-id", "access-control-model-id")
 
+	flags.String("condition-context-request-time-key", defaultConfig.ConditionContext.RequestTimeKey, "the condition context key set by the server to the time it handles the request, overriding the value sent by the caller. If empty, it is not set")
+
+	flags.Duration("condition-context-request-time-granularity", defaultConfig.ConditionContext.RequestTimeGranularity, "the duration the condition context request time is truncated to, so that the requests made within the same interval share the check cache entries. If 0, the time is not truncated")
+
+	flags.String("condition-context-peer-ip-key", defaultConfig.ConditionContext.PeerIPKey, "the condition context key set by the server to the IP address of the client, overriding the value sent by the caller. If empty, it is not set")
+
+	flags.StringSlice("condition-context-claim-keys", defaultConfig.ConditionContext.ClaimKeys, "the condition context keys set by the server to claims of the authenticated caller, overriding the values sent by the caller, as 'claim=key' pairs where claim is one of `subject`, `client_id` or `scopes`")
+
+	flags.Bool("rate-limit-enabled", defaultConfig.RateLimit.Enabled, "enable the token buckets that limit the rate of the requests of each client, identified by the client ID or subject of its credentials, to each store and API method")
+
+	flags.Float64("rate-limit-requests-per-second", defaultConfig.RateLimit.RequestsPerSecond, "if rate-limit-enabled, the rate at which the buckets of the API methods without their own limit are refilled. If 0, they are not limited")
+
+	flags.Int("rate-limit-burst", defaultConfig.RateLimit.Burst, "if rate-limit-enabled, the number of requests the buckets of the API methods without their own limit hold")
+
+	flags.StringSlice("rate-limit-method-limits", defaultConfig.RateLimit.MethodLimits, "if rate-limit-enabled, the limits of some API methods, as 'method=requestsPerSecond:burst' items, e.g. 'Check=500:1000'. A rate of 0 disables the limit of the method")
+
+	flags.String("rate-limit-backend", defaultConfig.RateLimit.Backend, "if rate-limit-enabled, where the buckets are stored: 'memory' limits the requests served by each server process, 'redis' enforces the limits across replicas through a Redis-compatible server")
+
+	flags.String("rate-limit-redis-addr", defaultConfig.RateLimit.Redis.Addr, "if rate-limit-backend is 'redis', the address (host:port, or rediss://host:port for TLS) of the Redis-compatible server. It must support Lua scripting")
+
+	flags.String("rate-limit-redis-password", defaultConfig.RateLimit.Redis.Password, "if rate-limit-backend is 'redis', the password used to authenticate to the Redis-compatible server")
+
+	flags.Int("rate-limit-redis-db", defaultConfig.RateLimit.Redis.DB, "if rate-limit-backend is 'redis', the index of the Redis database")
+
+	flags.Duration("rate-limit-redis-timeout", defaultConfig.RateLimit.Redis.Timeout, "if rate-limit-backend is 'redis', the timeout of every call to the Redis-compatible server. Requests are allowed when a call fails")
+
+	flags.Bool("decision-log-enabled", defaultConfig.DecisionLog.Enabled, "enable the log of the authorization decisions made by Check, BatchCheck and ListObjects")
+
+	flags.String("decision-log-sink", defaultConfig.DecisionLog.Sink, "if decision-log-enabled, where the decisions are written: 'stdout' and 'file' write JSON lines, 'otlp' exports log records to an OTLP collector")
+
+	flags.String("decision-log-file-path", defaultConfig.DecisionLog.File.Path, "if decision-log-sink is 'file', the path of the file the decisions are appended to")
+
+	flags.Int("decision-log-file-max-size-mb", defaultConfig.DecisionLog.File.MaxSizeMB, "if decision-log-sink is 'file', the size in megabytes at which the file is rotated")
+
+	flags.Int("decision-log-file-max-backups", defaultConfig.DecisionLog.File.MaxBackups, "if decision-log-sink is 'file', the number of rotated files that are kept")
+
+	flags.String("decision-log-otlp-endpoint", defaultConfig.DecisionLog.OTLP.Endpoint, "if decision-log-sink is 'otlp', the endpoint of the OTLP logs collector")
+
+	flags.Bool("decision-log-otlp-tls-enabled", defaultConfig.DecisionLog.OTLP.TLS.Enabled, "if decision-log-sink is 'otlp', whether to use TLS to connect to the collector")
+
+	flags.Float64("decision-log-sample-rate", defaultConfig.DecisionLog.SampleRate, "if decision-log-enabled, the fraction, between 0 and 1, of the decisions that are written")
+
+	flags.StringSlice("decision-log-store-sample-rates", defaultConfig.DecisionLog.StoreSampleRates, "if decision-log-enabled, the sample rates of some stores, as 'storeID=rate' items")
+
+	flags.StringSlice("decision-log-redact-fields", defaultConfig.DecisionLog.RedactFields, "if decision-log-enabled, the fields whose value is replaced by 'REDACTED', among `user`, `object`, `objects`, `principal` and `context`")
+
+	flags.Bool("slow-log-enabled", defaultConfig.SlowLog.Enabled, "enable/disable the log of the Check, ListObjects and StreamedListObjects requests that take longer than the threshold of their method")
+
+	flags.Duration("slow-log-threshold", defaultConfig.SlowLog.Threshold, "if slow-log-enabled, the duration over which the requests of the methods without their own threshold are slow")
+
+	flags.StringSlice("slow-log-method-thresholds", defaultConfig.SlowLog.MethodThresholds, "if slow-log-enabled, the thresholds of some methods, as 'method=duration' items, e.g. 'Check=200ms'")
+
+	flags.Int("slow-log-capacity", defaultConfig.SlowLog.Capacity, "if slow-log-enabled, the number of the most recent slow requests kept in memory and served by the '/slowlog' admin endpoint")
+
+	flags.Bool("load-shedding-enabled", defaultConfig.LoadShedding.Enabled, "enable/disable the queueing and shedding of the requests of the lowest priority classes first when the server is overloaded")
+
+	flags.String("load-shedding-default-class", defaultConfig.LoadShedding.DefaultClass, "if load-shedding-enabled, the priority class ('interactive', 'default' or 'background') of the requests whose method and client have no class of their own")
+
+	flags.StringSlice("load-shedding-method-classes", defaultConfig.LoadShedding.MethodClasses, "if load-shedding-enabled, the priority classes of some API methods, as 'method=class' items, e.g. 'ReadChanges=background'")
+
+	flags.StringSlice("load-shedding-client-classes", defaultConfig.LoadShedding.ClientClasses, "if load-shedding-enabled, the priority classes of the requests of some clients, identified by the client ID or subject of their credentials, as 'client=class' items. They take precedence over the classes of the methods")
+
+	flags.Int("load-shedding-interactive-max-in-flight", defaultConfig.LoadShedding.InteractiveMaxInFlight, "if load-shedding-enabled, the number of in-flight requests from which the requests of the interactive class are queued. 0 means no limit")
+
+	flags.Int("load-shedding-default-max-in-flight", defaultConfig.LoadShedding.DefaultMaxInFlight, "if load-shedding-enabled, the number of in-flight requests from which the requests of the default class are queued. 0 means no limit")
+
+	flags.Int("load-shedding-background-max-in-flight", defaultConfig.LoadShedding.BackgroundMaxInFlight, "if load-shedding-enabled, the number of in-flight requests from which the requests of the background class are queued. 0 means no limit")
+
+	flags.Duration("load-shedding-datastore-latency-threshold", defaultConfig.LoadShedding.DatastoreLatencyThreshold, "if load-shedding-enabled, the average datastore read latency over which the requests below the interactive class are queued. It requires datastore-adaptive-concurrency-enabled. 0 disables it")
+
+	flags.Duration("load-shedding-queue-timeout", defaultConfig.LoadShedding.QueueTimeout, "if load-shedding-enabled, how long the requests over the limit of their class wait to be admitted before being shed. 0 sheds them right away")
+
+	flags.Duration("load-shedding-queue-frequency", defaultConfig.LoadShedding.QueueFrequency, "if load-shedding-enabled, how often one of the queued requests is let through to try again")
+
+	flags.Duration("load-shedding-retry-after", defaultConfig.LoadShedding.RetryAfter, "if load-shedding-enabled, the delay after which the clients of shed requests are told to retry, in the Retry-After header and the RetryInfo error detail")
+
 	flags.String("grpc-addr", defaultConfig.GRPC.Addr, "the host:port address to serve the grpc server on")
 
 	flags.Bool("grpc-tls-enabled", defaultConfig.GRPC.TLS.Enabled, "enable/disable transport layer security (TLS)")
@@ -172,6 +258,16 @@ func NewRunCommand() *cobra.Command {
 
 	flags.Bool("datastore-metrics-enabled", defaultConfig.Datastore.Metrics.Enabled, "enable/disable sql metrics")
 
+	flags.Bool("datastore-adaptive-concurrency-enabled", defaultConfig.Datastore.AdaptiveConcurrency.Enabled, "enable a limit on the concurrent datastore reads of all the Check, BatchCheck, ListObjects and ListUsers requests that decreases when the reads are slow or fail and increases otherwise")
+
+	flags.Int("datastore-adaptive-concurrency-min-limit", defaultConfig.Datastore.AdaptiveConcurrency.MinLimit, "the floor of the adaptive limit on concurrent datastore reads")
+
+	flags.Int("datastore-adaptive-concurrency-max-limit", defaultConfig.Datastore.AdaptiveConcurrency.MaxLimit, "the ceiling, and initial value, of the adaptive limit on concurrent datastore reads")
+
+	flags.Duration("datastore-adaptive-concurrency-latency-threshold", defaultConfig.Datastore.AdaptiveConcurrency.LatencyThreshold, "the datastore read latency over which the adaptive limit on concurrent datastore reads decreases")
+
+	flags.Float64("datastore-adaptive-concurrency-backoff-ratio", defaultConfig.Datastore.AdaptiveConcurrency.BackoffRatio, "the ratio, between 0 and 1, the adaptive limit on concurrent datastore reads is multiplied by when it decreases")
+
 	flags.Bool("playground-enabled", defaultConfig.Playground.Enabled, "enable/disable the OpenFGA Playground")
 
 	flags.Int("playground-port", defaultConfig.Playground.Port, "the port to serve the local OpenFGA Playground on")
@@ -180,6 +276,12 @@ func NewRunCommand() *cobra.Command {
 
 	flags.String("profiler-addr", defaultConfig.Profiler.Addr, "the host:port address to serve the pprof profiler server on")
 
+	flags.Bool("admin-enabled", defaultConfig.Admin.Enabled, "enable/disable the admin HTTP server")
+
+	flags.String("admin-addr", defaultConfig.Admin.Addr, "the host:port address to serve the admin HTTP server on")
+
+	flags.StringSlice("admin-keys", defaultConfig.Admin.Keys, "if admin-enabled, one or more preshared keys that authenticate the admin requests as bearer tokens")
+
 	flags.String("log-format", defaultConfig.Log.Format, "the log format to output logs in")
 
 	flags.String("log-level", defaultConfig.Log.Level, "the log level to use")
@@ -202,11 +304,33 @@ func NewRunCommand() *cobra.Command {
 
 	flags.Bool("metrics-enable-rpc-histograms", defaultConfig.Metrics.EnableRPCHistograms, "enables prometheus histogram metrics for RPC latency distributions")
 
+	flags.Bool("metrics-otlp-enabled", defaultConfig.Metrics.OTLP.Enabled, "enable/disable pushing the metrics to an OTLP collector, independently of the prometheus '/metrics' endpoint")
+
+	flags.String("metrics-otlp-endpoint", defaultConfig.Metrics.OTLP.Endpoint, "the host:port address of the OTLP collector the metrics are pushed to")
+
+	flags.String("metrics-otlp-protocol", defaultConfig.Metrics.OTLP.Protocol, "the protocol used to push the metrics to the OTLP collector: 'grpc' or 'http'")
+
+	flags.Duration("metrics-otlp-export-interval", defaultConfig.Metrics.OTLP.ExportInterval, "how often the metrics are pushed to the OTLP collector")
+
+	flags.Bool("metrics-otlp-tls-enabled", defaultConfig.Metrics.OTLP.TLS.Enabled, "use TLS to connect to the OTLP metrics collector")
+
+	flags.Bool("metrics-store-labels-enabled", defaultConfig.Metrics.StoreLabels.Enabled, "enable/disable the store_id label of the request, datastore and cache metrics")
+
+	flags.StringSlice("metrics-store-labels-allowed-stores", defaultConfig.Metrics.StoreLabels.AllowedStores, "if metrics-store-labels-enabled, the stores always labeled with their ID, besides metrics-store-labels-max-stores")
+
+	flags.Int("metrics-store-labels-max-stores", defaultConfig.Metrics.StoreLabels.MaxStores, "if metrics-store-labels-enabled, the number of stores, besides the allowed ones, labeled with their ID. The stores with the most requests are labeled with their ID and the rest are labeled 'other'")
+
+	flags.Bool("metrics-store-labels-model-labels-enabled", defaultConfig.Metrics.StoreLabels.ModelLabelsEnabled, "if metrics-store-labels-enabled, enable/disable the model_id label of the request metrics")
+
+	flags.Int("metrics-store-labels-max-models", defaultConfig.Metrics.StoreLabels.MaxModels, "if metrics-store-labels-model-labels-enabled, the number of authorization models labeled with their ID. The rest are labeled 'other'")
+
+	flags.Duration("metrics-store-labels-ranking-interval", defaultConfig.Metrics.StoreLabels.RankingInterval, "if metrics-store-labels-enabled, how often the stores and models labeled with their ID are ranked again by their recent requests")
+
 	flags.Uint32("max-concurrent-checks-per-batch-check", defaultConfig.MaxConcurrentChecksPerBatchCheck, "the maximum number of checks that can be processed concurrently in a batch check request")
 
 	flags.Uint32("max-checks-per-batch-check", defaultConfig.MaxChecksPerBatchCheck, "the maximum number of tuples allowed in a BatchCheck request")
 
-	flags.Int("max-tuples-per-write", defaultConfig.MaxTuplesPerWrite, "the maximum allowed number of tuples per Write transaction")
+	flags.Int("max-tuples-per-write", defaultConfig.MaxTuplesPerWrite, "the maximum allowed number of tuples per Write transaction, applied atomically (at most 10000)")
 
 	flags.Int("max-types-per-authorization-model", defaultConfig.MaxTypesPerAuthorizationModel, "the maximum allowed number of type definitions per authorization model")
 
@@ -220,6 +344,8 @@ func NewRunCommand() *cobra.Command {
 
 	flags.Uint64("max-condition-evaluation-cost", defaultConfig.MaxConditionEvaluationCost, "the maximum cost for CEL condition evaluation before a request returns an error")
 
+	flags.Bool("condition-extensions-enabled", defaultConfig.ConditionExtensionsEnabled, "enable the extension functions of the condition library (inBusinessHours, dayOfWeek, ipaddress.inAnyCIDR, semverCompare and regexMatch). Authorization models that use them can only be written and evaluated while this is enabled")
+
 	flags.Int("changelog-horizon-offset", defaultConfig.ChangelogHorizonOffset, "the offset (in minutes) from the current time. Changes that occur after this offset will not be included in the response of ReadChanges")
 
 	flags.Uint32("resolve-node-limit", defaultConfig.ResolveNodeLimit, "maximum resolution depth to attempt before throwing an error (defines how deeply nested an authorization model can be before a query errors out).")
@@ -234,8 +360,26 @@ func NewRunCommand() *cobra.Command {
 
 	flags.Uint32("listUsers-max-results", defaultConfig.ListUsersMaxResults, "the maximum results to return in ListUsers API responses. If 0, all results can be returned")
 
+	flags.Uint32("permissionMatrix-max-objects", defaultConfig.PermissionMatrixMaxObjects, "the maximum number of objects of a single type that a permission matrix report can enumerate. If 0, there is no limit")
+
+	flags.Uint32("accessReview-max-changes", defaultConfig.AccessReviewMaxChanges, "the maximum number of changelog entries that an access review can read. If 0, there is no limit")
+
+	flags.Duration("storeStats-cache-ttl", defaultConfig.StoreStatsCacheTTL, "how long the statistics of a store are cached by the GetStoreStats API. If 0, they are not cached")
+
 	flags.Uint32("check-cache-limit", defaultConfig.CheckCache.Limit, "if check-query-cache-enabled or check-iterator-cache-enabled, this is the size limit of the cache")
 
+	flags.String("check-cache-backend", defaultConfig.CheckCache.Backend, "where the cache of Check requests and iterators is stored: 'memory' keeps it in each server process, 'redis' shares it across replicas through a Redis-compatible server")
+
+	flags.String("check-cache-redis-addr", defaultConfig.CheckCache.Redis.Addr, "if check-cache-backend is 'redis', the address (host:port, or rediss://host:port for TLS) of the Redis-compatible server")
+
+	flags.String("check-cache-redis-password", defaultConfig.CheckCache.Redis.Password, "if check-cache-backend is 'redis', the password used to authenticate to the Redis-compatible server")
+
+	flags.Int("check-cache-redis-db", defaultConfig.CheckCache.Redis.DB, "if check-cache-backend is 'redis', the index of the Redis database")
+
+	flags.Duration("check-cache-redis-local-ttl", defaultConfig.CheckCache.Redis.LocalTTL, "if check-cache-backend is 'redis', the maximum time an entry is also kept in the local cache of each replica, which bounds how long a replica can serve an entry invalidated by another one. If 0, there is no local cache")
+
+	flags.Duration("check-cache-redis-timeout", defaultConfig.CheckCache.Redis.Timeout, "if check-cache-backend is 'redis', the timeout of every call to the Redis-compatible server. A call that fails is treated as a cache miss")
+
 	flags.Bool("shared-iterator-enabled", defaultConfig.SharedIterator.Enabled, "enabling sharing of datastore iterators with different consumers. Each iterator is the result of a database query, for example usersets related to a specific object, or objects related to a specific user, up to a certain number of tuples per iterator.")
 
 	flags.Uint32("shared-iterator-limit", defaultConfig.SharedIterator.Limit, "if shared-iterator-enabled is enabled, this is the limit of the number of iterators that can be shared.")
@@ -377,6 +521,221 @@ func convertStringArrayToUintArray(stringArray []string) []uint {
 	return uintArray
 }
 
+// conditionContextProviders returns the providers of the trusted condition context values enabled in config.
+func conditionContextProviders(config serverconfig.ConditionContextConfig) ([]contextprovider.Provider, error) {
+	var providers []contextprovider.Provider
+	if config.RequestTimeKey != "" {
+		providers = append(providers, contextprovider.RequestTime(config.RequestTimeKey, config.RequestTimeGranularity))
+	}
+	if config.PeerIPKey != "" {
+		providers = append(providers, contextprovider.PeerIP(config.PeerIPKey))
+	}
+
+	claimKeys, err := config.ParseClaimKeys()
+	if err != nil {
+		return nil, err
+	}
+	if len(claimKeys) > 0 {
+		providers = append(providers, contextprovider.AuthClaims(claimKeys))
+	}
+	return providers, nil
+}
+
+// rateLimiterConfig returns the rate limiter enabled in config, or nil, and the function that must be called
+// to release its backend.
+func (s *ServerContext) rateLimiterConfig(config serverconfig.RateLimitConfig) (*ratelimit.Limiter, func(), error) {
+	if !config.Enabled {
+		return nil, func() {}, nil
+	}
+
+	parsedLimits, err := config.ParseMethodLimits()
+	if err != nil {
+		return nil, nil, err
+	}
+	methodLimits := make(map[apimethod.APIMethod]ratelimit.Limit, len(parsedLimits))
+	for method, limit := range parsedLimits {
+		methodLimits[apimethod.APIMethod(method)] = ratelimit.Limit{RequestsPerSecond: limit.RequestsPerSecond, Burst: limit.Burst}
+	}
+
+	opts := []ratelimit.LimiterOption{
+		ratelimit.WithMethodLimits(methodLimits),
+		ratelimit.WithTransport(gateway.NewRPCTransport(s.Logger)),
+		ratelimit.WithLogger(s.Logger),
+	}
+
+	closeBackend := func() {}
+	if config.Backend == serverconfig.RateLimitBackendRedis {
+		client, err := rediscache.NewClient(config.Redis.Addr, config.Redis.Password, config.Redis.DB, config.Redis.Timeout)
+		if err != nil {
+			return nil, nil, err
+		}
+		opts = append(opts, ratelimit.WithBackend(ratelimit.NewRedisBackend(client, ratelimit.DefaultRedisKeyPrefix)))
+		closeBackend = client.Close
+	}
+
+	s.Logger.Info(fmt.Sprintf("rate limiting enabled: %v requests per second with a burst of %d per client, store and method, stored in %s",
+		config.RequestsPerSecond, config.Burst, config.Backend))
+
+	defaultLimit := ratelimit.Limit{RequestsPerSecond: config.RequestsPerSecond, Burst: config.Burst}
+	return ratelimit.NewLimiter(defaultLimit, opts...), closeBackend, nil
+}
+
+// adminServerConfig returns the admin HTTP server enabled in config, or nil. Its requests are authenticated
+// with the preshared keys of the admin config, independently of the authentication of the API.
+func (s *ServerContext) adminServerConfig(config *serverconfig.Config, svr *server.Server, slowLog *slowlog.Log) (*http.Server, error) {
+	if !config.Admin.Enabled {
+		return nil, nil
+	}
+
+	authenticator, err := presharedkey.NewPresharedKeyAuthenticator(config.Admin.Keys)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize the admin authenticator: %w", err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("GET /config", configHandler(config))
+	if zapLogger, ok := s.Logger.(*logger.ZapLogger); ok && zapLogger.AtomicLevel() != nil {
+		// GET returns the log level, PUT changes it, e.g. with a {"level":"debug"} body.
+		mux.Handle("/loglevel", zapLogger.AtomicLevel())
+	}
+	if slowLog != nil {
+		mux.Handle("/slowlog", slowLog)
+	}
+	svr.RegisterAdminHandlers(mux)
+
+	return &http.Server{Addr: config.Admin.Addr, Handler: authnmw.HTTPHandler(authenticator, mux)}, nil
+}
+
+// configHandler serves config as JSON. The secrets are left out, like when the config is logged at startup.
+func configHandler(config *serverconfig.Config) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(config)
+	})
+}
+
+// loadShedderConfig returns the load shedder enabled in config, or nil. It must be closed on shutdown.
+func (s *ServerContext) loadShedderConfig(config serverconfig.LoadSheddingConfig, adaptiveLimiter *storagewrappers.AdaptiveLimiter) (*loadshed.Shedder, error) {
+	if !config.Enabled {
+		return nil, nil
+	}
+
+	parsedMethodClasses, err := config.ParseMethodClasses()
+	if err != nil {
+		return nil, err
+	}
+	methodClasses := make(map[apimethod.APIMethod]loadshed.Class, len(parsedMethodClasses))
+	for method, name := range parsedMethodClasses {
+		if methodClasses[apimethod.APIMethod(method)], err = loadshed.ParseClass(name); err != nil {
+			return nil, err
+		}
+	}
+
+	parsedClientClasses, err := config.ParseClientClasses()
+	if err != nil {
+		return nil, err
+	}
+	clientClasses := make(map[string]loadshed.Class, len(parsedClientClasses))
+	for client, name := range parsedClientClasses {
+		if clientClasses[client], err = loadshed.ParseClass(name); err != nil {
+			return nil, err
+		}
+	}
+
+	defaultClass, err := loadshed.ParseClass(config.DefaultClass)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := []loadshed.ShedderOption{
+		loadshed.WithMethodClasses(methodClasses),
+		loadshed.WithClientClasses(clientClasses),
+		loadshed.WithDefaultClass(defaultClass),
+		loadshed.WithMaxInFlight(loadshed.ClassInteractive, config.InteractiveMaxInFlight),
+		loadshed.WithMaxInFlight(loadshed.ClassDefault, config.DefaultMaxInFlight),
+		loadshed.WithMaxInFlight(loadshed.ClassBackground, config.BackgroundMaxInFlight),
+		loadshed.WithQueue(config.QueueTimeout, config.QueueFrequency),
+		loadshed.WithRetryAfter(config.RetryAfter),
+		loadshed.WithTransport(gateway.NewRPCTransport(s.Logger)),
+	}
+	if config.DatastoreLatencyThreshold > 0 {
+		opts = append(opts, loadshed.WithDatastoreLatency(adaptiveLimiter.Latency, config.DatastoreLatencyThreshold))
+	}
+
+	s.Logger.Info(fmt.Sprintf("load shedding enabled: queueing requests over %d (interactive), %d (default) and %d (background) in-flight requests for up to %v",
+		config.InteractiveMaxInFlight, config.DefaultMaxInFlight, config.BackgroundMaxInFlight, config.QueueTimeout))
+
+	return loadshed.NewShedder(opts...), nil
+}
+
+// decisionLoggerConfig returns the decision logger enabled in config, or nil. It must be closed on shutdown.
+func (s *ServerContext) decisionLoggerConfig(ctx context.Context, config serverconfig.DecisionLogConfig) (*decisionlog.Logger, error) {
+	if !config.Enabled {
+		return nil, nil
+	}
+
+	storeSampleRates, err := config.ParseStoreSampleRates()
+	if err != nil {
+		return nil, err
+	}
+
+	var sink decisionlog.Sink
+	switch config.Sink {
+	case serverconfig.DecisionLogSinkFile:
+		sink, err = decisionlog.NewFileSink(config.File.Path, int64(config.File.MaxSizeMB)*1024*1024, config.File.MaxBackups)
+	case serverconfig.DecisionLogSinkOTLP:
+		sink, err = decisionlog.NewOTLPSink(ctx, config.OTLP.Endpoint, !config.OTLP.TLS.Enabled)
+	default:
+		sink = decisionlog.NewWriterSink(os.Stdout)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	s.Logger.Info(fmt.Sprintf("decision log enabled: writing to %s with a sample rate of %v", config.Sink, config.SampleRate))
+
+	return decisionlog.NewLogger(sink,
+		decisionlog.WithSampleRate(config.SampleRate),
+		decisionlog.WithStoreSampleRates(storeSampleRates),
+		decisionlog.WithRedactedFields(config.RedactFields...),
+		decisionlog.WithLogger(s.Logger),
+	), nil
+}
+
+// slowLogConfig returns the slow request log enabled in config, or nil.
+func (s *ServerContext) slowLogConfig(config serverconfig.SlowLogConfig) (*slowlog.Log, error) {
+	if !config.Enabled {
+		return nil, nil
+	}
+
+	methodThresholds, err := config.ParseMethodThresholds()
+	if err != nil {
+		return nil, err
+	}
+
+	s.Logger.Info(fmt.Sprintf("slow request log enabled: threshold is %v, keeping the last %d slow requests", config.Threshold, config.Capacity))
+
+	return slowlog.NewLog(config.Capacity,
+		slowlog.WithThreshold(config.Threshold),
+		slowlog.WithMethodThresholds(methodThresholds),
+		slowlog.WithLogger(s.Logger),
+	), nil
+}
+
+// adaptiveLimiterConfig returns the adaptive limiter of the datastore reads enabled in config, or nil.
+func (s *ServerContext) adaptiveLimiterConfig(config serverconfig.DatastoreAdaptiveConcurrencyConfig) *storagewrappers.AdaptiveLimiter {
+	if !config.Enabled {
+		return nil
+	}
+
+	s.Logger.Info(fmt.Sprintf("datastore adaptive concurrency enabled: limit between %d and %d, latency threshold is %v", config.MinLimit, config.MaxLimit, config.LatencyThreshold))
+
+	return storagewrappers.NewAdaptiveLimiter(config.MinLimit, config.MaxLimit,
+		storagewrappers.WithAdaptiveLimiterLatencyThreshold(config.LatencyThreshold),
+		storagewrappers.WithAdaptiveLimiterBackoffRatio(config.BackoffRatio),
+	)
+}
+
 // telemetryConfig returns the function that must be called to shut down tracing.
 // The context provided to this function should be error-free, or shut down will be incomplete.
 func (s *ServerContext) telemetryConfig(config *serverconfig.Config) func() error {
@@ -412,6 +771,44 @@ func (s *ServerContext) telemetryConfig(config *serverconfig.Config) func() erro
 	}
 }
 
+// metricsExporterConfig returns the function that must be called to shut down the OTLP metrics exporter,
+// which pushes the metrics registered with Prometheus to a collector.
+func (s *ServerContext) metricsExporterConfig(ctx context.Context, config *serverconfig.Config) (func() error, error) {
+	if !config.Metrics.OTLP.Enabled {
+		return func() error {
+			return nil
+		}, nil
+	}
+
+	s.Logger.Info(fmt.Sprintf("📈 pushing metrics every %s to the OTLP collector at '%s' over %s, tls: %t",
+		config.Metrics.OTLP.ExportInterval, config.Metrics.OTLP.Endpoint, config.Metrics.OTLP.Protocol, config.Metrics.OTLP.TLS.Enabled))
+
+	options := []telemetry.MeterOption{
+		telemetry.WithMetricsOTLPEndpoint(config.Metrics.OTLP.Endpoint),
+		telemetry.WithMetricsOTLPProtocol(config.Metrics.OTLP.Protocol),
+		telemetry.WithMetricsExportInterval(config.Metrics.OTLP.ExportInterval),
+		telemetry.WithMetricsAttributes(
+			semconv.ServiceNameKey.String(config.Trace.ServiceName),
+			semconv.ServiceVersionKey.String(build.Version),
+		),
+	}
+
+	if !config.Metrics.OTLP.TLS.Enabled {
+		options = append(options, telemetry.WithMetricsOTLPInsecure())
+	}
+
+	mp, err := telemetry.NewMeterProvider(ctx, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	return func() error {
+		ctx, cancel := context.WithTimeout(context.Background(), 6*time.Second)
+		defer cancel()
+		return mp.Shutdown(ctx)
+	}, nil
+}
+
 func (s *ServerContext) datastoreConfig(config *serverconfig.Config) (storage.OpenFGADatastore, encoder.ContinuationTokenSerializer, error) {
 	// SQL Token Serializer by default
 	tokenSerializer := sqlcommon.NewSQLContinuationTokenSerializer()
@@ -507,6 +904,11 @@ func (s *ServerContext) Run(ctx context.Context, config *serverconfig.Config) er
 
 	tracerProviderCloser := s.telemetryConfig(config)
 
+	meterProviderCloser, err := s.metricsExporterConfig(ctx, config)
+	if err != nil {
+		return err
+	}
+
 	if len(config.Experimentals) > 0 {
 		s.Logger.Info(fmt.Sprintf("🧪 experimental features enabled: %v", config.Experimentals))
 	}
@@ -575,7 +977,7 @@ func (s *ServerContext) Run(ctx context.Context, config *serverconfig.Config) er
 		),
 	)
 
-	if config.Metrics.Enabled {
+	if config.Metrics.Enabled || config.Metrics.OTLP.Enabled {
 		serverOpts = append(serverOpts,
 			grpc.ChainUnaryInterceptor(grpc_prometheus.UnaryServerInterceptor),
 			grpc.ChainStreamInterceptor(grpc_prometheus.StreamServerInterceptor))
@@ -585,25 +987,67 @@ func (s *ServerContext) Run(ctx context.Context, config *serverconfig.Config) er
 		}
 	}
 
+	if (config.Metrics.Enabled || config.Metrics.OTLP.Enabled) && config.Metrics.StoreLabels.Enabled {
+		labelerOpts := []telemetry.StoreLabelerOption{
+			telemetry.WithAllowedStores(config.Metrics.StoreLabels.AllowedStores...),
+			telemetry.WithMaxStores(config.Metrics.StoreLabels.MaxStores),
+			telemetry.WithRankingInterval(config.Metrics.StoreLabels.RankingInterval),
+		}
+		if config.Metrics.StoreLabels.ModelLabelsEnabled {
+			labelerOpts = append(labelerOpts, telemetry.WithModelLabels(config.Metrics.StoreLabels.MaxModels))
+		}
+		telemetry.SetStoreLabeler(telemetry.NewStoreLabeler(labelerOpts...))
+
+		serverOpts = append(serverOpts,
+			grpc.ChainUnaryInterceptor(storemetrics.NewUnaryInterceptor()),
+			grpc.ChainStreamInterceptor(storemetrics.NewStreamingInterceptor()))
+	}
+
 	if config.Trace.Enabled {
 		serverOpts = append(serverOpts, grpc.StatsHandler(otelgrpc.NewServerHandler()))
 	}
 
-	serverOpts = append(serverOpts, grpc.ChainUnaryInterceptor(
-		[]grpc.UnaryServerInterceptor{
-			grpcauth.UnaryServerInterceptor(authnmw.AuthFunc(authenticator)),
-		}...),
-		grpc.ChainStreamInterceptor(
-			[]grpc.StreamServerInterceptor{
-				grpcauth.StreamServerInterceptor(authnmw.AuthFunc(authenticator)),
-				// The following interceptors wrap the server stream with our own
-				// wrapper and must come last.
-				storeid.NewStreamingInterceptor(),
-				logging.NewStreamingLoggingInterceptor(s.Logger),
-			}...,
-		),
+	serverOpts = append(serverOpts,
+		grpc.ChainUnaryInterceptor(grpcauth.UnaryServerInterceptor(authnmw.AuthFunc(authenticator))),
+		grpc.ChainStreamInterceptor(grpcauth.StreamServerInterceptor(authnmw.AuthFunc(authenticator))),
 	)
 
+	rateLimiter, closeRateLimiter, err := s.rateLimiterConfig(config.RateLimit)
+	if err != nil {
+		return err
+	}
+	if rateLimiter != nil {
+		// Clients are identified by their credentials, so the rate limiter comes after authentication.
+		serverOpts = append(serverOpts,
+			grpc.ChainUnaryInterceptor(ratelimit.NewUnaryInterceptor(rateLimiter)),
+			grpc.ChainStreamInterceptor(ratelimit.NewStreamingInterceptor(rateLimiter)),
+		)
+	}
+
+	adaptiveLimiter := s.adaptiveLimiterConfig(config.Datastore.AdaptiveConcurrency)
+
+	loadShedder, err := s.loadShedderConfig(config.LoadShedding, adaptiveLimiter)
+	if err != nil {
+		return err
+	}
+	if loadShedder != nil {
+		// Requests are classified by client, so the load shedder comes after authentication. It comes after the
+		// rate limiter so that requests over their rate limit do not take a place in the queue.
+		serverOpts = append(serverOpts,
+			grpc.ChainUnaryInterceptor(loadshed.NewUnaryInterceptor(loadShedder)),
+			grpc.ChainStreamInterceptor(loadshed.NewStreamingInterceptor(loadShedder)),
+		)
+	}
+
+	serverOpts = append(serverOpts, grpc.ChainStreamInterceptor(
+		[]grpc.StreamServerInterceptor{
+			// The following interceptors wrap the server stream with our own
+			// wrapper and must come last.
+			storeid.NewStreamingInterceptor(),
+			logging.NewStreamingLoggingInterceptor(s.Logger),
+		}...,
+	))
+
 	if config.GRPC.TLS.Enabled {
 		if config.GRPC.TLS.CertPath == "" || config.GRPC.TLS.KeyPath == "" {
 			return errors.New("'grpc.tls.cert' and 'grpc.tls.key' configs must be set")
@@ -664,6 +1108,21 @@ func (s *ServerContext) Run(ctx context.Context, config *serverconfig.Config) er
 		}()
 	}
 
+	slowLog, err := s.slowLogConfig(config.SlowLog)
+	if err != nil {
+		return err
+	}
+
+	contextProviders, err := conditionContextProviders(config.ConditionContext)
+	if err != nil {
+		return err
+	}
+
+	decisionLogger, err := s.decisionLoggerConfig(ctx, config.DecisionLog)
+	if err != nil {
+		return err
+	}
+
 	svr := server.MustNewServerWithOpts(
 		server.WithDatastore(datastore),
 		server.WithContinuationTokenSerializer(continuationTokenSerializer),
@@ -677,12 +1136,17 @@ func (s *ServerContext) Run(ctx context.Context, config *serverconfig.Config) er
 		server.WithListObjectsMaxResults(config.ListObjectsMaxResults),
 		server.WithListUsersDeadline(config.ListUsersDeadline),
 		server.WithListUsersMaxResults(config.ListUsersMaxResults),
+		server.WithPermissionMatrixMaxObjects(config.PermissionMatrixMaxObjects),
+		server.WithAccessReviewMaxChanges(config.AccessReviewMaxChanges),
+		server.WithStoreStatsCacheTTL(config.StoreStatsCacheTTL),
 		server.WithMaxConcurrentReadsForListObjects(config.MaxConcurrentReadsForListObjects),
 		server.WithMaxConcurrentReadsForCheck(config.MaxConcurrentReadsForCheck),
 		server.WithMaxConcurrentReadsForListUsers(config.MaxConcurrentReadsForListUsers),
 		server.WithCacheControllerEnabled(config.CacheController.Enabled),
 		server.WithCacheControllerTTL(config.CacheController.TTL),
 		server.WithCheckCacheLimit(config.CheckCache.Limit),
+		server.WithCheckCacheBackend(config.CheckCache.Backend),
+		server.WithCheckCacheRedis(config.CheckCache.Redis),
 		server.WithCheckIteratorCacheEnabled(config.CheckIteratorCache.Enabled),
 		server.WithCheckIteratorCacheMaxResults(config.CheckIteratorCache.MaxResults),
 		server.WithCheckIteratorCacheTTL(config.CheckIteratorCache.TTL),
@@ -691,6 +1155,7 @@ func (s *ServerContext) Run(ctx context.Context, config *serverconfig.Config) er
 		server.WithRequestDurationByQueryHistogramBuckets(convertStringArrayToUintArray(config.RequestDurationDatastoreQueryCountBuckets)),
 		server.WithRequestDurationByDispatchCountHistogramBuckets(convertStringArrayToUintArray(config.RequestDurationDispatchCountBuckets)),
 		server.WithMaxAuthorizationModelSizeInBytes(config.MaxAuthorizationModelSizeInBytes),
+		server.WithConditionExtensionsEnabled(config.ConditionExtensionsEnabled),
 		server.WithContextPropagationToDatastore(config.ContextPropagationToDatastore),
 		server.WithDispatchThrottlingCheckResolverEnabled(config.CheckDispatchThrottling.Enabled),
 		server.WithDispatchThrottlingCheckResolverFrequency(config.CheckDispatchThrottling.Frequency),
@@ -723,6 +1188,12 @@ func (s *ServerContext) Run(ctx context.Context, config *serverconfig.Config) er
 		server.WithSharedIteratorTTL(config.RequestTimeout+2*time.Second),
 		server.WithExperimentals(experimentals...),
 		server.WithAccessControlParams(config.AccessControl.Enabled, config.AccessControl.StoreID, config.AccessControl.ModelID, config.Authn.Method),
+		server.WithConditionContextProviders(contextProviders...),
+		server.WithRateLimiter(rateLimiter),
+		server.WithLoadShedder(loadShedder),
+		server.WithDecisionLogger(decisionLogger),
+		server.WithSlowLog(slowLog),
+		server.WithDatastoreAdaptiveLimiter(adaptiveLimiter),
 		server.WithContext(ctx),
 	)
 
@@ -735,9 +1206,26 @@ func (s *ServerContext) Run(ctx context.Context, config *serverconfig.Config) er
 		zap.Any("config", config),
 	)
 
+	adminServer, err := s.adminServerConfig(config, svr, slowLog)
+	if err != nil {
+		return err
+	}
+	if adminServer != nil {
+		go func() {
+			s.Logger.Info(fmt.Sprintf("🛠 starting admin server on '%s'", config.Admin.Addr))
+			if err := adminServer.ListenAndServe(); err != nil {
+				if !errors.Is(err, http.ErrServerClosed) {
+					s.Logger.Fatal("failed to start admin server", zap.Error(err))
+				}
+			}
+			s.Logger.Info("admin server shut down.")
+		}()
+	}
+
 	// nosemgrep: grpc-server-insecure-connection
 	grpcServer := grpc.NewServer(serverOpts...)
 	openfgav1.RegisterOpenFGAServiceServer(grpcServer, svr)
+	server.RegisterStreamedListUsersServer(grpcServer, svr)
 	healthServer := &health.Checker{TargetService: svr, TargetServiceName: openfgav1.OpenFGAService_ServiceDesc.ServiceName}
 	healthv1pb.RegisterHealthServer(grpcServer, healthServer)
 	reflection.Register(grpcServer)
@@ -791,13 +1279,10 @@ func (s *ServerContext) Run(ctx context.Context, config *serverconfig.Config) er
 		muxOpts := []runtime.ServeMuxOption{
 			runtime.WithForwardResponseOption(httpmiddleware.HTTPResponseModifier),
 			runtime.WithErrorHandler(func(c context.Context, sr *runtime.ServeMux, mm runtime.Marshaler, w http.ResponseWriter, r *http.Request, e error) {
-				intCode := serverErrors.ConvertToEncodedErrorCode(status.Convert(e))
-				httpmiddleware.CustomHTTPErrorHandler(c, w, r, serverErrors.NewEncodedError(intCode, e.Error()))
+				httpmiddleware.CustomHTTPErrorHandler(c, w, r, serverErrors.EncodeError(e))
 			}),
 			runtime.WithStreamErrorHandler(func(ctx context.Context, e error) *status.Status {
-				intCode := serverErrors.ConvertToEncodedErrorCode(status.Convert(e))
-				encodedErr := serverErrors.NewEncodedError(intCode, e.Error())
-				return status.Convert(encodedErr)
+				return status.Convert(serverErrors.EncodeError(e))
 			}),
 			runtime.WithHealthzEndpoint(healthv1pb.NewHealthClient(conn)),
 			runtime.WithOutgoingHeaderMatcher(func(s string) (string, bool) { return s, true }),
@@ -806,6 +1291,9 @@ func (s *ServerContext) Run(ctx context.Context, config *serverconfig.Config) er
 		if err := openfgav1.RegisterOpenFGAServiceHandler(ctx, mux, conn); err != nil {
 			return err
 		}
+		if err := svr.RegisterHTTPHandlers(mux, authenticator); err != nil {
+			return err
+		}
 		handler := http.Handler(mux)
 
 		if config.Trace.Enabled {
@@ -965,16 +1453,38 @@ func (s *ServerContext) Run(ctx context.Context, config *serverconfig.Config) er
 		}
 	}
 
+	if adminServer != nil {
+		if err := adminServer.Shutdown(ctx); err != nil {
+			s.Logger.Info("failed to shutdown the admin server", zap.Error(err))
+		}
+	}
+
 	grpcServer.GracefulStop()
 
 	svr.Close()
 
 	authenticator.Close()
 
+	closeRateLimiter()
+
+	if loadShedder != nil {
+		loadShedder.Close()
+	}
+
+	if decisionLogger != nil {
+		if err := decisionLogger.Close(); err != nil {
+			s.Logger.Error("failed to close the decision log", zap.Error(err))
+		}
+	}
+
 	if err := tracerProviderCloser(); err != nil {
 		s.Logger.Error("failed to shutdown tracing", zap.Error(err))
 	}
 
+	if err := meterProviderCloser(); err != nil {
+		s.Logger.Error("failed to shutdown the OTLP metrics exporter", zap.Error(err))
+	}
+
 	s.Logger.Info("server exited. goodbye 👋")
 
 	return nil
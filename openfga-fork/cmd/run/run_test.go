@@ -788,6 +788,69 @@ func TestGRPCServingTLS(t *testing.T) {
 	})
 }
 
+func TestAdminServer(t *testing.T) {
+	t.Cleanup(func() {
+		goleak.VerifyNone(t)
+	})
+	cfg := testutils.MustDefaultConfigWithRandomPorts()
+	cfg.Authn.Method = "preshared"
+	cfg.Authn.AuthnPresharedKeyConfig = &serverconfig.AuthnPresharedKeyConfig{Keys: []string{"API-KEY"}}
+	cfg.Admin.Enabled = true
+	cfg.Admin.Keys = []string{"ADMIN-KEY"}
+	adminPort, adminPortReleaser := testutils.TCPRandomPort()
+	adminPortReleaser()
+	cfg.Admin.Addr = fmt.Sprintf("localhost:%d", adminPort)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go func() {
+		if err := runServer(ctx, cfg); err != nil {
+			log.Fatal(err)
+		}
+	}()
+
+	testutils.EnsureServiceHealthy(t, cfg.GRPC.Addr, cfg.HTTP.Addr, nil)
+
+	request := func(method, path, key, body string) *http.Response {
+		req, err := http.NewRequest(method, "http://"+cfg.Admin.Addr+path, strings.NewReader(body))
+		require.NoError(t, err)
+		req.Header.Set("Authorization", "Bearer "+key)
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		t.Cleanup(func() { resp.Body.Close() })
+		return resp
+	}
+
+	t.Run("requires_an_admin_key", func(t *testing.T) {
+		require.Equal(t, http.StatusUnauthorized, request(http.MethodGet, "/config", "API-KEY", "").StatusCode)
+	})
+
+	t.Run("config_without_secrets", func(t *testing.T) {
+		resp := request(http.MethodGet, "/config", "ADMIN-KEY", "")
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		require.Contains(t, string(body), cfg.Admin.Addr)
+		require.NotContains(t, string(body), "API-KEY")
+		require.NotContains(t, string(body), "ADMIN-KEY")
+	})
+
+	t.Run("log_level", func(t *testing.T) {
+		resp := request(http.MethodPut, "/loglevel", "ADMIN-KEY", `{"level":"debug"}`)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var level map[string]string
+		require.NoError(t, json.NewDecoder(request(http.MethodGet, "/loglevel", "ADMIN-KEY", "").Body).Decode(&level))
+		require.Equal(t, "debug", level["level"])
+	})
+
+	t.Run("caches", func(t *testing.T) {
+		require.Equal(t, http.StatusOK, request(http.MethodGet, "/caches", "ADMIN-KEY", "").StatusCode)
+		require.Equal(t, http.StatusOK, request(http.MethodPost, "/caches/flush?store_id=store", "ADMIN-KEY", "").StatusCode)
+	})
+}
+
 func TestServerMetricsReporting(t *testing.T) {
 	t.Cleanup(func() {
 		goleak.VerifyNone(t)
@@ -1063,6 +1126,18 @@ func TestDefaultConfig(t *testing.T) {
 	require.True(t, val.Exists())
 	require.Equal(t, val.String(), cfg.Profiler.Addr)
 
+	val = res.Get("properties.admin.properties.enabled.default")
+	require.True(t, val.Exists())
+	require.Equal(t, val.Bool(), cfg.Admin.Enabled)
+
+	val = res.Get("properties.admin.properties.addr.default")
+	require.True(t, val.Exists())
+	require.Equal(t, val.String(), cfg.Admin.Addr)
+
+	val = res.Get("properties.admin.properties.keys.default")
+	require.True(t, val.Exists())
+	require.Len(t, cfg.Admin.Keys, len(val.Array()))
+
 	val = res.Get("properties.authn.properties.method.default")
 	require.True(t, val.Exists())
 	require.Equal(t, val.String(), cfg.Authn.Method)
@@ -1099,6 +1174,10 @@ func TestDefaultConfig(t *testing.T) {
 	require.True(t, val.Exists())
 	require.Equal(t, val.Uint(), cfg.MaxConditionEvaluationCost)
 
+	val = res.Get("properties.conditionExtensionsEnabled.default")
+	require.True(t, val.Exists())
+	require.Equal(t, val.Bool(), cfg.ConditionExtensionsEnabled)
+
 	val = res.Get("properties.maxConcurrentReadsForListUsers.default")
 	require.True(t, val.Exists())
 	require.EqualValues(t, val.Int(), cfg.MaxConcurrentReadsForListUsers)
@@ -1139,6 +1218,18 @@ func TestDefaultConfig(t *testing.T) {
 	require.True(t, val.Exists())
 	require.EqualValues(t, val.Int(), cfg.ListUsersMaxResults)
 
+	val = res.Get("properties.permissionMatrixMaxObjects.default")
+	require.True(t, val.Exists())
+	require.EqualValues(t, val.Int(), cfg.PermissionMatrixMaxObjects)
+
+	val = res.Get("properties.accessReviewMaxChanges.default")
+	require.True(t, val.Exists())
+	require.EqualValues(t, val.Int(), cfg.AccessReviewMaxChanges)
+
+	val = res.Get("properties.storeStatsCacheTTL.default")
+	require.True(t, val.Exists())
+	require.Equal(t, val.String(), cfg.StoreStatsCacheTTL.String())
+
 	val = res.Get("properties.experimentals.default")
 	require.True(t, val.Exists())
 	require.Len(t, cfg.Experimentals, len(val.Array()))
@@ -1171,6 +1262,178 @@ func TestDefaultConfig(t *testing.T) {
 	require.True(t, val.Exists())
 	require.EqualValues(t, val.Int(), cfg.CheckCache.Limit)
 
+	val = res.Get("properties.checkCache.properties.backend.default")
+	require.True(t, val.Exists())
+	require.Equal(t, val.String(), cfg.CheckCache.Backend)
+
+	val = res.Get("properties.checkCache.properties.redis.properties.db.default")
+	require.True(t, val.Exists())
+	require.EqualValues(t, val.Int(), cfg.CheckCache.Redis.DB)
+
+	val = res.Get("properties.checkCache.properties.redis.properties.localTTL.default")
+	require.True(t, val.Exists())
+	require.Equal(t, val.String(), cfg.CheckCache.Redis.LocalTTL.String())
+
+	val = res.Get("properties.checkCache.properties.redis.properties.timeout.default")
+	require.True(t, val.Exists())
+	require.Equal(t, val.String(), cfg.CheckCache.Redis.Timeout.String())
+
+	val = res.Get("properties.conditionContext.properties.requestTimeKey.default")
+	require.True(t, val.Exists())
+	require.Equal(t, val.String(), cfg.ConditionContext.RequestTimeKey)
+
+	val = res.Get("properties.conditionContext.properties.peerIPKey.default")
+	require.True(t, val.Exists())
+	require.Equal(t, val.String(), cfg.ConditionContext.PeerIPKey)
+
+	val = res.Get("properties.rateLimit.properties.enabled.default")
+	require.True(t, val.Exists())
+	require.Equal(t, val.Bool(), cfg.RateLimit.Enabled)
+
+	val = res.Get("properties.rateLimit.properties.requestsPerSecond.default")
+	require.True(t, val.Exists())
+	require.InDelta(t, val.Float(), cfg.RateLimit.RequestsPerSecond, 0)
+
+	val = res.Get("properties.rateLimit.properties.burst.default")
+	require.True(t, val.Exists())
+	require.EqualValues(t, val.Int(), cfg.RateLimit.Burst)
+
+	val = res.Get("properties.rateLimit.properties.backend.default")
+	require.True(t, val.Exists())
+	require.Equal(t, val.String(), cfg.RateLimit.Backend)
+
+	val = res.Get("properties.rateLimit.properties.redis.properties.timeout.default")
+	require.True(t, val.Exists())
+	require.Equal(t, val.String(), cfg.RateLimit.Redis.Timeout.String())
+
+	val = res.Get("properties.decisionLog.properties.enabled.default")
+	require.True(t, val.Exists())
+	require.Equal(t, val.Bool(), cfg.DecisionLog.Enabled)
+
+	val = res.Get("properties.decisionLog.properties.sink.default")
+	require.True(t, val.Exists())
+	require.Equal(t, val.String(), cfg.DecisionLog.Sink)
+
+	val = res.Get("properties.decisionLog.properties.file.properties.maxSizeMB.default")
+	require.True(t, val.Exists())
+	require.EqualValues(t, val.Int(), cfg.DecisionLog.File.MaxSizeMB)
+
+	val = res.Get("properties.decisionLog.properties.file.properties.maxBackups.default")
+	require.True(t, val.Exists())
+	require.EqualValues(t, val.Int(), cfg.DecisionLog.File.MaxBackups)
+
+	val = res.Get("properties.decisionLog.properties.otlp.properties.endpoint.default")
+	require.True(t, val.Exists())
+	require.Equal(t, val.String(), cfg.DecisionLog.OTLP.Endpoint)
+
+	val = res.Get("properties.decisionLog.properties.sampleRate.default")
+	require.True(t, val.Exists())
+	require.InDelta(t, val.Float(), cfg.DecisionLog.SampleRate, 0)
+
+	val = res.Get("properties.slowLog.properties.enabled.default")
+	require.True(t, val.Exists())
+	require.Equal(t, val.Bool(), cfg.SlowLog.Enabled)
+
+	val = res.Get("properties.slowLog.properties.threshold.default")
+	require.True(t, val.Exists())
+	require.Equal(t, val.String(), cfg.SlowLog.Threshold.String())
+
+	val = res.Get("properties.slowLog.properties.capacity.default")
+	require.True(t, val.Exists())
+	require.EqualValues(t, val.Int(), cfg.SlowLog.Capacity)
+
+	val = res.Get("properties.datastore.properties.adaptiveConcurrency.properties.enabled.default")
+	require.True(t, val.Exists())
+	require.Equal(t, val.Bool(), cfg.Datastore.AdaptiveConcurrency.Enabled)
+
+	val = res.Get("properties.datastore.properties.adaptiveConcurrency.properties.minLimit.default")
+	require.True(t, val.Exists())
+	require.EqualValues(t, val.Int(), cfg.Datastore.AdaptiveConcurrency.MinLimit)
+
+	val = res.Get("properties.datastore.properties.adaptiveConcurrency.properties.maxLimit.default")
+	require.True(t, val.Exists())
+	require.EqualValues(t, val.Int(), cfg.Datastore.AdaptiveConcurrency.MaxLimit)
+
+	val = res.Get("properties.datastore.properties.adaptiveConcurrency.properties.latencyThreshold.default")
+	require.True(t, val.Exists())
+	require.Equal(t, val.String(), cfg.Datastore.AdaptiveConcurrency.LatencyThreshold.String())
+
+	val = res.Get("properties.datastore.properties.adaptiveConcurrency.properties.backoffRatio.default")
+	require.True(t, val.Exists())
+	require.InDelta(t, val.Float(), cfg.Datastore.AdaptiveConcurrency.BackoffRatio, 0)
+
+	val = res.Get("properties.loadShedding.properties.enabled.default")
+	require.True(t, val.Exists())
+	require.Equal(t, val.Bool(), cfg.LoadShedding.Enabled)
+
+	val = res.Get("properties.loadShedding.properties.defaultClass.default")
+	require.True(t, val.Exists())
+	require.Equal(t, val.String(), cfg.LoadShedding.DefaultClass)
+
+	val = res.Get("properties.loadShedding.properties.interactiveMaxInFlight.default")
+	require.True(t, val.Exists())
+	require.EqualValues(t, val.Int(), cfg.LoadShedding.InteractiveMaxInFlight)
+
+	val = res.Get("properties.loadShedding.properties.defaultMaxInFlight.default")
+	require.True(t, val.Exists())
+	require.EqualValues(t, val.Int(), cfg.LoadShedding.DefaultMaxInFlight)
+
+	val = res.Get("properties.loadShedding.properties.backgroundMaxInFlight.default")
+	require.True(t, val.Exists())
+	require.EqualValues(t, val.Int(), cfg.LoadShedding.BackgroundMaxInFlight)
+
+	val = res.Get("properties.loadShedding.properties.datastoreLatencyThreshold.default")
+	require.True(t, val.Exists())
+	require.Equal(t, val.String(), cfg.LoadShedding.DatastoreLatencyThreshold.String())
+
+	val = res.Get("properties.loadShedding.properties.queueTimeout.default")
+	require.True(t, val.Exists())
+	require.Equal(t, val.String(), cfg.LoadShedding.QueueTimeout.String())
+
+	val = res.Get("properties.loadShedding.properties.queueFrequency.default")
+	require.True(t, val.Exists())
+	require.Equal(t, val.String(), cfg.LoadShedding.QueueFrequency.String())
+
+	val = res.Get("properties.loadShedding.properties.retryAfter.default")
+	require.True(t, val.Exists())
+	require.Equal(t, val.String(), cfg.LoadShedding.RetryAfter.String())
+
+	val = res.Get("properties.metrics.properties.otlp.properties.enabled.default")
+	require.True(t, val.Exists())
+	require.Equal(t, val.Bool(), cfg.Metrics.OTLP.Enabled)
+
+	val = res.Get("properties.metrics.properties.otlp.properties.endpoint.default")
+	require.True(t, val.Exists())
+	require.Equal(t, val.String(), cfg.Metrics.OTLP.Endpoint)
+
+	val = res.Get("properties.metrics.properties.otlp.properties.protocol.default")
+	require.True(t, val.Exists())
+	require.Equal(t, val.String(), cfg.Metrics.OTLP.Protocol)
+
+	val = res.Get("properties.metrics.properties.otlp.properties.exportInterval.default")
+	require.True(t, val.Exists())
+	require.Equal(t, val.String(), cfg.Metrics.OTLP.ExportInterval.String())
+
+	val = res.Get("properties.metrics.properties.storeLabels.properties.enabled.default")
+	require.True(t, val.Exists())
+	require.Equal(t, val.Bool(), cfg.Metrics.StoreLabels.Enabled)
+
+	val = res.Get("properties.metrics.properties.storeLabels.properties.maxStores.default")
+	require.True(t, val.Exists())
+	require.EqualValues(t, val.Int(), cfg.Metrics.StoreLabels.MaxStores)
+
+	val = res.Get("properties.metrics.properties.storeLabels.properties.modelLabelsEnabled.default")
+	require.True(t, val.Exists())
+	require.Equal(t, val.Bool(), cfg.Metrics.StoreLabels.ModelLabelsEnabled)
+
+	val = res.Get("properties.metrics.properties.storeLabels.properties.maxModels.default")
+	require.True(t, val.Exists())
+	require.EqualValues(t, val.Int(), cfg.Metrics.StoreLabels.MaxModels)
+
+	val = res.Get("properties.metrics.properties.storeLabels.properties.rankingInterval.default")
+	require.True(t, val.Exists())
+	require.Equal(t, val.String(), cfg.Metrics.StoreLabels.RankingInterval.String())
+
 	val = res.Get("properties.checkQueryCache.properties.enabled.default")
 	require.True(t, val.Exists())
 	require.Equal(t, val.Bool(), cfg.CheckQueryCache.Enabled)
@@ -0,0 +1,74 @@
+// Package storestats contains the command to print the tuple counts and other statistics of a store.
+package storestats
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/openfga/openfga/cmd/util"
+	"github.com/openfga/openfga/pkg/server/commands"
+)
+
+const (
+	datastoreEngineFlag = "datastore-engine"
+	datastoreURIFlag    = "datastore-uri"
+	storeIDFlag         = "store-id"
+)
+
+func NewStoreStatsCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "store-stats",
+		Short: "Print the tuple counts and other statistics of a store.",
+		Long: "Print, as JSON, the number of tuples of a store grouped by object type, relation and user type,\n" +
+			"the size of its changelog, the number of its authorization models and its latest change.\n" +
+			"The statistics are read from the datastore with aggregate queries.",
+		RunE: runStoreStats,
+		Args: cobra.NoArgs,
+	}
+
+	flags := cmd.Flags()
+	flags.String(datastoreEngineFlag, "", "the datastore engine")
+	flags.String(datastoreURIFlag, "", "the connection uri to the datastore")
+	flags.String(storeIDFlag, "", "the id of the store")
+
+	// NOTE: if you add a new flag here, update the function below, too
+
+	cmd.PreRun = bindRunFlagsFunc(flags)
+
+	return cmd
+}
+
+func runStoreStats(cmd *cobra.Command, _ []string) error {
+	req := &commands.GetStoreStatsRequest{StoreID: viper.GetString(storeIDFlag)}
+	if err := req.Validate(); err != nil {
+		return err
+	}
+
+	db, err := util.OpenDatastore(viper.GetString(datastoreEngineFlag), viper.GetString(datastoreURIFlag))
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	backend, ok := db.(commands.StoreStatsBackend)
+	if !ok {
+		return fmt.Errorf("the %s datastore does not support store statistics", viper.GetString(datastoreEngineFlag))
+	}
+
+	res, err := commands.NewGetStoreStatsQuery(backend).Execute(context.Background(), req)
+	if err != nil {
+		return err
+	}
+
+	marshalled, err := json.MarshalIndent(res, "", "    ")
+	if err != nil {
+		return fmt.Errorf("error marshalling the store statistics: %w", err)
+	}
+	fmt.Fprintln(cmd.OutOrStdout(), string(marshalled))
+
+	return nil
+}
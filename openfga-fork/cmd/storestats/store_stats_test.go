@@ -0,0 +1,75 @@
+package storestats
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/oklog/ulid/v2"
+	"github.com/stretchr/testify/require"
+
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+
+	"github.com/openfga/openfga/cmd/util"
+	"github.com/openfga/openfga/pkg/server/commands"
+	"github.com/openfga/openfga/pkg/tuple"
+)
+
+func TestStoreStatsCommand(t *testing.T) {
+	_, ds, uri := util.MustBootstrapDatastore(t, "sqlite")
+
+	ctx := context.Background()
+	storeID := ulid.Make().String()
+	_, err := ds.CreateStore(ctx, &openfgav1.Store{Id: storeID, Name: "store"})
+	require.NoError(t, err)
+
+	err = ds.Write(ctx, storeID, nil, []*openfgav1.TupleKey{
+		tuple.NewTupleKey("document:1", "viewer", "user:anne"),
+		tuple.NewTupleKey("document:1", "viewer", "group:eng#member"),
+		tuple.NewTupleKey("group:eng", "member", "user:bob"),
+	})
+	require.NoError(t, err)
+
+	cmd := NewStoreStatsCommand()
+	out := &bytes.Buffer{}
+	cmd.SetOut(out)
+	cmd.SetArgs([]string{"--datastore-engine", "sqlite", "--datastore-uri", uri, "--store-id", storeID})
+	require.NoError(t, cmd.Execute())
+
+	var res commands.GetStoreStatsResponse
+	require.NoError(t, json.Unmarshal(out.Bytes(), &res))
+	require.Equal(t, int64(3), res.TupleCount)
+	require.Equal(t, []commands.StoreStatsTupleCount{
+		{ObjectType: "document", Relation: "viewer", UserType: "group", Count: 1},
+		{ObjectType: "document", Relation: "viewer", UserType: "user", Count: 1},
+		{ObjectType: "group", Relation: "member", UserType: "user", Count: 1},
+	}, res.TupleCounts)
+	require.Equal(t, int64(3), res.ChangelogCount)
+}
+
+func TestStoreStatsCommandErrors(t *testing.T) {
+	for _, tc := range []struct {
+		name          string
+		args          []string
+		errorExpected string
+	}{
+		{
+			name:          "invalid_store_id",
+			args:          []string{"--datastore-engine", "sqlite", "--store-id", "invalid"},
+			errorExpected: "invalid store_id",
+		},
+		{
+			name:          "unsupported_engine",
+			args:          []string{"--datastore-engine", "memory", "--store-id", ulid.Make().String()},
+			errorExpected: "storage engine 'memory' is unsupported",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			cmd := NewStoreStatsCommand()
+			cmd.SetArgs(tc.args)
+			err := cmd.Execute()
+			require.ErrorContains(t, err, tc.errorExpected)
+		})
+	}
+}
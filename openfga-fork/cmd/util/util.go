@@ -3,6 +3,7 @@
 package util
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 	"testing"
@@ -17,6 +18,7 @@ import (
 	"github.com/openfga/openfga/pkg/storage/postgres"
 	"github.com/openfga/openfga/pkg/storage/sqlcommon"
 	"github.com/openfga/openfga/pkg/storage/sqlite"
+	"github.com/openfga/openfga/pkg/storage/sqlserver"
 	storagefixtures "github.com/openfga/openfga/pkg/testfixtures/storage"
 )
 
@@ -47,6 +49,38 @@ func Index[E comparable](s []E, v E) int {
 	return -1
 }
 
+// OpenDatastore opens a connection to the SQL datastore of the given engine. It is used by the
+// commands that operate directly on a datastore instead of going through a running server.
+func OpenDatastore(engine, uri string) (storage.OpenFGADatastore, error) {
+	var (
+		db  storage.OpenFGADatastore
+		err error
+	)
+	cfg := sqlcommon.NewConfig()
+	switch engine {
+	case "mysql":
+		db, err = mysql.New(uri, cfg)
+	case "postgres":
+		db, err = postgres.New(uri, cfg)
+	case "sqlite":
+		db, err = sqlite.New(uri, cfg)
+	case "sqlserver":
+		db, err = sqlserver.New(uri, cfg)
+	case "":
+		return nil, fmt.Errorf("missing datastore engine type")
+	case "memory":
+		fallthrough
+	default:
+		return nil, fmt.Errorf("storage engine '%s' is unsupported", engine)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to open a connection to the datastore: %v", err)
+	}
+
+	return db, nil
+}
+
 // MustBootstrapDatastore returns the datastore's container, the datastore, and the URI to connect to it.
 // It automatically cleans up the container after the test finishes.
 func MustBootstrapDatastore(t testing.TB, engine string) (storagefixtures.DatastoreTestContainer, storage.OpenFGADatastore, string) {
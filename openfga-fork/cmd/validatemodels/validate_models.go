@@ -9,11 +9,8 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 
+	"github.com/openfga/openfga/cmd/util"
 	"github.com/openfga/openfga/pkg/storage"
-	"github.com/openfga/openfga/pkg/storage/mysql"
-	"github.com/openfga/openfga/pkg/storage/postgres"
-	"github.com/openfga/openfga/pkg/storage/sqlcommon"
-	"github.com/openfga/openfga/pkg/storage/sqlite"
 	"github.com/openfga/openfga/pkg/typesystem"
 )
 
@@ -55,29 +52,11 @@ func runValidate(_ *cobra.Command, _ []string) error {
 
 	ctx := context.Background()
 
-	var (
-		db  storage.OpenFGADatastore
-		err error
-	)
-	cfg := sqlcommon.NewConfig()
-	switch engine {
-	case "mysql":
-		db, err = mysql.New(uri, cfg)
-	case "postgres":
-		db, err = postgres.New(uri, cfg)
-	case "sqlite":
-		db, err = sqlite.New(uri, cfg)
-	case "":
-		return fmt.Errorf("missing datastore engine type")
-	case "memory":
-		fallthrough
-	default:
-		return fmt.Errorf("storage engine '%s' is unsupported", engine)
-	}
-
+	db, err := util.OpenDatastore(engine, uri)
 	if err != nil {
-		return fmt.Errorf("failed to open a connection to the datastore: %v", err)
+		return err
 	}
+	defer db.Close()
 
 	validationResults, err := ValidateAllAuthorizationModels(ctx, db)
 	if err != nil {
@@ -147,41 +147,53 @@ func (e *authorizationError) Error() string {
 func (a *Authorizer) getRelation(apiMethod apimethod.APIMethod) (string, error) {
 	// TODO: Add a golangci-linter rule to ensure all the possible cases are handled.
 	switch apiMethod {
-	case apimethod.ReadAuthorizationModel, apimethod.ReadAuthorizationModels:
+	case apimethod.ReadAuthorizationModel, apimethod.ReadAuthorizationModels, apimethod.ReadActiveAuthorizationModel:
 		return CanCallReadAuthorizationModels, nil
-	case apimethod.Read:
+	case apimethod.Read, apimethod.SearchTuples:
 		return CanCallRead, nil
-	case apimethod.Write:
+	case apimethod.Write, apimethod.WhatIf:
 		return CanCallWrite, nil
 	case apimethod.ListObjects, apimethod.StreamedListObjects:
 		return CanCallListObjects, nil
-	case apimethod.Check, apimethod.BatchCheck:
+	case apimethod.Check, apimethod.BatchCheck, apimethod.StreamedBatchCheck, apimethod.ListRelations:
 		return CanCallCheck, nil
-	case apimethod.ListUsers:
+	case apimethod.ListUsers, apimethod.StreamedListUsers, apimethod.PermissionMatrix:
 		return CanCallListUsers, nil
 	case apimethod.WriteAssertions:
 		return CanCallWriteAssertions, nil
 	case apimethod.ReadAssertions:
 		return CanCallReadAssertions, nil
-	case apimethod.WriteAuthorizationModel:
+	case apimethod.WriteAuthorizationModel, apimethod.WriteActiveAuthorizationModel, apimethod.RollbackActiveAuthorizationModel:
 		return CanCallWriteAuthorizationModels, nil
 	case apimethod.ListStores:
 		return CanCallListStores, nil
 	case apimethod.CreateStore:
 		return CanCallCreateStore, nil
-	case apimethod.GetStore:
+	case apimethod.GetStore, apimethod.GetStoreStats:
 		return CanCallGetStore, nil
 	case apimethod.DeleteStore:
 		return CanCallDeleteStore, nil
 	case apimethod.Expand:
 		return CanCallExpand, nil
-	case apimethod.ReadChanges:
+	case apimethod.ReadChanges, apimethod.AccessReview:
 		return CanCallReadChanges, nil
 	default:
 		return "", fmt.Errorf("unknown API method: %s", apiMethod)
 	}
 }
 
+// getAdditionalRelations returns the relations that apiMethod requires on the store besides the one of
+// getRelation.
+func getAdditionalRelations(apiMethod apimethod.APIMethod) []string {
+	switch apiMethod {
+	case apimethod.PermissionMatrix:
+		// The permission matrix enumerates the objects from the tuples of the store.
+		return []string{CanCallRead}
+	default:
+		return nil
+	}
+}
+
 func (a *Authorizer) AccessControlStoreID() string {
 	if a.config != nil {
 		return a.config.StoreID
@@ -217,6 +229,12 @@ func (a *Authorizer) Authorize(ctx context.Context, storeID string, apiMethod ap
 		},
 	}
 
+	for _, additionalRelation := range getAdditionalRelations(apiMethod) {
+		if err := a.individualAuthorize(ctx, claims.ClientID, additionalRelation, StoreIDType(storeID).String(), &contextualTuples); err != nil {
+			return err
+		}
+	}
+
 	// Check if there is top-level authorization first, before checking modules
 	err = a.individualAuthorize(ctx, claims.ClientID, relation, StoreIDType(storeID).String(), &contextualTuples)
 	if err == nil {
@@ -36,20 +36,31 @@ func TestGetRelation(t *testing.T) {
 		{method: apimethod.ReadAuthorizationModel, expectedResult: CanCallReadAuthorizationModels},
 		{method: apimethod.ReadAuthorizationModels, expectedResult: CanCallReadAuthorizationModels},
 		{method: apimethod.Read, expectedResult: CanCallRead},
+		{method: apimethod.SearchTuples, expectedResult: CanCallRead},
 		{method: apimethod.Write, expectedResult: CanCallWrite},
 		{method: apimethod.ListObjects, expectedResult: CanCallListObjects},
 		{method: apimethod.StreamedListObjects, expectedResult: CanCallListObjects},
 		{method: apimethod.Check, expectedResult: CanCallCheck},
 		{method: apimethod.BatchCheck, expectedResult: CanCallCheck},
+		{method: apimethod.ListRelations, expectedResult: CanCallCheck},
+		{method: apimethod.StreamedBatchCheck, expectedResult: CanCallCheck},
 		{method: apimethod.ListUsers, expectedResult: CanCallListUsers},
+		{method: apimethod.StreamedListUsers, expectedResult: CanCallListUsers},
 		{method: apimethod.WriteAssertions, expectedResult: CanCallWriteAssertions},
 		{method: apimethod.ReadAssertions, expectedResult: CanCallReadAssertions},
 		{method: apimethod.WriteAuthorizationModel, expectedResult: CanCallWriteAuthorizationModels},
 		{method: apimethod.CreateStore, expectedResult: CanCallCreateStore},
 		{method: apimethod.GetStore, expectedResult: CanCallGetStore},
+		{method: apimethod.GetStoreStats, expectedResult: CanCallGetStore},
 		{method: apimethod.DeleteStore, expectedResult: CanCallDeleteStore},
 		{method: apimethod.Expand, expectedResult: CanCallExpand},
 		{method: apimethod.ReadChanges, expectedResult: CanCallReadChanges},
+		{method: apimethod.ReadActiveAuthorizationModel, expectedResult: CanCallReadAuthorizationModels},
+		{method: apimethod.WriteActiveAuthorizationModel, expectedResult: CanCallWriteAuthorizationModels},
+		{method: apimethod.RollbackActiveAuthorizationModel, expectedResult: CanCallWriteAuthorizationModels},
+		{method: apimethod.PermissionMatrix, expectedResult: CanCallListUsers},
+		{method: apimethod.AccessReview, expectedResult: CanCallReadChanges},
+		{method: apimethod.WhatIf, expectedResult: CanCallWrite},
 		{method: "Unknown", errorMsg: "unknown API method: Unknown"},
 	}
 
@@ -298,6 +309,30 @@ func TestAuthorize(t *testing.T) {
 		require.NoError(t, err)
 	})
 
+	t.Run("permission_matrix_requires_read", func(t *testing.T) {
+		t.Parallel()
+
+		mockController, mockServer, authorizer := setupAuthorizerAndController(t, storeID, modelID)
+		defer mockController.Finish()
+
+		canCall := func(relation string) any {
+			return gomock.Cond(func(req *openfgav1.CheckRequest) bool {
+				return req.GetTupleKey().GetRelation() == relation
+			})
+		}
+		mockServer.EXPECT().Check(gomock.Any(), canCall(CanCallRead)).Return(&openfgav1.CheckResponse{Allowed: false}, nil)
+		ctx := authclaims.ContextWithAuthClaims(context.Background(), &authclaims.AuthClaims{ClientID: "test-client"})
+
+		err := authorizer.Authorize(ctx, storeID, apimethod.PermissionMatrix)
+		require.ErrorContains(t, err, "check returned not allowed")
+
+		mockServer.EXPECT().Check(gomock.Any(), canCall(CanCallRead)).Return(&openfgav1.CheckResponse{Allowed: true}, nil)
+		mockServer.EXPECT().Check(gomock.Any(), canCall(CanCallListUsers)).Return(&openfgav1.CheckResponse{Allowed: true}, nil)
+
+		err = authorizer.Authorize(ctx, storeID, apimethod.PermissionMatrix)
+		require.NoError(t, err)
+	})
+
 	t.Run("succeed_with_modules", func(t *testing.T) {
 		t.Parallel()
 
@@ -27,17 +27,17 @@ import (
 var (
 	tracer = otel.Tracer("internal/cachecontroller")
 
-	cacheTotalCounter = promauto.NewCounter(prometheus.CounterOpts{
+	cacheTotalCounter = telemetry.StoreLabeled(promauto.NewCounterVec(prometheus.CounterOpts{
 		Namespace: build.ProjectName,
 		Name:      "cachecontroller_cache_total_count",
 		Help:      "The total number of cachecontroller requests.",
-	})
+	}, []string{telemetry.StoreIDLabel}))
 
-	cacheHitCounter = promauto.NewCounter(prometheus.CounterOpts{
+	cacheHitCounter = telemetry.StoreLabeled(promauto.NewCounterVec(prometheus.CounterOpts{
 		Namespace: build.ProjectName,
 		Name:      "cachecontroller_cache_hit_count",
 		Help:      "The total number of cache hits from cachecontroller requests.",
-	})
+	}, []string{telemetry.StoreIDLabel}))
 
 	cacheInvalidationCounter = promauto.NewCounter(prometheus.CounterOpts{
 		Namespace: build.ProjectName,
@@ -134,7 +134,7 @@ func (c *InMemoryCacheController) DetermineInvalidationTime(
 ) time.Time {
 	ctx, span := tracer.Start(ctx, "cacheController.DetermineInvalidationTime", trace.WithAttributes(attribute.Bool("cached", false)))
 	defer span.End()
-	cacheTotalCounter.Inc()
+	cacheTotalCounter.WithLabelValues(telemetry.StoreLabelFromContext(ctx, storeID)).Inc()
 
 	cacheKey := storage.GetChangelogCacheKey(storeID)
 	cacheResp := c.cache.Get(cacheKey)
@@ -146,7 +146,7 @@ func (c *InMemoryCacheController) DetermineInvalidationTime(
 		if entry, ok := cacheResp.(*storage.ChangelogCacheEntry); ok {
 			// the TTL grace period hasn't been breached
 			if entry.LastModified.Add(c.ttl).After(time.Now()) {
-				cacheHitCounter.Inc()
+				cacheHitCounter.WithLabelValues(telemetry.StoreLabelFromContext(ctx, storeID)).Inc()
 				span.SetAttributes(attribute.Bool("cached", true))
 				return entry.LastModified
 			}
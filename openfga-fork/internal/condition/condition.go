@@ -24,6 +24,9 @@ var tracer = otel.Tracer("openfga/internal/condition")
 
 var celBaseEnv *cel.Env
 
+// celExtendedEnv is celBaseEnv with the condition extension functions.
+var celExtendedEnv *cel.Env
+
 func init() {
 	var envOpts []cel.EnvOption
 	for _, customTypeOpts := range types.CustomParamTypes {
@@ -38,6 +41,13 @@ func init() {
 	}
 
 	celBaseEnv = env
+
+	extendedEnv, err := celBaseEnv.Extend(types.ExtensionsEnvOption())
+	if err != nil {
+		panic(fmt.Sprintf("failed to construct CEL extended env: %v", err))
+	}
+
+	celExtendedEnv = extendedEnv
 }
 
 var emptyEvaluationResult = EvaluationResult{}
@@ -56,6 +66,7 @@ type EvaluableCondition struct {
 	*openfgav1.Condition
 
 	celProgramOpts []cel.ProgramOption
+	extensions     bool
 	celEnv         *cel.Env
 	celProgram     cel.Program
 	compileOnce    sync.Once
@@ -105,7 +116,12 @@ func (e *EvaluableCondition) compile() error {
 		envOpts = append(envOpts, cel.Variable(paramName, paramType.CelType()))
 	}
 
-	env, err := celBaseEnv.Extend(envOpts...)
+	baseEnv := celBaseEnv
+	if e.extensions {
+		baseEnv = celExtendedEnv
+	}
+
+	env, err := baseEnv.Extend(envOpts...)
 	if err != nil {
 		return &CompilationError{
 			Condition: e.Name,
@@ -315,6 +331,15 @@ func (e *EvaluableCondition) WithInterruptCheckFrequency(checkFrequency uint) *E
 	return e
 }
 
+// WithExtensions makes the functions of the types.Extensions library available to the condition expression
+// and returns the mutated EvaluableCondition. The expectation is that this is called on the Uncompiled
+// condition because it modifies the CEL environment that is constructed in Compile.
+func (e *EvaluableCondition) WithExtensions() *EvaluableCondition {
+	e.extensions = true
+
+	return e
+}
+
 // NewUncompiled returns a new EvaluableCondition that has not
 // validated and compiled its expression.
 func NewUncompiled(condition *openfgav1.Condition) *EvaluableCondition {
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 
@@ -708,3 +709,182 @@ func mustConvertValue(varType types.ParameterType, value any) any {
 
 	return convertedParam
 }
+
+func TestEvaluateWithExtensions(t *testing.T) {
+	listOf := func(typeName openfgav1.ConditionParamTypeRef_TypeName) *openfgav1.ConditionParamTypeRef {
+		return &openfgav1.ConditionParamTypeRef{
+			TypeName:     openfgav1.ConditionParamTypeRef_TYPE_NAME_LIST,
+			GenericTypes: []*openfgav1.ConditionParamTypeRef{{TypeName: typeName}},
+		}
+	}
+
+	var tests = []struct {
+		name       string
+		expression string
+		parameters map[string]*openfgav1.ConditionParamTypeRef
+		context    map[string]any
+		maxCost    uint64
+		result     bool
+		err        error
+	}{
+		{
+			name:       "in_business_hours",
+			expression: `inBusinessHours(ts, "Europe/Oslo")`,
+			parameters: map[string]*openfgav1.ConditionParamTypeRef{
+				"ts": {TypeName: openfgav1.ConditionParamTypeRef_TYPE_NAME_TIMESTAMP},
+			},
+			// Monday 07:30 UTC is 09:30 in Oslo (CEST).
+			context: map[string]any{"ts": "2024-06-03T07:30:00Z"},
+			result:  true,
+		},
+		{
+			name:       "before_business_hours",
+			expression: `inBusinessHours(ts, "Europe/Oslo")`,
+			parameters: map[string]*openfgav1.ConditionParamTypeRef{
+				"ts": {TypeName: openfgav1.ConditionParamTypeRef_TYPE_NAME_TIMESTAMP},
+			},
+			context: map[string]any{"ts": "2024-06-03T06:30:00Z"},
+			result:  false,
+		},
+		{
+			name:       "custom_business_hours",
+			expression: `inBusinessHours(ts, "America/New_York", 6, 10)`,
+			parameters: map[string]*openfgav1.ConditionParamTypeRef{
+				"ts": {TypeName: openfgav1.ConditionParamTypeRef_TYPE_NAME_TIMESTAMP},
+			},
+			// Monday 11:00 UTC is 07:00 in New York (EDT).
+			context: map[string]any{"ts": "2024-06-03T11:00:00Z"},
+			result:  true,
+		},
+		{
+			name:       "weekend",
+			expression: `dayOfWeek(ts, "Asia/Tokyo") == "Sunday" && !inBusinessHours(ts, "Asia/Tokyo")`,
+			parameters: map[string]*openfgav1.ConditionParamTypeRef{
+				"ts": {TypeName: openfgav1.ConditionParamTypeRef_TYPE_NAME_TIMESTAMP},
+			},
+			// Saturday 16:00 UTC is Sunday 01:00 in Tokyo.
+			context: map[string]any{"ts": "2024-06-01T16:00:00Z"},
+			result:  true,
+		},
+		{
+			name:       "invalid_time_zone",
+			expression: `inBusinessHours(ts, "Mars/Olympus_Mons")`,
+			parameters: map[string]*openfgav1.ConditionParamTypeRef{
+				"ts": {TypeName: openfgav1.ConditionParamTypeRef_TYPE_NAME_TIMESTAMP},
+			},
+			context: map[string]any{"ts": "2024-06-03T07:30:00Z"},
+			err:     fmt.Errorf("'Mars/Olympus_Mons' is not a valid time zone"),
+		},
+		{
+			name:       "in_any_cidr",
+			expression: `user_ip.inAnyCIDR(cidrs)`,
+			parameters: map[string]*openfgav1.ConditionParamTypeRef{
+				"user_ip": {TypeName: openfgav1.ConditionParamTypeRef_TYPE_NAME_IPADDRESS},
+				"cidrs":   listOf(openfgav1.ConditionParamTypeRef_TYPE_NAME_STRING),
+			},
+			context: map[string]any{"user_ip": "10.1.2.3", "cidrs": []any{"192.168.0.0/16", "10.0.0.0/8"}},
+			result:  true,
+		},
+		{
+			name:       "in_any_cidr_cost_exceeded",
+			expression: `user_ip.inAnyCIDR(cidrs)`,
+			parameters: map[string]*openfgav1.ConditionParamTypeRef{
+				"user_ip": {TypeName: openfgav1.ConditionParamTypeRef_TYPE_NAME_IPADDRESS},
+				"cidrs":   listOf(openfgav1.ConditionParamTypeRef_TYPE_NAME_STRING),
+			},
+			context: map[string]any{"user_ip": "10.1.2.3", "cidrs": []any{"192.168.0.0/16", "172.16.0.0/12", "10.0.0.0/8"}},
+			maxCost: 4,
+			err:     fmt.Errorf("operation cancelled: actual cost limit exceeded"),
+		},
+		{
+			name:       "semver_compare",
+			expression: `semverCompare(version, "1.10.0") >= 0`,
+			parameters: map[string]*openfgav1.ConditionParamTypeRef{
+				"version": {TypeName: openfgav1.ConditionParamTypeRef_TYPE_NAME_STRING},
+			},
+			context: map[string]any{"version": "v1.10.2"},
+			result:  true,
+		},
+		{
+			name:       "semver_compare_invalid",
+			expression: `semverCompare(version, "1.10.0") >= 0`,
+			parameters: map[string]*openfgav1.ConditionParamTypeRef{
+				"version": {TypeName: openfgav1.ConditionParamTypeRef_TYPE_NAME_STRING},
+			},
+			context: map[string]any{"version": "latest"},
+			err:     fmt.Errorf("'latest' is not a valid semantic version"),
+		},
+		{
+			name:       "regex_match",
+			expression: `regexMatch(email, "^[a-z]+@example\\.com$")`,
+			parameters: map[string]*openfgav1.ConditionParamTypeRef{
+				"email": {TypeName: openfgav1.ConditionParamTypeRef_TYPE_NAME_STRING},
+			},
+			context: map[string]any{"email": "anne@example.com"},
+			result:  true,
+		},
+		{
+			name:       "regex_match_input_too_long",
+			expression: `regexMatch(email, "^[a-z]+@example\\.com$")`,
+			parameters: map[string]*openfgav1.ConditionParamTypeRef{
+				"email": {TypeName: openfgav1.ConditionParamTypeRef_TYPE_NAME_STRING},
+			},
+			context: map[string]any{"email": strings.Repeat("a", types.MaxRegexInputLength+1)},
+			maxCost: 1_000_000,
+			err:     fmt.Errorf("regexMatch input is longer than %d bytes", types.MaxRegexInputLength),
+		},
+		{
+			name:       "regex_match_cost_exceeded",
+			expression: `regexMatch(email, "^[a-z]+@example\\.com$")`,
+			parameters: map[string]*openfgav1.ConditionParamTypeRef{
+				"email": {TypeName: openfgav1.ConditionParamTypeRef_TYPE_NAME_STRING},
+			},
+			context: map[string]any{"email": strings.Repeat("a", 1000) + "@example.com"},
+			err:     fmt.Errorf("operation cancelled: actual cost limit exceeded"),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			maxCost := test.maxCost
+			if maxCost == 0 {
+				maxCost = 100
+			}
+
+			cond := &openfgav1.Condition{
+				Name:       "condition1",
+				Expression: test.expression,
+				Parameters: test.parameters,
+			}
+
+			evaluable := condition.NewUncompiled(cond).
+				WithTrackEvaluationCost().
+				WithMaxEvaluationCost(maxCost).
+				WithExtensions()
+			require.NoError(t, evaluable.Compile())
+
+			contextStruct, err := structpb.NewStruct(test.context)
+			require.NoError(t, err)
+
+			result, err := evaluable.Evaluate(context.Background(), contextStruct.GetFields())
+			if test.err != nil {
+				require.ErrorContains(t, err, test.err.Error())
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, test.result, result.ConditionMet)
+		})
+	}
+
+	t.Run("not_enabled", func(t *testing.T) {
+		_, err := condition.NewCompiled(&openfgav1.Condition{
+			Name:       "condition1",
+			Expression: `inBusinessHours(ts, "Europe/Oslo")`,
+			Parameters: map[string]*openfgav1.ConditionParamTypeRef{
+				"ts": {TypeName: openfgav1.ConditionParamTypeRef_TYPE_NAME_TIMESTAMP},
+			},
+		})
+		require.ErrorContains(t, err, "undeclared reference to 'inBusinessHours'")
+	})
+}
@@ -0,0 +1,195 @@
+// Package contextprovider supplies condition context values that the server trusts, such as the time
+// it received the request, instead of values sent by the caller.
+package contextprovider
+
+import (
+	"context"
+	"maps"
+	"net"
+	"net/netip"
+	"slices"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/openfga/openfga/pkg/authclaims"
+)
+
+const (
+	// ClaimSubject is the subject of the caller, see [authclaims.AuthClaims].
+	ClaimSubject = "subject"
+	// ClaimClientID is the client ID of the caller, see [authclaims.AuthClaims].
+	ClaimClientID = "client_id"
+	// ClaimScopes is the list of scopes granted to the caller, see [authclaims.AuthClaims].
+	ClaimScopes = "scopes"
+
+	// forwardedForHeader is the metadata set by the HTTP gateway with the address of the HTTP client.
+	forwardedForHeader = "x-forwarded-for"
+)
+
+// Provider supplies trusted condition context fields for a request.
+type Provider interface {
+	// Fields returns the fields for the request in ctx. A field is omitted when the request does not
+	// carry its value.
+	Fields(ctx context.Context) map[string]*structpb.Value
+}
+
+// ProviderFunc adapts a function to a Provider.
+type ProviderFunc func(ctx context.Context) map[string]*structpb.Value
+
+// Fields see [Provider.Fields].
+func (f ProviderFunc) Fields(ctx context.Context) map[string]*structpb.Value {
+	return f(ctx)
+}
+
+// RequestTime returns a Provider that sets key to the time the server handles the request, as an
+// RFC 3339 string that can be used as a `timestamp` parameter. The time is truncated to a multiple of
+// granularity, if positive, so that the requests made within the same interval have the same context and
+// share the check cache entries.
+func RequestTime(key string, granularity time.Duration) Provider {
+	return ProviderFunc(func(context.Context) map[string]*structpb.Value {
+		now := time.Now().UTC()
+		if granularity > 0 {
+			now = now.Truncate(granularity)
+		}
+		return map[string]*structpb.Value{
+			key: structpb.NewStringValue(now.Format(time.RFC3339Nano)),
+		}
+	})
+}
+
+// PeerIP returns a Provider that sets key to the IP address of the client, as a string that can be
+// used as an `ipaddress` parameter.
+//
+// Requests that reach the server through its own HTTP gateway come from a connection opened by the
+// server's host. For those, the last address of the x-forwarded-for metadata is used instead, which is
+// the address of the HTTP client as seen by the gateway. Addresses added by other proxies are ignored
+// because the client can forge them.
+func PeerIP(key string) Provider {
+	return ProviderFunc(func(ctx context.Context) map[string]*structpb.Value {
+		ip, ok := peerIP(ctx)
+		if !ok {
+			return nil
+		}
+		return map[string]*structpb.Value{
+			key: structpb.NewStringValue(ip.String()),
+		}
+	})
+}
+
+func peerIP(ctx context.Context) (netip.Addr, bool) {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return netip.Addr{}, false
+	}
+	ip, ok := addrIP(p.Addr)
+	if !ok {
+		return netip.Addr{}, false
+	}
+
+	localIP, _ := addrIP(p.LocalAddr)
+	if !ip.IsLoopback() && ip != localIP {
+		return ip, true
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	forwardedFor := md.Get(forwardedForHeader)
+	if len(forwardedFor) == 0 {
+		return ip, true
+	}
+	addrs := strings.Split(forwardedFor[len(forwardedFor)-1], ",")
+	forwarded, err := netip.ParseAddr(strings.TrimSpace(addrs[len(addrs)-1]))
+	if err != nil {
+		return ip, true
+	}
+	return forwarded.Unmap(), true
+}
+
+func addrIP(addr net.Addr) (netip.Addr, bool) {
+	if addr == nil {
+		return netip.Addr{}, false
+	}
+	if tcpAddr, ok := addr.(*net.TCPAddr); ok {
+		ip, ok := netip.AddrFromSlice(tcpAddr.IP)
+		return ip.Unmap(), ok
+	}
+	addrPort, err := netip.ParseAddrPort(addr.String())
+	if err != nil {
+		return netip.Addr{}, false
+	}
+	return addrPort.Addr().Unmap(), true
+}
+
+// AuthClaims returns a Provider that sets the fields of keys to the claims of the authenticated caller.
+// keys maps ClaimSubject, ClaimClientID or ClaimScopes to a context key. Scopes are set as a list of strings.
+func AuthClaims(keys map[string]string) Provider {
+	return ProviderFunc(func(ctx context.Context) map[string]*structpb.Value {
+		claims, ok := authclaims.AuthClaimsFromContext(ctx)
+		if !ok || claims == nil {
+			return nil
+		}
+
+		fields := make(map[string]*structpb.Value, len(keys))
+		for claim, key := range keys {
+			switch claim {
+			case ClaimSubject:
+				if claims.Subject != "" {
+					fields[key] = structpb.NewStringValue(claims.Subject)
+				}
+			case ClaimClientID:
+				if claims.ClientID != "" {
+					fields[key] = structpb.NewStringValue(claims.ClientID)
+				}
+			case ClaimScopes:
+				scopes := make([]*structpb.Value, 0, len(claims.Scopes))
+				for _, scope := range slices.Sorted(maps.Keys(claims.Scopes)) {
+					if claims.Scopes[scope] {
+						scopes = append(scopes, structpb.NewStringValue(scope))
+					}
+				}
+				fields[key] = structpb.NewListValue(&structpb.ListValue{Values: scopes})
+			}
+		}
+		return fields
+	})
+}
+
+// Merge returns the caller context with the fields of the providers added. Provider fields override the
+// caller fields with the same key. callerContext is not modified, and is returned as is if there are no
+// providers.
+func Merge(ctx context.Context, providers []Provider, callerContext *structpb.Struct) *structpb.Struct {
+	return MergeFields(Fields(ctx, providers), callerContext)
+}
+
+// Fields returns the fields of the providers for the request in ctx, or nil if there are no providers.
+// Requests with several items, such as BatchCheck, get them once and merge them into the context of each
+// item with MergeFields, so that the items share the same values.
+func Fields(ctx context.Context, providers []Provider) map[string]*structpb.Value {
+	if len(providers) == 0 {
+		return nil
+	}
+
+	fields := map[string]*structpb.Value{}
+	for _, provider := range providers {
+		maps.Copy(fields, provider.Fields(ctx))
+	}
+	return fields
+}
+
+// MergeFields returns the caller context with the provider fields added, see Merge. callerContext is
+// returned as is if providerFields is nil.
+func MergeFields(providerFields map[string]*structpb.Value, callerContext *structpb.Struct) *structpb.Struct {
+	if providerFields == nil {
+		return callerContext
+	}
+
+	fields := maps.Clone(callerContext.GetFields())
+	if fields == nil {
+		fields = map[string]*structpb.Value{}
+	}
+	maps.Copy(fields, providerFields)
+	return &structpb.Struct{Fields: fields}
+}
@@ -0,0 +1,179 @@
+package contextprovider
+
+import (
+	"context"
+	"net"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/openfga/openfga/pkg/authclaims"
+)
+
+func peerContext(remote, local string) context.Context {
+	p := &peer.Peer{Addr: net.TCPAddrFromAddrPort(netipAddrPort(remote))}
+	if local != "" {
+		p.LocalAddr = net.TCPAddrFromAddrPort(netipAddrPort(local))
+	}
+	return peer.NewContext(context.Background(), p)
+}
+
+func netipAddrPort(addr string) netip.AddrPort {
+	tcpAddr, err := net.ResolveTCPAddr("tcp", addr)
+	if err != nil {
+		panic(err)
+	}
+	return tcpAddr.AddrPort()
+}
+
+func TestRequestTime(t *testing.T) {
+	t.Run("without_granularity", func(t *testing.T) {
+		before := time.Now()
+		fields := RequestTime("current_time", 0).Fields(context.Background())
+
+		requestTime, err := time.Parse(time.RFC3339Nano, fields["current_time"].GetStringValue())
+		require.NoError(t, err)
+		require.False(t, requestTime.Before(before.Truncate(time.Nanosecond)))
+		require.False(t, requestTime.After(time.Now()))
+	})
+
+	t.Run("truncated_to_granularity", func(t *testing.T) {
+		before := time.Now().Truncate(time.Minute)
+		fields := RequestTime("current_time", time.Minute).Fields(context.Background())
+
+		requestTime, err := time.Parse(time.RFC3339Nano, fields["current_time"].GetStringValue())
+		require.NoError(t, err)
+		require.Equal(t, requestTime, requestTime.Truncate(time.Minute))
+		require.False(t, requestTime.Before(before))
+		require.False(t, requestTime.After(time.Now()))
+	})
+}
+
+func TestPeerIP(t *testing.T) {
+	tests := map[string]struct {
+		ctx      context.Context
+		expected string
+	}{
+		"no_peer": {
+			ctx: context.Background(),
+		},
+		"remote_peer": {
+			ctx:      peerContext("203.0.113.7:5000", "10.0.0.1:8081"),
+			expected: "203.0.113.7",
+		},
+		"remote_peer_ignores_forwarded_for": {
+			ctx: metadata.NewIncomingContext(peerContext("203.0.113.7:5000", "10.0.0.1:8081"),
+				metadata.Pairs("x-forwarded-for", "198.51.100.1")),
+			expected: "203.0.113.7",
+		},
+		"gateway_over_loopback": {
+			ctx: metadata.NewIncomingContext(peerContext("127.0.0.1:5000", "127.0.0.1:8081"),
+				metadata.Pairs("x-forwarded-for", "192.0.2.1, 198.51.100.1")),
+			expected: "198.51.100.1",
+		},
+		"gateway_over_local_address": {
+			ctx: metadata.NewIncomingContext(peerContext("10.0.0.1:5000", "10.0.0.1:8081"),
+				metadata.Pairs("x-forwarded-for", "198.51.100.1")),
+			expected: "198.51.100.1",
+		},
+		"local_peer_without_forwarded_for": {
+			ctx:      peerContext("[::1]:5000", "[::1]:8081"),
+			expected: "::1",
+		},
+		"local_peer_with_invalid_forwarded_for": {
+			ctx: metadata.NewIncomingContext(peerContext("127.0.0.1:5000", "127.0.0.1:8081"),
+				metadata.Pairs("x-forwarded-for", "unknown")),
+			expected: "127.0.0.1",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			fields := PeerIP("user_ip").Fields(test.ctx)
+			if test.expected == "" {
+				require.Empty(t, fields)
+				return
+			}
+			require.Equal(t, test.expected, fields["user_ip"].GetStringValue())
+		})
+	}
+}
+
+func TestAuthClaims(t *testing.T) {
+	provider := AuthClaims(map[string]string{
+		ClaimSubject:  "user_id",
+		ClaimClientID: "client",
+		ClaimScopes:   "scopes",
+	})
+
+	require.Empty(t, provider.Fields(context.Background()))
+
+	ctx := authclaims.ContextWithAuthClaims(context.Background(), &authclaims.AuthClaims{
+		Subject:  "anne",
+		ClientID: "app",
+		Scopes:   map[string]bool{"write": true, "read": true, "admin": false},
+	})
+	fields := provider.Fields(ctx)
+	require.Equal(t, "anne", fields["user_id"].GetStringValue())
+	require.Equal(t, "app", fields["client"].GetStringValue())
+	require.Equal(t, []any{"read", "write"}, fields["scopes"].GetListValue().AsSlice())
+
+	// Missing claims are not set.
+	ctx = authclaims.ContextWithAuthClaims(context.Background(), &authclaims.AuthClaims{Subject: "anne"})
+	fields = provider.Fields(ctx)
+	require.NotContains(t, fields, "client")
+	require.Empty(t, fields["scopes"].GetListValue().GetValues())
+}
+
+func TestMerge(t *testing.T) {
+	callerContext, err := structpb.NewStruct(map[string]any{
+		"current_time": "2000-01-01T00:00:00Z",
+		"x":            1,
+	})
+	require.NoError(t, err)
+
+	t.Run("without_providers", func(t *testing.T) {
+		require.Same(t, callerContext, Merge(context.Background(), nil, callerContext))
+	})
+
+	t.Run("providers_override_caller_values", func(t *testing.T) {
+		provider := ProviderFunc(func(context.Context) map[string]*structpb.Value {
+			return map[string]*structpb.Value{"current_time": structpb.NewStringValue("2024-01-01T00:00:00Z")}
+		})
+
+		merged := Merge(context.Background(), []Provider{provider}, callerContext)
+		require.Equal(t, map[string]any{
+			"current_time": "2024-01-01T00:00:00Z",
+			"x":            float64(1),
+		}, merged.AsMap())
+		require.Equal(t, "2000-01-01T00:00:00Z", callerContext.GetFields()["current_time"].GetStringValue())
+	})
+
+	t.Run("nil_caller_context", func(t *testing.T) {
+		merged := Merge(context.Background(), []Provider{RequestTime("current_time", 0)}, nil)
+		require.Contains(t, merged.GetFields(), "current_time")
+	})
+}
+
+func TestMergeFields(t *testing.T) {
+	callerContext, err := structpb.NewStruct(map[string]any{"x": 1})
+	require.NoError(t, err)
+
+	t.Run("without_provider_fields", func(t *testing.T) {
+		require.Same(t, callerContext, MergeFields(nil, callerContext))
+	})
+
+	t.Run("provider_fields_shared_by_items", func(t *testing.T) {
+		fields := Fields(context.Background(), []Provider{RequestTime("current_time", 0)})
+
+		first := MergeFields(fields, callerContext)
+		second := MergeFields(fields, nil)
+		require.Equal(t, first.GetFields()["current_time"].GetStringValue(), second.GetFields()["current_time"].GetStringValue())
+		require.NotContains(t, callerContext.GetFields(), "current_time")
+	})
+}
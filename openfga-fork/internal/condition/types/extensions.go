@@ -0,0 +1,343 @@
+package types
+
+import (
+	"math"
+	"net/netip"
+	"regexp"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+	_ "time/tzdata" // time zones must resolve the same way on hosts without a zoneinfo database
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+	"github.com/google/cel-go/common/types/traits"
+	"github.com/google/cel-go/interpreter"
+	"golang.org/x/mod/semver"
+)
+
+const (
+	// DefaultBusinessHoursStart is the hour of the day, in the time zone of the request, at which business hours start.
+	DefaultBusinessHoursStart = 9
+	// DefaultBusinessHoursEnd is the hour of the day, in the time zone of the request, at which business hours end.
+	DefaultBusinessHoursEnd = 17
+
+	// MaxRegexPatternLength is the maximum length in bytes of a regexMatch pattern.
+	MaxRegexPatternLength = 256
+	// MaxRegexInputLength is the maximum length in bytes of a string matched by regexMatch.
+	MaxRegexInputLength = 4096
+)
+
+const (
+	inBusinessHoursOverload      = "timestamp_string_in_business_hours"
+	inBusinessHoursRangeOverload = "timestamp_string_int_int_in_business_hours"
+	dayOfWeekOverload            = "timestamp_string_day_of_week"
+	inAnyCIDROverload            = "ipaddr_in_any_cidr"
+	semverCompareOverload        = "string_string_semver_compare"
+	regexMatchOverload           = "string_string_regex_match"
+)
+
+var extensionsLibraryDecls = map[string][]cel.FunctionOpt{
+	"inBusinessHours": {
+		cel.Overload(inBusinessHoursOverload,
+			[]*cel.Type{cel.TimestampType, cel.StringType}, cel.BoolType,
+			cel.BinaryBinding(func(ts, tz ref.Val) ref.Val {
+				return inBusinessHours(ts, tz, types.Int(DefaultBusinessHoursStart), types.Int(DefaultBusinessHoursEnd))
+			})),
+		cel.Overload(inBusinessHoursRangeOverload,
+			[]*cel.Type{cel.TimestampType, cel.StringType, cel.IntType, cel.IntType}, cel.BoolType,
+			cel.FunctionBinding(func(args ...ref.Val) ref.Val {
+				return inBusinessHours(args[0], args[1], args[2], args[3])
+			})),
+	},
+	"dayOfWeek": {
+		cel.Overload(dayOfWeekOverload,
+			[]*cel.Type{cel.TimestampType, cel.StringType}, cel.StringType,
+			cel.BinaryBinding(dayOfWeek)),
+	},
+	"inAnyCIDR": {
+		cel.MemberOverload(inAnyCIDROverload,
+			[]*cel.Type{ipaddrCelType, cel.ListType(cel.StringType)}, cel.BoolType,
+			cel.BinaryBinding(ipaddressInAnyCIDR)),
+	},
+	"semverCompare": {
+		cel.Overload(semverCompareOverload,
+			[]*cel.Type{cel.StringType, cel.StringType}, cel.IntType,
+			cel.BinaryBinding(semverCompare)),
+	},
+	"regexMatch": {
+		cel.Overload(regexMatchOverload,
+			[]*cel.Type{cel.StringType, cel.StringType}, cel.BoolType,
+			cel.BinaryBinding(regexMatch)),
+	},
+}
+
+// extensionsCostTrackers compute the runtime cost of the extension functions so that they count towards
+// the maximum condition evaluation cost like the built-in functions.
+var extensionsCostTrackers = map[string]interpreter.FunctionTracker{
+	inBusinessHoursOverload:      constantCost(1),
+	inBusinessHoursRangeOverload: constantCost(1),
+	dayOfWeekOverload:            constantCost(1),
+	inAnyCIDROverload: func(args []ref.Val, _ ref.Val) *uint64 {
+		cost := uint64(1)
+		if cidrs, ok := args[1].(traits.Sizer); ok {
+			cost += uint64(cidrs.Size().(types.Int))
+		}
+		return &cost
+	},
+	semverCompareOverload: func(args []ref.Val, _ ref.Val) *uint64 {
+		cost := 1 + traversalCost(args[0], 0.1) + traversalCost(args[1], 0.1)
+		return &cost
+	},
+	regexMatchOverload: func(args []ref.Val, _ ref.Val) *uint64 {
+		// Same as the built-in matches function: the size of the input times the size of the pattern.
+		cost := max(1, traversalCost(args[0], 0.1)*traversalCost(args[1], 0.25))
+		return &cost
+	},
+}
+
+// Extensions is the CEL library of the condition extension functions:
+//
+//   - inBusinessHours(timestamp, string[, int, int]) reports whether the timestamp is on a weekday between the
+//     start hour (included) and end hour (excluded) in the time zone, 9 to 17 by default.
+//   - dayOfWeek(timestamp, string) returns the English name of the day of the timestamp in the time zone.
+//   - ipaddress.inAnyCIDR(list<string>) reports whether the address is in any of the CIDRs.
+//   - semverCompare(string, string) returns -1, 0 or +1 as the first semantic version is lower than, equal to
+//     or greater than the second one. The "v" prefix is optional.
+//   - regexMatch(string, string) reports whether the string matches the RE2 pattern. The pattern and the
+//     string are limited to MaxRegexPatternLength and MaxRegexInputLength bytes.
+type Extensions struct{}
+
+var extensionsLib = &Extensions{}
+
+// ExtensionsEnvOption returns the CEL environment option that adds the Extensions library.
+func ExtensionsEnvOption() cel.EnvOption {
+	return cel.Lib(extensionsLib)
+}
+
+// CompileOptions implements cel.Library.
+func (Extensions) CompileOptions() []cel.EnvOption {
+	options := []cel.EnvOption{}
+	for name, overloads := range extensionsLibraryDecls {
+		options = append(options, cel.Function(name, overloads...))
+	}
+	return options
+}
+
+// ProgramOptions implements cel.Library.
+func (Extensions) ProgramOptions() []cel.ProgramOption {
+	trackers := make([]interpreter.CostTrackerOption, 0, len(extensionsCostTrackers))
+	for overload, tracker := range extensionsCostTrackers {
+		trackers = append(trackers, interpreter.OverloadCostTracker(overload, tracker))
+	}
+	return []cel.ProgramOption{cel.CostTrackerOptions(trackers...)}
+}
+
+func constantCost(cost uint64) interpreter.FunctionTracker {
+	return func([]ref.Val, ref.Val) *uint64 {
+		return &cost
+	}
+}
+
+func traversalCost(val ref.Val, factor float64) uint64 {
+	str, ok := val.(types.String)
+	if !ok {
+		return 0
+	}
+	return uint64(math.Ceil(float64(len(str)) * factor))
+}
+
+var locations sync.Map
+
+// loadLocation returns the time zone of the IANA name, caching the time zones that were found.
+func loadLocation(name string) (*time.Location, error) {
+	if loc, ok := locations.Load(name); ok {
+		return loc.(*time.Location), nil
+	}
+
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, err
+	}
+
+	locations.Store(name, loc)
+	return loc, nil
+}
+
+// maxCompiledRegexps bounds the number of regexMatch patterns kept compiled, since the patterns can come from
+// the context of the requests rather than from the model.
+const maxCompiledRegexps = 1000
+
+var (
+	compiledRegexps     sync.Map
+	compiledRegexpCount atomic.Int64
+)
+
+// compileRegexp returns the compiled pattern, caching the first maxCompiledRegexps valid patterns.
+func compileRegexp(pattern string) (*regexp.Regexp, error) {
+	if re, ok := compiledRegexps.Load(pattern); ok {
+		return re.(*regexp.Regexp), nil
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	if compiledRegexpCount.Load() < maxCompiledRegexps {
+		if _, loaded := compiledRegexps.LoadOrStore(pattern, re); !loaded {
+			compiledRegexpCount.Add(1)
+		}
+	}
+	return re, nil
+}
+
+func localTime(ts, tz ref.Val) (time.Time, ref.Val) {
+	t, ok := ts.(types.Timestamp)
+	if !ok {
+		return time.Time{}, types.MaybeNoSuchOverloadErr(ts)
+	}
+
+	name, ok := tz.(types.String)
+	if !ok {
+		return time.Time{}, types.MaybeNoSuchOverloadErr(tz)
+	}
+
+	loc, err := loadLocation(string(name))
+	if err != nil {
+		return time.Time{}, types.NewErr("'%s' is not a valid time zone", name)
+	}
+
+	return t.In(loc), nil
+}
+
+func inBusinessHours(ts, tz, start, end ref.Val) ref.Val {
+	startHour, ok := start.(types.Int)
+	if !ok {
+		return types.MaybeNoSuchOverloadErr(start)
+	}
+
+	endHour, ok := end.(types.Int)
+	if !ok {
+		return types.MaybeNoSuchOverloadErr(end)
+	}
+
+	if startHour < 0 || endHour > 24 || startHour >= endHour {
+		return types.NewErr("business hours must be a range of hours between 0 and 24, found [%d, %d)", startHour, endHour)
+	}
+
+	local, errVal := localTime(ts, tz)
+	if errVal != nil {
+		return errVal
+	}
+
+	if weekday := local.Weekday(); weekday == time.Saturday || weekday == time.Sunday {
+		return types.False
+	}
+
+	hour := types.Int(local.Hour())
+	return types.Bool(hour >= startHour && hour < endHour)
+}
+
+func dayOfWeek(ts, tz ref.Val) ref.Val {
+	local, errVal := localTime(ts, tz)
+	if errVal != nil {
+		return errVal
+	}
+
+	return types.String(local.Weekday().String())
+}
+
+// ipaddressInAnyCIDR implements a cel.BinaryBinding that is used as a receiver overload for checking
+// whether an ipaddress value is within any of a list of network CIDRs defined as strings.
+func ipaddressInAnyCIDR(lhs, rhs ref.Val) ref.Val {
+	ipaddr, ok := lhs.(IPAddress)
+	if !ok {
+		return types.NewErr("an IPAddress parameter value is required for comparison")
+	}
+
+	cidrs, ok := rhs.(traits.Lister)
+	if !ok {
+		return types.NewErr("a list of CIDR strings is required for comparison")
+	}
+
+	found := false
+	for it := cidrs.Iterator(); it.HasNext() == types.True; {
+		item := it.Next()
+		cidr, ok := item.(types.String)
+		if !ok {
+			return types.NewErr("a list of CIDR strings is required for comparison")
+		}
+
+		network, err := netip.ParsePrefix(string(cidr))
+		if err != nil {
+			return types.NewErr("'%s' is a malformed CIDR string", cidr)
+		}
+
+		// Every CIDR is parsed, even after a match, so that a malformed list is always reported.
+		found = found || network.Contains(ipaddr.addr)
+	}
+
+	return types.Bool(found)
+}
+
+func parseSemver(val ref.Val) (string, ref.Val) {
+	str, ok := val.(types.String)
+	if !ok {
+		return "", types.MaybeNoSuchOverloadErr(val)
+	}
+
+	version := string(str)
+	if !strings.HasPrefix(version, "v") {
+		version = "v" + version
+	}
+
+	if !semver.IsValid(version) {
+		return "", types.NewErr("'%s' is not a valid semantic version", str)
+	}
+
+	return version, nil
+}
+
+func semverCompare(lhs, rhs ref.Val) ref.Val {
+	v1, errVal := parseSemver(lhs)
+	if errVal != nil {
+		return errVal
+	}
+
+	v2, errVal := parseSemver(rhs)
+	if errVal != nil {
+		return errVal
+	}
+
+	return types.Int(semver.Compare(v1, v2))
+}
+
+func regexMatch(lhs, rhs ref.Val) ref.Val {
+	str, ok := lhs.(types.String)
+	if !ok {
+		return types.MaybeNoSuchOverloadErr(lhs)
+	}
+
+	pattern, ok := rhs.(types.String)
+	if !ok {
+		return types.MaybeNoSuchOverloadErr(rhs)
+	}
+
+	if len(pattern) > MaxRegexPatternLength {
+		return types.NewErr("regexMatch pattern is longer than %d bytes", MaxRegexPatternLength)
+	}
+
+	if len(str) > MaxRegexInputLength {
+		return types.NewErr("regexMatch input is longer than %d bytes", MaxRegexInputLength)
+	}
+
+	re, err := compileRegexp(string(pattern))
+	if err != nil {
+		return types.NewErr("'%s' is not a valid regular expression: %v", pattern, err)
+	}
+
+	return types.Bool(re.MatchString(string(str)))
+}
@@ -0,0 +1,120 @@
+package types
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInBusinessHours(t *testing.T) {
+	// Wednesday 12:00 UTC.
+	ts := types.Timestamp{Time: time.Date(2024, time.June, 5, 12, 0, 0, 0, time.UTC)}
+
+	tests := []struct {
+		name   string
+		tz     ref.Val
+		start  ref.Val
+		end    ref.Val
+		result ref.Val
+	}{
+		{
+			name:   "utc",
+			tz:     types.String("UTC"),
+			start:  types.Int(9),
+			end:    types.Int(17),
+			result: types.True,
+		},
+		{
+			name:   "end_hour_excluded",
+			tz:     types.String("Asia/Kolkata"), // 17:30
+			start:  types.Int(9),
+			end:    types.Int(17),
+			result: types.False,
+		},
+		{
+			name:   "invalid_range",
+			tz:     types.String("UTC"),
+			start:  types.Int(17),
+			end:    types.Int(9),
+			result: types.NewErr("business hours must be a range of hours between 0 and 24, found [17, 9)"),
+		},
+		{
+			name:   "invalid_time_zone",
+			tz:     types.String("Nowhere"),
+			start:  types.Int(9),
+			end:    types.Int(17),
+			result: types.NewErr("'Nowhere' is not a valid time zone"),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.Equal(t, test.result, inBusinessHours(ts, test.tz, test.start, test.end))
+		})
+	}
+}
+
+func TestIPAddressInAnyCIDR(t *testing.T) {
+	addr, err := ParseIPAddress("192.168.1.1")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		cidrs  []string
+		result ref.Val
+	}{
+		{
+			name:   "ip_in_cidr",
+			cidrs:  []string{"10.0.0.0/8", "192.168.1.0/24"},
+			result: types.True,
+		},
+		{
+			name:   "ip_not_in_cidr",
+			cidrs:  []string{"10.0.0.0/8"},
+			result: types.False,
+		},
+		{
+			name:   "empty_list",
+			cidrs:  []string{},
+			result: types.False,
+		},
+		{
+			name:   "malformed_cidr_after_match",
+			cidrs:  []string{"192.168.1.0/24", "malformed"},
+			result: types.NewErr("'malformed' is a malformed CIDR string"),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cidrs := types.DefaultTypeAdapter.NativeToValue(test.cidrs)
+			require.Equal(t, test.result, ipaddressInAnyCIDR(addr, cidrs))
+		})
+	}
+}
+
+func TestSemverCompare(t *testing.T) {
+	require.Equal(t, types.Int(-1), semverCompare(types.String("1.9.0"), types.String("v1.10.0")))
+	require.Equal(t, types.Int(0), semverCompare(types.String("v2.0.0"), types.String("2.0.0")))
+	require.Equal(t, types.Int(1), semverCompare(types.String("1.0.0"), types.String("1.0.0-rc.1")))
+	require.Equal(t, types.NewErr("'1.x' is not a valid semantic version"), semverCompare(types.String("1.x"), types.String("1.0.0")))
+}
+
+func TestRegexMatchCachesPatterns(t *testing.T) {
+	require.Equal(t, types.True, regexMatch(types.String("eng-platform"), types.String("^eng-[a-z]+$")))
+	require.Equal(t, types.False, regexMatch(types.String("sales"), types.String("^eng-[a-z]+$")))
+
+	re, err := compileRegexp("^eng-[a-z]+$")
+	require.NoError(t, err)
+	cached, err := compileRegexp("^eng-[a-z]+$")
+	require.NoError(t, err)
+	require.Same(t, re, cached)
+
+	_, err = compileRegexp("(")
+	require.Error(t, err)
+	_, ok := compiledRegexps.Load("(")
+	require.False(t, ok)
+}
@@ -2,6 +2,7 @@ package graph
 
 import (
 	"context"
+	"encoding/json"
 	"strconv"
 	"time"
 
@@ -27,26 +28,30 @@ const (
 )
 
 var (
-	checkCacheTotalCounter = promauto.NewCounter(prometheus.CounterOpts{
+	checkCacheTotalCounter = telemetry.StoreLabeled(promauto.NewCounterVec(prometheus.CounterOpts{
 		Namespace: build.ProjectName,
 		Name:      "check_cache_total_count",
 		Help:      "The total number of calls to ResolveCheck.",
-	})
+	}, []string{telemetry.StoreIDLabel}))
 
-	checkCacheHitCounter = promauto.NewCounter(prometheus.CounterOpts{
+	checkCacheHitCounter = telemetry.StoreLabeled(promauto.NewCounterVec(prometheus.CounterOpts{
 		Namespace: build.ProjectName,
 		Name:      "check_cache_hit_count",
 		Help:      "The total number of cache hits for ResolveCheck.",
-	})
+	}, []string{telemetry.StoreIDLabel}))
 
-	checkCacheInvalidHit = promauto.NewCounter(prometheus.CounterOpts{
+	checkCacheInvalidHit = telemetry.StoreLabeled(promauto.NewCounterVec(prometheus.CounterOpts{
 		Namespace: build.ProjectName,
 		Name:      "check_cache_invalid_hit_count",
 		Help:      "The total number of cache hits for ResolveCheck that were discarded because they were invalidated.",
-	})
+	}, []string{telemetry.StoreIDLabel}))
 )
 
-var _ storage.CacheItem = (*CheckResponseCacheEntry)(nil)
+var _ storage.SerializableCacheItem = (*CheckResponseCacheEntry)(nil)
+
+func init() {
+	storage.RegisterCacheItem(func() storage.SerializableCacheItem { return &CheckResponseCacheEntry{} })
+}
 
 type CheckResponseCacheEntry struct {
 	LastModified  time.Time
@@ -57,6 +62,14 @@ func (c *CheckResponseCacheEntry) CacheEntityType() string {
 	return "check_response"
 }
 
+func (c *CheckResponseCacheEntry) MarshalBinary() ([]byte, error) {
+	return json.Marshal(c)
+}
+
+func (c *CheckResponseCacheEntry) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, c)
+}
+
 // CachedCheckResolver attempts to resolve check sub-problems via prior computations before
 // delegating the request to some underlying CheckResolver.
 type CachedCheckResolver struct {
@@ -159,10 +172,11 @@ func (c *CachedCheckResolver) ResolveCheck(
 	tryCache := req.Consistency != openfgav1.ConsistencyPreference_HIGHER_CONSISTENCY
 
 	if tryCache {
-		checkCacheTotalCounter.Inc()
+		storeLabel := telemetry.StoreLabelFromContext(ctx, req.GetStoreID())
+		checkCacheTotalCounter.WithLabelValues(storeLabel).Inc()
 		if cachedResp := c.cache.Get(cacheKey); cachedResp != nil {
 			res := cachedResp.(*CheckResponseCacheEntry)
-			isValid := res.LastModified.After(req.LastCacheInvalidationTime)
+			isValid := res.LastModified.After(req.LastCacheInvalidationTime) && !c.invalidated(req.GetStoreID(), res.LastModified)
 			c.logger.Debug("CachedCheckResolver found cache key",
 				zap.String("store_id", req.GetStoreID()),
 				zap.String("authorization_model_id", req.GetAuthorizationModelID()),
@@ -171,13 +185,13 @@ func (c *CachedCheckResolver) ResolveCheck(
 
 			span.SetAttributes(attribute.Bool("cached", isValid))
 			if isValid {
-				checkCacheHitCounter.Inc()
+				checkCacheHitCounter.WithLabelValues(storeLabel).Inc()
 				// return a copy to avoid races across goroutines
 				return res.CheckResponse.clone(), nil
 			}
 
 			// we tried the cache and hit an invalid entry
-			checkCacheInvalidHit.Inc()
+			checkCacheInvalidHit.WithLabelValues(storeLabel).Inc()
 		} else {
 			c.logger.Debug("CachedCheckResolver not found cache key",
 				zap.String("store_id", req.GetStoreID()),
@@ -211,6 +225,13 @@ func (c *CachedCheckResolver) ResolveCheck(
 	return resp, nil
 }
 
+// invalidated reports whether the results of storeID cached at lastModified were invalidated afterward, e.g.
+// by a flush of the cache of the store.
+func (c *CachedCheckResolver) invalidated(storeID string, lastModified time.Time) bool {
+	entry, ok := c.cache.Get(storage.GetInvalidCheckCacheKey(storeID)).(*storage.InvalidEntityCacheEntry)
+	return ok && lastModified.Before(entry.LastModified)
+}
+
 func BuildCacheKey(req ResolveCheckRequest) string {
 	tup := tuple.From(req.GetTupleKey())
 	cacheKeyString := tup.String() + req.GetInvariantCacheKey()
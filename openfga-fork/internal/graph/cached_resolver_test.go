@@ -13,6 +13,7 @@ import (
 
 	openfgav1 "github.com/openfga/api/proto/openfga/v1"
 
+	"github.com/openfga/openfga/pkg/storage"
 	"github.com/openfga/openfga/pkg/tuple"
 )
 
@@ -612,6 +613,45 @@ func TestResolveCheckLastChangelogRecent(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestResolveCheckInvalidatedStore(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+
+	req := &ResolveCheckRequest{
+		StoreID:              "12",
+		AuthorizationModelID: "33",
+		TupleKey:             tuple.NewTupleKey("document:abc", "reader", "user:XYZ"),
+		RequestMetadata:      NewCheckRequestMetadata(),
+	}
+
+	result := &ResolveCheckResponse{Allowed: true}
+	initialMockResolver := NewMockCheckResolver(ctrl)
+	initialMockResolver.EXPECT().ResolveCheck(gomock.Any(), req).Times(2).Return(result, nil)
+
+	dut, err := NewCachedCheckResolver(WithCacheTTL(1 * time.Hour))
+	require.NoError(t, err)
+	defer dut.Close()
+
+	dut.SetDelegate(initialMockResolver)
+
+	_, err = dut.ResolveCheck(ctx, req)
+	require.NoError(t, err)
+
+	// results cached before the invalidation of their store are resolved again
+	time.Sleep(time.Millisecond)
+	dut.cache.Set(storage.GetInvalidCheckCacheKey("12"), &storage.InvalidEntityCacheEntry{LastModified: time.Now()}, time.Hour)
+
+	_, err = dut.ResolveCheck(ctx, req)
+	require.NoError(t, err)
+
+	// results cached after it are served from the cache
+	actualResult, err := dut.ResolveCheck(ctx, req)
+	require.NoError(t, err)
+	require.Equal(t, result.Allowed, actualResult.Allowed)
+}
+
 func TestCachedCheckResolver_FieldsInResponse(t *testing.T) {
 	t.Cleanup(func() {
 		goleak.VerifyNone(t)
@@ -662,3 +702,26 @@ func TestBuildCacheKey(t *testing.T) {
 	result := BuildCacheKey(*req)
 	require.NotEmpty(t, result)
 }
+
+func TestCheckResponseCacheEntrySerialization(t *testing.T) {
+	entry := &CheckResponseCacheEntry{
+		LastModified: time.Now().UTC(),
+		CheckResponse: &ResolveCheckResponse{
+			Allowed: true,
+			ResolutionMetadata: ResolveCheckResponseMetadata{
+				DatastoreQueryCount: 3,
+				CycleDetected:       true,
+				Duration:            5 * time.Millisecond,
+			},
+		},
+	}
+
+	data, err := storage.EncodeCacheItem(entry)
+	require.NoError(t, err)
+
+	decoded, err := storage.DecodeCacheItem(data)
+	require.NoError(t, err)
+	require.IsType(t, &CheckResponseCacheEntry{}, decoded)
+	require.True(t, entry.LastModified.Equal(decoded.(*CheckResponseCacheEntry).LastModified))
+	require.Equal(t, entry.CheckResponse, decoded.(*CheckResponseCacheEntry).CheckResponse)
+}
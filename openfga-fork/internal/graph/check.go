@@ -657,6 +657,7 @@ func (c *LocalChecker) checkDirectUsersetTuples(ctx context.Context, req *Resolv
 			}
 			defer iter.Stop()
 
+			req.resolutionPath().addResolver(defaultResolver)
 			return c.defaultUserset(ctx, req, directlyRelatedUsersetTypes, iter)(ctx)
 		}
 
@@ -684,6 +685,7 @@ func (c *LocalChecker) checkDirectUsersetTuples(ctx context.Context, req *Resolv
 			defer iter.Stop()
 
 			if !c.optimizationsEnabled {
+				req.resolutionPath().addResolver(recursiveResolver)
 				return c.recursiveUserset(ctx, req, directlyRelatedUsersetTypes, iter)(ctx)
 			}
 
@@ -693,6 +695,7 @@ func (c *LocalChecker) checkDirectUsersetTuples(ctx context.Context, req *Resolv
 			possibleStrategies[defaultResolver] = defaultRecursivePlan
 			possibleStrategies[recursiveResolver] = recursivePlan
 			plan := keyPlan.SelectStrategy(possibleStrategies)
+			req.resolutionPath().addPlan(key, plan.Type)
 
 			resolver := c.defaultUserset
 			if plan.Type == recursiveResolver {
@@ -726,6 +729,7 @@ func (c *LocalChecker) checkDirectUsersetTuples(ctx context.Context, req *Resolv
 				key := k.String()
 				keyPlan := c.planner.GetKeyPlan(key)
 				strategy := keyPlan.SelectStrategy(possibleStrategies)
+				req.resolutionPath().addPlan(key, strategy.Type)
 
 				resolver := c.defaultUserset
 				if strategy.Type == weightTwoResolver {
@@ -741,6 +745,7 @@ func (c *LocalChecker) checkDirectUsersetTuples(ctx context.Context, req *Resolv
 					return nil, err
 				}
 				defer iter.Stop()
+				req.resolutionPath().addResolver(defaultResolver)
 				resolvers = append(resolvers, c.defaultUserset(ctx, req, remainingUsersetTypes, iter))
 			}
 		} else {
@@ -757,6 +762,7 @@ func (c *LocalChecker) checkDirectUsersetTuples(ctx context.Context, req *Resolv
 				}
 				// NOTE: we collect defers given that the iterator won't be consumed until `union` resolves at the end.
 				defer iter.Stop()
+				req.resolutionPath().addResolver(weightTwoResolver)
 				resolvers = append(resolvers, c.weight2Userset(ctx, req, usersets, iter))
 			}
 			// for all usersets could not be resolved through weight2 resolver, resolve them all through the default resolver.
@@ -767,6 +773,7 @@ func (c *LocalChecker) checkDirectUsersetTuples(ctx context.Context, req *Resolv
 					return nil, err
 				}
 				defer iter.Stop()
+				req.resolutionPath().addResolver(defaultResolver)
 				resolvers = append(resolvers, c.defaultUserset(ctx, req, remainingUsersetTypes, iter))
 			}
 		}
@@ -893,7 +900,7 @@ func (c *LocalChecker) checkTTU(parentctx context.Context, req *ResolveCheckRequ
 		)
 		defer filteredIter.Stop()
 
-		resolver := c.defaultTTU
+		resolver, resolverName := c.defaultTTU, defaultResolver
 		possibleStrategies := map[string]*planner.KeyPlanStrategy{
 			defaultResolver: defaultPlan,
 		}
@@ -902,16 +909,17 @@ func (c *LocalChecker) checkTTU(parentctx context.Context, req *ResolveCheckRequ
 		if !isUserset {
 			if typesys.TTUUseWeight2Resolver(objectType, relation, userType, rewrite.GetTupleToUserset()) {
 				possibleStrategies[weightTwoResolver] = weight2Plan
-				resolver = c.weight2TTU
+				resolver, resolverName = c.weight2TTU, weightTwoResolver
 			} else if typesys.TTUUseRecursiveResolver(objectType, relation, userType, rewrite.GetTupleToUserset()) {
 				possibleStrategies[defaultResolver] = defaultRecursivePlan
 				possibleStrategies[recursiveResolver] = recursivePlan
-				resolver = c.recursiveTTU
+				resolver, resolverName = c.recursiveTTU, recursiveResolver
 			}
 		}
 
 		if len(possibleStrategies) == 1 || !c.optimizationsEnabled {
 			// short circuit, no additional resolvers are available or planner is not enabled yet
+			req.resolutionPath().addResolver(resolverName)
 			return resolver(ctx, req, rewrite, filteredIter)(ctx)
 		}
 
@@ -931,6 +939,7 @@ func (c *LocalChecker) checkTTU(parentctx context.Context, req *ResolveCheckRequ
 		planKey := b.String()
 		keyPlan := c.planner.GetKeyPlan(planKey)
 		strategy := keyPlan.SelectStrategy(possibleStrategies)
+		req.resolutionPath().addPlan(planKey, strategy.Type)
 
 		switch strategy.Type {
 		case defaultResolver:
@@ -1349,6 +1349,95 @@ func TestCheckDispatchCount(t *testing.T) {
 	})
 }
 
+func TestCheckResolutionPath(t *testing.T) {
+	ds := memory.New()
+	storeID := ulid.Make().String()
+
+	model := parser.MustTransformDSLToProto(`
+		model
+			schema 1.1
+
+		type user
+
+		type folder
+			relations
+				define viewer: [user] or viewer from parent
+				define parent: [folder]
+		`)
+
+	err := ds.Write(context.Background(), storeID, nil, []*openfgav1.TupleKey{
+		tuple.NewTupleKey("folder:B", "viewer", "user:jon"),
+		tuple.NewTupleKey("folder:A", "parent", "folder:B"),
+	})
+	require.NoError(t, err)
+
+	typesys, err := typesystem.NewAndValidate(context.Background(), model)
+	require.NoError(t, err)
+
+	ctx := setRequestContext(context.Background(), typesys, ds, nil)
+
+	t.Run("without_optimizations", func(t *testing.T) {
+		checker := NewLocalChecker()
+		t.Cleanup(checker.Close)
+
+		checkRequestMetadata := NewCheckRequestMetadata()
+		checkRequestMetadata.ResolutionPath = new(ResolutionPath)
+		resp, err := checker.ResolveCheck(ctx, &ResolveCheckRequest{
+			StoreID:              storeID,
+			AuthorizationModelID: model.GetId(),
+			TupleKey:             tuple.NewTupleKey("folder:A", "viewer", "user:jon"),
+			RequestMetadata:      checkRequestMetadata,
+		})
+		require.NoError(t, err)
+		require.True(t, resp.Allowed)
+
+		require.Equal(t, []string{recursiveResolver}, checkRequestMetadata.ResolutionPath.Resolvers())
+		require.Empty(t, checkRequestMetadata.ResolutionPath.Plans())
+	})
+
+	t.Run("with_optimizations", func(t *testing.T) {
+		checker := NewLocalChecker(WithOptimizations(true))
+		t.Cleanup(checker.Close)
+
+		checkRequestMetadata := NewCheckRequestMetadata()
+		checkRequestMetadata.ResolutionPath = new(ResolutionPath)
+		resp, err := checker.ResolveCheck(ctx, &ResolveCheckRequest{
+			StoreID:              storeID,
+			AuthorizationModelID: model.GetId(),
+			TupleKey:             tuple.NewTupleKey("folder:A", "viewer", "user:jon"),
+			RequestMetadata:      checkRequestMetadata,
+		})
+		require.NoError(t, err)
+		require.True(t, resp.Allowed)
+
+		plans := checkRequestMetadata.ResolutionPath.Plans()
+		require.Len(t, plans, 1)
+		for key, strategy := range plans {
+			require.Contains(t, key, "ttu|"+model.GetId())
+			require.Contains(t, []string{defaultResolver, recursiveResolver}, strategy)
+			require.Contains(t, checkRequestMetadata.ResolutionPath.Resolvers(), strategy)
+		}
+	})
+
+	t.Run("not_recorded_by_default", func(t *testing.T) {
+		checker := NewLocalChecker(WithOptimizations(true))
+		t.Cleanup(checker.Close)
+
+		req, err := NewResolveCheckRequest(ResolveCheckRequestParams{
+			StoreID:              storeID,
+			AuthorizationModelID: ulid.Make().String(),
+			TupleKey:             tuple.NewTupleKey("folder:A", "viewer", "user:jon"),
+		})
+		require.NoError(t, err)
+		require.Nil(t, req.GetRequestMetadata().ResolutionPath)
+
+		resp, err := checker.ResolveCheck(ctx, req)
+		require.NoError(t, err)
+		require.True(t, resp.Allowed)
+		require.Nil(t, req.GetRequestMetadata().ResolutionPath.Resolvers())
+	})
+}
+
 func TestUnionCheckFuncReducer(t *testing.T) {
 	t.Cleanup(func() {
 		goleak.VerifyNone(t)
@@ -0,0 +1,73 @@
+package graph
+
+import (
+	"sort"
+	"sync"
+
+	"golang.org/x/exp/maps"
+)
+
+// ResolutionPath records the resolvers ("default", "weight2" or "recursive") used to resolve the userset and
+// tuple to userset rewrites of a Check, and the strategy selected by the planner for each plan key. It is shared
+// by the subproblems of the request and safe for concurrent use. A nil ResolutionPath records nothing.
+type ResolutionPath struct {
+	mu        sync.Mutex
+	resolvers map[string]struct{}
+	plans     map[string]string
+}
+
+func (p *ResolutionPath) addResolver(resolver string) {
+	if p == nil {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.resolvers == nil {
+		p.resolvers = make(map[string]struct{})
+	}
+	p.resolvers[resolver] = struct{}{}
+}
+
+func (p *ResolutionPath) addPlan(key, strategy string) {
+	if p == nil {
+		return
+	}
+
+	p.addResolver(strategy)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.plans == nil {
+		p.plans = make(map[string]string)
+	}
+	p.plans[key] = strategy
+}
+
+// Resolvers returns the sorted resolvers used.
+func (p *ResolutionPath) Resolvers() []string {
+	if p == nil {
+		return nil
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	resolvers := maps.Keys(p.resolvers)
+	sort.Strings(resolvers)
+	return resolvers
+}
+
+// Plans returns the strategy selected by the planner for each plan key.
+func (p *ResolutionPath) Plans() map[string]string {
+	if p == nil {
+		return nil
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return maps.Clone(p.plans)
+}
@@ -46,6 +46,10 @@ type ResolveCheckRequestMetadata struct {
 
 	// WasThrottled indicates whether the request was throttled
 	WasThrottled *atomic.Bool
+
+	// ResolutionPath records the resolvers and planner strategies used to solve the root/parent problem. It is
+	// nil, and records nothing, unless ResolveCheckRequestParams.RecordResolutionPath is set.
+	ResolutionPath *ResolutionPath
 }
 
 type ResolveCheckRequestParams struct {
@@ -56,6 +60,9 @@ type ResolveCheckRequestParams struct {
 	Consistency               openfgav1.ConsistencyPreference
 	LastCacheInvalidationTime time.Time
 	AuthorizationModelID      string
+	// RecordResolutionPath allocates the ResolutionPath of the request metadata. It is only needed by the slow
+	// request log, and recording the path locks a mutex on every userset and tuple to userset resolution.
+	RecordResolutionPath bool
 }
 
 func NewCheckRequestMetadata() *ResolveCheckRequestMetadata {
@@ -102,6 +109,10 @@ func NewResolveCheckRequest(
 
 	r.invariantCacheKey = keyBuilder.String()
 
+	if params.RecordResolutionPath {
+		r.RequestMetadata.ResolutionPath = new(ResolutionPath)
+	}
+
 	return r, nil
 }
 
@@ -113,6 +124,7 @@ func (r *ResolveCheckRequest) clone() *ResolveCheckRequest {
 			DispatchCounter: origRequestMetadata.DispatchCounter,
 			Depth:           origRequestMetadata.Depth,
 			WasThrottled:    origRequestMetadata.WasThrottled,
+			ResolutionPath:  origRequestMetadata.ResolutionPath,
 		}
 	}
 
@@ -204,3 +216,10 @@ func (r *ResolveCheckRequest) GetInvariantCacheKey() string {
 	}
 	return r.invariantCacheKey
 }
+
+func (r *ResolveCheckRequest) resolutionPath() *ResolutionPath {
+	if r.GetRequestMetadata() == nil {
+		return nil
+	}
+	return r.GetRequestMetadata().ResolutionPath
+}
@@ -2,8 +2,11 @@ package authn
 
 import (
 	"context"
+	"net/http"
 
 	grpcauth "github.com/grpc-ecosystem/go-grpc-middleware/v2/interceptors/auth"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 
 	"github.com/openfga/openfga/internal/authn"
 	"github.com/openfga/openfga/pkg/authclaims"
@@ -19,3 +22,20 @@ func AuthFunc(authenticator authn.Authenticator) grpcauth.AuthFunc {
 		return authclaims.ContextWithAuthClaims(ctx, claims), nil
 	}
 }
+
+// HTTPHandler authenticates the requests of next with the credentials of their Authorization header.
+// Requests that fail to authenticate get a 401 response.
+func HTTPHandler(authenticator authn.Authenticator, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Authenticators read the credentials from the incoming gRPC metadata.
+		ctx := metadata.NewIncomingContext(r.Context(), metadata.Pairs("authorization", r.Header.Get("Authorization")))
+
+		ctx, err := AuthFunc(authenticator)(ctx)
+		if err != nil {
+			http.Error(w, status.Convert(err).Message(), http.StatusUnauthorized)
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
@@ -0,0 +1,40 @@
+package authn
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/openfga/openfga/internal/authn/presharedkey"
+)
+
+func TestHTTPHandler(t *testing.T) {
+	authenticator, err := presharedkey.NewPresharedKeyAuthenticator([]string{"key"})
+	require.NoError(t, err)
+
+	handler := HTTPHandler(authenticator, http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	for _, tc := range []struct {
+		name          string
+		authorization string
+		expectedCode  int
+	}{
+		{name: "valid_key", authorization: "Bearer key", expectedCode: http.StatusNoContent},
+		{name: "invalid_key", authorization: "Bearer other", expectedCode: http.StatusUnauthorized},
+		{name: "missing_key", expectedCode: http.StatusUnauthorized},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tc.authorization != "" {
+				req.Header.Set("Authorization", tc.authorization)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			require.Equal(t, tc.expectedCode, rec.Code)
+		})
+	}
+}
@@ -308,6 +308,85 @@ func (mr *MockRelationshipTupleWriterMockRecorder) Write(ctx, store, d, w any, o
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Write", reflect.TypeOf((*MockRelationshipTupleWriter)(nil).Write), varargs...)
 }
 
+// MockTupleSearchBackend is a mock of TupleSearchBackend interface.
+type MockTupleSearchBackend struct {
+	ctrl     *gomock.Controller
+	recorder *MockTupleSearchBackendMockRecorder
+	isgomock struct{}
+}
+
+// MockTupleSearchBackendMockRecorder is the mock recorder for MockTupleSearchBackend.
+type MockTupleSearchBackendMockRecorder struct {
+	mock *MockTupleSearchBackend
+}
+
+// NewMockTupleSearchBackend creates a new mock instance.
+func NewMockTupleSearchBackend(ctrl *gomock.Controller) *MockTupleSearchBackend {
+	mock := &MockTupleSearchBackend{ctrl: ctrl}
+	mock.recorder = &MockTupleSearchBackendMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTupleSearchBackend) EXPECT() *MockTupleSearchBackendMockRecorder {
+	return m.recorder
+}
+
+// SearchTuples mocks base method.
+func (m *MockTupleSearchBackend) SearchTuples(ctx context.Context, store string, filter storage.SearchTuplesFilter, options storage.SearchTuplesOptions) ([]*openfgav1.Tuple, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchTuples", ctx, store, filter, options)
+	ret0, _ := ret[0].([]*openfgav1.Tuple)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// SearchTuples indicates an expected call of SearchTuples.
+func (mr *MockTupleSearchBackendMockRecorder) SearchTuples(ctx, store, filter, options any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchTuples", reflect.TypeOf((*MockTupleSearchBackend)(nil).SearchTuples), ctx, store, filter, options)
+}
+
+// MockStoreStatsBackend is a mock of StoreStatsBackend interface.
+type MockStoreStatsBackend struct {
+	ctrl     *gomock.Controller
+	recorder *MockStoreStatsBackendMockRecorder
+	isgomock struct{}
+}
+
+// MockStoreStatsBackendMockRecorder is the mock recorder for MockStoreStatsBackend.
+type MockStoreStatsBackendMockRecorder struct {
+	mock *MockStoreStatsBackend
+}
+
+// NewMockStoreStatsBackend creates a new mock instance.
+func NewMockStoreStatsBackend(ctrl *gomock.Controller) *MockStoreStatsBackend {
+	mock := &MockStoreStatsBackend{ctrl: ctrl}
+	mock.recorder = &MockStoreStatsBackendMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStoreStatsBackend) EXPECT() *MockStoreStatsBackendMockRecorder {
+	return m.recorder
+}
+
+// ReadStoreStats mocks base method.
+func (m *MockStoreStatsBackend) ReadStoreStats(ctx context.Context, store string) (*storage.StoreStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadStoreStats", ctx, store)
+	ret0, _ := ret[0].(*storage.StoreStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadStoreStats indicates an expected call of ReadStoreStats.
+func (mr *MockStoreStatsBackendMockRecorder) ReadStoreStats(ctx, store any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadStoreStats", reflect.TypeOf((*MockStoreStatsBackend)(nil).ReadStoreStats), ctx, store)
+}
+
 // MockAuthorizationModelReadBackend is a mock of AuthorizationModelReadBackend interface.
 type MockAuthorizationModelReadBackend struct {
 	ctrl     *gomock.Controller
@@ -347,6 +426,21 @@ func (mr *MockAuthorizationModelReadBackendMockRecorder) FindLatestAuthorization
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindLatestAuthorizationModel", reflect.TypeOf((*MockAuthorizationModelReadBackend)(nil).FindLatestAuthorizationModel), ctx, store)
 }
 
+// ReadActiveAuthorizationModelID mocks base method.
+func (m *MockAuthorizationModelReadBackend) ReadActiveAuthorizationModelID(ctx context.Context, store string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadActiveAuthorizationModelID", ctx, store)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadActiveAuthorizationModelID indicates an expected call of ReadActiveAuthorizationModelID.
+func (mr *MockAuthorizationModelReadBackendMockRecorder) ReadActiveAuthorizationModelID(ctx, store any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadActiveAuthorizationModelID", reflect.TypeOf((*MockAuthorizationModelReadBackend)(nil).ReadActiveAuthorizationModelID), ctx, store)
+}
+
 // ReadAuthorizationModel mocks base method.
 func (m *MockAuthorizationModelReadBackend) ReadAuthorizationModel(ctx context.Context, store, id string) (*openfgav1.AuthorizationModel, error) {
 	m.ctrl.T.Helper()
@@ -430,6 +524,44 @@ func (mr *MockTypeDefinitionWriteBackendMockRecorder) WriteAuthorizationModel(ct
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WriteAuthorizationModel", reflect.TypeOf((*MockTypeDefinitionWriteBackend)(nil).WriteAuthorizationModel), ctx, store, model)
 }
 
+// MockActiveAuthorizationModelWriteBackend is a mock of ActiveAuthorizationModelWriteBackend interface.
+type MockActiveAuthorizationModelWriteBackend struct {
+	ctrl     *gomock.Controller
+	recorder *MockActiveAuthorizationModelWriteBackendMockRecorder
+	isgomock struct{}
+}
+
+// MockActiveAuthorizationModelWriteBackendMockRecorder is the mock recorder for MockActiveAuthorizationModelWriteBackend.
+type MockActiveAuthorizationModelWriteBackendMockRecorder struct {
+	mock *MockActiveAuthorizationModelWriteBackend
+}
+
+// NewMockActiveAuthorizationModelWriteBackend creates a new mock instance.
+func NewMockActiveAuthorizationModelWriteBackend(ctrl *gomock.Controller) *MockActiveAuthorizationModelWriteBackend {
+	mock := &MockActiveAuthorizationModelWriteBackend{ctrl: ctrl}
+	mock.recorder = &MockActiveAuthorizationModelWriteBackendMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockActiveAuthorizationModelWriteBackend) EXPECT() *MockActiveAuthorizationModelWriteBackendMockRecorder {
+	return m.recorder
+}
+
+// WriteActiveAuthorizationModelID mocks base method.
+func (m *MockActiveAuthorizationModelWriteBackend) WriteActiveAuthorizationModelID(ctx context.Context, store, modelID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WriteActiveAuthorizationModelID", ctx, store, modelID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WriteActiveAuthorizationModelID indicates an expected call of WriteActiveAuthorizationModelID.
+func (mr *MockActiveAuthorizationModelWriteBackendMockRecorder) WriteActiveAuthorizationModelID(ctx, store, modelID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WriteActiveAuthorizationModelID", reflect.TypeOf((*MockActiveAuthorizationModelWriteBackend)(nil).WriteActiveAuthorizationModelID), ctx, store, modelID)
+}
+
 // MockAuthorizationModelBackend is a mock of AuthorizationModelBackend interface.
 type MockAuthorizationModelBackend struct {
 	ctrl     *gomock.Controller
@@ -483,6 +615,21 @@ func (mr *MockAuthorizationModelBackendMockRecorder) MaxTypesPerAuthorizationMod
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MaxTypesPerAuthorizationModel", reflect.TypeOf((*MockAuthorizationModelBackend)(nil).MaxTypesPerAuthorizationModel))
 }
 
+// ReadActiveAuthorizationModelID mocks base method.
+func (m *MockAuthorizationModelBackend) ReadActiveAuthorizationModelID(ctx context.Context, store string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadActiveAuthorizationModelID", ctx, store)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadActiveAuthorizationModelID indicates an expected call of ReadActiveAuthorizationModelID.
+func (mr *MockAuthorizationModelBackendMockRecorder) ReadActiveAuthorizationModelID(ctx, store any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadActiveAuthorizationModelID", reflect.TypeOf((*MockAuthorizationModelBackend)(nil).ReadActiveAuthorizationModelID), ctx, store)
+}
+
 // ReadAuthorizationModel mocks base method.
 func (m *MockAuthorizationModelBackend) ReadAuthorizationModel(ctx context.Context, store, id string) (*openfgav1.AuthorizationModel, error) {
 	m.ctrl.T.Helper()
@@ -514,6 +661,20 @@ func (mr *MockAuthorizationModelBackendMockRecorder) ReadAuthorizationModels(ctx
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadAuthorizationModels", reflect.TypeOf((*MockAuthorizationModelBackend)(nil).ReadAuthorizationModels), ctx, store, options)
 }
 
+// WriteActiveAuthorizationModelID mocks base method.
+func (m *MockAuthorizationModelBackend) WriteActiveAuthorizationModelID(ctx context.Context, store, modelID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WriteActiveAuthorizationModelID", ctx, store, modelID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WriteActiveAuthorizationModelID indicates an expected call of WriteActiveAuthorizationModelID.
+func (mr *MockAuthorizationModelBackendMockRecorder) WriteActiveAuthorizationModelID(ctx, store, modelID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WriteActiveAuthorizationModelID", reflect.TypeOf((*MockAuthorizationModelBackend)(nil).WriteActiveAuthorizationModelID), ctx, store, modelID)
+}
+
 // WriteAuthorizationModel mocks base method.
 func (m *MockAuthorizationModelBackend) WriteAuthorizationModel(ctx context.Context, store string, model *openfgav1.AuthorizationModel) error {
 	m.ctrl.T.Helper()
@@ -874,6 +1035,21 @@ func (mr *MockOpenFGADatastoreMockRecorder) Read(ctx, store, tupleKey, options a
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Read", reflect.TypeOf((*MockOpenFGADatastore)(nil).Read), ctx, store, tupleKey, options)
 }
 
+// ReadActiveAuthorizationModelID mocks base method.
+func (m *MockOpenFGADatastore) ReadActiveAuthorizationModelID(ctx context.Context, store string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadActiveAuthorizationModelID", ctx, store)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadActiveAuthorizationModelID indicates an expected call of ReadActiveAuthorizationModelID.
+func (mr *MockOpenFGADatastoreMockRecorder) ReadActiveAuthorizationModelID(ctx, store any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadActiveAuthorizationModelID", reflect.TypeOf((*MockOpenFGADatastore)(nil).ReadActiveAuthorizationModelID), ctx, store)
+}
+
 // ReadAssertions mocks base method.
 func (m *MockOpenFGADatastore) ReadAssertions(ctx context.Context, store, modelID string) ([]*openfgav1.Assertion, error) {
 	m.ctrl.T.Helper()
@@ -1016,6 +1192,20 @@ func (mr *MockOpenFGADatastoreMockRecorder) Write(ctx, store, d, w any, opts ...
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Write", reflect.TypeOf((*MockOpenFGADatastore)(nil).Write), varargs...)
 }
 
+// WriteActiveAuthorizationModelID mocks base method.
+func (m *MockOpenFGADatastore) WriteActiveAuthorizationModelID(ctx context.Context, store, modelID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WriteActiveAuthorizationModelID", ctx, store, modelID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WriteActiveAuthorizationModelID indicates an expected call of WriteActiveAuthorizationModelID.
+func (mr *MockOpenFGADatastoreMockRecorder) WriteActiveAuthorizationModelID(ctx, store, modelID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WriteActiveAuthorizationModelID", reflect.TypeOf((*MockOpenFGADatastore)(nil).WriteActiveAuthorizationModelID), ctx, store, modelID)
+}
+
 // WriteAssertions mocks base method.
 func (m *MockOpenFGADatastore) WriteAssertions(ctx context.Context, store, modelID string, assertions []*openfgav1.Assertion) error {
 	m.ctrl.T.Helper()
@@ -2,6 +2,7 @@ package planner
 
 import (
 	"math/rand"
+	"sort"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -174,6 +175,37 @@ func (kp *KeyPlan) UpdateStats(plan *KeyPlanStrategy, duration time.Duration) {
 	ts.Update(duration)
 }
 
+// KeyStats is a snapshot of the plan of a key.
+type KeyStats struct {
+	Key          string                   `json:"key"`
+	LastAccessed time.Time                `json:"last_accessed"`
+	Strategies   map[string]StrategyStats `json:"strategies"`
+}
+
+// Stats returns a snapshot of the plans of all the keys, sorted by key.
+func (p *Planner) Stats() []KeyStats {
+	stats := make([]KeyStats, 0)
+	p.keys.Range(func(key, value interface{}) bool {
+		kp := value.(*KeyPlan)
+		keyStats := KeyStats{
+			Key:          key.(string),
+			LastAccessed: time.Unix(0, kp.lastAccessed.Load()),
+			Strategies:   make(map[string]StrategyStats),
+		}
+		kp.stats.Range(func(strategy, ts interface{}) bool {
+			keyStats.Strategies[strategy.(string)] = ts.(*ThompsonStats).Stats()
+			return true
+		})
+		stats = append(stats, keyStats)
+		return true
+	})
+
+	sort.Slice(stats, func(i, j int) bool {
+		return stats[i].Key < stats[j].Key
+	})
+	return stats
+}
+
 // startCleanupRoutine runs a background goroutine that periodically evicts stale keys.
 func (p *Planner) startCleanupRoutine(interval time.Duration) {
 	ticker := time.NewTicker(interval)
@@ -122,3 +122,23 @@ func TestPlanner_EvictStaleKeys(t *testing.T) {
 	_, exists := p.keys.Load("fresh_key")
 	require.True(t, exists, "fresh key should not have been evicted")
 }
+
+func TestPlanner_Stats(t *testing.T) {
+	p := New(&Config{})
+	require.Empty(t, p.Stats())
+
+	strategy := &KeyPlanStrategy{Type: "fast", InitialGuess: 10 * time.Millisecond, Lambda: 1, Alpha: 1, Beta: 1}
+	p.GetKeyPlan("b").UpdateStats(strategy, 20*time.Millisecond)
+	p.GetKeyPlan("a").UpdateStats(strategy, 10*time.Millisecond)
+
+	stats := p.Stats()
+	require.Len(t, stats, 2)
+	require.Equal(t, "a", stats[0].Key)
+	require.Equal(t, "b", stats[1].Key)
+	require.WithinDuration(t, time.Now(), stats[1].LastAccessed, time.Minute)
+
+	fast := stats[1].Strategies["fast"]
+	require.InDelta(t, 15, fast.MeanMs, 0.001)
+	require.InDelta(t, 2, fast.Lambda, 0)
+	require.InDelta(t, 1.5, fast.Alpha, 0)
+}
@@ -77,6 +77,27 @@ func (ts *ThompsonStats) fastGammaSample(r *rand.Rand, alpha, beta float64) floa
 	return distuv.Gamma{Alpha: alpha, Beta: beta, Src: r}.Rand()
 }
 
+// StrategyStats is a snapshot of the belief about the execution time of a strategy.
+type StrategyStats struct {
+	// MeanMs is the expected execution time, in milliseconds.
+	MeanMs float64 `json:"mean_ms"`
+	// Lambda is the number of runs the mean accounts for, including the confidence in the initial guess.
+	Lambda float64 `json:"lambda"`
+	Alpha  float64 `json:"alpha"`
+	Beta   float64 `json:"beta"`
+}
+
+// Stats returns a snapshot of the parameters of the distribution.
+func (ts *ThompsonStats) Stats() StrategyStats {
+	params := (*samplingParams)(atomic.LoadPointer(&ts.params))
+	return StrategyStats{
+		MeanMs: params.mu,
+		Lambda: params.lambda,
+		Alpha:  params.alpha,
+		Beta:   params.beta,
+	}
+}
+
 // Update performs a Bayesian update on the distribution's parameters
 // using the new data point (the observed execution duration). It is the responsibility of the caller
 // to enforce synchronization if multiple goroutines may call Update concurrently.
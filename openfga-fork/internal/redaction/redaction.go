@@ -0,0 +1,5 @@
+// Package redaction holds what the decision log and the slow log share to redact the sensitive fields they record.
+package redaction
+
+// Placeholder replaces the value of a redacted field.
+const Placeholder = "REDACTED"
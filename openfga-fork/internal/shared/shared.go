@@ -2,7 +2,9 @@ package shared
 
 import (
 	"context"
+	"math"
 	"sync"
+	"time"
 
 	"golang.org/x/sync/singleflight"
 
@@ -10,6 +12,7 @@ import (
 	"github.com/openfga/openfga/pkg/logger"
 	serverconfig "github.com/openfga/openfga/pkg/server/config"
 	"github.com/openfga/openfga/pkg/storage"
+	"github.com/openfga/openfga/pkg/storage/rediscache"
 	"github.com/openfga/openfga/pkg/storage/storagewrappers/sharediterator"
 )
 
@@ -71,9 +74,7 @@ func NewSharedDatastoreResources(
 
 	if settings.ShouldCreateNewCache() {
 		var err error
-		s.CheckCache, err = storage.NewInMemoryLRUCache([]storage.InMemoryLRUCacheOpt[any]{
-			storage.WithMaxCacheSize[any](int64(settings.CheckCacheLimit)),
-		}...)
+		s.CheckCache, err = newCheckCache(settings, "", s.Logger)
 		if err != nil {
 			return nil, err
 		}
@@ -92,9 +93,7 @@ func NewSharedDatastoreResources(
 
 	if settings.ShouldCreateShadowNewCache() {
 		var err error
-		s.ShadowCheckCache, err = storage.NewInMemoryLRUCache([]storage.InMemoryLRUCacheOpt[any]{
-			storage.WithMaxCacheSize[any](int64(settings.CheckCacheLimit)),
-		}...)
+		s.ShadowCheckCache, err = newCheckCache(settings, "shadow:", s.Logger)
 		if err != nil {
 			return nil, err
 		}
@@ -111,6 +110,49 @@ func NewSharedDatastoreResources(
 	return s, nil
 }
 
+// newCheckCache creates the cache configured by settings. keyPrefix keeps the entries of distinct caches
+// apart when they are stored in the same Redis database.
+func newCheckCache(settings serverconfig.CacheSettings, keyPrefix string, logger logger.Logger) (storage.InMemoryCache[any], error) {
+	if settings.CheckCacheBackend == serverconfig.CheckCacheBackendRedis {
+		redis := settings.CheckCacheRedis
+		return rediscache.New(redis.Addr,
+			rediscache.WithPassword[any](redis.Password),
+			rediscache.WithDB[any](redis.DB),
+			rediscache.WithKeyPrefix[any](serverconfig.DefaultCheckCacheRedisKeyPrefix+keyPrefix),
+			rediscache.WithLocalTTL[any](redis.LocalTTL),
+			rediscache.WithLocalMaxItems[any](int64(settings.CheckCacheLimit)),
+			rediscache.WithTimeout[any](redis.Timeout),
+			rediscache.WithLogger[any](logger),
+		)
+	}
+
+	return storage.NewInMemoryLRUCache([]storage.InMemoryLRUCacheOpt[any]{
+		storage.WithMaxCacheSize[any](int64(settings.CheckCacheLimit)),
+	}...)
+}
+
+// InvalidateCheckCache invalidates the Check results cached for storeID so far, in the check cache and in
+// the shadow check cache.
+func (s *SharedDatastoreResources) InvalidateCheckCache(storeID string) {
+	s.invalidate(storage.GetInvalidCheckCacheKey(storeID))
+}
+
+// InvalidateIteratorCache invalidates the iterators cached for storeID so far, the same way the cache
+// controller does it when all the iterators of a store are stale.
+func (s *SharedDatastoreResources) InvalidateIteratorCache(storeID string) {
+	s.invalidate(storage.GetInvalidIteratorCacheKey(storeID))
+}
+
+func (s *SharedDatastoreResources) invalidate(key string) {
+	entry := &storage.InvalidEntityCacheEntry{LastModified: time.Now()}
+	if s.CheckCache != nil {
+		s.CheckCache.Set(key, entry, math.MaxInt)
+	}
+	if s.ShadowCheckCache != nil && s.ShadowCheckCache != s.CheckCache {
+		s.ShadowCheckCache.Set(key, entry, math.MaxInt)
+	}
+}
+
 func (s *SharedDatastoreResources) Close() {
 	// wait for any goroutines still in flight before
 	// closing the cache instance to avoid data races
@@ -3,6 +3,7 @@ package shared
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
@@ -11,6 +12,8 @@ import (
 	"github.com/openfga/openfga/internal/cachecontroller"
 	mockstorage "github.com/openfga/openfga/internal/mocks"
 	"github.com/openfga/openfga/pkg/server/config"
+	"github.com/openfga/openfga/pkg/storage"
+	"github.com/openfga/openfga/pkg/storage/rediscache"
 )
 
 func TestSharedDatastoreResources(t *testing.T) {
@@ -50,6 +53,30 @@ func TestSharedDatastoreResources(t *testing.T) {
 		require.Equal(t, s.CheckCache, s.ShadowCheckCache)
 	})
 
+	t.Run("with_redis_cache", func(t *testing.T) {
+		settings := config.CacheSettings{
+			CheckCacheLimit:           1,
+			CheckIteratorCacheEnabled: true,
+			ShadowCheckCacheEnabled:   true,
+			CheckCacheBackend:         config.CheckCacheBackendRedis,
+			CheckCacheRedis: config.CheckCacheRedisConfig{
+				// The server does not need to be reachable.
+				Addr:    "127.0.0.1:0",
+				Timeout: time.Millisecond,
+			},
+		}
+
+		s, err := NewSharedDatastoreResources(sharedCtx, sharedSf, mockDatastore, settings)
+		require.NoError(t, err)
+		t.Cleanup(s.Close)
+
+		_, ok := s.CheckCache.(*rediscache.Cache[any])
+		require.True(t, ok)
+		_, ok = s.ShadowCheckCache.(*rediscache.Cache[any])
+		require.True(t, ok)
+		require.NotEqual(t, s.CheckCache, s.ShadowCheckCache)
+	})
+
 	t.Run("with_cache_controller", func(t *testing.T) {
 		settings := config.CacheSettings{
 			CheckCacheLimit:           1,
@@ -99,4 +126,26 @@ func TestSharedDatastoreResources(t *testing.T) {
 		require.True(t, ok)
 		require.NotEqual(t, s.CacheController, s.ShadowCacheController)
 	})
+	t.Run("invalidate", func(t *testing.T) {
+		settings := config.CacheSettings{
+			CheckCacheLimit:           10,
+			CheckIteratorCacheEnabled: true,
+			ShadowCheckCacheEnabled:   true,
+		}
+
+		s, err := NewSharedDatastoreResources(sharedCtx, sharedSf, mockDatastore, settings)
+		require.NoError(t, err)
+		t.Cleanup(s.Close)
+
+		s.InvalidateCheckCache("store")
+		s.InvalidateIteratorCache("store")
+
+		for _, cache := range []storage.InMemoryCache[any]{s.CheckCache, s.ShadowCheckCache} {
+			for _, key := range []string{storage.GetInvalidCheckCacheKey("store"), storage.GetInvalidIteratorCacheKey("store")} {
+				entry, ok := cache.Get(key).(*storage.InvalidEntityCacheEntry)
+				require.True(t, ok)
+				require.WithinDuration(t, time.Now(), entry.LastModified, time.Minute)
+			}
+		}
+	})
 }
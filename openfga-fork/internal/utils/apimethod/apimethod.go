@@ -28,4 +28,54 @@ const (
 	DeleteStore             APIMethod = "DeleteStore"
 	Expand                  APIMethod = "Expand"
 	ReadChanges             APIMethod = "ReadChanges"
+
+	ReadActiveAuthorizationModel     APIMethod = "ReadActiveAuthorizationModel"
+	WriteActiveAuthorizationModel    APIMethod = "WriteActiveAuthorizationModel"
+	RollbackActiveAuthorizationModel APIMethod = "RollbackActiveAuthorizationModel"
+	PermissionMatrix                 APIMethod = "PermissionMatrix"
+	AccessReview                     APIMethod = "AccessReview"
+	WhatIf                           APIMethod = "WhatIf"
+	StreamedListUsers                APIMethod = "StreamedListUsers"
+	ListRelations                    APIMethod = "ListRelations"
+	StreamedBatchCheck               APIMethod = "StreamedBatchCheck"
+	SearchTuples                     APIMethod = "SearchTuples"
+	GetStoreStats                    APIMethod = "GetStoreStats"
 )
+
+var methods = map[APIMethod]struct{}{
+	ReadAuthorizationModel:           {},
+	ReadAuthorizationModels:          {},
+	Read:                             {},
+	Write:                            {},
+	ListObjects:                      {},
+	StreamedListObjects:              {},
+	Check:                            {},
+	BatchCheck:                       {},
+	ListUsers:                        {},
+	WriteAssertions:                  {},
+	ReadAssertions:                   {},
+	WriteAuthorizationModel:          {},
+	ListStores:                       {},
+	CreateStore:                      {},
+	GetStore:                         {},
+	DeleteStore:                      {},
+	Expand:                           {},
+	ReadChanges:                      {},
+	ReadActiveAuthorizationModel:     {},
+	WriteActiveAuthorizationModel:    {},
+	RollbackActiveAuthorizationModel: {},
+	PermissionMatrix:                 {},
+	AccessReview:                     {},
+	WhatIf:                           {},
+	StreamedListUsers:                {},
+	ListRelations:                    {},
+	StreamedBatchCheck:               {},
+	SearchTuples:                     {},
+	GetStoreStats:                    {},
+}
+
+// Parse returns the API method with the given name, or false if there is none.
+func Parse(name string) (APIMethod, bool) {
+	_, ok := methods[APIMethod(name)]
+	return APIMethod(name), ok
+}
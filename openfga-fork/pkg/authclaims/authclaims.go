@@ -34,6 +34,19 @@ func AuthClaimsFromContext(ctx context.Context) (*AuthClaims, bool) {
 	return claims, true
 }
 
+// ClientIDFromContext returns the client ID, or else the subject, of the AuthClaims in ctx, or an empty
+// string if ctx has no AuthClaims.
+func ClientIDFromContext(ctx context.Context) string {
+	claims, ok := AuthClaimsFromContext(ctx)
+	if !ok || claims == nil {
+		return ""
+	}
+	if claims.ClientID != "" {
+		return claims.ClientID
+	}
+	return claims.Subject
+}
+
 // ContextWithSkipAuthzCheck creates a copy of the parent context and attaches whether to skip authz check to.
 func ContextWithSkipAuthzCheck(parent context.Context, skipAuthzCheck bool) context.Context {
 	return context.WithValue(parent, skipAuthz, skipAuthzCheck)
@@ -26,6 +26,12 @@ func TestAuthClaimsFromContext(t *testing.T) {
 	require.False(t, value)
 }
 
+func TestClientIDFromContext(t *testing.T) {
+	require.Empty(t, ClientIDFromContext(context.Background()))
+	require.Equal(t, "openfga", ClientIDFromContext(ContextWithAuthClaims(context.Background(), &AuthClaims{Subject: "openfga client", ClientID: "openfga"})))
+	require.Equal(t, "openfga client", ClientIDFromContext(ContextWithAuthClaims(context.Background(), &AuthClaims{Subject: "openfga client"})))
+}
+
 func TestSkipAuthzCheckFromContext(t *testing.T) {
 	t.Run("false", func(t *testing.T) {
 		ctx := ContextWithSkipAuthzCheck(context.Background(), false)
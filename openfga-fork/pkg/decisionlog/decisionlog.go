@@ -0,0 +1,224 @@
+// Package decisionlog records the authorization decisions made by Check, BatchCheck and ListObjects to a
+// sink, sampled per store and with sensitive fields redacted.
+package decisionlog
+
+import (
+	"context"
+	"math/rand/v2"
+	"time"
+
+	grpc_ctxtags "github.com/grpc-ecosystem/go-grpc-middleware/tags"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/openfga/openfga/internal/build"
+	"github.com/openfga/openfga/internal/redaction"
+	"github.com/openfga/openfga/pkg/authclaims"
+	"github.com/openfga/openfga/pkg/logger"
+)
+
+const (
+	// Redacted replaces the value of the redacted fields.
+	Redacted = redaction.Placeholder
+
+	// The fields of a Decision that can be redacted.
+	FieldUser      = "user"
+	FieldObject    = "object"
+	FieldObjects   = "objects"
+	FieldPrincipal = "principal"
+	FieldContext   = "context"
+
+	resultWritten    = "written"
+	resultSampledOut = "sampled_out"
+	resultFailed     = "failed"
+
+	requestIDTag = "request_id"
+)
+
+// RedactableFields are the fields of a Decision that can be redacted.
+var RedactableFields = []string{FieldUser, FieldObject, FieldObjects, FieldPrincipal, FieldContext}
+
+var decisionLogCount = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: build.ProjectName,
+	Name:      "decision_log_count",
+	Help:      "The total number of authorization decisions seen by the decision log, labeled by method and result ('written', 'sampled_out' or 'failed').",
+}, []string{"grpc_method", "result"})
+
+// Decision is an authorization decision. Allowed is set for Check and BatchCheck decisions, and Objects for
+// ListObjects decisions. Error is set for the checks of a BatchCheck that could not be resolved.
+type Decision struct {
+	Time                 time.Time      `json:"time"`
+	Method               string         `json:"method"`
+	StoreID              string         `json:"store_id"`
+	AuthorizationModelID string         `json:"authorization_model_id"`
+	RequestID            string         `json:"request_id,omitempty"`
+	CorrelationID        string         `json:"correlation_id,omitempty"`
+	Principal            string         `json:"principal,omitempty"`
+	User                 string         `json:"user"`
+	Relation             string         `json:"relation"`
+	Object               string         `json:"object,omitempty"`
+	ObjectType           string         `json:"object_type,omitempty"`
+	Allowed              *bool          `json:"allowed,omitempty"`
+	Objects              []string       `json:"objects,omitempty"`
+	Error                string         `json:"error,omitempty"`
+	Context              map[string]any `json:"context,omitempty"`
+	ContextualTupleCount int            `json:"contextual_tuple_count"`
+	DispatchCount        uint32         `json:"dispatch_count"`
+	DatastoreQueryCount  uint32         `json:"datastore_query_count"`
+}
+
+// Sink stores the decisions.
+type Sink interface {
+	Write(ctx context.Context, decision *Decision) error
+	Close() error
+}
+
+// Logger samples, redacts and writes decisions to a Sink.
+type Logger struct {
+	sink             Sink
+	sampleRate       float64
+	storeSampleRates map[string]float64
+	redactFields     map[string]struct{}
+	logger           logger.Logger
+}
+
+type LoggerOption func(*Logger)
+
+// WithSampleRate sets the fraction, between 0 and 1, of the decisions that are written. The default is 1.
+func WithSampleRate(rate float64) LoggerOption {
+	return func(l *Logger) {
+		l.sampleRate = rate
+	}
+}
+
+// WithStoreSampleRates overrides the sample rate of the decisions of some stores.
+func WithStoreSampleRates(rates map[string]float64) LoggerOption {
+	return func(l *Logger) {
+		l.storeSampleRates = rates
+	}
+}
+
+// WithRedactedFields replaces the value of the fields, among RedactableFields, with Redacted.
+func WithRedactedFields(fields ...string) LoggerOption {
+	return func(l *Logger) {
+		for _, field := range fields {
+			l.redactFields[field] = struct{}{}
+		}
+	}
+}
+
+// WithLogger sets the logger of the errors of the sink.
+func WithLogger(logger logger.Logger) LoggerOption {
+	return func(l *Logger) {
+		l.logger = logger
+	}
+}
+
+// NewLogger returns a Logger that writes the decisions to sink.
+func NewLogger(sink Sink, opts ...LoggerOption) *Logger {
+	l := &Logger{
+		sink:         sink,
+		sampleRate:   1,
+		redactFields: map[string]struct{}{},
+		logger:       logger.NewNoopLogger(),
+	}
+
+	for _, opt := range opts {
+		opt(l)
+	}
+
+	return l
+}
+
+// Log writes the decision if it is sampled. The time, request ID and principal are set from ctx when they are
+// missing. Errors of the sink are logged and not returned, so that they never fail the request.
+func (l *Logger) Log(ctx context.Context, decision *Decision) {
+	l.LogWithConditionContext(ctx, decision, nil)
+}
+
+// LogWithConditionContext is Log for a decision whose Context is set from the condition context of the request,
+// which is only converted once the decision is sampled.
+func (l *Logger) LogWithConditionContext(ctx context.Context, decision *Decision, conditionContext *structpb.Struct) {
+	if !l.sampled(decision.StoreID) {
+		decisionLogCount.WithLabelValues(decision.Method, resultSampledOut).Inc()
+		return
+	}
+
+	if len(conditionContext.GetFields()) > 0 {
+		decision.Context = conditionContext.AsMap()
+	}
+
+	if decision.Time.IsZero() {
+		decision.Time = time.Now().UTC()
+	}
+	if decision.RequestID == "" {
+		if requestID, ok := grpc_ctxtags.Extract(ctx).Values()[requestIDTag].(string); ok {
+			decision.RequestID = requestID
+		}
+	}
+	if decision.Principal == "" {
+		decision.Principal = authclaims.ClientIDFromContext(ctx)
+	}
+
+	l.redact(decision)
+
+	if err := l.sink.Write(ctx, decision); err != nil {
+		decisionLogCount.WithLabelValues(decision.Method, resultFailed).Inc()
+		l.logger.WarnWithContext(ctx, "failed to write decision log", zap.String("method", decision.Method), zap.Error(err))
+		return
+	}
+
+	decisionLogCount.WithLabelValues(decision.Method, resultWritten).Inc()
+}
+
+// Close closes the sink.
+func (l *Logger) Close() error {
+	return l.sink.Close()
+}
+
+func (l *Logger) sampled(storeID string) bool {
+	rate, ok := l.storeSampleRates[storeID]
+	if !ok {
+		rate = l.sampleRate
+	}
+
+	switch {
+	case rate >= 1:
+		return true
+	case rate <= 0:
+		return false
+	default:
+		return rand.Float64() < rate
+	}
+}
+
+func (l *Logger) redact(decision *Decision) {
+	for field := range l.redactFields {
+		switch field {
+		case FieldUser:
+			decision.User = Redacted
+		case FieldObject:
+			if decision.Object != "" {
+				decision.Object = Redacted
+			}
+		case FieldObjects:
+			// The objects are replaced one by one, so that their number is kept. The slice is not modified in
+			// place since it may be the one of the response.
+			objects := make([]string, len(decision.Objects))
+			for i := range objects {
+				objects[i] = Redacted
+			}
+			decision.Objects = objects
+		case FieldPrincipal:
+			if decision.Principal != "" {
+				decision.Principal = Redacted
+			}
+		case FieldContext:
+			for key := range decision.Context {
+				decision.Context[key] = Redacted
+			}
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package decisionlog
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	grpc_ctxtags "github.com/grpc-ecosystem/go-grpc-middleware/tags"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/openfga/openfga/pkg/authclaims"
+)
+
+type recordingSink struct {
+	decisions []*Decision
+	err       error
+}
+
+func (s *recordingSink) Write(_ context.Context, decision *Decision) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.decisions = append(s.decisions, decision)
+	return nil
+}
+
+func (s *recordingSink) Close() error {
+	return nil
+}
+
+func TestLoggerLog(t *testing.T) {
+	t.Run("sets_time_request_id_and_principal", func(t *testing.T) {
+		sink := &recordingSink{}
+		logger := NewLogger(sink)
+
+		ctx := grpc_ctxtags.SetInContext(context.Background(), grpc_ctxtags.NewTags())
+		grpc_ctxtags.Extract(ctx).Set(requestIDTag, "request")
+		ctx = authclaims.ContextWithAuthClaims(ctx, &authclaims.AuthClaims{Subject: "anne", ClientID: "app"})
+
+		logger.Log(ctx, &Decision{Method: "Check", StoreID: "store"})
+
+		require.Len(t, sink.decisions, 1)
+		require.False(t, sink.decisions[0].Time.IsZero())
+		require.Equal(t, "request", sink.decisions[0].RequestID)
+		require.Equal(t, "app", sink.decisions[0].Principal)
+	})
+
+	t.Run("samples_per_store", func(t *testing.T) {
+		sink := &recordingSink{}
+		logger := NewLogger(sink, WithSampleRate(0), WithStoreSampleRates(map[string]float64{"audited": 1}))
+
+		for range 10 {
+			logger.Log(context.Background(), &Decision{Method: "Check", StoreID: "store"})
+			logger.Log(context.Background(), &Decision{Method: "Check", StoreID: "audited"})
+		}
+
+		require.Len(t, sink.decisions, 10)
+		for _, decision := range sink.decisions {
+			require.Equal(t, "audited", decision.StoreID)
+		}
+	})
+
+	t.Run("condition_context_of_sampled_decisions", func(t *testing.T) {
+		sink := &recordingSink{}
+		logger := NewLogger(sink, WithSampleRate(0), WithStoreSampleRates(map[string]float64{"audited": 1}))
+
+		conditionContext, err := structpb.NewStruct(map[string]any{"ip": "10.0.0.1"})
+		require.NoError(t, err)
+
+		sampledOut := &Decision{Method: "Check", StoreID: "store"}
+		logger.LogWithConditionContext(context.Background(), sampledOut, conditionContext)
+		require.Nil(t, sampledOut.Context)
+
+		logger.LogWithConditionContext(context.Background(), &Decision{Method: "Check", StoreID: "audited"}, conditionContext)
+		require.Len(t, sink.decisions, 1)
+		require.Equal(t, map[string]any{"ip": "10.0.0.1"}, sink.decisions[0].Context)
+	})
+
+	t.Run("redacts_fields", func(t *testing.T) {
+		sink := &recordingSink{}
+		logger := NewLogger(sink, WithRedactedFields(FieldUser, FieldObjects, FieldPrincipal, FieldContext))
+
+		objects := []string{"document:1", "document:2"}
+		ctx := authclaims.ContextWithAuthClaims(context.Background(), &authclaims.AuthClaims{Subject: "anne"})
+		logger.Log(ctx, &Decision{
+			Method:     "ListObjects",
+			User:       "user:anne",
+			Relation:   "viewer",
+			ObjectType: "document",
+			Objects:    objects,
+			Context:    map[string]any{"ip": "10.0.0.1"},
+		})
+
+		require.Len(t, sink.decisions, 1)
+		decision := sink.decisions[0]
+		require.Equal(t, Redacted, decision.User)
+		require.Equal(t, "viewer", decision.Relation)
+		require.Equal(t, "document", decision.ObjectType)
+		require.Equal(t, []string{Redacted, Redacted}, decision.Objects)
+		require.Equal(t, Redacted, decision.Principal)
+		require.Equal(t, map[string]any{"ip": Redacted}, decision.Context)
+
+		// The objects of the response are not modified.
+		require.Equal(t, []string{"document:1", "document:2"}, objects)
+	})
+
+	t.Run("sink_errors_are_not_returned", func(t *testing.T) {
+		logger := NewLogger(&recordingSink{err: errors.New("unavailable")})
+
+		require.NotPanics(t, func() {
+			logger.Log(context.Background(), &Decision{Method: "Check"})
+		})
+	})
+}
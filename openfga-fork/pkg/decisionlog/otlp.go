@@ -0,0 +1,138 @@
+package decisionlog
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploggrpc"
+	otellog "go.opentelemetry.io/otel/log"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	"go.opentelemetry.io/otel/sdk/resource"
+
+	"github.com/openfga/openfga/internal/build"
+)
+
+const (
+	// otlpEventName is the event name of the log records of the decisions.
+	otlpEventName = "openfga.decision"
+
+	otlpShutdownTimeout = 5 * time.Second
+)
+
+// OTLPSink exports the decisions as log records to an OTLP collector over gRPC. Records are exported in
+// batches in the background.
+type OTLPSink struct {
+	provider *sdklog.LoggerProvider
+	logger   otellog.Logger
+}
+
+var _ Sink = (*OTLPSink)(nil)
+
+// NewOTLPSink returns an OTLPSink that exports to the collector at endpoint, without TLS if insecure is set.
+func NewOTLPSink(ctx context.Context, endpoint string, insecure bool) (*OTLPSink, error) {
+	options := []otlploggrpc.Option{otlploggrpc.WithEndpoint(endpoint)}
+	if insecure {
+		options = append(options, otlploggrpc.WithInsecure())
+	}
+
+	exporter, err := otlploggrpc.New(ctx, options...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the decision log exporter: %w", err)
+	}
+
+	res, err := resource.Merge(
+		resource.Default(),
+		resource.NewSchemaless(attribute.String("service.name", build.ProjectName)),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	provider := sdklog.NewLoggerProvider(
+		sdklog.WithResource(res),
+		sdklog.WithProcessor(sdklog.NewBatchProcessor(exporter)),
+	)
+
+	return &OTLPSink{
+		provider: provider,
+		logger:   provider.Logger("github.com/openfga/openfga/pkg/decisionlog"),
+	}, nil
+}
+
+// Write see [Sink.Write].
+func (s *OTLPSink) Write(ctx context.Context, decision *Decision) error {
+	record, err := newRecord(decision)
+	if err != nil {
+		return err
+	}
+
+	s.logger.Emit(ctx, record)
+	return nil
+}
+
+// Close see [Sink.Close]. It exports the pending records.
+func (s *OTLPSink) Close() error {
+	ctx, cancel := context.WithTimeout(context.Background(), otlpShutdownTimeout)
+	defer cancel()
+
+	return s.provider.Shutdown(ctx)
+}
+
+func newRecord(decision *Decision) (otellog.Record, error) {
+	var record otellog.Record
+	record.SetEventName(otlpEventName)
+	record.SetTimestamp(decision.Time)
+	record.SetSeverity(otellog.SeverityInfo)
+	record.SetBody(otellog.StringValue("authorization decision"))
+
+	record.AddAttributes(
+		otellog.String("method", decision.Method),
+		otellog.String("store_id", decision.StoreID),
+		otellog.String("authorization_model_id", decision.AuthorizationModelID),
+		otellog.String("user", decision.User),
+		otellog.String("relation", decision.Relation),
+		otellog.Int("contextual_tuple_count", decision.ContextualTupleCount),
+		otellog.Int64("dispatch_count", int64(decision.DispatchCount)),
+		otellog.Int64("datastore_query_count", int64(decision.DatastoreQueryCount)),
+	)
+
+	optional := []struct{ key, value string }{
+		{"request_id", decision.RequestID},
+		{"correlation_id", decision.CorrelationID},
+		{"principal", decision.Principal},
+		{"object", decision.Object},
+		{"object_type", decision.ObjectType},
+		{"error", decision.Error},
+	}
+	for _, attr := range optional {
+		if attr.value != "" {
+			record.AddAttributes(otellog.String(attr.key, attr.value))
+		}
+	}
+
+	if decision.Allowed != nil {
+		record.AddAttributes(otellog.Bool("allowed", *decision.Allowed))
+	}
+
+	if decision.Objects != nil {
+		objects := make([]otellog.Value, 0, len(decision.Objects))
+		for _, object := range decision.Objects {
+			objects = append(objects, otellog.StringValue(object))
+		}
+		record.AddAttributes(otellog.Slice("objects", objects...))
+	}
+
+	if len(decision.Context) > 0 {
+		// The context is exported as JSON, since its values can be of any type.
+		encoded, err := json.Marshal(decision.Context)
+		if err != nil {
+			return record, err
+		}
+		record.AddAttributes(otellog.String("context", string(encoded)))
+	}
+
+	return record, nil
+}
@@ -0,0 +1,44 @@
+package decisionlog
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	otellog "go.opentelemetry.io/otel/log"
+)
+
+func TestNewRecord(t *testing.T) {
+	allowed := false
+	record, err := newRecord(&Decision{
+		Time:                 time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		Method:               "Check",
+		StoreID:              "store",
+		User:                 "user:anne",
+		Relation:             "viewer",
+		Object:               "document:1",
+		Allowed:              &allowed,
+		Context:              map[string]any{"ip": "10.0.0.1"},
+		ContextualTupleCount: 2,
+		DispatchCount:        3,
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, otlpEventName, record.EventName())
+	require.Equal(t, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), record.Timestamp())
+
+	attributes := map[string]otellog.Value{}
+	record.WalkAttributes(func(kv otellog.KeyValue) bool {
+		attributes[kv.Key] = kv.Value
+		return true
+	})
+
+	require.Equal(t, "store", attributes["store_id"].AsString())
+	require.Equal(t, "document:1", attributes["object"].AsString())
+	require.False(t, attributes["allowed"].AsBool())
+	require.Equal(t, `{"ip":"10.0.0.1"}`, attributes["context"].AsString())
+	require.Equal(t, int64(2), attributes["contextual_tuple_count"].AsInt64())
+	require.Equal(t, int64(3), attributes["dispatch_count"].AsInt64())
+	require.NotContains(t, attributes, "object_type")
+	require.NotContains(t, attributes, "objects")
+}
@@ -0,0 +1,192 @@
+package decisionlog
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// backupTimeFormat is the suffix of the rotated files of a FileSink. It sorts in chronological order.
+const backupTimeFormat = "20060102T150405.000000000"
+
+// WriterSink writes the decisions to an io.Writer as JSON lines.
+type WriterSink struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+var _ Sink = (*WriterSink)(nil)
+
+// NewWriterSink returns a WriterSink that writes to w, e.g. os.Stdout.
+func NewWriterSink(w io.Writer) *WriterSink {
+	return &WriterSink{w: w}
+}
+
+// Write see [Sink.Write].
+func (s *WriterSink) Write(_ context.Context, decision *Decision) error {
+	line, err := marshalLine(decision)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, err = s.w.Write(line)
+	return err
+}
+
+// Close see [Sink.Close]. The writer is not closed.
+func (s *WriterSink) Close() error {
+	return nil
+}
+
+// FileSink writes the decisions to a file as JSON lines. When the file would grow over its maximum size, it is
+// renamed with the time of the rotation as a suffix and a new file is started. Only the most recent rotated
+// files are kept.
+type FileSink struct {
+	mu         sync.Mutex
+	path       string
+	maxSize    int64
+	maxBackups int
+	file       *os.File
+	size       int64
+	now        func() time.Time
+}
+
+var _ Sink = (*FileSink)(nil)
+
+// NewFileSink returns a FileSink that appends to the file at path, rotated when it reaches maxSize bytes. At
+// most maxBackups rotated files are kept.
+func NewFileSink(path string, maxSize int64, maxBackups int) (*FileSink, error) {
+	s := &FileSink{
+		path:       path,
+		maxSize:    maxSize,
+		maxBackups: maxBackups,
+		now:        time.Now,
+	}
+
+	if err := s.open(); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// Write see [Sink.Write].
+func (s *FileSink) Write(_ context.Context, decision *Decision) error {
+	line, err := marshalLine(decision)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.file == nil {
+		return errors.New("decision log file is closed")
+	}
+
+	if s.size > 0 && s.size+int64(len(line)) > s.maxSize {
+		if err := s.rotate(); err != nil {
+			return err
+		}
+	}
+
+	n, err := s.file.Write(line)
+	s.size += int64(n)
+	return err
+}
+
+// Close see [Sink.Close].
+func (s *FileSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.file == nil {
+		return nil
+	}
+
+	err := s.file.Close()
+	s.file = nil
+	return err
+}
+
+func (s *FileSink) open() error {
+	file, err := os.OpenFile(s.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open decision log file: %w", err)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return fmt.Errorf("failed to open decision log file: %w", err)
+	}
+
+	s.file = file
+	s.size = info.Size()
+	return nil
+}
+
+// rotate renames the active file to a backup and opens a new active file. The active file is opened again
+// whatever fails, so that a failed rotation only fails the current write and is retried by the next one.
+func (s *FileSink) rotate() error {
+	err := s.file.Close()
+	s.file = nil
+
+	if err == nil {
+		backup := s.path + "." + s.now().UTC().Format(backupTimeFormat)
+		if renameErr := os.Rename(s.path, backup); renameErr != nil {
+			err = fmt.Errorf("failed to rotate decision log file: %w", renameErr)
+		} else {
+			err = s.removeOldBackups()
+		}
+	}
+
+	return errors.Join(err, s.open())
+}
+
+// removeOldBackups removes the rotated files but the maxBackups most recent ones.
+func (s *FileSink) removeOldBackups() error {
+	matches, err := filepath.Glob(s.path + ".*")
+	if err != nil {
+		return err
+	}
+
+	backups := make([]string, 0, len(matches))
+	for _, match := range matches {
+		if _, err := time.Parse(backupTimeFormat, strings.TrimPrefix(match, s.path+".")); err == nil {
+			backups = append(backups, match)
+		}
+	}
+
+	if len(backups) <= s.maxBackups {
+		return nil
+	}
+
+	slices.Sort(backups)
+	for _, backup := range backups[:len(backups)-s.maxBackups] {
+		if err := os.Remove(backup); err != nil {
+			return fmt.Errorf("failed to remove rotated decision log file: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func marshalLine(decision *Decision) ([]byte, error) {
+	line, err := json.Marshal(decision)
+	if err != nil {
+		return nil, err
+	}
+	return append(line, '\n'), nil
+}
@@ -0,0 +1,105 @@
+package decisionlog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriterSink(t *testing.T) {
+	var buf bytes.Buffer
+	sink := NewWriterSink(&buf)
+
+	allowed := true
+	require.NoError(t, sink.Write(context.Background(), &Decision{
+		Method:   "Check",
+		StoreID:  "store",
+		User:     "user:anne",
+		Relation: "viewer",
+		Object:   "document:1",
+		Allowed:  &allowed,
+	}))
+	require.NoError(t, sink.Write(context.Background(), &Decision{Method: "Check"}))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 2)
+
+	var decision map[string]any
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &decision))
+	require.Equal(t, "store", decision["store_id"])
+	require.Equal(t, "document:1", decision["object"])
+	require.Equal(t, true, decision["allowed"])
+	require.NotContains(t, decision, "objects")
+}
+
+func TestFileSink(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "decisions.log")
+
+	sink, err := NewFileSink(path, 500, 2)
+	require.NoError(t, err)
+
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	sink.now = func() time.Time {
+		now = now.Add(time.Second)
+		return now
+	}
+
+	// An unrelated file next to the log is never removed.
+	require.NoError(t, os.WriteFile(path+".old", []byte("keep"), 0o600))
+
+	for range 20 {
+		require.NoError(t, sink.Write(context.Background(), &Decision{Method: "Check", StoreID: "store", User: "user:anne"}))
+	}
+	require.NoError(t, sink.Close())
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+
+	var backups int
+	for _, entry := range entries {
+		info, err := entry.Info()
+		require.NoError(t, err)
+		require.LessOrEqual(t, info.Size(), int64(500))
+		if strings.HasPrefix(entry.Name(), "decisions.log.2025") {
+			backups++
+		}
+	}
+	require.Equal(t, 2, backups)
+	require.FileExists(t, path)
+	require.FileExists(t, path+".old")
+
+	// Writes after Close fail instead of reopening the file.
+	require.Error(t, sink.Write(context.Background(), &Decision{Method: "Check"}))
+}
+
+func TestFileSinkFailedRotation(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "decisions.log")
+
+	sink, err := NewFileSink(path, 100, 2)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = sink.Close() })
+
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	sink.now = func() time.Time { return now }
+
+	// The backup cannot be renamed onto a directory.
+	require.NoError(t, os.Mkdir(path+"."+now.Format(backupTimeFormat), 0o700))
+
+	decision := &Decision{Method: "Check", StoreID: "store", User: "user:anne"}
+	require.NoError(t, sink.Write(context.Background(), decision))
+	require.ErrorContains(t, sink.Write(context.Background(), decision), "failed to rotate decision log file")
+
+	// The next write rotates the file again instead of failing because the file is closed.
+	now = now.Add(time.Second)
+	require.NoError(t, sink.Write(context.Background(), decision))
+	require.FileExists(t, path+"."+now.Format(backupTimeFormat))
+}
@@ -33,7 +33,7 @@ type Logger interface {
 // NewNoopLogger provides a noop logger.
 func NewNoopLogger() *ZapLogger {
 	return &ZapLogger{
-		zap.NewNop(),
+		Logger: zap.NewNop(),
 	}
 }
 
@@ -41,6 +41,9 @@ func NewNoopLogger() *ZapLogger {
 // It provides additional methods such as ones that logs based on context.
 type ZapLogger struct {
 	*zap.Logger
+
+	// level is the level of the loggers built by NewLogger, which can be changed at runtime.
+	level *zap.AtomicLevel
 }
 
 var _ Logger = (*ZapLogger)(nil)
@@ -49,7 +52,13 @@ var _ Logger = (*ZapLogger)(nil)
 // to the child don't affect the parent, and vice versa. Any fields that
 // require evaluation (such as Objects) are evaluated upon invocation of With.
 func (l *ZapLogger) With(fields ...zap.Field) Logger {
-	return &ZapLogger{l.Logger.With(fields...)}
+	return &ZapLogger{Logger: l.Logger.With(fields...), level: l.level}
+}
+
+// AtomicLevel returns the level of the logger, which can be changed at runtime, e.g. by serving it over HTTP
+// (see [zap.AtomicLevel.ServeHTTP]). It is nil for the loggers not built by NewLogger and for the noop logger.
+func (l *ZapLogger) AtomicLevel() *zap.AtomicLevel {
+	return l.level
 }
 
 func (l *ZapLogger) Debug(msg string, fields ...zap.Field) {
@@ -196,7 +205,7 @@ func NewLogger(options ...OptionLogger) (*ZapLogger, error) {
 		log = log.With(zap.String("build.version", build.Version), zap.String("build.commit", build.Commit))
 	}
 
-	return &ZapLogger{log}, nil
+	return &ZapLogger{Logger: log, level: &level}, nil
 }
 
 func MustNewLogger(logFormat, logLevel, logTimestampFormat string) *ZapLogger {
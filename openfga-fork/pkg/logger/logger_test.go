@@ -2,6 +2,7 @@ package logger
 
 import (
 	"context"
+	"os"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -33,7 +34,7 @@ func TestWithoutContext(t *testing.T) {
 		},
 	} {
 		observerLogger, logs := observer.New(zap.DebugLevel)
-		dut := ZapLogger{zap.New(observerLogger)}
+		dut := ZapLogger{Logger: zap.New(observerLogger)}
 		const testMessage = "ABC"
 		switch tc.name {
 		case "Info":
@@ -82,7 +83,7 @@ func TestWithContext(t *testing.T) {
 	} {
 		t.Run(tc.name, func(t *testing.T) {
 			observerLogger, logs := observer.New(zap.DebugLevel)
-			dut := ZapLogger{zap.New(observerLogger)}
+			dut := ZapLogger{Logger: zap.New(observerLogger)}
 			const testMessage = "ABC"
 			switch tc.name {
 			case "InfoWithContext":
@@ -110,7 +111,7 @@ func TestWithContext(t *testing.T) {
 
 func TestWithFields(t *testing.T) {
 	observerLogger, logs := observer.New(zap.DebugLevel)
-	logger := ZapLogger{zap.New(observerLogger)}
+	logger := ZapLogger{Logger: zap.New(observerLogger)}
 
 	const testMessage = "ABC"
 
@@ -132,3 +133,21 @@ func TestWithFields(t *testing.T) {
 	parentMessage := logs.All()[1]
 	require.Empty(t, parentMessage.ContextMap())
 }
+
+func TestAtomicLevel(t *testing.T) {
+	logger, err := NewLogger(WithLevel("info"), WithOutputPaths(os.DevNull))
+	require.NoError(t, err)
+
+	level := logger.AtomicLevel()
+	require.NotNil(t, level)
+	require.False(t, logger.Core().Enabled(zap.DebugLevel))
+
+	// The level is shared with the child loggers and can be changed at runtime.
+	child := logger.With(zap.String("key", "value")).(*ZapLogger)
+	level.SetLevel(zap.DebugLevel)
+	require.True(t, logger.Core().Enabled(zap.DebugLevel))
+	require.True(t, child.Core().Enabled(zap.DebugLevel))
+	require.Equal(t, level, child.AtomicLevel())
+
+	require.Nil(t, NewNoopLogger().AtomicLevel())
+}
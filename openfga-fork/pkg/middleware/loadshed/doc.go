@@ -0,0 +1,3 @@
+// Package loadshed contains middleware that assigns a priority class to each request from its API method and
+// client, and queues or sheds the requests of the lowest classes first when the server is overloaded.
+package loadshed
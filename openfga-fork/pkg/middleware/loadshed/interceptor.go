@@ -0,0 +1,61 @@
+package loadshed
+
+import (
+	"context"
+	"path"
+	"strings"
+
+	"google.golang.org/grpc"
+
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+
+	"github.com/openfga/openfga/internal/utils/apimethod"
+)
+
+// NewUnaryInterceptor creates a grpc.UnaryServerInterceptor that queues or sheds the requests of the OpenFGA
+// service over the limit of their class. It must come after the authentication interceptor.
+func NewUnaryInterceptor(shedder *Shedder) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if !isOpenFGAMethod(info.FullMethod) {
+			return handler(ctx, req)
+		}
+
+		release, err := shedder.Admit(ctx, methodName(info.FullMethod))
+		if err != nil {
+			return nil, err
+		}
+		defer release()
+
+		return handler(ctx, req)
+	}
+}
+
+// NewStreamingInterceptor creates a grpc.StreamServerInterceptor that queues or sheds the streams of the
+// OpenFGA service over the limit of their class. It must come after the authentication interceptor.
+func NewStreamingInterceptor(shedder *Shedder) grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if !isOpenFGAMethod(info.FullMethod) {
+			return handler(srv, stream)
+		}
+
+		release, err := shedder.Admit(stream.Context(), methodName(info.FullMethod))
+		if err != nil {
+			return err
+		}
+		defer release()
+
+		return handler(srv, stream)
+	}
+}
+
+// isOpenFGAMethod reports whether fullMethod belongs to the OpenFGA service, or to the services of the
+// openfga.v1 package that serve the RPCs it does not have, such as StreamedListUsers, so that health
+// checks and reflection are never shed.
+func isOpenFGAMethod(fullMethod string) bool {
+	return strings.HasPrefix(fullMethod, "/"+string(openfgav1.File_openfga_v1_openfga_service_proto.Package())+".")
+}
+
+// methodName returns the API method of a full gRPC method name, e.g. Check for /openfga.v1.OpenFGAService/Check.
+func methodName(fullMethod string) apimethod.APIMethod {
+	return apimethod.APIMethod(path.Base(fullMethod))
+}
@@ -0,0 +1,71 @@
+package loadshed
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestUnaryInterceptor(t *testing.T) {
+	shedder := NewShedder(WithMaxInFlight(ClassDefault, 1))
+	t.Cleanup(shedder.Close)
+
+	interceptor := NewUnaryInterceptor(shedder)
+	info := &grpc.UnaryServerInfo{FullMethod: "/openfga.v1.OpenFGAService/Check"}
+
+	var nested error
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		// The request is in flight until the handler returns.
+		_, nested = interceptor(ctx, req, info, func(context.Context, interface{}) (interface{}, error) {
+			return "ok", nil
+		})
+		return "ok", nil
+	}
+
+	res, err := interceptor(context.Background(), nil, info, handler)
+	require.NoError(t, err)
+	require.Equal(t, "ok", res)
+	require.Equal(t, codes.Unavailable, status.Code(nested))
+
+	// Methods outside the OpenFGA service, like health checks, are never shed.
+	healthInfo := &grpc.UnaryServerInfo{FullMethod: "/grpc.health.v1.Health/Check"}
+	_, err = interceptor(context.Background(), nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return interceptor(ctx, req, healthInfo, func(context.Context, interface{}) (interface{}, error) {
+			return "ok", nil
+		})
+	})
+	require.NoError(t, err)
+}
+
+type mockServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *mockServerStream) Context() context.Context {
+	return s.ctx
+}
+
+func TestStreamingInterceptor(t *testing.T) {
+	shedder := NewShedder(WithMaxInFlight(ClassDefault, 1))
+	t.Cleanup(shedder.Close)
+
+	interceptor := NewStreamingInterceptor(shedder)
+	info := &grpc.StreamServerInfo{FullMethod: "/openfga.v1.OpenFGAService/StreamedListObjects"}
+	stream := &mockServerStream{ctx: context.Background()}
+
+	var nested error
+	handler := func(srv interface{}, stream grpc.ServerStream) error {
+		nested = interceptor(srv, stream, info, func(interface{}, grpc.ServerStream) error {
+			return nil
+		})
+		return nil
+	}
+
+	require.NoError(t, interceptor(nil, stream, info, handler))
+	require.Equal(t, codes.Unavailable, status.Code(nested))
+}
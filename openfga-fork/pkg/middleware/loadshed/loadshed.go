@@ -0,0 +1,284 @@
+package loadshed
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"github.com/openfga/openfga/internal/build"
+	"github.com/openfga/openfga/internal/throttler"
+	"github.com/openfga/openfga/internal/utils/apimethod"
+	"github.com/openfga/openfga/pkg/authclaims"
+	"github.com/openfga/openfga/pkg/gateway"
+)
+
+const (
+	// RetryAfterHeader is the response header set to the number of seconds to wait before a shed request
+	// can be retried.
+	RetryAfterHeader = "Retry-After"
+
+	throttlerName = "load_shedding"
+
+	resultAdmitted = "admitted"
+	resultQueued   = "queued"
+	resultShed     = "shed"
+)
+
+var (
+	loadSheddingRequestCount = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: build.ProjectName,
+		Name:      "load_shedding_request_count",
+		Help:      "The total number of requests seen by the load shedder, labeled by method, priority class and result ('admitted', 'queued' or 'shed').",
+	}, []string{"grpc_method", "class", "result"})
+
+	loadSheddingInFlightGauge = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: build.ProjectName,
+		Name:      "load_shedding_in_flight",
+		Help:      "The number of requests admitted by the load shedder that are in flight.",
+	})
+
+	loadSheddingQueueDelayMsHistogram = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace:                       build.ProjectName,
+		Name:                            "load_shedding_queue_delay_ms",
+		Help:                            "Time spent queued by the requests that were admitted after waiting",
+		Buckets:                         []float64{1, 3, 5, 10, 25, 50, 100, 1000, 5000}, // Milliseconds. Upper bound is config.UpstreamTimeout.
+		NativeHistogramBucketFactor:     1.1,
+		NativeHistogramMaxBucketNumber:  100,
+		NativeHistogramMinResetDuration: time.Hour,
+	}, []string{"grpc_method", "class"})
+)
+
+// Class is the priority class of a request. Higher classes are admitted first.
+type Class int
+
+const (
+	// ClassBackground is for the requests of batch jobs, which can wait or be retried later.
+	ClassBackground Class = iota
+	// ClassDefault is for the requests that have no class of their own.
+	ClassDefault
+	// ClassInteractive is for the requests a user is waiting for. They are never shed because of the
+	// datastore latency.
+	ClassInteractive
+
+	numClasses = int(ClassInteractive) + 1
+)
+
+var classNames = [numClasses]string{"background", "default", "interactive"}
+
+func (c Class) String() string {
+	return classNames[c]
+}
+
+// ParseClass returns the Class named name: "background", "default" or "interactive".
+func ParseClass(name string) (Class, error) {
+	for c, className := range classNames {
+		if className == name {
+			return Class(c), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown priority class '%s'", name)
+}
+
+// Shedder admits the requests of each class while the number of in-flight requests is under the limit of
+// the class. The requests over their limit are queued until they can be admitted or the queue timeout
+// elapses, in which case they are shed with an UNAVAILABLE error. Queued requests are released at the pace of
+// a constant rate throttler, the same way dispatch throttling releases throttled dispatches.
+//
+// When the datastore latency is over the latency threshold, the requests of the classes below
+// ClassInteractive are handled as if they were over their limit.
+type Shedder struct {
+	methodClasses map[apimethod.APIMethod]Class
+	clientClasses map[string]Class
+	defaultClass  Class
+	maxInFlight   [numClasses]int64
+
+	latency          func() time.Duration
+	latencyThreshold time.Duration
+
+	queueTimeout   time.Duration
+	queueFrequency time.Duration
+	queue          throttler.Throttler
+	retryAfter     time.Duration
+	transport      gateway.Transport
+
+	inFlight atomic.Int64
+}
+
+type ShedderOption func(*Shedder)
+
+// WithMethodClasses sets the class of some API methods.
+func WithMethodClasses(classes map[apimethod.APIMethod]Class) ShedderOption {
+	return func(s *Shedder) {
+		s.methodClasses = classes
+	}
+}
+
+// WithClientClasses sets the class of the requests of some clients, identified by the client ID or the
+// subject of their AuthClaims. It takes precedence over the class of the method.
+func WithClientClasses(classes map[string]Class) ShedderOption {
+	return func(s *Shedder) {
+		s.clientClasses = classes
+	}
+}
+
+// WithDefaultClass sets the class of the requests whose method and client have no class. The default is
+// ClassDefault.
+func WithDefaultClass(class Class) ShedderOption {
+	return func(s *Shedder) {
+		s.defaultClass = class
+	}
+}
+
+// WithMaxInFlight sets the number of in-flight requests from which the requests of class are queued.
+// Zero, the default, means no limit.
+func WithMaxInFlight(class Class, maxInFlight int) ShedderOption {
+	return func(s *Shedder) {
+		s.maxInFlight[class] = int64(maxInFlight)
+	}
+}
+
+// WithDatastoreLatency sets the source of the datastore latency, and the latency over which the requests
+// below ClassInteractive are queued or shed. latency must decay when there are no reads, as
+// storagewrappers.AdaptiveLimiter.Latency does, since shedding those requests may stop every read that would
+// lower it.
+func WithDatastoreLatency(latency func() time.Duration, threshold time.Duration) ShedderOption {
+	return func(s *Shedder) {
+		s.latency = latency
+		s.latencyThreshold = threshold
+	}
+}
+
+// WithQueue sets how long the requests over their limit wait to be admitted before being shed, and how often
+// one of them is let through to try again. A zero timeout, the default, sheds them right away.
+func WithQueue(timeout, frequency time.Duration) ShedderOption {
+	return func(s *Shedder) {
+		s.queueTimeout = timeout
+		s.queueFrequency = frequency
+	}
+}
+
+// WithRetryAfter sets the delay after which the clients of shed requests are told to retry. The default is
+// one second.
+func WithRetryAfter(retryAfter time.Duration) ShedderOption {
+	return func(s *Shedder) {
+		s.retryAfter = retryAfter
+	}
+}
+
+// WithTransport sets the transport used to set the Retry-After header of shed requests.
+func WithTransport(transport gateway.Transport) ShedderOption {
+	return func(s *Shedder) {
+		s.transport = transport
+	}
+}
+
+// NewShedder returns a Shedder. It must be closed to stop its queue.
+func NewShedder(opts ...ShedderOption) *Shedder {
+	s := &Shedder{
+		defaultClass: ClassDefault,
+		retryAfter:   time.Second,
+		transport:    gateway.NewNoopTransport(),
+		queue:        throttler.NewNoopThrottler(),
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	if s.queueTimeout > 0 {
+		s.queue = throttler.NewConstantRateThrottler(s.queueFrequency, throttlerName)
+	}
+
+	return s
+}
+
+// Close stops the queue of the Shedder.
+func (s *Shedder) Close() {
+	s.queue.Close()
+}
+
+// Class returns the class of a request of the client in ctx to method.
+func (s *Shedder) Class(ctx context.Context, method apimethod.APIMethod) Class {
+	if class, ok := s.clientClasses[authclaims.ClientIDFromContext(ctx)]; ok {
+		return class
+	}
+	if class, ok := s.methodClasses[method]; ok {
+		return class
+	}
+	return s.defaultClass
+}
+
+// Admit admits the request of the client in ctx to method, after queueing it if it is over the limit of its
+// class. The returned function must be called when the request completes. If the request cannot be
+// admitted, it sets the Retry-After header and returns an UNAVAILABLE error carrying a RetryInfo detail.
+func (s *Shedder) Admit(ctx context.Context, method apimethod.APIMethod) (func(), error) {
+	class := s.Class(ctx, method)
+
+	if s.tryAdmit(class) {
+		loadSheddingRequestCount.WithLabelValues(method.String(), class.String(), resultAdmitted).Inc()
+		return s.release, nil
+	}
+
+	if s.queueTimeout > 0 {
+		start := time.Now()
+		queueCtx, cancel := context.WithTimeout(ctx, s.queueTimeout)
+		defer cancel()
+
+		for queueCtx.Err() == nil {
+			s.queue.Throttle(queueCtx)
+			if s.tryAdmit(class) {
+				loadSheddingRequestCount.WithLabelValues(method.String(), class.String(), resultQueued).Inc()
+				loadSheddingQueueDelayMsHistogram.WithLabelValues(method.String(), class.String()).Observe(float64(time.Since(start).Milliseconds()))
+				return s.release, nil
+			}
+		}
+
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	loadSheddingRequestCount.WithLabelValues(method.String(), class.String(), resultShed).Inc()
+
+	seconds := max(1, int(math.Ceil(s.retryAfter.Seconds())))
+	s.transport.SetHeader(ctx, RetryAfterHeader, strconv.Itoa(seconds))
+
+	st := status.Newf(codes.Unavailable, "server overloaded, %s request of priority class '%s' shed, retry after %d seconds", method, class, seconds)
+	if detailed, err := st.WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(s.retryAfter)}); err == nil {
+		st = detailed
+	}
+	return nil, st.Err()
+}
+
+// tryAdmit counts a request of class as in flight if it is under the limit of class.
+func (s *Shedder) tryAdmit(class Class) bool {
+	if class < ClassInteractive && s.latency != nil && s.latency() > s.latencyThreshold {
+		return false
+	}
+
+	limit := s.maxInFlight[class]
+	for {
+		inFlight := s.inFlight.Load()
+		if limit > 0 && inFlight >= limit {
+			return false
+		}
+		if s.inFlight.CompareAndSwap(inFlight, inFlight+1) {
+			loadSheddingInFlightGauge.Set(float64(inFlight + 1))
+			return true
+		}
+	}
+}
+
+func (s *Shedder) release() {
+	loadSheddingInFlightGauge.Set(float64(s.inFlight.Add(-1)))
+}
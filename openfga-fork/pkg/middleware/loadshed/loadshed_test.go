@@ -0,0 +1,175 @@
+package loadshed
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/openfga/openfga/internal/utils/apimethod"
+	"github.com/openfga/openfga/pkg/authclaims"
+)
+
+type recordingTransport struct {
+	headers map[string]string
+}
+
+func (r *recordingTransport) SetHeader(_ context.Context, key, value string) {
+	r.headers[key] = value
+}
+
+func clientContext(clientID, subject string) context.Context {
+	return authclaims.ContextWithAuthClaims(context.Background(), &authclaims.AuthClaims{ClientID: clientID, Subject: subject})
+}
+
+func TestParseClass(t *testing.T) {
+	for _, class := range []Class{ClassBackground, ClassDefault, ClassInteractive} {
+		parsed, err := ParseClass(class.String())
+		require.NoError(t, err)
+		require.Equal(t, class, parsed)
+	}
+
+	_, err := ParseClass("urgent")
+	require.ErrorContains(t, err, "unknown priority class 'urgent'")
+}
+
+func TestShedderClass(t *testing.T) {
+	shedder := NewShedder(
+		WithMethodClasses(map[apimethod.APIMethod]Class{
+			apimethod.Check:       ClassInteractive,
+			apimethod.ReadChanges: ClassBackground,
+		}),
+		WithClientClasses(map[string]Class{
+			"batch": ClassBackground,
+			"anne":  ClassInteractive,
+		}),
+	)
+	t.Cleanup(shedder.Close)
+
+	require.Equal(t, ClassInteractive, shedder.Class(context.Background(), apimethod.Check))
+	require.Equal(t, ClassBackground, shedder.Class(context.Background(), apimethod.ReadChanges))
+	require.Equal(t, ClassDefault, shedder.Class(context.Background(), apimethod.ListObjects))
+
+	// Client classes take precedence over method classes.
+	require.Equal(t, ClassBackground, shedder.Class(clientContext("batch", ""), apimethod.Check))
+	require.Equal(t, ClassInteractive, shedder.Class(clientContext("", "anne"), apimethod.ReadChanges))
+}
+
+func TestShedderAdmit(t *testing.T) {
+	t.Run("sheds_requests_over_the_limit_of_their_class", func(t *testing.T) {
+		transport := &recordingTransport{headers: map[string]string{}}
+		shedder := NewShedder(
+			WithMethodClasses(map[apimethod.APIMethod]Class{
+				apimethod.Check:       ClassInteractive,
+				apimethod.ReadChanges: ClassBackground,
+			}),
+			WithMaxInFlight(ClassInteractive, 2),
+			WithMaxInFlight(ClassBackground, 1),
+			WithRetryAfter(2*time.Second),
+			WithTransport(transport),
+		)
+		t.Cleanup(shedder.Close)
+
+		releaseBackground, err := shedder.Admit(context.Background(), apimethod.ReadChanges)
+		require.NoError(t, err)
+
+		_, err = shedder.Admit(context.Background(), apimethod.ReadChanges)
+		require.Equal(t, codes.Unavailable, status.Code(err))
+		require.Equal(t, "2", transport.headers[RetryAfterHeader])
+
+		var retryInfo *errdetails.RetryInfo
+		for _, detail := range status.Convert(err).Details() {
+			if info, ok := detail.(*errdetails.RetryInfo); ok {
+				retryInfo = info
+			}
+		}
+		require.NotNil(t, retryInfo)
+		require.Equal(t, 2*time.Second, retryInfo.GetRetryDelay().AsDuration())
+
+		// Interactive requests have a higher limit.
+		releaseInteractive, err := shedder.Admit(context.Background(), apimethod.Check)
+		require.NoError(t, err)
+		_, err = shedder.Admit(context.Background(), apimethod.Check)
+		require.Equal(t, codes.Unavailable, status.Code(err))
+
+		releaseInteractive()
+		releaseBackground()
+
+		release, err := shedder.Admit(context.Background(), apimethod.ReadChanges)
+		require.NoError(t, err)
+		release()
+	})
+
+	t.Run("queued_requests_are_admitted_when_a_request_completes", func(t *testing.T) {
+		shedder := NewShedder(WithMaxInFlight(ClassDefault, 1), WithQueue(time.Second, time.Millisecond))
+		t.Cleanup(shedder.Close)
+
+		release, err := shedder.Admit(context.Background(), apimethod.Check)
+		require.NoError(t, err)
+
+		admitted := make(chan error)
+		go func() {
+			queuedRelease, err := shedder.Admit(context.Background(), apimethod.Check)
+			if err == nil {
+				defer queuedRelease()
+			}
+			admitted <- err
+		}()
+
+		time.Sleep(10 * time.Millisecond)
+		release()
+		require.NoError(t, <-admitted)
+	})
+
+	t.Run("queued_requests_are_shed_after_the_queue_timeout", func(t *testing.T) {
+		shedder := NewShedder(WithMaxInFlight(ClassDefault, 1), WithQueue(10*time.Millisecond, time.Millisecond))
+		t.Cleanup(shedder.Close)
+
+		release, err := shedder.Admit(context.Background(), apimethod.Check)
+		require.NoError(t, err)
+		defer release()
+
+		_, err = shedder.Admit(context.Background(), apimethod.Check)
+		require.Equal(t, codes.Unavailable, status.Code(err))
+	})
+
+	t.Run("queued_requests_return_when_canceled", func(t *testing.T) {
+		shedder := NewShedder(WithMaxInFlight(ClassDefault, 1), WithQueue(time.Minute, time.Millisecond))
+		t.Cleanup(shedder.Close)
+
+		release, err := shedder.Admit(context.Background(), apimethod.Check)
+		require.NoError(t, err)
+		defer release()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		_, err = shedder.Admit(ctx, apimethod.Check)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("sheds_non_interactive_requests_over_the_latency_threshold", func(t *testing.T) {
+		latency := time.Millisecond
+		shedder := NewShedder(
+			WithMethodClasses(map[apimethod.APIMethod]Class{apimethod.Check: ClassInteractive}),
+			WithDatastoreLatency(func() time.Duration { return latency }, 50*time.Millisecond),
+		)
+		t.Cleanup(shedder.Close)
+
+		release, err := shedder.Admit(context.Background(), apimethod.ListObjects)
+		require.NoError(t, err)
+		release()
+
+		latency = 100 * time.Millisecond
+
+		_, err = shedder.Admit(context.Background(), apimethod.ListObjects)
+		require.Equal(t, codes.Unavailable, status.Code(err))
+
+		release, err = shedder.Admit(context.Background(), apimethod.Check)
+		require.NoError(t, err)
+		release()
+	})
+}
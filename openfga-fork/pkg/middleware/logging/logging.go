@@ -93,8 +93,7 @@ func (r *reporter) PostCall(err error, rpcDuration time.Duration) {
 func (r *reporter) PostMsgSend(msg interface{}, err error, _ time.Duration) {
 	if err != nil {
 		// This is the actual error that customers see.
-		intCode := serverErrors.ConvertToEncodedErrorCode(status.Convert(err))
-		encodedError := serverErrors.NewEncodedError(intCode, err.Error())
+		encodedError := serverErrors.EncodeError(err)
 		protomsg := encodedError.ActualError
 		if resp, err := json.Marshal(protomsg); err == nil {
 			r.fields = append(r.fields, zap.Any(rawResponseKey, json.RawMessage(resp)))
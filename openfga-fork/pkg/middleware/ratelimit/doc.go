@@ -0,0 +1,3 @@
+// Package ratelimit contains middleware that limits the rate of the requests of each client to each store
+// and API method with token buckets.
+package ratelimit
@@ -0,0 +1,71 @@
+package ratelimit
+
+import (
+	"context"
+	"path"
+
+	"google.golang.org/grpc"
+
+	"github.com/openfga/openfga/internal/utils/apimethod"
+)
+
+type hasGetStoreID interface {
+	GetStoreId() string
+}
+
+// NewUnaryInterceptor creates a grpc.UnaryServerInterceptor that rejects the requests over the limit of
+// their client, store and method. It must come after the authentication interceptor.
+func NewUnaryInterceptor(limiter *Limiter) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if err := limiter.Allow(ctx, storeID(req), methodName(info.FullMethod)); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// NewStreamingInterceptor creates a grpc.StreamServerInterceptor that rejects the streams over the limit of
+// their client, store and method when their first message is received. It must come after the authentication
+// interceptor.
+func NewStreamingInterceptor(limiter *Limiter) grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, &limitedStream{
+			ServerStream: stream,
+			limiter:      limiter,
+			method:       methodName(info.FullMethod),
+		})
+	}
+}
+
+type limitedStream struct {
+	grpc.ServerStream
+	limiter *Limiter
+	method  apimethod.APIMethod
+	checked bool
+}
+
+// RecvMsg receives a message and, for the first one, takes a token for the stream.
+func (s *limitedStream) RecvMsg(m interface{}) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+
+	if s.checked {
+		return nil
+	}
+	s.checked = true
+
+	return s.limiter.Allow(s.Context(), storeID(m), s.method)
+}
+
+func storeID(req interface{}) string {
+	if r, ok := req.(hasGetStoreID); ok {
+		return r.GetStoreId()
+	}
+	return ""
+}
+
+// methodName returns the API method of a full gRPC method name, e.g. Check for /openfga.v1.OpenFGAService/Check.
+func methodName(fullMethod string) apimethod.APIMethod {
+	return apimethod.APIMethod(path.Base(fullMethod))
+}
@@ -0,0 +1,59 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+)
+
+func TestUnaryInterceptor(t *testing.T) {
+	interceptor := NewUnaryInterceptor(NewLimiter(Limit{RequestsPerSecond: 1, Burst: 1}))
+	info := &grpc.UnaryServerInfo{FullMethod: "/openfga.v1.OpenFGAService/Check"}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "ok", nil
+	}
+
+	res, err := interceptor(context.Background(), &openfgav1.CheckRequest{StoreId: "store"}, info, handler)
+	require.NoError(t, err)
+	require.Equal(t, "ok", res)
+
+	_, err = interceptor(context.Background(), &openfgav1.CheckRequest{StoreId: "store"}, info, handler)
+	require.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+	_, err = interceptor(context.Background(), &openfgav1.CheckRequest{StoreId: "other"}, info, handler)
+	require.NoError(t, err)
+}
+
+type mockServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *mockServerStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *mockServerStream) RecvMsg(m interface{}) error {
+	m.(*openfgav1.StreamedListObjectsRequest).StoreId = "store"
+	return nil
+}
+
+func TestStreamingInterceptor(t *testing.T) {
+	interceptor := NewStreamingInterceptor(NewLimiter(Limit{RequestsPerSecond: 1, Burst: 1}))
+	info := &grpc.StreamServerInfo{FullMethod: "/openfga.v1.OpenFGAService/StreamedListObjects"}
+	handler := func(srv interface{}, stream grpc.ServerStream) error {
+		return stream.RecvMsg(&openfgav1.StreamedListObjectsRequest{})
+	}
+
+	stream := &mockServerStream{ctx: context.Background()}
+	require.NoError(t, interceptor(nil, stream, info, handler))
+
+	err := interceptor(nil, stream, info, handler)
+	require.Equal(t, codes.ResourceExhausted, status.Code(err))
+}
@@ -0,0 +1,80 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// sweepInterval is how often the buckets that are full again are dropped from a MemoryBackend.
+const sweepInterval = time.Minute
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+	limit  Limit
+}
+
+// tokensAt returns the tokens of the bucket at now, including the ones accumulated since the last refill.
+func (b *bucket) tokensAt(now time.Time) float64 {
+	elapsed := now.Sub(b.last).Seconds()
+	return min(float64(b.limit.Burst), b.tokens+elapsed*b.limit.RequestsPerSecond)
+}
+
+// MemoryBackend stores the token buckets in memory. Buckets that are full again are dropped, since a new
+// bucket starts full.
+type MemoryBackend struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+var _ Backend = (*MemoryBackend)(nil)
+
+// NewMemoryBackend returns an empty MemoryBackend.
+func NewMemoryBackend() *MemoryBackend {
+	return &MemoryBackend{
+		buckets:   map[string]*bucket{},
+		lastSweep: time.Now(),
+		now:       time.Now,
+	}
+}
+
+// Take see [Backend.Take].
+func (m *MemoryBackend) Take(_ context.Context, key string, limit Limit) (bool, time.Duration, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	if now.Sub(m.lastSweep) >= sweepInterval {
+		m.sweep(now)
+	}
+
+	b, ok := m.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(limit.Burst), last: now}
+		m.buckets[key] = b
+	}
+	b.limit = limit
+	b.tokens = b.tokensAt(now)
+	b.last = now
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0, nil
+	}
+
+	wait := (1 - b.tokens) / limit.RequestsPerSecond
+	return false, time.Duration(wait * float64(time.Second)), nil
+}
+
+// sweep drops the buckets that are full.
+func (m *MemoryBackend) sweep(now time.Time) {
+	for key, b := range m.buckets {
+		if b.tokensAt(now) >= float64(b.limit.Burst) {
+			delete(m.buckets, key)
+		}
+	}
+	m.lastSweep = now
+}
@@ -0,0 +1,39 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryBackend(t *testing.T) {
+	now := time.Now()
+	backend := NewMemoryBackend()
+	backend.now = func() time.Time { return now }
+	limit := Limit{RequestsPerSecond: 2, Burst: 2}
+
+	for range 2 {
+		allowed, _, err := backend.Take(context.Background(), "key", limit)
+		require.NoError(t, err)
+		require.True(t, allowed)
+	}
+
+	allowed, retryAfter, err := backend.Take(context.Background(), "key", limit)
+	require.NoError(t, err)
+	require.False(t, allowed)
+	require.Equal(t, 500*time.Millisecond, retryAfter)
+
+	now = now.Add(500 * time.Millisecond)
+	allowed, _, err = backend.Take(context.Background(), "key", limit)
+	require.NoError(t, err)
+	require.True(t, allowed)
+
+	// Full buckets are dropped by the next sweep.
+	now = now.Add(sweepInterval)
+	_, _, err = backend.Take(context.Background(), "other", limit)
+	require.NoError(t, err)
+	require.NotContains(t, backend.buckets, "key")
+	require.Contains(t, backend.buckets, "other")
+}
@@ -0,0 +1,174 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.uber.org/zap"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"github.com/openfga/openfga/internal/build"
+	"github.com/openfga/openfga/internal/utils/apimethod"
+	"github.com/openfga/openfga/pkg/authclaims"
+	"github.com/openfga/openfga/pkg/gateway"
+	"github.com/openfga/openfga/pkg/logger"
+)
+
+const (
+	// RetryAfterHeader is the response header set to the number of seconds to wait before the request
+	// can be retried when it is rejected.
+	RetryAfterHeader = "Retry-After"
+
+	// anonymousClient is the client of the requests that do not carry a client ID or a subject.
+	anonymousClient = "anonymous"
+
+	resultAllowed = "allowed"
+	resultLimited = "limited"
+	resultError   = "error"
+)
+
+var rateLimitRequestCount = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: build.ProjectName,
+	Name:      "rate_limit_request_count",
+	Help:      "The total number of requests checked by the rate limiter, labeled by method and result ('allowed', 'limited' or 'error').",
+}, []string{"grpc_method", "result"})
+
+// Limit is the rate at which the tokens of a bucket are refilled, and the number of tokens the bucket holds.
+// Each request takes one token. A zero rate means no limit.
+type Limit struct {
+	RequestsPerSecond float64
+	Burst             int
+}
+
+// IsUnlimited reports whether the limit lets all the requests through.
+func (l Limit) IsUnlimited() bool {
+	return l.RequestsPerSecond <= 0
+}
+
+// Backend stores the token buckets.
+type Backend interface {
+	// Take takes a token from the bucket of key, created full with limit if it does not exist. When the
+	// bucket is empty, it returns false and the time after which a token is available.
+	Take(ctx context.Context, key string, limit Limit) (bool, time.Duration, error)
+}
+
+// Limiter limits the rate of the requests of each client, identified by the client ID or the subject of
+// its AuthClaims, to each store and API method.
+type Limiter struct {
+	defaultLimit Limit
+	methodLimits map[apimethod.APIMethod]Limit
+	backend      Backend
+	transport    gateway.Transport
+	logger       logger.Logger
+}
+
+type LimiterOption func(*Limiter)
+
+// WithMethodLimits overrides the default limit of some API methods.
+func WithMethodLimits(limits map[apimethod.APIMethod]Limit) LimiterOption {
+	return func(l *Limiter) {
+		l.methodLimits = limits
+	}
+}
+
+// WithBackend sets where the token buckets are stored. The default is a [MemoryBackend], which limits
+// the requests served by this process only.
+func WithBackend(backend Backend) LimiterOption {
+	return func(l *Limiter) {
+		l.backend = backend
+	}
+}
+
+// WithTransport sets the transport used to set the Retry-After header of rejected requests.
+func WithTransport(transport gateway.Transport) LimiterOption {
+	return func(l *Limiter) {
+		l.transport = transport
+	}
+}
+
+// WithLogger sets the logger of the errors of the backend.
+func WithLogger(logger logger.Logger) LimiterOption {
+	return func(l *Limiter) {
+		l.logger = logger
+	}
+}
+
+// NewLimiter returns a Limiter that applies defaultLimit to the API methods without their own limit.
+func NewLimiter(defaultLimit Limit, opts ...LimiterOption) *Limiter {
+	l := &Limiter{
+		defaultLimit: defaultLimit,
+		transport:    gateway.NewNoopTransport(),
+		logger:       logger.NewNoopLogger(),
+	}
+
+	for _, opt := range opts {
+		opt(l)
+	}
+
+	if l.backend == nil {
+		l.backend = NewMemoryBackend()
+	}
+
+	return l
+}
+
+// Allow takes a token for the request of the client in ctx to the API method on the store. If there is
+// none, it sets the Retry-After header and returns a RESOURCE_EXHAUSTED error. Requests are let through
+// when the backend fails.
+func (l *Limiter) Allow(ctx context.Context, storeID string, method apimethod.APIMethod) error {
+	limit := l.limit(method)
+	if limit.IsUnlimited() {
+		return nil
+	}
+
+	allowed, retryAfter, err := l.backend.Take(ctx, key(clientID(ctx), storeID, method), limit)
+	if err != nil {
+		rateLimitRequestCount.WithLabelValues(method.String(), resultError).Inc()
+		l.logger.WarnWithContext(ctx, "rate limiter failed, request allowed", zap.String("method", method.String()), zap.Error(err))
+		return nil
+	}
+
+	if allowed {
+		rateLimitRequestCount.WithLabelValues(method.String(), resultAllowed).Inc()
+		return nil
+	}
+
+	rateLimitRequestCount.WithLabelValues(method.String(), resultLimited).Inc()
+	seconds := max(1, int(math.Ceil(retryAfter.Seconds())))
+	l.transport.SetHeader(ctx, RetryAfterHeader, strconv.Itoa(seconds))
+	st := status.Newf(codes.ResourceExhausted, "rate limit exceeded for %s, retry after %d seconds", method, seconds)
+	if detailed, err := st.WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(retryAfter)}); err == nil {
+		st = detailed
+	}
+	return st.Err()
+}
+
+func (l *Limiter) limit(method apimethod.APIMethod) Limit {
+	if limit, ok := l.methodLimits[method]; ok {
+		return limit
+	}
+	return l.defaultLimit
+}
+
+// clientID returns the client of the request in ctx, or anonymousClient if the request does not carry a
+// client ID or a subject.
+func clientID(ctx context.Context) string {
+	if client := authclaims.ClientIDFromContext(ctx); client != "" {
+		return client
+	}
+	return anonymousClient
+}
+
+// key returns the key of the bucket of the client, store and method. The parts are separated by a character
+// that is not valid in store IDs and method names, so that keys do not collide.
+func key(client, storeID string, method apimethod.APIMethod) string {
+	return strings.Join([]string{method.String(), storeID, client}, "|")
+}
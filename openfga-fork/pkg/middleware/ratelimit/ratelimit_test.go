@@ -0,0 +1,97 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/openfga/openfga/internal/utils/apimethod"
+	"github.com/openfga/openfga/pkg/authclaims"
+)
+
+type recordingTransport struct {
+	headers map[string]string
+}
+
+func (r *recordingTransport) SetHeader(_ context.Context, key, value string) {
+	r.headers[key] = value
+}
+
+type failingBackend struct{}
+
+func (failingBackend) Take(context.Context, string, Limit) (bool, time.Duration, error) {
+	return false, 0, errors.New("unavailable")
+}
+
+func clientContext(clientID, subject string) context.Context {
+	return authclaims.ContextWithAuthClaims(context.Background(), &authclaims.AuthClaims{ClientID: clientID, Subject: subject})
+}
+
+func TestLimiterAllow(t *testing.T) {
+	t.Run("rejects_requests_over_the_burst", func(t *testing.T) {
+		transport := &recordingTransport{headers: map[string]string{}}
+		limiter := NewLimiter(Limit{RequestsPerSecond: 0.5, Burst: 2}, WithTransport(transport))
+		ctx := clientContext("app", "")
+
+		require.NoError(t, limiter.Allow(ctx, "store", apimethod.Check))
+		require.NoError(t, limiter.Allow(ctx, "store", apimethod.Check))
+
+		err := limiter.Allow(ctx, "store", apimethod.Check)
+		require.Equal(t, codes.ResourceExhausted, status.Code(err))
+		require.Equal(t, "2", transport.headers[RetryAfterHeader])
+	})
+
+	t.Run("buckets_are_per_client_store_and_method", func(t *testing.T) {
+		limiter := NewLimiter(Limit{RequestsPerSecond: 1, Burst: 1})
+
+		require.NoError(t, limiter.Allow(clientContext("app", ""), "store", apimethod.Check))
+		require.Error(t, limiter.Allow(clientContext("app", ""), "store", apimethod.Check))
+
+		require.NoError(t, limiter.Allow(clientContext("other", ""), "store", apimethod.Check))
+		require.NoError(t, limiter.Allow(clientContext("app", ""), "other", apimethod.Check))
+		require.NoError(t, limiter.Allow(clientContext("app", ""), "store", apimethod.ListObjects))
+	})
+
+	t.Run("subject_identifies_clients_without_client_id", func(t *testing.T) {
+		limiter := NewLimiter(Limit{RequestsPerSecond: 1, Burst: 1})
+
+		require.NoError(t, limiter.Allow(clientContext("", "anne"), "store", apimethod.Check))
+		require.Error(t, limiter.Allow(clientContext("", "anne"), "store", apimethod.Check))
+		require.NoError(t, limiter.Allow(clientContext("", "bob"), "store", apimethod.Check))
+	})
+
+	t.Run("anonymous_clients_share_a_bucket", func(t *testing.T) {
+		limiter := NewLimiter(Limit{RequestsPerSecond: 1, Burst: 1})
+
+		require.NoError(t, limiter.Allow(context.Background(), "store", apimethod.Check))
+		require.Error(t, limiter.Allow(clientContext("", ""), "store", apimethod.Check))
+	})
+
+	t.Run("method_limits", func(t *testing.T) {
+		limiter := NewLimiter(Limit{RequestsPerSecond: 1, Burst: 1}, WithMethodLimits(map[apimethod.APIMethod]Limit{
+			apimethod.Check: {RequestsPerSecond: 1, Burst: 3},
+			apimethod.Read:  {},
+		}))
+		ctx := clientContext("app", "")
+
+		for range 3 {
+			require.NoError(t, limiter.Allow(ctx, "store", apimethod.Check))
+		}
+		require.Error(t, limiter.Allow(ctx, "store", apimethod.Check))
+
+		for range 10 {
+			require.NoError(t, limiter.Allow(ctx, "store", apimethod.Read))
+		}
+	})
+
+	t.Run("backend_errors_allow_requests", func(t *testing.T) {
+		limiter := NewLimiter(Limit{RequestsPerSecond: 1, Burst: 1}, WithBackend(failingBackend{}))
+
+		require.NoError(t, limiter.Allow(clientContext("app", ""), "store", apimethod.Check))
+	})
+}
@@ -0,0 +1,93 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/openfga/openfga/pkg/storage/rediscache"
+)
+
+// DefaultRedisKeyPrefix is the prefix of the keys of the token buckets in Redis.
+const DefaultRedisKeyPrefix = "openfga:ratelimit:"
+
+// takeScript takes a token from the bucket stored in the hash KEYS[1], refilled at ARGV[1] tokens per
+// second up to ARGV[2] tokens. The server clock is used so that all the replicas agree on the time. It
+// returns whether a token was taken and, if not, the microseconds after which one is available. The hash
+// expires once the bucket is full again, since a new bucket starts full.
+const takeScript = `
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local clock = redis.call('TIME')
+local now = tonumber(clock[1]) * 1000000 + tonumber(clock[2])
+
+local bucket = redis.call('HMGET', KEYS[1], 'tokens', 'last')
+local tokens = tonumber(bucket[1])
+local last = tonumber(bucket[2])
+if tokens == nil or last == nil then
+  tokens = burst
+  last = now
+end
+tokens = math.min(burst, tokens + math.max(0, now - last) * rate / 1000000)
+
+local allowed = 0
+local wait = 0
+if tokens >= 1 then
+  tokens = tokens - 1
+  allowed = 1
+else
+  wait = math.ceil((1 - tokens) * 1000000 / rate)
+end
+
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'last', tostring(now))
+redis.call('PEXPIRE', KEYS[1], math.ceil((burst - tokens) * 1000 / rate) + 1000)
+return {allowed, wait}
+`
+
+// RedisBackend stores the token buckets in a Redis-compatible server, so that the limits hold across the
+// replicas of the server. The server must support Lua scripting.
+type RedisBackend struct {
+	client    *rediscache.Client
+	keyPrefix string
+}
+
+var _ Backend = (*RedisBackend)(nil)
+
+// NewRedisBackend returns a RedisBackend that stores the buckets under keyPrefix.
+func NewRedisBackend(client *rediscache.Client, keyPrefix string) *RedisBackend {
+	return &RedisBackend{client: client, keyPrefix: keyPrefix}
+}
+
+// Take see [Backend.Take].
+func (r *RedisBackend) Take(ctx context.Context, key string, limit Limit) (bool, time.Duration, error) {
+	reply, err := r.client.Do(ctx,
+		"EVAL", takeScript, "1", r.keyPrefix+key,
+		strconv.FormatFloat(limit.RequestsPerSecond, 'f', -1, 64),
+		strconv.Itoa(limit.Burst),
+	)
+	if err != nil {
+		return false, 0, err
+	}
+
+	return parseTakeReply(reply)
+}
+
+func parseTakeReply(reply any) (bool, time.Duration, error) {
+	values, ok := reply.([]any)
+	if !ok || len(values) != 2 {
+		return false, 0, fmt.Errorf("unexpected rate limit script reply %v", reply)
+	}
+
+	allowed, ok := values[0].(int64)
+	if !ok {
+		return false, 0, fmt.Errorf("unexpected rate limit script reply %v", reply)
+	}
+
+	wait, ok := values[1].(int64)
+	if !ok {
+		return false, 0, fmt.Errorf("unexpected rate limit script reply %v", reply)
+	}
+
+	return allowed == 1, time.Duration(wait) * time.Microsecond, nil
+}
@@ -0,0 +1,71 @@
+package ratelimit
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/openfga/openfga/pkg/storage/rediscache"
+)
+
+func TestParseTakeReply(t *testing.T) {
+	allowed, retryAfter, err := parseTakeReply([]any{int64(1), int64(0)})
+	require.NoError(t, err)
+	require.True(t, allowed)
+	require.Zero(t, retryAfter)
+
+	allowed, retryAfter, err = parseTakeReply([]any{int64(0), int64(250000)})
+	require.NoError(t, err)
+	require.False(t, allowed)
+	require.Equal(t, 250*time.Millisecond, retryAfter)
+
+	_, _, err = parseTakeReply("OK")
+	require.Error(t, err)
+}
+
+func TestRedisBackendUnavailable(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := listener.Addr().String()
+	require.NoError(t, listener.Close())
+
+	client, err := rediscache.NewClient(addr, "", 0, 50*time.Millisecond)
+	require.NoError(t, err)
+	t.Cleanup(client.Close)
+
+	_, _, err = NewRedisBackend(client, DefaultRedisKeyPrefix).Take(context.Background(), "key", Limit{RequestsPerSecond: 1, Burst: 1})
+	require.Error(t, err)
+}
+
+func TestRedisBackendContextDone(t *testing.T) {
+	// The server accepts connections but never replies.
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	accepted := make(chan net.Conn, 1)
+	go func() {
+		defer close(accepted)
+		if conn, err := listener.Accept(); err == nil {
+			accepted <- conn
+		}
+	}()
+	t.Cleanup(func() {
+		_ = listener.Close()
+		if conn, ok := <-accepted; ok {
+			_ = conn.Close()
+		}
+	})
+
+	client, err := rediscache.NewClient(listener.Addr().String(), "", 0, time.Minute)
+	require.NoError(t, err)
+	t.Cleanup(client.Close)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, _, err = NewRedisBackend(client, DefaultRedisKeyPrefix).Take(ctx, "key", Limit{RequestsPerSecond: 1, Burst: 1})
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.Less(t, time.Since(start), 10*time.Second)
+}
@@ -0,0 +1,2 @@
+// Package storemetrics contains middleware to report the requests, labeled by store and model, to Prometheus.
+package storemetrics
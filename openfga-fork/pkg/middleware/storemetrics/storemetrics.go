@@ -0,0 +1,120 @@
+package storemetrics
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/grpc-ecosystem/go-grpc-middleware/v2/interceptors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+
+	"github.com/openfga/openfga/internal/build"
+	"github.com/openfga/openfga/pkg/telemetry"
+)
+
+var (
+	storeRequestCounter = telemetry.StoreLabeled(promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: build.ProjectName,
+		Name:      "store_request_count",
+		Help:      "The total number of requests labeled by method, gRPC code, store and model. This allows for reporting error rates per store.",
+	}, []string{"grpc_service", "grpc_method", "grpc_code", telemetry.StoreIDLabel, telemetry.ModelIDLabel}))
+
+	storeRequestDurationHistogram = telemetry.StoreLabeled(promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace:                       build.ProjectName,
+		Name:                            "store_request_duration_ms",
+		Help:                            "The request duration (in ms) labeled by method, gRPC code, store and model. This allows for reporting latency percentiles per store.",
+		Buckets:                         []float64{1, 5, 10, 25, 50, 80, 100, 150, 200, 300, 1000, 2000, 5000},
+		NativeHistogramBucketFactor:     1.1,
+		NativeHistogramMaxBucketNumber:  100,
+		NativeHistogramMinResetDuration: time.Hour,
+	}, []string{"grpc_service", "grpc_method", "grpc_code", telemetry.StoreIDLabel, telemetry.ModelIDLabel}))
+)
+
+type hasGetStoreID interface {
+	GetStoreId() string
+}
+
+type hasGetAuthorizationModelID interface {
+	GetAuthorizationModelId() string
+}
+
+// NewUnaryInterceptor creates a grpc.UnaryServerInterceptor which reports the number and duration of the
+// requests labeled by store and model. The store and model labels are bounded by the labeler set with
+// telemetry.SetStoreLabeler. Every request is counted once towards the ranking of its store, and its labels
+// are passed to the handler in the context, see telemetry.StoreLabelFromContext.
+func NewUnaryInterceptor() grpc.UnaryServerInterceptor {
+	return interceptors.UnaryServerInterceptor(reportable())
+}
+
+// NewStreamingInterceptor creates a grpc.StreamServerInterceptor which reports the number and duration of the
+// streams labeled by the store and model of their first message.
+func NewStreamingInterceptor() grpc.StreamServerInterceptor {
+	return interceptors.StreamServerInterceptor(reportable())
+}
+
+type reporter struct {
+	callMeta interceptors.CallMeta
+	labels   *telemetry.RequestLabels
+
+	mu      sync.Mutex
+	storeID string
+	modelID string
+}
+
+// PostCall records the request with the gRPC code of err. The model label is the one the handler resolved, if
+// any, which is the model used when the request has no model ID.
+func (r *reporter) PostCall(err error, duration time.Duration) {
+	r.mu.Lock()
+	storeID, modelID := r.storeID, r.modelID
+	r.mu.Unlock()
+
+	storeLabel := r.labels.StoreLabel(storeID)
+	modelLabel, ok := r.labels.ResolvedModelLabel()
+	if !ok {
+		modelLabel = r.labels.ModelLabel(storeID, modelID)
+	}
+
+	labels := []string{
+		r.callMeta.Service,
+		r.callMeta.Method,
+		status.Code(err).String(),
+		storeLabel,
+		modelLabel,
+	}
+
+	storeRequestCounter.WithLabelValues(labels...).Inc()
+	storeRequestDurationHistogram.WithLabelValues(labels...).Observe(float64(duration.Milliseconds()))
+}
+
+// PostMsgSend is a placeholder for handling actions after sending a message in streaming requests.
+func (r *reporter) PostMsgSend(interface{}, error, time.Duration) {}
+
+// PostMsgReceive keeps the store and model of the first message received, and counts the request towards
+// the ranking of its store.
+func (r *reporter) PostMsgReceive(msg interface{}, _ error, _ time.Duration) {
+	r.mu.Lock()
+	if r.storeID != "" {
+		r.mu.Unlock()
+		return
+	}
+	if m, ok := msg.(hasGetStoreID); ok {
+		r.storeID = m.GetStoreId()
+	}
+	if m, ok := msg.(hasGetAuthorizationModelID); ok {
+		r.modelID = m.GetAuthorizationModelId()
+	}
+	storeID := r.storeID
+	r.mu.Unlock()
+
+	r.labels.StoreLabel(storeID)
+}
+
+func reportable() interceptors.CommonReportableFunc {
+	return func(ctx context.Context, c interceptors.CallMeta) (interceptors.Reporter, context.Context) {
+		ctx, labels := telemetry.ContextWithRequestLabels(ctx)
+		return &reporter{callMeta: c, labels: labels}, ctx
+	}
+}
@@ -0,0 +1,96 @@
+package storemetrics
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+
+	"github.com/openfga/openfga/pkg/telemetry"
+)
+
+func TestUnaryInterceptor(t *testing.T) {
+	telemetry.SetStoreLabeler(telemetry.NewStoreLabeler(
+		telemetry.WithAllowedStores("allowed"),
+		telemetry.WithModelLabels(10),
+	))
+	t.Cleanup(func() {
+		telemetry.SetStoreLabeler(nil)
+	})
+
+	interceptor := NewUnaryInterceptor()
+	info := &grpc.UnaryServerInfo{FullMethod: "/openfga.v1.OpenFGAService/Check"}
+
+	_, err := interceptor(context.Background(), &openfgav1.CheckRequest{StoreId: "allowed", AuthorizationModelId: "model"}, info,
+		func(ctx context.Context, req interface{}) (interface{}, error) {
+			return nil, nil
+		})
+	require.NoError(t, err)
+
+	_, err = interceptor(context.Background(), &openfgav1.CheckRequest{StoreId: "not-allowed", AuthorizationModelId: "model"}, info,
+		func(ctx context.Context, req interface{}) (interface{}, error) {
+			return nil, status.Error(codes.InvalidArgument, "invalid")
+		})
+	require.Error(t, err)
+
+	require.InDelta(t, 1, testutil.ToFloat64(storeRequestCounter.WithLabelValues("openfga.v1.OpenFGAService", "Check", "OK", "allowed", "model")), 0)
+	require.InDelta(t, 1, testutil.ToFloat64(storeRequestCounter.WithLabelValues("openfga.v1.OpenFGAService", "Check", "InvalidArgument", telemetry.OtherLabelValue, telemetry.OtherLabelValue)), 0)
+}
+
+func TestUnaryInterceptorPassesLabelsToHandler(t *testing.T) {
+	telemetry.SetStoreLabeler(telemetry.NewStoreLabeler(telemetry.WithMaxStores(1), telemetry.WithModelLabels(1)))
+	t.Cleanup(func() {
+		telemetry.SetStoreLabeler(nil)
+	})
+
+	interceptor := NewUnaryInterceptor()
+	info := &grpc.UnaryServerInfo{FullMethod: "/openfga.v1.OpenFGAService/ListObjects"}
+
+	// The request has no model ID, so the model label is the one of the model the handler resolves.
+	_, err := interceptor(context.Background(), &openfgav1.ListObjectsRequest{StoreId: "store"}, info,
+		func(ctx context.Context, req interface{}) (interface{}, error) {
+			require.Equal(t, "store", telemetry.StoreLabelFromContext(ctx, "store"))
+			require.Equal(t, "latest", telemetry.ModelLabelFromContext(ctx, "store", "latest"))
+			return nil, nil
+		})
+	require.NoError(t, err)
+
+	require.InDelta(t, 1, testutil.ToFloat64(storeRequestCounter.WithLabelValues("openfga.v1.OpenFGAService", "ListObjects", "OK", "store", "latest")), 0)
+}
+
+type mockServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *mockServerStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *mockServerStream) RecvMsg(m interface{}) error {
+	m.(*openfgav1.StreamedListObjectsRequest).StoreId = "streamed"
+	return nil
+}
+
+func TestStreamingInterceptor(t *testing.T) {
+	telemetry.SetStoreLabeler(telemetry.NewStoreLabeler(telemetry.WithMaxStores(1)))
+	t.Cleanup(func() {
+		telemetry.SetStoreLabeler(nil)
+	})
+
+	interceptor := NewStreamingInterceptor()
+	info := &grpc.StreamServerInfo{FullMethod: "/openfga.v1.OpenFGAService/StreamedListObjects"}
+
+	err := interceptor(nil, &mockServerStream{ctx: context.Background()}, info, func(srv interface{}, stream grpc.ServerStream) error {
+		return stream.RecvMsg(&openfgav1.StreamedListObjectsRequest{})
+	})
+	require.NoError(t, err)
+
+	require.InDelta(t, 1, testutil.ToFloat64(storeRequestCounter.WithLabelValues("openfga.v1.OpenFGAService", "StreamedListObjects", "OK", "streamed", "")), 0)
+}
@@ -0,0 +1,168 @@
+package server
+
+import (
+	"context"
+	"errors"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+
+	"github.com/openfga/openfga/internal/condition"
+	"github.com/openfga/openfga/internal/graph"
+	"github.com/openfga/openfga/internal/utils/apimethod"
+	"github.com/openfga/openfga/pkg/server/commands"
+	"github.com/openfga/openfga/pkg/server/commands/listusers"
+	serverErrors "github.com/openfga/openfga/pkg/server/errors"
+	"github.com/openfga/openfga/pkg/storage"
+	"github.com/openfga/openfga/pkg/telemetry"
+	"github.com/openfga/openfga/pkg/typesystem"
+)
+
+// AccessReview reports the (user, relation, object) permissions of an object type that were gained or
+// lost between two positions of the changelog of a store. Rows are passed to emit once all of them
+// have been resolved. Permissions are resolved with Check, ListObjects and ListUsers, and so are subject
+// to the deadlines, result limits and throttling configured on the server for those APIs. The review fails
+// with commands.ErrAccessReviewIncomplete if one of them stops at its deadline.
+func (s *Server) AccessReview(
+	ctx context.Context,
+	req *commands.AccessReviewRequest,
+	emit func(*commands.AccessReviewRow) error,
+) error {
+	ctx, span := tracer.Start(ctx, apimethod.AccessReview.String(), trace.WithAttributes(
+		attribute.String("store_id", req.StoreID),
+		attribute.KeyValue{Key: authorizationModelIDKey, Value: attribute.StringValue(req.AuthorizationModelID)},
+		attribute.String("object_type", req.ObjectType),
+		attribute.StringSlice("relations", req.Relations),
+		attribute.String("from", req.From),
+		attribute.String("to", req.To),
+	))
+	defer span.End()
+
+	if err := req.Validate(); err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	ctx = telemetry.ContextWithRPCInfo(ctx, telemetry.RPCInfo{
+		Service: s.serviceName,
+		Method:  apimethod.AccessReview.String(),
+	})
+
+	err := s.checkAuthz(ctx, req.StoreID, apimethod.AccessReview)
+	if err != nil {
+		return err
+	}
+
+	typesys, err := s.resolveTypesystem(ctx, req.StoreID, req.AuthorizationModelID)
+	if err != nil {
+		return err
+	}
+	span.SetAttributes(attribute.KeyValue{Key: authorizationModelIDKey, Value: attribute.StringValue(typesys.GetAuthorizationModelID())})
+
+	checker := s.newUncachedChecker()
+	defer checker.Close()
+
+	// Every object and user is compared, so the result limits of the ListObjects and ListUsers APIs do not apply.
+	q := commands.NewAccessReviewQuery(s.datastore,
+		&accessReviewResolver{server: s, checker: checker, unlimited: true},
+		commands.WithAccessReviewMaxChanges(s.accessReviewMaxChanges),
+	)
+
+	err = q.Execute(typesystem.ContextWithTypesystem(ctx, typesys), typesys, req, emit)
+	if err != nil {
+		telemetry.TraceError(span, err)
+		return err
+	}
+	return nil
+}
+
+// newUncachedChecker returns a checker for tuples other than the current tuples of a store. The check
+// cache is keyed by the request only, so such tuples are resolved by a dedicated checker without cache.
+func (s *Server) newUncachedChecker() *graph.LocalChecker {
+	return graph.NewLocalChecker(
+		graph.WithResolveNodeBreadthLimit(s.resolveNodeBreadthLimit),
+		graph.WithMaxResolutionDepth(s.resolveNodeLimit),
+		graph.WithUpstreamTimeout(s.requestTimeout),
+		graph.WithLocalCheckerLogger(s.logger),
+	)
+}
+
+// accessReviewResolver implements [commands.AccessReviewResolver] with the server settings. Permissions
+// that depend on condition parameters cannot be resolved without a request context, so they are
+// treated as not granted.
+type accessReviewResolver struct {
+	server  *Server
+	checker graph.CheckResolver
+	// unlimited disables the result limits of the ListObjects and ListUsers APIs.
+	unlimited bool
+}
+
+var _ commands.AccessReviewResolver = (*accessReviewResolver)(nil)
+
+func (r *accessReviewResolver) Check(ctx context.Context, ds storage.RelationshipTupleReader, req *openfgav1.CheckRequest) (bool, error) {
+	typesys, _ := typesystem.TypesystemFromContext(ctx)
+	resp, _, err := commands.NewCheckCommand(ds, r.checker, typesys,
+		commands.WithCheckCommandLogger(r.server.logger),
+		commands.WithCheckCommandMaxConcurrentReads(r.server.maxConcurrentReadsForCheck),
+		commands.WithCheckAdaptiveLimiter(r.server.adaptiveLimiter),
+	).Execute(ctx, &commands.CheckCommandParams{
+		StoreID:          req.GetStoreId(),
+		TupleKey:         req.GetTupleKey(),
+		ContextualTuples: req.GetContextualTuples(),
+	})
+	if err != nil {
+		if errors.Is(err, condition.ErrEvaluationFailed) {
+			return false, nil
+		}
+		return false, commands.CheckCommandErrorToServerError(err)
+	}
+	return resp.GetAllowed(), nil
+}
+
+func (r *accessReviewResolver) ListObjects(ctx context.Context, ds storage.RelationshipTupleReader, req *openfgav1.ListObjectsRequest) ([]string, bool, error) {
+	maxResults := r.server.listObjectsMaxResults
+	if r.unlimited {
+		maxResults = 0
+	}
+	q, err := commands.NewListObjectsQuery(ds, r.checker,
+		commands.WithLogger(r.server.logger),
+		commands.WithListObjectsDeadline(r.server.listObjectsDeadline),
+		commands.WithListObjectsMaxResults(maxResults),
+		commands.WithResolveNodeLimit(r.server.resolveNodeLimit),
+		commands.WithResolveNodeBreadthLimit(r.server.resolveNodeBreadthLimit),
+		commands.WithMaxConcurrentReads(r.server.maxConcurrentReadsForListObjects),
+		commands.WithListObjectsAdaptiveLimiter(r.server.adaptiveLimiter),
+	)
+	if err != nil {
+		return nil, false, serverErrors.NewInternalError("", err)
+	}
+
+	resp, err := q.Execute(ctx, req)
+	if err != nil {
+		if errors.Is(err, condition.ErrEvaluationFailed) {
+			return nil, false, serverErrors.ValidationError(err)
+		}
+		return nil, false, err
+	}
+	return resp.Objects, resp.ResolutionMetadata.WasTruncated.Load(), nil
+}
+
+func (r *accessReviewResolver) ListUsers(ctx context.Context, ds storage.RelationshipTupleReader, req *openfgav1.ListUsersRequest) ([]*openfgav1.User, bool, error) {
+	typesys, _ := typesystem.TypesystemFromContext(ctx)
+	if err := listusers.ValidateListUsersRequest(ctx, req, typesys); err != nil {
+		return nil, false, err
+	}
+
+	opts := r.server.listUsersQueryOptions()
+	if r.unlimited {
+		opts = append(opts, listusers.WithListUsersMaxResults(0))
+	}
+	resp, err := listusers.NewListUsersQuery(ds, req.GetContextualTuples(), opts...).ListUsers(ctx, req)
+	if err != nil {
+		return nil, false, translateListUsersError(err)
+	}
+	return resp.GetUsers(), resp.Truncated(), nil
+}
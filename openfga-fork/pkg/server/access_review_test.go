@@ -0,0 +1,143 @@
+package server
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+
+	"github.com/openfga/openfga/pkg/server/commands"
+	"github.com/openfga/openfga/pkg/tuple"
+)
+
+func TestAccessReview(t *testing.T) {
+	ctx := context.Background()
+
+	s, _, storeID := newTestServer(t)
+
+	writeTestModel(t, s, storeID, `
+		model
+			schema 1.1
+		type user
+		type group
+			relations
+				define member: [user]
+		type folder
+			relations
+				define viewer: [user, group#member]
+		type document
+			relations
+				define parent: [folder]
+				define blocked: [user]
+				define viewer: ([user, user:*] or viewer from parent) but not blocked`)
+
+	write := func(writes []*openfgav1.TupleKey, deletes []*openfgav1.TupleKeyWithoutCondition) {
+		req := &openfgav1.WriteRequest{StoreId: storeID}
+		if len(writes) > 0 {
+			req.Writes = &openfgav1.WriteRequestWrites{TupleKeys: writes}
+		}
+		if len(deletes) > 0 {
+			req.Deletes = &openfgav1.WriteRequestDeletes{TupleKeys: deletes}
+		}
+		_, err := s.Write(ctx, req)
+		require.NoError(t, err)
+	}
+
+	write([]*openfgav1.TupleKey{
+		tuple.NewTupleKey("group:eng", "member", "user:anne"),
+		tuple.NewTupleKey("folder:specs", "viewer", "group:eng#member"),
+		tuple.NewTupleKey("document:roadmap", "viewer", "user:carl"),
+		tuple.NewTupleKey("document:plan", "parent", "folder:specs"),
+	}, nil)
+
+	time.Sleep(2 * time.Millisecond)
+	from := time.Now().UTC().Format(time.RFC3339Nano)
+	time.Sleep(2 * time.Millisecond)
+
+	write([]*openfgav1.TupleKey{
+		// bob joins a group that can view a folder: he gains access to the document in that folder.
+		tuple.NewTupleKey("group:eng", "member", "user:bob"),
+		// The roadmap becomes public.
+		tuple.NewTupleKey("document:roadmap", "viewer", "user:*"),
+		// anne is blocked from the plan, which she could view through the folder.
+		tuple.NewTupleKey("document:plan", "blocked", "user:anne"),
+		// The folder is made a parent of the roadmap: the group members gain access to it.
+		tuple.NewTupleKey("document:roadmap", "parent", "folder:specs"),
+	}, nil)
+	// carl still views the roadmap through the public access.
+	write(nil, []*openfgav1.TupleKeyWithoutCondition{
+		{Object: "document:roadmap", Relation: "viewer", User: "user:carl"},
+	})
+
+	expected := []commands.AccessReviewRow{
+		{Change: commands.AccessGained, User: "user:anne", Relation: "blocked", Object: "document:plan"},
+		{Change: commands.AccessLost, User: "user:anne", Relation: "viewer", Object: "document:plan"},
+		{Change: commands.AccessGained, User: "user:bob", Relation: "viewer", Object: "document:plan"},
+		{Change: commands.AccessGained, User: "folder:specs", Relation: "parent", Object: "document:roadmap"},
+		{Change: commands.AccessGained, User: "user:*", Relation: "viewer", Object: "document:roadmap"},
+		{Change: commands.AccessGained, User: "user:anne", Relation: "viewer", Object: "document:roadmap"},
+		{Change: commands.AccessGained, User: "user:bob", Relation: "viewer", Object: "document:roadmap"},
+	}
+
+	t.Run("resolves_effective_changes", func(t *testing.T) {
+		var rows []commands.AccessReviewRow
+		err := s.AccessReview(ctx, &commands.AccessReviewRequest{
+			StoreID:    storeID,
+			ObjectType: "document",
+			From:       from,
+		}, func(row *commands.AccessReviewRow) error {
+			rows = append(rows, *row)
+			return nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, expected, rows)
+	})
+
+	t.Run("invalid_request", func(t *testing.T) {
+		err := s.AccessReview(ctx, &commands.AccessReviewRequest{StoreID: storeID, ObjectType: "document"}, func(*commands.AccessReviewRow) error { return nil })
+		require.ErrorContains(t, err, "from is required")
+	})
+
+	t.Run("http_csv", func(t *testing.T) {
+		mux := newTestHTTPMux(t, s)
+		httpServer := httptest.NewServer(mux)
+		t.Cleanup(httpServer.Close)
+
+		params := url.Values{
+			"object_type": {"document"},
+			"relation":    {"viewer"},
+			"from":        {from},
+			"format":      {"csv"},
+		}
+		resp, err := http.Get(httpServer.URL + "/stores/" + storeID + "/access-review?" + params.Encode())
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.Equal(t, "change,user,relation,object\n"+
+			"lost,user:anne,viewer,document:plan\n"+
+			"gained,user:bob,viewer,document:plan\n"+
+			"gained,user:*,viewer,document:roadmap\n"+
+			"gained,user:anne,viewer,document:roadmap\n"+
+			"gained,user:bob,viewer,document:roadmap\n", string(body))
+		require.Empty(t, resp.Trailer.Get(httpErrorTrailer))
+	})
+
+	t.Run("http_invalid_position", func(t *testing.T) {
+		mux := newTestHTTPMux(t, s)
+
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/stores/"+storeID+"/access-review?object_type=document&from=yesterday", nil))
+		require.Equal(t, http.StatusBadRequest, rec.Code)
+		require.Contains(t, rec.Body.String(), "neither a ULID nor an RFC 3339 timestamp")
+	})
+}
@@ -0,0 +1,98 @@
+package server
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/openfga/openfga/internal/utils/apimethod"
+	"github.com/openfga/openfga/pkg/server/commands"
+	"github.com/openfga/openfga/pkg/telemetry"
+)
+
+// ReadActiveAuthorizationModel returns the model used for the store when requests omit the model ID,
+// and whether the store is pinned to it.
+func (s *Server) ReadActiveAuthorizationModel(ctx context.Context, req *commands.ReadActiveAuthorizationModelRequest) (*commands.ActiveAuthorizationModelResponse, error) {
+	ctx, span := tracer.Start(ctx, apimethod.ReadActiveAuthorizationModel.String(), trace.WithAttributes(
+		attribute.String("store_id", req.StoreID),
+	))
+	defer span.End()
+
+	if err := req.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	ctx = telemetry.ContextWithRPCInfo(ctx, telemetry.RPCInfo{
+		Service: s.serviceName,
+		Method:  apimethod.ReadActiveAuthorizationModel.String(),
+	})
+
+	err := s.checkAuthz(ctx, req.StoreID, apimethod.ReadActiveAuthorizationModel)
+	if err != nil {
+		return nil, err
+	}
+
+	q := commands.NewReadActiveAuthorizationModelQuery(s.datastore)
+	return q.Execute(ctx, req)
+}
+
+// WriteActiveAuthorizationModel pins the store to one of its models. An empty model ID removes the pin.
+// The change applies to the next request that omits the model ID.
+func (s *Server) WriteActiveAuthorizationModel(ctx context.Context, req *commands.WriteActiveAuthorizationModelRequest) (*commands.ActiveAuthorizationModelResponse, error) {
+	ctx, span := tracer.Start(ctx, apimethod.WriteActiveAuthorizationModel.String(), trace.WithAttributes(
+		attribute.String("store_id", req.StoreID),
+		attribute.KeyValue{Key: authorizationModelIDKey, Value: attribute.StringValue(req.AuthorizationModelID)},
+	))
+	defer span.End()
+
+	if err := req.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	ctx = telemetry.ContextWithRPCInfo(ctx, telemetry.RPCInfo{
+		Service: s.serviceName,
+		Method:  apimethod.WriteActiveAuthorizationModel.String(),
+	})
+
+	err := s.checkAuthz(ctx, req.StoreID, apimethod.WriteActiveAuthorizationModel)
+	if err != nil {
+		return nil, err
+	}
+
+	c := commands.NewWriteActiveAuthorizationModelCommand(s.datastore)
+	return c.Execute(ctx, req)
+}
+
+// RollbackActiveAuthorizationModel pins the store to the model written immediately before the one it currently uses.
+func (s *Server) RollbackActiveAuthorizationModel(ctx context.Context, req *commands.RollbackActiveAuthorizationModelRequest) (*commands.ActiveAuthorizationModelResponse, error) {
+	ctx, span := tracer.Start(ctx, apimethod.RollbackActiveAuthorizationModel.String(), trace.WithAttributes(
+		attribute.String("store_id", req.StoreID),
+	))
+	defer span.End()
+
+	if err := req.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	ctx = telemetry.ContextWithRPCInfo(ctx, telemetry.RPCInfo{
+		Service: s.serviceName,
+		Method:  apimethod.RollbackActiveAuthorizationModel.String(),
+	})
+
+	err := s.checkAuthz(ctx, req.StoreID, apimethod.RollbackActiveAuthorizationModel)
+	if err != nil {
+		return nil, err
+	}
+
+	c := commands.NewRollbackActiveAuthorizationModelCommand(s.datastore)
+	res, err := c.Execute(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	span.SetAttributes(attribute.KeyValue{Key: authorizationModelIDKey, Value: attribute.StringValue(res.AuthorizationModelID)})
+	return res, nil
+}
@@ -0,0 +1,120 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+	language "github.com/openfga/language/pkg/go/transformer"
+
+	"github.com/openfga/openfga/pkg/server/commands"
+)
+
+func TestActiveAuthorizationModel(t *testing.T) {
+	ctx := context.Background()
+
+	s, _, storeID := newTestServer(t)
+
+	model := language.MustTransformDSLToProto(`
+		model
+			schema 1.1
+		type user`)
+
+	modelIDs := make([]string, 0, 3)
+	for i := 0; i < 3; i++ {
+		writeModelResp, err := s.WriteAuthorizationModel(ctx, &openfgav1.WriteAuthorizationModelRequest{
+			StoreId:         storeID,
+			SchemaVersion:   model.GetSchemaVersion(),
+			TypeDefinitions: model.GetTypeDefinitions(),
+		})
+		require.NoError(t, err)
+		modelIDs = append(modelIDs, writeModelResp.GetAuthorizationModelId())
+	}
+
+	requireResolvedModel := func(t *testing.T, expectedModelID string) {
+		typesys, err := s.resolveTypesystem(ctx, storeID, "")
+		require.NoError(t, err)
+		require.Equal(t, expectedModelID, typesys.GetAuthorizationModelID())
+	}
+
+	t.Run("latest_model_is_active_by_default", func(t *testing.T) {
+		resp, err := s.ReadActiveAuthorizationModel(ctx, &commands.ReadActiveAuthorizationModelRequest{StoreID: storeID})
+		require.NoError(t, err)
+		require.Equal(t, &commands.ActiveAuthorizationModelResponse{AuthorizationModelID: modelIDs[2]}, resp)
+		requireResolvedModel(t, modelIDs[2])
+	})
+
+	t.Run("rollback_pins_previous_model", func(t *testing.T) {
+		resp, err := s.RollbackActiveAuthorizationModel(ctx, &commands.RollbackActiveAuthorizationModelRequest{StoreID: storeID})
+		require.NoError(t, err)
+		require.Equal(t, &commands.ActiveAuthorizationModelResponse{AuthorizationModelID: modelIDs[1], Pinned: true}, resp)
+		requireResolvedModel(t, modelIDs[1])
+
+		resp, err = s.RollbackActiveAuthorizationModel(ctx, &commands.RollbackActiveAuthorizationModelRequest{StoreID: storeID})
+		require.NoError(t, err)
+		require.Equal(t, modelIDs[0], resp.AuthorizationModelID)
+		requireResolvedModel(t, modelIDs[0])
+
+		_, err = s.RollbackActiveAuthorizationModel(ctx, &commands.RollbackActiveAuthorizationModelRequest{StoreID: storeID})
+		require.ErrorContains(t, err, "no authorization model older than")
+	})
+
+	t.Run("pin_and_unpin", func(t *testing.T) {
+		resp, err := s.WriteActiveAuthorizationModel(ctx, &commands.WriteActiveAuthorizationModelRequest{StoreID: storeID, AuthorizationModelID: modelIDs[1]})
+		require.NoError(t, err)
+		require.Equal(t, &commands.ActiveAuthorizationModelResponse{AuthorizationModelID: modelIDs[1], Pinned: true}, resp)
+		requireResolvedModel(t, modelIDs[1])
+
+		resp, err = s.WriteActiveAuthorizationModel(ctx, &commands.WriteActiveAuthorizationModelRequest{StoreID: storeID})
+		require.NoError(t, err)
+		require.Equal(t, &commands.ActiveAuthorizationModelResponse{AuthorizationModelID: modelIDs[2]}, resp)
+		requireResolvedModel(t, modelIDs[2])
+	})
+
+	t.Run("invalid_store_id", func(t *testing.T) {
+		_, err := s.ReadActiveAuthorizationModel(ctx, &commands.ReadActiveAuthorizationModelRequest{StoreID: "abc"})
+		require.ErrorContains(t, err, "invalid store_id")
+	})
+
+	t.Run("http_routes", func(t *testing.T) {
+		mux := newTestHTTPMux(t, s)
+
+		do := func(method, path, body string) (int, map[string]any) {
+			req := httptest.NewRequest(method, path, strings.NewReader(body))
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, req)
+
+			var decoded map[string]any
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &decoded))
+			return rec.Code, decoded
+		}
+
+		route := "/stores/" + storeID + "/active-authorization-model"
+
+		code, body := do(http.MethodPut, route, `{"authorization_model_id":"`+modelIDs[0]+`"}`)
+		require.Equal(t, http.StatusOK, code)
+		require.Equal(t, map[string]any{"authorization_model_id": modelIDs[0], "pinned": true}, body)
+
+		code, body = do(http.MethodGet, route, "")
+		require.Equal(t, http.StatusOK, code)
+		require.Equal(t, map[string]any{"authorization_model_id": modelIDs[0], "pinned": true}, body)
+
+		code, body = do(http.MethodPost, route+"/rollback", "")
+		require.Equal(t, http.StatusBadRequest, code)
+		require.Equal(t, "validation_error", body["code"])
+
+		code, body = do(http.MethodDelete, route, "")
+		require.Equal(t, http.StatusOK, code)
+		require.Equal(t, map[string]any{"authorization_model_id": modelIDs[2], "pinned": false}, body)
+
+		code, body = do(http.MethodPut, route, `{"authorization_model_id":"`+storeID+`"}`)
+		require.Equal(t, http.StatusBadRequest, code)
+		require.Equal(t, "authorization_model_not_found", body["code"])
+	})
+}
@@ -0,0 +1,140 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"slices"
+
+	"go.uber.org/zap"
+
+	"github.com/openfga/openfga/internal/planner"
+	"github.com/openfga/openfga/pkg/storage"
+)
+
+const (
+	adminCacheCheck    = "check"
+	adminCacheIterator = "iterator"
+	adminCacheModel    = "model"
+)
+
+// adminCaches are the caches that can be flushed through the admin endpoints.
+var adminCaches = []string{adminCacheCheck, adminCacheIterator, adminCacheModel}
+
+// RegisterAdminHandlers registers on mux the admin endpoints that inspect and flush the caches of the server:
+//
+//   - GET /models lists the cached authorization models, optionally of the store in the 'store_id' query parameter.
+//   - GET /planner returns the statistics of the query planner keys.
+//   - GET /caches returns the number of entries in the caches and of shared iterators.
+//   - POST /caches/flush flushes the caches of the store in the 'store_id' query parameter. The 'cache' query
+//     parameter, repeated, restricts the flush to some of the 'check', 'iterator' and 'model' caches.
+//
+// The endpoints are not authenticated: mux must only be served behind authentication.
+func (s *Server) RegisterAdminHandlers(mux *http.ServeMux) {
+	mux.HandleFunc("GET /models", s.handleAdminModels)
+	mux.HandleFunc("GET /planner", s.handleAdminPlanner)
+	mux.HandleFunc("GET /caches", s.handleAdminCaches)
+	mux.HandleFunc("POST /caches/flush", s.handleAdminFlushCaches)
+}
+
+func (s *Server) handleAdminModels(w http.ResponseWriter, r *http.Request) {
+	writeAdminJSON(w, map[string]any{
+		"authorization_models": s.authorizationModelCache.CachedAuthorizationModels(r.URL.Query().Get("store_id")),
+	})
+}
+
+func (s *Server) handleAdminPlanner(w http.ResponseWriter, _ *http.Request) {
+	keys := []planner.KeyStats{}
+	if s.planner != nil {
+		keys = s.planner.Stats()
+	}
+	writeAdminJSON(w, map[string]any{
+		"keys": keys,
+	})
+}
+
+// adminCacheStats are the number of entries of a cache. Caches that are not enabled have none.
+type adminCacheStats struct {
+	Enabled bool `json:"enabled"`
+	Items   int  `json:"items"`
+	Limit   int  `json:"limit,omitempty"`
+}
+
+func (s *Server) handleAdminCaches(w http.ResponseWriter, _ *http.Request) {
+	resources := s.sharedDatastoreResources
+	writeAdminJSON(w, map[string]adminCacheStats{
+		// The check cache also holds the cached iterators.
+		"check_cache": {
+			Enabled: resources.CheckCache != nil,
+			Items:   cacheLen(resources.CheckCache),
+			Limit:   int(s.cacheSettings.CheckCacheLimit),
+		},
+		"shadow_check_cache": {
+			Enabled: resources.ShadowCheckCache != nil && resources.ShadowCheckCache != resources.CheckCache,
+			Items:   cacheLen(resources.ShadowCheckCache),
+			Limit:   int(s.cacheSettings.CheckCacheLimit),
+		},
+		"authorization_model_cache": {
+			Enabled: true,
+			Items:   s.authorizationModelCache.CachedAuthorizationModelCount(),
+			Limit:   s.maxAuthorizationModelCacheSize,
+		},
+		"shared_iterators": {
+			Enabled: s.cacheSettings.SharedIteratorEnabled,
+			Items:   resources.SharedIteratorStorage.Len(),
+			Limit:   int(s.cacheSettings.SharedIteratorLimit),
+		},
+	})
+}
+
+func (s *Server) handleAdminFlushCaches(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	storeID := query.Get("store_id")
+	if storeID == "" {
+		http.Error(w, "'store_id' is required", http.StatusBadRequest)
+		return
+	}
+
+	caches := query["cache"]
+	if len(caches) == 0 {
+		caches = adminCaches
+	}
+	for _, cache := range caches {
+		if !slices.Contains(adminCaches, cache) {
+			http.Error(w, "'cache' must be one of 'check', 'iterator' and 'model'", http.StatusBadRequest)
+			return
+		}
+	}
+
+	removedModels := 0
+	for _, cache := range caches {
+		switch cache {
+		case adminCacheCheck:
+			s.sharedDatastoreResources.InvalidateCheckCache(storeID)
+		case adminCacheIterator:
+			s.sharedDatastoreResources.InvalidateIteratorCache(storeID)
+		case adminCacheModel:
+			removedModels = s.authorizationModelCache.FlushAuthorizationModels(storeID)
+		}
+	}
+
+	s.logger.Info("caches flushed through the admin endpoint", zap.String("store_id", storeID), zap.Strings("caches", caches))
+
+	writeAdminJSON(w, map[string]any{
+		"store_id":                     storeID,
+		"flushed":                      caches,
+		"removed_authorization_models": removedModels,
+	})
+}
+
+// cacheLen returns the number of entries of cache, if it can tell.
+func cacheLen(cache storage.InMemoryCache[any]) int {
+	if sized, ok := cache.(interface{ Len() int }); ok {
+		return sized.Len()
+	}
+	return 0
+}
+
+func writeAdminJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,94 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	serverconfig "github.com/openfga/openfga/pkg/server/config"
+	"github.com/openfga/openfga/pkg/storage"
+	"github.com/openfga/openfga/pkg/typesystem"
+)
+
+func TestAdminHandlers(t *testing.T) {
+	ctx := context.Background()
+
+	s, _, storeID := newTestServer(t, WithCheckQueryCacheEnabled(true), WithCheckCacheLimit(100))
+
+	modelID := writeTestModel(t, s, storeID, `
+		model
+			schema 1.1
+		type user`)
+
+	_, err := s.resolveTypesystem(ctx, storeID, modelID)
+	require.NoError(t, err)
+
+	mux := http.NewServeMux()
+	s.RegisterAdminHandlers(mux)
+
+	serve := func(t *testing.T, method, target string, expectedCode int) map[string]any {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(method, target, nil))
+		require.Equal(t, expectedCode, rec.Code, rec.Body.String())
+		if expectedCode != http.StatusOK {
+			return nil
+		}
+
+		var body map[string]any
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+		return body
+	}
+
+	t.Run("models", func(t *testing.T) {
+		body := serve(t, http.MethodGet, "/models?store_id="+storeID, http.StatusOK)
+		require.Equal(t, []any{map[string]any{
+			"store_id":               storeID,
+			"authorization_model_id": modelID,
+			"schema_version":         typesystem.SchemaVersion1_1,
+		}}, body["authorization_models"])
+
+		body = serve(t, http.MethodGet, "/models?store_id=other", http.StatusOK)
+		require.Empty(t, body["authorization_models"])
+	})
+
+	t.Run("planner", func(t *testing.T) {
+		body := serve(t, http.MethodGet, "/planner", http.StatusOK)
+		require.NotNil(t, body["keys"])
+	})
+
+	t.Run("caches", func(t *testing.T) {
+		body := serve(t, http.MethodGet, "/caches", http.StatusOK)
+		require.Equal(t, map[string]any{"enabled": true, "items": float64(1), "limit": float64(serverconfig.DefaultMaxAuthorizationModelCacheSize)}, body["authorization_model_cache"])
+		require.Equal(t, true, body["check_cache"].(map[string]any)["enabled"])
+		require.Equal(t, false, body["shadow_check_cache"].(map[string]any)["enabled"])
+		require.Contains(t, body, "shared_iterators")
+	})
+
+	t.Run("flush_requires_a_store", func(t *testing.T) {
+		serve(t, http.MethodPost, "/caches/flush", http.StatusBadRequest)
+		serve(t, http.MethodPost, "/caches/flush?store_id="+storeID+"&cache=unknown", http.StatusBadRequest)
+		serve(t, http.MethodGet, "/caches/flush?store_id="+storeID, http.StatusMethodNotAllowed)
+	})
+
+	t.Run("flush_check_cache", func(t *testing.T) {
+		body := serve(t, http.MethodPost, "/caches/flush?store_id="+storeID+"&cache=check&cache=iterator", http.StatusOK)
+		require.Equal(t, []any{"check", "iterator"}, body["flushed"])
+		require.InDelta(t, 0, body["removed_authorization_models"], 0)
+
+		for _, key := range []string{storage.GetInvalidCheckCacheKey(storeID), storage.GetInvalidIteratorCacheKey(storeID)} {
+			require.NotNil(t, s.sharedDatastoreResources.CheckCache.Get(key))
+		}
+		require.Len(t, s.authorizationModelCache.CachedAuthorizationModels(storeID), 1)
+	})
+
+	t.Run("flush_all_caches", func(t *testing.T) {
+		body := serve(t, http.MethodPost, "/caches/flush?store_id="+storeID, http.StatusOK)
+		require.Equal(t, []any{"check", "iterator", "model"}, body["flushed"])
+		require.InDelta(t, 1, body["removed_authorization_models"], 0)
+		require.Empty(t, s.authorizationModelCache.CachedAuthorizationModels(storeID))
+	})
+}
@@ -13,8 +13,10 @@ import (
 	openfgav1 "github.com/openfga/api/proto/openfga/v1"
 
 	"github.com/openfga/openfga/internal/condition"
+	"github.com/openfga/openfga/internal/condition/contextprovider"
 	"github.com/openfga/openfga/internal/graph"
 	"github.com/openfga/openfga/internal/utils/apimethod"
+	"github.com/openfga/openfga/pkg/decisionlog"
 	"github.com/openfga/openfga/pkg/middleware/validator"
 	"github.com/openfga/openfga/pkg/server/commands"
 	serverErrors "github.com/openfga/openfga/pkg/server/errors"
@@ -46,11 +48,20 @@ func (s *Server) BatchCheck(ctx context.Context, req *openfgav1.BatchCheckReques
 		return nil, err
 	}
 
+	// The trusted values are computed once, so that identical checks have the same cache key and are
+	// deduplicated.
+	trustedFields := contextprovider.Fields(ctx, s.conditionContextProviders)
+	for _, check := range req.GetChecks() {
+		check.Context = contextprovider.MergeFields(trustedFields, check.GetContext())
+	}
+
 	typesys, err := s.resolveTypesystem(ctx, storeID, req.GetAuthorizationModelId())
 	if err != nil {
 		return nil, err
 	}
 
+	storeLabel, modelLabel := telemetry.StoreLabelFromContext(ctx, storeID), telemetry.ModelLabelFromContext(ctx, storeID, typesys.GetAuthorizationModelID())
+
 	cmd := commands.NewBatchCheckCommand(
 		s.datastore,
 		s.checkResolver,
@@ -60,6 +71,7 @@ func (s *Server) BatchCheck(ctx context.Context, req *openfgav1.BatchCheckReques
 		commands.WithBatchCheckMaxChecksPerBatch(s.maxChecksPerBatchCheck),
 		commands.WithBatchCheckMaxConcurrentChecks(s.maxConcurrentChecksPerBatch),
 		commands.WithBatchCheckDatastoreThrottler(s.checkDatastoreThrottleThreshold, s.checkDatastoreThrottleDuration),
+		commands.WithBatchCheckAdaptiveLimiter(s.adaptiveLimiter),
 	)
 
 	result, metadata, err := cmd.Execute(ctx, &commands.BatchCheckCommandParams{
@@ -87,13 +99,15 @@ func (s *Server) BatchCheck(ctx context.Context, req *openfgav1.BatchCheckReques
 	dispatchCountHistogram.WithLabelValues(
 		s.serviceName,
 		methodName,
+		storeLabel,
+		modelLabel,
 	).Observe(dispatchCount)
 
 	var throttled bool
 
 	if metadata.ThrottleCount > 0 {
 		throttled = true
-		throttledRequestCounter.WithLabelValues(s.serviceName, methodName).Add(float64(metadata.ThrottleCount))
+		throttledRequestCounter.WithLabelValues(s.serviceName, methodName, storeLabel, modelLabel).Add(float64(metadata.ThrottleCount))
 	}
 	grpc_ctxtags.Extract(ctx).Set("request.throttled", throttled)
 
@@ -102,6 +116,8 @@ func (s *Server) BatchCheck(ctx context.Context, req *openfgav1.BatchCheckReques
 	datastoreQueryCountHistogram.WithLabelValues(
 		s.serviceName,
 		methodName,
+		storeLabel,
+		modelLabel,
 	).Observe(queryCount)
 
 	duplicateChecks := "duplicate_checks"
@@ -116,9 +132,178 @@ func (s *Server) BatchCheck(ctx context.Context, req *openfgav1.BatchCheckReques
 
 	grpc_ctxtags.Extract(ctx).Set(datastoreQueryCountHistogramName, metadata.DatastoreQueryCount)
 
+	s.logBatchCheckDecisions(ctx, req, typesys.GetAuthorizationModelID(), result)
+
 	return &openfgav1.BatchCheckResponse{Result: batchResult}, nil
 }
 
+// logBatchCheckDecisions records a decision for each check of the batch. The dispatch count is only known
+// for the whole batch, so it is not recorded.
+func (s *Server) logBatchCheckDecisions(
+	ctx context.Context,
+	req *openfgav1.BatchCheckRequest,
+	modelID string,
+	result map[commands.CorrelationID]*commands.BatchCheckOutcome,
+) {
+	if s.decisionLogger == nil {
+		return
+	}
+
+	for _, check := range req.GetChecks() {
+		outcome, ok := result[commands.CorrelationID(check.GetCorrelationId())]
+		if !ok {
+			continue
+		}
+		s.logBatchCheckDecision(ctx, apimethod.BatchCheck, req.GetStoreId(), modelID, check, outcome)
+	}
+}
+
+func (s *Server) logBatchCheckDecision(
+	ctx context.Context,
+	method apimethod.APIMethod,
+	storeID, modelID string,
+	check *openfgav1.BatchCheckItem,
+	outcome *commands.BatchCheckOutcome,
+) {
+	if s.decisionLogger == nil {
+		return
+	}
+
+	decision := &decisionlog.Decision{
+		Method:               method.String(),
+		StoreID:              storeID,
+		AuthorizationModelID: modelID,
+		CorrelationID:        check.GetCorrelationId(),
+		User:                 check.GetTupleKey().GetUser(),
+		Relation:             check.GetTupleKey().GetRelation(),
+		Object:               check.GetTupleKey().GetObject(),
+		ContextualTupleCount: len(check.GetContextualTuples().GetTupleKeys()),
+	}
+
+	if outcome.Err != nil {
+		decision.Error = outcome.Err.Error()
+	} else {
+		allowed := outcome.CheckResponse.GetAllowed()
+		decision.Allowed = &allowed
+		decision.DatastoreQueryCount = outcome.CheckResponse.GetResolutionMetadata().DatastoreQueryCount
+	}
+
+	s.logDecision(ctx, decision, check.GetContext())
+}
+
+// StreamedBatchCheck runs the checks returned by params.Next as they are received and sends the result of each
+// check to emit, with its correlation ID, as soon as it is known. Unlike BatchCheck, the number of checks is
+// not limited by maxChecksPerBatchCheck, and identical checks are evaluated once across the whole stream.
+func (s *Server) StreamedBatchCheck(
+	ctx context.Context,
+	params *commands.StreamedBatchCheckParams,
+	emit func(correlationID string, result *openfgav1.BatchCheckSingleResult) error,
+) (*commands.StreamedBatchCheckMetadata, error) {
+	const methodName = "streamedbatchcheck"
+
+	ctx, span := tracer.Start(ctx, apimethod.StreamedBatchCheck.String(), trace.WithAttributes(
+		attribute.String("store_id", params.StoreID),
+		attribute.String("consistency", params.Consistency.String()),
+	))
+	defer span.End()
+
+	if err := params.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	ctx = telemetry.ContextWithRPCInfo(ctx, telemetry.RPCInfo{
+		Service: s.serviceName,
+		Method:  apimethod.StreamedBatchCheck.String(),
+	})
+
+	err := s.checkAuthz(ctx, params.StoreID, apimethod.StreamedBatchCheck)
+	if err != nil {
+		return nil, err
+	}
+
+	typesys, err := s.resolveTypesystem(ctx, params.StoreID, params.AuthorizationModelID)
+	if err != nil {
+		return nil, err
+	}
+
+	storeLabel, modelLabel := telemetry.StoreLabelFromContext(ctx, params.StoreID), telemetry.ModelLabelFromContext(ctx, params.StoreID, typesys.GetAuthorizationModelID())
+
+	cmd := commands.NewBatchCheckCommand(
+		s.datastore,
+		s.checkResolver,
+		typesys,
+		commands.WithBatchCheckCacheOptions(s.sharedDatastoreResources, s.cacheSettings),
+		commands.WithBatchCheckCommandLogger(s.logger),
+		commands.WithBatchCheckMaxConcurrentChecks(s.maxConcurrentChecksPerBatch),
+		commands.WithBatchCheckDatastoreThrottler(s.checkDatastoreThrottleThreshold, s.checkDatastoreThrottleDuration),
+		commands.WithBatchCheckAdaptiveLimiter(s.adaptiveLimiter),
+	)
+
+	// As for BatchCheck, the trusted values are computed once for the whole stream, so that identical
+	// checks are deduplicated.
+	trustedFields := contextprovider.Fields(ctx, s.conditionContextProviders)
+	metadata, err := cmd.ExecuteStreamed(ctx, &commands.StreamedBatchCheckParams{
+		StoreID:     params.StoreID,
+		Consistency: params.Consistency,
+		Next: func() (*openfgav1.BatchCheckItem, error) {
+			check, err := params.Next()
+			if err != nil {
+				return nil, err
+			}
+			if err := check.Validate(); err != nil {
+				return nil, status.Error(codes.InvalidArgument, err.Error())
+			}
+			check.Context = contextprovider.MergeFields(trustedFields, check.GetContext())
+			return check, nil
+		},
+	}, func(check *openfgav1.BatchCheckItem, outcome *commands.BatchCheckOutcome) error {
+		s.emitCheckDurationMetric(outcome.CheckResponse.GetResolutionMetadata(), methodName)
+		s.logBatchCheckDecision(ctx, apimethod.StreamedBatchCheck, params.StoreID, typesys.GetAuthorizationModelID(), check, outcome)
+		return emit(check.GetCorrelationId(), transformCheckResultToProto(outcome))
+	})
+	if err != nil {
+		telemetry.TraceError(span, err)
+		var batchValidationError *commands.BatchCheckValidationError
+		if errors.As(err, &batchValidationError) {
+			return nil, serverErrors.ValidationError(err)
+		}
+		return nil, err
+	}
+
+	span.SetAttributes(attribute.Int("check_count", metadata.CheckCount))
+
+	dispatchCount := float64(metadata.DispatchCount)
+	grpc_ctxtags.Extract(ctx).Set(dispatchCountHistogramName, dispatchCount)
+	span.SetAttributes(attribute.Float64(dispatchCountHistogramName, dispatchCount))
+	dispatchCountHistogram.WithLabelValues(
+		s.serviceName,
+		methodName,
+		storeLabel,
+		modelLabel,
+	).Observe(dispatchCount)
+
+	if metadata.ThrottleCount > 0 {
+		throttledRequestCounter.WithLabelValues(s.serviceName, methodName, storeLabel, modelLabel).Add(float64(metadata.ThrottleCount))
+	}
+	grpc_ctxtags.Extract(ctx).Set("request.throttled", metadata.ThrottleCount > 0)
+
+	queryCount := float64(metadata.DatastoreQueryCount)
+	grpc_ctxtags.Extract(ctx).Set(datastoreQueryCountHistogramName, metadata.DatastoreQueryCount)
+	span.SetAttributes(attribute.Float64(datastoreQueryCountHistogramName, queryCount))
+	datastoreQueryCountHistogram.WithLabelValues(
+		s.serviceName,
+		methodName,
+		storeLabel,
+		modelLabel,
+	).Observe(queryCount)
+
+	duplicateChecks := "duplicate_checks"
+	span.SetAttributes(attribute.Int(duplicateChecks, metadata.DuplicateCheckCount))
+	grpc_ctxtags.Extract(ctx).Set(duplicateChecks, metadata.DuplicateCheckCount)
+
+	return metadata, nil
+}
+
 // transformCheckResultToProto transforms the internal BatchCheckOutcome into the external-facing
 // BatchCheckSingleResult struct for transmission back via the api.
 func transformCheckResultToProto(outcome *commands.BatchCheckOutcome) *openfgav1.BatchCheckSingleResult {
@@ -1,18 +1,27 @@
 package server
 
 import (
+	"bufio"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
 	"github.com/oklog/ulid/v2"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/goleak"
 
 	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+	language "github.com/openfga/language/pkg/go/transformer"
 
 	"github.com/openfga/openfga/cmd/util"
+	"github.com/openfga/openfga/internal/authn"
 	"github.com/openfga/openfga/internal/condition"
 	"github.com/openfga/openfga/internal/graph"
 	"github.com/openfga/openfga/pkg/server/commands"
@@ -223,6 +232,106 @@ func TestBatchCheckFailsIfTooManyChecks(t *testing.T) {
 	)
 	require.ErrorContains(t, err, msg)
 }
+func TestStreamedBatchCheck(t *testing.T) {
+	t.Cleanup(func() {
+		goleak.VerifyNone(t)
+	})
+
+	ctx := context.Background()
+
+	_, ds, _ := util.MustBootstrapDatastore(t, "memory")
+	s := MustNewServerWithOpts(WithDatastore(ds))
+	t.Cleanup(s.Close)
+
+	createStoreResp, err := s.CreateStore(ctx, &openfgav1.CreateStoreRequest{Name: "openfga-test"})
+	require.NoError(t, err)
+	storeID := createStoreResp.GetId()
+
+	model := language.MustTransformDSLToProto(`
+		model
+			schema 1.1
+		type user
+		type doc
+			relations
+				define viewer: [user]`)
+	_, err = s.WriteAuthorizationModel(ctx, &openfgav1.WriteAuthorizationModelRequest{
+		StoreId:         storeID,
+		SchemaVersion:   model.GetSchemaVersion(),
+		TypeDefinitions: model.GetTypeDefinitions(),
+	})
+	require.NoError(t, err)
+
+	_, err = s.Write(ctx, &openfgav1.WriteRequest{
+		StoreId: storeID,
+		Writes: &openfgav1.WriteRequestWrites{TupleKeys: []*openfgav1.TupleKey{
+			tuple.NewTupleKey("doc:1", "viewer", "user:anne"),
+		}},
+	})
+	require.NoError(t, err)
+
+	mux := runtime.NewServeMux()
+	require.NoError(t, s.RegisterHTTPHandlers(mux, authn.NoopAuthenticator{}))
+	httpServer := httptest.NewServer(mux)
+	t.Cleanup(httpServer.Close)
+
+	checkLine := func(correlationID, object string) string {
+		return fmt.Sprintf(`{"tuple_key": {"object": %q, "relation": "viewer", "user": "user:anne"}, "correlation_id": %q}`+"\n", object, correlationID)
+	}
+
+	t.Run("results_are_sent_while_the_checks_are_received", func(t *testing.T) {
+		body, bodyWriter := io.Pipe()
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, httpServer.URL+"/stores/"+storeID+"/streamed-batch-check", body)
+		require.NoError(t, err)
+
+		// The pipe is only read once the request is sent.
+		go func() {
+			_, _ = io.WriteString(bodyWriter, checkLine("a", "doc:1"))
+		}()
+
+		res, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer res.Body.Close()
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		lines := bufio.NewScanner(res.Body)
+
+		var line streamedBatchCheckHTTPLine
+		require.True(t, lines.Scan())
+		require.NoError(t, json.Unmarshal(lines.Bytes(), &line))
+		require.Equal(t, "a", line.Result.CorrelationID)
+		require.JSONEq(t, `{"allowed": true}`, string(line.Result.Check))
+
+		_, err = io.WriteString(bodyWriter, checkLine("b", "doc:2")+checkLine("c", "doc:1"))
+		require.NoError(t, err)
+		require.NoError(t, bodyWriter.Close())
+
+		results := map[string]string{}
+		var metadata *commands.StreamedBatchCheckMetadata
+		for lines.Scan() {
+			line := streamedBatchCheckHTTPLine{}
+			require.NoError(t, json.Unmarshal(lines.Bytes(), &line))
+			if line.Result.Metadata != nil {
+				metadata = line.Result.Metadata
+				continue
+			}
+			results[line.Result.CorrelationID] = string(line.Result.Check)
+		}
+		require.Len(t, results, 2)
+		require.JSONEq(t, `{"allowed": false}`, results["b"])
+		require.JSONEq(t, `{"allowed": true}`, results["c"])
+		require.NotNil(t, metadata)
+		require.Equal(t, 3, metadata.CheckCount)
+		require.Equal(t, 1, metadata.DuplicateCheckCount)
+		require.Empty(t, res.Trailer.Get(httpErrorTrailer))
+	})
+
+	t.Run("invalid_check", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/stores/"+storeID+"/streamed-batch-check",
+			strings.NewReader(`{"tuple_key": {"object": "doc:1", "relation": "viewer", "user": "user:anne"}}`)))
+		require.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
+
 func TestTransformCheckCommandErrorToBatchCheckError(t *testing.T) {
 	t.Cleanup(func() {
 		goleak.VerifyNone(t)
@@ -17,9 +17,11 @@ import (
 
 	"github.com/openfga/openfga/internal/utils"
 	"github.com/openfga/openfga/internal/utils/apimethod"
+	"github.com/openfga/openfga/pkg/decisionlog"
 	"github.com/openfga/openfga/pkg/middleware/validator"
 	"github.com/openfga/openfga/pkg/server/commands"
 	serverErrors "github.com/openfga/openfga/pkg/server/errors"
+	"github.com/openfga/openfga/pkg/slowlog"
 	"github.com/openfga/openfga/pkg/telemetry"
 )
 
@@ -54,6 +56,8 @@ func (s *Server) Check(ctx context.Context, req *openfgav1.CheckRequest) (*openf
 		return nil, err
 	}
 
+	req.Context = s.trustedConditionContext(ctx, req.GetContext())
+
 	storeID := req.GetStoreId()
 
 	typesys, err := s.resolveTypesystem(ctx, storeID, req.GetAuthorizationModelId())
@@ -61,6 +65,8 @@ func (s *Server) Check(ctx context.Context, req *openfgav1.CheckRequest) (*openf
 		return nil, err
 	}
 
+	storeLabel, modelLabel := telemetry.StoreLabelFromContext(ctx, storeID), telemetry.ModelLabelFromContext(ctx, storeID, typesys.GetAuthorizationModelID())
+
 	checkQuery := commands.NewCheckCommand(
 		s.datastore,
 		s.checkResolver,
@@ -69,6 +75,8 @@ func (s *Server) Check(ctx context.Context, req *openfgav1.CheckRequest) (*openf
 		commands.WithCheckCommandMaxConcurrentReads(s.maxConcurrentReadsForCheck),
 		commands.WithCheckCommandCache(s.sharedDatastoreResources, s.cacheSettings),
 		commands.WithCheckDatastoreThrottler(s.checkDatastoreThrottleThreshold, s.checkDatastoreThrottleDuration),
+		commands.WithCheckAdaptiveLimiter(s.adaptiveLimiter),
+		commands.WithCheckCommandResolutionPath(s.slowLog != nil),
 	)
 
 	resp, checkRequestMetadata, err := checkQuery.Execute(ctx, &commands.CheckCommandParams{
@@ -81,6 +89,23 @@ func (s *Server) Check(ctx context.Context, req *openfgav1.CheckRequest) (*openf
 
 	endTime := time.Since(startTime).Milliseconds()
 
+	s.logSlowRequest(ctx, apimethod.Check.String(), req, time.Since(startTime), func() *slowlog.Entry {
+		entry := &slowlog.Entry{
+			StoreID:              storeID,
+			AuthorizationModelID: typesys.GetAuthorizationModelID(),
+			DatastoreQueryCount:  resp.GetResolutionMetadata().DatastoreQueryCount,
+		}
+		if checkRequestMetadata != nil {
+			entry.DispatchCount = checkRequestMetadata.DispatchCounter.Load()
+			entry.Resolvers = checkRequestMetadata.ResolutionPath.Resolvers()
+			entry.PlannerDecisions = checkRequestMetadata.ResolutionPath.Plans()
+		}
+		if err != nil {
+			entry.Error = err.Error()
+		}
+		return entry
+	})
+
 	var (
 		wasRequestThrottled bool
 		rawDispatchCount    uint32
@@ -96,6 +121,8 @@ func (s *Server) Check(ctx context.Context, req *openfgav1.CheckRequest) (*openf
 		dispatchCountHistogram.WithLabelValues(
 			s.serviceName,
 			methodName,
+			storeLabel,
+			modelLabel,
 		).Observe(dispatchCount)
 	}
 
@@ -107,6 +134,8 @@ func (s *Server) Check(ctx context.Context, req *openfgav1.CheckRequest) (*openf
 		datastoreQueryCountHistogram.WithLabelValues(
 			s.serviceName,
 			methodName,
+			storeLabel,
+			modelLabel,
 		).Observe(queryCount)
 
 		requestDurationHistogram.WithLabelValues(
@@ -115,6 +144,8 @@ func (s *Server) Check(ctx context.Context, req *openfgav1.CheckRequest) (*openf
 			utils.Bucketize(uint(queryCount), s.requestDurationByQueryHistogramBuckets),
 			utils.Bucketize(uint(rawDispatchCount), s.requestDurationByDispatchCountHistogramBuckets),
 			req.GetConsistency().String(),
+			storeLabel,
+			modelLabel,
 		).Observe(float64(endTime))
 
 		if s.authorizer.AccessControlStoreID() == req.GetStoreId() {
@@ -126,7 +157,7 @@ func (s *Server) Check(ctx context.Context, req *openfgav1.CheckRequest) (*openf
 		}
 
 		if wasRequestThrottled {
-			throttledRequestCounter.WithLabelValues(s.serviceName, methodName).Inc()
+			throttledRequestCounter.WithLabelValues(s.serviceName, methodName, storeLabel, modelLabel).Inc()
 		}
 		grpc_ctxtags.Extract(ctx).Set("request.throttled", wasRequestThrottled)
 	}
@@ -135,7 +166,7 @@ func (s *Server) Check(ctx context.Context, req *openfgav1.CheckRequest) (*openf
 		telemetry.TraceError(span, err)
 		finalErr := commands.CheckCommandErrorToServerError(err)
 		if errors.Is(finalErr, serverErrors.ErrThrottledTimeout) {
-			throttledRequestCounter.WithLabelValues(s.serviceName, methodName).Inc()
+			throttledRequestCounter.WithLabelValues(s.serviceName, methodName, storeLabel, modelLabel).Inc()
 		}
 		// should we define all metrics in one place that is accessible from everywhere (including LocalChecker!)
 		// and add a wrapper helper that automatically injects the service name tag?
@@ -148,6 +179,20 @@ func (s *Server) Check(ctx context.Context, req *openfgav1.CheckRequest) (*openf
 		attribute.Bool("cycle_detected", resp.GetCycleDetected()),
 		attribute.Bool("allowed", resp.GetAllowed()))
 
+	allowed := resp.GetAllowed()
+	s.logDecision(ctx, &decisionlog.Decision{
+		Method:               apimethod.Check.String(),
+		StoreID:              storeID,
+		AuthorizationModelID: typesys.GetAuthorizationModelID(),
+		User:                 tk.GetUser(),
+		Relation:             tk.GetRelation(),
+		Object:               tk.GetObject(),
+		Allowed:              &allowed,
+		ContextualTupleCount: len(req.GetContextualTuples().GetTupleKeys()),
+		DispatchCount:        rawDispatchCount,
+		DatastoreQueryCount:  resp.GetResolutionMetadata().DatastoreQueryCount,
+	}, req.GetContext())
+
 	res := &openfgav1.CheckResponse{
 		Allowed: resp.Allowed,
 	}
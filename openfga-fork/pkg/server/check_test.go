@@ -1,13 +1,24 @@
 package server
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
 
 	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+
+	"github.com/openfga/openfga/internal/condition/contextprovider"
+	"github.com/openfga/openfga/internal/utils/apimethod"
+	"github.com/openfga/openfga/pkg/decisionlog"
+	"github.com/openfga/openfga/pkg/slowlog"
+	"github.com/openfga/openfga/pkg/tuple"
 )
 
 func TestCheck_Validation(t *testing.T) {
@@ -82,3 +93,186 @@ func TestCheck_Validation(t *testing.T) {
 		})
 	}
 }
+
+func TestCheck_ConditionContextProviders(t *testing.T) {
+	ctx := context.Background()
+
+	trustedIP := contextprovider.ProviderFunc(func(context.Context) map[string]*structpb.Value {
+		return map[string]*structpb.Value{"user_ip": structpb.NewStringValue("10.0.0.1")}
+	})
+	s, _, storeID := newTestServer(t, WithConditionContextProviders(trustedIP))
+
+	writeTestModel(t, s, storeID, `
+		model
+			schema 1.1
+		type user
+		type document
+			relations
+				define viewer: [user with in_network]
+		condition in_network(user_ip: ipaddress, cidr: string) {
+			user_ip.in_cidr(cidr)
+		}`)
+
+	tupleCondition, err := structpb.NewStruct(map[string]any{"cidr": "10.0.0.0/8"})
+	require.NoError(t, err)
+	_, err = s.Write(ctx, &openfgav1.WriteRequest{
+		StoreId: storeID,
+		Writes: &openfgav1.WriteRequestWrites{TupleKeys: []*openfgav1.TupleKey{
+			tuple.NewTupleKeyWithCondition("document:plan", "viewer", "user:anne", "in_network", tupleCondition),
+		}},
+	})
+	require.NoError(t, err)
+
+	// The caller claims to be outside the network, but the trusted value wins.
+	callerContext, err := structpb.NewStruct(map[string]any{"user_ip": "192.168.0.1"})
+	require.NoError(t, err)
+	resp, err := s.Check(ctx, &openfgav1.CheckRequest{
+		StoreId:  storeID,
+		TupleKey: tuple.NewCheckRequestTupleKey("document:plan", "viewer", "user:anne"),
+		Context:  callerContext,
+	})
+	require.NoError(t, err)
+	require.True(t, resp.GetAllowed())
+
+	listResp, err := s.ListObjects(ctx, &openfgav1.ListObjectsRequest{
+		StoreId:  storeID,
+		Type:     "document",
+		Relation: "viewer",
+		User:     "user:anne",
+		Context:  callerContext,
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"document:plan"}, listResp.GetObjects())
+}
+
+func TestDecisionLog(t *testing.T) {
+	ctx := context.Background()
+
+	var buf bytes.Buffer
+	decisionLogger := decisionlog.NewLogger(decisionlog.NewWriterSink(&buf), decisionlog.WithRedactedFields(decisionlog.FieldObjects))
+
+	s, _, storeID := newTestServer(t, WithDecisionLogger(decisionLogger))
+
+	modelID := writeTestModel(t, s, storeID, `
+		model
+			schema 1.1
+		type user
+		type document
+			relations
+				define viewer: [user]`)
+
+	_, err := s.Write(ctx, &openfgav1.WriteRequest{
+		StoreId: storeID,
+		Writes: &openfgav1.WriteRequestWrites{TupleKeys: []*openfgav1.TupleKey{
+			tuple.NewTupleKey("document:plan", "viewer", "user:anne"),
+		}},
+	})
+	require.NoError(t, err)
+
+	_, err = s.Check(ctx, &openfgav1.CheckRequest{
+		StoreId:  storeID,
+		TupleKey: tuple.NewCheckRequestTupleKey("document:plan", "viewer", "user:anne"),
+		ContextualTuples: &openfgav1.ContextualTupleKeys{TupleKeys: []*openfgav1.TupleKey{
+			tuple.NewTupleKey("document:budget", "viewer", "user:anne"),
+		}},
+	})
+	require.NoError(t, err)
+
+	_, err = s.BatchCheck(ctx, &openfgav1.BatchCheckRequest{
+		StoreId: storeID,
+		Checks: []*openfgav1.BatchCheckItem{{
+			TupleKey:      &openfgav1.CheckRequestTupleKey{Object: "document:plan", Relation: "viewer", User: "user:bob"},
+			CorrelationId: "bob",
+		}},
+	})
+	require.NoError(t, err)
+
+	listResp, err := s.ListObjects(ctx, &openfgav1.ListObjectsRequest{
+		StoreId:  storeID,
+		Type:     "document",
+		Relation: "viewer",
+		User:     "user:anne",
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"document:plan"}, listResp.GetObjects())
+
+	var decisions []decisionlog.Decision
+	decoder := json.NewDecoder(&buf)
+	for decoder.More() {
+		var decision decisionlog.Decision
+		require.NoError(t, decoder.Decode(&decision))
+		decisions = append(decisions, decision)
+	}
+	require.Len(t, decisions, 3)
+
+	check := decisions[0]
+	require.Equal(t, apimethod.Check.String(), check.Method)
+	require.Equal(t, storeID, check.StoreID)
+	require.Equal(t, modelID, check.AuthorizationModelID)
+	require.Equal(t, "user:anne", check.User)
+	require.Equal(t, "document:plan", check.Object)
+	require.True(t, *check.Allowed)
+	require.Equal(t, 1, check.ContextualTupleCount)
+
+	batchCheck := decisions[1]
+	require.Equal(t, apimethod.BatchCheck.String(), batchCheck.Method)
+	require.Equal(t, "bob", batchCheck.CorrelationID)
+	require.False(t, *batchCheck.Allowed)
+
+	listObjects := decisions[2]
+	require.Equal(t, apimethod.ListObjects.String(), listObjects.Method)
+	require.Equal(t, "document", listObjects.ObjectType)
+	require.Equal(t, []string{decisionlog.Redacted}, listObjects.Objects)
+}
+
+func TestSlowLog(t *testing.T) {
+	ctx := context.Background()
+
+	// A zero threshold records every Check, while ListObjects is never slow enough.
+	slowLog := slowlog.NewLog(10, slowlog.WithThreshold(time.Hour), slowlog.WithMethodThresholds(map[string]time.Duration{
+		apimethod.Check.String(): 0,
+	}))
+
+	s, _, storeID := newTestServer(t, WithSlowLog(slowLog))
+
+	modelID := writeTestModel(t, s, storeID, `
+		model
+			schema 1.1
+		type user
+		type folder
+			relations
+				define viewer: [user] or viewer from parent
+				define parent: [folder]`)
+
+	_, err := s.Write(ctx, &openfgav1.WriteRequest{
+		StoreId: storeID,
+		Writes: &openfgav1.WriteRequestWrites{TupleKeys: []*openfgav1.TupleKey{
+			tuple.NewTupleKey("folder:parent", "viewer", "user:anne"),
+			tuple.NewTupleKey("folder:child", "parent", "folder:parent"),
+		}},
+	})
+	require.NoError(t, err)
+
+	_, err = s.Check(ctx, &openfgav1.CheckRequest{
+		StoreId:  storeID,
+		TupleKey: tuple.NewCheckRequestTupleKey("folder:child", "viewer", "user:anne"),
+	})
+	require.NoError(t, err)
+
+	_, err = s.ListObjects(ctx, &openfgav1.ListObjectsRequest{
+		StoreId:  storeID,
+		Type:     "folder",
+		Relation: "viewer",
+		User:     "user:anne",
+	})
+	require.NoError(t, err)
+
+	entries := slowLog.Entries()
+	require.Len(t, entries, 1)
+	require.Equal(t, apimethod.Check.String(), entries[0].Method)
+	require.Equal(t, storeID, entries[0].StoreID)
+	require.Equal(t, modelID, entries[0].AuthorizationModelID)
+	require.Equal(t, []string{"recursive"}, entries[0].Resolvers)
+	require.Positive(t, entries[0].DatastoreQueryCount)
+	require.Contains(t, string(entries[0].Request), "folder:child")
+}
@@ -0,0 +1,380 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+
+	serverconfig "github.com/openfga/openfga/pkg/server/config"
+	serverErrors "github.com/openfga/openfga/pkg/server/errors"
+	"github.com/openfga/openfga/pkg/storage"
+	"github.com/openfga/openfga/pkg/storage/storagewrappers"
+	"github.com/openfga/openfga/pkg/tuple"
+	"github.com/openfga/openfga/pkg/typesystem"
+)
+
+const (
+	// AccessGained marks a permission that exists at the end of the reviewed range but not at its start.
+	AccessGained = "gained"
+	// AccessLost marks a permission that exists at the start of the reviewed range but not at its end.
+	AccessLost = "lost"
+)
+
+// AccessReviewResolver evaluates authorization queries against the tuples returned by ds and the contextual
+// tuples of the request, which lets a query observe the tuples of a store as they were at some point in time,
+// or as they would be after a write. The server provides an implementation that bypasses the check cache,
+// because the results do not reflect the current tuples. ListObjects and ListUsers also report whether they
+// stopped at their deadline or result limit, in which case their results may be incomplete.
+type AccessReviewResolver interface {
+	Check(ctx context.Context, ds storage.RelationshipTupleReader, req *openfgav1.CheckRequest) (bool, error)
+	ListObjects(ctx context.Context, ds storage.RelationshipTupleReader, req *openfgav1.ListObjectsRequest) (objects []string, truncated bool, err error)
+	ListUsers(ctx context.Context, ds storage.RelationshipTupleReader, req *openfgav1.ListUsersRequest) (users []*openfgav1.User, truncated bool, err error)
+}
+
+// ErrAccessReviewIncomplete is returned when a ListObjects or ListUsers query of an access review stopped at its
+// deadline or result limit. The review fails rather than miss permissions that were gained or lost.
+var ErrAccessReviewIncomplete = status.Error(codes.DeadlineExceeded, "the access review is incomplete because the objects or users of some subjects could not all be listed within the ListObjects and ListUsers deadlines; review a shorter range or fewer relations")
+
+// AccessReviewRequest selects the permissions compared between two positions of the changelog of a store.
+type AccessReviewRequest struct {
+	StoreID              string
+	AuthorizationModelID string
+	// ObjectType is the type of the objects whose permissions are reviewed. Required.
+	ObjectType string
+	// Relations are the reviewed relations of ObjectType. If empty, all relations are reviewed.
+	Relations []string
+	// From is the start of the review, as a changelog ULID or an RFC 3339 timestamp. Required.
+	From string
+	// To is the end of the review, as a changelog ULID or an RFC 3339 timestamp. If empty, the review ends now.
+	To string
+}
+
+// Validate checks that the object type and the start of the review are set, and that the review ends after it starts.
+// The object type is only checked against the model when the review is executed.
+func (r *AccessReviewRequest) Validate() error {
+	if err := validateStoreID(r.StoreID); err != nil {
+		return err
+	}
+	if r.ObjectType == "" {
+		return errors.New("object_type is required")
+	}
+	if r.From == "" {
+		return errors.New("from is required")
+	}
+	from, err := parseChangelogPosition(r.From)
+	if err != nil {
+		return fmt.Errorf("invalid from: %w", err)
+	}
+	if r.To != "" {
+		to, err := parseChangelogEnd(r.To)
+		if err != nil {
+			return fmt.Errorf("invalid to: %w", err)
+		}
+		if to.Compare(from) <= 0 {
+			return errors.New("from must be before to")
+		}
+	}
+	return nil
+}
+
+// parseChangelogPosition parses a changelog ULID or an RFC 3339 timestamp. A timestamp is
+// converted to the smallest ULID of its millisecond, the way ReadChanges treats a start time.
+func parseChangelogPosition(position string) (ulid.ULID, error) {
+	if id, err := ulid.ParseStrict(position); err == nil {
+		return id, nil
+	}
+	t, err := time.Parse(time.RFC3339Nano, position)
+	if err != nil {
+		return ulid.ULID{}, fmt.Errorf("'%s' is neither a ULID nor an RFC 3339 timestamp", position)
+	}
+	return ulid.New(ulid.Timestamp(t), nil)
+}
+
+// parseChangelogEnd parses the end of a range of the changelog like parseChangelogPosition, except that a
+// timestamp is converted to the largest ULID of its millisecond, so that the changes made during that
+// millisecond are in the range.
+func parseChangelogEnd(position string) (ulid.ULID, error) {
+	if id, err := ulid.ParseStrict(position); err == nil {
+		return id, nil
+	}
+	id, err := parseChangelogPosition(position)
+	if err != nil {
+		return ulid.ULID{}, err
+	}
+	if err := id.SetEntropy(bytes.Repeat([]byte{0xff}, 10)); err != nil {
+		return ulid.ULID{}, err
+	}
+	return id, nil
+}
+
+// AccessReviewRow is a (user, relation, object) permission that was gained or lost.
+type AccessReviewRow struct {
+	// Change is AccessGained or AccessLost.
+	Change   string `json:"change"`
+	User     string `json:"user"`
+	Relation string `json:"relation"`
+	Object   string `json:"object"`
+}
+
+// AccessReviewQuery reports the effective permissions gained and lost between two positions of the
+// changelog. The tuples at each position are rebuilt by undoing, on top of the current tuples, the
+// changes made after it. The users whose permissions may have changed are derived from the changed
+// tuples that the reviewed relations depend on, according to the weighted graph of the model; their
+// objects are listed at both positions and every difference is confirmed with Check.
+type AccessReviewQuery struct {
+	datastore  storage.OpenFGADatastore
+	resolver   AccessReviewResolver
+	maxChanges uint32
+}
+
+type AccessReviewQueryOption func(*AccessReviewQuery)
+
+// WithAccessReviewMaxChanges see server.WithAccessReviewMaxChanges.
+func WithAccessReviewMaxChanges(maxChanges uint32) AccessReviewQueryOption {
+	return func(q *AccessReviewQuery) {
+		q.maxChanges = maxChanges
+	}
+}
+
+func NewAccessReviewQuery(datastore storage.OpenFGADatastore, resolver AccessReviewResolver, opts ...AccessReviewQueryOption) *AccessReviewQuery {
+	q := &AccessReviewQuery{
+		datastore:  datastore,
+		resolver:   resolver,
+		maxChanges: serverconfig.DefaultAccessReviewMaxChanges,
+	}
+
+	for _, opt := range opts {
+		opt(q)
+	}
+	return q
+}
+
+// Execute computes the permission changes and calls emit for every row, ordered by object, relation and user.
+// It fails with ErrAccessReviewIncomplete, without calling emit, if some permissions could not be resolved.
+func (q *AccessReviewQuery) Execute(
+	ctx context.Context,
+	typesys *typesystem.TypeSystem,
+	req *AccessReviewRequest,
+	emit func(*AccessReviewRow) error,
+) error {
+	relations, err := reviewedRelations(typesys, req)
+	if err != nil {
+		return err
+	}
+
+	from, err := parseChangelogPosition(req.From)
+	if err != nil {
+		return serverErrors.ValidationError(err)
+	}
+	// A zero to means that the review ends now.
+	var to ulid.ULID
+	if req.To != "" {
+		to, err = parseChangelogEnd(req.To)
+		if err != nil {
+			return serverErrors.ValidationError(err)
+		}
+	}
+
+	diff, err := q.buildDiff(ctx, typesys, req.StoreID, from, to)
+	if err != nil {
+		return err
+	}
+
+	rows, err := diff.rows(ctx, req.ObjectType, relations)
+	if err != nil {
+		return err
+	}
+	if diff.truncated {
+		return ErrAccessReviewIncomplete
+	}
+	for _, row := range rows {
+		if err := emit(row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func reviewedRelations(typesys *typesystem.TypeSystem, req *AccessReviewRequest) ([]string, error) {
+	relations, err := typesys.GetRelations(req.ObjectType)
+	if err != nil {
+		return nil, serverErrors.ValidationError(err)
+	}
+
+	if len(req.Relations) == 0 {
+		return sortedKeys(relations), nil
+	}
+
+	for _, relation := range req.Relations {
+		if _, ok := relations[relation]; !ok {
+			return nil, serverErrors.ValidationError(
+				fmt.Errorf("relation '%s' is not defined for type '%s'", relation, req.ObjectType),
+			)
+		}
+	}
+	return req.Relations, nil
+}
+
+// buildDiff reads the changes made after from, and rebuilds the tuples at from and at to.
+// For every changed tuple, the first change after a position tells whether the tuple existed
+// at that position: a write means it did not, a delete means it did.
+func (q *AccessReviewQuery) buildDiff(ctx context.Context, typesys *typesystem.TypeSystem, storeID string, from, to ulid.ULID) (*permissionDiff, error) {
+	all := func([]*openfgav1.TupleChange) bool { return false }
+
+	// The changes of the range are read up to the ULID of to, since several changes can be made within the
+	// millisecond of a ULID. The changes made after to are read from to.
+	changes, err := q.readChanges(ctx, storeID, from.String(), to, false, all)
+	if err != nil {
+		return nil, err
+	}
+	var changesAfter []*openfgav1.TupleChange
+	if to != (ulid.ULID{}) {
+		changesAfter, err = q.readChanges(ctx, storeID, to.String(), ulid.ULID{}, false, all)
+		if err != nil {
+			return nil, err
+		}
+		if err := q.checkChangeCount(len(changes) + len(changesAfter)); err != nil {
+			return nil, err
+		}
+	}
+
+	atFrom := make(map[string]*openfgav1.TupleKey)
+	atTo := make(map[string]*openfgav1.TupleKey)
+	lastWrite := make(map[string]*openfgav1.TupleKey)
+	// deleted are the tuples that existed at a position, but whose condition was redacted from the changelog when they were deleted.
+	deleted := make(map[string][]*openfgav1.TupleKey)
+	seen := make(map[string]struct{})
+	var changed []*openfgav1.TupleKey
+
+	existedBefore := func(change *openfgav1.TupleChange, key string) *openfgav1.TupleKey {
+		if change.GetOperation() != openfgav1.TupleOperation_TUPLE_OPERATION_DELETE {
+			return nil
+		}
+		if tk, ok := lastWrite[key]; ok {
+			return tk
+		}
+		tk := tuple.NewTupleKey(change.GetTupleKey().GetObject(), change.GetTupleKey().GetRelation(), change.GetTupleKey().GetUser())
+		deleted[key] = append(deleted[key], tk)
+		return tk
+	}
+
+	for _, change := range changes {
+		key := tuple.TupleKeyToString(change.GetTupleKey())
+		if _, ok := atFrom[key]; !ok {
+			atFrom[key] = existedBefore(change, key)
+		}
+		if _, ok := seen[key]; !ok {
+			seen[key] = struct{}{}
+			changed = append(changed, change.GetTupleKey())
+		}
+		if change.GetOperation() == openfgav1.TupleOperation_TUPLE_OPERATION_WRITE {
+			lastWrite[key] = change.GetTupleKey()
+		}
+	}
+	for _, change := range changesAfter {
+		key := tuple.TupleKeyToString(change.GetTupleKey())
+		if _, ok := atFrom[key]; !ok {
+			atFrom[key] = existedBefore(change, key)
+		}
+		if _, ok := atTo[key]; !ok {
+			atTo[key] = existedBefore(change, key)
+		}
+	}
+
+	if len(deleted) > 0 && len(typesys.GetConditions()) > 0 {
+		if err := q.restoreConditions(ctx, storeID, from.String(), deleted); err != nil {
+			return nil, err
+		}
+	}
+
+	return &permissionDiff{
+		resolver: q.resolver,
+		typesys:  typesys,
+		storeID:  storeID,
+		from:     tupleState{reader: storagewrappers.NewPointInTimeTupleReader(q.datastore, atFrom)},
+		to:       tupleState{reader: storagewrappers.NewPointInTimeTupleReader(q.datastore, atTo)},
+		changed:  changed,
+	}, nil
+}
+
+// restoreConditions reads the changelog backwards from position to find the writes of the deleted
+// tuples, and copies their conditions. A tuple whose write is not found is kept without condition.
+func (q *AccessReviewQuery) restoreConditions(ctx context.Context, storeID, position string, deleted map[string][]*openfgav1.TupleKey) error {
+	_, err := q.readChanges(ctx, storeID, position, ulid.ULID{}, true, func(page []*openfgav1.TupleChange) bool {
+		for _, change := range page {
+			key := tuple.TupleKeyToString(change.GetTupleKey())
+			tks, ok := deleted[key]
+			if !ok {
+				continue
+			}
+			for _, tk := range tks {
+				tk.Condition = change.GetTupleKey().GetCondition()
+			}
+			delete(deleted, key)
+		}
+		return len(deleted) == 0
+	})
+	return err
+}
+
+// readChanges reads the changelog from position until it ends, until its change with the ULID until unless
+// until is zero, or until done returns true. It fails if more than maxChanges changes have to be read.
+func (q *AccessReviewQuery) readChanges(ctx context.Context, storeID, position string, until ulid.ULID, sortDesc bool, done func([]*openfgav1.TupleChange) bool) ([]*openfgav1.TupleChange, error) {
+	var changes []*openfgav1.TupleChange
+	pageSize := storage.DefaultPageSize
+	for {
+		page, token, err := q.datastore.ReadChanges(ctx, storeID, storage.ReadChangesFilter{}, storage.ReadChangesOptions{
+			Pagination: storage.NewPaginationOptions(int32(pageSize), position),
+			SortDesc:   sortDesc,
+		})
+		if err != nil {
+			if errors.Is(err, storage.ErrNotFound) {
+				return changes, nil
+			}
+			return nil, serverErrors.HandleError("", err)
+		}
+
+		if until != (ulid.ULID{}) {
+			// The continuation token is the ULID of the last change of the page. The page that ends after
+			// until is read again one change at a time, to stop right after the change with the ULID until.
+			last, err := ulid.Parse(token)
+			if err != nil {
+				return nil, serverErrors.HandleError("", err)
+			}
+			if last.Compare(until) > 0 {
+				if pageSize == 1 {
+					return changes, nil
+				}
+				pageSize = 1
+				continue
+			}
+		}
+
+		changes = append(changes, page...)
+		if err := q.checkChangeCount(len(changes)); err != nil {
+			return nil, err
+		}
+		if done(page) || token == "" {
+			return changes, nil
+		}
+		position = token
+	}
+}
+
+// checkChangeCount fails if count changes are more than maxChanges.
+func (q *AccessReviewQuery) checkChangeCount(count int) error {
+	if q.maxChanges > 0 && uint32(count) > q.maxChanges {
+		return serverErrors.ValidationError(
+			fmt.Errorf("more than %d tuple changes must be read, which is the maximum allowed in an access review", q.maxChanges),
+		)
+	}
+	return nil
+}
@@ -0,0 +1,232 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+	"github.com/stretchr/testify/require"
+
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+	parser "github.com/openfga/language/pkg/go/transformer"
+
+	"github.com/openfga/openfga/pkg/storage"
+	"github.com/openfga/openfga/pkg/storage/memory"
+	"github.com/openfga/openfga/pkg/storage/storagewrappers"
+	"github.com/openfga/openfga/pkg/tuple"
+	"github.com/openfga/openfga/pkg/typesystem"
+)
+
+// directResolver resolves direct relationships only, and treats tuples with the 'never' condition as not granting access.
+type directResolver struct{}
+
+func (directResolver) Check(ctx context.Context, ds storage.RelationshipTupleReader, req *openfgav1.CheckRequest) (bool, error) {
+	ds = storagewrappers.NewCombinedTupleReader(ds, req.GetContextualTuples().GetTupleKeys())
+	t, err := ds.ReadUserTuple(ctx, req.GetStoreId(), tuple.ConvertCheckRequestTupleKeyToTupleKey(req.GetTupleKey()), storage.ReadUserTupleOptions{})
+	if errors.Is(err, storage.ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return t.GetKey().GetCondition().GetName() != "never", nil
+}
+
+func (directResolver) ListObjects(ctx context.Context, ds storage.RelationshipTupleReader, req *openfgav1.ListObjectsRequest) ([]string, bool, error) {
+	ds = storagewrappers.NewCombinedTupleReader(ds, req.GetContextualTuples().GetTupleKeys())
+	iter, err := ds.ReadStartingWithUser(ctx, req.GetStoreId(), storage.ReadStartingWithUserFilter{
+		ObjectType: req.GetType(),
+		Relation:   req.GetRelation(),
+		UserFilter: []*openfgav1.ObjectRelation{{Object: req.GetUser()}},
+	}, storage.ReadStartingWithUserOptions{})
+	if err != nil {
+		return nil, false, err
+	}
+	defer iter.Stop()
+
+	var objects []string
+	for {
+		t, err := iter.Next(ctx)
+		if errors.Is(err, storage.ErrIteratorDone) {
+			return objects, false, nil
+		}
+		if err != nil {
+			return nil, false, err
+		}
+		if t.GetKey().GetCondition().GetName() != "never" {
+			objects = append(objects, t.GetKey().GetObject())
+		}
+	}
+}
+
+func (directResolver) ListUsers(context.Context, storage.RelationshipTupleReader, *openfgav1.ListUsersRequest) ([]*openfgav1.User, bool, error) {
+	return nil, false, nil
+}
+
+// truncatedResolver is a directResolver whose ListObjects queries stop at their deadline.
+type truncatedResolver struct {
+	directResolver
+}
+
+func (r truncatedResolver) ListObjects(ctx context.Context, ds storage.RelationshipTupleReader, req *openfgav1.ListObjectsRequest) ([]string, bool, error) {
+	objects, _, err := r.directResolver.ListObjects(ctx, ds, req)
+	return objects, true, err
+}
+
+func TestAccessReviewQuery(t *testing.T) {
+	ctx := context.Background()
+	storeID := ulid.Make().String()
+
+	ds := memory.New()
+	t.Cleanup(ds.Close)
+
+	model := parser.MustTransformDSLToProto(`
+		model
+			schema 1.1
+		type user
+		type folder
+			relations
+				define viewer: [user]
+		type document
+			relations
+				define owner: [user]
+				define viewer: [user, user with never]
+		condition never(x: int) {
+			x < 0
+		}`)
+	typesys, err := typesystem.New(model)
+	require.NoError(t, err)
+
+	// position returns a timestamp that separates the changes written before and after it.
+	position := func() string {
+		time.Sleep(2 * time.Millisecond)
+		p := time.Now().UTC().Format(time.RFC3339Nano)
+		time.Sleep(2 * time.Millisecond)
+		return p
+	}
+	write := func(deletes []*openfgav1.TupleKeyWithoutCondition, writes []*openfgav1.TupleKey) {
+		require.NoError(t, ds.Write(ctx, storeID, deletes, writes))
+	}
+	deleteKey := func(object, relation, user string) []*openfgav1.TupleKeyWithoutCondition {
+		return []*openfgav1.TupleKeyWithoutCondition{{Object: object, Relation: relation, User: user}}
+	}
+
+	write(nil, []*openfgav1.TupleKey{
+		tuple.NewTupleKey("document:1", "viewer", "user:anne"),
+		tuple.NewTupleKey("document:2", "viewer", "user:bob"),
+		tuple.NewTupleKeyWithCondition("document:3", "viewer", "user:carl", "never", nil),
+	})
+	start := position()
+	write(deleteKey("document:1", "viewer", "user:anne"), []*openfgav1.TupleKey{
+		tuple.NewTupleKey("document:1", "viewer", "user:bob"),
+		tuple.NewTupleKey("document:1", "owner", "user:anne"),
+		tuple.NewTupleKey("folder:1", "viewer", "user:dave"),
+	})
+	write(deleteKey("document:3", "viewer", "user:carl"), nil)
+	middle := position()
+	write(deleteKey("document:2", "viewer", "user:bob"), []*openfgav1.TupleKey{
+		tuple.NewTupleKey("document:2", "viewer", "user:erin"),
+	})
+	// A tuple deleted and written back within the range is not a change.
+	write(deleteKey("document:1", "viewer", "user:bob"), nil)
+	write(nil, []*openfgav1.TupleKey{tuple.NewTupleKey("document:1", "viewer", "user:bob")})
+	end := position()
+	// Changes after the end of the range are ignored.
+	write(nil, []*openfgav1.TupleKey{tuple.NewTupleKey("document:4", "viewer", "user:frank")})
+
+	collectWith := func(resolver AccessReviewResolver, req *AccessReviewRequest, opts ...AccessReviewQueryOption) ([]AccessReviewRow, error) {
+		var rows []AccessReviewRow
+		err := NewAccessReviewQuery(ds, resolver, opts...).Execute(ctx, typesys, req, func(row *AccessReviewRow) error {
+			rows = append(rows, *row)
+			return nil
+		})
+		return rows, err
+	}
+	collect := func(t *testing.T, req *AccessReviewRequest, opts ...AccessReviewQueryOption) ([]AccessReviewRow, error) {
+		return collectWith(directResolver{}, req, opts...)
+	}
+
+	t.Run("whole_range", func(t *testing.T) {
+		rows, err := collect(t, &AccessReviewRequest{StoreID: storeID, ObjectType: "document", From: start, To: end})
+		require.NoError(t, err)
+		require.Equal(t, []AccessReviewRow{
+			{Change: AccessGained, User: "user:anne", Relation: "owner", Object: "document:1"},
+			{Change: AccessLost, User: "user:anne", Relation: "viewer", Object: "document:1"},
+			{Change: AccessGained, User: "user:bob", Relation: "viewer", Object: "document:1"},
+			{Change: AccessLost, User: "user:bob", Relation: "viewer", Object: "document:2"},
+			{Change: AccessGained, User: "user:erin", Relation: "viewer", Object: "document:2"},
+		}, rows)
+	})
+
+	t.Run("partial_range_and_relation", func(t *testing.T) {
+		rows, err := collect(t, &AccessReviewRequest{StoreID: storeID, ObjectType: "document", Relations: []string{"viewer"}, From: middle, To: end})
+		require.NoError(t, err)
+		require.Equal(t, []AccessReviewRow{
+			{Change: AccessLost, User: "user:bob", Relation: "viewer", Object: "document:2"},
+			{Change: AccessGained, User: "user:erin", Relation: "viewer", Object: "document:2"},
+		}, rows)
+	})
+
+	t.Run("range_ending_at_a_change", func(t *testing.T) {
+		// The delete of document:2#viewer@user:bob and the write of document:2#viewer@user:erin are made in the
+		// same write, so usually within the same millisecond. A range that ends at the delete excludes the write.
+		middleULID, err := parseChangelogPosition(middle)
+		require.NoError(t, err)
+		changes, deleteULID, err := ds.ReadChanges(ctx, storeID, storage.ReadChangesFilter{}, storage.ReadChangesOptions{
+			Pagination: storage.NewPaginationOptions(1, middleULID.String()),
+		})
+		require.NoError(t, err)
+		require.Equal(t, openfgav1.TupleOperation_TUPLE_OPERATION_DELETE, changes[0].GetOperation())
+		require.Equal(t, "user:bob", changes[0].GetTupleKey().GetUser())
+
+		rows, err := collect(t, &AccessReviewRequest{StoreID: storeID, ObjectType: "document", From: middle, To: deleteULID})
+		require.NoError(t, err)
+		require.Equal(t, []AccessReviewRow{
+			{Change: AccessLost, User: "user:bob", Relation: "viewer", Object: "document:2"},
+		}, rows)
+	})
+
+	t.Run("range_until_now", func(t *testing.T) {
+		rows, err := collect(t, &AccessReviewRequest{StoreID: storeID, ObjectType: "document", From: end})
+		require.NoError(t, err)
+		require.Equal(t, []AccessReviewRow{
+			{Change: AccessGained, User: "user:frank", Relation: "viewer", Object: "document:4"},
+		}, rows)
+	})
+
+	t.Run("no_changes", func(t *testing.T) {
+		rows, err := collect(t, &AccessReviewRequest{StoreID: storeID, ObjectType: "document", From: position()})
+		require.NoError(t, err)
+		require.Empty(t, rows)
+	})
+
+	t.Run("unknown_relation", func(t *testing.T) {
+		_, err := collect(t, &AccessReviewRequest{StoreID: storeID, ObjectType: "folder", Relations: []string{"owner"}, From: start})
+		require.ErrorContains(t, err, "relation 'owner' is not defined for type 'folder'")
+	})
+
+	t.Run("too_many_changes", func(t *testing.T) {
+		_, err := collect(t, &AccessReviewRequest{StoreID: storeID, ObjectType: "document", From: start}, WithAccessReviewMaxChanges(3))
+		require.ErrorContains(t, err, "more than 3 tuple changes")
+	})
+
+	t.Run("incomplete", func(t *testing.T) {
+		rows, err := collectWith(truncatedResolver{}, &AccessReviewRequest{StoreID: storeID, ObjectType: "document", From: start, To: end})
+		require.ErrorIs(t, err, ErrAccessReviewIncomplete)
+		require.Empty(t, rows)
+	})
+}
+
+func TestAccessReviewRequestValidate(t *testing.T) {
+	storeID := ulid.Make().String()
+
+	require.NoError(t, (&AccessReviewRequest{StoreID: storeID, ObjectType: "document", From: "2024-01-01T00:00:00Z"}).Validate())
+	require.NoError(t, (&AccessReviewRequest{StoreID: storeID, ObjectType: "document", From: ulid.Make().String()}).Validate())
+	require.ErrorContains(t, (&AccessReviewRequest{StoreID: "abc", ObjectType: "document", From: "2024-01-01T00:00:00Z"}).Validate(), "invalid store_id")
+	require.ErrorContains(t, (&AccessReviewRequest{StoreID: storeID, From: "2024-01-01T00:00:00Z"}).Validate(), "object_type is required")
+	require.ErrorContains(t, (&AccessReviewRequest{StoreID: storeID, ObjectType: "document"}).Validate(), "from is required")
+	require.ErrorContains(t, (&AccessReviewRequest{StoreID: storeID, ObjectType: "document", From: "yesterday"}).Validate(), "neither a ULID nor an RFC 3339 timestamp")
+	require.ErrorContains(t, (&AccessReviewRequest{StoreID: storeID, ObjectType: "document", From: "2024-01-02T00:00:00Z", To: "2024-01-01T00:00:00Z"}).Validate(), "from must be before to")
+}
@@ -0,0 +1,185 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/oklog/ulid/v2"
+
+	serverErrors "github.com/openfga/openfga/pkg/server/errors"
+	"github.com/openfga/openfga/pkg/storage"
+)
+
+// ReadActiveAuthorizationModelRequest asks which model is used for a store when requests omit the model ID.
+type ReadActiveAuthorizationModelRequest struct {
+	StoreID string `json:"store_id"`
+}
+
+// Validate returns an error if the store ID is not a valid ULID.
+func (r *ReadActiveAuthorizationModelRequest) Validate() error {
+	return validateStoreID(r.StoreID)
+}
+
+// WriteActiveAuthorizationModelRequest pins a store to an existing model.
+// An empty AuthorizationModelID removes the pin, so that the latest model is used again.
+type WriteActiveAuthorizationModelRequest struct {
+	StoreID              string `json:"store_id"`
+	AuthorizationModelID string `json:"authorization_model_id"`
+}
+
+// Validate returns an error if the store ID or a non-empty model ID is not a valid ULID.
+func (r *WriteActiveAuthorizationModelRequest) Validate() error {
+	if err := validateStoreID(r.StoreID); err != nil {
+		return err
+	}
+	if r.AuthorizationModelID == "" {
+		return nil
+	}
+	if _, err := ulid.Parse(r.AuthorizationModelID); err != nil {
+		return fmt.Errorf("invalid authorization_model_id '%s'", r.AuthorizationModelID)
+	}
+	return nil
+}
+
+// RollbackActiveAuthorizationModelRequest pins a store to the model written just before the one it currently uses.
+type RollbackActiveAuthorizationModelRequest struct {
+	StoreID string `json:"store_id"`
+}
+
+// Validate returns an error if the store ID is not a valid ULID.
+func (r *RollbackActiveAuthorizationModelRequest) Validate() error {
+	return validateStoreID(r.StoreID)
+}
+
+// ActiveAuthorizationModelResponse describes the model used for a store when requests omit the model ID.
+type ActiveAuthorizationModelResponse struct {
+	AuthorizationModelID string `json:"authorization_model_id"`
+	// Pinned is false when the store has no pin and AuthorizationModelID is the latest model.
+	Pinned bool `json:"pinned"`
+}
+
+func validateStoreID(storeID string) error {
+	if _, err := ulid.Parse(storeID); err != nil {
+		return fmt.Errorf("invalid store_id '%s'", storeID)
+	}
+	return nil
+}
+
+// resolveActiveAuthorizationModelID returns the ID of the model the store is pinned to or,
+// if there is no pin, the ID of the latest model.
+func resolveActiveAuthorizationModelID(ctx context.Context, backend storage.AuthorizationModelReadBackend, storeID string) (*ActiveAuthorizationModelResponse, error) {
+	modelID, err := backend.ReadActiveAuthorizationModelID(ctx, storeID)
+	if err == nil {
+		return &ActiveAuthorizationModelResponse{AuthorizationModelID: modelID, Pinned: true}, nil
+	}
+	if !errors.Is(err, storage.ErrNotFound) {
+		return nil, serverErrors.HandleError("", err)
+	}
+
+	model, err := backend.FindLatestAuthorizationModel(ctx, storeID)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, serverErrors.LatestAuthorizationModelNotFound(storeID)
+		}
+		return nil, serverErrors.HandleError("", err)
+	}
+
+	return &ActiveAuthorizationModelResponse{AuthorizationModelID: model.GetId(), Pinned: false}, nil
+}
+
+// ReadActiveAuthorizationModelQuery resolves the model used for a store when requests omit the model ID.
+type ReadActiveAuthorizationModelQuery struct {
+	backend storage.AuthorizationModelReadBackend
+}
+
+func NewReadActiveAuthorizationModelQuery(backend storage.AuthorizationModelReadBackend) *ReadActiveAuthorizationModelQuery {
+	return &ReadActiveAuthorizationModelQuery{backend: backend}
+}
+
+func (q *ReadActiveAuthorizationModelQuery) Execute(ctx context.Context, req *ReadActiveAuthorizationModelRequest) (*ActiveAuthorizationModelResponse, error) {
+	return resolveActiveAuthorizationModelID(ctx, q.backend, req.StoreID)
+}
+
+// WriteActiveAuthorizationModelCommand pins a store to one of its models, or removes the pin.
+type WriteActiveAuthorizationModelCommand struct {
+	backend storage.AuthorizationModelBackend
+}
+
+func NewWriteActiveAuthorizationModelCommand(backend storage.AuthorizationModelBackend) *WriteActiveAuthorizationModelCommand {
+	return &WriteActiveAuthorizationModelCommand{backend: backend}
+}
+
+func (c *WriteActiveAuthorizationModelCommand) Execute(ctx context.Context, req *WriteActiveAuthorizationModelRequest) (*ActiveAuthorizationModelResponse, error) {
+	modelID := req.AuthorizationModelID
+	if modelID != "" {
+		// Only models that exist in the store can be pinned.
+		if _, err := c.backend.ReadAuthorizationModel(ctx, req.StoreID, modelID); err != nil {
+			if errors.Is(err, storage.ErrNotFound) {
+				return nil, serverErrors.AuthorizationModelNotFound(modelID)
+			}
+			return nil, serverErrors.HandleError("", err)
+		}
+	}
+
+	if err := c.backend.WriteActiveAuthorizationModelID(ctx, req.StoreID, modelID); err != nil {
+		return nil, serverErrors.HandleError("", err)
+	}
+
+	return resolveActiveAuthorizationModelID(ctx, c.backend, req.StoreID)
+}
+
+// RollbackActiveAuthorizationModelCommand pins a store to the model that was written immediately
+// before the model it currently uses, whether that one is pinned or is the latest.
+type RollbackActiveAuthorizationModelCommand struct {
+	backend storage.AuthorizationModelBackend
+}
+
+func NewRollbackActiveAuthorizationModelCommand(backend storage.AuthorizationModelBackend) *RollbackActiveAuthorizationModelCommand {
+	return &RollbackActiveAuthorizationModelCommand{backend: backend}
+}
+
+func (c *RollbackActiveAuthorizationModelCommand) Execute(ctx context.Context, req *RollbackActiveAuthorizationModelRequest) (*ActiveAuthorizationModelResponse, error) {
+	current, err := resolveActiveAuthorizationModelID(ctx, c.backend, req.StoreID)
+	if err != nil {
+		return nil, err
+	}
+
+	previousModelID, err := c.findPreviousModelID(ctx, req.StoreID, current.AuthorizationModelID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.backend.WriteActiveAuthorizationModelID(ctx, req.StoreID, previousModelID); err != nil {
+		return nil, serverErrors.HandleError("", err)
+	}
+
+	return &ActiveAuthorizationModelResponse{AuthorizationModelID: previousModelID, Pinned: true}, nil
+}
+
+// findPreviousModelID walks the models of the store from newest to oldest and returns the
+// first one that is older than modelID.
+func (c *RollbackActiveAuthorizationModelCommand) findPreviousModelID(ctx context.Context, storeID, modelID string) (string, error) {
+	continuationToken := ""
+	for {
+		models, token, err := c.backend.ReadAuthorizationModels(ctx, storeID, storage.ReadAuthorizationModelsOptions{
+			Pagination: storage.NewPaginationOptions(storage.DefaultPageSize, continuationToken),
+		})
+		if err != nil {
+			return "", serverErrors.HandleError("", err)
+		}
+
+		for _, model := range models {
+			if model.GetId() < modelID {
+				return model.GetId(), nil
+			}
+		}
+
+		if token == "" {
+			return "", serverErrors.ValidationError(
+				fmt.Errorf("no authorization model older than '%s' found in store '%s'", modelID, storeID),
+			)
+		}
+		continuationToken = token
+	}
+}
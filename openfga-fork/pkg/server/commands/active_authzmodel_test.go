@@ -0,0 +1,210 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/oklog/ulid/v2"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+
+	mockstorage "github.com/openfga/openfga/internal/mocks"
+	serverErrors "github.com/openfga/openfga/pkg/server/errors"
+	"github.com/openfga/openfga/pkg/storage"
+)
+
+func TestReadActiveAuthorizationModelQuery(t *testing.T) {
+	storeID := ulid.Make().String()
+	modelID := ulid.Make().String()
+
+	var tests = []struct {
+		name             string
+		setMock          func(*mockstorage.MockOpenFGADatastore)
+		expectedResponse *ActiveAuthorizationModelResponse
+		expectedError    error
+	}{
+		{
+			name: "returns_pinned_model",
+			setMock: func(mockDatastore *mockstorage.MockOpenFGADatastore) {
+				mockDatastore.EXPECT().ReadActiveAuthorizationModelID(gomock.Any(), storeID).Return(modelID, nil)
+			},
+			expectedResponse: &ActiveAuthorizationModelResponse{AuthorizationModelID: modelID, Pinned: true},
+		},
+		{
+			name: "returns_latest_model_when_not_pinned",
+			setMock: func(mockDatastore *mockstorage.MockOpenFGADatastore) {
+				mockDatastore.EXPECT().ReadActiveAuthorizationModelID(gomock.Any(), storeID).Return("", storage.ErrNotFound)
+				mockDatastore.EXPECT().FindLatestAuthorizationModel(gomock.Any(), storeID).Return(&openfgav1.AuthorizationModel{Id: modelID}, nil)
+			},
+			expectedResponse: &ActiveAuthorizationModelResponse{AuthorizationModelID: modelID, Pinned: false},
+		},
+		{
+			name: "returns_error_when_store_has_no_models",
+			setMock: func(mockDatastore *mockstorage.MockOpenFGADatastore) {
+				mockDatastore.EXPECT().ReadActiveAuthorizationModelID(gomock.Any(), storeID).Return("", storage.ErrNotFound)
+				mockDatastore.EXPECT().FindLatestAuthorizationModel(gomock.Any(), storeID).Return(nil, storage.ErrNotFound)
+			},
+			expectedError: serverErrors.LatestAuthorizationModelNotFound(storeID),
+		},
+		{
+			name: "returns_internal_error_when_lookup_fails",
+			setMock: func(mockDatastore *mockstorage.MockOpenFGADatastore) {
+				mockDatastore.EXPECT().ReadActiveAuthorizationModelID(gomock.Any(), storeID).Return("", errors.New("internal"))
+			},
+			expectedError: serverErrors.HandleError("", errors.New("internal")),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockController := gomock.NewController(t)
+			defer mockController.Finish()
+
+			mockDatastore := mockstorage.NewMockOpenFGADatastore(mockController)
+			test.setMock(mockDatastore)
+
+			resp, err := NewReadActiveAuthorizationModelQuery(mockDatastore).
+				Execute(context.Background(), &ReadActiveAuthorizationModelRequest{StoreID: storeID})
+			require.Equal(t, test.expectedError, err)
+			require.Equal(t, test.expectedResponse, resp)
+		})
+	}
+}
+
+func TestWriteActiveAuthorizationModelCommand(t *testing.T) {
+	storeID := ulid.Make().String()
+	modelID := ulid.Make().String()
+	latestModelID := ulid.Make().String()
+
+	var tests = []struct {
+		name             string
+		modelID          string
+		setMock          func(*mockstorage.MockOpenFGADatastore)
+		expectedResponse *ActiveAuthorizationModelResponse
+		expectedError    error
+	}{
+		{
+			name:    "pins_existing_model",
+			modelID: modelID,
+			setMock: func(mockDatastore *mockstorage.MockOpenFGADatastore) {
+				mockDatastore.EXPECT().ReadAuthorizationModel(gomock.Any(), storeID, modelID).Return(&openfgav1.AuthorizationModel{Id: modelID}, nil)
+				mockDatastore.EXPECT().WriteActiveAuthorizationModelID(gomock.Any(), storeID, modelID).Return(nil)
+				mockDatastore.EXPECT().ReadActiveAuthorizationModelID(gomock.Any(), storeID).Return(modelID, nil)
+			},
+			expectedResponse: &ActiveAuthorizationModelResponse{AuthorizationModelID: modelID, Pinned: true},
+		},
+		{
+			name:    "rejects_unknown_model",
+			modelID: modelID,
+			setMock: func(mockDatastore *mockstorage.MockOpenFGADatastore) {
+				mockDatastore.EXPECT().ReadAuthorizationModel(gomock.Any(), storeID, modelID).Return(nil, storage.ErrNotFound)
+			},
+			expectedError: serverErrors.AuthorizationModelNotFound(modelID),
+		},
+		{
+			name:    "empty_model_id_removes_pin",
+			modelID: "",
+			setMock: func(mockDatastore *mockstorage.MockOpenFGADatastore) {
+				mockDatastore.EXPECT().WriteActiveAuthorizationModelID(gomock.Any(), storeID, "").Return(nil)
+				mockDatastore.EXPECT().ReadActiveAuthorizationModelID(gomock.Any(), storeID).Return("", storage.ErrNotFound)
+				mockDatastore.EXPECT().FindLatestAuthorizationModel(gomock.Any(), storeID).Return(&openfgav1.AuthorizationModel{Id: latestModelID}, nil)
+			},
+			expectedResponse: &ActiveAuthorizationModelResponse{AuthorizationModelID: latestModelID, Pinned: false},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockController := gomock.NewController(t)
+			defer mockController.Finish()
+
+			mockDatastore := mockstorage.NewMockOpenFGADatastore(mockController)
+			test.setMock(mockDatastore)
+
+			resp, err := NewWriteActiveAuthorizationModelCommand(mockDatastore).
+				Execute(context.Background(), &WriteActiveAuthorizationModelRequest{StoreID: storeID, AuthorizationModelID: test.modelID})
+			require.Equal(t, test.expectedError, err)
+			require.Equal(t, test.expectedResponse, resp)
+		})
+	}
+}
+
+func TestRollbackActiveAuthorizationModelCommand(t *testing.T) {
+	storeID := ulid.Make().String()
+	oldestModelID := ulid.Make().String()
+	olderModelID := ulid.Make().String()
+	latestModelID := ulid.Make().String()
+	models := []*openfgav1.AuthorizationModel{{Id: latestModelID}, {Id: olderModelID}, {Id: oldestModelID}}
+
+	var tests = []struct {
+		name             string
+		setMock          func(*mockstorage.MockOpenFGADatastore)
+		expectedResponse *ActiveAuthorizationModelResponse
+		expectedError    error
+	}{
+		{
+			name: "rolls_back_from_latest",
+			setMock: func(mockDatastore *mockstorage.MockOpenFGADatastore) {
+				mockDatastore.EXPECT().ReadActiveAuthorizationModelID(gomock.Any(), storeID).Return("", storage.ErrNotFound)
+				mockDatastore.EXPECT().FindLatestAuthorizationModel(gomock.Any(), storeID).Return(models[0], nil)
+				mockDatastore.EXPECT().ReadAuthorizationModels(gomock.Any(), storeID, gomock.Any()).Return(models, "", nil)
+				mockDatastore.EXPECT().WriteActiveAuthorizationModelID(gomock.Any(), storeID, olderModelID).Return(nil)
+			},
+			expectedResponse: &ActiveAuthorizationModelResponse{AuthorizationModelID: olderModelID, Pinned: true},
+		},
+		{
+			name: "rolls_back_from_pinned_model_across_pages",
+			setMock: func(mockDatastore *mockstorage.MockOpenFGADatastore) {
+				mockDatastore.EXPECT().ReadActiveAuthorizationModelID(gomock.Any(), storeID).Return(olderModelID, nil)
+				gomock.InOrder(
+					mockDatastore.EXPECT().ReadAuthorizationModels(gomock.Any(), storeID, gomock.Any()).Return(models[:2], "token", nil),
+					mockDatastore.EXPECT().ReadAuthorizationModels(gomock.Any(), storeID, storage.ReadAuthorizationModelsOptions{
+						Pagination: storage.NewPaginationOptions(storage.DefaultPageSize, "token"),
+					}).Return(models[2:], "", nil),
+				)
+				mockDatastore.EXPECT().WriteActiveAuthorizationModelID(gomock.Any(), storeID, oldestModelID).Return(nil)
+			},
+			expectedResponse: &ActiveAuthorizationModelResponse{AuthorizationModelID: oldestModelID, Pinned: true},
+		},
+		{
+			name: "fails_when_active_model_is_the_oldest",
+			setMock: func(mockDatastore *mockstorage.MockOpenFGADatastore) {
+				mockDatastore.EXPECT().ReadActiveAuthorizationModelID(gomock.Any(), storeID).Return(oldestModelID, nil)
+				mockDatastore.EXPECT().ReadAuthorizationModels(gomock.Any(), storeID, gomock.Any()).Return(models, "", nil)
+			},
+			expectedError: serverErrors.ValidationError(
+				errors.New("no authorization model older than '" + oldestModelID + "' found in store '" + storeID + "'"),
+			),
+		},
+		{
+			name: "fails_when_store_has_no_models",
+			setMock: func(mockDatastore *mockstorage.MockOpenFGADatastore) {
+				mockDatastore.EXPECT().ReadActiveAuthorizationModelID(gomock.Any(), storeID).Return("", storage.ErrNotFound)
+				mockDatastore.EXPECT().FindLatestAuthorizationModel(gomock.Any(), storeID).Return(nil, storage.ErrNotFound)
+			},
+			expectedError: serverErrors.LatestAuthorizationModelNotFound(storeID),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockController := gomock.NewController(t)
+			defer mockController.Finish()
+
+			mockDatastore := mockstorage.NewMockOpenFGADatastore(mockController)
+			test.setMock(mockDatastore)
+
+			resp, err := NewRollbackActiveAuthorizationModelCommand(mockDatastore).
+				Execute(context.Background(), &RollbackActiveAuthorizationModelRequest{StoreID: storeID})
+			if test.expectedError != nil {
+				require.EqualError(t, err, test.expectedError.Error())
+			} else {
+				require.NoError(t, err)
+			}
+			require.Equal(t, test.expectedResponse, resp)
+		})
+	}
+}
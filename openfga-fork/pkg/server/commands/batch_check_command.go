@@ -19,6 +19,7 @@ import (
 	"github.com/openfga/openfga/pkg/logger"
 	"github.com/openfga/openfga/pkg/server/config"
 	"github.com/openfga/openfga/pkg/storage"
+	"github.com/openfga/openfga/pkg/storage/storagewrappers"
 	"github.com/openfga/openfga/pkg/typesystem"
 )
 
@@ -33,6 +34,10 @@ type BatchCheckQuery struct {
 	typesys                    *typesystem.TypeSystem
 	datastoreThrottleThreshold int
 	datastoreThrottleDuration  time.Duration
+	adaptiveLimiter            *storagewrappers.AdaptiveLimiter
+	// maxStreamedOutcomes is the number of outcomes of completed checks kept by ExecuteStreamed to deduplicate
+	// the checks of a stream.
+	maxStreamedOutcomes int
 }
 
 type BatchCheckCommandParams struct {
@@ -104,6 +109,13 @@ func WithBatchCheckDatastoreThrottler(threshold int, duration time.Duration) Bat
 	}
 }
 
+// WithBatchCheckAdaptiveLimiter bounds the datastore reads of the checks by the adaptive limiter shared by the server.
+func WithBatchCheckAdaptiveLimiter(limiter *storagewrappers.AdaptiveLimiter) BatchCheckQueryOption {
+	return func(bq *BatchCheckQuery) {
+		bq.adaptiveLimiter = limiter
+	}
+}
+
 func NewBatchCheckCommand(datastore storage.RelationshipTupleReader, checkResolver graph.CheckResolver, typesys *typesystem.TypeSystem, opts ...BatchCheckQueryOption) *BatchCheckQuery {
 	cmd := &BatchCheckQuery{
 		logger:              logger.NewNoopLogger(),
@@ -112,6 +124,7 @@ func NewBatchCheckCommand(datastore storage.RelationshipTupleReader, checkResolv
 		typesys:             typesys,
 		maxChecksAllowed:    config.DefaultMaxChecksPerBatchCheck,
 		maxConcurrentChecks: config.DefaultMaxConcurrentChecksPerBatchCheck,
+		maxStreamedOutcomes: defaultMaxStreamedOutcomes,
 		cacheSettings:       config.NewDefaultCacheSettings(),
 		sharedCheckResources: &shared.SharedDatastoreResources{
 			CacheController: cachecontroller.NewNoopCacheController(),
@@ -179,24 +192,7 @@ func (bq *BatchCheckQuery) Execute(ctx context.Context, params *BatchCheckComman
 			default:
 			}
 
-			checkQuery := NewCheckCommand(
-				bq.datastore,
-				bq.checkResolver,
-				bq.typesys,
-				WithCheckCommandLogger(bq.logger),
-				WithCheckCommandCache(bq.sharedCheckResources, bq.cacheSettings),
-				WithCheckDatastoreThrottler(bq.datastoreThrottleThreshold, bq.datastoreThrottleDuration),
-			)
-
-			checkParams := &CheckCommandParams{
-				StoreID:          params.StoreID,
-				TupleKey:         check.GetTupleKey(),
-				ContextualTuples: check.GetContextualTuples(),
-				Context:          check.GetContext(),
-				Consistency:      params.Consistency,
-			}
-
-			response, metadata, err := checkQuery.Execute(ctx, checkParams)
+			response, metadata, err := bq.executeCheck(ctx, params.StoreID, params.Consistency, check)
 
 			resultMap.Store(key, &BatchCheckOutcome{
 				CheckResponse: response,
@@ -239,6 +235,32 @@ func (bq *BatchCheckQuery) Execute(ctx context.Context, params *BatchCheckComman
 	}, nil
 }
 
+// executeCheck runs one check of a batch.
+func (bq *BatchCheckQuery) executeCheck(
+	ctx context.Context,
+	storeID string,
+	consistency openfgav1.ConsistencyPreference,
+	check *openfgav1.BatchCheckItem,
+) (*graph.ResolveCheckResponse, *graph.ResolveCheckRequestMetadata, error) {
+	checkQuery := NewCheckCommand(
+		bq.datastore,
+		bq.checkResolver,
+		bq.typesys,
+		WithCheckCommandLogger(bq.logger),
+		WithCheckCommandCache(bq.sharedCheckResources, bq.cacheSettings),
+		WithCheckDatastoreThrottler(bq.datastoreThrottleThreshold, bq.datastoreThrottleDuration),
+		WithCheckAdaptiveLimiter(bq.adaptiveLimiter),
+	)
+
+	return checkQuery.Execute(ctx, &CheckCommandParams{
+		StoreID:          storeID,
+		TupleKey:         check.GetTupleKey(),
+		ContextualTuples: check.GetContextualTuples(),
+		Context:          check.GetContext(),
+		Consistency:      consistency,
+	})
+}
+
 func validateCorrelationIDs(checks []*openfgav1.BatchCheckItem) error {
 	seen := map[string]struct{}{}
 
@@ -38,6 +38,8 @@ type CheckQuery struct {
 	shouldCacheIterators       bool
 	datastoreThrottleThreshold int
 	datastoreThrottleDuration  time.Duration
+	adaptiveLimiter            *storagewrappers.AdaptiveLimiter
+	recordResolutionPath       bool
 }
 
 type CheckCommandParams struct {
@@ -76,6 +78,21 @@ func WithCheckDatastoreThrottler(threshold int, duration time.Duration) CheckQue
 	}
 }
 
+// WithCheckCommandResolutionPath records the resolvers and planner strategies used by the check in the
+// ResolutionPath of the returned metadata, which is nil otherwise.
+func WithCheckCommandResolutionPath(record bool) CheckQueryOption {
+	return func(c *CheckQuery) {
+		c.recordResolutionPath = record
+	}
+}
+
+// WithCheckAdaptiveLimiter bounds the datastore reads of the query by the adaptive limiter shared by the server.
+func WithCheckAdaptiveLimiter(limiter *storagewrappers.AdaptiveLimiter) CheckQueryOption {
+	return func(c *CheckQuery) {
+		c.adaptiveLimiter = limiter
+	}
+}
+
 // TODO accept CheckCommandParams so we can build the datastore object right away.
 func NewCheckCommand(datastore storage.RelationshipTupleReader, checkResolver graph.CheckResolver, typesys *typesystem.TypeSystem, opts ...CheckQueryOption) *CheckQuery {
 	cmd := &CheckQuery{
@@ -118,6 +135,7 @@ func (c *CheckQuery) Execute(ctx context.Context, params *CheckCommandParams) (*
 			Consistency:               params.Consistency,
 			LastCacheInvalidationTime: cacheInvalidationTime,
 			AuthorizationModelID:      c.typesys.GetAuthorizationModelID(),
+			RecordResolutionPath:      c.recordResolutionPath,
 		},
 	)
 
@@ -133,6 +151,7 @@ func (c *CheckQuery) Execute(ctx context.Context, params *CheckCommandParams) (*
 			Concurrency:       c.maxConcurrentReads,
 			ThrottleThreshold: c.datastoreThrottleThreshold,
 			ThrottleDuration:  c.datastoreThrottleDuration,
+			AdaptiveLimiter:   c.adaptiveLimiter,
 		},
 		storagewrappers.DataResourceConfiguration{
 			Resources:      c.sharedCheckResources,
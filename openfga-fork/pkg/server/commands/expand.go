@@ -13,6 +13,7 @@ import (
 	openfgaErrors "github.com/openfga/openfga/internal/errors"
 	"github.com/openfga/openfga/internal/validation"
 	"github.com/openfga/openfga/pkg/logger"
+	"github.com/openfga/openfga/pkg/server/config"
 	serverErrors "github.com/openfga/openfga/pkg/server/errors"
 	"github.com/openfga/openfga/pkg/storage"
 	"github.com/openfga/openfga/pkg/storage/storagewrappers"
@@ -22,8 +23,11 @@ import (
 
 // ExpandQuery resolves a target TupleKey into a UsersetTree by expanding type definitions.
 type ExpandQuery struct {
-	logger    logger.Logger
-	datastore storage.RelationshipTupleReader
+	logger                  logger.Logger
+	datastore               storage.RelationshipTupleReader
+	resolveNodeLimit        uint32
+	resolveNodeBreadthLimit uint32
+	maxNodes                uint32
 }
 
 type ExpandQueryOption func(*ExpandQuery)
@@ -34,11 +38,36 @@ func WithExpandQueryLogger(l logger.Logger) ExpandQueryOption {
 	}
 }
 
+// WithExpandResolveNodeLimit sets the maximum depth of a recursive expansion.
+func WithExpandResolveNodeLimit(limit uint32) ExpandQueryOption {
+	return func(eq *ExpandQuery) {
+		eq.resolveNodeLimit = limit
+	}
+}
+
+// WithExpandResolveNodeBreadthLimit sets the number of nodes of a recursive expansion that are expanded
+// concurrently.
+func WithExpandResolveNodeBreadthLimit(limit uint32) ExpandQueryOption {
+	return func(eq *ExpandQuery) {
+		eq.resolveNodeBreadthLimit = limit
+	}
+}
+
+// WithExpandMaxNodes sets the number of relations a recursive expansion expands at most.
+func WithExpandMaxNodes(limit uint32) ExpandQueryOption {
+	return func(eq *ExpandQuery) {
+		eq.maxNodes = limit
+	}
+}
+
 // NewExpandQuery creates a new ExpandQuery using the supplied backends for retrieving data.
 func NewExpandQuery(datastore storage.OpenFGADatastore, opts ...ExpandQueryOption) *ExpandQuery {
 	eq := &ExpandQuery{
-		datastore: datastore,
-		logger:    logger.NewNoopLogger(),
+		datastore:               datastore,
+		logger:                  logger.NewNoopLogger(),
+		resolveNodeLimit:        config.DefaultResolveNodeLimit,
+		resolveNodeBreadthLimit: config.DefaultResolveNodeBreadthLimit,
+		maxNodes:                defaultRecursiveExpandMaxNodes,
 	}
 
 	for _, opt := range opts {
@@ -50,67 +79,78 @@ func NewExpandQuery(datastore storage.OpenFGADatastore, opts ...ExpandQueryOptio
 func (q *ExpandQuery) Execute(ctx context.Context, req *openfgav1.ExpandRequest) (*openfgav1.ExpandResponse, error) {
 	store := req.GetStoreId()
 	tupleKey := req.GetTupleKey()
-	object := tupleKey.GetObject()
-	relation := tupleKey.GetRelation()
 
-	if object == "" || relation == "" {
-		return nil, serverErrors.ErrInvalidExpandInput
+	tk := tupleUtils.NewTupleKey(tupleKey.GetObject(), tupleKey.GetRelation(), "")
+
+	typesys, rel, err := q.validate(ctx, tk, req.GetContextualTuples().GetTupleKeys())
+	if err != nil {
+		return nil, err
+	}
+
+	q.datastore = storagewrappers.NewCombinedTupleReader(
+		q.datastore,
+		req.GetContextualTuples().GetTupleKeys(),
+	)
+
+	userset := rel.GetRewrite()
+
+	root, err := q.resolveUserset(ctx, store, userset, tk, typesys, req.GetConsistency())
+	if err != nil {
+		return nil, err
 	}
 
-	tk := tupleUtils.NewTupleKey(object, relation, "")
+	return &openfgav1.ExpandResponse{
+		Tree: &openfgav1.UsersetTree{
+			Root: root,
+		},
+	}, nil
+}
+
+// validate validates the object and relation of tk, and the contextual tuples, against the typesystem of ctx.
+// It returns the typesystem and the relation to expand.
+func (q *ExpandQuery) validate(ctx context.Context, tk *openfgav1.TupleKey, contextualTuples []*openfgav1.TupleKey) (*typesystem.TypeSystem, *openfgav1.Relation, error) {
+	object := tk.GetObject()
+	relation := tk.GetRelation()
+
+	if object == "" || relation == "" {
+		return nil, nil, serverErrors.ErrInvalidExpandInput
+	}
 
 	typesys, ok := typesystem.TypesystemFromContext(ctx)
 	if !ok {
-		return nil, fmt.Errorf("%w: typesystem missing in context", openfgaErrors.ErrUnknown)
+		return nil, nil, fmt.Errorf("%w: typesystem missing in context", openfgaErrors.ErrUnknown)
 	}
 
-	for _, ctxTuple := range req.GetContextualTuples().GetTupleKeys() {
+	for _, ctxTuple := range contextualTuples {
 		if err := validation.ValidateTupleForWrite(typesys, ctxTuple); err != nil {
-			return nil, serverErrors.HandleTupleValidateError(err)
+			return nil, nil, serverErrors.HandleTupleValidateError(err)
 		}
 	}
 
 	err := validation.ValidateObject(typesys, tk)
 	if err != nil {
-		return nil, serverErrors.ValidationError(err)
+		return nil, nil, serverErrors.ValidationError(err)
 	}
 
 	err = validation.ValidateRelation(typesys, tk)
 	if err != nil {
-		return nil, serverErrors.ValidationError(err)
+		return nil, nil, serverErrors.ValidationError(err)
 	}
 
-	q.datastore = storagewrappers.NewCombinedTupleReader(
-		q.datastore,
-		req.GetContextualTuples().GetTupleKeys(),
-	)
-
 	objectType := tupleUtils.GetType(object)
 	rel, err := typesys.GetRelation(objectType, relation)
 	if err != nil {
 		if errors.Is(err, typesystem.ErrObjectTypeUndefined) {
-			return nil, serverErrors.TypeNotFound(objectType)
+			return nil, nil, serverErrors.TypeNotFound(objectType)
 		}
 
 		if errors.Is(err, typesystem.ErrRelationUndefined) {
-			return nil, serverErrors.RelationNotFound(relation, objectType, tk)
+			return nil, nil, serverErrors.RelationNotFound(relation, objectType, tk)
 		}
 
-		return nil, serverErrors.HandleError("", err)
-	}
-
-	userset := rel.GetRewrite()
-
-	root, err := q.resolveUserset(ctx, store, userset, tk, typesys, req.GetConsistency())
-	if err != nil {
-		return nil, err
+		return nil, nil, serverErrors.HandleError("", err)
 	}
-
-	return &openfgav1.ExpandResponse{
-		Tree: &openfgav1.UsersetTree{
-			Root: root,
-		},
-	}, nil
+	return typesys, rel, nil
 }
 
 func (q *ExpandQuery) resolveUserset(
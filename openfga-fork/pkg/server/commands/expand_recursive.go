@@ -0,0 +1,607 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+	"sync/atomic"
+
+	"google.golang.org/protobuf/types/known/structpb"
+
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+
+	"github.com/openfga/openfga/internal/condition"
+	"github.com/openfga/openfga/internal/condition/eval"
+	"github.com/openfga/openfga/internal/validation"
+	serverErrors "github.com/openfga/openfga/pkg/server/errors"
+	"github.com/openfga/openfga/pkg/storage"
+	"github.com/openfga/openfga/pkg/storage/storagewrappers"
+	tupleUtils "github.com/openfga/openfga/pkg/tuple"
+	"github.com/openfga/openfga/pkg/typesystem"
+)
+
+// The types of the nodes of a recursively expanded tree.
+const (
+	ExpandNodeDirect         = "direct"
+	ExpandNodeComputed       = "computed"
+	ExpandNodeTupleToUserset = "tuple_to_userset"
+	ExpandNodeUnion          = "union"
+	ExpandNodeIntersection   = "intersection"
+	ExpandNodeDifference     = "difference"
+	// ExpandNodeCycle is a relation that is already being expanded above the node. It adds no users.
+	ExpandNodeCycle = "cycle"
+	// ExpandNodeTruncated is a relation that is deeper than the maximum depth, or that was reached once the
+	// node budget of the request was spent, and was not expanded.
+	ExpandNodeTruncated = "truncated"
+	// ExpandNodeReference is a relation already expanded elsewhere in the tree. Its users are those of the
+	// expanded node.
+	ExpandNodeReference = "reference"
+)
+
+// defaultRecursiveExpandMaxNodes is the number of relations a recursive expansion expands at most.
+const defaultRecursiveExpandMaxNodes = 10_000
+
+// RecursiveExpandRequest asks for the userset tree of a relation of an object, with the usersets, computed
+// relations and tuple to usersets it refers to expanded as well, down to MaxDepth.
+type RecursiveExpandRequest struct {
+	StoreID              string
+	AuthorizationModelID string
+	Object               string
+	Relation             string
+	ContextualTuples     []*openfgav1.TupleKey
+	// Context is used to evaluate the conditions of the tuples. Tuples whose condition is not met are ignored.
+	Context     *structpb.Struct
+	Consistency openfgav1.ConsistencyPreference
+	// MaxDepth is the number of relations followed from the expanded relation. It defaults to, and cannot
+	// exceed, the resolve node limit.
+	MaxDepth uint32
+}
+
+// Validate checks the form of the object and relation to expand. Whether the model defines the relation on the
+// type of the object is checked when the tree is built.
+func (r *RecursiveExpandRequest) Validate() error {
+	if err := validateStoreID(r.StoreID); err != nil {
+		return err
+	}
+	if !tupleUtils.IsValidObject(r.Object) {
+		return errors.New("object must be of the form 'type:id'")
+	}
+	if !tupleUtils.IsValidRelation(r.Relation) {
+		return fmt.Errorf("invalid relation '%s'", r.Relation)
+	}
+	return nil
+}
+
+type RecursiveExpandResponse struct {
+	Tree *ExpandNode `json:"tree"`
+	// Users are the users, wildcards included, that have the relation with the object according to the tree.
+	Users []string `json:"users"`
+	// ExcludedUsers are the users that do not have the relation although the wildcard of their type is in Users,
+	// such as the users subtracted from a wildcard by an exclusion.
+	ExcludedUsers []string `json:"excluded_users"`
+	// Complete is false when some relations were not expanded because of the maximum depth or the node budget,
+	// in which case the users of these relations are missing from Users.
+	Complete bool `json:"complete"`
+}
+
+// ExpandNode is a node of a recursively expanded userset tree. Name is the object and relation that the node
+// belongs to, and Type tells which of the other fields is set.
+type ExpandNode struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+	// Users and Usersets are the users of a direct node. The usersets are expanded.
+	Users          []string              `json:"users,omitempty"`
+	Usersets       []*ExpandNode         `json:"usersets,omitempty"`
+	Computed       *ExpandNode           `json:"computed,omitempty"`
+	TupleToUserset *ExpandTupleToUserset `json:"tuple_to_userset,omitempty"`
+	// Nodes are the children of a union or an intersection.
+	Nodes      []*ExpandNode          `json:"nodes,omitempty"`
+	Difference *ExpandDifferenceNodes `json:"difference,omitempty"`
+}
+
+type ExpandTupleToUserset struct {
+	Tupleset string `json:"tupleset"`
+	// Computed are the expanded relations of the objects of the tupleset.
+	Computed []*ExpandNode `json:"computed"`
+}
+
+type ExpandDifferenceNodes struct {
+	Base     *ExpandNode `json:"base"`
+	Subtract *ExpandNode `json:"subtract"`
+}
+
+// expandResult is an expanded node with the users it resolves to.
+type expandResult struct {
+	node     *ExpandNode
+	users    userSet
+	complete bool
+	// height is the number of relations followed below the node.
+	height uint32
+	// cycles are the relations marked as cycles below the node that are expanded above it. The users of the
+	// node depend on the path to it until they are all expanded.
+	cycles map[string]struct{}
+}
+
+// addChild records the completeness, height and cycles of child, which is levels relations below r.
+func (r *expandResult) addChild(child *expandResult, levels uint32) {
+	r.complete = r.complete && child.complete
+	r.height = max(r.height, child.height+levels)
+	for name := range child.cycles {
+		if r.cycles == nil {
+			r.cycles = map[string]struct{}{}
+		}
+		r.cycles[name] = struct{}{}
+	}
+}
+
+// recursiveExpansion holds the parameters and the state shared by all the nodes of a recursive expansion.
+type recursiveExpansion struct {
+	q           *ExpandQuery
+	datastore   storage.RelationshipTupleReader
+	typesys     *typesystem.TypeSystem
+	req         *RecursiveExpandRequest
+	maxDepth    uint32
+	readOptions storage.ReadOptions
+
+	// nodes is the number of relations expanded so far, up to q.maxNodes.
+	nodes atomic.Uint32
+	// workers bounds the number of goroutines of the whole expansion to the resolve node breadth limit.
+	workers chan struct{}
+
+	mu sync.Mutex
+	// expanded are the complete results of the relations expanded so far, whose users do not depend on the
+	// path to them.
+	expanded map[string]*expandResult
+}
+
+// ExecuteRecursive expands the relation of the object of req, and recursively the relations the tree refers to,
+// down to the maximum depth. A relation that is already being expanded above is marked as a cycle, and a
+// relation already expanded elsewhere in the tree, as a reference to it. At most q.maxNodes relations are
+// expanded. The children of the nodes are expanded concurrently, with at most as many goroutines in the whole
+// tree as the resolve node breadth limit.
+func (q *ExpandQuery) ExecuteRecursive(ctx context.Context, req *RecursiveExpandRequest) (*RecursiveExpandResponse, error) {
+	maxDepth := req.MaxDepth
+	if maxDepth == 0 {
+		maxDepth = q.resolveNodeLimit
+	}
+	if maxDepth > q.resolveNodeLimit {
+		return nil, serverErrors.ValidationError(fmt.Errorf("max_depth must be at most %d", q.resolveNodeLimit))
+	}
+
+	tk := tupleUtils.NewTupleKey(req.Object, req.Relation, "")
+	typesys, _, err := q.validate(ctx, tk, req.ContextualTuples)
+	if err != nil {
+		return nil, err
+	}
+
+	e := &recursiveExpansion{
+		q:         q,
+		datastore: storagewrappers.NewCombinedTupleReader(q.datastore, req.ContextualTuples),
+		typesys:   typesys,
+		req:       req,
+		maxDepth:  maxDepth,
+		readOptions: storage.ReadOptions{
+			Consistency: storage.ConsistencyOptions{
+				Preference: req.Consistency,
+			},
+		},
+		workers:  make(chan struct{}, max(1, q.resolveNodeBreadthLimit)),
+		expanded: map[string]*expandResult{},
+	}
+
+	res, err := e.expandRelation(ctx, req.Object, req.Relation, 0, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	users := slices.Sorted(maps.Keys(res.users.users))
+	if users == nil {
+		users = []string{}
+	}
+	excludedUsers := slices.Sorted(maps.Keys(res.users.excluded))
+	if excludedUsers == nil {
+		excludedUsers = []string{}
+	}
+	return &RecursiveExpandResponse{
+		Tree:          res.node,
+		Users:         users,
+		ExcludedUsers: excludedUsers,
+		Complete:      res.complete,
+	}, nil
+}
+
+// expandRelation expands the rewrite of relation of object. path holds the relations being expanded above.
+func (e *recursiveExpansion) expandRelation(ctx context.Context, object, relation string, depth uint32, path []string) (*expandResult, error) {
+	name := tupleUtils.ToObjectRelationString(object, relation)
+	if slices.Contains(path, name) {
+		return &expandResult{
+			node:     &ExpandNode{Name: name, Type: ExpandNodeCycle},
+			complete: true,
+			cycles:   map[string]struct{}{name: {}},
+		}, nil
+	}
+	if depth > e.maxDepth {
+		return &expandResult{node: &ExpandNode{Name: name, Type: ExpandNodeTruncated}}, nil
+	}
+
+	e.mu.Lock()
+	expanded, ok := e.expanded[name]
+	e.mu.Unlock()
+	// The expanded node is only reused if it is not deeper than the maximum depth from here.
+	if ok && depth+expanded.height <= e.maxDepth {
+		return &expandResult{
+			node:     &ExpandNode{Name: name, Type: ExpandNodeReference},
+			users:    expanded.users,
+			complete: true,
+			height:   expanded.height,
+		}, nil
+	}
+
+	if e.nodes.Add(1) > e.q.maxNodes {
+		return &expandResult{node: &ExpandNode{Name: name, Type: ExpandNodeTruncated}}, nil
+	}
+
+	rel, err := e.typesys.GetRelation(tupleUtils.GetType(object), relation)
+	if err != nil {
+		return nil, serverErrors.HandleError("", err)
+	}
+
+	res, err := e.expandUserset(ctx, rel.GetRewrite(), object, relation, depth, append(slices.Clip(path), name))
+	if err != nil {
+		return nil, err
+	}
+
+	// The cycles back to the relation itself add no users, wherever it is expanded from.
+	delete(res.cycles, name)
+	if res.complete && len(res.cycles) == 0 {
+		e.mu.Lock()
+		e.expanded[name] = res
+		e.mu.Unlock()
+	}
+	return res, nil
+}
+
+func (e *recursiveExpansion) expandUserset(
+	ctx context.Context,
+	userset *openfgav1.Userset,
+	object, relation string,
+	depth uint32,
+	path []string,
+) (*expandResult, error) {
+	name := tupleUtils.ToObjectRelationString(object, relation)
+
+	switch us := userset.GetUserset().(type) {
+	case nil, *openfgav1.Userset_This:
+		return e.expandDirect(ctx, object, relation, depth, path)
+	case *openfgav1.Userset_ComputedUserset:
+		child, err := e.expandRelation(ctx, object, us.ComputedUserset.GetRelation(), depth+1, path)
+		if err != nil {
+			return nil, err
+		}
+		res := &expandResult{
+			node:     &ExpandNode{Name: name, Type: ExpandNodeComputed, Computed: child.node},
+			users:    child.users,
+			complete: true,
+		}
+		res.addChild(child, 1)
+		return res, nil
+	case *openfgav1.Userset_TupleToUserset:
+		return e.expandTupleToUserset(ctx, us.TupleToUserset, object, relation, depth, path)
+	case *openfgav1.Userset_Union:
+		children, err := e.expandUsersets(ctx, us.Union.GetChild(), object, relation, depth, path)
+		if err != nil {
+			return nil, err
+		}
+		res := &expandResult{node: &ExpandNode{Name: name, Type: ExpandNodeUnion}, complete: true}
+		for _, child := range children {
+			res.node.Nodes = append(res.node.Nodes, child.node)
+			res.users = unionUsers(res.users, child.users)
+			res.addChild(child, 0)
+		}
+		return res, nil
+	case *openfgav1.Userset_Intersection:
+		children, err := e.expandUsersets(ctx, us.Intersection.GetChild(), object, relation, depth, path)
+		if err != nil {
+			return nil, err
+		}
+		res := &expandResult{node: &ExpandNode{Name: name, Type: ExpandNodeIntersection}, users: children[0].users, complete: true}
+		for i, child := range children {
+			res.node.Nodes = append(res.node.Nodes, child.node)
+			if i > 0 {
+				res.users = combineUsers(res.users, child.users, func(inA, inB bool) bool { return inA && inB })
+			}
+			res.addChild(child, 0)
+		}
+		return res, nil
+	case *openfgav1.Userset_Difference:
+		children, err := e.expandUsersets(ctx, []*openfgav1.Userset{us.Difference.GetBase(), us.Difference.GetSubtract()}, object, relation, depth, path)
+		if err != nil {
+			return nil, err
+		}
+		base, subtract := children[0], children[1]
+		res := &expandResult{
+			node: &ExpandNode{Name: name, Type: ExpandNodeDifference, Difference: &ExpandDifferenceNodes{
+				Base:     base.node,
+				Subtract: subtract.node,
+			}},
+			users:    combineUsers(base.users, subtract.users, func(inBase, inSubtract bool) bool { return inBase && !inSubtract }),
+			complete: true,
+		}
+		res.addChild(base, 0)
+		res.addChild(subtract, 0)
+		return res, nil
+	default:
+		return nil, serverErrors.ErrUnsupportedUserSet
+	}
+}
+
+// expandDirect reads the tuples of relation of object. Usersets are expanded one level deeper.
+func (e *recursiveExpansion) expandDirect(ctx context.Context, object, relation string, depth uint32, path []string) (*expandResult, error) {
+	users, err := e.readUsers(ctx, object, relation)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &expandResult{
+		node:     &ExpandNode{Name: tupleUtils.ToObjectRelationString(object, relation), Type: ExpandNodeDirect},
+		users:    newUserSet(),
+		complete: true,
+	}
+	var usersets []string
+	for _, user := range users {
+		if tupleUtils.IsObjectRelation(user) {
+			usersets = append(usersets, user)
+			continue
+		}
+		res.node.Users = append(res.node.Users, user)
+		res.users.users[user] = struct{}{}
+	}
+
+	children, err := expandEach(ctx, e.workers, usersets, func(ctx context.Context, userset string) (*expandResult, error) {
+		userObject, userRelation := tupleUtils.SplitObjectRelation(userset)
+		return e.expandRelation(ctx, userObject, userRelation, depth+1, path)
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, child := range children {
+		res.node.Usersets = append(res.node.Usersets, child.node)
+		res.users = unionUsers(res.users, child.users)
+		res.addChild(child, 1)
+	}
+	return res, nil
+}
+
+// expandTupleToUserset expands the computed relation of each object of the tupleset of object. Objects whose
+// type does not define the computed relation are skipped, as Check does.
+func (e *recursiveExpansion) expandTupleToUserset(
+	ctx context.Context,
+	ttu *openfgav1.TupleToUserset,
+	object, relation string,
+	depth uint32,
+	path []string,
+) (*expandResult, error) {
+	tupleset := ttu.GetTupleset().GetRelation()
+	computedRelation := ttu.GetComputedUserset().GetRelation()
+
+	users, err := e.readUsers(ctx, object, tupleset)
+	if err != nil {
+		return nil, err
+	}
+
+	var parents []string
+	for _, user := range users {
+		if tupleUtils.IsObjectRelation(user) || tupleUtils.IsWildcard(user) {
+			continue
+		}
+		if _, err := e.typesys.GetRelation(tupleUtils.GetType(user), computedRelation); err != nil {
+			continue
+		}
+		parents = append(parents, user)
+	}
+
+	children, err := expandEach(ctx, e.workers, parents, func(ctx context.Context, parent string) (*expandResult, error) {
+		return e.expandRelation(ctx, parent, computedRelation, depth+1, path)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res := &expandResult{
+		node: &ExpandNode{
+			Name: tupleUtils.ToObjectRelationString(object, relation),
+			Type: ExpandNodeTupleToUserset,
+			TupleToUserset: &ExpandTupleToUserset{
+				Tupleset: tupleUtils.ToObjectRelationString(object, tupleset),
+				Computed: []*ExpandNode{},
+			},
+		},
+		complete: true,
+	}
+	for _, child := range children {
+		res.node.TupleToUserset.Computed = append(res.node.TupleToUserset.Computed, child.node)
+		res.users = unionUsers(res.users, child.users)
+		res.addChild(child, 1)
+	}
+	return res, nil
+}
+
+// expandUsersets expands the children of a rewrite of relation of object.
+func (e *recursiveExpansion) expandUsersets(
+	ctx context.Context,
+	usersets []*openfgav1.Userset,
+	object, relation string,
+	depth uint32,
+	path []string,
+) ([]*expandResult, error) {
+	return expandEach(ctx, e.workers, usersets, func(ctx context.Context, userset *openfgav1.Userset) (*expandResult, error) {
+		return e.expandUserset(ctx, userset, object, relation, depth, path)
+	})
+}
+
+// expandEach expands the items and returns their results in order. An item is expanded in a new goroutine if
+// one of the workers is free, and otherwise in the calling goroutine, so that the nodes of the whole tree share
+// the workers without waiting for each other.
+func expandEach[T any](ctx context.Context, workers chan struct{}, items []T, expand func(context.Context, T) (*expandResult, error)) ([]*expandResult, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make([]*expandResult, len(items))
+	errs := make([]error, len(items))
+	run := func(i int, item T) {
+		results[i], errs[i] = expand(ctx, item)
+		if errs[i] != nil {
+			cancel()
+		}
+	}
+
+	var wg sync.WaitGroup
+	for i, item := range items {
+		if ctx.Err() != nil {
+			errs[i] = ctx.Err()
+			break
+		}
+		select {
+		case workers <- struct{}{}:
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				defer func() { <-workers }()
+				run(i, item)
+			}()
+		default:
+			run(i, item)
+		}
+	}
+	wg.Wait()
+
+	// The first error other than the cancellation caused by another error is returned.
+	var firstErr error
+	for _, err := range errs {
+		if err != nil && (firstErr == nil || errors.Is(firstErr, context.Canceled)) {
+			firstErr = err
+		}
+	}
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	return results, nil
+}
+
+// readUsers returns the distinct users, sorted, of the tuples of relation of object whose condition is met.
+func (e *recursiveExpansion) readUsers(ctx context.Context, object, relation string) ([]string, error) {
+	tupleIter, err := e.datastore.Read(ctx, e.req.StoreID, tupleUtils.NewTupleKey(object, relation, ""), e.readOptions)
+	if err != nil {
+		return nil, serverErrors.HandleError("", err)
+	}
+
+	filteredIter := storage.NewFilteredTupleKeyIterator(
+		storage.NewTupleKeyIteratorFromTupleIterator(tupleIter),
+		validation.FilterInvalidTuples(e.typesys),
+	)
+	defer filteredIter.Stop()
+
+	distinctUsers := make(map[string]struct{})
+	for {
+		tk, err := filteredIter.Next(ctx)
+		if err != nil {
+			if err == storage.ErrIteratorDone {
+				break
+			}
+			return nil, serverErrors.HandleError("", err)
+		}
+
+		met, err := e.conditionMet(ctx, tk)
+		if err != nil {
+			return nil, err
+		}
+		if met {
+			distinctUsers[tk.GetUser()] = struct{}{}
+		}
+	}
+
+	return slices.Sorted(maps.Keys(distinctUsers)), nil
+}
+
+func (e *recursiveExpansion) conditionMet(ctx context.Context, tk *openfgav1.TupleKey) (bool, error) {
+	condEvalResult, err := eval.EvaluateTupleCondition(ctx, tk, e.typesys, e.req.Context)
+	if err != nil {
+		return false, err
+	}
+
+	if len(condEvalResult.MissingParameters) > 0 {
+		return false, condition.NewEvaluationError(
+			tk.GetCondition().GetName(),
+			fmt.Errorf("tuple '%s' is missing context parameters '%v'",
+				tupleUtils.TupleKeyToString(tk),
+				condEvalResult.MissingParameters),
+		)
+	}
+
+	return condEvalResult.ConditionMet, nil
+}
+
+// userSet is a set of users in which the wildcard of a type stands for every user of the type that is not
+// excluded.
+type userSet struct {
+	users map[string]struct{}
+	// excluded are users that are not in the set although the wildcard of their type is in users.
+	excluded map[string]struct{}
+}
+
+func newUserSet() userSet {
+	return userSet{users: map[string]struct{}{}, excluded: map[string]struct{}{}}
+}
+
+// contains returns true if user, which is not a wildcard, is in s.
+func (s userSet) contains(user string) bool {
+	if _, ok := s.users[user]; ok {
+		return true
+	}
+	if _, ok := s.excluded[user]; ok {
+		return false
+	}
+	_, ok := s.users[tupleUtils.TypedPublicWildcard(tupleUtils.GetType(user))]
+	return ok
+}
+
+func unionUsers(a, b userSet) userSet {
+	return combineUsers(a, b, func(inA, inB bool) bool { return inA || inB })
+}
+
+// combineUsers returns the users of a and b for which in returns true, given whether they are in a and in b. A
+// wildcard is in the result if in returns true for a user of its type that a and b do not name, and the users
+// that a and b name are then excluded from it where in returns false for them.
+func combineUsers(a, b userSet, in func(inA, inB bool) bool) userSet {
+	out := newUserSet()
+	for _, set := range []userSet{a, b} {
+		for user := range set.users {
+			if !tupleUtils.IsTypedWildcard(user) {
+				continue
+			}
+			_, inA := a.users[user]
+			_, inB := b.users[user]
+			if in(inA, inB) {
+				out.users[user] = struct{}{}
+			}
+		}
+	}
+	for _, set := range []userSet{a, b} {
+		for _, users := range []map[string]struct{}{set.users, set.excluded} {
+			for user := range users {
+				if tupleUtils.IsTypedWildcard(user) {
+					continue
+				}
+				if in(a.contains(user), b.contains(user)) {
+					out.users[user] = struct{}{}
+				} else if _, ok := out.users[tupleUtils.TypedPublicWildcard(tupleUtils.GetType(user))]; ok {
+					out.excluded[user] = struct{}{}
+				}
+			}
+		}
+	}
+	return out
+}
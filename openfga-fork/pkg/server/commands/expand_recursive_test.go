@@ -0,0 +1,343 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/oklog/ulid/v2"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+
+	"github.com/openfga/openfga/internal/condition"
+	"github.com/openfga/openfga/pkg/storage/memory"
+	storagetest "github.com/openfga/openfga/pkg/storage/test"
+	"github.com/openfga/openfga/pkg/tuple"
+	"github.com/openfga/openfga/pkg/typesystem"
+)
+
+func TestExpandQueryExecuteRecursive(t *testing.T) {
+	t.Cleanup(func() {
+		goleak.VerifyNone(t)
+	})
+
+	ds := memory.New()
+	t.Cleanup(ds.Close)
+
+	storeID, model := storagetest.BootstrapFGAStore(t, ds, `
+		model
+			schema 1.1
+		type user
+		type group
+			relations
+				define member: [user, group#member]
+		type folder
+			relations
+				define viewer: [user]
+		type document
+			relations
+				define parent: [folder]
+				define blocked: [user]
+				define editor: [user with in_office]
+				define viewer: [group#member] or editor or viewer from parent
+				define can_view: viewer but not blocked
+				define shared: viewer and editor
+		condition in_office(office: bool) {
+			office
+		}`,
+		[]string{
+			"group:eng#member@user:anne",
+			"group:eng#member@group:all#member",
+			"group:all#member@user:bob",
+			"group:all#member@group:eng#member",
+			"document:1#viewer@group:eng#member",
+			"document:1#parent@folder:x",
+			"folder:x#viewer@user:dan",
+			"document:1#blocked@user:bob",
+		})
+	err := ds.Write(context.Background(), storeID, nil, []*openfgav1.TupleKey{
+		tuple.NewTupleKeyWithCondition("document:1", "editor", "user:charlie", "in_office", nil),
+	})
+	require.NoError(t, err)
+
+	typesys, err := typesystem.NewAndValidate(context.Background(), model)
+	require.NoError(t, err)
+	ctx := typesystem.ContextWithTypesystem(context.Background(), typesys)
+
+	inOffice := func(office bool) *structpb.Struct {
+		s, err := structpb.NewStruct(map[string]any{"office": office})
+		require.NoError(t, err)
+		return s
+	}
+
+	tests := []struct {
+		name             string
+		relation         string
+		context          *structpb.Struct
+		maxDepth         uint32
+		expectedUsers    []string
+		expectedComplete bool
+	}{
+		{
+			name:             "difference_with_condition_met",
+			relation:         "can_view",
+			context:          inOffice(true),
+			expectedUsers:    []string{"user:anne", "user:charlie", "user:dan"},
+			expectedComplete: true,
+		},
+		{
+			name:             "difference_with_condition_not_met",
+			relation:         "can_view",
+			context:          inOffice(false),
+			expectedUsers:    []string{"user:anne", "user:dan"},
+			expectedComplete: true,
+		},
+		{
+			name:             "intersection",
+			relation:         "shared",
+			context:          inOffice(true),
+			expectedUsers:    []string{"user:charlie"},
+			expectedComplete: true,
+		},
+		{
+			name:             "truncated_at_max_depth",
+			relation:         "viewer",
+			context:          inOffice(true),
+			maxDepth:         1,
+			expectedUsers:    []string{"user:anne", "user:charlie", "user:dan"},
+			expectedComplete: false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			res, err := NewExpandQuery(ds).ExecuteRecursive(ctx, &RecursiveExpandRequest{
+				StoreID:  storeID,
+				Object:   "document:1",
+				Relation: test.relation,
+				Context:  test.context,
+				MaxDepth: test.maxDepth,
+			})
+			require.NoError(t, err)
+			require.Equal(t, test.expectedUsers, res.Users)
+			require.Equal(t, test.expectedComplete, res.Complete)
+		})
+	}
+
+	t.Run("tree_marks_cycles_and_truncated_nodes", func(t *testing.T) {
+		res, err := NewExpandQuery(ds).ExecuteRecursive(ctx, &RecursiveExpandRequest{
+			StoreID:  storeID,
+			Object:   "group:eng",
+			Relation: "member",
+			MaxDepth: 2,
+		})
+		require.NoError(t, err)
+		require.Equal(t, []string{"user:anne", "user:bob"}, res.Users)
+		require.True(t, res.Complete)
+
+		tree, err := json.Marshal(res.Tree)
+		require.NoError(t, err)
+		require.JSONEq(t, `{
+			"name": "group:eng#member",
+			"type": "direct",
+			"users": ["user:anne"],
+			"usersets": [{
+				"name": "group:all#member",
+				"type": "direct",
+				"users": ["user:bob"],
+				"usersets": [{"name": "group:eng#member", "type": "cycle"}]
+			}]
+		}`, string(tree))
+
+		res, err = NewExpandQuery(ds, WithExpandResolveNodeLimit(1)).ExecuteRecursive(ctx, &RecursiveExpandRequest{
+			StoreID:  storeID,
+			Object:   "document:1",
+			Relation: "viewer",
+			Context:  inOffice(true),
+		})
+		require.NoError(t, err)
+		require.False(t, res.Complete)
+		tree, err = json.Marshal(res.Tree.Nodes[0].Usersets[0].Usersets[0])
+		require.NoError(t, err)
+		require.JSONEq(t, `{"name": "group:all#member", "type": "truncated"}`, string(tree))
+	})
+
+	t.Run("missing_condition_parameters", func(t *testing.T) {
+		_, err := NewExpandQuery(ds).ExecuteRecursive(ctx, &RecursiveExpandRequest{
+			StoreID:  storeID,
+			Object:   "document:1",
+			Relation: "editor",
+		})
+		require.ErrorIs(t, err, condition.ErrEvaluationFailed)
+	})
+
+	t.Run("max_depth_above_resolve_node_limit", func(t *testing.T) {
+		_, err := NewExpandQuery(ds, WithExpandResolveNodeLimit(5)).ExecuteRecursive(ctx, &RecursiveExpandRequest{
+			StoreID:  storeID,
+			Object:   "document:1",
+			Relation: "viewer",
+			MaxDepth: 6,
+		})
+		require.ErrorContains(t, err, "max_depth must be at most 5")
+	})
+}
+
+func TestExpandQueryExecuteRecursiveSharedRelations(t *testing.T) {
+	t.Cleanup(func() {
+		goleak.VerifyNone(t)
+	})
+
+	ds := memory.New()
+	t.Cleanup(ds.Close)
+
+	storeID, model := storagetest.BootstrapFGAStore(t, ds, `
+		model
+			schema 1.1
+		type user
+		type group
+			relations
+				define member: [user, group#member]
+		type document
+			relations
+				define viewer: [group#member]`,
+		[]string{
+			"document:1#viewer@group:x#member",
+			"document:1#viewer@group:y#member",
+			"document:1#viewer@group:z#member",
+			"group:x#member@group:shared#member",
+			"group:y#member@group:shared#member",
+			"group:z#member@group:shared#member",
+			"group:shared#member@user:anne",
+		})
+
+	typesys, err := typesystem.NewAndValidate(context.Background(), model)
+	require.NoError(t, err)
+	ctx := typesystem.ContextWithTypesystem(context.Background(), typesys)
+	req := &RecursiveExpandRequest{StoreID: storeID, Object: "document:1", Relation: "viewer"}
+
+	t.Run("relation_expanded_once", func(t *testing.T) {
+		// With a single worker, the last groups are expanded one after the other, so the shared group is
+		// expanded before at least one of them.
+		res, err := NewExpandQuery(ds, WithExpandResolveNodeBreadthLimit(1)).ExecuteRecursive(ctx, req)
+		require.NoError(t, err)
+		require.Equal(t, []string{"user:anne"}, res.Users)
+		require.True(t, res.Complete)
+
+		var references int
+		for _, group := range res.Tree.Usersets {
+			require.Len(t, group.Usersets, 1)
+			require.Equal(t, "group:shared#member", group.Usersets[0].Name)
+			if group.Usersets[0].Type == ExpandNodeReference {
+				references++
+			}
+		}
+		require.Positive(t, references)
+	})
+
+	t.Run("node_budget_spent", func(t *testing.T) {
+		res, err := NewExpandQuery(ds, WithExpandMaxNodes(1)).ExecuteRecursive(ctx, req)
+		require.NoError(t, err)
+		require.Empty(t, res.Users)
+		require.False(t, res.Complete)
+		for _, group := range res.Tree.Usersets {
+			require.Equal(t, ExpandNodeTruncated, group.Type)
+		}
+	})
+}
+
+func TestExpandQueryExecuteRecursiveWildcardExclusions(t *testing.T) {
+	t.Cleanup(func() {
+		goleak.VerifyNone(t)
+	})
+
+	ds := memory.New()
+	t.Cleanup(ds.Close)
+
+	storeID, model := storagetest.BootstrapFGAStore(t, ds, `
+		model
+			schema 1.1
+		type user
+		type document
+			relations
+				define blocked: [user]
+				define editor: [user]
+				define viewer: [user:*] but not blocked
+				define can_edit: editor and viewer
+				define can_read: viewer or editor`,
+		[]string{
+			"document:1#viewer@user:*",
+			"document:1#blocked@user:bob",
+			"document:1#editor@user:anne",
+			"document:1#editor@user:bob",
+		})
+	typesys, err := typesystem.NewAndValidate(context.Background(), model)
+	require.NoError(t, err)
+	ctx := typesystem.ContextWithTypesystem(context.Background(), typesys)
+
+	tests := []struct {
+		relation              string
+		expectedUsers         []string
+		expectedExcludedUsers []string
+	}{
+		{relation: "viewer", expectedUsers: []string{"user:*"}, expectedExcludedUsers: []string{"user:bob"}},
+		{relation: "can_edit", expectedUsers: []string{"user:anne"}, expectedExcludedUsers: []string{}},
+		{relation: "can_read", expectedUsers: []string{"user:*", "user:anne", "user:bob"}, expectedExcludedUsers: []string{}},
+	}
+	for _, test := range tests {
+		t.Run(test.relation, func(t *testing.T) {
+			res, err := NewExpandQuery(ds).ExecuteRecursive(ctx, &RecursiveExpandRequest{
+				StoreID:  storeID,
+				Object:   "document:1",
+				Relation: test.relation,
+			})
+			require.NoError(t, err)
+			require.Equal(t, test.expectedUsers, res.Users)
+			require.Equal(t, test.expectedExcludedUsers, res.ExcludedUsers)
+			require.True(t, res.Complete)
+		})
+	}
+}
+
+func TestCombineUsersWithWildcards(t *testing.T) {
+	users := func(u ...string) map[string]struct{} {
+		m := map[string]struct{}{}
+		for _, user := range u {
+			m[user] = struct{}{}
+		}
+		return m
+	}
+	intersect := func(inA, inB bool) bool { return inA && inB }
+	subtract := func(inA, inB bool) bool { return inA && !inB }
+
+	require.Equal(t,
+		userSet{users: users("user:anne", "user:bob"), excluded: users()},
+		combineUsers(userSet{users: users("user:*", "group:x")}, userSet{users: users("user:anne", "user:bob")}, intersect))
+	require.Equal(t,
+		userSet{users: users("user:*"), excluded: users()},
+		combineUsers(userSet{users: users("user:*")}, userSet{users: users("user:*", "group:x")}, intersect))
+	require.Equal(t,
+		userSet{users: users("group:x"), excluded: users()},
+		combineUsers(userSet{users: users("user:anne", "group:x")}, userSet{users: users("user:*")}, subtract))
+	require.Equal(t,
+		userSet{users: users("user:*"), excluded: users("user:anne")},
+		combineUsers(userSet{users: users("user:*", "user:anne")}, userSet{users: users("user:anne")}, subtract))
+	// A wildcard minus a wildcard with exclusions leaves the excluded users.
+	require.Equal(t,
+		userSet{users: users("user:bob"), excluded: users()},
+		combineUsers(userSet{users: users("user:*")}, userSet{users: users("user:*"), excluded: users("user:bob")}, subtract))
+	require.Equal(t,
+		userSet{users: users("user:*", "user:bob"), excluded: users()},
+		unionUsers(userSet{users: users("user:*"), excluded: users("user:bob")}, userSet{users: users("user:bob")}))
+}
+
+func TestRecursiveExpandRequestValidate(t *testing.T) {
+	req := &RecursiveExpandRequest{StoreID: ulid.Make().String(), Object: "document:1", Relation: "viewer"}
+	require.NoError(t, req.Validate())
+
+	require.Error(t, (&RecursiveExpandRequest{StoreID: req.StoreID, Object: "document", Relation: "viewer"}).Validate())
+	require.Error(t, (&RecursiveExpandRequest{StoreID: req.StoreID, Object: "document:1", Relation: ""}).Validate())
+	require.Error(t, (&RecursiveExpandRequest{Object: "document:1", Relation: "viewer"}).Validate())
+}
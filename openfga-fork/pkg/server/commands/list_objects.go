@@ -64,6 +64,7 @@ type ListObjectsQuery struct {
 
 	datastoreThrottleThreshold int
 	datastoreThrottleDuration  time.Duration
+	adaptiveLimiter            *storagewrappers.AdaptiveLimiter
 
 	checkResolver            graph.CheckResolver
 	cacheSettings            serverconfig.CacheSettings
@@ -71,6 +72,8 @@ type ListObjectsQuery struct {
 
 	optimizationsEnabled bool // Indicates if experimental optimizations are enabled for ListObjectsResolver
 	useShadowCache       bool // Indicates that the shadow cache should be used instead of the main cache
+
+	candidateObjectIDs storage.SortedSet // If set, only the objects with these IDs are listed
 }
 
 type ListObjectsResolver interface {
@@ -97,6 +100,10 @@ type ListObjectsResolutionMetadata struct {
 	// WasWeightedGraphUsed indicates whether the weighted graph was used as the algorithm for the ListObjects request.
 	WasWeightedGraphUsed atomic.Bool
 
+	// WasTruncated indicates that the ListObjects request stopped at its deadline or at the maximum number of
+	// results, so that some objects may be missing.
+	WasTruncated atomic.Bool
+
 	// CheckCounter is the total number of check requests made during the ListObjects execution for the optimized path
 	CheckCounter atomic.Uint32
 
@@ -172,12 +179,28 @@ func WithListObjectsDatastoreThrottler(threshold int, duration time.Duration) Li
 	}
 }
 
+// WithListObjectsAdaptiveLimiter bounds the datastore reads of the query by the adaptive limiter shared by the server.
+func WithListObjectsAdaptiveLimiter(limiter *storagewrappers.AdaptiveLimiter) ListObjectsQueryOption {
+	return func(d *ListObjectsQuery) {
+		d.adaptiveLimiter = limiter
+	}
+}
+
 func WithListObjectsOptimizationsEnabled(enabled bool) ListObjectsQueryOption {
 	return func(d *ListObjectsQuery) {
 		d.optimizationsEnabled = enabled
 	}
 }
 
+// WithListObjectsCandidateObjectIDs restricts the objects that are listed to those of the target type with
+// these IDs. Where possible, only the tuples of these objects are read, so that the cost of the query depends
+// on the number of candidates rather than on the number of objects in the store.
+func WithListObjectsCandidateObjectIDs(objectIDs []string) ListObjectsQueryOption {
+	return func(d *ListObjectsQuery) {
+		d.candidateObjectIDs = storage.NewSortedSet(objectIDs...)
+	}
+}
+
 func WithListObjectsUseShadowCache(useShadowCache bool) ListObjectsQueryOption {
 	return func(d *ListObjectsQuery) {
 		d.useShadowCache = useShadowCache
@@ -246,6 +269,33 @@ type listObjectsRequest interface {
 	GetConsistency() openfgav1.ConsistencyPreference
 }
 
+// reverseExpandUserRef returns the reverse expansion source of user, which is an object, a typed wildcard
+// or a userset.
+func reverseExpandUserRef(user string) reverseexpand.IsUserRef {
+	userObj, userRel := tuple.SplitObjectRelation(user)
+	userObjType, userObjID := tuple.SplitObject(userObj)
+
+	if userRel != "" {
+		return &reverseexpand.UserRefObjectRelation{
+			ObjectRelation: &openfgav1.ObjectRelation{
+				Object:   userObj,
+				Relation: userRel,
+			},
+		}
+	}
+
+	if tuple.IsTypedWildcard(userObj) {
+		return &reverseexpand.UserRefTypedWildcard{Type: tuple.GetType(userObj)}
+	}
+
+	return &reverseexpand.UserRefObject{
+		Object: &openfgav1.Object{
+			Type: userObjType,
+			Id:   userObjID,
+		},
+	}
+}
+
 // evaluate fires of evaluation of the ListObjects query by delegating to
 // [[reverseexpand.ReverseExpand#Execute]] and resolving the results yielded
 // from it. If any results yielded by reverse expansion require further eval,
@@ -264,92 +314,37 @@ func (q *ListObjectsQuery) evaluate(
 	targetObjectType := req.GetType()
 	targetRelation := req.GetRelation()
 
-	typesys, ok := typesystem.TypesystemFromContext(ctx)
-	if !ok {
-		return fmt.Errorf("%w: typesystem missing in context", openfgaErrors.ErrUnknown)
-	}
-
-	if !typesystem.IsSchemaVersionSupported(typesys.GetSchemaVersion()) {
-		return serverErrors.ValidationError(typesystem.ErrInvalidSchemaVersion)
+	typesys, err := validateListObjectsRequest(ctx, req)
+	if err != nil {
+		return err
 	}
 
-	for _, ctxTuple := range req.GetContextualTuples().GetTupleKeys() {
-		if err := validation.ValidateTupleForWrite(typesys, ctxTuple); err != nil {
-			return serverErrors.HandleTupleValidateError(err)
-		}
+	reverseExpandOptions := []reverseexpand.ReverseExpandQueryOption{
+		reverseexpand.WithResolveNodeLimit(q.resolveNodeLimit),
+		reverseexpand.WithDispatchThrottlerConfig(q.dispatchThrottlerConfig),
+		reverseexpand.WithResolveNodeBreadthLimit(q.resolveNodeBreadthLimit),
+		reverseexpand.WithLogger(q.logger),
+		reverseexpand.WithCheckResolver(q.checkResolver),
+		reverseexpand.WithListObjectOptimizationsEnabled(q.optimizationsEnabled),
 	}
 
-	_, err := typesys.GetRelation(targetObjectType, targetRelation)
-	if err != nil {
-		if errors.Is(err, typesystem.ErrObjectTypeUndefined) {
-			return serverErrors.TypeNotFound(targetObjectType)
+	if q.candidateObjectIDs != nil {
+		// Objects of the target type that are users of other objects may lead to candidates without being
+		// candidates themselves, in which case their tuples must all be read.
+		if !isUserType(typesys, targetObjectType) {
+			reverseExpandOptions = append(reverseExpandOptions, reverseexpand.WithCandidateObjectIDs(q.candidateObjectIDs))
 		}
-
-		if errors.Is(err, typesystem.ErrRelationUndefined) {
-			return serverErrors.RelationNotFound(targetRelation, targetObjectType, nil)
-		}
-
-		return serverErrors.HandleError("", err)
-	}
-
-	if err := validation.ValidateUser(typesys, req.GetUser()); err != nil {
-		return serverErrors.ValidationError(fmt.Errorf("invalid 'user' value: %s", err))
 	}
 
 	handler := func() {
-		userObj, userRel := tuple.SplitObjectRelation(req.GetUser())
-		userObjType, userObjID := tuple.SplitObject(userObj)
-
-		var sourceUserRef reverseexpand.IsUserRef
-		sourceUserRef = &reverseexpand.UserRefObject{
-			Object: &openfgav1.Object{
-				Type: userObjType,
-				Id:   userObjID,
-			},
-		}
-
-		if tuple.IsTypedWildcard(userObj) {
-			sourceUserRef = &reverseexpand.UserRefTypedWildcard{Type: tuple.GetType(userObj)}
-		}
-
-		if userRel != "" {
-			sourceUserRef = &reverseexpand.UserRefObjectRelation{
-				ObjectRelation: &openfgav1.ObjectRelation{
-					Object:   userObj,
-					Relation: userRel,
-				},
-			}
-		}
+		sourceUserRef := reverseExpandUserRef(req.GetUser())
 
 		reverseExpandResultsChan := make(chan *reverseexpand.ReverseExpandResult, 1)
 		objectsFound := atomic.Uint32{}
 
-		ds := storagewrappers.NewRequestStorageWrapperWithCache(
-			q.datastore,
-			req.GetContextualTuples().GetTupleKeys(),
-			&storagewrappers.Operation{
-				Method:            apimethod.ListObjects,
-				Concurrency:       q.maxConcurrentReads,
-				ThrottleThreshold: q.datastoreThrottleThreshold,
-				ThrottleDuration:  q.datastoreThrottleDuration,
-			},
-			storagewrappers.DataResourceConfiguration{
-				Resources:      q.sharedDatastoreResources,
-				CacheSettings:  q.cacheSettings,
-				UseShadowCache: q.useShadowCache,
-			},
-		)
-
-		reverseExpandQuery := reverseexpand.NewReverseExpandQuery(
-			ds,
-			typesys,
-			reverseexpand.WithResolveNodeLimit(q.resolveNodeLimit),
-			reverseexpand.WithDispatchThrottlerConfig(q.dispatchThrottlerConfig),
-			reverseexpand.WithResolveNodeBreadthLimit(q.resolveNodeBreadthLimit),
-			reverseexpand.WithLogger(q.logger),
-			reverseexpand.WithCheckResolver(q.checkResolver),
-			reverseexpand.WithListObjectOptimizationsEnabled(q.optimizationsEnabled),
-		)
+		ds := q.requestStorage(req)
+
+		reverseExpandQuery := reverseexpand.NewReverseExpandQuery(ds, typesys, reverseExpandOptions...)
 
 		reverseExpandDoneWithError := make(chan struct{}, 1)
 		cancelCtx, cancel := context.WithCancel(ctx)
@@ -389,6 +384,7 @@ func (q *ListObjectsQuery) evaluate(
 				break ConsumerReadLoop
 			case <-ctx.Done():
 				cancel() // cancel any inflight work if e.g. deadline exceeded
+				resolutionMetadata.WasTruncated.Store(true)
 				break ConsumerReadLoop
 			case res, channelOpen := <-reverseExpandResultsChan:
 				if !channelOpen {
@@ -402,9 +398,14 @@ func (q *ListObjectsQuery) evaluate(
 
 				if (maxResults != 0) && objectsFound.Load() >= maxResults {
 					cancel() // cancel any inflight work if we already found enough results
+					resolutionMetadata.WasTruncated.Store(true)
 					break ConsumerReadLoop
 				}
 
+				if !q.isCandidate(res.Object) {
+					continue
+				}
+
 				if res.ResultStatus == reverseexpand.NoFurtherEvalStatus {
 					noFurtherEvalRequiredCounter.Inc()
 					trySendObject(ctx, res.Object, &objectsFound, maxResults, resultsChan)
@@ -414,27 +415,11 @@ func (q *ListObjectsQuery) evaluate(
 				furtherEvalRequiredCounter.Inc()
 
 				pool.Go(func(ctx context.Context) error {
-					resp, checkRequestMetadata, err := NewCheckCommand(q.datastore, q.checkResolver, typesys,
-						WithCheckCommandLogger(q.logger),
-						WithCheckCommandMaxConcurrentReads(q.maxConcurrentReads),
-						WithCheckDatastoreThrottler(q.datastoreThrottleThreshold, q.datastoreThrottleDuration),
-					).
-						Execute(ctx, &CheckCommandParams{
-							StoreID:          req.GetStoreId(),
-							TupleKey:         tuple.NewCheckRequestTupleKey(res.Object, req.GetRelation(), req.GetUser()),
-							ContextualTuples: req.GetContextualTuples(),
-							Context:          req.GetContext(),
-							Consistency:      req.GetConsistency(),
-						})
+					allowed, err := q.checkObject(ctx, typesys, req, res.Object, resolutionMetadata)
 					if err != nil {
 						return err
 					}
-					resolutionMetadata.DatastoreQueryCount.Add(resp.GetResolutionMetadata().DatastoreQueryCount)
-					resolutionMetadata.DispatchCounter.Add(checkRequestMetadata.DispatchCounter.Load())
-					if !resolutionMetadata.WasThrottled.Load() && checkRequestMetadata.WasThrottled.Load() {
-						resolutionMetadata.WasThrottled.Store(true)
-					}
-					if resp.Allowed {
+					if allowed {
 						trySendObject(ctx, res.Object, &objectsFound, maxResults, resultsChan)
 					}
 					return nil
@@ -446,6 +431,8 @@ func (q *ListObjectsQuery) evaluate(
 		if err != nil {
 			if !errors.Is(err, context.DeadlineExceeded) && !errors.Is(err, context.Canceled) {
 				resultsChan <- ListObjectsResult{Err: err}
+			} else {
+				resolutionMetadata.WasTruncated.Store(true)
 			}
 			// TODO set header to indicate "deadline exceeded"
 		}
@@ -460,6 +447,138 @@ func (q *ListObjectsQuery) evaluate(
 	return nil
 }
 
+// checkObject returns true if the user of req has the relation of req with object. The cost of the Check is
+// added to resolutionMetadata.
+func (q *ListObjectsQuery) checkObject(
+	ctx context.Context,
+	typesys *typesystem.TypeSystem,
+	req listObjectsRequest,
+	object string,
+	resolutionMetadata *ListObjectsResolutionMetadata,
+) (bool, error) {
+	resp, checkRequestMetadata, err := NewCheckCommand(q.datastore, q.checkResolver, typesys,
+		WithCheckCommandLogger(q.logger),
+		WithCheckCommandMaxConcurrentReads(q.maxConcurrentReads),
+		WithCheckDatastoreThrottler(q.datastoreThrottleThreshold, q.datastoreThrottleDuration),
+		WithCheckAdaptiveLimiter(q.adaptiveLimiter),
+	).
+		Execute(ctx, &CheckCommandParams{
+			StoreID:          req.GetStoreId(),
+			TupleKey:         tuple.NewCheckRequestTupleKey(object, req.GetRelation(), req.GetUser()),
+			ContextualTuples: req.GetContextualTuples(),
+			Context:          req.GetContext(),
+			Consistency:      req.GetConsistency(),
+		})
+	if err != nil {
+		return false, err
+	}
+	resolutionMetadata.DatastoreQueryCount.Add(resp.GetResolutionMetadata().DatastoreQueryCount)
+	resolutionMetadata.DispatchCounter.Add(checkRequestMetadata.DispatchCounter.Load())
+	if !resolutionMetadata.WasThrottled.Load() && checkRequestMetadata.WasThrottled.Load() {
+		resolutionMetadata.WasThrottled.Store(true)
+	}
+	return resp.Allowed, nil
+}
+
+// validateListObjectsRequest validates req against the typesystem of ctx, which it returns.
+func validateListObjectsRequest(ctx context.Context, req listObjectsRequest) (*typesystem.TypeSystem, error) {
+	typesys, ok := typesystem.TypesystemFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("%w: typesystem missing in context", openfgaErrors.ErrUnknown)
+	}
+
+	if !typesystem.IsSchemaVersionSupported(typesys.GetSchemaVersion()) {
+		return nil, serverErrors.ValidationError(typesystem.ErrInvalidSchemaVersion)
+	}
+
+	for _, ctxTuple := range req.GetContextualTuples().GetTupleKeys() {
+		if err := validation.ValidateTupleForWrite(typesys, ctxTuple); err != nil {
+			return nil, serverErrors.HandleTupleValidateError(err)
+		}
+	}
+
+	_, err := typesys.GetRelation(req.GetType(), req.GetRelation())
+	if err != nil {
+		if errors.Is(err, typesystem.ErrObjectTypeUndefined) {
+			return nil, serverErrors.TypeNotFound(req.GetType())
+		}
+
+		if errors.Is(err, typesystem.ErrRelationUndefined) {
+			return nil, serverErrors.RelationNotFound(req.GetRelation(), req.GetType(), nil)
+		}
+
+		return nil, serverErrors.HandleError("", err)
+	}
+
+	if err := validation.ValidateUser(typesys, req.GetUser()); err != nil {
+		return nil, serverErrors.ValidationError(fmt.Errorf("invalid 'user' value: %s", err))
+	}
+
+	return typesys, nil
+}
+
+// isCandidate returns true if object is one of the candidate objects of q, or if q has no candidates.
+func (q *ListObjectsQuery) isCandidate(object string) bool {
+	if q.candidateObjectIDs == nil {
+		return true
+	}
+	_, objectID := tuple.SplitObject(object)
+	return q.candidateObjectIDs.Exists(objectID)
+}
+
+// isUserType returns true if objects of objectType can be the user of a relation, directly or through a
+// userset or a tupleset.
+func isUserType(typesys *typesystem.TypeSystem, objectType string) bool {
+	for typeName, relations := range typesys.GetAllRelations() {
+		for relationName := range relations {
+			directlyRelatedTypes, err := typesys.GetDirectlyRelatedUserTypes(typeName, relationName)
+			if err != nil {
+				return true
+			}
+			for _, ref := range directlyRelatedTypes {
+				if ref.GetType() == objectType {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// requestStorage returns the datastore used to resolve req, which includes its contextual tuples.
+func (q *ListObjectsQuery) requestStorage(req listObjectsRequest) *storagewrappers.RequestStorageWrapper {
+	return storagewrappers.NewRequestStorageWrapperWithCache(
+		q.datastore,
+		req.GetContextualTuples().GetTupleKeys(),
+		&storagewrappers.Operation{
+			Method:            apimethod.ListObjects,
+			Concurrency:       q.maxConcurrentReads,
+			ThrottleThreshold: q.datastoreThrottleThreshold,
+			ThrottleDuration:  q.datastoreThrottleDuration,
+			AdaptiveLimiter:   q.adaptiveLimiter,
+		},
+		storagewrappers.DataResourceConfiguration{
+			Resources:      q.sharedDatastoreResources,
+			CacheSettings:  q.cacheSettings,
+			UseShadowCache: q.useShadowCache,
+		},
+	)
+}
+
+// invalidateIteratorCaches kicks off the background jobs that check if the iterators cached for the store of
+// req are stale, invalidating where needed.
+func (q *ListObjectsQuery) invalidateIteratorCaches(ctx context.Context, req listObjectsRequest) {
+	if req.GetConsistency() == openfgav1.ConsistencyPreference_HIGHER_CONSISTENCY {
+		return
+	}
+	if q.cacheSettings.ShouldCacheListObjectsIterators() {
+		q.sharedDatastoreResources.CacheController.InvalidateIfNeeded(ctx, req.GetStoreId())
+	}
+	if q.cacheSettings.ShouldShadowCacheListObjectsIterators() {
+		q.sharedDatastoreResources.ShadowCacheController.InvalidateIfNeeded(ctx, req.GetStoreId())
+	}
+}
+
 func trySendObject(ctx context.Context, object string, objectsFound *atomic.Uint32, maxResults uint32, resultsChan chan<- ListObjectsResult) {
 	if maxResults != 0 {
 		if objectsFound.Add(1) > maxResults {
@@ -490,15 +609,7 @@ func (q *ListObjectsQuery) Execute(
 
 	var listObjectsResponse ListObjectsResponse
 
-	if req.GetConsistency() != openfgav1.ConsistencyPreference_HIGHER_CONSISTENCY {
-		if q.cacheSettings.ShouldCacheListObjectsIterators() {
-			// Kick off background job to check if cache records are stale, invalidating where needed
-			q.sharedDatastoreResources.CacheController.InvalidateIfNeeded(ctx, req.GetStoreId())
-		}
-		if q.cacheSettings.ShouldShadowCacheListObjectsIterators() {
-			q.sharedDatastoreResources.ShadowCacheController.InvalidateIfNeeded(ctx, req.GetStoreId())
-		}
-	}
+	q.invalidateIteratorCaches(ctx, req)
 
 	err := q.evaluate(timeoutCtx, req, resultsChan, maxResults, &listObjectsResponse.ResolutionMetadata)
 	if err != nil {
@@ -529,6 +640,10 @@ func (q *ListObjectsQuery) Execute(
 	if len(listObjectsResponse.Objects) < int(maxResults) && errs != nil {
 		return nil, errs
 	}
+	if maxResults > 0 && len(listObjectsResponse.Objects) >= int(maxResults) {
+		// The objects found beyond maxResults are dropped without being counted.
+		listObjectsResponse.ResolutionMetadata.WasTruncated.Store(true)
+	}
 
 	return &listObjectsResponse, nil
 }
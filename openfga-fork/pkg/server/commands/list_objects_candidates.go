@@ -0,0 +1,36 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+)
+
+const maxListObjectsCandidates = 1000
+
+// ListObjectsCandidatesRequest is a ListObjects request that only lists the objects among ObjectIDs.
+type ListObjectsCandidatesRequest struct {
+	*openfgav1.ListObjectsRequest
+	// ObjectIDs are the IDs, without the type, of the candidate objects.
+	ObjectIDs []string
+}
+
+// Validate checks the embedded ListObjects request and that the candidates are a bounded list of non-empty object IDs.
+func (r *ListObjectsCandidatesRequest) Validate() error {
+	if r.ListObjectsRequest == nil {
+		return errors.New("the ListObjects request is required")
+	}
+	if err := r.ListObjectsRequest.Validate(); err != nil {
+		return err
+	}
+	if len(r.ObjectIDs) == 0 || len(r.ObjectIDs) > maxListObjectsCandidates {
+		return fmt.Errorf("object_ids must have between 1 and %d items", maxListObjectsCandidates)
+	}
+	for _, objectID := range r.ObjectIDs {
+		if objectID == "" {
+			return errors.New("object_ids must not be empty")
+		}
+	}
+	return nil
+}
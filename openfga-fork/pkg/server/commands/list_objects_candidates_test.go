@@ -0,0 +1,149 @@
+package commands
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+
+	"github.com/openfga/openfga/internal/graph"
+	"github.com/openfga/openfga/pkg/storage"
+	"github.com/openfga/openfga/pkg/storage/memory"
+	storagetest "github.com/openfga/openfga/pkg/storage/test"
+	"github.com/openfga/openfga/pkg/typesystem"
+)
+
+// readStartingWithUserRecorder records the object IDs that the reads starting with a user are restricted to.
+type readStartingWithUserRecorder struct {
+	storage.RelationshipTupleReader
+
+	mu        sync.Mutex
+	objectIDs map[string][]string
+}
+
+func (r *readStartingWithUserRecorder) ReadStartingWithUser(ctx context.Context, store string, filter storage.ReadStartingWithUserFilter, options storage.ReadStartingWithUserOptions) (storage.TupleIterator, error) {
+	r.mu.Lock()
+	if filter.ObjectIDs != nil {
+		r.objectIDs[filter.ObjectType] = filter.ObjectIDs.Values()
+	}
+	r.mu.Unlock()
+	return r.RelationshipTupleReader.ReadStartingWithUser(ctx, store, filter, options)
+}
+
+func TestListObjectsWithCandidateObjectIDs(t *testing.T) {
+	t.Cleanup(func() {
+		goleak.VerifyNone(t)
+	})
+
+	checker, checkResolverCloser, err := graph.NewOrderedCheckResolvers().Build()
+	require.NoError(t, err)
+	t.Cleanup(checkResolverCloser)
+
+	tests := []struct {
+		name              string
+		model             string
+		tuples            []string
+		req               *openfgav1.ListObjectsRequest
+		candidates        []string
+		expected          []string
+		expectedReadsOnly bool
+	}{
+		{
+			name: "direct_relation",
+			model: `
+				model
+					schema 1.1
+				type user
+				type document
+					relations
+						define owner: [user]
+						define viewer: [user] or owner`,
+			tuples: []string{
+				"document:1#viewer@user:jon",
+				"document:2#owner@user:jon",
+				"document:3#viewer@user:jon",
+				"document:4#viewer@user:anne",
+			},
+			req:               &openfgav1.ListObjectsRequest{Type: "document", Relation: "viewer", User: "user:jon"},
+			candidates:        []string{"2", "3", "4", "5"},
+			expected:          []string{"document:2", "document:3"},
+			expectedReadsOnly: true,
+		},
+		{
+			name: "target_type_is_a_user_type",
+			model: `
+				model
+					schema 1.1
+				type user
+				type folder
+					relations
+						define parent: [folder]
+						define viewer: [user] or viewer from parent`,
+			tuples: []string{
+				"folder:root#viewer@user:jon",
+				"folder:a#parent@folder:root",
+				"folder:b#parent@folder:a",
+				"folder:c#viewer@user:anne",
+			},
+			req:        &openfgav1.ListObjectsRequest{Type: "folder", Relation: "viewer", User: "user:jon"},
+			candidates: []string{"b", "c"},
+			expected:   []string{"folder:b"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ds := memory.New()
+			t.Cleanup(ds.Close)
+
+			storeID, model := storagetest.BootstrapFGAStore(t, ds, test.model, test.tuples)
+			typesys, err := typesystem.NewAndValidate(context.Background(), model)
+			require.NoError(t, err)
+			ctx := typesystem.ContextWithTypesystem(context.Background(), typesys)
+
+			recorder := &readStartingWithUserRecorder{RelationshipTupleReader: ds, objectIDs: map[string][]string{}}
+			q, err := NewListObjectsQuery(recorder, checker, WithListObjectsCandidateObjectIDs(test.candidates))
+			require.NoError(t, err)
+
+			test.req.StoreId = storeID
+			test.req.AuthorizationModelId = model.GetId()
+			res, err := q.Execute(ctx, test.req)
+			require.NoError(t, err)
+			require.ElementsMatch(t, test.expected, res.Objects)
+
+			if test.expectedReadsOnly {
+				require.Equal(t, map[string][]string{test.req.GetType(): test.candidates}, recorder.objectIDs)
+			} else {
+				require.Empty(t, recorder.objectIDs)
+			}
+		})
+	}
+}
+
+func TestListObjectsCandidatesRequestValidate(t *testing.T) {
+	req := &ListObjectsCandidatesRequest{
+		ListObjectsRequest: &openfgav1.ListObjectsRequest{
+			StoreId:  "01JBDC5AQCZC4J8AC3TNC4PH5B",
+			Type:     "document",
+			Relation: "viewer",
+			User:     "user:jon",
+		},
+		ObjectIDs: []string{"1"},
+	}
+	require.NoError(t, req.Validate())
+
+	req.ObjectIDs = nil
+	require.ErrorContains(t, req.Validate(), "object_ids must have between 1 and 1000 items")
+
+	req.ObjectIDs = make([]string, maxListObjectsCandidates+1)
+	require.ErrorContains(t, req.Validate(), "object_ids must have between 1 and 1000 items")
+
+	req.ObjectIDs = []string{"1", ""}
+	require.ErrorContains(t, req.Validate(), "object_ids must not be empty")
+
+	require.Error(t, (&ListObjectsCandidatesRequest{}).Validate())
+}
@@ -0,0 +1,756 @@
+package commands
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+
+	"github.com/openfga/openfga/internal/checkutil"
+	"github.com/openfga/openfga/internal/concurrency"
+	"github.com/openfga/openfga/internal/condition"
+	"github.com/openfga/openfga/internal/condition/contextprovider"
+	"github.com/openfga/openfga/internal/graph"
+	"github.com/openfga/openfga/internal/iterator"
+	"github.com/openfga/openfga/internal/validation"
+	"github.com/openfga/openfga/pkg/encoder"
+	serverErrors "github.com/openfga/openfga/pkg/server/errors"
+	"github.com/openfga/openfga/pkg/storage"
+	"github.com/openfga/openfga/pkg/tuple"
+	"github.com/openfga/openfga/pkg/typesystem"
+)
+
+const (
+	defaultListObjectsPageSize = 100
+	maxListObjectsPageSize     = 1000
+	// listObjectsPageUserFilterSize is the maximum number of users in each read of the candidate objects of a page.
+	listObjectsPageUserFilterSize = 100
+)
+
+// ListObjectsPageRequest is a ListObjects request that returns one page of the objects, sorted by object ID.
+type ListObjectsPageRequest struct {
+	*openfgav1.ListObjectsRequest
+	// PageSize is the maximum number of objects in the page. If 0, 100 objects are returned.
+	PageSize          int32
+	ContinuationToken string
+	// TrustedContextFields are the condition context values added by the server to the Context of the request,
+	// see contextprovider.Fields. Unlike the Context sent by the caller, they are not part of the continuation
+	// token, since some of them, such as the request time, change from page to page.
+	TrustedContextFields map[string]*structpb.Value
+}
+
+// Validate checks the embedded ListObjects request and the page size. The continuation token is only decoded on
+// execution, since it is bound to the resolved model.
+func (r *ListObjectsPageRequest) Validate() error {
+	if r.ListObjectsRequest == nil {
+		return errors.New("the ListObjects request is required")
+	}
+	if err := r.ListObjectsRequest.Validate(); err != nil {
+		return err
+	}
+	if r.PageSize < 0 || r.PageSize > maxListObjectsPageSize {
+		return fmt.Errorf("page_size must be between 0 and %d", maxListObjectsPageSize)
+	}
+	return nil
+}
+
+// ListObjectsPageResponse is one page of the objects of a ListObjectsPageRequest.
+type ListObjectsPageResponse struct {
+	Objects []string `json:"objects"`
+	// ContinuationToken is empty when there are no more objects.
+	ContinuationToken string `json:"continuation_token"`
+	// Truncated is true when the deadline was exceeded before the page was full. The objects that were not
+	// checked yet are then part of the pages that follow it.
+	Truncated          bool                          `json:"truncated"`
+	ResolutionMetadata ListObjectsResolutionMetadata `json:"-"`
+}
+
+// listObjectsPageToken resumes a ListObjects request after the last object of the previous page. It is bound
+// to the request it was issued for, and to the model that resolved it.
+type listObjectsPageToken struct {
+	After                string `json:"after"`
+	AuthorizationModelID string `json:"authorization_model_id"`
+	Request              string `json:"request"`
+}
+
+// ListObjectsPageAuthorizationModelID returns the ID of the model that resolved the pages preceding
+// continuationToken, so that the following pages are resolved with the same model.
+func ListObjectsPageAuthorizationModelID(continuationToken string) (string, error) {
+	token, err := decodeListObjectsPageToken(encoder.NewBase64Encoder(), continuationToken)
+	if err != nil {
+		return "", err
+	}
+	if token == nil {
+		return "", nil
+	}
+	return token.AuthorizationModelID, nil
+}
+
+// ExecutePage returns the page of objects that follows req.ContinuationToken. Objects are sorted by ID and
+// deduplicated, so that the pages of a request are disjoint and, as long as the tuples don't change, the same
+// every time they are read.
+//
+// When the objects are the objects of the tuples that directly relate them to the user, the page is read from
+// those tuples sorted by object ID, and the read stops once the page is full. Otherwise, the candidate objects
+// are read sorted by object ID and checked in that order until the page is full, see evaluatePage. If the
+// deadline, q.listObjectsDeadline, is exceeded before, the objects found so far are returned as a Truncated
+// page whose token resumes after the last candidate that was checked, or the page fails with a deadline
+// exceeded error if no candidate was checked. q.listObjectsMaxResults does not apply to pages.
+func (q *ListObjectsQuery) ExecutePage(ctx context.Context, req *ListObjectsPageRequest) (*ListObjectsPageResponse, error) {
+	typesys, err := validateListObjectsRequest(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	fingerprint, err := listObjectsPageFingerprint(req.ListObjectsRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	enc := encoder.NewBase64Encoder()
+	token, err := decodeListObjectsPageToken(enc, req.ContinuationToken)
+	if err != nil {
+		return nil, err
+	}
+	if token != nil && (token.Request != fingerprint || token.AuthorizationModelID != req.GetAuthorizationModelId()) {
+		return nil, serverErrors.ErrInvalidContinuationToken
+	}
+	req.Context = contextprovider.MergeFields(req.TrustedContextFields, req.GetContext())
+
+	pageSize := int(req.PageSize)
+	if pageSize == 0 {
+		pageSize = defaultListObjectsPageSize
+	}
+
+	// One more object than the page size is collected to know whether another page follows.
+	page := &listObjectsPage{limit: pageSize + 1, objects: make([]string, 0, pageSize+1)}
+	if token != nil {
+		page.after = token.After
+	}
+
+	timeoutCtx := ctx
+	if q.listObjectsDeadline != 0 {
+		var cancel context.CancelFunc
+		timeoutCtx, cancel = context.WithTimeout(ctx, q.listObjectsDeadline)
+		defer cancel()
+	}
+
+	q.invalidateIteratorCaches(ctx, req)
+
+	res := &ListObjectsPageResponse{}
+	if isDirectOnlyRelation(typesys, req) {
+		err = q.readPage(timeoutCtx, typesys, req, page, &res.ResolutionMetadata)
+	} else {
+		res.Truncated, err = q.evaluatePage(timeoutCtx, typesys, req, page, &res.ResolutionMetadata)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	res.Objects = page.objects
+	if len(res.Objects) > pageSize {
+		res.Objects = res.Objects[:pageSize]
+	}
+	// A truncated page is followed by another page, which resumes after the last candidate that was checked.
+	after := ""
+	switch {
+	case len(page.objects) > pageSize:
+		after = res.Objects[len(res.Objects)-1]
+	case res.Truncated:
+		after = page.evaluated
+	}
+	if after != "" {
+		res.ContinuationToken, err = encodeListObjectsPageToken(enc, &listObjectsPageToken{
+			After:                after,
+			AuthorizationModelID: req.GetAuthorizationModelId(),
+			Request:              fingerprint,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+// evaluatePage collects the page from the candidate objects of req that sort after the continuation token,
+// see pageCandidates, which are checked in ascending order until the page is full. If the deadline is exceeded
+// before, it returns true and page.evaluated is the last candidate that was checked, after which the next page
+// resumes.
+func (q *ListObjectsQuery) evaluatePage(
+	ctx context.Context,
+	typesys *typesystem.TypeSystem,
+	req *ListObjectsPageRequest,
+	page *listObjectsPage,
+	resolutionMetadata *ListObjectsResolutionMetadata,
+) (bool, error) {
+	ds := q.requestStorage(req)
+	defer func() {
+		dsMeta := ds.GetMetadata()
+		resolutionMetadata.DatastoreQueryCount.Add(dsMeta.DatastoreQueryCount)
+		resolutionMetadata.WasThrottled.CompareAndSwap(false, dsMeta.WasThrottled)
+	}()
+
+	page.evaluated = page.after
+	candidates, err := q.pageCandidates(ctx, typesys, ds, req, page.after, resolutionMetadata)
+	if err != nil {
+		return endPage(ctx, page, err)
+	}
+	defer candidates.stop()
+
+	for len(page.objects) < page.limit {
+		objects, err := candidates.next(ctx, page.limit-len(page.objects))
+		if err != nil {
+			return endPage(ctx, page, err)
+		}
+		if len(objects) == 0 {
+			return false, nil
+		}
+
+		allowed, err := q.checkPageCandidates(ctx, typesys, req, objects, resolutionMetadata)
+		for i, ok := range allowed {
+			if ok {
+				page.add(objects[i])
+			}
+		}
+		if len(allowed) > 0 {
+			page.evaluated = objects[len(allowed)-1]
+		}
+		if err != nil {
+			return endPage(ctx, page, err)
+		}
+	}
+	return false, nil
+}
+
+// endPage ends the page of evaluatePage, which stopped with err. If the deadline was exceeded after at least
+// one candidate was checked, the page is truncated; otherwise the page fails.
+func endPage(ctx context.Context, page *listObjectsPage, err error) (bool, error) {
+	if ctx.Err() != nil {
+		if page.evaluated != page.after {
+			return true, nil
+		}
+		return false, serverErrors.HandleError("", ctx.Err())
+	}
+	if errors.Is(err, graph.ErrResolutionDepthExceeded) {
+		return false, serverErrors.ErrAuthorizationModelResolutionTooComplex
+	}
+	if errors.Is(err, condition.ErrEvaluationFailed) {
+		return false, serverErrors.ValidationError(err)
+	}
+	return false, serverErrors.HandleError("", err)
+}
+
+// checkPageCandidates checks objects concurrently. The returned slice holds the result of the leading objects
+// that were checked before the first error, if any, which is returned with it.
+func (q *ListObjectsQuery) checkPageCandidates(
+	ctx context.Context,
+	typesys *typesystem.TypeSystem,
+	req *ListObjectsPageRequest,
+	objects []string,
+	resolutionMetadata *ListObjectsResolutionMetadata,
+) ([]bool, error) {
+	allowed := make([]bool, len(objects))
+	errs := make([]error, len(objects))
+	pool := concurrency.NewPool(ctx, max(1, int(q.resolveNodeBreadthLimit)))
+	for i, object := range objects {
+		pool.Go(func(ctx context.Context) error {
+			allowed[i], errs[i] = q.checkObject(ctx, typesys, req, object, resolutionMetadata)
+			return errs[i]
+		})
+	}
+	// The first error is the one that canceled the other checks.
+	err := pool.Wait()
+	if checked := slices.IndexFunc(errs, func(err error) bool { return err != nil }); checked >= 0 {
+		return allowed[:checked], err
+	}
+	return allowed, nil
+}
+
+// pageCandidates returns the candidate objects of req that sort after the object after, in ascending order.
+// An object can only have a relation with a user through its own tuples, so the candidates are the objects of
+// the tuples of the relations that the relation of req is computed from, whose user is either:
+//   - the user of req, or the wildcard of its type;
+//   - a userset that includes the user of req;
+//   - for a tupleset relation, a parent object that has the computed relation of the tupleset with the user.
+//
+// Each of those reads is sorted by object, and the reads are merged into one stream without duplicates. The
+// usersets and parents are listed with a complete ListObjects each, whose cost does not depend on the number
+// of objects of the type of req unless the relation is recursive.
+func (q *ListObjectsQuery) pageCandidates(
+	ctx context.Context,
+	typesys *typesystem.TypeSystem,
+	ds storage.RelationshipTupleReader,
+	req *ListObjectsPageRequest,
+	after string,
+	resolutionMetadata *ListObjectsResolutionMetadata,
+) (*listObjectsPageCandidates, error) {
+	filters, err := q.pageCandidateFilters(ctx, typesys, req, resolutionMetadata)
+	if err != nil {
+		return nil, err
+	}
+
+	streams := make([]*iterator.Stream, 0, len(filters))
+	for _, filter := range filters {
+		tupleIter, err := ds.ReadStartingWithUser(ctx, req.GetStoreId(), filter, storage.ReadStartingWithUserOptions{
+			Consistency:                storage.ConsistencyOptions{Preference: req.GetConsistency()},
+			WithResultsSortedAscending: true,
+		})
+		if err != nil {
+			iterator.NewStreams(streams).Stop()
+			return nil, err
+		}
+		source := make(chan *iterator.Msg, 1)
+		source <- &iterator.Msg{Iter: storage.WrapIterator(storage.ObjectIDKind, storage.NewFilteredTupleKeyIterator(
+			storage.NewTupleKeyIteratorFromTupleIterator(tupleIter),
+			validation.FilterInvalidTuples(typesys),
+		))}
+		close(source)
+		streams = append(streams, iterator.NewStream(len(streams), source))
+	}
+	return &listObjectsPageCandidates{streams: iterator.NewStreams(streams), after: after}, nil
+}
+
+// pageCandidateFilters returns the reads of the tuples whose objects are the candidates of req, see
+// pageCandidates.
+func (q *ListObjectsQuery) pageCandidateFilters(
+	ctx context.Context,
+	typesys *typesystem.TypeSystem,
+	req *ListObjectsPageRequest,
+	resolutionMetadata *ListObjectsResolutionMetadata,
+) ([]storage.ReadStartingWithUserFilter, error) {
+	objectType := req.GetType()
+	userObject, userRelation := tuple.SplitObjectRelation(req.GetUser())
+	userType := tuple.GetType(userObject)
+
+	related := make(map[string][]string)
+	listRelated := func(objectType, relation string) ([]string, error) {
+		key := tuple.ToObjectRelationString(objectType, relation)
+		if objects, ok := related[key]; ok {
+			return objects, nil
+		}
+		objects, err := q.listRelatedObjects(ctx, req, objectType, relation, resolutionMetadata)
+		if err != nil {
+			return nil, err
+		}
+		related[key] = objects
+		return objects, nil
+	}
+
+	var filters []storage.ReadStartingWithUserFilter
+	addFilters := func(relation string, users []*openfgav1.ObjectRelation) {
+		for chunk := range slices.Chunk(users, listObjectsPageUserFilterSize) {
+			filters = append(filters, storage.ReadStartingWithUserFilter{
+				ObjectType: objectType,
+				Relation:   relation,
+				UserFilter: chunk,
+				ObjectIDs:  q.candidateObjectIDs,
+			})
+		}
+	}
+
+	visited := make(map[string]struct{})
+	relations := []string{req.GetRelation()}
+	for len(relations) > 0 {
+		relation := relations[0]
+		relations = relations[1:]
+		if _, ok := visited[relation]; ok {
+			continue
+		}
+		visited[relation] = struct{}{}
+
+		rel, err := typesys.GetRelation(objectType, relation)
+		if err != nil {
+			return nil, err
+		}
+		// The handler may be called more than once for the same rewrite.
+		direct := false
+		tuplesets := make(map[string]map[string]struct{})
+		_, err = typesystem.WalkUsersetRewrite(rel.GetRewrite(), func(rewrite *openfgav1.Userset) interface{} {
+			switch rw := rewrite.GetUserset().(type) {
+			case *openfgav1.Userset_This:
+				direct = true
+			case *openfgav1.Userset_ComputedUserset:
+				relations = append(relations, rw.ComputedUserset.GetRelation())
+			case *openfgav1.Userset_TupleToUserset:
+				tupleset := rw.TupleToUserset.GetTupleset().GetRelation()
+				if tuplesets[tupleset] == nil {
+					tuplesets[tupleset] = make(map[string]struct{})
+				}
+				tuplesets[tupleset][rw.TupleToUserset.GetComputedUserset().GetRelation()] = struct{}{}
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		if direct {
+			directlyRelatedTypes, err := typesys.GetDirectlyRelatedUserTypes(objectType, relation)
+			if err != nil {
+				return nil, err
+			}
+			var users []*openfgav1.ObjectRelation
+			for _, ref := range directlyRelatedTypes {
+				switch {
+				case ref.GetWildcard() != nil:
+					if userRelation == "" && ref.GetType() == userType {
+						users = append(users, &openfgav1.ObjectRelation{Object: tuple.TypedPublicWildcard(userType)})
+					}
+				case ref.GetRelation() != "":
+					if userRelation == ref.GetRelation() && userType == ref.GetType() {
+						users = append(users, &openfgav1.ObjectRelation{Object: userObject, Relation: userRelation})
+					}
+					objects, err := listRelated(ref.GetType(), ref.GetRelation())
+					if err != nil {
+						return nil, err
+					}
+					for _, object := range objects {
+						users = append(users, &openfgav1.ObjectRelation{Object: object, Relation: ref.GetRelation()})
+					}
+				case userRelation == "" && ref.GetType() == userType:
+					users = append(users, &openfgav1.ObjectRelation{Object: userObject})
+				}
+			}
+			addFilters(relation, users)
+		}
+
+		for tupleset, computedRelations := range tuplesets {
+			directlyRelatedTypes, err := typesys.GetDirectlyRelatedUserTypes(objectType, tupleset)
+			if err != nil {
+				return nil, err
+			}
+			var users []*openfgav1.ObjectRelation
+			for _, ref := range directlyRelatedTypes {
+				for computedRelation := range computedRelations {
+					if _, err := typesys.GetRelation(ref.GetType(), computedRelation); err != nil {
+						continue
+					}
+					objects, err := listRelated(ref.GetType(), computedRelation)
+					if err != nil {
+						return nil, err
+					}
+					for _, object := range objects {
+						users = append(users, &openfgav1.ObjectRelation{Object: object})
+					}
+				}
+			}
+			addFilters(tupleset, users)
+		}
+	}
+	return filters, nil
+}
+
+// listRelatedObjects returns every object of objectType that has relation with the user of req.
+func (q *ListObjectsQuery) listRelatedObjects(
+	ctx context.Context,
+	req *ListObjectsPageRequest,
+	objectType, relation string,
+	resolutionMetadata *ListObjectsResolutionMetadata,
+) ([]string, error) {
+	// The candidates of q are objects of the type of req, which may not be objectType.
+	related := *q
+	related.candidateObjectIDs = nil
+
+	var relatedMetadata ListObjectsResolutionMetadata
+	resultsChan := make(chan ListObjectsResult, streamedBufferSize)
+	err := related.evaluate(ctx, &openfgav1.ListObjectsRequest{
+		StoreId:          req.GetStoreId(),
+		Type:             objectType,
+		Relation:         relation,
+		User:             req.GetUser(),
+		ContextualTuples: req.GetContextualTuples(),
+		Context:          req.GetContext(),
+		Consistency:      req.GetConsistency(),
+	}, resultsChan, 0, &relatedMetadata)
+	if err != nil {
+		return nil, err
+	}
+
+	var objects []string
+	var errs error
+	for result := range resultsChan {
+		if result.Err != nil {
+			if errs == nil {
+				errs = result.Err
+			}
+			continue
+		}
+		objects = append(objects, result.ObjectID)
+	}
+	resolutionMetadata.DatastoreQueryCount.Add(relatedMetadata.DatastoreQueryCount.Load())
+	resolutionMetadata.DispatchCounter.Add(relatedMetadata.DispatchCounter.Load())
+	resolutionMetadata.CheckCounter.Add(relatedMetadata.CheckCounter.Load())
+	resolutionMetadata.WasThrottled.CompareAndSwap(false, relatedMetadata.WasThrottled.Load())
+
+	if errs != nil {
+		return nil, errs
+	}
+	// Objects missing from the list would be candidates missing from the page.
+	if relatedMetadata.WasTruncated.Load() {
+		return nil, context.DeadlineExceeded
+	}
+	return objects, nil
+}
+
+// listObjectsPageCandidates merges the sorted streams of candidate objects of a page.
+type listObjectsPageCandidates struct {
+	streams *iterator.Streams
+	after   string
+}
+
+// next returns the next n candidates, or fewer if the streams are exhausted.
+func (c *listObjectsPageCandidates) next(ctx context.Context, n int) ([]string, error) {
+	objects := make([]string, 0, n)
+	for len(objects) < n {
+		object, err := c.nextObject(ctx)
+		if err != nil {
+			if errors.Is(err, storage.ErrIteratorDone) {
+				break
+			}
+			return nil, err
+		}
+		objects = append(objects, object)
+	}
+	return objects, nil
+}
+
+func (c *listObjectsPageCandidates) nextObject(ctx context.Context) (string, error) {
+	for {
+		// A stream canceled by the context is dropped as if it was exhausted.
+		if err := ctx.Err(); err != nil {
+			return "", err
+		}
+		streams, err := c.streams.CleanDone(ctx)
+		if err != nil {
+			return "", err
+		}
+		if len(streams) == 0 {
+			return "", storage.ErrIteratorDone
+		}
+
+		minObject := ""
+		var minStreams []int
+		exhausted := false
+		for idx, stream := range streams {
+			object, err := stream.Head(ctx)
+			if err != nil {
+				if storage.IterIsDoneOrCancelled(err) {
+					exhausted = true
+					break
+				}
+				return "", err
+			}
+			switch {
+			case len(minStreams) == 0 || object < minObject:
+				minObject = object
+				minStreams = []int{idx}
+			case object == minObject:
+				minStreams = append(minStreams, idx)
+			}
+		}
+		if exhausted {
+			// The exhausted stream is dropped before the heads are compared again.
+			continue
+		}
+
+		if _, err := iterator.NextItemInSliceStreams(ctx, streams, minStreams); err != nil {
+			return "", err
+		}
+		if minObject > c.after {
+			return minObject, nil
+		}
+	}
+}
+
+func (c *listObjectsPageCandidates) stop() {
+	c.streams.Stop()
+}
+
+// isDirectOnlyRelation returns true if the objects of req are the objects of the tuples that directly relate
+// them to the user of req, in which case the page can be read from the tuples sorted by object ID. That is
+// the case when the relation is only directly assignable, to no userset, and the user is not a userset.
+func isDirectOnlyRelation(typesys *typesystem.TypeSystem, req *ListObjectsPageRequest) bool {
+	if tuple.IsObjectRelation(req.GetUser()) {
+		return false
+	}
+	relation, err := typesys.GetRelation(req.GetType(), req.GetRelation())
+	if err != nil {
+		return false
+	}
+	if _, ok := relation.GetRewrite().GetUserset().(*openfgav1.Userset_This); !ok {
+		return false
+	}
+	directlyRelatedTypes, err := typesys.GetDirectlyRelatedUserTypes(req.GetType(), req.GetRelation())
+	if err != nil {
+		return false
+	}
+	for _, ref := range directlyRelatedTypes {
+		if ref.GetRelation() != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// readPage reads the page from the tuples of the relation of req, which are read sorted by object ID, and
+// stops as soon as the page is full.
+func (q *ListObjectsQuery) readPage(
+	ctx context.Context,
+	typesys *typesystem.TypeSystem,
+	req *ListObjectsPageRequest,
+	page *listObjectsPage,
+	resolutionMetadata *ListObjectsResolutionMetadata,
+) error {
+	ds := q.requestStorage(req)
+	defer func() {
+		dsMeta := ds.GetMetadata()
+		resolutionMetadata.DatastoreQueryCount.Add(dsMeta.DatastoreQueryCount)
+		resolutionMetadata.WasThrottled.CompareAndSwap(false, dsMeta.WasThrottled)
+	}()
+
+	user := req.GetUser()
+	userFilter := []*openfgav1.ObjectRelation{{Object: user}}
+	if !tuple.IsTypedWildcard(user) {
+		userType := tuple.GetType(user)
+		if public, _ := typesys.IsPubliclyAssignable(typesystem.DirectRelationReference(req.GetType(), req.GetRelation()), userType); public {
+			userFilter = append(userFilter, &openfgav1.ObjectRelation{Object: tuple.TypedPublicWildcard(userType)})
+		}
+	}
+
+	tupleIter, err := ds.ReadStartingWithUser(ctx, req.GetStoreId(), storage.ReadStartingWithUserFilter{
+		ObjectType: req.GetType(),
+		Relation:   req.GetRelation(),
+		UserFilter: userFilter,
+		ObjectIDs:  q.candidateObjectIDs,
+	}, storage.ReadStartingWithUserOptions{
+		Consistency:                storage.ConsistencyOptions{Preference: req.GetConsistency()},
+		WithResultsSortedAscending: true,
+	})
+	if err != nil {
+		return serverErrors.HandleError("", err)
+	}
+	iter := storage.NewFilteredTupleKeyIterator(
+		storage.NewTupleKeyIteratorFromTupleIterator(tupleIter),
+		validation.FilterInvalidTuples(typesys),
+	)
+	defer iter.Stop()
+
+	conditionFilter := checkutil.BuildTupleKeyConditionFilter(ctx, req.GetContext(), typesys)
+	for {
+		tk, err := iter.Next(ctx)
+		if err != nil {
+			if errors.Is(err, storage.ErrIteratorDone) {
+				return nil
+			}
+			return serverErrors.HandleError("", err)
+		}
+
+		object := tk.GetObject()
+		if object <= page.after || !q.isCandidate(object) {
+			continue
+		}
+		if !page.accepts(object) {
+			// Every following object sorts after the last object of the full page.
+			return nil
+		}
+
+		valid, err := conditionFilter(tk)
+		if err != nil {
+			return serverErrors.ValidationError(err)
+		}
+		if valid {
+			page.add(object)
+		}
+	}
+}
+
+// listObjectsPage keeps, sorted, the smallest objects after the continuation token found so far.
+type listObjectsPage struct {
+	mu      sync.Mutex
+	after   string
+	limit   int
+	objects []string
+	// evaluated is the last candidate object checked by evaluatePage, after which a truncated page resumes.
+	evaluated string
+}
+
+// accepts returns true if object can still be part of the page.
+func (p *listObjectsPage) accepts(object string) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.acceptsLocked(object)
+}
+
+func (p *listObjectsPage) acceptsLocked(object string) bool {
+	if object <= p.after {
+		return false
+	}
+	return len(p.objects) < p.limit || object < p.objects[len(p.objects)-1]
+}
+
+func (p *listObjectsPage) add(object string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !p.acceptsLocked(object) {
+		return
+	}
+	i, found := slices.BinarySearch(p.objects, object)
+	if found {
+		return
+	}
+	p.objects = slices.Insert(p.objects, i, object)
+	if len(p.objects) > p.limit {
+		p.objects = p.objects[:p.limit]
+	}
+}
+
+// listObjectsPageFingerprint identifies the parameters of req that select its objects, including the condition
+// context sent by the caller. The model and the consistency preference are not part of it; the model is recorded
+// in the token separately.
+func listObjectsPageFingerprint(req *openfgav1.ListObjectsRequest) (string, error) {
+	marshalled, err := proto.MarshalOptions{Deterministic: true}.Marshal(&openfgav1.ListObjectsRequest{
+		StoreId:          req.GetStoreId(),
+		Type:             req.GetType(),
+		Relation:         req.GetRelation(),
+		User:             req.GetUser(),
+		ContextualTuples: req.GetContextualTuples(),
+		Context:          req.GetContext(),
+	})
+	if err != nil {
+		return "", serverErrors.HandleError("", err)
+	}
+	sum := sha256.Sum256(marshalled)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func encodeListObjectsPageToken(enc encoder.Encoder, token *listObjectsPageToken) (string, error) {
+	marshalled, err := json.Marshal(token)
+	if err != nil {
+		return "", serverErrors.HandleError("", err)
+	}
+	return enc.Encode(marshalled)
+}
+
+func decodeListObjectsPageToken(enc encoder.Encoder, continuationToken string) (*listObjectsPageToken, error) {
+	if continuationToken == "" {
+		return nil, nil
+	}
+	decoded, err := enc.Decode(continuationToken)
+	if err != nil {
+		return nil, serverErrors.ErrInvalidContinuationToken
+	}
+	var token listObjectsPageToken
+	if err := json.Unmarshal(decoded, &token); err != nil {
+		return nil, serverErrors.ErrInvalidContinuationToken
+	}
+	return &token, nil
+}
@@ -0,0 +1,354 @@
+package commands
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+
+	"github.com/openfga/openfga/internal/graph"
+	serverErrors "github.com/openfga/openfga/pkg/server/errors"
+	"github.com/openfga/openfga/pkg/storage/memory"
+	storagetest "github.com/openfga/openfga/pkg/storage/test"
+	"github.com/openfga/openfga/pkg/testutils"
+	"github.com/openfga/openfga/pkg/tuple"
+	"github.com/openfga/openfga/pkg/typesystem"
+)
+
+func TestListObjectsExecutePage(t *testing.T) {
+	t.Cleanup(func() {
+		goleak.VerifyNone(t)
+	})
+
+	ds := memory.New()
+	t.Cleanup(ds.Close)
+
+	storeID, model := storagetest.BootstrapFGAStore(t, ds, `
+		model
+			schema 1.1
+
+		type user
+
+		type document
+			relations
+				define owner: [user, user:*]
+				define editor: [user]
+				define viewer: owner or editor`,
+		[]string{
+			"document:e#owner@user:jon",
+			"document:c#owner@user:jon",
+			"document:a#owner@user:*",
+			"document:d#owner@user:jon",
+			"document:b#editor@user:jon",
+			"document:c#editor@user:jon",
+			"document:e#editor@user:jon",
+		})
+	typesys, err := typesystem.NewAndValidate(context.Background(), model)
+	require.NoError(t, err)
+	ctx := typesystem.ContextWithTypesystem(context.Background(), typesys)
+
+	checker, checkResolverCloser, err := graph.NewOrderedCheckResolvers().Build()
+	require.NoError(t, err)
+	t.Cleanup(checkResolverCloser)
+
+	q, err := NewListObjectsQuery(ds, checker)
+	require.NoError(t, err)
+
+	readPages := func(t *testing.T, req *openfgav1.ListObjectsRequest, pageSize int32) [][]string {
+		var pages [][]string
+		token := ""
+		for {
+			res, err := q.ExecutePage(ctx, &ListObjectsPageRequest{
+				ListObjectsRequest: req,
+				PageSize:           pageSize,
+				ContinuationToken:  token,
+			})
+			require.NoError(t, err)
+			pages = append(pages, res.Objects)
+			if res.ContinuationToken == "" {
+				return pages
+			}
+			token = res.ContinuationToken
+		}
+	}
+
+	tests := []struct {
+		name     string
+		req      *openfgav1.ListObjectsRequest
+		pageSize int32
+		expected [][]string
+	}{
+		{
+			name: "direct_relation",
+			req: &openfgav1.ListObjectsRequest{
+				StoreId:              storeID,
+				AuthorizationModelId: model.GetId(),
+				Type:                 "document",
+				Relation:             "owner",
+				User:                 "user:jon",
+			},
+			pageSize: 2,
+			expected: [][]string{{"document:a", "document:c"}, {"document:d", "document:e"}},
+		},
+		{
+			name: "direct_relation_with_contextual_tuples",
+			req: &openfgav1.ListObjectsRequest{
+				StoreId:              storeID,
+				AuthorizationModelId: model.GetId(),
+				Type:                 "document",
+				Relation:             "owner",
+				User:                 "user:jon",
+				ContextualTuples: &openfgav1.ContextualTupleKeys{TupleKeys: []*openfgav1.TupleKey{
+					tuple.NewTupleKey("document:b", "owner", "user:jon"),
+					tuple.NewTupleKey("document:c", "owner", "user:jon"),
+				}},
+			},
+			pageSize: 3,
+			expected: [][]string{{"document:a", "document:b", "document:c"}, {"document:d", "document:e"}},
+		},
+		{
+			name: "computed_relation_deduplicated",
+			req: &openfgav1.ListObjectsRequest{
+				StoreId:              storeID,
+				AuthorizationModelId: model.GetId(),
+				Type:                 "document",
+				Relation:             "viewer",
+				User:                 "user:jon",
+			},
+			pageSize: 2,
+			expected: [][]string{{"document:a", "document:b"}, {"document:c", "document:d"}, {"document:e"}},
+		},
+		{
+			name: "default_page_size",
+			req: &openfgav1.ListObjectsRequest{
+				StoreId:              storeID,
+				AuthorizationModelId: model.GetId(),
+				Type:                 "document",
+				Relation:             "viewer",
+				User:                 "user:jon",
+			},
+			expected: [][]string{{"document:a", "document:b", "document:c", "document:d", "document:e"}},
+		},
+		{
+			name: "no_objects",
+			req: &openfgav1.ListObjectsRequest{
+				StoreId:              storeID,
+				AuthorizationModelId: model.GetId(),
+				Type:                 "document",
+				Relation:             "editor",
+				User:                 "user:anne",
+			},
+			pageSize: 2,
+			expected: [][]string{{}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.Equal(t, test.expected, readPages(t, test.req, test.pageSize))
+		})
+	}
+
+	t.Run("token_of_another_request", func(t *testing.T) {
+		req := &openfgav1.ListObjectsRequest{
+			StoreId:              storeID,
+			AuthorizationModelId: model.GetId(),
+			Type:                 "document",
+			Relation:             "viewer",
+			User:                 "user:jon",
+		}
+		res, err := q.ExecutePage(ctx, &ListObjectsPageRequest{ListObjectsRequest: req, PageSize: 1})
+		require.NoError(t, err)
+		require.NotEmpty(t, res.ContinuationToken)
+
+		req.User = "user:anne"
+		_, err = q.ExecutePage(ctx, &ListObjectsPageRequest{ListObjectsRequest: req, PageSize: 1, ContinuationToken: res.ContinuationToken})
+		require.ErrorIs(t, err, serverErrors.ErrInvalidContinuationToken)
+
+		_, err = q.ExecutePage(ctx, &ListObjectsPageRequest{ListObjectsRequest: req, PageSize: 1, ContinuationToken: "invalid"})
+		require.ErrorIs(t, err, serverErrors.ErrInvalidContinuationToken)
+	})
+
+	t.Run("token_of_another_context", func(t *testing.T) {
+		req := &openfgav1.ListObjectsRequest{
+			StoreId:              storeID,
+			AuthorizationModelId: model.GetId(),
+			Type:                 "document",
+			Relation:             "viewer",
+			User:                 "user:jon",
+			Context:              testutils.MustNewStruct(t, map[string]any{"x": 1}),
+		}
+		trusted := map[string]*structpb.Value{"current_time": structpb.NewStringValue("2024-01-01T00:00:00Z")}
+		res, err := q.ExecutePage(ctx, &ListObjectsPageRequest{ListObjectsRequest: req, PageSize: 1, TrustedContextFields: trusted})
+		require.NoError(t, err)
+		require.NotEmpty(t, res.ContinuationToken)
+		require.Contains(t, req.GetContext().GetFields(), "current_time")
+
+		// The trusted values may change from page to page.
+		req.Context = testutils.MustNewStruct(t, map[string]any{"x": 1})
+		trusted = map[string]*structpb.Value{"current_time": structpb.NewStringValue("2024-01-01T00:00:01Z")}
+		_, err = q.ExecutePage(ctx, &ListObjectsPageRequest{ListObjectsRequest: req, PageSize: 1, ContinuationToken: res.ContinuationToken, TrustedContextFields: trusted})
+		require.NoError(t, err)
+
+		req.Context = testutils.MustNewStruct(t, map[string]any{"x": 2})
+		_, err = q.ExecutePage(ctx, &ListObjectsPageRequest{ListObjectsRequest: req, PageSize: 1, ContinuationToken: res.ContinuationToken})
+		require.ErrorIs(t, err, serverErrors.ErrInvalidContinuationToken)
+	})
+
+	t.Run("model_of_the_token", func(t *testing.T) {
+		res, err := q.ExecutePage(ctx, &ListObjectsPageRequest{
+			ListObjectsRequest: &openfgav1.ListObjectsRequest{
+				StoreId:              storeID,
+				AuthorizationModelId: model.GetId(),
+				Type:                 "document",
+				Relation:             "viewer",
+				User:                 "user:jon",
+			},
+			PageSize: 1,
+		})
+		require.NoError(t, err)
+
+		modelID, err := ListObjectsPageAuthorizationModelID(res.ContinuationToken)
+		require.NoError(t, err)
+		require.Equal(t, model.GetId(), modelID)
+
+		modelID, err = ListObjectsPageAuthorizationModelID("")
+		require.NoError(t, err)
+		require.Empty(t, modelID)
+	})
+}
+
+func TestListObjectsExecutePageCandidates(t *testing.T) {
+	t.Cleanup(func() {
+		goleak.VerifyNone(t)
+	})
+
+	ds := memory.New()
+	t.Cleanup(ds.Close)
+
+	storeID, model := storagetest.BootstrapFGAStore(t, ds, `
+		model
+			schema 1.1
+
+		type user
+
+		type group
+			relations
+				define member: [user, group#member]
+
+		type folder
+			relations
+				define viewer: [user, group#member]
+
+		type document
+			relations
+				define parent: [folder]
+				define blocked: [user]
+				define owner: [user]
+				define viewer: (owner or viewer from parent) but not blocked`,
+		[]string{
+			"group:eng#member@user:jon",
+			"group:all#member@group:eng#member",
+			"folder:x#viewer@group:all#member",
+			"folder:y#viewer@user:anne",
+			"document:a#parent@folder:x",
+			"document:b#owner@user:jon",
+			"document:c#parent@folder:y",
+			"document:d#parent@folder:x",
+			"document:d#blocked@user:jon",
+			"document:e#parent@folder:x",
+			"document:f#owner@user:jon",
+			"document:f#parent@folder:x",
+		})
+	typesys, err := typesystem.NewAndValidate(context.Background(), model)
+	require.NoError(t, err)
+	ctx := typesystem.ContextWithTypesystem(context.Background(), typesys)
+
+	checker, checkResolverCloser, err := graph.NewOrderedCheckResolvers().Build()
+	require.NoError(t, err)
+	t.Cleanup(checkResolverCloser)
+
+	req := &openfgav1.ListObjectsRequest{
+		StoreId:              storeID,
+		AuthorizationModelId: model.GetId(),
+		Type:                 "document",
+		Relation:             "viewer",
+		User:                 "user:jon",
+	}
+
+	t.Run("pages", func(t *testing.T) {
+		q, err := NewListObjectsQuery(ds, checker)
+		require.NoError(t, err)
+
+		for pageSize, expected := range map[int32][][]string{
+			1: {{"document:a"}, {"document:b"}, {"document:e"}, {"document:f"}},
+			3: {{"document:a", "document:b", "document:e"}, {"document:f"}},
+			4: {{"document:a", "document:b", "document:e", "document:f"}},
+		} {
+			var pages [][]string
+			token := ""
+			for {
+				res, err := q.ExecutePage(ctx, &ListObjectsPageRequest{ListObjectsRequest: req, PageSize: pageSize, ContinuationToken: token})
+				require.NoError(t, err)
+				require.False(t, res.Truncated)
+				pages = append(pages, res.Objects)
+				if res.ContinuationToken == "" {
+					break
+				}
+				token = res.ContinuationToken
+			}
+			require.Equal(t, expected, pages, "page size %d", pageSize)
+		}
+	})
+
+	t.Run("deadline_before_any_check", func(t *testing.T) {
+		q, err := NewListObjectsQuery(ds, checker, WithListObjectsDeadline(time.Nanosecond))
+		require.NoError(t, err)
+
+		_, err = q.ExecutePage(ctx, &ListObjectsPageRequest{ListObjectsRequest: req, PageSize: 2})
+		require.ErrorIs(t, err, serverErrors.ErrRequestDeadlineExceeded)
+	})
+}
+
+func TestEndPage(t *testing.T) {
+	canceledCtx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	t.Run("truncated_after_the_last_checked_candidate", func(t *testing.T) {
+		truncated, err := endPage(canceledCtx, &listObjectsPage{after: "document:a", evaluated: "document:c"}, context.Canceled)
+		require.NoError(t, err)
+		require.True(t, truncated)
+	})
+
+	t.Run("no_candidate_checked", func(t *testing.T) {
+		_, err := endPage(canceledCtx, &listObjectsPage{after: "document:a", evaluated: "document:a"}, context.Canceled)
+		require.ErrorIs(t, err, serverErrors.ErrRequestCancelled)
+	})
+
+	t.Run("resolution_too_complex", func(t *testing.T) {
+		_, err := endPage(context.Background(), &listObjectsPage{}, graph.ErrResolutionDepthExceeded)
+		require.ErrorIs(t, err, serverErrors.ErrAuthorizationModelResolutionTooComplex)
+	})
+}
+
+func TestListObjectsPageRequestValidate(t *testing.T) {
+	req := &ListObjectsPageRequest{
+		ListObjectsRequest: &openfgav1.ListObjectsRequest{
+			StoreId:  "01JBDC5AQCZC4J8AC3TNC4PH5B",
+			Type:     "document",
+			Relation: "viewer",
+			User:     "user:jon",
+		},
+	}
+	require.NoError(t, req.Validate())
+
+	req.PageSize = maxListObjectsPageSize + 1
+	require.ErrorContains(t, req.Validate(), "page_size must be between 0 and 1000")
+
+	require.Error(t, (&ListObjectsPageRequest{}).Validate())
+}
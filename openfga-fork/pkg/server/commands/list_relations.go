@@ -0,0 +1,286 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/protobuf/types/known/structpb"
+
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+
+	"github.com/openfga/openfga/internal/cachecontroller"
+	"github.com/openfga/openfga/internal/concurrency"
+	"github.com/openfga/openfga/internal/graph"
+	"github.com/openfga/openfga/internal/shared"
+	"github.com/openfga/openfga/internal/utils/apimethod"
+	"github.com/openfga/openfga/pkg/logger"
+	"github.com/openfga/openfga/pkg/server/config"
+	"github.com/openfga/openfga/pkg/storage"
+	"github.com/openfga/openfga/pkg/storage/storagewrappers"
+	"github.com/openfga/openfga/pkg/storage/storagewrappers/sharediterator"
+	"github.com/openfga/openfga/pkg/tuple"
+	"github.com/openfga/openfga/pkg/typesystem"
+)
+
+// ListRelationsRequest asks which relations a user has with an object.
+type ListRelationsRequest struct {
+	StoreID              string
+	AuthorizationModelID string
+	Object               string
+	User                 string
+	// Relations are the candidate relations. If empty, every relation of the type of the object is evaluated.
+	Relations        []string
+	ContextualTuples *openfgav1.ContextualTupleKeys
+	Context          *structpb.Struct
+	Consistency      openfgav1.ConsistencyPreference
+}
+
+// Validate checks the form of the object and the user. The relations to evaluate are taken from the model, or
+// checked against it, on execution.
+func (r *ListRelationsRequest) Validate() error {
+	if err := validateStoreID(r.StoreID); err != nil {
+		return err
+	}
+	if !tuple.IsValidObject(r.Object) {
+		return errors.New("object must be of the form 'type:id'")
+	}
+	if !tuple.IsValidUser(r.User) {
+		return errors.New("user must be of the form 'type:id', 'type:*' or 'type:id#relation'")
+	}
+	return nil
+}
+
+// ListRelationsResponse lists the relations the user has with the object, in the order they were requested.
+type ListRelationsResponse struct {
+	Relations          []string                        `json:"relations"`
+	ResolutionMetadata ListRelationsResolutionMetadata `json:"-"`
+}
+
+type ListRelationsResolutionMetadata struct {
+	DatastoreQueryCount uint32
+	DispatchCount       uint32
+	WasThrottled        bool
+	// SkippedRelationCount is the number of relations that the type of the user cannot have with the
+	// object, according to the model, and that were not evaluated.
+	SkippedRelationCount int
+}
+
+// ListRelationsQuery checks the relations of a user with an object. The checks of the relations read the
+// tuples through the same request storage, so that the iterators read for one relation are reused by the
+// others, and relations that the model does not connect to the type of the user are not checked.
+type ListRelationsQuery struct {
+	logger                     logger.Logger
+	checkResolver              graph.CheckResolver
+	typesys                    *typesystem.TypeSystem
+	datastore                  storage.RelationshipTupleReader
+	sharedCheckResources       *shared.SharedDatastoreResources
+	cacheSettings              config.CacheSettings
+	maxConcurrentReads         uint32
+	maxConcurrentChecks        uint32
+	datastoreThrottleThreshold int
+	datastoreThrottleDuration  time.Duration
+	adaptiveLimiter            *storagewrappers.AdaptiveLimiter
+}
+
+type ListRelationsQueryOption func(*ListRelationsQuery)
+
+func WithListRelationsLogger(l logger.Logger) ListRelationsQueryOption {
+	return func(q *ListRelationsQuery) {
+		q.logger = l
+	}
+}
+
+func WithListRelationsCache(sharedCheckResources *shared.SharedDatastoreResources, cacheSettings config.CacheSettings) ListRelationsQueryOption {
+	return func(q *ListRelationsQuery) {
+		q.sharedCheckResources = sharedCheckResources
+		q.cacheSettings = cacheSettings
+	}
+}
+
+func WithListRelationsMaxConcurrentReads(m uint32) ListRelationsQueryOption {
+	return func(q *ListRelationsQuery) {
+		q.maxConcurrentReads = m
+	}
+}
+
+// WithListRelationsMaxConcurrentChecks sets the number of relations that are checked concurrently.
+func WithListRelationsMaxConcurrentChecks(m uint32) ListRelationsQueryOption {
+	return func(q *ListRelationsQuery) {
+		q.maxConcurrentChecks = m
+	}
+}
+
+func WithListRelationsDatastoreThrottler(threshold int, duration time.Duration) ListRelationsQueryOption {
+	return func(q *ListRelationsQuery) {
+		q.datastoreThrottleThreshold = threshold
+		q.datastoreThrottleDuration = duration
+	}
+}
+
+// WithListRelationsAdaptiveLimiter bounds the datastore reads of the query by the adaptive limiter shared by the server.
+func WithListRelationsAdaptiveLimiter(limiter *storagewrappers.AdaptiveLimiter) ListRelationsQueryOption {
+	return func(q *ListRelationsQuery) {
+		q.adaptiveLimiter = limiter
+	}
+}
+
+func NewListRelationsQuery(datastore storage.RelationshipTupleReader, checkResolver graph.CheckResolver, typesys *typesystem.TypeSystem, opts ...ListRelationsQueryOption) *ListRelationsQuery {
+	q := &ListRelationsQuery{
+		logger:              logger.NewNoopLogger(),
+		datastore:           datastore,
+		checkResolver:       checkResolver,
+		typesys:             typesys,
+		maxConcurrentReads:  defaultMaxConcurrentReadsForCheck,
+		maxConcurrentChecks: config.DefaultMaxConcurrentChecksPerBatchCheck,
+		cacheSettings:       config.NewDefaultCacheSettings(),
+		sharedCheckResources: &shared.SharedDatastoreResources{
+			CacheController: cachecontroller.NewNoopCacheController(),
+		},
+	}
+
+	for _, opt := range opts {
+		opt(q)
+	}
+	return q
+}
+
+func (q *ListRelationsQuery) Execute(ctx context.Context, req *ListRelationsRequest) (*ListRelationsResponse, error) {
+	relations, err := q.candidateRelations(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var checkedRelations []string
+	for _, relation := range relations {
+		if q.isReachable(req, relation) {
+			checkedRelations = append(checkedRelations, relation)
+		}
+	}
+
+	cacheInvalidationTime := time.Time{}
+	if req.Consistency != openfgav1.ConsistencyPreference_HIGHER_CONSISTENCY {
+		cacheInvalidationTime = q.sharedCheckResources.CacheController.DetermineInvalidationTime(ctx, req.StoreID)
+	}
+
+	datastoreWithTupleCache := storagewrappers.NewRequestStorageWrapperWithCache(
+		q.datastore,
+		req.ContextualTuples.GetTupleKeys(),
+		&storagewrappers.Operation{
+			Method:            apimethod.Check,
+			Concurrency:       q.maxConcurrentReads,
+			ThrottleThreshold: q.datastoreThrottleThreshold,
+			ThrottleDuration:  q.datastoreThrottleDuration,
+			AdaptiveLimiter:   q.adaptiveLimiter,
+		},
+		storagewrappers.DataResourceConfiguration{
+			Resources:              q.sharedCheckResources,
+			CacheSettings:          q.cacheSettings,
+			RequestSharedIterators: sharediterator.NewSharedIteratorDatastoreStorage(),
+		},
+	)
+
+	ctx = typesystem.ContextWithTypesystem(ctx, q.typesys)
+	ctx = storage.ContextWithRelationshipTupleReader(ctx, datastoreWithTupleCache)
+
+	allowed := make([]bool, len(checkedRelations))
+	var dispatchCount atomic.Uint32
+	var wasThrottled atomic.Bool
+
+	pool := concurrency.NewPool(ctx, int(q.maxConcurrentChecks))
+	for i, relation := range checkedRelations {
+		pool.Go(func(ctx context.Context) error {
+			resolveCheckRequest, err := graph.NewResolveCheckRequest(graph.ResolveCheckRequestParams{
+				StoreID:                   req.StoreID,
+				TupleKey:                  tuple.NewTupleKey(req.Object, relation, req.User),
+				Context:                   req.Context,
+				ContextualTuples:          req.ContextualTuples,
+				Consistency:               req.Consistency,
+				LastCacheInvalidationTime: cacheInvalidationTime,
+				AuthorizationModelID:      q.typesys.GetAuthorizationModelID(),
+			})
+			if err != nil {
+				return err
+			}
+
+			resp, err := q.checkResolver.ResolveCheck(ctx, resolveCheckRequest)
+			dispatchCount.Add(resolveCheckRequest.GetRequestMetadata().DispatchCounter.Load())
+			wasThrottled.CompareAndSwap(false, resolveCheckRequest.GetRequestMetadata().WasThrottled.Load())
+			if err != nil {
+				return err
+			}
+			allowed[i] = resp.GetAllowed()
+			return nil
+		})
+	}
+	err = pool.Wait()
+
+	dsMeta := datastoreWithTupleCache.GetMetadata()
+	wasThrottled.CompareAndSwap(false, dsMeta.WasThrottled)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) && wasThrottled.Load() {
+			return nil, &ThrottledError{Cause: err}
+		}
+		return nil, err
+	}
+
+	res := &ListRelationsResponse{
+		Relations: make([]string, 0, len(checkedRelations)),
+		ResolutionMetadata: ListRelationsResolutionMetadata{
+			DatastoreQueryCount:  dsMeta.DatastoreQueryCount,
+			DispatchCount:        dispatchCount.Load(),
+			WasThrottled:         wasThrottled.Load(),
+			SkippedRelationCount: len(relations) - len(checkedRelations),
+		},
+	}
+	for i, relation := range checkedRelations {
+		if allowed[i] {
+			res.Relations = append(res.Relations, relation)
+		}
+	}
+	return res, nil
+}
+
+// candidateRelations returns the relations of req, deduplicated, after validating them against the model. If
+// req has no relations, every relation of the type of the object is returned, sorted by name.
+func (q *ListRelationsQuery) candidateRelations(req *ListRelationsRequest) ([]string, error) {
+	relations := req.Relations
+	if len(relations) == 0 {
+		typeRelations, err := q.typesys.GetRelations(tuple.GetType(req.Object))
+		if err != nil {
+			return nil, &InvalidRelationError{Cause: err}
+		}
+		for relation := range typeRelations {
+			relations = append(relations, relation)
+		}
+		slices.Sort(relations)
+	}
+
+	candidates := make([]string, 0, len(relations))
+	for _, relation := range relations {
+		if slices.Contains(candidates, relation) {
+			continue
+		}
+		err := validateCheckRequest(q.typesys, tuple.NewCheckRequestTupleKey(req.Object, relation, req.User), req.ContextualTuples)
+		if err != nil {
+			return nil, err
+		}
+		candidates = append(candidates, relation)
+	}
+	return candidates, nil
+}
+
+// isReachable returns false if, according to the model, the user of req cannot have relation with the object.
+func (q *ListRelationsQuery) isReachable(req *ListRelationsRequest, relation string) bool {
+	if req.User == tuple.ToObjectRelationString(req.Object, relation) {
+		return true
+	}
+	exists, err := q.typesys.PathExists(req.User, relation, tuple.GetType(req.Object))
+	if err != nil {
+		// Let Check decide when the graph cannot be queried.
+		return true
+	}
+	return exists
+}
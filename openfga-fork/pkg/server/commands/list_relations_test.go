@@ -0,0 +1,161 @@
+package commands
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+
+	"github.com/openfga/openfga/internal/graph"
+	"github.com/openfga/openfga/pkg/storage"
+	"github.com/openfga/openfga/pkg/storage/memory"
+	storagetest "github.com/openfga/openfga/pkg/storage/test"
+	"github.com/openfga/openfga/pkg/typesystem"
+)
+
+// readCounter counts the reads of tuples by object and relation.
+type readCounter struct {
+	storage.RelationshipTupleReader
+	reads atomic.Int32
+}
+
+func (r *readCounter) Read(ctx context.Context, store string, tupleKey *openfgav1.TupleKey, options storage.ReadOptions) (storage.TupleIterator, error) {
+	r.reads.Add(1)
+	return r.RelationshipTupleReader.Read(ctx, store, tupleKey, options)
+}
+
+func TestListRelationsQuery(t *testing.T) {
+	t.Cleanup(func() {
+		goleak.VerifyNone(t)
+	})
+
+	ds := memory.New()
+	t.Cleanup(ds.Close)
+
+	storeID, model := storagetest.BootstrapFGAStore(t, ds, `
+		model
+			schema 1.1
+		type user
+		type group
+			relations
+				define member: [user]
+		type folder
+			relations
+				define owner: [user]
+				define viewer: [user]
+		type document
+			relations
+				define parent: [folder]
+				define owner: [user] or owner from parent
+				define editor: [user] or owner
+				define viewer: [user] or editor or viewer from parent
+				define reviewer: [group#member]`,
+		[]string{
+			"document:1#parent@folder:x",
+			"folder:x#owner@user:anne",
+			"folder:x#viewer@user:bob",
+			"document:1#editor@user:charlie",
+		})
+	typesys, err := typesystem.NewAndValidate(context.Background(), model)
+	require.NoError(t, err)
+
+	checker, checkResolverCloser, err := graph.NewOrderedCheckResolvers().Build()
+	require.NoError(t, err)
+	t.Cleanup(checkResolverCloser)
+
+	tests := []struct {
+		name            string
+		user            string
+		relations       []string
+		expected        []string
+		expectedSkipped int
+	}{
+		{
+			name:            "all_relations",
+			user:            "user:anne",
+			expected:        []string{"editor", "owner", "viewer"},
+			expectedSkipped: 1, // parent cannot be related to users
+		},
+		{
+			name:            "requested_relations_in_order",
+			user:            "user:anne",
+			relations:       []string{"viewer", "owner", "viewer"},
+			expected:        []string{"viewer", "owner"},
+			expectedSkipped: 0,
+		},
+		{
+			name:            "through_parent",
+			user:            "user:bob",
+			expected:        []string{"viewer"},
+			expectedSkipped: 1,
+		},
+		{
+			name:            "direct_and_computed",
+			user:            "user:charlie",
+			relations:       []string{"owner", "editor", "viewer", "reviewer"},
+			expected:        []string{"editor", "viewer"},
+			expectedSkipped: 0,
+		},
+		{
+			name:            "userset",
+			user:            "group:eng#member",
+			expected:        []string{},
+			expectedSkipped: 4,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			q := NewListRelationsQuery(ds, checker, typesys)
+			res, err := q.Execute(context.Background(), &ListRelationsRequest{
+				StoreID:   storeID,
+				Object:    "document:1",
+				User:      test.user,
+				Relations: test.relations,
+			})
+			require.NoError(t, err)
+			require.Equal(t, test.expected, res.Relations)
+			require.Equal(t, test.expectedSkipped, res.ResolutionMetadata.SkippedRelationCount)
+		})
+	}
+
+	t.Run("reads_shared_across_relations", func(t *testing.T) {
+		counter := &readCounter{RelationshipTupleReader: ds}
+		q := NewListRelationsQuery(counter, checker, typesys, WithListRelationsMaxConcurrentChecks(1))
+		res, err := q.Execute(context.Background(), &ListRelationsRequest{
+			StoreID:   storeID,
+			Object:    "document:1",
+			User:      "user:dave",
+			Relations: []string{"owner", "viewer"},
+		})
+		require.NoError(t, err)
+		require.Empty(t, res.Relations)
+		// Both relations read the parents of the document.
+		require.Equal(t, int32(1), counter.reads.Load())
+	})
+
+	t.Run("undefined_relation", func(t *testing.T) {
+		q := NewListRelationsQuery(ds, checker, typesys)
+		_, err := q.Execute(context.Background(), &ListRelationsRequest{
+			StoreID:   storeID,
+			Object:    "document:1",
+			User:      "user:anne",
+			Relations: []string{"viewer", "unknown"},
+		})
+		var invalidRelationError *InvalidRelationError
+		require.ErrorAs(t, err, &invalidRelationError)
+	})
+}
+
+func TestListRelationsRequestValidate(t *testing.T) {
+	req := &ListRelationsRequest{StoreID: "01JBDC5AQCZC4J8AC3TNC4PH5B", Object: "document:1", User: "user:anne"}
+	require.NoError(t, req.Validate())
+
+	require.ErrorContains(t, (&ListRelationsRequest{StoreID: req.StoreID, Object: "document", User: "user:anne"}).Validate(), "object must be")
+	require.ErrorContains(t, (&ListRelationsRequest{StoreID: req.StoreID, Object: "document:1", User: ""}).Validate(), "user must be")
+	require.Error(t, (&ListRelationsRequest{Object: "document:1", User: "user:anne"}).Validate())
+}
@@ -110,6 +110,24 @@ type listUsersResponseMetadata struct {
 
 	// WasThrottled indicates whether the request was throttled
 	WasThrottled *atomic.Bool
+
+	// DeadlineExceeded indicates that the deadline was exceeded before all the users were found
+	DeadlineExceeded bool
+
+	// MaxResultsReached indicates that the maximum number of results was found, so that some users may be missing
+	MaxResultsReached bool
+}
+
+// StreamedListUsersMetadata is sent after the last user of a StreamedListUsers response.
+type StreamedListUsersMetadata struct {
+	// UserCount is the number of users sent.
+	UserCount uint32 `json:"user_count"`
+	// DeadlineExceeded is set when the stream ended at the ListUsers deadline, before all the users were found.
+	DeadlineExceeded bool `json:"deadline_exceeded"`
+	// WasThrottled is set when dispatches or datastore reads were throttled.
+	WasThrottled        bool   `json:"throttled"`
+	DispatchCount       uint32 `json:"dispatch_count"`
+	DatastoreQueryCount uint32 `json:"datastore_query_count"`
 }
 
 func (r *listUsersResponse) GetUsers() []*openfgav1.User {
@@ -126,6 +144,13 @@ func (r *listUsersResponse) GetMetadata() listUsersResponseMetadata {
 	return r.Metadata
 }
 
+// Truncated reports whether the users may not all have been found, because the deadline was exceeded or the
+// maximum number of results was reached.
+func (r *listUsersResponse) Truncated() bool {
+	metadata := r.GetMetadata()
+	return metadata.DeadlineExceeded || metadata.MaxResultsReached
+}
+
 func fromListUsersRequest(o listUsersRequest, dispatchCount *atomic.Uint32) *internalListUsersRequest {
 	if dispatchCount == nil {
 		dispatchCount = new(atomic.Uint32)
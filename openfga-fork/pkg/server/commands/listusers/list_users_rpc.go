@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -51,6 +52,7 @@ type listUsersQuery struct {
 	expandDirectDispatch       expandDirectDispatchHandler
 	datastoreThrottleThreshold int
 	datastoreThrottleDuration  time.Duration
+	adaptiveLimiter            *storagewrappers.AdaptiveLimiter
 }
 
 type expandResponse struct {
@@ -130,6 +132,7 @@ func WithListUsersMaxConcurrentReads(limit uint32) ListUsersQueryOption {
 	}
 }
 
+// WithListUsersDatastoreThrottler throttles the datastore reads of the query once it has made threshold reads.
 func WithListUsersDatastoreThrottler(threshold int, duration time.Duration) ListUsersQueryOption {
 	return func(d *listUsersQuery) {
 		d.datastoreThrottleThreshold = threshold
@@ -137,6 +140,13 @@ func WithListUsersDatastoreThrottler(threshold int, duration time.Duration) List
 	}
 }
 
+// WithListUsersAdaptiveLimiter bounds the datastore reads of the query by the adaptive limiter shared by the server.
+func WithListUsersAdaptiveLimiter(limiter *storagewrappers.AdaptiveLimiter) ListUsersQueryOption {
+	return func(d *listUsersQuery) {
+		d.adaptiveLimiter = limiter
+	}
+}
+
 func (l *listUsersQuery) throttle(ctx context.Context, currentNumDispatch uint32) {
 	span := trace.SpanFromContext(ctx)
 
@@ -181,8 +191,11 @@ func NewListUsersQuery(ds storage.RelationshipTupleReader, contextualTuples []*o
 	}
 
 	l.datastore = storagewrappers.NewRequestStorageWrapper(ds, contextualTuples, &storagewrappers.Operation{
-		Method:      apimethod.ListUsers,
-		Concurrency: l.maxConcurrentReads,
+		Method:            apimethod.ListUsers,
+		Concurrency:       l.maxConcurrentReads,
+		ThrottleThreshold: l.datastoreThrottleThreshold,
+		ThrottleDuration:  l.datastoreThrottleDuration,
+		AdaptiveLimiter:   l.adaptiveLimiter,
 	})
 
 	return l
@@ -198,6 +211,102 @@ func (l *listUsersQuery) ListUsers(
 	))
 	defer span.End()
 
+	foundUsersUnique := make(map[tuple.UserString]foundUser, 1000)
+	maxResultsReached := false
+	metadata, err := l.expandUsers(ctx, req, func(foundUser foundUser) bool {
+		foundUsersUnique[tuple.UserProtoToString(foundUser.user)] = foundUser
+
+		if l.maxResults > 0 {
+			if uint32(len(foundUsersUnique)) >= l.maxResults {
+				span.SetAttributes(attribute.Bool("max_results_found", true))
+				maxResultsReached = true
+				return false
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	metadata.MaxResultsReached = maxResultsReached
+
+	foundUsers := make([]*openfgav1.User, 0, len(foundUsersUnique))
+	for foundUserKey, foundUser := range foundUsersUnique {
+		if foundUser.relationshipStatus == NoRelationship {
+			continue
+		}
+
+		foundUsers = append(foundUsers, tuple.StringToUserProto(foundUserKey))
+	}
+
+	span.SetAttributes(attribute.Int("result_count", len(foundUsers)))
+
+	return &listUsersResponse{
+		Users:    foundUsers,
+		Metadata: metadata,
+	}, nil
+}
+
+// StreamedListUsers sends the users of req to emit as they are found, each user once. Unlike ListUsers, it is
+// not limited by the maximum number of results, and it stops when all the users have been found, when the
+// deadline is exceeded or when emit fails. It assumes that the typesystem is in the context and that the
+// request is valid.
+func (l *listUsersQuery) StreamedListUsers(
+	ctx context.Context,
+	req *openfgav1.ListUsersRequest,
+	emit func(*openfgav1.User) error,
+) (*StreamedListUsersMetadata, error) {
+	ctx, span := tracer.Start(ctx, "StreamedListUsers", trace.WithAttributes(
+		attribute.String("store_id", req.GetStoreId()),
+	))
+	defer span.End()
+
+	sentUsers := make(map[tuple.UserString]struct{}, 1000)
+	var emitErr error
+	metadata, err := l.expandUsers(ctx, req, func(foundUser foundUser) bool {
+		if foundUser.relationshipStatus == NoRelationship {
+			return true
+		}
+
+		key := tuple.UserProtoToString(foundUser.user)
+		if _, ok := sentUsers[key]; ok {
+			return true
+		}
+		sentUsers[key] = struct{}{}
+
+		if emitErr = emit(foundUser.user); emitErr != nil {
+			return false
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	if emitErr != nil {
+		telemetry.TraceError(span, emitErr)
+		return nil, emitErr
+	}
+
+	span.SetAttributes(attribute.Int("result_count", len(sentUsers)))
+
+	return &StreamedListUsersMetadata{
+		UserCount:           uint32(len(sentUsers)),
+		DeadlineExceeded:    metadata.DeadlineExceeded,
+		WasThrottled:        metadata.WasThrottled.Load(),
+		DispatchCount:       metadata.DispatchCounter.Load(),
+		DatastoreQueryCount: metadata.DatastoreQueryCount,
+	}, nil
+}
+
+// expandUsers expands req and calls handle, from a single goroutine, with every user found until all the
+// users have been found, handle returns false or the deadline is exceeded.
+func (l *listUsersQuery) expandUsers(
+	ctx context.Context,
+	req *openfgav1.ListUsersRequest,
+	handle func(foundUser) bool,
+) (listUsersResponseMetadata, error) {
+	span := trace.SpanFromContext(ctx)
+
 	cancellableCtx, cancelCtx := context.WithCancel(ctx)
 	if l.deadline != 0 {
 		cancellableCtx, cancelCtx = context.WithTimeout(cancellableCtx, l.deadline)
@@ -207,25 +316,24 @@ func (l *listUsersQuery) ListUsers(
 
 	typesys, ok := typesystem.TypesystemFromContext(cancellableCtx)
 	if !ok {
-		return nil, fmt.Errorf("%w: typesystem missing in context", openfgaErrors.ErrUnknown)
+		return listUsersResponseMetadata{}, fmt.Errorf("%w: typesystem missing in context", openfgaErrors.ErrUnknown)
 	}
 
-	userFilter := req.GetUserFilters()[0]
 	userset := tuple.ToObjectRelationString(tuple.ObjectKey(req.GetObject()), req.GetRelation())
 
-	if !tuple.UsersetMatchTypeAndRelation(userset, userFilter.GetRelation(), userFilter.GetType()) {
+	matchesUserset := slices.ContainsFunc(req.GetUserFilters(), func(userFilter *openfgav1.UserTypeFilter) bool {
+		return tuple.UsersetMatchTypeAndRelation(userset, userFilter.GetRelation(), userFilter.GetType())
+	})
+	if !matchesUserset {
 		hasPossibleEdges, err := doesHavePossibleEdges(typesys, req)
 		if err != nil {
-			return nil, err
+			return listUsersResponseMetadata{}, err
 		}
 		if !hasPossibleEdges {
 			span.SetAttributes(attribute.Bool("no_possible_edges", true))
-			return &listUsersResponse{
-				Users: []*openfgav1.User{},
-				Metadata: listUsersResponseMetadata{
-					DispatchCounter: new(atomic.Uint32),
-					WasThrottled:    new(atomic.Bool),
-				},
+			return listUsersResponseMetadata{
+				DispatchCounter: new(atomic.Uint32),
+				WasThrottled:    new(atomic.Bool),
 			}, nil
 		}
 	}
@@ -235,18 +343,11 @@ func (l *listUsersQuery) ListUsers(
 	foundUsersCh := l.buildResultsChannel()
 	expandErrCh := make(chan error, 1)
 
-	foundUsersUnique := make(map[tuple.UserString]foundUser, 1000)
-
 	doneWithFoundUsersCh := make(chan struct{}, 1)
 	go func() {
 		for foundUser := range foundUsersCh {
-			foundUsersUnique[tuple.UserProtoToString(foundUser.user)] = foundUser
-
-			if l.maxResults > 0 {
-				if uint32(len(foundUsersUnique)) >= l.maxResults {
-					span.SetAttributes(attribute.Bool("max_results_found", true))
-					break
-				}
+			if !handle(foundUser) {
+				break
 			}
 		}
 
@@ -269,7 +370,7 @@ func (l *listUsersQuery) ListUsers(
 		break
 	case <-cancellableCtx.Done():
 		deadlineExceeded = true
-		// to avoid a race on the 'foundUsersUnique' map below, wait for the range over the channel to close
+		// to avoid a race on the state of handle, wait for the range over the channel to close
 		<-doneWithFoundUsersCh
 		break
 	}
@@ -278,53 +379,55 @@ func (l *listUsersQuery) ListUsers(
 	case err := <-expandErrCh:
 		if deadlineExceeded || errors.Is(err, context.DeadlineExceeded) {
 			// We skip the error because we want to send at least partial results to the user (but we should probably set response headers)
+			deadlineExceeded = true
 			break
 		}
 		telemetry.TraceError(span, err)
-		return nil, err
+		return listUsersResponseMetadata{}, err
 	default:
 		break
 	}
 
 	cancelCtx()
 
-	foundUsers := make([]*openfgav1.User, 0, len(foundUsersUnique))
-	for foundUserKey, foundUser := range foundUsersUnique {
-		if foundUser.relationshipStatus == NoRelationship {
-			continue
-		}
-
-		foundUsers = append(foundUsers, tuple.StringToUserProto(foundUserKey))
-	}
-
-	span.SetAttributes(attribute.Int("result_count", len(foundUsers)))
-
 	dsMeta := l.datastore.GetMetadata()
 	l.wasThrottled.CompareAndSwap(false, dsMeta.WasThrottled)
-	return &listUsersResponse{
-		Users: foundUsers,
-		Metadata: listUsersResponseMetadata{
-			DatastoreQueryCount: dsMeta.DatastoreQueryCount,
-			DispatchCounter:     &dispatchCount,
-			WasThrottled:        l.wasThrottled,
-		},
+	return listUsersResponseMetadata{
+		DatastoreQueryCount: dsMeta.DatastoreQueryCount,
+		DispatchCounter:     &dispatchCount,
+		WasThrottled:        l.wasThrottled,
+		DeadlineExceeded:    deadlineExceeded,
 	}, nil
 }
 
 func doesHavePossibleEdges(typesys *typesystem.TypeSystem, req *openfgav1.ListUsersRequest) (bool, error) {
 	g := graph.New(typesys)
 
-	userFilters := req.GetUserFilters()
-
-	source := typesystem.DirectRelationReference(userFilters[0].GetType(), userFilters[0].GetRelation())
 	target := typesystem.DirectRelationReference(req.GetObject().GetType(), req.GetRelation())
 
-	edges, err := g.GetPrunedRelationshipEdges(target, source)
-	if err != nil {
-		return false, err
+	for _, userFilter := range req.GetUserFilters() {
+		source := typesystem.DirectRelationReference(userFilter.GetType(), userFilter.GetRelation())
+
+		edges, err := g.GetPrunedRelationshipEdges(target, source)
+		if err != nil {
+			return false, err
+		}
+		if len(edges) > 0 {
+			return true, nil
+		}
 	}
 
-	return len(edges) > 0, err
+	return false, nil
+}
+
+// userWildcardKey returns the typed wildcard that includes the user, or "" if the user is a userset, which
+// no wildcard includes.
+func userWildcardKey(user string) string {
+	object, relation := tuple.SplitObjectRelation(user)
+	if relation != "" {
+		return ""
+	}
+	return tuple.TypedPublicWildcard(tuple.GetType(object))
 }
 
 func (l *listUsersQuery) dispatch(
@@ -587,8 +690,8 @@ func (l *listUsersQuery) expandIntersection(
 	var wg sync.WaitGroup
 	wg.Add(len(childOperands))
 
-	wildcardCount := atomic.Uint32{}
-	wildcardKey := tuple.TypedPublicWildcard(req.GetUserFilters()[0].GetType())
+	// The number of operands that found the wildcard, per wildcard.
+	wildcardCountMap := make(map[string]uint32, 0)
 	foundUsersCountMap := make(map[string]uint32, 0)
 	excludedUsersMap := make(map[string]struct{}, 0)
 	for _, foundUsersChan := range intersectionFoundUsersChans {
@@ -609,12 +712,12 @@ func (l *listUsersQuery) expandIntersection(
 				foundUsersMap[key]++
 			}
 
-			_, wildcardExists := foundUsersMap[wildcardKey]
-			if wildcardExists {
-				wildcardCount.Add(1)
-			}
 			for userKey := range foundUsersMap {
 				mu.Lock()
+				_, wildcardExists := foundUsersMap[userWildcardKey(userKey)]
+				if tuple.IsTypedWildcard(userKey) {
+					wildcardCountMap[userKey]++
+				}
 				// Increment the count for a user but decrement if a wildcard
 				// also exists to prevent double counting. This ensures accurate
 				// tracking for intersection criteria, avoiding inflated counts
@@ -639,7 +742,7 @@ func (l *listUsersQuery) expandIntersection(
 		// all intersection operands plus the number of wildcards.
 		// If this summed value equals the number of operands, the user satisfies
 		// the intersection expression and can be sent on `foundUsersChan`
-		if (count + wildcardCount.Load()) == uint32(len(childOperands)) {
+		if (count + wildcardCountMap[userWildcardKey(key)]) == uint32(len(childOperands)) {
 			fu := foundUser{
 				user:          tuple.StringToUserProto(key),
 				excludedUsers: excludedUsers,
@@ -785,24 +888,26 @@ func (l *listUsersQuery) expandExclusion(
 		}
 	}
 
-	wildcardKey := tuple.TypedPublicWildcard(req.GetUserFilters()[0].GetType())
-
-	_, baseWildcardExists := baseFoundUsersMap[wildcardKey]
-	_, subtractWildcardExists := subtractFoundUsersMap[wildcardKey]
-
 	for userKey, fu := range baseFoundUsersMap {
+		// Wildcards only include the users of their type.
+		wildcardKey := userWildcardKey(userKey)
+		_, baseWildcardExists := baseFoundUsersMap[wildcardKey]
+		_, subtractWildcardExists := subtractFoundUsersMap[wildcardKey]
+
 		subtractedUser, userIsSubtracted := subtractFoundUsersMap[userKey]
-		_, wildcardSubtracted := subtractFoundUsersMap[wildcardKey]
 
 		switch {
 		case baseWildcardExists:
-			if !userIsSubtracted && !wildcardSubtracted {
+			if !userIsSubtracted && !subtractWildcardExists {
 				concurrency.TrySendThroughChannel(ctx, foundUser{
 					user: tuple.StringToUserProto(userKey),
 				}, foundUsersChan)
 			}
 
 			for subtractedUserKey, subtractedFu := range subtractFoundUsersMap {
+				if userWildcardKey(subtractedUserKey) != wildcardKey {
+					continue
+				}
 				if tuple.IsTypedWildcard(subtractedUserKey) {
 					if !userIsSubtracted {
 						concurrency.TrySendThroughChannel(ctx, foundUser{
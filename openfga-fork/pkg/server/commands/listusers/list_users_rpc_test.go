@@ -1973,6 +1973,79 @@ func TestListUsersExclusionWildcards(t *testing.T) {
 	tests.runListUsersTestCases(t)
 }
 
+func TestListUsersMultipleUserFilters(t *testing.T) {
+	t.Cleanup(func() {
+		goleak.VerifyNone(t)
+	})
+
+	model := `
+		model
+			schema 1.1
+
+		type user
+		type employee
+		type group
+			relations
+				define member: [user]
+
+		type document
+			relations
+				define blocked: [user, employee]
+				define allowed: [user:*, employee:*, user, employee]
+				define editor: [user:*, employee:*, user, employee, group#member]
+				define viewer: editor but not blocked
+				define approver: editor and allowed`
+
+	userFilters := []*openfgav1.UserTypeFilter{{Type: "user"}, {Type: "employee"}, {Type: "group", Relation: "member"}}
+	tuples := []*openfgav1.TupleKey{
+		tuple.NewTupleKey("document:1", "editor", "user:*"),
+		tuple.NewTupleKey("document:1", "editor", "employee:anne"),
+		tuple.NewTupleKey("document:1", "editor", "employee:bob"),
+		tuple.NewTupleKey("document:1", "editor", "group:eng#member"),
+		tuple.NewTupleKey("document:1", "blocked", "user:carl"),
+		tuple.NewTupleKey("document:1", "blocked", "employee:bob"),
+		tuple.NewTupleKey("document:1", "allowed", "employee:*"),
+		tuple.NewTupleKey("document:1", "allowed", "user:dave"),
+	}
+
+	tests := ListUsersTests{
+		{
+			name: "users_of_every_filter",
+			req: &openfgav1.ListUsersRequest{
+				Object:      &openfgav1.Object{Type: "document", Id: "1"},
+				Relation:    "editor",
+				UserFilters: userFilters,
+			},
+			model:         model,
+			tuples:        tuples,
+			expectedUsers: []string{"user:*", "employee:anne", "employee:bob", "group:eng#member"},
+		},
+		{
+			name: "wildcards_only_exclude_users_of_their_type",
+			req: &openfgav1.ListUsersRequest{
+				Object:      &openfgav1.Object{Type: "document", Id: "1"},
+				Relation:    "viewer",
+				UserFilters: userFilters,
+			},
+			model:         model,
+			tuples:        tuples,
+			expectedUsers: []string{"user:*", "employee:anne", "group:eng#member"},
+		},
+		{
+			name: "wildcards_only_intersect_users_of_their_type",
+			req: &openfgav1.ListUsersRequest{
+				Object:      &openfgav1.Object{Type: "document", Id: "1"},
+				Relation:    "approver",
+				UserFilters: userFilters,
+			},
+			model:         model,
+			tuples:        tuples,
+			expectedUsers: []string{"user:dave", "employee:anne", "employee:bob"},
+		},
+	}
+	tests.runListUsersTestCases(t)
+}
+
 func TestListUsersWildcards(t *testing.T) {
 	t.Cleanup(func() {
 		goleak.VerifyNone(t)
@@ -4131,3 +4204,73 @@ func NewListUsersQueryPanicExpandDirect(ds storage.RelationshipTupleReader, cont
 
 	return l
 }
+
+func TestStreamedListUsers(t *testing.T) {
+	t.Cleanup(func() {
+		goleak.VerifyNone(t)
+	})
+
+	ds := memory.New()
+	t.Cleanup(ds.Close)
+
+	storeID, model := storagetest.BootstrapFGAStore(t, ds, `
+		model
+			schema 1.1
+		type user
+		type document
+			relations
+				define owner: [user]
+				define editor: [user]
+				define viewer: owner or editor`,
+		[]string{
+			"document:1#owner@user:anne",
+			"document:1#editor@user:anne",
+			"document:1#editor@user:bob",
+			"document:1#owner@user:charlie",
+		})
+	typesys, err := typesystem.NewAndValidate(context.Background(), model)
+	require.NoError(t, err)
+	ctx := typesystem.ContextWithTypesystem(context.Background(), typesys)
+
+	req := &openfgav1.ListUsersRequest{
+		StoreId:     storeID,
+		Object:      &openfgav1.Object{Type: "document", Id: "1"},
+		Relation:    "viewer",
+		UserFilters: []*openfgav1.UserTypeFilter{{Type: "user"}},
+	}
+
+	t.Run("each_user_sent_once_regardless_of_max_results", func(t *testing.T) {
+		var users []string
+		metadata, err := NewListUsersQuery(ds, emptyContextualTuples, WithListUsersMaxResults(1)).
+			StreamedListUsers(ctx, req, func(user *openfgav1.User) error {
+				users = append(users, tuple.UserProtoToString(user))
+				return nil
+			})
+		require.NoError(t, err)
+		require.ElementsMatch(t, []string{"user:anne", "user:bob", "user:charlie"}, users)
+		require.Equal(t, uint32(3), metadata.UserCount)
+		require.False(t, metadata.DeadlineExceeded)
+	})
+
+	t.Run("stops_when_emit_fails", func(t *testing.T) {
+		emitErr := fmt.Errorf("client gone")
+		sent := 0
+		_, err := NewListUsersQuery(ds, emptyContextualTuples).
+			StreamedListUsers(ctx, req, func(*openfgav1.User) error {
+				sent++
+				return emitErr
+			})
+		require.ErrorIs(t, err, emitErr)
+		require.Equal(t, 1, sent)
+	})
+
+	t.Run("deadline_exceeded_reported_in_metadata", func(t *testing.T) {
+		metadata, err := NewListUsersQuery(
+			mocks.NewMockSlowDataStorage(ds, 50*time.Millisecond),
+			emptyContextualTuples,
+			WithListUsersDeadline(1*time.Millisecond),
+		).StreamedListUsers(ctx, req, func(*openfgav1.User) error { return nil })
+		require.NoError(t, err)
+		require.True(t, metadata.DeadlineExceeded)
+	})
+}
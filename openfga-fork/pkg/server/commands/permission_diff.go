@@ -0,0 +1,257 @@
+package commands
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+
+	"github.com/openfga/openfga/pkg/storage"
+	"github.com/openfga/openfga/pkg/tuple"
+	"github.com/openfga/openfga/pkg/typesystem"
+)
+
+// tupleState is the set of tuples of a store at one end of a permission comparison: the tuples
+// returned by reader, plus the contextual tuples.
+type tupleState struct {
+	reader           storage.RelationshipTupleReader
+	contextualTuples []*openfgav1.TupleKey
+}
+
+// permissionDiff compares the effective permissions of a store between two sets of tuples. The users
+// whose permissions may differ are derived from the changed tuples that the compared relations depend
+// on, according to the weighted graph of the model; their objects are listed in both states and every
+// difference is confirmed with Check.
+type permissionDiff struct {
+	resolver AccessReviewResolver
+	typesys  *typesystem.TypeSystem
+	storeID  string
+	from, to tupleState
+	// changed holds the tuples that may differ between from and to.
+	changed []*openfgav1.TupleKey
+	// listObjects, if set, lists the objects of objectType that user has relation with in state, in place of
+	// the ListObjects of the resolver. The candidates may or may not be granted, and are confirmed with Check.
+	listObjects func(ctx context.Context, state tupleState, objectType, relation, user string) (objects, candidates []string, err error)
+	// truncated is set when a ListObjects or ListUsers query stopped at its deadline or result limit, in which
+	// case the rows may be incomplete.
+	truncated bool
+}
+
+// rows returns the permissions on objects of objectType, for the given relations, that differ between
+// from and to, ordered by object, relation and user.
+func (d *permissionDiff) rows(ctx context.Context, objectType string, relations []string) ([]*AccessReviewRow, error) {
+	candidates, err := d.candidateUsers(ctx, objectType, relations)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []*AccessReviewRow
+	for _, user := range sortedKeys(candidates) {
+		for _, relation := range sortedKeys(candidates[user]) {
+			diff, err := d.diff(ctx, objectType, user, relation)
+			if err != nil {
+				return nil, err
+			}
+			rows = append(rows, diff...)
+		}
+	}
+
+	slices.SortFunc(rows, func(a, b *AccessReviewRow) int {
+		return cmpStrings(a.Object, b.Object, a.Relation, b.Relation, a.User, b.User)
+	})
+	return rows, nil
+}
+
+// candidateUsers returns, for every user whose permissions may have changed, the relations that may have changed.
+// A changed tuple affects its user and, when the user is a userset or an object with relations, the users of that
+// userset or object in both states.
+func (d *permissionDiff) candidateUsers(ctx context.Context, objectType string, relations []string) (map[string]map[string]struct{}, error) {
+	candidates := make(map[string]map[string]struct{})
+	add := func(user string, relations []string) {
+		if candidates[user] == nil {
+			candidates[user] = make(map[string]struct{})
+		}
+		for _, relation := range relations {
+			candidates[user][relation] = struct{}{}
+		}
+	}
+
+	for _, tk := range d.changed {
+		affected := dependentRelations(d.typesys, objectType, relations, tuple.GetType(tk.GetObject()), tk.GetRelation())
+		if len(affected) == 0 {
+			continue
+		}
+
+		user := tk.GetUser()
+		userObject, userRelation := tuple.SplitObjectRelation(user)
+		userType, userID := tuple.SplitObject(userObject)
+
+		var expand []string
+		switch {
+		case tuple.IsWildcard(user):
+			add(user, affected)
+			continue
+		case userRelation != "":
+			add(user, affected)
+			expand = []string{userRelation}
+		default:
+			add(user, affected)
+			userRelations, err := d.typesys.GetRelations(userType)
+			if err != nil {
+				continue
+			}
+			for relation := range userRelations {
+				if len(dependentRelations(d.typesys, objectType, affected, userType, relation)) > 0 {
+					expand = append(expand, relation)
+				}
+			}
+		}
+
+		for _, relation := range expand {
+			for _, userFilter := range allUserTypeFilters(d.typesys) {
+				for _, state := range []tupleState{d.from, d.to} {
+					users, truncated, err := d.resolver.ListUsers(ctx, state.reader, &openfgav1.ListUsersRequest{
+						StoreId:              d.storeID,
+						AuthorizationModelId: d.typesys.GetAuthorizationModelID(),
+						Object:               &openfgav1.Object{Type: userType, Id: userID},
+						Relation:             relation,
+						UserFilters:          []*openfgav1.UserTypeFilter{userFilter},
+						ContextualTuples:     state.contextualTuples,
+					})
+					if err != nil {
+						return nil, err
+					}
+					d.truncated = d.truncated || truncated
+					for _, u := range users {
+						add(tuple.UserProtoToString(u), affected)
+					}
+				}
+			}
+		}
+	}
+	return candidates, nil
+}
+
+// dependentRelations returns the relations of objectType that may read the tuples of tupleObjectType#tupleRelation.
+func dependentRelations(typesys *typesystem.TypeSystem, objectType string, relations []string, tupleObjectType, tupleRelation string) []string {
+	var dependent []string
+	for _, relation := range relations {
+		if typesys.RelationDependsOn(objectType, relation, tupleObjectType, tupleRelation) {
+			dependent = append(dependent, relation)
+		}
+	}
+	return dependent
+}
+
+// diff lists the objects user has relation with in both states, and confirms every difference with Check.
+func (d *permissionDiff) diff(ctx context.Context, objectType, user, relation string) ([]*AccessReviewRow, error) {
+	if !isReachableUserType(d.typesys, objectType, relation, user) {
+		return nil, nil
+	}
+
+	listObjects := d.listObjects
+	if listObjects == nil {
+		listObjects = d.resolverListObjects
+	}
+	check := func(state tupleState, object string) (bool, error) {
+		return d.resolver.Check(ctx, state.reader, &openfgav1.CheckRequest{
+			StoreId:              d.storeID,
+			AuthorizationModelId: d.typesys.GetAuthorizationModelID(),
+			TupleKey:             tuple.NewCheckRequestTupleKey(object, relation, user),
+			ContextualTuples:     contextualTupleKeys(state.contextualTuples),
+		})
+	}
+
+	before, candidatesBefore, err := listObjects(ctx, d.from, objectType, relation, user)
+	if err != nil {
+		return nil, err
+	}
+	after, candidatesAfter, err := listObjects(ctx, d.to, objectType, relation, user)
+	if err != nil {
+		return nil, err
+	}
+
+	objects := slices.Concat(before, candidatesBefore, after, candidatesAfter)
+	slices.Sort(objects)
+
+	var rows []*AccessReviewRow
+	for _, object := range slices.Compact(objects) {
+		if slices.Contains(before, object) && slices.Contains(after, object) {
+			continue
+		}
+
+		allowedBefore, err := check(d.from, object)
+		if err != nil {
+			return nil, err
+		}
+		allowedAfter, err := check(d.to, object)
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case allowedAfter && !allowedBefore:
+			rows = append(rows, &AccessReviewRow{Change: AccessGained, User: user, Relation: relation, Object: object})
+		case allowedBefore && !allowedAfter:
+			rows = append(rows, &AccessReviewRow{Change: AccessLost, User: user, Relation: relation, Object: object})
+		}
+	}
+	return rows, nil
+}
+
+// resolverListObjects lists the objects of objectType that user has relation with in state with the ListObjects
+// of the resolver, whose objects are all granted.
+func (d *permissionDiff) resolverListObjects(ctx context.Context, state tupleState, objectType, relation, user string) ([]string, []string, error) {
+	objects, truncated, err := d.resolver.ListObjects(ctx, state.reader, &openfgav1.ListObjectsRequest{
+		StoreId:              d.storeID,
+		AuthorizationModelId: d.typesys.GetAuthorizationModelID(),
+		Type:                 objectType,
+		Relation:             relation,
+		User:                 user,
+		ContextualTuples:     contextualTupleKeys(state.contextualTuples),
+	})
+	d.truncated = d.truncated || truncated
+	return objects, nil, err
+}
+
+func contextualTupleKeys(tuples []*openfgav1.TupleKey) *openfgav1.ContextualTupleKeys {
+	if len(tuples) == 0 {
+		return nil
+	}
+	return &openfgav1.ContextualTupleKeys{TupleKeys: tuples}
+}
+
+// isReachableUserType reports whether objectType#relation can be granted to users of the type of user.
+// The weighted graph only weighs terminal types, so usersets, and every type when the weighted graph
+// could not be built for the model, are considered reachable.
+func isReachableUserType(typesys *typesystem.TypeSystem, objectType, relation, user string) bool {
+	if tuple.IsObjectRelation(user) {
+		return true
+	}
+	node, ok := typesys.GetNode(tuple.ToObjectRelationString(objectType, relation))
+	if !ok {
+		return true
+	}
+	_, ok = node.GetWeight(tuple.GetType(user))
+	return ok
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// cmpStrings compares pairs of strings in order, and returns the result of the first pair that differs.
+func cmpStrings(pairs ...string) int {
+	for i := 0; i+1 < len(pairs); i += 2 {
+		if c := strings.Compare(pairs[i], pairs[i+1]); c != 0 {
+			return c
+		}
+	}
+	return 0
+}
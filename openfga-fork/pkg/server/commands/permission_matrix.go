@@ -0,0 +1,387 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+
+	"github.com/openfga/openfga/pkg/encoder"
+	serverconfig "github.com/openfga/openfga/pkg/server/config"
+	serverErrors "github.com/openfga/openfga/pkg/server/errors"
+	"github.com/openfga/openfga/pkg/storage"
+	"github.com/openfga/openfga/pkg/tuple"
+	"github.com/openfga/openfga/pkg/typesystem"
+)
+
+const (
+	defaultPermissionMatrixPageSize = 50
+	maxPermissionMatrixPageSize     = 1000
+
+	// permissionMatrixTuplePageSize is the number of tuples read at a time to enumerate the objects of a type.
+	permissionMatrixTuplePageSize = 100
+)
+
+// ListUsersFunc returns the users that have a relation with an object, and whether some users may be
+// missing. The server provides an implementation that applies the ListUsers deadline, throttling and
+// result limits, which truncate the users. Unlike the ListUsers API, the request may have several user
+// filters, so that the users of a cell are resolved at once.
+type ListUsersFunc func(ctx context.Context, req *openfgav1.ListUsersRequest) (*openfgav1.ListUsersResponse, bool, error)
+
+// PermissionMatrixRequest selects the cells of a user × object × relation matrix.
+type PermissionMatrixRequest struct {
+	StoreID              string
+	AuthorizationModelID string
+	// ObjectTypes are the types of the objects in the matrix. At least one is required.
+	ObjectTypes []string
+	// Relations are evaluated for every object type. If empty, all relations of each object type are used.
+	Relations []string
+	// UserFilters restricts the users in the matrix. If empty, every type of the model is used.
+	UserFilters []*openfgav1.UserTypeFilter
+	// PageSize is the number of (object, relation) cells evaluated per page.
+	PageSize          int32
+	ContinuationToken string
+	Consistency       openfgav1.ConsistencyPreference
+}
+
+// Validate checks that at least one object type is requested and that the page size is within bounds. Unknown
+// object types are reported on execution.
+func (r *PermissionMatrixRequest) Validate() error {
+	if err := validateStoreID(r.StoreID); err != nil {
+		return err
+	}
+	if len(r.ObjectTypes) == 0 {
+		return errors.New("at least one object type is required")
+	}
+	if r.PageSize < 0 || r.PageSize > maxPermissionMatrixPageSize {
+		return fmt.Errorf("page_size must be between 0 and %d", maxPermissionMatrixPageSize)
+	}
+	return nil
+}
+
+// PermissionMatrixRow is one (user, relation, object) entry of the matrix.
+type PermissionMatrixRow struct {
+	User     string `json:"user"`
+	Relation string `json:"relation"`
+	Object   string `json:"object"`
+}
+
+// PermissionMatrixResponse is returned once all the rows of a page have been emitted.
+type PermissionMatrixResponse struct {
+	// ContinuationToken is empty when the matrix has no more cells.
+	ContinuationToken string `json:"continuation_token"`
+	// Truncated is true when the ListUsers query of some cells of the page was truncated, in which case
+	// users are missing from the rows of these cells.
+	Truncated bool `json:"truncated"`
+}
+
+func (r *PermissionMatrixResponse) GetContinuationToken() string {
+	if r == nil {
+		return ""
+	}
+	return r.ContinuationToken
+}
+
+func (r *PermissionMatrixResponse) GetTruncated() bool {
+	if r == nil {
+		return false
+	}
+	return r.Truncated
+}
+
+// permissionMatrixToken identifies the first cell of the next page.
+type permissionMatrixToken struct {
+	ObjectType string `json:"object_type"`
+	// Tuples is the datastore continuation token of the page of tuples that holds the first tuple of
+	// Object, at index Offset. The page resumes there if Object has no tuples anymore.
+	Tuples   string `json:"tuples"`
+	Offset   int    `json:"offset"`
+	Object   string `json:"object"`
+	Relation string `json:"relation"`
+}
+
+// ParseUserTypeFilters converts filters in the "type" or "type#relation" form.
+func ParseUserTypeFilters(filters []string) []*openfgav1.UserTypeFilter {
+	parsed := make([]*openfgav1.UserTypeFilter, 0, len(filters))
+	for _, f := range filters {
+		userType, relation, _ := strings.Cut(f, "#")
+		parsed = append(parsed, &openfgav1.UserTypeFilter{Type: userType, Relation: relation})
+	}
+	return parsed
+}
+
+// PermissionMatrixQuery builds the effective permission matrix of a store. Objects are
+// enumerated from the tuples of each object type, in the order of their first tuple in the
+// datastore, and the users of every (object, relation) cell are resolved with ListUsers. Rows
+// are emitted in a stable order, so that pages can be resumed with a continuation token. The
+// tuples of the object type are read again from the start on every page, to skip the objects
+// of the previous pages without looking up the first tuple of every object.
+type PermissionMatrixQuery struct {
+	datastore  storage.RelationshipTupleReader
+	listUsers  ListUsersFunc
+	encoder    encoder.Encoder
+	maxObjects uint32
+}
+
+type PermissionMatrixQueryOption func(*PermissionMatrixQuery)
+
+// WithPermissionMatrixMaxObjects see server.WithPermissionMatrixMaxObjects.
+func WithPermissionMatrixMaxObjects(maxObjects uint32) PermissionMatrixQueryOption {
+	return func(q *PermissionMatrixQuery) {
+		q.maxObjects = maxObjects
+	}
+}
+
+func NewPermissionMatrixQuery(datastore storage.RelationshipTupleReader, listUsers ListUsersFunc, opts ...PermissionMatrixQueryOption) *PermissionMatrixQuery {
+	q := &PermissionMatrixQuery{
+		datastore:  datastore,
+		listUsers:  listUsers,
+		encoder:    encoder.NewBase64Encoder(),
+		maxObjects: serverconfig.DefaultPermissionMatrixMaxObjects,
+	}
+
+	for _, opt := range opts {
+		opt(q)
+	}
+	return q
+}
+
+// Execute evaluates one page of cells and calls emit for every row, in order.
+func (q *PermissionMatrixQuery) Execute(
+	ctx context.Context,
+	typesys *typesystem.TypeSystem,
+	req *PermissionMatrixRequest,
+	emit func(*PermissionMatrixRow) error,
+) (*PermissionMatrixResponse, error) {
+	relationsByType, err := q.relationsByType(typesys, req)
+	if err != nil {
+		return nil, err
+	}
+
+	userFilters := req.UserFilters
+	if len(userFilters) == 0 {
+		userFilters = allUserTypeFilters(typesys)
+	}
+
+	from, err := q.decodeToken(req.ContinuationToken)
+	if err != nil {
+		return nil, err
+	}
+
+	pageSize := int(req.PageSize)
+	if pageSize == 0 {
+		pageSize = defaultPermissionMatrixPageSize
+	}
+
+	evaluated := 0
+	truncated := false
+	for _, objectType := range req.ObjectTypes {
+		if from != nil && objectType != from.ObjectType {
+			continue
+		}
+
+		resume := from
+		from = nil
+		var next *permissionMatrixToken
+		err := q.scanObjects(ctx, req, objectType, resume, func(position permissionMatrixToken) (bool, error) {
+			relations := relationsByType[objectType]
+			if resume != nil && position.Object == resume.Object {
+				// The first cell of the page has been reached. If the relation is not in the
+				// matrix anymore, the object is evaluated from its first relation.
+				relations = relations[max(0, slices.Index(relations, resume.Relation)):]
+			}
+			resume = nil
+
+			for _, relation := range relations {
+				if evaluated == pageSize {
+					position.Relation = relation
+					next = &position
+					return true, nil
+				}
+
+				cellTruncated, err := q.evaluateCell(ctx, typesys, req, objectType, position.Object, relation, userFilters, emit)
+				if err != nil {
+					return false, err
+				}
+				truncated = truncated || cellTruncated
+				evaluated++
+			}
+			return false, nil
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		if next != nil {
+			token, err := q.encodeToken(next)
+			if err != nil {
+				return nil, err
+			}
+			return &PermissionMatrixResponse{ContinuationToken: token, Truncated: truncated}, nil
+		}
+	}
+
+	return &PermissionMatrixResponse{Truncated: truncated}, nil
+}
+
+func (q *PermissionMatrixQuery) relationsByType(typesys *typesystem.TypeSystem, req *PermissionMatrixRequest) (map[string][]string, error) {
+	relationsByType := make(map[string][]string, len(req.ObjectTypes))
+	for _, objectType := range req.ObjectTypes {
+		relations, err := typesys.GetRelations(objectType)
+		if err != nil {
+			return nil, serverErrors.ValidationError(err)
+		}
+
+		if len(req.Relations) == 0 {
+			names := make([]string, 0, len(relations))
+			for name := range relations {
+				names = append(names, name)
+			}
+			slices.Sort(names)
+			relationsByType[objectType] = names
+			continue
+		}
+
+		for _, relation := range req.Relations {
+			if _, ok := relations[relation]; !ok {
+				return nil, serverErrors.ValidationError(
+					fmt.Errorf("relation '%s' is not defined for type '%s'", relation, objectType),
+				)
+			}
+		}
+		relationsByType[objectType] = req.Relations
+	}
+	return relationsByType, nil
+}
+
+func allUserTypeFilters(typesys *typesystem.TypeSystem) []*openfgav1.UserTypeFilter {
+	types := make([]string, 0, len(typesys.GetAllRelations()))
+	for userType := range typesys.GetAllRelations() {
+		types = append(types, userType)
+	}
+	slices.Sort(types)
+	return ParseUserTypeFilters(types)
+}
+
+// scanObjects calls visit with the position of every object of objectType, in the order of their first
+// tuple, from the position of from if it is not nil, until visit returns true. An object that appears in
+// no tuple cannot have any permission.
+func (q *PermissionMatrixQuery) scanObjects(
+	ctx context.Context,
+	req *PermissionMatrixRequest,
+	objectType string,
+	from *permissionMatrixToken,
+	visit func(position permissionMatrixToken) (bool, error),
+) error {
+	// The objects whose first tuple has been read, which are not visited again.
+	seen := make(map[string]struct{})
+	resumed := from == nil
+
+	position := permissionMatrixToken{ObjectType: objectType}
+	for {
+		tuples, next, err := q.datastore.ReadPage(ctx, req.StoreID, tuple.NewTupleKey(objectType+":", "", ""), storage.ReadPageOptions{
+			Pagination:  storage.NewPaginationOptions(permissionMatrixTuplePageSize, position.Tuples),
+			Consistency: storage.ConsistencyOptions{Preference: req.Consistency},
+		})
+		if err != nil {
+			return serverErrors.HandleError("", err)
+		}
+
+		for i, t := range tuples {
+			_, objectID := tuple.SplitObject(t.GetKey().GetObject())
+			if _, ok := seen[objectID]; ok {
+				continue
+			}
+
+			if q.maxObjects > 0 && len(seen) == int(q.maxObjects) {
+				return serverErrors.ValidationError(
+					fmt.Errorf("type '%s' has more than %d objects, which is the maximum allowed in a permission matrix", objectType, q.maxObjects),
+				)
+			}
+			seen[objectID] = struct{}{}
+
+			if !resumed {
+				// The objects before the first object of the page were visited by the previous pages.
+				resumed = objectID == from.Object || (position.Tuples == from.Tuples && i >= from.Offset)
+				if !resumed {
+					continue
+				}
+			}
+
+			position.Offset, position.Object = i, objectID
+			stop, err := visit(position)
+			if err != nil || stop {
+				return err
+			}
+		}
+
+		if next == "" {
+			return nil
+		}
+		position.Tuples = next
+	}
+}
+
+// evaluateCell emits the rows of a cell, and returns true if some of its users may be missing.
+func (q *PermissionMatrixQuery) evaluateCell(
+	ctx context.Context,
+	typesys *typesystem.TypeSystem,
+	req *PermissionMatrixRequest,
+	objectType, objectID, relation string,
+	userFilters []*openfgav1.UserTypeFilter,
+	emit func(*PermissionMatrixRow) error,
+) (bool, error) {
+	object := tuple.BuildObject(objectType, objectID)
+
+	resp, truncated, err := q.listUsers(ctx, &openfgav1.ListUsersRequest{
+		StoreId:              req.StoreID,
+		AuthorizationModelId: typesys.GetAuthorizationModelID(),
+		Object:               &openfgav1.Object{Type: objectType, Id: objectID},
+		Relation:             relation,
+		UserFilters:          userFilters,
+		Consistency:          req.Consistency,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	users := make([]string, 0, len(resp.GetUsers()))
+	for _, user := range resp.GetUsers() {
+		users = append(users, tuple.UserProtoToString(user))
+	}
+	slices.Sort(users)
+
+	for _, user := range slices.Compact(users) {
+		if err := emit(&PermissionMatrixRow{User: user, Relation: relation, Object: object}); err != nil {
+			return false, err
+		}
+	}
+	return truncated, nil
+}
+
+func (q *PermissionMatrixQuery) encodeToken(token *permissionMatrixToken) (string, error) {
+	marshalled, err := json.Marshal(token)
+	if err != nil {
+		return "", serverErrors.HandleError("", err)
+	}
+	return q.encoder.Encode(marshalled)
+}
+
+func (q *PermissionMatrixQuery) decodeToken(continuationToken string) (*permissionMatrixToken, error) {
+	if continuationToken == "" {
+		return nil, nil
+	}
+	decoded, err := q.encoder.Decode(continuationToken)
+	if err != nil {
+		return nil, serverErrors.ErrInvalidContinuationToken
+	}
+	var token permissionMatrixToken
+	if err := json.Unmarshal(decoded, &token); err != nil {
+		return nil, serverErrors.ErrInvalidContinuationToken
+	}
+	return &token, nil
+}
@@ -0,0 +1,277 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+
+	"github.com/oklog/ulid/v2"
+	"github.com/stretchr/testify/require"
+
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+	parser "github.com/openfga/language/pkg/go/transformer"
+
+	serverErrors "github.com/openfga/openfga/pkg/server/errors"
+	"github.com/openfga/openfga/pkg/storage"
+	"github.com/openfga/openfga/pkg/storage/memory"
+	"github.com/openfga/openfga/pkg/tuple"
+	"github.com/openfga/openfga/pkg/typesystem"
+)
+
+func TestPermissionMatrixQuery(t *testing.T) {
+	ctx := context.Background()
+	storeID := ulid.Make().String()
+
+	ds := memory.New()
+	t.Cleanup(ds.Close)
+
+	model := parser.MustTransformDSLToProto(`
+		model
+			schema 1.1
+		type user
+		type group
+			relations
+				define member: [user]
+		type document
+			relations
+				define editor: [user]
+				define viewer: [user, group#member] or editor`)
+	typesys, err := typesystem.New(model)
+	require.NoError(t, err)
+
+	err = ds.Write(ctx, storeID, nil, []*openfgav1.TupleKey{
+		tuple.NewTupleKey("document:b", "editor", "user:anne"),
+		tuple.NewTupleKey("document:a", "viewer", "user:bob"),
+		tuple.NewTupleKey("document:a", "viewer", "group:eng#member"),
+		tuple.NewTupleKey("group:eng", "member", "user:carl"),
+	})
+	require.NoError(t, err)
+
+	// listUsers returns one user per filter type, named after the cell, so that the order of the rows can be asserted.
+	// The query of a viewer of document:a is truncated.
+	listUsers := func(_ context.Context, req *openfgav1.ListUsersRequest) (*openfgav1.ListUsersResponse, bool, error) {
+		id := req.GetObject().GetId() + "-" + req.GetRelation()
+		var users []*openfgav1.User
+		for _, userFilter := range req.GetUserFilters() {
+			users = append(users, &openfgav1.User{User: &openfgav1.User_Object{Object: &openfgav1.Object{Type: userFilter.GetType(), Id: id}}})
+		}
+		return &openfgav1.ListUsersResponse{Users: users}, id == "a-viewer", nil
+	}
+
+	reads := &countingReader{RelationshipTupleReader: ds}
+	collect := func(t *testing.T, req *PermissionMatrixRequest, opts ...PermissionMatrixQueryOption) ([]PermissionMatrixRow, string, error) {
+		var rows []PermissionMatrixRow
+		resp, err := NewPermissionMatrixQuery(reads, listUsers, opts...).Execute(ctx, typesys, req, func(row *PermissionMatrixRow) error {
+			rows = append(rows, *row)
+			return nil
+		})
+		return rows, resp.GetContinuationToken(), err
+	}
+
+	t.Run("all_relations_in_order", func(t *testing.T) {
+		rows, token, err := collect(t, &PermissionMatrixRequest{
+			StoreID:     storeID,
+			ObjectTypes: []string{"document"},
+			UserFilters: ParseUserTypeFilters([]string{"user"}),
+		})
+		require.NoError(t, err)
+		require.Empty(t, token)
+		require.Equal(t, []PermissionMatrixRow{
+			{User: "user:b-editor", Relation: "editor", Object: "document:b"},
+			{User: "user:b-viewer", Relation: "viewer", Object: "document:b"},
+			{User: "user:a-editor", Relation: "editor", Object: "document:a"},
+			{User: "user:a-viewer", Relation: "viewer", Object: "document:a"},
+		}, rows)
+	})
+
+	t.Run("truncated_cells", func(t *testing.T) {
+		req := &PermissionMatrixRequest{
+			StoreID:     storeID,
+			ObjectTypes: []string{"document"},
+			UserFilters: ParseUserTypeFilters([]string{"user"}),
+			PageSize:    2,
+		}
+		resp, err := NewPermissionMatrixQuery(ds, listUsers).Execute(ctx, typesys, req, func(*PermissionMatrixRow) error { return nil })
+		require.NoError(t, err)
+		require.False(t, resp.Truncated)
+
+		req.ContinuationToken = resp.ContinuationToken
+		resp, err = NewPermissionMatrixQuery(ds, listUsers).Execute(ctx, typesys, req, func(*PermissionMatrixRow) error { return nil })
+		require.NoError(t, err)
+		require.True(t, resp.Truncated)
+	})
+
+	t.Run("one_list_users_per_cell", func(t *testing.T) {
+		calls := 0
+		listUsers := func(_ context.Context, req *openfgav1.ListUsersRequest) (*openfgav1.ListUsersResponse, bool, error) {
+			calls++
+			require.Len(t, req.GetUserFilters(), 2)
+			return &openfgav1.ListUsersResponse{}, false, nil
+		}
+		_, err := NewPermissionMatrixQuery(ds, listUsers).Execute(ctx, typesys, &PermissionMatrixRequest{
+			StoreID:     storeID,
+			ObjectTypes: []string{"document"},
+			UserFilters: ParseUserTypeFilters([]string{"user", "group#member"}),
+		}, func(*PermissionMatrixRow) error { return nil })
+		require.NoError(t, err)
+		require.Equal(t, 4, calls)
+	})
+
+	t.Run("default_user_filters_are_all_types", func(t *testing.T) {
+		rows, _, err := collect(t, &PermissionMatrixRequest{
+			StoreID:     storeID,
+			ObjectTypes: []string{"group"},
+		})
+		require.NoError(t, err)
+		require.Equal(t, []PermissionMatrixRow{
+			{User: "document:eng-member", Relation: "member", Object: "group:eng"},
+			{User: "group:eng-member", Relation: "member", Object: "group:eng"},
+			{User: "user:eng-member", Relation: "member", Object: "group:eng"},
+		}, rows)
+	})
+
+	t.Run("pages_resume_where_previous_page_stopped", func(t *testing.T) {
+		req := &PermissionMatrixRequest{
+			StoreID:     storeID,
+			ObjectTypes: []string{"document", "group"},
+			Relations:   nil,
+			UserFilters: ParseUserTypeFilters([]string{"user"}),
+			PageSize:    2,
+		}
+
+		var all []PermissionMatrixRow
+		pages := 0
+		for {
+			rows, token, err := collect(t, req)
+			require.NoError(t, err)
+			all = append(all, rows...)
+			pages++
+			if token == "" {
+				break
+			}
+			req.ContinuationToken = token
+		}
+		require.Equal(t, 3, pages)
+		require.Len(t, all, 5)
+		require.Equal(t, PermissionMatrixRow{User: "user:a-editor", Relation: "editor", Object: "document:a"}, all[2])
+		require.Equal(t, PermissionMatrixRow{User: "user:eng-member", Relation: "member", Object: "group:eng"}, all[4])
+	})
+
+	t.Run("objects_spread_over_tuple_pages", func(t *testing.T) {
+		storeID := ulid.Make().String()
+		var writes []*openfgav1.TupleKey
+		for i := range 3 * permissionMatrixTuplePageSize {
+			writes = append(writes, tuple.NewTupleKey(fmt.Sprintf("group:%d", i%7), "member", fmt.Sprintf("user:%d", i)))
+		}
+		require.NoError(t, ds.Write(ctx, storeID, nil, writes))
+
+		req := &PermissionMatrixRequest{
+			StoreID:     storeID,
+			ObjectTypes: []string{"group"},
+			UserFilters: ParseUserTypeFilters([]string{"user"}),
+			PageSize:    2,
+		}
+		var objects []string
+		for {
+			// The objects are deduplicated from the tuples of the type, which span 3 datastore pages.
+			reads.pages.Store(0)
+			rows, token, err := collect(t, req)
+			require.NoError(t, err)
+			require.LessOrEqual(t, reads.pages.Load(), int64(3))
+			for _, row := range rows {
+				objects = append(objects, row.Object)
+			}
+			if token == "" {
+				break
+			}
+			req.ContinuationToken = token
+		}
+		require.Equal(t, []string{"group:0", "group:1", "group:2", "group:3", "group:4", "group:5", "group:6"}, objects)
+	})
+
+	t.Run("resume_after_deleted_object", func(t *testing.T) {
+		storeID := ulid.Make().String()
+		require.NoError(t, ds.Write(ctx, storeID, nil, []*openfgav1.TupleKey{
+			tuple.NewTupleKey("group:a", "member", "user:anne"),
+			tuple.NewTupleKey("group:b", "member", "user:anne"),
+			tuple.NewTupleKey("group:c", "member", "user:anne"),
+		}))
+
+		req := &PermissionMatrixRequest{
+			StoreID:     storeID,
+			ObjectTypes: []string{"group"},
+			UserFilters: ParseUserTypeFilters([]string{"user"}),
+			PageSize:    1,
+		}
+		_, token, err := collect(t, req)
+		require.NoError(t, err)
+
+		// The next page starts at group:b, which is deleted before the page is read.
+		require.NoError(t, ds.Write(ctx, storeID, []*openfgav1.TupleKeyWithoutCondition{
+			tuple.TupleKeyToTupleKeyWithoutCondition(tuple.NewTupleKey("group:b", "member", "user:anne")),
+		}, nil))
+		req.ContinuationToken = token
+		rows, _, err := collect(t, req)
+		require.NoError(t, err)
+		require.Equal(t, []PermissionMatrixRow{{User: "user:c-member", Relation: "member", Object: "group:c"}}, rows)
+	})
+
+	t.Run("unknown_relation", func(t *testing.T) {
+		_, _, err := collect(t, &PermissionMatrixRequest{
+			StoreID:     storeID,
+			ObjectTypes: []string{"document", "group"},
+			Relations:   []string{"viewer"},
+		})
+		require.ErrorContains(t, err, "relation 'viewer' is not defined for type 'group'")
+	})
+
+	t.Run("too_many_objects", func(t *testing.T) {
+		_, _, err := collect(t, &PermissionMatrixRequest{
+			StoreID:     storeID,
+			ObjectTypes: []string{"document"},
+		}, WithPermissionMatrixMaxObjects(1))
+		require.ErrorContains(t, err, "type 'document' has more than 1 objects")
+	})
+
+	t.Run("invalid_token", func(t *testing.T) {
+		_, _, err := collect(t, &PermissionMatrixRequest{
+			StoreID:           storeID,
+			ObjectTypes:       []string{"document"},
+			ContinuationToken: "not-a-token",
+		})
+		require.ErrorIs(t, err, serverErrors.ErrInvalidContinuationToken)
+	})
+
+	t.Run("list_users_error_is_returned", func(t *testing.T) {
+		failing := func(context.Context, *openfgav1.ListUsersRequest) (*openfgav1.ListUsersResponse, bool, error) {
+			return nil, false, errors.New("boom")
+		}
+		_, err := NewPermissionMatrixQuery(ds, failing).Execute(ctx, typesys, &PermissionMatrixRequest{
+			StoreID:     storeID,
+			ObjectTypes: []string{"document"},
+		}, func(*PermissionMatrixRow) error { return nil })
+		require.EqualError(t, err, "boom")
+	})
+}
+
+func TestPermissionMatrixRequestValidate(t *testing.T) {
+	storeID := ulid.Make().String()
+
+	require.NoError(t, (&PermissionMatrixRequest{StoreID: storeID, ObjectTypes: []string{"document"}}).Validate())
+	require.ErrorContains(t, (&PermissionMatrixRequest{StoreID: "abc", ObjectTypes: []string{"document"}}).Validate(), "invalid store_id")
+	require.ErrorContains(t, (&PermissionMatrixRequest{StoreID: storeID}).Validate(), "at least one object type")
+	require.ErrorContains(t, (&PermissionMatrixRequest{StoreID: storeID, ObjectTypes: []string{"document"}, PageSize: 1001}).Validate(), "page_size")
+}
+
+// countingReader counts the pages of tuples read with ReadPage.
+type countingReader struct {
+	storage.RelationshipTupleReader
+	pages atomic.Int64
+}
+
+func (r *countingReader) ReadPage(ctx context.Context, store string, tk *openfgav1.TupleKey, options storage.ReadPageOptions) ([]*openfgav1.Tuple, string, error) {
+	r.pages.Add(1)
+	return r.RelationshipTupleReader.ReadPage(ctx, store, tk, options)
+}
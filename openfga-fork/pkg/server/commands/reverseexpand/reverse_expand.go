@@ -145,6 +145,9 @@ type ReverseExpandQuery struct {
 	// localCheckResolver allows reverse expand to call check locally
 	localCheckResolver   graph.CheckRewriteResolver
 	optimizationsEnabled bool
+
+	// candidateObjectIDs restricts the tuples of the target type that are read to those of these objects
+	candidateObjectIDs storage.SortedSet
 }
 
 type ReverseExpandQueryOption func(d *ReverseExpandQuery)
@@ -182,6 +185,23 @@ func WithListObjectOptimizationsEnabled(enabled bool) ReverseExpandQueryOption {
 	}
 }
 
+// WithCandidateObjectIDs restricts the reads of tuples of the target type to the objects with these IDs. It
+// must only be set when objects of the target type are not users of any relation, so that the objects of the
+// target type that are found are never expanded further.
+func WithCandidateObjectIDs(objectIDs storage.SortedSet) ReverseExpandQueryOption {
+	return func(d *ReverseExpandQuery) {
+		d.candidateObjectIDs = objectIDs
+	}
+}
+
+// readObjectIDs returns the object IDs that the tuples of objectType read for req are restricted to, if any.
+func (c *ReverseExpandQuery) readObjectIDs(req *ReverseExpandRequest, objectType string) storage.SortedSet {
+	if objectType != req.ObjectType {
+		return nil
+	}
+	return c.candidateObjectIDs
+}
+
 // TODO accept ReverseExpandRequest so we can build the datastore object right away.
 func NewReverseExpandQuery(ds storage.RelationshipTupleReader, ts *typesystem.TypeSystem, opts ...ReverseExpandQueryOption) *ReverseExpandQuery {
 	query := &ReverseExpandQuery{
@@ -641,6 +661,7 @@ func (c *ReverseExpandQuery) readTuplesAndExecute(
 		ObjectType: req.edge.TargetReference.GetType(),
 		Relation:   relationFilter,
 		UserFilter: userFilter,
+		ObjectIDs:  c.readObjectIDs(req, req.edge.TargetReference.GetType()),
 	}, storage.ReadStartingWithUserOptions{
 		Consistency: storage.ConsistencyOptions{
 			Preference: req.Consistency,
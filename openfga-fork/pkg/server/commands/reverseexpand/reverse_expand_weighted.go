@@ -516,6 +516,7 @@ func (c *ReverseExpandQuery) buildFilteredIterator(
 		ObjectType: objectType,
 		Relation:   relation,
 		UserFilter: userFilter,
+		ObjectIDs:  c.readObjectIDs(req, objectType),
 	}, storage.ReadStartingWithUserOptions{
 		Consistency: storage.ConsistencyOptions{
 			Preference: req.Consistency,
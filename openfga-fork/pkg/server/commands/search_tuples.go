@@ -0,0 +1,202 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+
+	"github.com/openfga/openfga/pkg/encoder"
+	"github.com/openfga/openfga/pkg/logger"
+	serverErrors "github.com/openfga/openfga/pkg/server/errors"
+	"github.com/openfga/openfga/pkg/storage"
+)
+
+const maxSearchTuplesPageSize = 100
+
+// SearchTuplesRequest searches the tuples of a store. Every filter that is set must match, empty filters are
+// ignored.
+type SearchTuplesRequest struct {
+	StoreID    string
+	ObjectType string
+	// ObjectIDPrefix matches the object IDs that start with it. It requires ObjectType.
+	ObjectIDPrefix string
+	Relation       string
+	// UserType matches the users of the type, including the wildcard and the usersets of the type.
+	UserType string
+	// UserPrefix matches the users that start with it, e.g. 'user:a' or 'group:eng#'.
+	UserPrefix    string
+	ConditionName string
+	// WrittenAfter is the inclusive lower bound of the time the tuples were written at, as an RFC 3339
+	// timestamp or a ULID. Timestamps are truncated to the millisecond.
+	WrittenAfter string
+	// WrittenBefore is the exclusive upper bound of the time the tuples were written at, as an RFC 3339
+	// timestamp or a ULID. Timestamps are truncated to the millisecond.
+	WrittenBefore string
+	// PageSize is the maximum number of tuples in the page. If 0, storage.DefaultPageSize tuples are returned.
+	PageSize          int32
+	ContinuationToken string
+	Consistency       openfgav1.ConsistencyPreference
+}
+
+// Validate returns an error if the request is malformed.
+func (r *SearchTuplesRequest) Validate() error {
+	if err := validateStoreID(r.StoreID); err != nil {
+		return err
+	}
+	if r.ObjectIDPrefix != "" && r.ObjectType == "" {
+		return errors.New("object_id_prefix requires object_type")
+	}
+	if strings.ContainsAny(r.ObjectType, ":#") {
+		return fmt.Errorf("invalid object_type '%s'", r.ObjectType)
+	}
+	if strings.ContainsAny(r.UserType, ":#") {
+		return fmt.Errorf("invalid user_type '%s'", r.UserType)
+	}
+	if r.PageSize < 0 || r.PageSize > maxSearchTuplesPageSize {
+		return fmt.Errorf("page_size must be between 0 and %d", maxSearchTuplesPageSize)
+	}
+	if _, err := parseWrittenAt("written_after", r.WrittenAfter); err != nil {
+		return err
+	}
+	if _, err := parseWrittenAt("written_before", r.WrittenBefore); err != nil {
+		return err
+	}
+	return nil
+}
+
+// parseWrittenAt returns the ULID bound of a time range given as an RFC 3339 timestamp or a ULID. The bound of
+// a timestamp is the lowest ULID of its millisecond.
+func parseWrittenAt(field, value string) (string, error) {
+	if value == "" {
+		return "", nil
+	}
+	if id, err := ulid.ParseStrict(value); err == nil {
+		return id.String(), nil
+	}
+	t, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return "", fmt.Errorf("%s must be an RFC 3339 timestamp or a ULID", field)
+	}
+	if t.Before(time.UnixMilli(0)) {
+		return "", fmt.Errorf("%s must not be before 1970-01-01T00:00:00Z", field)
+	}
+	id, err := ulid.New(ulid.Timestamp(t), nil)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", field, err)
+	}
+	return id.String(), nil
+}
+
+// SearchTuplesQuery searches the tuples of a store with the filters of [storage.TupleSearchBackend].
+type SearchTuplesQuery struct {
+	datastore       storage.TupleSearchBackend
+	logger          logger.Logger
+	encoder         encoder.Encoder
+	tokenSerializer encoder.ContinuationTokenSerializer
+}
+
+type SearchTuplesQueryOption func(*SearchTuplesQuery)
+
+func WithSearchTuplesQueryLogger(l logger.Logger) SearchTuplesQueryOption {
+	return func(q *SearchTuplesQuery) {
+		q.logger = l
+	}
+}
+
+func WithSearchTuplesQueryEncoder(e encoder.Encoder) SearchTuplesQueryOption {
+	return func(q *SearchTuplesQuery) {
+		q.encoder = e
+	}
+}
+
+func WithSearchTuplesQueryTokenSerializer(serializer encoder.ContinuationTokenSerializer) SearchTuplesQueryOption {
+	return func(q *SearchTuplesQuery) {
+		q.tokenSerializer = serializer
+	}
+}
+
+// NewSearchTuplesQuery creates a SearchTuplesQuery using the provided datastore implementation.
+func NewSearchTuplesQuery(datastore storage.TupleSearchBackend, opts ...SearchTuplesQueryOption) *SearchTuplesQuery {
+	q := &SearchTuplesQuery{
+		datastore:       datastore,
+		logger:          logger.NewNoopLogger(),
+		encoder:         encoder.NewBase64Encoder(),
+		tokenSerializer: encoder.NewStringContinuationTokenSerializer(),
+	}
+
+	for _, opt := range opts {
+		opt(q)
+	}
+	return q
+}
+
+// Execute returns a page of the tuples that match the request, in the order they were written.
+func (q *SearchTuplesQuery) Execute(ctx context.Context, req *SearchTuplesRequest) (*openfgav1.ReadResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, serverErrors.ValidationError(err)
+	}
+
+	fromULID, _ := parseWrittenAt("written_after", req.WrittenAfter)
+	toULID, _ := parseWrittenAt("written_before", req.WrittenBefore)
+
+	decodedContToken, err := q.encoder.Decode(req.ContinuationToken)
+	if err != nil {
+		return nil, serverErrors.ErrInvalidContinuationToken
+	}
+
+	var from string
+	if len(decodedContToken) > 0 {
+		from, _, err = q.tokenSerializer.Deserialize(string(decodedContToken))
+		if err != nil {
+			return nil, serverErrors.ErrInvalidContinuationToken
+		}
+	}
+
+	filter := storage.SearchTuplesFilter{
+		ObjectType:     req.ObjectType,
+		ObjectIDPrefix: req.ObjectIDPrefix,
+		Relation:       req.Relation,
+		UserType:       req.UserType,
+		UserPrefix:     req.UserPrefix,
+		ConditionName:  req.ConditionName,
+		FromULID:       fromULID,
+		ToULID:         toULID,
+	}
+	opts := storage.SearchTuplesOptions{
+		Pagination:  storage.NewPaginationOptions(req.PageSize, from),
+		Consistency: storage.ConsistencyOptions{Preference: req.Consistency},
+	}
+
+	tuples, contUlid, err := q.datastore.SearchTuples(ctx, req.StoreID, filter, opts)
+	if err != nil {
+		return nil, serverErrors.HandleError("", err)
+	}
+
+	if len(contUlid) == 0 {
+		return &openfgav1.ReadResponse{
+			Tuples:            tuples,
+			ContinuationToken: "",
+		}, nil
+	}
+
+	contToken, err := q.tokenSerializer.Serialize(contUlid, "")
+	if err != nil {
+		return nil, serverErrors.HandleError("", err)
+	}
+
+	encodedContToken, err := q.encoder.Encode(contToken)
+	if err != nil {
+		return nil, serverErrors.HandleError("", err)
+	}
+
+	return &openfgav1.ReadResponse{
+		Tuples:            tuples,
+		ContinuationToken: encodedContToken,
+	}, nil
+}
@@ -0,0 +1,126 @@
+package commands
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+
+	serverErrors "github.com/openfga/openfga/pkg/server/errors"
+	"github.com/openfga/openfga/pkg/storage/memory"
+	"github.com/openfga/openfga/pkg/tuple"
+)
+
+func TestSearchTuplesQuery(t *testing.T) {
+	t.Cleanup(func() {
+		goleak.VerifyNone(t)
+	})
+
+	ctx := context.Background()
+	ds := memory.New().(*memory.MemoryBackend)
+	t.Cleanup(ds.Close)
+	storeID := ulid.Make().String()
+
+	err := ds.Write(ctx, storeID, nil, []*openfgav1.TupleKey{
+		tuple.NewTupleKey("menu_item:report-1", "viewer", "user:anne"),
+		tuple.NewTupleKey("menu_item:report-2", "viewer", "user:bob"),
+		tuple.NewTupleKey("menu_item:settings", "viewer", "user:anne"),
+	})
+	require.NoError(t, err)
+
+	time.Sleep(2 * time.Millisecond)
+	boundary := time.Now()
+	time.Sleep(2 * time.Millisecond)
+
+	err = ds.Write(ctx, storeID, nil, []*openfgav1.TupleKey{
+		tuple.NewTupleKeyWithCondition("menu_item:report-3", "viewer", "user:charlie", "business_hours", nil),
+	})
+	require.NoError(t, err)
+
+	objects := func(res *openfgav1.ReadResponse) []string {
+		var objects []string
+		for _, tk := range res.GetTuples() {
+			objects = append(objects, tk.GetKey().GetObject())
+		}
+		return objects
+	}
+
+	t.Run("object_id_prefix_paginated", func(t *testing.T) {
+		q := NewSearchTuplesQuery(ds)
+		req := &SearchTuplesRequest{StoreID: storeID, ObjectType: "menu_item", ObjectIDPrefix: "report-", PageSize: 2}
+
+		res, err := q.Execute(ctx, req)
+		require.NoError(t, err)
+		require.Equal(t, []string{"menu_item:report-1", "menu_item:report-2"}, objects(res))
+		require.NotEmpty(t, res.GetContinuationToken())
+
+		req.ContinuationToken = res.GetContinuationToken()
+		res, err = q.Execute(ctx, req)
+		require.NoError(t, err)
+		require.Equal(t, []string{"menu_item:report-3"}, objects(res))
+		require.Empty(t, res.GetContinuationToken())
+	})
+
+	t.Run("written_after_and_before_timestamp", func(t *testing.T) {
+		q := NewSearchTuplesQuery(ds)
+
+		res, err := q.Execute(ctx, &SearchTuplesRequest{StoreID: storeID, WrittenAfter: boundary.Format(time.RFC3339Nano)})
+		require.NoError(t, err)
+		require.Equal(t, []string{"menu_item:report-3"}, objects(res))
+
+		res, err = q.Execute(ctx, &SearchTuplesRequest{StoreID: storeID, WrittenBefore: boundary.Format(time.RFC3339Nano), UserPrefix: "user:anne"})
+		require.NoError(t, err)
+		require.ElementsMatch(t, []string{"menu_item:report-1", "menu_item:settings"}, objects(res))
+	})
+
+	t.Run("written_before_ulid", func(t *testing.T) {
+		before := ulid.MustNew(ulid.Timestamp(boundary), nil).String()
+		res, err := NewSearchTuplesQuery(ds).Execute(ctx, &SearchTuplesRequest{StoreID: storeID, WrittenBefore: before})
+		require.NoError(t, err)
+		require.Len(t, res.GetTuples(), 3)
+	})
+
+	t.Run("condition_name", func(t *testing.T) {
+		res, err := NewSearchTuplesQuery(ds).Execute(ctx, &SearchTuplesRequest{StoreID: storeID, ConditionName: "business_hours"})
+		require.NoError(t, err)
+		require.Equal(t, []string{"menu_item:report-3"}, objects(res))
+		require.Equal(t, "business_hours", res.GetTuples()[0].GetKey().GetCondition().GetName())
+	})
+
+	t.Run("invalid_continuation_token", func(t *testing.T) {
+		_, err := NewSearchTuplesQuery(ds).Execute(ctx, &SearchTuplesRequest{StoreID: storeID, ContinuationToken: "invalid"})
+		require.ErrorIs(t, err, serverErrors.ErrInvalidContinuationToken)
+	})
+}
+
+func TestSearchTuplesRequestValidate(t *testing.T) {
+	storeID := ulid.Make().String()
+
+	require.NoError(t, (&SearchTuplesRequest{StoreID: storeID}).Validate())
+	require.NoError(t, (&SearchTuplesRequest{StoreID: storeID, WrittenAfter: "2024-01-02T03:04:05Z", WrittenBefore: ulid.Make().String()}).Validate())
+
+	require.Error(t, (&SearchTuplesRequest{StoreID: "invalid"}).Validate())
+	require.ErrorContains(t, (&SearchTuplesRequest{StoreID: storeID, ObjectIDPrefix: "report-"}).Validate(), "object_id_prefix requires object_type")
+	require.ErrorContains(t, (&SearchTuplesRequest{StoreID: storeID, UserType: "group:eng"}).Validate(), "invalid user_type")
+	require.ErrorContains(t, (&SearchTuplesRequest{StoreID: storeID, PageSize: 101}).Validate(), "page_size")
+	require.ErrorContains(t, (&SearchTuplesRequest{StoreID: storeID, WrittenAfter: "yesterday"}).Validate(), "written_after")
+	require.ErrorContains(t, (&SearchTuplesRequest{StoreID: storeID, WrittenBefore: "1969-12-31T00:00:00Z"}).Validate(), "written_before")
+}
+
+func TestParseWrittenAt(t *testing.T) {
+	at := time.Date(2024, 1, 2, 3, 4, 5, 6_500_000, time.UTC)
+
+	bound, err := parseWrittenAt("written_after", at.Format(time.RFC3339Nano))
+	require.NoError(t, err)
+	parsed := ulid.MustParse(bound)
+	require.Equal(t, ulid.Timestamp(at), parsed.Time())
+	require.Equal(t, ulid.ULID{}.Entropy(), parsed.Entropy())
+
+	_, err = parseWrittenAt("written_after", "")
+	require.NoError(t, err)
+}
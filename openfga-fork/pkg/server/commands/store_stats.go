@@ -0,0 +1,143 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/openfga/openfga/pkg/logger"
+	serverErrors "github.com/openfga/openfga/pkg/server/errors"
+	"github.com/openfga/openfga/pkg/storage"
+)
+
+// GetStoreStatsRequest asks for the statistics of a store.
+type GetStoreStatsRequest struct {
+	StoreID string `json:"store_id"`
+}
+
+// Validate returns an error if the store ID is not a valid ULID.
+func (r *GetStoreStatsRequest) Validate() error {
+	return validateStoreID(r.StoreID)
+}
+
+// StoreStatsTupleCount is the number of tuples of a store with the same object type, relation and user type.
+type StoreStatsTupleCount struct {
+	ObjectType string `json:"object_type"`
+	Relation   string `json:"relation"`
+	// UserType is the type of the users, usersets and wildcards of the tuples.
+	UserType string `json:"user_type"`
+	Count    int64  `json:"count"`
+}
+
+// GetStoreStatsResponse describes the size of a store.
+type GetStoreStatsResponse struct {
+	StoreID string `json:"store_id"`
+	// TupleCount is the total number of tuples in the store.
+	TupleCount  int64                  `json:"tuple_count"`
+	TupleCounts []StoreStatsTupleCount `json:"tuple_counts"`
+	// ChangelogCount is the number of changes in the changelog of the store.
+	ChangelogCount          int64 `json:"changelog_count"`
+	AuthorizationModelCount int64 `json:"authorization_model_count"`
+	// LatestChangeULID is the ULID of the latest change of the store, or empty if it has no changes.
+	LatestChangeULID string `json:"latest_change_ulid"`
+	// ComputedAt is when the statistics were read from the datastore. It is older than the request when the
+	// response is cached.
+	ComputedAt time.Time `json:"computed_at"`
+}
+
+// StoreStatsBackend is the part of the datastore GetStoreStatsQuery needs.
+type StoreStatsBackend interface {
+	storage.StoresBackend
+	storage.StoreStatsBackend
+}
+
+// GetStoreStatsQuery reads the statistics of a store, optionally caching them.
+type GetStoreStatsQuery struct {
+	backend  StoreStatsBackend
+	logger   logger.Logger
+	cache    storage.InMemoryCache[*GetStoreStatsResponse]
+	cacheTTL time.Duration
+}
+
+type GetStoreStatsQueryOption func(*GetStoreStatsQuery)
+
+func WithGetStoreStatsQueryLogger(l logger.Logger) GetStoreStatsQueryOption {
+	return func(q *GetStoreStatsQuery) {
+		q.logger = l
+	}
+}
+
+// WithGetStoreStatsQueryCache caches the statistics of each store for the ttl. The cache is disabled if the
+// cache is nil or the ttl is not positive.
+func WithGetStoreStatsQueryCache(cache storage.InMemoryCache[*GetStoreStatsResponse], ttl time.Duration) GetStoreStatsQueryOption {
+	return func(q *GetStoreStatsQuery) {
+		q.cache = cache
+		q.cacheTTL = ttl
+	}
+}
+
+func NewGetStoreStatsQuery(backend StoreStatsBackend, opts ...GetStoreStatsQueryOption) *GetStoreStatsQuery {
+	q := &GetStoreStatsQuery{
+		backend: backend,
+		logger:  logger.NewNoopLogger(),
+	}
+
+	for _, opt := range opts {
+		opt(q)
+	}
+	return q
+}
+
+func storeStatsCacheKey(storeID string) string {
+	return "store_stats/" + storeID
+}
+
+// Execute returns the statistics of the store, or serverErrors.ErrStoreIDNotFound if the store does not exist.
+func (q *GetStoreStatsQuery) Execute(ctx context.Context, req *GetStoreStatsRequest) (*GetStoreStatsResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, serverErrors.ValidationError(err)
+	}
+
+	cacheEnabled := q.cache != nil && q.cacheTTL > 0
+	if cacheEnabled {
+		if res := q.cache.Get(storeStatsCacheKey(req.StoreID)); res != nil {
+			return res, nil
+		}
+	}
+
+	if _, err := q.backend.GetStore(ctx, req.StoreID); err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, serverErrors.ErrStoreIDNotFound
+		}
+		return nil, serverErrors.HandleError("", err)
+	}
+
+	stats, err := q.backend.ReadStoreStats(ctx, req.StoreID)
+	if err != nil {
+		return nil, serverErrors.HandleError("", err)
+	}
+
+	res := &GetStoreStatsResponse{
+		StoreID:                 req.StoreID,
+		TupleCounts:             make([]StoreStatsTupleCount, 0, len(stats.TupleCounts)),
+		ChangelogCount:          stats.ChangelogCount,
+		AuthorizationModelCount: stats.AuthorizationModelCount,
+		LatestChangeULID:        stats.LatestChangeULID,
+		ComputedAt:              time.Now().UTC(),
+	}
+	for _, count := range stats.TupleCounts {
+		res.TupleCount += count.Count
+		res.TupleCounts = append(res.TupleCounts, StoreStatsTupleCount{
+			ObjectType: count.ObjectType,
+			Relation:   count.Relation,
+			UserType:   count.UserType,
+			Count:      count.Count,
+		})
+	}
+
+	if cacheEnabled {
+		q.cache.Set(storeStatsCacheKey(req.StoreID), res, q.cacheTTL)
+	}
+
+	return res, nil
+}
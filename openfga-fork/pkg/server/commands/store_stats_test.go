@@ -0,0 +1,85 @@
+package commands
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+
+	serverErrors "github.com/openfga/openfga/pkg/server/errors"
+	"github.com/openfga/openfga/pkg/storage"
+	"github.com/openfga/openfga/pkg/storage/memory"
+	"github.com/openfga/openfga/pkg/tuple"
+)
+
+func TestGetStoreStatsQuery(t *testing.T) {
+	t.Cleanup(func() {
+		goleak.VerifyNone(t)
+	})
+
+	ctx := context.Background()
+	ds := memory.New().(*memory.MemoryBackend)
+	t.Cleanup(ds.Close)
+
+	storeID := ulid.Make().String()
+	_, err := ds.CreateStore(ctx, &openfgav1.Store{Id: storeID, Name: "store"})
+	require.NoError(t, err)
+
+	err = ds.Write(ctx, storeID, nil, []*openfgav1.TupleKey{
+		tuple.NewTupleKey("document:1", "viewer", "user:anne"),
+		tuple.NewTupleKey("document:1", "viewer", "group:eng#member"),
+		tuple.NewTupleKey("document:2", "viewer", "user:*"),
+	})
+	require.NoError(t, err)
+
+	t.Run("stats", func(t *testing.T) {
+		res, err := NewGetStoreStatsQuery(ds).Execute(ctx, &GetStoreStatsRequest{StoreID: storeID})
+		require.NoError(t, err)
+		require.Equal(t, storeID, res.StoreID)
+		require.Equal(t, int64(3), res.TupleCount)
+		require.Equal(t, []StoreStatsTupleCount{
+			{ObjectType: "document", Relation: "viewer", UserType: "group", Count: 1},
+			{ObjectType: "document", Relation: "viewer", UserType: "user", Count: 2},
+		}, res.TupleCounts)
+		require.Equal(t, int64(3), res.ChangelogCount)
+		require.Equal(t, int64(0), res.AuthorizationModelCount)
+		require.NotEmpty(t, res.LatestChangeULID)
+		require.False(t, res.ComputedAt.IsZero())
+	})
+
+	t.Run("cached", func(t *testing.T) {
+		cache, err := storage.NewInMemoryLRUCache[*GetStoreStatsResponse]()
+		require.NoError(t, err)
+		t.Cleanup(cache.Stop)
+
+		q := NewGetStoreStatsQuery(ds, WithGetStoreStatsQueryCache(cache, time.Hour))
+		first, err := q.Execute(ctx, &GetStoreStatsRequest{StoreID: storeID})
+		require.NoError(t, err)
+
+		err = ds.Write(ctx, storeID, nil, []*openfgav1.TupleKey{tuple.NewTupleKey("document:3", "viewer", "user:bob")})
+		require.NoError(t, err)
+
+		second, err := q.Execute(ctx, &GetStoreStatsRequest{StoreID: storeID})
+		require.NoError(t, err)
+		require.Same(t, first, second)
+
+		uncached, err := NewGetStoreStatsQuery(ds).Execute(ctx, &GetStoreStatsRequest{StoreID: storeID})
+		require.NoError(t, err)
+		require.Equal(t, first.TupleCount+1, uncached.TupleCount)
+	})
+
+	t.Run("store_not_found", func(t *testing.T) {
+		_, err := NewGetStoreStatsQuery(ds).Execute(ctx, &GetStoreStatsRequest{StoreID: ulid.Make().String()})
+		require.ErrorIs(t, err, serverErrors.ErrStoreIDNotFound)
+	})
+
+	t.Run("invalid_store_id", func(t *testing.T) {
+		_, err := NewGetStoreStatsQuery(ds).Execute(ctx, &GetStoreStatsRequest{StoreID: "invalid"})
+		require.ErrorContains(t, err, "invalid store_id")
+	})
+}
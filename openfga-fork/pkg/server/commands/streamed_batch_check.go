@@ -0,0 +1,240 @@
+package commands
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
+
+	"github.com/oklog/ulid/v2"
+	"go.uber.org/zap"
+
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+
+	"github.com/openfga/openfga/internal/concurrency"
+)
+
+type StreamedBatchCheckParams struct {
+	StoreID              string
+	AuthorizationModelID string
+	Consistency          openfgav1.ConsistencyPreference
+	// Next returns the next check of the stream, or io.EOF once every check has been received.
+	Next func() (*openfgav1.BatchCheckItem, error)
+}
+
+// Validate returns an error if the parameters of the stream are malformed. The checks are validated as they
+// are received.
+func (p *StreamedBatchCheckParams) Validate() error {
+	if err := validateStoreID(p.StoreID); err != nil {
+		return err
+	}
+	if p.AuthorizationModelID != "" {
+		if _, err := ulid.Parse(p.AuthorizationModelID); err != nil {
+			return fmt.Errorf("invalid authorization_model_id '%s'", p.AuthorizationModelID)
+		}
+	}
+	return nil
+}
+
+// StreamedBatchCheckMetadata is sent after the last result of a streamed batch check.
+type StreamedBatchCheckMetadata struct {
+	// CheckCount is the number of checks received, including the duplicate ones.
+	CheckCount          int    `json:"check_count"`
+	DuplicateCheckCount int    `json:"duplicate_check_count"`
+	ThrottleCount       uint32 `json:"throttle_count"`
+	DispatchCount       uint32 `json:"dispatch_count"`
+	DatastoreQueryCount uint32 `json:"datastore_query_count"`
+}
+
+// defaultMaxStreamedOutcomes is the number of outcomes of completed checks kept to deduplicate the checks of a
+// stream.
+const defaultMaxStreamedOutcomes = 10_000
+
+// streamedCheck is a running check of a stream. It keeps the checks received with the same cache key while it
+// runs, whose results are sent with its own.
+type streamedCheck struct {
+	pending []*openfgav1.BatchCheckItem
+}
+
+// streamedOutcomes keeps the outcomes of the most recently used completed checks of a stream, up to capacity.
+type streamedOutcomes struct {
+	capacity int
+	// order holds the *streamedOutcome of the checks, most recently used first.
+	order    *list.List
+	outcomes map[CacheKey]*list.Element
+}
+
+type streamedOutcome struct {
+	key     CacheKey
+	outcome *BatchCheckOutcome
+}
+
+func newStreamedOutcomes(capacity int) *streamedOutcomes {
+	return &streamedOutcomes{
+		capacity: capacity,
+		order:    list.New(),
+		outcomes: map[CacheKey]*list.Element{},
+	}
+}
+
+func (o *streamedOutcomes) get(key CacheKey) (*BatchCheckOutcome, bool) {
+	element, ok := o.outcomes[key]
+	if !ok {
+		return nil, false
+	}
+	o.order.MoveToFront(element)
+	return element.Value.(*streamedOutcome).outcome, true
+}
+
+// add keeps the outcome of key, and drops the least recently used outcome if over capacity.
+func (o *streamedOutcomes) add(key CacheKey, outcome *BatchCheckOutcome) {
+	if o.capacity <= 0 {
+		return
+	}
+	o.outcomes[key] = o.order.PushFront(&streamedOutcome{key: key, outcome: outcome})
+	if o.order.Len() > o.capacity {
+		oldest := o.order.Remove(o.order.Back()).(*streamedOutcome)
+		delete(o.outcomes, oldest.key)
+	}
+}
+
+// ExecuteStreamed runs the checks returned by params.Next as they are received, with at most the configured
+// number of concurrent checks; receiving the next check waits while they are all busy. The outcome of each
+// check is sent to emit as soon as it is known. Checks with the same cache key as a running check, or as one of
+// the last 10,000 completed checks of the stream, are not evaluated again, and get the outcome of that check.
+// The number of checks is not limited by the maximum number of checks per batch, and the memory used does not
+// grow with it.
+//
+// Correlation IDs must be unique among the checks whose results have not been sent yet. An invalid check, or an
+// error returned by params.Next or emit, stops the stream: the checks that are running are canceled and the
+// error is returned.
+func (bq *BatchCheckQuery) ExecuteStreamed(
+	ctx context.Context,
+	params *StreamedBatchCheckParams,
+	emit func(*openfgav1.BatchCheckItem, *BatchCheckOutcome) error,
+) (*StreamedBatchCheckMetadata, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var totalQueryCount atomic.Uint32
+	var totalDispatchCount atomic.Uint32
+	var totalThrottleCount atomic.Uint32
+
+	// mu guards the checks of the stream and serializes the calls to emit.
+	var mu sync.Mutex
+	var emitErr error
+	// pendingIDs are the correlation IDs of the checks whose results have not been sent yet.
+	pendingIDs := map[CorrelationID]struct{}{}
+	running := map[CacheKey]*streamedCheck{}
+	completed := newStreamedOutcomes(bq.maxStreamedOutcomes)
+	checkCount := 0
+	evaluatedCount := 0
+
+	pool := concurrency.NewPool(ctx, int(bq.maxConcurrentChecks))
+
+	var err error
+	for {
+		var check *openfgav1.BatchCheckItem
+		check, err = params.Next()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				err = nil
+			}
+			break
+		}
+		checkCount++
+
+		correlationID := CorrelationID(check.GetCorrelationId())
+		if correlationID == "" {
+			err = &BatchCheckValidationError{
+				Message: "received empty correlation id for tuple: " + check.GetTupleKey().String(),
+			}
+			break
+		}
+
+		var key CacheKey
+		key, err = generateCacheKeyFromCheck(check, params.StoreID, bq.typesys.GetAuthorizationModelID())
+		if err != nil {
+			bq.logger.Error("batch check cache key computation failed with error", zap.Error(err))
+			break
+		}
+
+		mu.Lock()
+		if emitErr != nil {
+			mu.Unlock()
+			break
+		}
+		if _, ok := pendingIDs[correlationID]; ok {
+			mu.Unlock()
+			err = &BatchCheckValidationError{
+				Message: "received duplicate correlation id: " + string(correlationID),
+			}
+			break
+		}
+
+		if outcome, ok := completed.get(key); ok {
+			emitErr = emit(check, outcome)
+			mu.Unlock()
+			continue
+		}
+		pendingIDs[correlationID] = struct{}{}
+		if item, ok := running[key]; ok {
+			item.pending = append(item.pending, check)
+			mu.Unlock()
+			continue
+		}
+		item := &streamedCheck{pending: []*openfgav1.BatchCheckItem{check}}
+		running[key] = item
+		evaluatedCount++
+		mu.Unlock()
+
+		pool.Go(func(ctx context.Context) error {
+			response, metadata, err := bq.executeCheck(ctx, params.StoreID, params.Consistency, check)
+			if metadata != nil {
+				if metadata.WasThrottled.Load() {
+					totalThrottleCount.Add(1)
+				}
+				totalDispatchCount.Add(metadata.DispatchCounter.Load())
+			}
+			totalQueryCount.Add(response.GetResolutionMetadata().DatastoreQueryCount)
+
+			mu.Lock()
+			defer mu.Unlock()
+			outcome := &BatchCheckOutcome{CheckResponse: response, Err: err}
+			for _, pending := range item.pending {
+				if emitErr != nil {
+					break
+				}
+				emitErr = emit(pending, outcome)
+				delete(pendingIDs, CorrelationID(pending.GetCorrelationId()))
+			}
+			delete(running, key)
+			completed.add(key, outcome)
+			// Stop the other checks when the results can no longer be sent.
+			return emitErr
+		})
+	}
+
+	if err != nil {
+		cancel()
+	}
+	_ = pool.Wait()
+
+	if err != nil {
+		return nil, err
+	}
+	if emitErr != nil {
+		return nil, emitErr
+	}
+
+	return &StreamedBatchCheckMetadata{
+		CheckCount:          checkCount,
+		DuplicateCheckCount: checkCount - evaluatedCount,
+		ThrottleCount:       totalThrottleCount.Load(),
+		DispatchCount:       totalDispatchCount.Load(),
+		DatastoreQueryCount: totalQueryCount.Load(),
+	}, nil
+}
@@ -0,0 +1,255 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/oklog/ulid/v2"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+	"go.uber.org/mock/gomock"
+
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+
+	"github.com/openfga/openfga/internal/graph"
+	mockstorage "github.com/openfga/openfga/internal/mocks"
+	"github.com/openfga/openfga/pkg/server/config"
+	"github.com/openfga/openfga/pkg/testutils"
+	"github.com/openfga/openfga/pkg/typesystem"
+)
+
+func TestBatchCheckQueryExecuteStreamed(t *testing.T) {
+	t.Cleanup(func() {
+		goleak.VerifyNone(t)
+	})
+
+	mockController := gomock.NewController(t)
+	defer mockController.Finish()
+	ds := mockstorage.NewMockOpenFGADatastore(mockController)
+
+	model := testutils.MustTransformDSLToProtoWithID(`
+		model
+			schema 1.1
+		type user
+		type doc
+			relations
+				define viewer: [user]
+	`)
+	ts, err := typesystem.NewAndValidate(context.Background(), model)
+	require.NoError(t, err)
+
+	// streamOf returns the checks of objects, in order, with a distinct correlation ID each.
+	streamOf := func(objects ...string) func() (*openfgav1.BatchCheckItem, error) {
+		i := 0
+		return func() (*openfgav1.BatchCheckItem, error) {
+			if i == len(objects) {
+				return nil, io.EOF
+			}
+			check := &openfgav1.BatchCheckItem{
+				TupleKey: &openfgav1.CheckRequestTupleKey{
+					Object:   objects[i],
+					Relation: "viewer",
+					User:     "user:justin",
+				},
+				CorrelationId: fmt.Sprintf("id%d", i),
+			}
+			i++
+			return check, nil
+		}
+	}
+
+	t.Run("more_checks_than_a_batch_deduplicated_across_the_stream", func(t *testing.T) {
+		mockCheckResolver := graph.NewMockCheckResolver(mockController)
+		cmd := NewBatchCheckCommand(ds, mockCheckResolver, ts, WithBatchCheckMaxConcurrentChecks(4))
+
+		numChecks := 3 * config.DefaultMaxChecksPerBatchCheck
+		objects := make([]string, numChecks)
+		for i := range objects {
+			objects[i] = fmt.Sprintf("doc:%d", i%10)
+		}
+
+		mockCheckResolver.EXPECT().ResolveCheck(gomock.Any(), gomock.Any()).
+			Times(10).
+			DoAndReturn(func(_ context.Context, req *graph.ResolveCheckRequest) (*graph.ResolveCheckResponse, error) {
+				return &graph.ResolveCheckResponse{Allowed: req.GetTupleKey().GetObject() == "doc:1"}, nil
+			})
+
+		results := map[string]bool{}
+		meta, err := cmd.ExecuteStreamed(context.Background(), &StreamedBatchCheckParams{
+			StoreID: ulid.Make().String(),
+			Next:    streamOf(objects...),
+		}, func(check *openfgav1.BatchCheckItem, outcome *BatchCheckOutcome) error {
+			require.NoError(t, outcome.Err)
+			require.NotContains(t, results, check.GetCorrelationId())
+			results[check.GetCorrelationId()] = outcome.CheckResponse.GetAllowed()
+			return nil
+		})
+		require.NoError(t, err)
+
+		require.Len(t, results, numChecks)
+		for i := 0; i < numChecks; i++ {
+			require.Equal(t, i%10 == 1, results[fmt.Sprintf("id%d", i)])
+		}
+		require.Equal(t, numChecks, meta.CheckCount)
+		require.Equal(t, numChecks-10, meta.DuplicateCheckCount)
+	})
+
+	t.Run("duplicate_correlation_id", func(t *testing.T) {
+		mockCheckResolver := graph.NewMockCheckResolver(mockController)
+		// The first check runs until the stream is stopped, so its correlation ID is still pending.
+		mockCheckResolver.EXPECT().ResolveCheck(gomock.Any(), gomock.Any()).
+			AnyTimes().
+			DoAndReturn(func(ctx context.Context, _ *graph.ResolveCheckRequest) (*graph.ResolveCheckResponse, error) {
+				<-ctx.Done()
+				return nil, ctx.Err()
+			})
+		cmd := NewBatchCheckCommand(ds, mockCheckResolver, ts)
+
+		sent := false
+		_, err := cmd.ExecuteStreamed(context.Background(), &StreamedBatchCheckParams{
+			StoreID: ulid.Make().String(),
+			Next: func() (*openfgav1.BatchCheckItem, error) {
+				check := &openfgav1.BatchCheckItem{
+					TupleKey:      &openfgav1.CheckRequestTupleKey{Object: "doc:1", Relation: "viewer", User: "user:justin"},
+					CorrelationId: "id",
+				}
+				if sent {
+					check.TupleKey.Object = "doc:2"
+				}
+				sent = true
+				return check, nil
+			},
+		}, func(*openfgav1.BatchCheckItem, *BatchCheckOutcome) error {
+			return nil
+		})
+		var validationError *BatchCheckValidationError
+		require.ErrorAs(t, err, &validationError)
+		require.Contains(t, err.Error(), "duplicate correlation id")
+	})
+
+	t.Run("correlation_id_reused_once_its_result_is_sent", func(t *testing.T) {
+		mockCheckResolver := graph.NewMockCheckResolver(mockController)
+		mockCheckResolver.EXPECT().ResolveCheck(gomock.Any(), gomock.Any()).
+			Times(2).
+			Return(&graph.ResolveCheckResponse{Allowed: true}, nil)
+		cmd := NewBatchCheckCommand(ds, mockCheckResolver, ts)
+
+		emitted := make(chan struct{}, 2)
+		objects := []string{"doc:1", "doc:2"}
+		sent := 0
+		meta, err := cmd.ExecuteStreamed(context.Background(), &StreamedBatchCheckParams{
+			StoreID: ulid.Make().String(),
+			Next: func() (*openfgav1.BatchCheckItem, error) {
+				if sent == len(objects) {
+					return nil, io.EOF
+				}
+				if sent > 0 {
+					<-emitted
+				}
+				sent++
+				return &openfgav1.BatchCheckItem{
+					TupleKey:      &openfgav1.CheckRequestTupleKey{Object: objects[sent-1], Relation: "viewer", User: "user:justin"},
+					CorrelationId: "id",
+				}, nil
+			},
+		}, func(*openfgav1.BatchCheckItem, *BatchCheckOutcome) error {
+			emitted <- struct{}{}
+			return nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, 2, meta.CheckCount)
+	})
+
+	t.Run("least_recently_used_outcomes_evicted", func(t *testing.T) {
+		mockCheckResolver := graph.NewMockCheckResolver(mockController)
+		cmd := NewBatchCheckCommand(ds, mockCheckResolver, ts, WithBatchCheckMaxConcurrentChecks(1))
+		cmd.maxStreamedOutcomes = 2
+
+		// doc:0 is evicted by doc:2, so that its last check is evaluated again.
+		objects := []string{"doc:0", "doc:1", "doc:1", "doc:2", "doc:0"}
+		mockCheckResolver.EXPECT().ResolveCheck(gomock.Any(), gomock.Any()).
+			Times(4).
+			Return(&graph.ResolveCheckResponse{}, nil)
+
+		emitted := make(chan struct{}, len(objects))
+		next := streamOf(objects...)
+		received := 0
+		meta, err := cmd.ExecuteStreamed(context.Background(), &StreamedBatchCheckParams{
+			StoreID: ulid.Make().String(),
+			Next: func() (*openfgav1.BatchCheckItem, error) {
+				// Each check is received once the previous one is complete.
+				if received > 0 && received < len(objects) {
+					<-emitted
+				}
+				received++
+				return next()
+			},
+		}, func(*openfgav1.BatchCheckItem, *BatchCheckOutcome) error {
+			emitted <- struct{}{}
+			return nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, len(objects), meta.CheckCount)
+		require.Equal(t, 1, meta.DuplicateCheckCount)
+	})
+
+	t.Run("next_error_stops_the_stream", func(t *testing.T) {
+		mockCheckResolver := graph.NewMockCheckResolver(mockController)
+		mockCheckResolver.EXPECT().ResolveCheck(gomock.Any(), gomock.Any()).
+			AnyTimes().
+			Return(&graph.ResolveCheckResponse{}, nil)
+		cmd := NewBatchCheckCommand(ds, mockCheckResolver, ts)
+
+		next := streamOf("doc:1", "doc:2")
+		calls := 0
+		readErr := errors.New("read error")
+		_, err := cmd.ExecuteStreamed(context.Background(), &StreamedBatchCheckParams{
+			StoreID: ulid.Make().String(),
+			Next: func() (*openfgav1.BatchCheckItem, error) {
+				calls++
+				if calls == 2 {
+					return nil, readErr
+				}
+				return next()
+			},
+		}, func(*openfgav1.BatchCheckItem, *BatchCheckOutcome) error {
+			return nil
+		})
+		require.ErrorIs(t, err, readErr)
+	})
+
+	t.Run("emit_error_stops_the_stream", func(t *testing.T) {
+		mockCheckResolver := graph.NewMockCheckResolver(mockController)
+		mockCheckResolver.EXPECT().ResolveCheck(gomock.Any(), gomock.Any()).
+			AnyTimes().
+			DoAndReturn(func(_ context.Context, _ *graph.ResolveCheckRequest) (*graph.ResolveCheckResponse, error) {
+				return &graph.ResolveCheckResponse{}, nil
+			})
+		cmd := NewBatchCheckCommand(ds, mockCheckResolver, ts, WithBatchCheckMaxConcurrentChecks(1))
+
+		objects := make([]string, 100)
+		for i := range objects {
+			objects[i] = fmt.Sprintf("doc:%d", i)
+		}
+		emitErr := errors.New("emit error")
+		emitted := 0
+		_, err := cmd.ExecuteStreamed(context.Background(), &StreamedBatchCheckParams{
+			StoreID: ulid.Make().String(),
+			Next:    streamOf(objects...),
+		}, func(*openfgav1.BatchCheckItem, *BatchCheckOutcome) error {
+			emitted++
+			return emitErr
+		})
+		require.ErrorIs(t, err, emitErr)
+		require.Equal(t, 1, emitted)
+	})
+}
+
+func TestStreamedBatchCheckParamsValidate(t *testing.T) {
+	require.NoError(t, (&StreamedBatchCheckParams{StoreID: ulid.Make().String()}).Validate())
+	require.Error(t, (&StreamedBatchCheckParams{StoreID: "invalid"}).Validate())
+	require.ErrorContains(t, (&StreamedBatchCheckParams{StoreID: ulid.Make().String(), AuthorizationModelID: "invalid"}).Validate(), "authorization_model_id")
+}
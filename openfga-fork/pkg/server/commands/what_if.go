@@ -0,0 +1,321 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+
+	"github.com/openfga/openfga/internal/condition"
+	"github.com/openfga/openfga/internal/validation"
+	"github.com/openfga/openfga/pkg/server/commands/reverseexpand"
+	serverconfig "github.com/openfga/openfga/pkg/server/config"
+	serverErrors "github.com/openfga/openfga/pkg/server/errors"
+	"github.com/openfga/openfga/pkg/storage"
+	"github.com/openfga/openfga/pkg/storage/storagewrappers"
+	"github.com/openfga/openfga/pkg/tuple"
+	"github.com/openfga/openfga/pkg/typesystem"
+)
+
+// WhatIfRequest describes a write whose effect on the permissions of a store is previewed. Nothing is written.
+type WhatIfRequest struct {
+	StoreID              string
+	AuthorizationModelID string
+	// Writes are the tuples that would be written.
+	Writes []*openfgav1.TupleKey
+	// Deletes are the tuples that would be deleted.
+	Deletes []*openfgav1.TupleKeyWithoutCondition
+	// ObjectTypes restricts the analysis to the objects of these types. If empty, every type is analyzed.
+	ObjectTypes []string
+}
+
+// Validate checks that the request changes at least one tuple. The tuples themselves are validated against the
+// model on execution, like those of a Write.
+func (r *WhatIfRequest) Validate() error {
+	if err := validateStoreID(r.StoreID); err != nil {
+		return err
+	}
+	if len(r.Writes) == 0 && len(r.Deletes) == 0 {
+		return errors.New("at least one write or delete is required")
+	}
+	return nil
+}
+
+// WhatIfPermission is a (user, relation, object) permission.
+type WhatIfPermission struct {
+	User     string `json:"user"`
+	Relation string `json:"relation"`
+	Object   string `json:"object"`
+}
+
+// WhatIfResponse lists the permissions the write would add and remove, ordered by object, relation and user.
+type WhatIfResponse struct {
+	Added   []*WhatIfPermission `json:"added"`
+	Removed []*WhatIfPermission `json:"removed"`
+	// Truncated is true when the analysis stopped at the result limit or at the deadline, or when one of its
+	// ListUsers queries did, in which case the lists may not be complete.
+	Truncated bool `json:"truncated"`
+}
+
+// WhatIfQuery previews the permissions added and removed by a write. The written tuples are evaluated
+// as contextual tuples and the deleted tuples are hidden from the datastore. The users whose permissions
+// may change are derived from the written and deleted tuples; their objects are found by reverse-expanding
+// the model from the user over the tuples before and after the write, and every difference is confirmed
+// with Check.
+type WhatIfQuery struct {
+	datastore               storage.OpenFGADatastore
+	resolver                AccessReviewResolver
+	maxResults              uint32
+	deadline                time.Duration
+	resolveNodeLimit        uint32
+	resolveNodeBreadthLimit uint32
+}
+
+type WhatIfQueryOption func(*WhatIfQuery)
+
+// WithWhatIfMaxResults sets the maximum number of permissions returned. 0 means no limit.
+func WithWhatIfMaxResults(maxResults uint32) WhatIfQueryOption {
+	return func(q *WhatIfQuery) {
+		q.maxResults = maxResults
+	}
+}
+
+// WithWhatIfDeadline sets the time after which the analysis stops and returns the permissions found so far. 0 means no deadline.
+func WithWhatIfDeadline(deadline time.Duration) WhatIfQueryOption {
+	return func(q *WhatIfQuery) {
+		q.deadline = deadline
+	}
+}
+
+// WithWhatIfResolveNodeLimit sets the maximum depth of the reverse expansion of a user.
+func WithWhatIfResolveNodeLimit(limit uint32) WhatIfQueryOption {
+	return func(q *WhatIfQuery) {
+		q.resolveNodeLimit = limit
+	}
+}
+
+// WithWhatIfResolveNodeBreadthLimit sets the maximum number of concurrent branches of the reverse expansion of a user.
+func WithWhatIfResolveNodeBreadthLimit(limit uint32) WhatIfQueryOption {
+	return func(q *WhatIfQuery) {
+		q.resolveNodeBreadthLimit = limit
+	}
+}
+
+func NewWhatIfQuery(datastore storage.OpenFGADatastore, resolver AccessReviewResolver, opts ...WhatIfQueryOption) *WhatIfQuery {
+	q := &WhatIfQuery{
+		datastore:               datastore,
+		resolver:                resolver,
+		maxResults:              serverconfig.DefaultListObjectsMaxResults,
+		deadline:                serverconfig.DefaultListObjectsDeadline,
+		resolveNodeLimit:        serverconfig.DefaultResolveNodeLimit,
+		resolveNodeBreadthLimit: serverconfig.DefaultResolveNodeBreadthLimit,
+	}
+
+	for _, opt := range opts {
+		opt(q)
+	}
+	return q
+}
+
+// Execute computes the permissions added and removed by the write described by req.
+func (q *WhatIfQuery) Execute(ctx context.Context, typesys *typesystem.TypeSystem, req *WhatIfRequest) (*WhatIfResponse, error) {
+	diff, err := q.buildDiff(typesys, req)
+	if err != nil {
+		return nil, err
+	}
+
+	objectTypes, err := whatIfObjectTypes(typesys, req.ObjectTypes)
+	if err != nil {
+		return nil, err
+	}
+
+	if q.deadline > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, q.deadline)
+		defer cancel()
+	}
+
+	resp := &WhatIfResponse{Added: []*WhatIfPermission{}, Removed: []*WhatIfPermission{}}
+	for _, objectType := range objectTypes {
+		rows, err := diff.rows(ctx, objectType, sortedKeys(typesys.GetAllRelations()[objectType]))
+		if ctx.Err() != nil {
+			// The permissions of the types analyzed before the deadline are complete.
+			resp.Truncated = true
+			return resp, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		resp.Truncated = resp.Truncated || diff.truncated
+
+		for _, row := range rows {
+			if q.maxResults > 0 && uint32(len(resp.Added)+len(resp.Removed)) >= q.maxResults {
+				resp.Truncated = true
+				return resp, nil
+			}
+			permission := &WhatIfPermission{User: row.User, Relation: row.Relation, Object: row.Object}
+			if row.Change == AccessGained {
+				resp.Added = append(resp.Added, permission)
+			} else {
+				resp.Removed = append(resp.Removed, permission)
+			}
+		}
+	}
+	return resp, nil
+}
+
+// buildDiff validates the write against the model, the way the Write API does, and returns the states
+// of the tuples before and after it.
+func (q *WhatIfQuery) buildDiff(typesys *typesystem.TypeSystem, req *WhatIfRequest) (*permissionDiff, error) {
+	seen := make(map[string]struct{})
+	afterWrite := make(map[string]*openfgav1.TupleKey)
+	var changed []*openfgav1.TupleKey
+
+	for _, tk := range req.Deletes {
+		key := tuple.TupleKeyToString(tk)
+		if _, ok := seen[key]; ok {
+			return nil, serverErrors.DuplicateTupleInWrite(tk)
+		}
+		seen[key] = struct{}{}
+
+		deleted := tuple.TupleKeyWithoutConditionToTupleKey(tk)
+		if err := validation.ValidateUserObjectRelation(typesys, deleted); err != nil {
+			return nil, serverErrors.ValidationError(&tuple.InvalidTupleError{Cause: err, TupleKey: tk})
+		}
+		afterWrite[key] = nil
+		changed = append(changed, deleted)
+	}
+
+	for _, tk := range req.Writes {
+		key := tuple.TupleKeyToString(tk)
+		if _, ok := seen[key]; ok {
+			return nil, serverErrors.DuplicateTupleInWrite(tk)
+		}
+		seen[key] = struct{}{}
+
+		if err := validation.ValidateTupleForWrite(typesys, tk); err != nil {
+			return nil, serverErrors.ValidationError(err)
+		}
+		changed = append(changed, tk)
+	}
+
+	if len(seen) > q.datastore.MaxTuplesPerWrite() {
+		return nil, serverErrors.ExceededEntityLimit("write operations", q.datastore.MaxTuplesPerWrite())
+	}
+
+	return &permissionDiff{
+		resolver: q.resolver,
+		typesys:  typesys,
+		storeID:  req.StoreID,
+		from:     tupleState{reader: q.datastore},
+		to: tupleState{
+			reader:           storagewrappers.NewPointInTimeTupleReader(q.datastore, afterWrite),
+			contextualTuples: req.Writes,
+		},
+		changed: changed,
+		listObjects: func(ctx context.Context, state tupleState, objectType, relation, user string) ([]string, []string, error) {
+			return q.reverseExpand(ctx, typesys, req.StoreID, state, objectType, relation, user)
+		},
+	}, nil
+}
+
+// reverseExpand reverse-expands the model from user over the tuples of state, with its contextual tuples
+// overlaid, and returns the objects of objectType that user has relation with, and the candidates that
+// reverse expansion cannot decide alone. The tuples whose condition needs parameters grant nothing, since
+// the preview has no request context.
+func (q *WhatIfQuery) reverseExpand(ctx context.Context, typesys *typesystem.TypeSystem, storeID string, state tupleState, objectType, relation, user string) ([]string, []string, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	query := reverseexpand.NewReverseExpandQuery(
+		storagewrappers.NewCombinedTupleReader(state.reader, state.contextualTuples),
+		typesys,
+		reverseexpand.WithResolveNodeLimit(q.resolveNodeLimit),
+		reverseexpand.WithResolveNodeBreadthLimit(q.resolveNodeBreadthLimit),
+	)
+
+	results := make(chan *reverseexpand.ReverseExpandResult, 1)
+	done := make(chan error, 1)
+	go func() {
+		done <- query.Execute(ctx, &reverseexpand.ReverseExpandRequest{
+			StoreID:    storeID,
+			ObjectType: objectType,
+			Relation:   relation,
+			User:       reverseExpandUserRef(user),
+		}, results, reverseexpand.NewResolutionMetadata())
+	}()
+
+	var objects, candidates []string
+	collect := func(res *reverseexpand.ReverseExpandResult) {
+		if res.ResultStatus == reverseexpand.NoFurtherEvalStatus {
+			objects = append(objects, res.Object)
+		} else {
+			candidates = append(candidates, res.Object)
+		}
+	}
+
+	for {
+		select {
+		case res, ok := <-results:
+			if !ok {
+				return objects, candidates, <-done
+			}
+			collect(res)
+		case err := <-done:
+			if err != nil && !isConditionEvaluationError(err) {
+				return nil, nil, err
+			}
+			// The results sent before Execute returned are still buffered. Execute closes the channel
+			// when it succeeds, and leaves it open when it fails.
+			for {
+				select {
+				case res, ok := <-results:
+					if !ok {
+						return objects, candidates, nil
+					}
+					collect(res)
+				default:
+					return objects, candidates, nil
+				}
+			}
+		}
+	}
+}
+
+// isConditionEvaluationError reports whether err, or every error it joins, is a condition evaluation error.
+func isConditionEvaluationError(err error) bool {
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		for _, err := range joined.Unwrap() {
+			if !isConditionEvaluationError(err) {
+				return false
+			}
+		}
+		return true
+	}
+	return errors.Is(err, condition.ErrEvaluationFailed)
+}
+
+// whatIfObjectTypes returns, sorted, the requested object types or, if none is requested, every type with relations.
+func whatIfObjectTypes(typesys *typesystem.TypeSystem, requested []string) ([]string, error) {
+	if len(requested) == 0 {
+		var objectTypes []string
+		for objectType, relations := range typesys.GetAllRelations() {
+			if len(relations) > 0 {
+				objectTypes = append(objectTypes, objectType)
+			}
+		}
+		slices.Sort(objectTypes)
+		return objectTypes, nil
+	}
+
+	for _, objectType := range requested {
+		if _, ok := typesys.GetTypeDefinition(objectType); !ok {
+			return nil, serverErrors.TypeNotFound(objectType)
+		}
+	}
+	objectTypes := slices.Clone(requested)
+	slices.Sort(objectTypes)
+	return slices.Compact(objectTypes), nil
+}
@@ -0,0 +1,145 @@
+package commands
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+	"github.com/stretchr/testify/require"
+
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+	parser "github.com/openfga/language/pkg/go/transformer"
+
+	"github.com/openfga/openfga/pkg/storage"
+	"github.com/openfga/openfga/pkg/storage/memory"
+	"github.com/openfga/openfga/pkg/tuple"
+	"github.com/openfga/openfga/pkg/typesystem"
+)
+
+func TestWhatIfQuery(t *testing.T) {
+	ctx := context.Background()
+	storeID := ulid.Make().String()
+
+	ds := memory.New()
+	t.Cleanup(ds.Close)
+
+	model := parser.MustTransformDSLToProto(`
+		model
+			schema 1.1
+		type user
+		type folder
+			relations
+				define viewer: [user]
+		type document
+			relations
+				define owner: [user]
+				define viewer: [user, user with never]
+		condition never(x: int) {
+			x < 0
+		}`)
+	typesys, err := typesystem.New(model)
+	require.NoError(t, err)
+
+	require.NoError(t, ds.Write(ctx, storeID, nil, []*openfgav1.TupleKey{
+		tuple.NewTupleKey("document:1", "viewer", "user:anne"),
+		tuple.NewTupleKey("document:2", "viewer", "user:bob"),
+		tuple.NewTupleKey("folder:1", "viewer", "user:carl"),
+	}))
+
+	whatIf := func(req *WhatIfRequest, opts ...WhatIfQueryOption) (*WhatIfResponse, error) {
+		req.StoreID = storeID
+		return NewWhatIfQuery(ds, directResolver{}, opts...).Execute(ctx, typesys, req)
+	}
+
+	t.Run("writes_and_deletes", func(t *testing.T) {
+		resp, err := whatIf(&WhatIfRequest{
+			Writes: []*openfgav1.TupleKey{
+				tuple.NewTupleKey("document:1", "owner", "user:anne"),
+				tuple.NewTupleKey("document:2", "viewer", "user:erin"),
+				// Writing a tuple that exists changes nothing.
+				tuple.NewTupleKey("folder:1", "viewer", "user:carl"),
+				// A tuple whose condition never holds grants nothing.
+				tuple.NewTupleKeyWithCondition("document:3", "viewer", "user:dave", "never", nil),
+			},
+			Deletes: []*openfgav1.TupleKeyWithoutCondition{
+				{Object: "document:2", Relation: "viewer", User: "user:bob"},
+				// Deleting a tuple that does not exist changes nothing.
+				{Object: "document:1", Relation: "viewer", User: "user:bob"},
+			},
+		})
+		require.NoError(t, err)
+		require.Equal(t, &WhatIfResponse{
+			Added: []*WhatIfPermission{
+				{User: "user:anne", Relation: "owner", Object: "document:1"},
+				{User: "user:erin", Relation: "viewer", Object: "document:2"},
+			},
+			Removed: []*WhatIfPermission{
+				{User: "user:bob", Relation: "viewer", Object: "document:2"},
+			},
+		}, resp)
+
+		// Nothing was written.
+		_, err = ds.ReadUserTuple(ctx, storeID, tuple.NewTupleKey("document:2", "viewer", "user:bob"), storage.ReadUserTupleOptions{})
+		require.NoError(t, err)
+	})
+
+	t.Run("object_types", func(t *testing.T) {
+		resp, err := whatIf(&WhatIfRequest{
+			Writes: []*openfgav1.TupleKey{
+				tuple.NewTupleKey("document:1", "owner", "user:anne"),
+				tuple.NewTupleKey("folder:2", "viewer", "user:anne"),
+			},
+			ObjectTypes: []string{"folder"},
+		})
+		require.NoError(t, err)
+		require.Equal(t, []*WhatIfPermission{{User: "user:anne", Relation: "viewer", Object: "folder:2"}}, resp.Added)
+		require.Empty(t, resp.Removed)
+	})
+
+	t.Run("max_results", func(t *testing.T) {
+		resp, err := whatIf(&WhatIfRequest{
+			Writes: []*openfgav1.TupleKey{
+				tuple.NewTupleKey("document:1", "owner", "user:anne"),
+				tuple.NewTupleKey("document:2", "owner", "user:anne"),
+			},
+		}, WithWhatIfMaxResults(1))
+		require.NoError(t, err)
+		require.True(t, resp.Truncated)
+		require.Len(t, resp.Added, 1)
+	})
+
+	t.Run("deadline", func(t *testing.T) {
+		resp, err := whatIf(&WhatIfRequest{
+			Writes: []*openfgav1.TupleKey{tuple.NewTupleKey("document:1", "owner", "user:anne")},
+		}, WithWhatIfDeadline(time.Nanosecond))
+		require.NoError(t, err)
+		require.True(t, resp.Truncated)
+		require.Empty(t, resp.Added)
+	})
+
+	t.Run("invalid_tuple", func(t *testing.T) {
+		_, err := whatIf(&WhatIfRequest{Writes: []*openfgav1.TupleKey{tuple.NewTupleKey("document:1", "editor", "user:anne")}})
+		require.ErrorContains(t, err, "relation 'document#editor' not found")
+
+		_, err = whatIf(&WhatIfRequest{ObjectTypes: []string{"report"}, Writes: []*openfgav1.TupleKey{tuple.NewTupleKey("document:1", "owner", "user:anne")}})
+		require.ErrorContains(t, err, "type 'report' not found")
+	})
+
+	t.Run("duplicate_tuple", func(t *testing.T) {
+		_, err := whatIf(&WhatIfRequest{
+			Writes:  []*openfgav1.TupleKey{tuple.NewTupleKey("document:1", "owner", "user:anne")},
+			Deletes: []*openfgav1.TupleKeyWithoutCondition{{Object: "document:1", Relation: "owner", User: "user:anne"}},
+		})
+		require.ErrorContains(t, err, "duplicate tuple in write")
+	})
+}
+
+func TestWhatIfRequestValidate(t *testing.T) {
+	storeID := ulid.Make().String()
+	writes := []*openfgav1.TupleKey{tuple.NewTupleKey("document:1", "owner", "user:anne")}
+
+	require.NoError(t, (&WhatIfRequest{StoreID: storeID, Writes: writes}).Validate())
+	require.ErrorContains(t, (&WhatIfRequest{StoreID: "abc", Writes: writes}).Validate(), "invalid store_id")
+	require.ErrorContains(t, (&WhatIfRequest{StoreID: storeID}).Validate(), "at least one write or delete is required")
+}
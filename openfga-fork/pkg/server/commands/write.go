@@ -79,6 +79,19 @@ func parseOptionOnMissing(wr *openfgav1.WriteRequestDeletes) (storage.OnMissingD
 
 // Execute deletes and writes the specified tuples. Deletes are applied first, then writes.
 func (c *WriteCommand) Execute(ctx context.Context, req *openfgav1.WriteRequest) (*openfgav1.WriteResponse, error) {
+	return c.execute(ctx, req)
+}
+
+// ExecuteWithPreconditions is like Execute, but the tuples are only deleted and written if the preconditions
+// of the request hold. Otherwise, it returns a FailedPrecondition error and changes nothing.
+func (c *WriteCommand) ExecuteWithPreconditions(ctx context.Context, req *WriteWithPreconditionsRequest) (*openfgav1.WriteResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, serverErrors.ValidationError(err)
+	}
+	return c.execute(ctx, req.WriteRequest, storage.WithPreconditions(req.Preconditions()))
+}
+
+func (c *WriteCommand) execute(ctx context.Context, req *openfgav1.WriteRequest, opts ...storage.TupleWriteOption) (*openfgav1.WriteResponse, error) {
 	if err := c.validateWriteRequest(ctx, req); err != nil {
 		return nil, err
 	}
@@ -98,10 +111,15 @@ func (c *WriteCommand) Execute(ctx context.Context, req *openfgav1.WriteRequest)
 		req.GetStoreId(),
 		req.GetDeletes().GetTupleKeys(),
 		req.GetWrites().GetTupleKeys(),
-		storage.WithOnMissingDelete(onEmptyDelete),
-		storage.WithOnDuplicateInsert(onDuplicateInsert),
+		append([]storage.TupleWriteOption{
+			storage.WithOnMissingDelete(onEmptyDelete),
+			storage.WithOnDuplicateInsert(onDuplicateInsert),
+		}, opts...)...,
 	)
 	if err != nil {
+		if errors.Is(err, storage.ErrWritePreconditionFailed) {
+			return nil, serverErrors.WritePreconditionFailed(err)
+		}
 		if errors.Is(err, storage.ErrTransactionalWriteFailed) {
 			return nil, status.Error(codes.Aborted, err.Error())
 		}
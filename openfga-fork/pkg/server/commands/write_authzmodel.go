@@ -23,6 +23,7 @@ type WriteAuthorizationModelCommand struct {
 	backend                          storage.TypeDefinitionWriteBackend
 	logger                           logger.Logger
 	maxAuthorizationModelSizeInBytes int
+	typesystemOpts                   []typesystem.Option
 }
 
 type WriteAuthModelOption func(*WriteAuthorizationModelCommand)
@@ -39,6 +40,13 @@ func WithWriteAuthModelMaxSizeInBytes(size int) WriteAuthModelOption {
 	}
 }
 
+// WithWriteAuthModelTypesystemOptions sets the options of the typesystem that validates the model.
+func WithWriteAuthModelTypesystemOptions(opts ...typesystem.Option) WriteAuthModelOption {
+	return func(m *WriteAuthorizationModelCommand) {
+		m.typesystemOpts = opts
+	}
+}
+
 func NewWriteAuthorizationModelCommand(backend storage.TypeDefinitionWriteBackend, opts ...WriteAuthModelOption) *WriteAuthorizationModelCommand {
 	model := &WriteAuthorizationModelCommand{
 		backend:                          backend,
@@ -81,7 +89,7 @@ func (w *WriteAuthorizationModelCommand) Execute(ctx context.Context, req *openf
 		)
 	}
 
-	_, err := typesystem.NewAndValidate(ctx, model)
+	_, err := typesystem.NewAndValidate(ctx, model, w.typesystemOpts...)
 	if err != nil {
 		return nil, serverErrors.InvalidAuthorizationModelInput(err)
 	}
@@ -0,0 +1,85 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/oklog/ulid/v2"
+
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+
+	"github.com/openfga/openfga/pkg/storage"
+	tupleUtils "github.com/openfga/openfga/pkg/tuple"
+)
+
+const maxWritePreconditionTuples = 100
+
+// WriteWithPreconditionsRequest is a Write request that is only applied if its preconditions hold, so that
+// concurrent writers cannot overwrite each other's changes without noticing.
+type WriteWithPreconditionsRequest struct {
+	*openfgav1.WriteRequest
+	// TuplesExist must all exist when the write is applied, whatever their condition.
+	TuplesExist []*openfgav1.TupleKeyWithoutCondition
+	// TuplesNotExist must not exist when the write is applied, whatever their condition.
+	TuplesNotExist []*openfgav1.TupleKeyWithoutCondition
+	// LatestChangeULID, if not empty, must be the ULID of the latest change of the store when the write is
+	// applied, as returned by GetStoreStats.
+	LatestChangeULID string
+}
+
+// Validate checks the embedded Write request and the preconditions: at least one is required, their tuples are
+// bounded in number, no tuple is required both to exist and not to exist, and the latest change is a ULID.
+func (r *WriteWithPreconditionsRequest) Validate() error {
+	if r.WriteRequest == nil {
+		return errors.New("the Write request is required")
+	}
+	if err := r.WriteRequest.Validate(); err != nil {
+		return err
+	}
+
+	if len(r.TuplesExist) == 0 && len(r.TuplesNotExist) == 0 && r.LatestChangeULID == "" {
+		return errors.New("at least one precondition is required")
+	}
+	if len(r.TuplesExist)+len(r.TuplesNotExist) > maxWritePreconditionTuples {
+		return fmt.Errorf("the preconditions must have at most %d tuples", maxWritePreconditionTuples)
+	}
+
+	mustExist := make(map[string]struct{}, len(r.TuplesExist))
+	for _, tk := range r.TuplesExist {
+		if err := validatePreconditionTuple(tk); err != nil {
+			return err
+		}
+		mustExist[tupleUtils.TupleKeyToString(tk)] = struct{}{}
+	}
+	for _, tk := range r.TuplesNotExist {
+		if err := validatePreconditionTuple(tk); err != nil {
+			return err
+		}
+		if _, ok := mustExist[tupleUtils.TupleKeyToString(tk)]; ok {
+			return fmt.Errorf("the tuple '%s' cannot both exist and not exist", tupleUtils.TupleKeyToString(tk))
+		}
+	}
+
+	if r.LatestChangeULID != "" {
+		if _, err := ulid.ParseStrict(r.LatestChangeULID); err != nil {
+			return fmt.Errorf("invalid latest_change_ulid '%s'", r.LatestChangeULID)
+		}
+	}
+	return nil
+}
+
+func validatePreconditionTuple(tk *openfgav1.TupleKeyWithoutCondition) error {
+	if !tupleUtils.IsValidObject(tk.GetObject()) || !tupleUtils.IsValidRelation(tk.GetRelation()) || !tupleUtils.IsValidUser(tk.GetUser()) {
+		return fmt.Errorf("invalid precondition tuple '%s'", tupleUtils.TupleKeyToString(tk))
+	}
+	return nil
+}
+
+// Preconditions returns the preconditions of the request for [storage.WithPreconditions].
+func (r *WriteWithPreconditionsRequest) Preconditions() storage.WritePreconditions {
+	return storage.WritePreconditions{
+		TuplesExist:      r.TuplesExist,
+		TuplesNotExist:   r.TuplesNotExist,
+		LatestChangeULID: r.LatestChangeULID,
+	}
+}
@@ -0,0 +1,113 @@
+package commands
+
+import (
+	"context"
+	"testing"
+
+	"github.com/oklog/ulid/v2"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+
+	"github.com/openfga/openfga/pkg/storage"
+	"github.com/openfga/openfga/pkg/storage/memory"
+	"github.com/openfga/openfga/pkg/testutils"
+	"github.com/openfga/openfga/pkg/tuple"
+)
+
+func TestWriteCommandExecuteWithPreconditions(t *testing.T) {
+	t.Cleanup(func() {
+		goleak.VerifyNone(t)
+	})
+
+	ctx := context.Background()
+	ds := memory.New()
+	t.Cleanup(ds.Close)
+	storeID := ulid.Make().String()
+
+	model := testutils.MustTransformDSLToProtoWithID(`
+		model
+			schema 1.1
+		type user
+		type document
+			relations
+				define viewer: [user]`)
+	require.NoError(t, ds.WriteAuthorizationModel(ctx, storeID, model))
+
+	anne := tuple.NewTupleKey("document:1", "viewer", "user:anne")
+	bob := tuple.NewTupleKey("document:1", "viewer", "user:bob")
+	require.NoError(t, ds.Write(ctx, storeID, nil, []*openfgav1.TupleKey{anne}))
+
+	writeReq := func(tk *openfgav1.TupleKey) *openfgav1.WriteRequest {
+		return &openfgav1.WriteRequest{
+			StoreId:              storeID,
+			AuthorizationModelId: model.GetId(),
+			Writes:               &openfgav1.WriteRequestWrites{TupleKeys: []*openfgav1.TupleKey{tk}},
+		}
+	}
+
+	t.Run("failed_precondition", func(t *testing.T) {
+		_, err := NewWriteCommand(ds).ExecuteWithPreconditions(ctx, &WriteWithPreconditionsRequest{
+			WriteRequest: writeReq(bob),
+			TuplesExist:  []*openfgav1.TupleKeyWithoutCondition{tuple.TupleKeyToTupleKeyWithoutCondition(bob)},
+		})
+		require.Equal(t, codes.FailedPrecondition, status.Code(err))
+		require.ErrorContains(t, err, "tuple does not exist")
+
+		_, err = ds.ReadUserTuple(ctx, storeID, bob, storage.ReadUserTupleOptions{})
+		require.ErrorIs(t, err, storage.ErrNotFound)
+	})
+
+	t.Run("preconditions_hold", func(t *testing.T) {
+		_, err := NewWriteCommand(ds).ExecuteWithPreconditions(ctx, &WriteWithPreconditionsRequest{
+			WriteRequest:   writeReq(bob),
+			TuplesExist:    []*openfgav1.TupleKeyWithoutCondition{tuple.TupleKeyToTupleKeyWithoutCondition(anne)},
+			TuplesNotExist: []*openfgav1.TupleKeyWithoutCondition{tuple.TupleKeyToTupleKeyWithoutCondition(bob)},
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("invalid_request", func(t *testing.T) {
+		_, err := NewWriteCommand(ds).ExecuteWithPreconditions(ctx, &WriteWithPreconditionsRequest{
+			WriteRequest:     writeReq(tuple.NewTupleKey("document:2", "viewer", "user:anne")),
+			LatestChangeULID: "latest",
+		})
+		require.ErrorContains(t, err, "invalid latest_change_ulid")
+	})
+}
+
+func TestWriteWithPreconditionsRequestValidate(t *testing.T) {
+	writeReq := &openfgav1.WriteRequest{
+		StoreId: ulid.Make().String(),
+		Writes: &openfgav1.WriteRequestWrites{TupleKeys: []*openfgav1.TupleKey{
+			tuple.NewTupleKey("document:1", "viewer", "user:anne"),
+		}},
+	}
+	tk := &openfgav1.TupleKeyWithoutCondition{Object: "document:1", Relation: "viewer", User: "user:bob"}
+
+	require.NoError(t, (&WriteWithPreconditionsRequest{WriteRequest: writeReq, TuplesExist: []*openfgav1.TupleKeyWithoutCondition{tk}}).Validate())
+	require.NoError(t, (&WriteWithPreconditionsRequest{WriteRequest: writeReq, LatestChangeULID: ulid.Make().String()}).Validate())
+
+	require.ErrorContains(t, (&WriteWithPreconditionsRequest{TuplesExist: []*openfgav1.TupleKeyWithoutCondition{tk}}).Validate(), "the Write request is required")
+	require.Error(t, (&WriteWithPreconditionsRequest{WriteRequest: &openfgav1.WriteRequest{StoreId: "invalid"}, TuplesExist: []*openfgav1.TupleKeyWithoutCondition{tk}}).Validate())
+	require.ErrorContains(t, (&WriteWithPreconditionsRequest{WriteRequest: writeReq}).Validate(), "at least one precondition is required")
+	require.ErrorContains(t, (&WriteWithPreconditionsRequest{
+		WriteRequest:   writeReq,
+		TuplesExist:    []*openfgav1.TupleKeyWithoutCondition{tk},
+		TuplesNotExist: []*openfgav1.TupleKeyWithoutCondition{tk},
+	}).Validate(), "cannot both exist and not exist")
+	require.ErrorContains(t, (&WriteWithPreconditionsRequest{
+		WriteRequest: writeReq,
+		TuplesExist:  []*openfgav1.TupleKeyWithoutCondition{{Object: "document", Relation: "viewer", User: "user:bob"}},
+	}).Validate(), "invalid precondition tuple")
+	require.ErrorContains(t, (&WriteWithPreconditionsRequest{WriteRequest: writeReq, LatestChangeULID: "latest"}).Validate(), "invalid latest_change_ulid")
+
+	tooMany := make([]*openfgav1.TupleKeyWithoutCondition, maxWritePreconditionTuples+1)
+	for i := range tooMany {
+		tooMany[i] = tk
+	}
+	require.ErrorContains(t, (&WriteWithPreconditionsRequest{WriteRequest: writeReq, TuplesExist: tooMany}).Validate(), "at most 100 tuples")
+}
@@ -6,6 +6,8 @@ import (
 
 type CacheSettings struct {
 	CheckCacheLimit                    uint32
+	CheckCacheBackend                  string
+	CheckCacheRedis                    CheckCacheRedisConfig
 	CacheControllerEnabled             bool
 	CacheControllerTTL                 time.Duration
 	CheckQueryCacheEnabled             bool
@@ -24,7 +26,12 @@ type CacheSettings struct {
 
 func NewDefaultCacheSettings() CacheSettings {
 	return CacheSettings{
-		CheckCacheLimit:                    DefaultCheckCacheLimit,
+		CheckCacheLimit:   DefaultCheckCacheLimit,
+		CheckCacheBackend: DefaultCheckCacheBackend,
+		CheckCacheRedis: CheckCacheRedisConfig{
+			LocalTTL: DefaultCheckCacheRedisLocalTTL,
+			Timeout:  DefaultCheckCacheRedisTimeout,
+		},
 		CacheControllerEnabled:             DefaultCacheControllerEnabled,
 		CacheControllerTTL:                 DefaultCacheControllerTTL,
 		CheckQueryCacheEnabled:             DefaultCheckQueryCacheEnabled,
@@ -6,15 +6,20 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"slices"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/spf13/viper"
+
+	"github.com/openfga/openfga/internal/utils/apimethod"
 )
 
 const (
 	DefaultMaxRPCMessageSizeInBytes         = 512 * 1_204 // 512 KB
 	DefaultMaxTuplesPerWrite                = 100
+	MaxTuplesPerWriteCeiling                = 10_000
 	DefaultMaxTypesPerAuthorizationModel    = 100
 	DefaultMaxAuthorizationModelSizeInBytes = 256 * 1_024
 	DefaultMaxAuthorizationModelCacheSize   = 100000
@@ -28,11 +33,83 @@ const (
 	DefaultListUsersDeadline                = 3 * time.Second
 	DefaultListUsersMaxResults              = 1000
 	DefaultMaxConcurrentReadsForListUsers   = math.MaxUint32
+	DefaultPermissionMatrixMaxObjects       = 10000
+	DefaultAccessReviewMaxChanges           = 10000
+	DefaultStoreStatsCacheTTL               = 0
+
+	DefaultConditionContextRequestTimeGranularity = time.Second
 
 	DefaultWriteContextByteLimit = 32 * 1_024 // 32KB
 
 	DefaultCheckCacheLimit = 10000
 
+	CheckCacheBackendMemory = "memory"
+	CheckCacheBackendRedis  = "redis"
+
+	DefaultCheckCacheBackend       = CheckCacheBackendMemory
+	DefaultCheckCacheRedisLocalTTL = 5 * time.Second
+	DefaultCheckCacheRedisTimeout  = 100 * time.Millisecond
+	// DefaultCheckCacheRedisKeyPrefix is the prefix of the keys of the check cache in Redis.
+	DefaultCheckCacheRedisKeyPrefix = "openfga:"
+
+	RateLimitBackendMemory = "memory"
+	RateLimitBackendRedis  = "redis"
+
+	DefaultRateLimitEnabled           = false
+	DefaultRateLimitRequestsPerSecond = 100
+	DefaultRateLimitBurst             = 200
+	DefaultRateLimitBackend           = RateLimitBackendMemory
+	DefaultRateLimitRedisTimeout      = 100 * time.Millisecond
+
+	MetricsOTLPProtocolGRPC = "grpc"
+	MetricsOTLPProtocolHTTP = "http"
+
+	DefaultMetricsOTLPEnabled        = false
+	DefaultMetricsOTLPEndpoint       = "0.0.0.0:4317"
+	DefaultMetricsOTLPProtocol       = MetricsOTLPProtocolGRPC
+	DefaultMetricsOTLPExportInterval = time.Minute
+
+	DefaultSlowLogEnabled   = false
+	DefaultSlowLogThreshold = time.Second
+	DefaultSlowLogCapacity  = 1000
+
+	DefaultDatastoreAdaptiveConcurrencyEnabled          = false
+	DefaultDatastoreAdaptiveConcurrencyMinLimit         = 10
+	DefaultDatastoreAdaptiveConcurrencyMaxLimit         = 200
+	DefaultDatastoreAdaptiveConcurrencyLatencyThreshold = 100 * time.Millisecond
+	DefaultDatastoreAdaptiveConcurrencyBackoffRatio     = 0.9
+
+	DefaultLoadSheddingEnabled                   = false
+	DefaultLoadSheddingDefaultClass              = "default"
+	DefaultLoadSheddingInteractiveMaxInFlight    = 1000
+	DefaultLoadSheddingDefaultMaxInFlight        = 800
+	DefaultLoadSheddingBackgroundMaxInFlight     = 500
+	DefaultLoadSheddingDatastoreLatencyThreshold = 0 // 0 means the datastore latency is not considered
+	DefaultLoadSheddingQueueTimeout              = 500 * time.Millisecond
+	DefaultLoadSheddingQueueFrequency            = time.Millisecond
+	DefaultLoadSheddingRetryAfter                = time.Second
+
+	DefaultAdminEnabled = false
+	DefaultAdminAddr    = ":3002"
+
+	DefaultMetricsStoreLabelsEnabled   = false
+	DefaultMetricsStoreLabelsMaxStores = 50
+	DefaultMetricsModelLabelsEnabled   = false
+	DefaultMetricsModelLabelsMaxModels = 100
+
+	DefaultMetricsStoreLabelsRankingInterval = time.Minute
+
+	DecisionLogSinkStdout = "stdout"
+	DecisionLogSinkFile   = "file"
+	DecisionLogSinkOTLP   = "otlp"
+
+	DefaultDecisionLogEnabled        = false
+	DefaultDecisionLogSink           = DecisionLogSinkStdout
+	DefaultDecisionLogFileMaxSizeMB  = 100
+	DefaultDecisionLogFileMaxBackups = 5
+	DefaultDecisionLogOTLPEndpoint   = "0.0.0.0:4317"
+	DefaultDecisionLogSampleRate     = 1.0
+
 	DefaultCacheControllerEnabled = false
 	DefaultCacheControllerTTL     = 10 * time.Second
 
@@ -103,11 +180,50 @@ const (
 	DefaultPlannerCleanupInterval   = 0
 )
 
+// LoadSheddingClasses are the priority classes of the load shedder, from the highest to the lowest.
+var LoadSheddingClasses = []string{"interactive", "default", "background"}
+
+// SlowLogMethods are the methods whose slow requests are recorded by the slow request log.
+var SlowLogMethods = []string{"Check", "ListObjects", "StreamedListObjects"}
+
 type DatastoreMetricsConfig struct {
 	// Enabled enables export of the Datastore metrics.
 	Enabled bool
 }
 
+// DatastoreAdaptiveConcurrencyConfig defines the limit on the concurrent datastore reads of all the Check,
+// BatchCheck, ListObjects and ListUsers requests that adapts to the latency and errors of the datastore.
+type DatastoreAdaptiveConcurrencyConfig struct {
+	Enabled bool
+	// MinLimit is the floor of the limit.
+	MinLimit int
+	// MaxLimit is the ceiling of the limit, and its initial value.
+	MaxLimit int
+	// LatencyThreshold is the read latency over which the limit decreases.
+	LatencyThreshold time.Duration
+	// BackoffRatio is the ratio, between 0 and 1, the limit is multiplied by when it decreases.
+	BackoffRatio float64
+}
+
+func (c DatastoreAdaptiveConcurrencyConfig) verify() error {
+	if !c.Enabled {
+		return nil
+	}
+	if c.MinLimit <= 0 {
+		return errors.New("'datastore.adaptiveConcurrency.minLimit' must be greater than zero")
+	}
+	if c.MaxLimit < c.MinLimit {
+		return errors.New("'datastore.adaptiveConcurrency.maxLimit' must be greater than or equal to 'datastore.adaptiveConcurrency.minLimit'")
+	}
+	if c.LatencyThreshold <= 0 {
+		return errors.New("'datastore.adaptiveConcurrency.latencyThreshold' must be greater than zero")
+	}
+	if c.BackoffRatio <= 0 || c.BackoffRatio >= 1 {
+		return errors.New("'datastore.adaptiveConcurrency.backoffRatio' must be between 0 and 1, exclusive")
+	}
+	return nil
+}
+
 // DatastoreConfig defines OpenFGA server configurations for datastore specific settings.
 type DatastoreConfig struct {
 	// Engine is the datastore engine to use (e.g. 'memory', 'postgres', 'mysql', 'sqlite')
@@ -137,6 +253,9 @@ type DatastoreConfig struct {
 
 	// Metrics is configuration for the Datastore metrics.
 	Metrics DatastoreMetricsConfig
+
+	// AdaptiveConcurrency bounds the concurrent reads sent to the datastore based on its latency and errors.
+	AdaptiveConcurrency DatastoreAdaptiveConcurrencyConfig
 }
 
 // GRPCConfig defines OpenFGA server configurations for grpc server specific settings.
@@ -226,6 +345,21 @@ type PlaygroundConfig struct {
 	Port    int
 }
 
+// AdminConfig defines the HTTP server of the admin endpoints, e.g. '/slowlog'.
+type AdminConfig struct {
+	Enabled bool
+	Addr    string
+	// Keys are the preshared keys that authenticate the admin requests, as bearer tokens.
+	Keys []string `json:"-"` // private field, won't be logged
+}
+
+func (c AdminConfig) verify() error {
+	if c.Enabled && len(c.Keys) == 0 {
+		return errors.New("'admin.keys' is required when 'admin.enabled' is set")
+	}
+	return nil
+}
+
 // ProfilerConfig defines server configurations specific to pprof profiling.
 type ProfilerConfig struct {
 	Enabled bool
@@ -237,6 +371,68 @@ type MetricConfig struct {
 	Enabled             bool
 	Addr                string
 	EnableRPCHistograms bool
+	OTLP                OTLPMetricConfig `mapstructure:"otlp"`
+	StoreLabels         StoreLabelsConfig
+}
+
+// OTLPMetricConfig defines the OTLP collector the metrics are pushed to, independently of the Prometheus
+// '/metrics' endpoint. The resource attributes of the metrics include the service name of the traces.
+type OTLPMetricConfig struct {
+	Enabled bool
+	// Endpoint is the host:port of the collector.
+	Endpoint string
+	// Protocol is "grpc" or "http".
+	Protocol string
+	// ExportInterval is how often the metrics are pushed.
+	ExportInterval time.Duration
+	TLS            OTLPTraceTLSConfig
+}
+
+func (c OTLPMetricConfig) verify() error {
+	if !c.Enabled {
+		return nil
+	}
+	if c.Endpoint == "" {
+		return errors.New("'metrics.otlp.endpoint' is required when 'metrics.otlp.enabled' is set")
+	}
+	if c.Protocol != MetricsOTLPProtocolGRPC && c.Protocol != MetricsOTLPProtocolHTTP {
+		return fmt.Errorf("'metrics.otlp.protocol' must be '%s' or '%s'", MetricsOTLPProtocolGRPC, MetricsOTLPProtocolHTTP)
+	}
+	if c.ExportInterval <= 0 {
+		return errors.New("'metrics.otlp.exportInterval' must be greater than zero")
+	}
+	return nil
+}
+
+// StoreLabelsConfig defines the store_id and model_id labels of the request, datastore and cache metrics.
+// The allowed stores are always labeled with their ID. The MaxStores other stores with the most requests are
+// labeled with their ID too, and the rest are labeled 'other'. Model IDs are bounded by MaxModels the same way.
+type StoreLabelsConfig struct {
+	Enabled       bool
+	AllowedStores []string
+	MaxStores     int
+	// ModelLabelsEnabled labels the request metrics with the authorization model ID too.
+	ModelLabelsEnabled bool
+	MaxModels          int
+	// RankingInterval is how often the stores and models labeled with their ID are ranked again by their
+	// recent requests.
+	RankingInterval time.Duration
+}
+
+func (c StoreLabelsConfig) verify() error {
+	if !c.Enabled {
+		return nil
+	}
+	if c.MaxStores < 0 {
+		return errors.New("'metrics.storeLabels.maxStores' must be non-negative")
+	}
+	if c.ModelLabelsEnabled && c.MaxModels <= 0 {
+		return errors.New("'metrics.storeLabels.maxModels' must be greater than zero when 'metrics.storeLabels.modelLabelsEnabled' is set")
+	}
+	if c.RankingInterval <= 0 {
+		return errors.New("'metrics.storeLabels.rankingInterval' must be greater than zero")
+	}
+	return nil
 }
 
 // CheckQueryCache defines configuration for caching when resolving check.
@@ -248,6 +444,326 @@ type CheckQueryCache struct {
 // CheckCacheConfig defines configuration for a cache that is shared across Check requests.
 type CheckCacheConfig struct {
 	Limit uint32
+	// Backend is where the cache is stored: "memory" keeps it in each server process, "redis" shares it
+	// across the replicas of the server through a Redis-compatible server.
+	Backend string
+	Redis   CheckCacheRedisConfig
+}
+
+// CheckCacheRedisConfig defines the Redis-compatible server that stores the check cache when its backend
+// is "redis". Recently used entries are also kept in a local cache, limited by CheckCacheConfig.Limit,
+// for at most LocalTTL.
+type CheckCacheRedisConfig struct {
+	Addr     string
+	Password string `json:"-"` // private field, won't be logged
+	DB       int
+	LocalTTL time.Duration
+	Timeout  time.Duration
+}
+
+// RateLimitConfig defines the token buckets that limit the rate of the requests of each client, identified
+// by the client ID or subject of its credentials, to each store and API method.
+type RateLimitConfig struct {
+	Enabled bool
+	// RequestsPerSecond and Burst are the limit of the API methods without their own limit in MethodLimits.
+	RequestsPerSecond float64
+	Burst             int
+	// MethodLimits overrides the limit of some API methods, as 'method=requestsPerSecond:burst' items,
+	// e.g. 'Check=500:1000'. A rate of 0 disables the limit of the method.
+	MethodLimits []string
+	// Backend is where the token buckets are stored: "memory" limits the requests served by each server
+	// process, "redis" enforces the limits across the replicas of the server through a Redis-compatible server.
+	Backend string
+	Redis   RateLimitRedisConfig
+}
+
+// RateLimitRedisConfig defines the Redis-compatible server that stores the token buckets when the rate limit
+// backend is "redis".
+type RateLimitRedisConfig struct {
+	Addr     string
+	Password string `json:"-"` // private field, won't be logged
+	DB       int
+	Timeout  time.Duration
+}
+
+// MethodRateLimit is the rate limit of an API method.
+type MethodRateLimit struct {
+	RequestsPerSecond float64
+	Burst             int
+}
+
+// ParseMethodLimits returns the limit of each API method in MethodLimits. It returns an error if a method is not
+// an API method, since its limit would never apply.
+func (c RateLimitConfig) ParseMethodLimits() (map[string]MethodRateLimit, error) {
+	limits := make(map[string]MethodRateLimit, len(c.MethodLimits))
+	for _, item := range c.MethodLimits {
+		method, limit, ok := strings.Cut(item, "=")
+		rate, burst, ok2 := strings.Cut(limit, ":")
+		if !ok || !ok2 || method == "" {
+			return nil, fmt.Errorf("'rateLimit.methodLimits' item '%s' must be a 'method=requestsPerSecond:burst' item", item)
+		}
+		if _, ok := apimethod.Parse(method); !ok {
+			return nil, fmt.Errorf("'rateLimit.methodLimits' item '%s' has an unknown API method '%s'", item, method)
+		}
+
+		requestsPerSecond, err := strconv.ParseFloat(rate, 64)
+		if err != nil || requestsPerSecond < 0 {
+			return nil, fmt.Errorf("'rateLimit.methodLimits' item '%s' must have a rate greater than or equal to zero", item)
+		}
+
+		burstSize, err := strconv.Atoi(burst)
+		if err != nil || (requestsPerSecond > 0 && burstSize < 1) {
+			return nil, fmt.Errorf("'rateLimit.methodLimits' item '%s' must have a burst greater than zero", item)
+		}
+
+		limits[method] = MethodRateLimit{RequestsPerSecond: requestsPerSecond, Burst: burstSize}
+	}
+	return limits, nil
+}
+
+func (c RateLimitConfig) verify() error {
+	if !c.Enabled {
+		return nil
+	}
+	if c.RequestsPerSecond < 0 {
+		return errors.New("'rateLimit.requestsPerSecond' must be greater than or equal to zero")
+	}
+	if c.RequestsPerSecond > 0 && c.Burst < 1 {
+		return errors.New("'rateLimit.burst' must be greater than zero")
+	}
+	if _, err := c.ParseMethodLimits(); err != nil {
+		return err
+	}
+
+	switch c.Backend {
+	case RateLimitBackendMemory:
+	case RateLimitBackendRedis:
+		if c.Redis.Addr == "" {
+			return errors.New("'rateLimit.redis.addr' is required when 'rateLimit.backend' is 'redis'")
+		}
+		if c.Redis.Timeout <= 0 {
+			return errors.New("'rateLimit.redis.timeout' must be greater than zero")
+		}
+	default:
+		return fmt.Errorf("'rateLimit.backend' must be '%s' or '%s'", RateLimitBackendMemory, RateLimitBackendRedis)
+	}
+	return nil
+}
+
+// LoadSheddingConfig defines the priority classes of the requests, and the limits over which the requests of
+// the lowest classes are queued, then shed with an UNAVAILABLE error.
+type LoadSheddingConfig struct {
+	Enabled bool
+	// DefaultClass is the class of the requests whose method and client have no class of their own.
+	DefaultClass string
+	// MethodClasses sets the class of some API methods, as 'method=class' items, e.g. 'ReadChanges=background'.
+	MethodClasses []string
+	// ClientClasses sets the class of the requests of some clients, identified by the client ID or subject of
+	// their credentials, as 'client=class' items. They take precedence over MethodClasses.
+	ClientClasses []string
+	// InteractiveMaxInFlight, DefaultMaxInFlight and BackgroundMaxInFlight are the numbers of in-flight requests
+	// from which the requests of each class are queued. 0 means no limit.
+	InteractiveMaxInFlight int
+	DefaultMaxInFlight     int
+	BackgroundMaxInFlight  int
+	// DatastoreLatencyThreshold is the average datastore read latency over which the requests below the
+	// interactive class are queued. It requires the datastore adaptive concurrency. 0 disables it.
+	DatastoreLatencyThreshold time.Duration
+	// QueueTimeout is how long the requests over their limit wait to be admitted before being shed.
+	QueueTimeout time.Duration
+	// QueueFrequency is how often one of the queued requests is let through to try again.
+	QueueFrequency time.Duration
+	// RetryAfter is the delay after which the clients of shed requests are told to retry.
+	RetryAfter time.Duration
+}
+
+// ParseMethodClasses returns the class of each method in MethodClasses.
+func (c LoadSheddingConfig) ParseMethodClasses() (map[string]string, error) {
+	return parseLoadSheddingClasses("loadShedding.methodClasses", "method", c.MethodClasses)
+}
+
+// ParseClientClasses returns the class of each client in ClientClasses.
+func (c LoadSheddingConfig) ParseClientClasses() (map[string]string, error) {
+	return parseLoadSheddingClasses("loadShedding.clientClasses", "client", c.ClientClasses)
+}
+
+func parseLoadSheddingClasses(name, key string, items []string) (map[string]string, error) {
+	classes := make(map[string]string, len(items))
+	for _, item := range items {
+		k, class, ok := strings.Cut(item, "=")
+		if !ok || k == "" || !slices.Contains(LoadSheddingClasses, class) {
+			return nil, fmt.Errorf("'%s' item '%s' must be a '%s=class' item, where class is one of %v", name, item, key, LoadSheddingClasses)
+		}
+		classes[k] = class
+	}
+	return classes, nil
+}
+
+func (c LoadSheddingConfig) verify() error {
+	if !c.Enabled {
+		return nil
+	}
+	if !slices.Contains(LoadSheddingClasses, c.DefaultClass) {
+		return fmt.Errorf("'loadShedding.defaultClass' must be one of %v", LoadSheddingClasses)
+	}
+	if c.InteractiveMaxInFlight < 0 || c.DefaultMaxInFlight < 0 || c.BackgroundMaxInFlight < 0 {
+		return errors.New("'loadShedding.interactiveMaxInFlight', 'loadShedding.defaultMaxInFlight' and 'loadShedding.backgroundMaxInFlight' must be non-negative")
+	}
+	if c.DatastoreLatencyThreshold < 0 {
+		return errors.New("'loadShedding.datastoreLatencyThreshold' must be non-negative")
+	}
+	if c.QueueTimeout < 0 {
+		return errors.New("'loadShedding.queueTimeout' must be non-negative")
+	}
+	if c.QueueTimeout > 0 && c.QueueFrequency <= 0 {
+		return errors.New("'loadShedding.queueFrequency' must be greater than zero")
+	}
+	if c.RetryAfter <= 0 {
+		return errors.New("'loadShedding.retryAfter' must be greater than zero")
+	}
+	if _, err := c.ParseMethodClasses(); err != nil {
+		return err
+	}
+	_, err := c.ParseClientClasses()
+	return err
+}
+
+// SlowLogConfig defines the log of the Check, ListObjects and StreamedListObjects requests that take longer than
+// the threshold of their method. The most recent slow requests are kept in memory and served by the '/slowlog'
+// admin endpoint.
+type SlowLogConfig struct {
+	Enabled bool
+	// Threshold is the duration over which the requests of the methods without their own threshold are slow.
+	Threshold time.Duration
+	// MethodThresholds overrides the threshold of some methods, as 'method=duration' items, e.g. 'Check=200ms'.
+	MethodThresholds []string
+	// Capacity is the number of slow requests kept in memory.
+	Capacity int
+}
+
+// ParseMethodThresholds returns the threshold of each method in MethodThresholds.
+func (c SlowLogConfig) ParseMethodThresholds() (map[string]time.Duration, error) {
+	thresholds := make(map[string]time.Duration, len(c.MethodThresholds))
+	for _, item := range c.MethodThresholds {
+		method, value, ok := strings.Cut(item, "=")
+		if !ok || !slices.Contains(SlowLogMethods, method) {
+			return nil, fmt.Errorf("'slowLog.methodThresholds' item '%s' must be a 'method=duration' item, where method is one of %v", item, SlowLogMethods)
+		}
+
+		threshold, err := time.ParseDuration(value)
+		if err != nil || threshold < 0 {
+			return nil, fmt.Errorf("'slowLog.methodThresholds' item '%s' must have a non-negative duration", item)
+		}
+
+		thresholds[method] = threshold
+	}
+	return thresholds, nil
+}
+
+func (c SlowLogConfig) verify() error {
+	if !c.Enabled {
+		return nil
+	}
+	if c.Threshold < 0 {
+		return errors.New("'slowLog.threshold' must be non-negative")
+	}
+	if c.Capacity < 0 {
+		return errors.New("'slowLog.capacity' must be non-negative")
+	}
+	_, err := c.ParseMethodThresholds()
+	return err
+}
+
+// DecisionLogConfig defines the log of the authorization decisions made by Check, BatchCheck and ListObjects.
+type DecisionLogConfig struct {
+	Enabled bool
+	// Sink is where the decisions are written: "stdout" and "file" write JSON lines, "otlp" exports log
+	// records to an OTLP collector.
+	Sink string
+	File DecisionLogFileConfig
+	OTLP DecisionLogOTLPConfig `mapstructure:"otlp"`
+	// SampleRate is the fraction, between 0 and 1, of the decisions that are written.
+	SampleRate float64
+	// StoreSampleRates overrides the sample rate of some stores, as 'storeID=rate' items.
+	StoreSampleRates []string
+	// RedactFields are the fields whose value is replaced by 'REDACTED', among 'user', 'object',
+	// 'objects', 'principal' and 'context'.
+	RedactFields []string
+}
+
+// DecisionLogFileConfig defines the file the decisions are written to when the sink is "file". The file is
+// rotated when it reaches MaxSizeMB megabytes, and at most MaxBackups rotated files are kept.
+type DecisionLogFileConfig struct {
+	Path       string
+	MaxSizeMB  int
+	MaxBackups int
+}
+
+// DecisionLogOTLPConfig defines the OTLP collector the decisions are exported to when the sink is "otlp".
+type DecisionLogOTLPConfig struct {
+	Endpoint string
+	TLS      OTLPTraceTLSConfig
+}
+
+// ParseStoreSampleRates returns the sample rate of each store in StoreSampleRates.
+func (c DecisionLogConfig) ParseStoreSampleRates() (map[string]float64, error) {
+	rates := make(map[string]float64, len(c.StoreSampleRates))
+	for _, item := range c.StoreSampleRates {
+		storeID, value, ok := strings.Cut(item, "=")
+		if !ok || storeID == "" {
+			return nil, fmt.Errorf("'decisionLog.storeSampleRates' item '%s' must be a 'storeID=rate' item", item)
+		}
+
+		rate, err := strconv.ParseFloat(value, 64)
+		if err != nil || rate < 0 || rate > 1 {
+			return nil, fmt.Errorf("'decisionLog.storeSampleRates' item '%s' must have a rate between 0 and 1", item)
+		}
+
+		rates[storeID] = rate
+	}
+	return rates, nil
+}
+
+func (c DecisionLogConfig) verify() error {
+	if !c.Enabled {
+		return nil
+	}
+
+	switch c.Sink {
+	case DecisionLogSinkStdout:
+	case DecisionLogSinkFile:
+		if c.File.Path == "" {
+			return errors.New("'decisionLog.file.path' is required when 'decisionLog.sink' is 'file'")
+		}
+		if c.File.MaxSizeMB < 1 {
+			return errors.New("'decisionLog.file.maxSizeMB' must be greater than zero")
+		}
+		if c.File.MaxBackups < 0 {
+			return errors.New("'decisionLog.file.maxBackups' must be greater than or equal to zero")
+		}
+	case DecisionLogSinkOTLP:
+		if c.OTLP.Endpoint == "" {
+			return errors.New("'decisionLog.otlp.endpoint' is required when 'decisionLog.sink' is 'otlp'")
+		}
+	default:
+		return fmt.Errorf("'decisionLog.sink' must be one of ['%s', '%s', '%s']", DecisionLogSinkStdout, DecisionLogSinkFile, DecisionLogSinkOTLP)
+	}
+
+	if c.SampleRate < 0 || c.SampleRate > 1 {
+		return errors.New("'decisionLog.sampleRate' must be between 0 and 1")
+	}
+	if _, err := c.ParseStoreSampleRates(); err != nil {
+		return err
+	}
+
+	for _, field := range c.RedactFields {
+		switch field {
+		case "user", "object", "objects", "principal", "context":
+		default:
+			return fmt.Errorf("'decisionLog.redactFields' field '%s' must be one of ['user', 'object', 'objects', 'principal', 'context']", field)
+		}
+	}
+	return nil
 }
 
 // IteratorCacheConfig defines configuration to cache storage iterator results.
@@ -292,6 +808,66 @@ type AccessControlConfig struct {
 	ModelID string
 }
 
+// ConditionContextConfig defines the trusted values that the server adds to the context of the conditions
+// evaluated by Check, BatchCheck, ListObjects and ListUsers. They override the values with the same key
+// sent by the caller. An empty key disables the value.
+type ConditionContextConfig struct {
+	// RequestTimeKey is the key set to the time the server handles the request.
+	RequestTimeKey string
+	// RequestTimeGranularity is the duration the request time is truncated to, so that the requests made
+	// within the same interval share the check cache entries. If 0, the time is not truncated.
+	RequestTimeGranularity time.Duration
+	// PeerIPKey is the key set to the IP address of the client.
+	PeerIPKey string
+	// ClaimKeys sets keys to claims of the authenticated caller, as 'claim=key' pairs where claim is
+	// 'subject', 'client_id' or 'scopes'.
+	ClaimKeys []string
+}
+
+// ParseClaimKeys returns the context key of each claim in ClaimKeys.
+func (c ConditionContextConfig) ParseClaimKeys() (map[string]string, error) {
+	keys := make(map[string]string, len(c.ClaimKeys))
+	for _, pair := range c.ClaimKeys {
+		claim, key, ok := strings.Cut(pair, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("'conditionContext.claimKeys' item '%s' must be a 'claim=key' pair", pair)
+		}
+		if claim != "subject" && claim != "client_id" && claim != "scopes" {
+			return nil, fmt.Errorf("'conditionContext.claimKeys' claim '%s' must be one of ['subject', 'client_id', 'scopes']", claim)
+		}
+		keys[claim] = key
+	}
+	return keys, nil
+}
+
+func (c ConditionContextConfig) verify() error {
+	if c.RequestTimeGranularity < 0 {
+		return errors.New("'conditionContext.requestTimeGranularity' must be non-negative")
+	}
+
+	claimKeys, err := c.ParseClaimKeys()
+	if err != nil {
+		return err
+	}
+
+	keys := []string{c.RequestTimeKey, c.PeerIPKey}
+	for _, key := range claimKeys {
+		keys = append(keys, key)
+	}
+
+	seen := map[string]bool{}
+	for _, key := range keys {
+		if key == "" {
+			continue
+		}
+		if seen[key] {
+			return fmt.Errorf("'conditionContext' key '%s' is set by more than one value", key)
+		}
+		seen[key] = true
+	}
+	return nil
+}
+
 type PlannerConfig struct {
 	EvictionThreshold time.Duration
 	CleanupInterval   time.Duration
@@ -321,7 +897,21 @@ type Config struct {
 	// This is to protect the server from misuse of the ListUsers endpoints.
 	ListUsersMaxResults uint32
 
-	// MaxTuplesPerWrite defines the maximum number of tuples per Write endpoint.
+	// PermissionMatrixMaxObjects defines the maximum number of objects of a single type that a
+	// permission matrix report can enumerate. This is to protect the server from reports that
+	// would evaluate every object of a large store.
+	PermissionMatrixMaxObjects uint32
+
+	// AccessReviewMaxChanges defines the maximum number of changelog entries that an access review
+	// can read to rebuild the tuples of a store at an earlier point in time.
+	AccessReviewMaxChanges uint32
+
+	// StoreStatsCacheTTL defines how long the GetStoreStats API caches the statistics of a store,
+	// which are aggregated over all its tuples. If 0, they are read on every request.
+	StoreStatsCacheTTL time.Duration
+
+	// MaxTuplesPerWrite defines the maximum number of tuples per Write endpoint. It cannot be larger than
+	// MaxTuplesPerWriteCeiling.
 	MaxTuplesPerWrite int
 
 	// MaxChecksPerBatchCheck defines the maximum number of tuples
@@ -355,6 +945,10 @@ type Config struct {
 	// MaxConditionEvaluationCost defines the maximum cost for CEL condition evaluation before a request returns an error
 	MaxConditionEvaluationCost uint64
 
+	// ConditionExtensionsEnabled makes the extension functions of the condition library (time zones, CIDR lists,
+	// semantic versions and bounded regular expressions) available to condition expressions.
+	ConditionExtensionsEnabled bool
+
 	// ChangelogHorizonOffset is an offset in minutes from the current time. Changes that occur
 	// after this offset will not be included in the response of ReadChanges.
 	ChangelogHorizonOffset int
@@ -365,6 +959,21 @@ type Config struct {
 	// AccessControl is the configuration for the access control feature.
 	AccessControl AccessControlConfig
 
+	// ConditionContext defines the trusted values added to the context of condition evaluations.
+	ConditionContext ConditionContextConfig
+
+	// RateLimit limits the rate of the requests of each client.
+	RateLimit RateLimitConfig
+
+	// DecisionLog records the authorization decisions made by Check, BatchCheck and ListObjects.
+	DecisionLog DecisionLogConfig
+
+	// SlowLog records the Check, ListObjects and StreamedListObjects requests slower than their threshold.
+	SlowLog SlowLogConfig
+
+	// LoadShedding queues or sheds the requests of the lowest priority classes first when the server is overloaded.
+	LoadShedding LoadSheddingConfig
+
 	// ResolveNodeLimit indicates how deeply nested an authorization model can be before a query
 	// errors out.
 	ResolveNodeLimit uint32
@@ -389,6 +998,7 @@ type Config struct {
 	Trace                         TraceConfig
 	Playground                    PlaygroundConfig
 	Profiler                      ProfilerConfig
+	Admin                         AdminConfig
 	Metrics                       MetricConfig
 	CheckCache                    CheckCacheConfig
 	CheckIteratorCache            IteratorCacheConfig
@@ -424,6 +1034,10 @@ func (cfg *Config) VerifyServerSettings() error {
 		return fmt.Errorf("config 'maxConcurrentReadsForListUsers' cannot be 0")
 	}
 
+	if cfg.MaxTuplesPerWrite < 1 || cfg.MaxTuplesPerWrite > MaxTuplesPerWriteCeiling {
+		return fmt.Errorf("config 'maxTuplesPerWrite' must be between 1 and %d", MaxTuplesPerWriteCeiling)
+	}
+
 	if err := cfg.verifyRequestDurationDatastoreQueryCountBuckets(); err != nil {
 		return err
 	}
@@ -454,6 +1068,10 @@ func (cfg *Config) VerifyServerSettings() error {
 		return err
 	}
 
+	if err := cfg.Datastore.AdaptiveConcurrency.verify(); err != nil {
+		return err
+	}
+
 	if cfg.ListObjectsDeadline < 0 {
 		return errors.New("listObjectsDeadline must be non-negative time duration")
 	}
@@ -466,6 +1084,42 @@ func (cfg *Config) VerifyServerSettings() error {
 		return errors.New("maxConditionsEvaluationCosts less than 100 can cause API compatibility problems with Conditions")
 	}
 
+	if err := cfg.ConditionContext.verify(); err != nil {
+		return err
+	}
+
+	if err := cfg.RateLimit.verify(); err != nil {
+		return err
+	}
+
+	if err := cfg.DecisionLog.verify(); err != nil {
+		return err
+	}
+
+	if err := cfg.Admin.verify(); err != nil {
+		return err
+	}
+
+	if err := cfg.SlowLog.verify(); err != nil {
+		return err
+	}
+
+	if err := cfg.LoadShedding.verify(); err != nil {
+		return err
+	}
+
+	if cfg.LoadShedding.Enabled && cfg.LoadShedding.DatastoreLatencyThreshold > 0 && !cfg.Datastore.AdaptiveConcurrency.Enabled {
+		return errors.New("'loadShedding.datastoreLatencyThreshold' requires 'datastore.adaptiveConcurrency.enabled'")
+	}
+
+	if err := cfg.Metrics.OTLP.verify(); err != nil {
+		return err
+	}
+
+	if err := cfg.Metrics.StoreLabels.verify(); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -672,6 +1326,18 @@ func (cfg *Config) verifyCacheConfig() error {
 	if cfg.CacheController.Enabled && cfg.CacheController.TTL <= 0 {
 		return errors.New("'cacheController.ttl' must be greater than zero")
 	}
+	switch cfg.CheckCache.Backend {
+	case CheckCacheBackendMemory:
+	case CheckCacheBackendRedis:
+		if cfg.CheckCache.Redis.Addr == "" {
+			return errors.New("'checkCache.redis.addr' is required when 'checkCache.backend' is 'redis'")
+		}
+		if cfg.CheckCache.Redis.Timeout <= 0 {
+			return errors.New("'checkCache.redis.timeout' must be greater than zero")
+		}
+	default:
+		return fmt.Errorf("'checkCache.backend' must be '%s' or '%s'", CheckCacheBackendMemory, CheckCacheBackendRedis)
+	}
 	return nil
 }
 
@@ -692,15 +1358,20 @@ func DefaultConfig() *Config {
 		MaxConcurrentReadsForListObjects:          DefaultMaxConcurrentReadsForListObjects,
 		MaxConcurrentReadsForListUsers:            DefaultMaxConcurrentReadsForListUsers,
 		MaxConditionEvaluationCost:                DefaultMaxConditionEvaluationCost,
+		ConditionExtensionsEnabled:                false,
 		ChangelogHorizonOffset:                    DefaultChangelogHorizonOffset,
 		ResolveNodeLimit:                          DefaultResolveNodeLimit,
 		ResolveNodeBreadthLimit:                   DefaultResolveNodeBreadthLimit,
 		Experimentals:                             []string{},
 		AccessControl:                             AccessControlConfig{Enabled: false, StoreID: "", ModelID: ""},
+		ConditionContext:                          ConditionContextConfig{RequestTimeGranularity: DefaultConditionContextRequestTimeGranularity, ClaimKeys: []string{}},
 		ListObjectsDeadline:                       DefaultListObjectsDeadline,
 		ListObjectsMaxResults:                     DefaultListObjectsMaxResults,
 		ListUsersMaxResults:                       DefaultListUsersMaxResults,
 		ListUsersDeadline:                         DefaultListUsersDeadline,
+		PermissionMatrixMaxObjects:                DefaultPermissionMatrixMaxObjects,
+		AccessReviewMaxChanges:                    DefaultAccessReviewMaxChanges,
+		StoreStatsCacheTTL:                        DefaultStoreStatsCacheTTL,
 		RequestDurationDatastoreQueryCountBuckets: []string{"50", "200"},
 		RequestDurationDispatchCountBuckets:       []string{"50", "200"},
 		Datastore: DatastoreConfig{
@@ -708,6 +1379,13 @@ func DefaultConfig() *Config {
 			MaxCacheSize: DefaultMaxAuthorizationModelCacheSize,
 			MaxIdleConns: 10,
 			MaxOpenConns: 30,
+			AdaptiveConcurrency: DatastoreAdaptiveConcurrencyConfig{
+				Enabled:          DefaultDatastoreAdaptiveConcurrencyEnabled,
+				MinLimit:         DefaultDatastoreAdaptiveConcurrencyMinLimit,
+				MaxLimit:         DefaultDatastoreAdaptiveConcurrencyMaxLimit,
+				LatencyThreshold: DefaultDatastoreAdaptiveConcurrencyLatencyThreshold,
+				BackoffRatio:     DefaultDatastoreAdaptiveConcurrencyBackoffRatio,
+			},
 		},
 		GRPC: GRPCConfig{
 			Addr: "0.0.0.0:8081",
@@ -754,6 +1432,20 @@ func DefaultConfig() *Config {
 			Enabled:             true,
 			Addr:                "0.0.0.0:2112",
 			EnableRPCHistograms: false,
+			OTLP: OTLPMetricConfig{
+				Enabled:        DefaultMetricsOTLPEnabled,
+				Endpoint:       DefaultMetricsOTLPEndpoint,
+				Protocol:       DefaultMetricsOTLPProtocol,
+				ExportInterval: DefaultMetricsOTLPExportInterval,
+			},
+			StoreLabels: StoreLabelsConfig{
+				Enabled:            DefaultMetricsStoreLabelsEnabled,
+				AllowedStores:      []string{},
+				MaxStores:          DefaultMetricsStoreLabelsMaxStores,
+				ModelLabelsEnabled: DefaultMetricsModelLabelsEnabled,
+				MaxModels:          DefaultMetricsModelLabelsMaxModels,
+				RankingInterval:    DefaultMetricsStoreLabelsRankingInterval,
+			},
 		},
 		CheckIteratorCache: IteratorCacheConfig{
 			Enabled:    DefaultCheckIteratorCacheEnabled,
@@ -765,7 +1457,60 @@ func DefaultConfig() *Config {
 			TTL:     DefaultCheckQueryCacheTTL,
 		},
 		CheckCache: CheckCacheConfig{
-			Limit: DefaultCheckCacheLimit,
+			Limit:   DefaultCheckCacheLimit,
+			Backend: DefaultCheckCacheBackend,
+			Redis: CheckCacheRedisConfig{
+				LocalTTL: DefaultCheckCacheRedisLocalTTL,
+				Timeout:  DefaultCheckCacheRedisTimeout,
+			},
+		},
+		RateLimit: RateLimitConfig{
+			Enabled:           DefaultRateLimitEnabled,
+			RequestsPerSecond: DefaultRateLimitRequestsPerSecond,
+			Burst:             DefaultRateLimitBurst,
+			MethodLimits:      []string{},
+			Backend:           DefaultRateLimitBackend,
+			Redis: RateLimitRedisConfig{
+				Timeout: DefaultRateLimitRedisTimeout,
+			},
+		},
+		DecisionLog: DecisionLogConfig{
+			Enabled: DefaultDecisionLogEnabled,
+			Sink:    DefaultDecisionLogSink,
+			File: DecisionLogFileConfig{
+				MaxSizeMB:  DefaultDecisionLogFileMaxSizeMB,
+				MaxBackups: DefaultDecisionLogFileMaxBackups,
+			},
+			OTLP: DecisionLogOTLPConfig{
+				Endpoint: DefaultDecisionLogOTLPEndpoint,
+			},
+			SampleRate:       DefaultDecisionLogSampleRate,
+			StoreSampleRates: []string{},
+			RedactFields:     []string{},
+		},
+		SlowLog: SlowLogConfig{
+			Enabled:          DefaultSlowLogEnabled,
+			Threshold:        DefaultSlowLogThreshold,
+			MethodThresholds: []string{},
+			Capacity:         DefaultSlowLogCapacity,
+		},
+		LoadShedding: LoadSheddingConfig{
+			Enabled:                   DefaultLoadSheddingEnabled,
+			DefaultClass:              DefaultLoadSheddingDefaultClass,
+			MethodClasses:             []string{},
+			ClientClasses:             []string{},
+			InteractiveMaxInFlight:    DefaultLoadSheddingInteractiveMaxInFlight,
+			DefaultMaxInFlight:        DefaultLoadSheddingDefaultMaxInFlight,
+			BackgroundMaxInFlight:     DefaultLoadSheddingBackgroundMaxInFlight,
+			DatastoreLatencyThreshold: DefaultLoadSheddingDatastoreLatencyThreshold,
+			QueueTimeout:              DefaultLoadSheddingQueueTimeout,
+			QueueFrequency:            DefaultLoadSheddingQueueFrequency,
+			RetryAfter:                DefaultLoadSheddingRetryAfter,
+		},
+		Admin: AdminConfig{
+			Enabled: DefaultAdminEnabled,
+			Addr:    DefaultAdminAddr,
+			Keys:    []string{},
 		},
 		SharedIterator: SharedIteratorConfig{
 			Enabled: DefaultSharedIteratorEnabled,
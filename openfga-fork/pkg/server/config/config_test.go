@@ -40,6 +40,18 @@ func TestVerifyConfig(t *testing.T) {
 		require.EqualError(t, err, "config 'maxConcurrentReadsForListUsers' cannot be 0")
 	})
 
+	t.Run("maxTuplesPerWrite_within_ceiling", func(t *testing.T) {
+		cfg := DefaultConfig()
+		cfg.MaxTuplesPerWrite = MaxTuplesPerWriteCeiling
+		require.NoError(t, cfg.Verify())
+
+		cfg.MaxTuplesPerWrite = MaxTuplesPerWriteCeiling + 1
+		require.EqualError(t, cfg.Verify(), "config 'maxTuplesPerWrite' must be between 1 and 10000")
+
+		cfg.MaxTuplesPerWrite = 0
+		require.EqualError(t, cfg.Verify(), "config 'maxTuplesPerWrite' must be between 1 and 10000")
+	})
+
 	t.Run("failing_to_set_http_cert_path_will_not_allow_server_to_start", func(t *testing.T) {
 		cfg := DefaultConfig()
 		cfg.HTTP.TLS = &TLSConfig{
@@ -551,6 +563,379 @@ func TestVerifyConfig(t *testing.T) {
 		})
 	})
 
+	t.Run("check_cache_backend", func(t *testing.T) {
+		t.Run("unknown_backend", func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.CheckCache.Backend = "memcached"
+			err := cfg.Verify()
+			require.EqualError(t, err, "'checkCache.backend' must be 'memory' or 'redis'")
+		})
+		t.Run("redis_without_addr", func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.CheckCache.Backend = CheckCacheBackendRedis
+			err := cfg.Verify()
+			require.EqualError(t, err, "'checkCache.redis.addr' is required when 'checkCache.backend' is 'redis'")
+		})
+		t.Run("redis_with_addr", func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.CheckCache.Backend = CheckCacheBackendRedis
+			cfg.CheckCache.Redis.Addr = "localhost:6379"
+			err := cfg.Verify()
+			require.NoError(t, err)
+		})
+	})
+
+	t.Run("condition_context", func(t *testing.T) {
+		t.Run("invalid_claim_pair", func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.ConditionContext.ClaimKeys = []string{"subject"}
+			err := cfg.Verify()
+			require.EqualError(t, err, "'conditionContext.claimKeys' item 'subject' must be a 'claim=key' pair")
+		})
+		t.Run("unknown_claim", func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.ConditionContext.ClaimKeys = []string{"email=user_email"}
+			err := cfg.Verify()
+			require.EqualError(t, err, "'conditionContext.claimKeys' claim 'email' must be one of ['subject', 'client_id', 'scopes']")
+		})
+		t.Run("negative_request_time_granularity", func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.ConditionContext.RequestTimeGranularity = -time.Second
+			err := cfg.Verify()
+			require.EqualError(t, err, "'conditionContext.requestTimeGranularity' must be non-negative")
+		})
+		t.Run("duplicate_key", func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.ConditionContext.PeerIPKey = "user_ip"
+			cfg.ConditionContext.ClaimKeys = []string{"subject=user_ip"}
+			err := cfg.Verify()
+			require.EqualError(t, err, "'conditionContext' key 'user_ip' is set by more than one value")
+		})
+		t.Run("valid", func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.ConditionContext.RequestTimeKey = "current_time"
+			cfg.ConditionContext.PeerIPKey = "user_ip"
+			cfg.ConditionContext.ClaimKeys = []string{"subject=user_id", "scopes=scopes"}
+			err := cfg.Verify()
+			require.NoError(t, err)
+		})
+	})
+
+	t.Run("rate_limit", func(t *testing.T) {
+		t.Run("negative_rate", func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.RateLimit.Enabled = true
+			cfg.RateLimit.RequestsPerSecond = -1
+			err := cfg.Verify()
+			require.EqualError(t, err, "'rateLimit.requestsPerSecond' must be greater than or equal to zero")
+		})
+		t.Run("zero_burst", func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.RateLimit.Enabled = true
+			cfg.RateLimit.Burst = 0
+			err := cfg.Verify()
+			require.EqualError(t, err, "'rateLimit.burst' must be greater than zero")
+		})
+		t.Run("invalid_method_limit", func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.RateLimit.Enabled = true
+			cfg.RateLimit.MethodLimits = []string{"Check=10"}
+			err := cfg.Verify()
+			require.EqualError(t, err, "'rateLimit.methodLimits' item 'Check=10' must be a 'method=requestsPerSecond:burst' item")
+		})
+		t.Run("unknown_method_limit", func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.RateLimit.Enabled = true
+			cfg.RateLimit.MethodLimits = []string{"Chekc=10:20"}
+			err := cfg.Verify()
+			require.EqualError(t, err, "'rateLimit.methodLimits' item 'Chekc=10:20' has an unknown API method 'Chekc'")
+		})
+		t.Run("redis_without_addr", func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.RateLimit.Enabled = true
+			cfg.RateLimit.Backend = RateLimitBackendRedis
+			err := cfg.Verify()
+			require.EqualError(t, err, "'rateLimit.redis.addr' is required when 'rateLimit.backend' is 'redis'")
+		})
+		t.Run("unknown_backend", func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.RateLimit.Enabled = true
+			cfg.RateLimit.Backend = "memcached"
+			err := cfg.Verify()
+			require.EqualError(t, err, "'rateLimit.backend' must be 'memory' or 'redis'")
+		})
+		t.Run("disabled_is_not_verified", func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.RateLimit.Backend = "memcached"
+			err := cfg.Verify()
+			require.NoError(t, err)
+		})
+		t.Run("valid", func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.RateLimit.Enabled = true
+			cfg.RateLimit.MethodLimits = []string{"Check=50:100", "Read=0:1"}
+			cfg.RateLimit.Backend = RateLimitBackendRedis
+			cfg.RateLimit.Redis.Addr = "localhost:6379"
+			err := cfg.Verify()
+			require.NoError(t, err)
+
+			limits, err := cfg.RateLimit.ParseMethodLimits()
+			require.NoError(t, err)
+			require.Equal(t, map[string]MethodRateLimit{
+				"Check": {RequestsPerSecond: 50, Burst: 100},
+				"Read":  {RequestsPerSecond: 0, Burst: 1},
+			}, limits)
+		})
+	})
+
+	t.Run("decision_log", func(t *testing.T) {
+		t.Run("file_without_path", func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.DecisionLog.Enabled = true
+			cfg.DecisionLog.Sink = DecisionLogSinkFile
+			err := cfg.Verify()
+			require.EqualError(t, err, "'decisionLog.file.path' is required when 'decisionLog.sink' is 'file'")
+		})
+		t.Run("unknown_sink", func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.DecisionLog.Enabled = true
+			cfg.DecisionLog.Sink = "kafka"
+			err := cfg.Verify()
+			require.EqualError(t, err, "'decisionLog.sink' must be one of ['stdout', 'file', 'otlp']")
+		})
+		t.Run("invalid_sample_rate", func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.DecisionLog.Enabled = true
+			cfg.DecisionLog.SampleRate = 2
+			err := cfg.Verify()
+			require.EqualError(t, err, "'decisionLog.sampleRate' must be between 0 and 1")
+		})
+		t.Run("invalid_store_sample_rate", func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.DecisionLog.Enabled = true
+			cfg.DecisionLog.StoreSampleRates = []string{"store=all"}
+			err := cfg.Verify()
+			require.EqualError(t, err, "'decisionLog.storeSampleRates' item 'store=all' must have a rate between 0 and 1")
+		})
+		t.Run("unknown_redact_field", func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.DecisionLog.Enabled = true
+			cfg.DecisionLog.RedactFields = []string{"relation"}
+			err := cfg.Verify()
+			require.EqualError(t, err, "'decisionLog.redactFields' field 'relation' must be one of ['user', 'object', 'objects', 'principal', 'context']")
+		})
+		t.Run("valid", func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.DecisionLog.Enabled = true
+			cfg.DecisionLog.Sink = DecisionLogSinkFile
+			cfg.DecisionLog.File.Path = "/var/log/openfga/decisions.log"
+			cfg.DecisionLog.SampleRate = 0.1
+			cfg.DecisionLog.StoreSampleRates = []string{"01K3RZVNE3NJ4FYKK99QN013G2=1"}
+			cfg.DecisionLog.RedactFields = []string{"user", "context"}
+			err := cfg.Verify()
+			require.NoError(t, err)
+
+			rates, err := cfg.DecisionLog.ParseStoreSampleRates()
+			require.NoError(t, err)
+			require.Equal(t, map[string]float64{"01K3RZVNE3NJ4FYKK99QN013G2": 1}, rates)
+		})
+	})
+
+	t.Run("metrics_otlp", func(t *testing.T) {
+		t.Run("unknown_protocol", func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.Metrics.OTLP.Enabled = true
+			cfg.Metrics.OTLP.Protocol = "udp"
+			err := cfg.Verify()
+			require.EqualError(t, err, "'metrics.otlp.protocol' must be 'grpc' or 'http'")
+		})
+		t.Run("zero_export_interval", func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.Metrics.OTLP.Enabled = true
+			cfg.Metrics.OTLP.ExportInterval = 0
+			err := cfg.Verify()
+			require.EqualError(t, err, "'metrics.otlp.exportInterval' must be greater than zero")
+		})
+		t.Run("valid", func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.Metrics.OTLP.Enabled = true
+			cfg.Metrics.OTLP.Protocol = MetricsOTLPProtocolHTTP
+			cfg.Metrics.OTLP.Endpoint = "collector:4318"
+			err := cfg.Verify()
+			require.NoError(t, err)
+		})
+	})
+
+	t.Run("admin", func(t *testing.T) {
+		t.Run("missing_keys", func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.Admin.Enabled = true
+			err := cfg.Verify()
+			require.EqualError(t, err, "'admin.keys' is required when 'admin.enabled' is set")
+		})
+		t.Run("valid", func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.Admin.Enabled = true
+			cfg.Admin.Keys = []string{"key"}
+			require.NoError(t, cfg.Verify())
+		})
+	})
+
+	t.Run("slow_log", func(t *testing.T) {
+		t.Run("unknown_method", func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.SlowLog.Enabled = true
+			cfg.SlowLog.MethodThresholds = []string{"Write=1s"}
+			err := cfg.Verify()
+			require.EqualError(t, err, "'slowLog.methodThresholds' item 'Write=1s' must be a 'method=duration' item, where method is one of [Check ListObjects StreamedListObjects]")
+		})
+		t.Run("invalid_duration", func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.SlowLog.Enabled = true
+			cfg.SlowLog.MethodThresholds = []string{"Check=fast"}
+			err := cfg.Verify()
+			require.EqualError(t, err, "'slowLog.methodThresholds' item 'Check=fast' must have a non-negative duration")
+		})
+		t.Run("negative_capacity", func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.SlowLog.Enabled = true
+			cfg.SlowLog.Capacity = -1
+			err := cfg.Verify()
+			require.EqualError(t, err, "'slowLog.capacity' must be non-negative")
+		})
+		t.Run("valid", func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.SlowLog.Enabled = true
+			cfg.SlowLog.MethodThresholds = []string{"Check=200ms", "ListObjects=2s"}
+			require.NoError(t, cfg.Verify())
+
+			thresholds, err := cfg.SlowLog.ParseMethodThresholds()
+			require.NoError(t, err)
+			require.Equal(t, map[string]time.Duration{"Check": 200 * time.Millisecond, "ListObjects": 2 * time.Second}, thresholds)
+		})
+	})
+
+	t.Run("datastore_adaptive_concurrency", func(t *testing.T) {
+		t.Run("zero_min_limit", func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.Datastore.AdaptiveConcurrency.Enabled = true
+			cfg.Datastore.AdaptiveConcurrency.MinLimit = 0
+			err := cfg.Verify()
+			require.EqualError(t, err, "'datastore.adaptiveConcurrency.minLimit' must be greater than zero")
+		})
+		t.Run("max_limit_below_min_limit", func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.Datastore.AdaptiveConcurrency.Enabled = true
+			cfg.Datastore.AdaptiveConcurrency.MinLimit = 20
+			cfg.Datastore.AdaptiveConcurrency.MaxLimit = 10
+			err := cfg.Verify()
+			require.EqualError(t, err, "'datastore.adaptiveConcurrency.maxLimit' must be greater than or equal to 'datastore.adaptiveConcurrency.minLimit'")
+		})
+		t.Run("invalid_backoff_ratio", func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.Datastore.AdaptiveConcurrency.Enabled = true
+			cfg.Datastore.AdaptiveConcurrency.BackoffRatio = 1
+			err := cfg.Verify()
+			require.EqualError(t, err, "'datastore.adaptiveConcurrency.backoffRatio' must be between 0 and 1, exclusive")
+		})
+		t.Run("valid", func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.Datastore.AdaptiveConcurrency.Enabled = true
+			require.NoError(t, cfg.Verify())
+		})
+	})
+
+	t.Run("load_shedding", func(t *testing.T) {
+		t.Run("invalid_default_class", func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.LoadShedding.Enabled = true
+			cfg.LoadShedding.DefaultClass = "urgent"
+			err := cfg.Verify()
+			require.EqualError(t, err, "'loadShedding.defaultClass' must be one of [interactive default background]")
+		})
+		t.Run("invalid_method_class", func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.LoadShedding.Enabled = true
+			cfg.LoadShedding.MethodClasses = []string{"Check=urgent"}
+			err := cfg.Verify()
+			require.EqualError(t, err, "'loadShedding.methodClasses' item 'Check=urgent' must be a 'method=class' item, where class is one of [interactive default background]")
+		})
+		t.Run("invalid_client_class", func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.LoadShedding.Enabled = true
+			cfg.LoadShedding.ClientClasses = []string{"batch"}
+			err := cfg.Verify()
+			require.EqualError(t, err, "'loadShedding.clientClasses' item 'batch' must be a 'client=class' item, where class is one of [interactive default background]")
+		})
+		t.Run("negative_max_in_flight", func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.LoadShedding.Enabled = true
+			cfg.LoadShedding.BackgroundMaxInFlight = -1
+			err := cfg.Verify()
+			require.EqualError(t, err, "'loadShedding.interactiveMaxInFlight', 'loadShedding.defaultMaxInFlight' and 'loadShedding.backgroundMaxInFlight' must be non-negative")
+		})
+		t.Run("zero_retry_after", func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.LoadShedding.Enabled = true
+			cfg.LoadShedding.RetryAfter = 0
+			err := cfg.Verify()
+			require.EqualError(t, err, "'loadShedding.retryAfter' must be greater than zero")
+		})
+		t.Run("datastore_latency_threshold_without_adaptive_concurrency", func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.LoadShedding.Enabled = true
+			cfg.LoadShedding.DatastoreLatencyThreshold = 50 * time.Millisecond
+			err := cfg.Verify()
+			require.EqualError(t, err, "'loadShedding.datastoreLatencyThreshold' requires 'datastore.adaptiveConcurrency.enabled'")
+		})
+		t.Run("valid", func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.LoadShedding.Enabled = true
+			cfg.LoadShedding.MethodClasses = []string{"Check=interactive", "ReadChanges=background"}
+			cfg.LoadShedding.ClientClasses = []string{"batch=background"}
+			cfg.LoadShedding.DatastoreLatencyThreshold = 50 * time.Millisecond
+			cfg.Datastore.AdaptiveConcurrency.Enabled = true
+			require.NoError(t, cfg.Verify())
+
+			classes, err := cfg.LoadShedding.ParseMethodClasses()
+			require.NoError(t, err)
+			require.Equal(t, map[string]string{"Check": "interactive", "ReadChanges": "background"}, classes)
+		})
+	})
+
+	t.Run("metrics_store_labels", func(t *testing.T) {
+		t.Run("negative_max_stores", func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.Metrics.StoreLabels.Enabled = true
+			cfg.Metrics.StoreLabels.MaxStores = -1
+			err := cfg.Verify()
+			require.EqualError(t, err, "'metrics.storeLabels.maxStores' must be non-negative")
+		})
+		t.Run("zero_max_models", func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.Metrics.StoreLabels.Enabled = true
+			cfg.Metrics.StoreLabels.ModelLabelsEnabled = true
+			cfg.Metrics.StoreLabels.MaxModels = 0
+			err := cfg.Verify()
+			require.EqualError(t, err, "'metrics.storeLabels.maxModels' must be greater than zero when 'metrics.storeLabels.modelLabelsEnabled' is set")
+		})
+		t.Run("zero_ranking_interval", func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.Metrics.StoreLabels.Enabled = true
+			cfg.Metrics.StoreLabels.RankingInterval = 0
+			err := cfg.Verify()
+			require.EqualError(t, err, "'metrics.storeLabels.rankingInterval' must be greater than zero")
+		})
+		t.Run("valid", func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.Metrics.StoreLabels.Enabled = true
+			cfg.Metrics.StoreLabels.AllowedStores = []string{"01K3RZVNE3NJ4FYKK99QN013G2"}
+			cfg.Metrics.StoreLabels.ModelLabelsEnabled = true
+			err := cfg.Verify()
+			require.NoError(t, err)
+		})
+	})
+
 	t.Run("prints_warning_when_log_level_is_none", func(t *testing.T) {
 		cfg := DefaultConfig()
 		cfg.Log.Level = "none"
@@ -5,6 +5,7 @@ import (
 	"regexp"
 	"strings"
 
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
@@ -130,6 +131,40 @@ func NewEncodedError(errorCode int32, message string) *EncodedError {
 	}
 }
 
+// EncodeError returns the encoded error of err, the way NewEncodedError encodes the code of its gRPC status.
+// The errors that carry a RetryInfo detail, i.e. rate limited and shed requests, keep their gRPC code and are
+// a 429 Too Many Requests when ResourceExhausted and a 503 Service Unavailable when Unavailable. The errors
+// that carry a PreconditionFailure detail, i.e. failed write preconditions, keep their gRPC code and are a
+// 412 Precondition Failed. The other ResourceExhausted, Unavailable and FailedPrecondition errors, such as
+// ErrTransactionThrottled, remain internal errors.
+func EncodeError(err error) *EncodedError {
+	st := status.Convert(err)
+	encodedError := NewEncodedError(ConvertToEncodedErrorCode(st), err.Error())
+
+	for _, detail := range st.Details() {
+		var httpStatusCode int
+		switch detail.(type) {
+		case *errdetails.RetryInfo:
+			switch st.Code() {
+			case codes.ResourceExhausted:
+				httpStatusCode = http.StatusTooManyRequests
+			case codes.Unavailable:
+				httpStatusCode = http.StatusServiceUnavailable
+			}
+		case *errdetails.PreconditionFailure:
+			if st.Code() == codes.FailedPrecondition {
+				httpStatusCode = http.StatusPreconditionFailed
+			}
+		}
+		if httpStatusCode != 0 {
+			encodedError.HTTPStatusCode = httpStatusCode
+			encodedError.GRPCStatusCode = st.Code()
+			break
+		}
+	}
+	return encodedError
+}
+
 // IsValidEncodedError returns whether the error code is a valid encoded error.
 func IsValidEncodedError(errorCode int32) bool {
 	return errorCode >= cFirstAuthenticationErrorCode
@@ -1,12 +1,17 @@
 package errors
 
 import (
+	"errors"
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+	"google.golang.org/protobuf/types/known/durationpb"
 
 	openfgav1 "github.com/openfga/api/proto/openfga/v1"
 )
@@ -137,6 +142,82 @@ func TestEncodedError(t *testing.T) {
 	}
 }
 
+func TestEncodeError(t *testing.T) {
+	withDetail := func(code codes.Code, detail protoadapt.MessageV1) error {
+		st, err := status.New(code, "error message").WithDetails(detail)
+		require.NoError(t, err)
+		return st.Err()
+	}
+	retryInfo := &errdetails.RetryInfo{RetryDelay: durationpb.New(time.Second)}
+
+	var tests = []struct {
+		_name                  string
+		err                    error
+		expectedHTTPStatusCode int
+		expectedGRPCStatusCode codes.Code
+		expectedCodeString     string
+	}{
+		{
+			_name:                  "rate_limited",
+			err:                    withDetail(codes.ResourceExhausted, retryInfo),
+			expectedHTTPStatusCode: http.StatusTooManyRequests,
+			expectedGRPCStatusCode: codes.ResourceExhausted,
+			expectedCodeString:     "resource_exhausted",
+		},
+		{
+			_name:                  "shed",
+			err:                    withDetail(codes.Unavailable, retryInfo),
+			expectedHTTPStatusCode: http.StatusServiceUnavailable,
+			expectedGRPCStatusCode: codes.Unavailable,
+			expectedCodeString:     "unavailable",
+		},
+		{
+			_name:                  "write_precondition_failed",
+			err:                    WritePreconditionFailed(errors.New("tuple exists")),
+			expectedHTTPStatusCode: http.StatusPreconditionFailed,
+			expectedGRPCStatusCode: codes.FailedPrecondition,
+			expectedCodeString:     "failed_precondition",
+		},
+		{
+			_name:                  "transaction_throttled",
+			err:                    ErrTransactionThrottled,
+			expectedHTTPStatusCode: http.StatusInternalServerError,
+			expectedGRPCStatusCode: codes.Internal,
+			expectedCodeString:     "resource_exhausted",
+		},
+		{
+			_name:                  "unavailable_without_retry_info",
+			err:                    status.Error(codes.Unavailable, "error message"),
+			expectedHTTPStatusCode: http.StatusInternalServerError,
+			expectedGRPCStatusCode: codes.Internal,
+			expectedCodeString:     "unavailable",
+		},
+		{
+			_name:                  "failed_precondition_without_precondition_failure",
+			err:                    status.Error(codes.FailedPrecondition, "error message"),
+			expectedHTTPStatusCode: http.StatusInternalServerError,
+			expectedGRPCStatusCode: codes.Internal,
+			expectedCodeString:     "failed_precondition",
+		},
+		{
+			_name:                  "validation_error",
+			err:                    status.Error(codes.Code(openfgav1.ErrorCode_validation_error), "error message"),
+			expectedHTTPStatusCode: http.StatusBadRequest,
+			expectedGRPCStatusCode: codes.InvalidArgument,
+			expectedCodeString:     "validation_error",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test._name, func(t *testing.T) {
+			actualError := EncodeError(test.err)
+
+			require.Equal(t, test.expectedHTTPStatusCode, actualError.HTTPStatusCode)
+			require.Equal(t, test.expectedGRPCStatusCode, actualError.GRPCStatusCode)
+			require.Equal(t, test.expectedCodeString, actualError.Code())
+		})
+	}
+}
+
 func TestConvertToEncodedErrorCode(t *testing.T) {
 	type encodedTests struct {
 		_name             string
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
@@ -113,6 +114,17 @@ func WriteFailedDueToInvalidInput(err error) error {
 	return status.Error(codes.Code(openfgav1.ErrorCode_write_failed_due_to_invalid_input), err.Error())
 }
 
+// WritePreconditionFailed is returned when a precondition of a write does not hold. It carries a
+// PreconditionFailure detail, so that over HTTP it is a 412 Precondition Failed with the code 'failed_precondition'.
+func WritePreconditionFailed(err error) error {
+	st := status.New(codes.FailedPrecondition, err.Error())
+	violation := &errdetails.PreconditionFailure_Violation{Type: "write", Description: err.Error()}
+	if detailed, err := st.WithDetails(&errdetails.PreconditionFailure{Violations: []*errdetails.PreconditionFailure_Violation{violation}}); err == nil {
+		st = detailed
+	}
+	return st.Err()
+}
+
 func InvalidAuthorizationModelInput(err error) error {
 	return status.Error(codes.Code(openfgav1.ErrorCode_invalid_authorization_model), err.Error())
 }
@@ -2,6 +2,7 @@ package server
 
 import (
 	"context"
+	"errors"
 
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/trace"
@@ -10,9 +11,11 @@ import (
 
 	openfgav1 "github.com/openfga/api/proto/openfga/v1"
 
+	"github.com/openfga/openfga/internal/condition"
 	"github.com/openfga/openfga/internal/utils/apimethod"
 	"github.com/openfga/openfga/pkg/middleware/validator"
 	"github.com/openfga/openfga/pkg/server/commands"
+	serverErrors "github.com/openfga/openfga/pkg/server/errors"
 	"github.com/openfga/openfga/pkg/telemetry"
 	"github.com/openfga/openfga/pkg/typesystem"
 )
@@ -60,3 +63,56 @@ func (s *Server) Expand(ctx context.Context, req *openfgav1.ExpandRequest) (*ope
 			ContextualTuples: req.GetContextualTuples(),
 		})
 }
+
+// ExpandRecursive returns the userset tree of a relation of an object with the relations it refers to expanded
+// recursively, down to the requested depth, and the users the tree resolves to. The depth is limited by the
+// resolve node limit, and the breadth of each node by the resolve node breadth limit.
+func (s *Server) ExpandRecursive(ctx context.Context, req *commands.RecursiveExpandRequest) (*commands.RecursiveExpandResponse, error) {
+	ctx, span := tracer.Start(ctx, apimethod.Expand.String(), trace.WithAttributes(
+		attribute.String("store_id", req.StoreID),
+		attribute.String("object", req.Object),
+		attribute.String("relation", req.Relation),
+		attribute.Int("max_depth", int(req.MaxDepth)),
+		attribute.String("consistency", req.Consistency.String()),
+	))
+	defer span.End()
+
+	if err := req.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	ctx = telemetry.ContextWithRPCInfo(ctx, telemetry.RPCInfo{
+		Service: s.serviceName,
+		Method:  apimethod.Expand.String(),
+	})
+
+	err := s.checkAuthz(ctx, req.StoreID, apimethod.Expand)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Context = s.trustedConditionContext(ctx, req.Context)
+
+	typesys, err := s.resolveTypesystem(ctx, req.StoreID, req.AuthorizationModelID)
+	if err != nil {
+		return nil, err
+	}
+
+	q := commands.NewExpandQuery(
+		s.datastore,
+		commands.WithExpandQueryLogger(s.logger),
+		commands.WithExpandResolveNodeLimit(s.resolveNodeLimit),
+		commands.WithExpandResolveNodeBreadthLimit(s.resolveNodeBreadthLimit),
+	)
+	res, err := q.ExecuteRecursive(typesystem.ContextWithTypesystem(ctx, typesys), req)
+	if err != nil {
+		telemetry.TraceError(span, err)
+		if errors.Is(err, condition.ErrEvaluationFailed) {
+			return nil, serverErrors.ValidationError(err)
+		}
+		return nil, err
+	}
+	span.SetAttributes(attribute.Int("user_count", len(res.Users)), attribute.Bool("complete", res.Complete))
+
+	return res, nil
+}
@@ -0,0 +1,75 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+
+	"github.com/openfga/openfga/pkg/server/commands"
+	"github.com/openfga/openfga/pkg/tuple"
+)
+
+func TestExpandRecursive(t *testing.T) {
+	ctx := context.Background()
+
+	s, _, storeID := newTestServer(t, WithResolveNodeLimit(10))
+
+	writeTestModel(t, s, storeID, `
+		model
+			schema 1.1
+		type user
+		type group
+			relations
+				define member: [user, group#member]
+		type document
+			relations
+				define viewer: [group#member, user with in_office]
+		condition in_office(office: bool) {
+			office
+		}`)
+
+	_, err := s.Write(ctx, &openfgav1.WriteRequest{
+		StoreId: storeID,
+		Writes: &openfgav1.WriteRequestWrites{TupleKeys: []*openfgav1.TupleKey{
+			tuple.NewTupleKey("document:1", "viewer", "group:eng#member"),
+			tuple.NewTupleKey("group:eng", "member", "user:anne"),
+			tuple.NewTupleKeyWithCondition("document:1", "viewer", "user:bob", "in_office", nil),
+		}},
+	})
+	require.NoError(t, err)
+
+	mux := newTestHTTPMux(t, s)
+
+	expand := func(body string) (int, *commands.RecursiveExpandResponse) {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/stores/"+storeID+"/expand/recursive", strings.NewReader(body)))
+		resp := &commands.RecursiveExpandResponse{}
+		if rec.Code == http.StatusOK {
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), resp))
+		}
+		return rec.Code, resp
+	}
+
+	code, resp := expand(`{"object": "document:1", "relation": "viewer", "context": {"office": true}}`)
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, []string{"user:anne", "user:bob"}, resp.Users)
+	require.True(t, resp.Complete)
+	require.Equal(t, commands.ExpandNodeDirect, resp.Tree.Usersets[0].Type)
+
+	code, resp = expand(`{"object": "document:1", "relation": "viewer", "context": {"office": false}, "contextual_tuples": {"tuple_keys": [{"object": "group:eng", "relation": "member", "user": "user:charlie"}]}}`)
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, []string{"user:anne", "user:charlie"}, resp.Users)
+
+	code, _ = expand(`{"object": "document:1", "relation": "viewer", "max_depth": 11}`)
+	require.Equal(t, http.StatusBadRequest, code)
+
+	code, _ = expand(`{"object": "document:1", "relation": "viewer"}`)
+	require.Equal(t, http.StatusBadRequest, code)
+}
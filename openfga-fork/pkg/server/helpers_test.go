@@ -0,0 +1,58 @@
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+	language "github.com/openfga/language/pkg/go/transformer"
+
+	"github.com/openfga/openfga/internal/authn"
+	"github.com/openfga/openfga/pkg/storage"
+	"github.com/openfga/openfga/pkg/storage/memory"
+)
+
+// newTestServer returns a server over an in-memory datastore, the datastore, and the ID of a store created for
+// the test. The goroutines of the server are checked for leaks once the test is done.
+func newTestServer(t *testing.T, opts ...OpenFGAServiceV1Option) (*Server, storage.OpenFGADatastore, string) {
+	t.Helper()
+	t.Cleanup(func() {
+		goleak.VerifyNone(t)
+	})
+
+	ds := memory.New()
+	s := MustNewServerWithOpts(append([]OpenFGAServiceV1Option{WithDatastore(ds)}, opts...)...)
+	t.Cleanup(s.Close)
+
+	createStoreResp, err := s.CreateStore(context.Background(), &openfgav1.CreateStoreRequest{Name: "openfga-demo"})
+	require.NoError(t, err)
+	return s, ds, createStoreResp.GetId()
+}
+
+// writeTestModel writes the model in DSL to the store and returns its ID.
+func writeTestModel(t *testing.T, s *Server, storeID, dsl string) string {
+	t.Helper()
+
+	model := language.MustTransformDSLToProto(dsl)
+	writeModelResp, err := s.WriteAuthorizationModel(context.Background(), &openfgav1.WriteAuthorizationModelRequest{
+		StoreId:         storeID,
+		SchemaVersion:   model.GetSchemaVersion(),
+		TypeDefinitions: model.GetTypeDefinitions(),
+		Conditions:      model.GetConditions(),
+	})
+	require.NoError(t, err)
+	return writeModelResp.GetAuthorizationModelId()
+}
+
+// newTestHTTPMux returns a mux serving the HTTP endpoints of the server without authentication.
+func newTestHTTPMux(t *testing.T, s *Server) *runtime.ServeMux {
+	t.Helper()
+
+	mux := runtime.NewServeMux()
+	require.NoError(t, s.RegisterHTTPHandlers(mux, authn.NoopAuthenticator{}))
+	return mux
+}
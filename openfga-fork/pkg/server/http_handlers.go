@@ -0,0 +1,678 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+
+	"github.com/openfga/openfga/internal/authn"
+	"github.com/openfga/openfga/internal/utils/apimethod"
+	"github.com/openfga/openfga/pkg/authclaims"
+	httpmiddleware "github.com/openfga/openfga/pkg/middleware/http"
+	"github.com/openfga/openfga/pkg/server/commands"
+	"github.com/openfga/openfga/pkg/server/commands/listusers"
+	serverErrors "github.com/openfga/openfga/pkg/server/errors"
+)
+
+// httpHandlerFunc serves one HTTP API that has no counterpart in the OpenFGA protobuf service.
+// The returned value is encoded as the JSON response body.
+type httpHandlerFunc func(ctx context.Context, r *http.Request, pathParams map[string]string) (any, error)
+
+// httpStreamHandlerFunc serves an HTTP API that writes its own response body. Errors returned
+// before anything is written to w are rendered like any other API error.
+type httpStreamHandlerFunc func(ctx context.Context, w http.ResponseWriter, r *http.Request, pathParams map[string]string) error
+
+// maxHTTPRequestBodySize is the maximum size of the body of the requests that are read at once. It is the
+// default maximum size of a gRPC message, which bounds the requests of the grpc-gateway routes.
+const maxHTTPRequestBodySize = 4 << 20
+
+type httpRoute struct {
+	method    string
+	pattern   string
+	apiMethod apimethod.APIMethod
+	handler   httpStreamHandlerFunc
+}
+
+// httpRoutes lists the HTTP APIs that are served next to the grpc-gateway routes.
+func (s *Server) httpRoutes() []httpRoute {
+	return []httpRoute{
+		{http.MethodGet, "/stores/{store_id}/active-authorization-model", apimethod.ReadActiveAuthorizationModel, jsonHTTPHandler(s.handleReadActiveAuthorizationModel)},
+		{http.MethodPut, "/stores/{store_id}/active-authorization-model", apimethod.WriteActiveAuthorizationModel, jsonHTTPHandler(s.handleWriteActiveAuthorizationModel)},
+		{http.MethodDelete, "/stores/{store_id}/active-authorization-model", apimethod.WriteActiveAuthorizationModel, jsonHTTPHandler(s.handleDeleteActiveAuthorizationModel)},
+		{http.MethodPost, "/stores/{store_id}/active-authorization-model/rollback", apimethod.RollbackActiveAuthorizationModel, jsonHTTPHandler(s.handleRollbackActiveAuthorizationModel)},
+		{http.MethodGet, "/stores/{store_id}/permission-matrix", apimethod.PermissionMatrix, s.handlePermissionMatrix},
+		{http.MethodGet, "/stores/{store_id}/access-review", apimethod.AccessReview, s.handleAccessReview},
+		{http.MethodPost, "/stores/{store_id}/write/preconditions", apimethod.Write, jsonHTTPHandler(s.handleWriteWithPreconditions)},
+		{http.MethodPost, "/stores/{store_id}/what-if", apimethod.WhatIf, jsonHTTPHandler(s.handleWhatIf)},
+		{http.MethodPost, "/stores/{store_id}/list-objects/pages", apimethod.ListObjects, jsonHTTPHandler(s.handleListObjectsPage)},
+		{http.MethodPost, "/stores/{store_id}/list-objects/candidates", apimethod.ListObjects, jsonHTTPHandler(s.handleListObjectsWithCandidates)},
+		{http.MethodPost, "/stores/{store_id}/list-relations", apimethod.ListRelations, jsonHTTPHandler(s.handleListRelations)},
+		{http.MethodPost, "/stores/{store_id}/tuples/search", apimethod.SearchTuples, jsonHTTPHandler(s.handleSearchTuples)},
+		{http.MethodGet, "/stores/{store_id}/stats", apimethod.GetStoreStats, jsonHTTPHandler(s.handleGetStoreStats)},
+		{http.MethodPost, "/stores/{store_id}/expand/recursive", apimethod.Expand, jsonHTTPHandler(s.handleExpandRecursive)},
+		{http.MethodPost, "/stores/{store_id}/streamed-list-users", apimethod.StreamedListUsers, s.handleStreamedListUsers},
+		{http.MethodPost, "/stores/{store_id}/streamed-batch-check", apimethod.StreamedBatchCheck, s.handleStreamedBatchCheck},
+	}
+}
+
+// RegisterHTTPHandlers registers on mux the HTTP APIs that are not part of the OpenFGA protobuf service.
+// Requests are authenticated with authenticator and rate limited, the same way the gRPC interceptors do it.
+func (s *Server) RegisterHTTPHandlers(mux *runtime.ServeMux, authenticator authn.Authenticator) error {
+	for _, route := range s.httpRoutes() {
+		if err := mux.HandlePath(route.method, route.pattern, s.serveHTTP(authenticator, route)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Server) serveHTTP(authenticator authn.Authenticator, route httpRoute) runtime.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request, pathParams map[string]string) {
+		// Authenticators read the credentials from the incoming gRPC metadata.
+		ctx := metadata.NewIncomingContext(r.Context(), metadata.Pairs("authorization", r.Header.Get("Authorization")))
+		if addr, err := net.ResolveTCPAddr("tcp", r.RemoteAddr); err == nil {
+			ctx = peer.NewContext(ctx, &peer.Peer{Addr: addr})
+		}
+
+		// Headers set through the gRPC transport (e.g. the x-http-code header) are collected
+		// by the stream so that they can be written to the HTTP response.
+		stream := &httpServerTransportStream{header: metadata.MD{}}
+		ctx = grpc.NewContextWithServerTransportStream(ctx, stream)
+		ctx = runtime.NewServerMetadataContext(ctx, runtime.ServerMetadata{HeaderMD: stream.header})
+
+		claims, err := authenticator.Authenticate(ctx)
+		if err != nil {
+			writeHTTPError(ctx, w, r, err)
+			return
+		}
+		ctx = authclaims.ContextWithAuthClaims(ctx, claims)
+
+		if s.rateLimiter != nil {
+			if err := s.rateLimiter.Allow(ctx, pathParams["store_id"], route.apiMethod); err != nil {
+				writeHTTPError(ctx, w, r, err)
+				return
+			}
+		}
+
+		if s.loadShedder != nil {
+			release, err := s.loadShedder.Admit(ctx, route.apiMethod)
+			if err != nil {
+				writeHTTPError(ctx, w, r, err)
+				return
+			}
+			defer release()
+		}
+
+		if err := route.handler(ctx, w, r, pathParams); err != nil {
+			writeHTTPError(ctx, w, r, err)
+		}
+	}
+}
+
+// jsonHTTPHandler adapts a handler whose response is a single JSON document.
+func jsonHTTPHandler(handler httpHandlerFunc) httpStreamHandlerFunc {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request, pathParams map[string]string) error {
+		res, err := handler(ctx, r, pathParams)
+		if err != nil {
+			return err
+		}
+
+		code := http.StatusOK
+		if stream, ok := grpc.ServerTransportStreamFromContext(ctx).(*httpServerTransportStream); ok {
+			if vals := stream.header.Get(httpmiddleware.XHttpCode); len(vals) > 0 {
+				if c, err := strconv.Atoi(vals[0]); err == nil {
+					code = c
+				}
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(code)
+		_ = json.NewEncoder(w).Encode(res)
+		return nil
+	}
+}
+
+func writeHTTPError(ctx context.Context, w http.ResponseWriter, r *http.Request, err error) {
+	httpmiddleware.CustomHTTPErrorHandler(ctx, w, r, serverErrors.EncodeError(err))
+}
+
+// decodeHTTPBody decodes the JSON request body into v. An empty body leaves v untouched.
+func decodeHTTPBody(r *http.Request, v any) error {
+	if r.Body == nil {
+		return nil
+	}
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil && !errors.Is(err, io.EOF) {
+		return status.Errorf(codes.InvalidArgument, "invalid request body: %s", err)
+	}
+	return nil
+}
+
+// httpServerTransportStream is the grpc.ServerTransportStream of requests served by httpHandlerFunc.
+type httpServerTransportStream struct {
+	header metadata.MD
+}
+
+var _ grpc.ServerTransportStream = (*httpServerTransportStream)(nil)
+
+func (s *httpServerTransportStream) Method() string { return "" }
+
+func (s *httpServerTransportStream) SetHeader(md metadata.MD) error {
+	for k, v := range md {
+		s.header[k] = append(s.header[k], v...)
+	}
+	return nil
+}
+
+func (s *httpServerTransportStream) SendHeader(md metadata.MD) error {
+	return s.SetHeader(md)
+}
+
+func (s *httpServerTransportStream) SetTrailer(metadata.MD) error { return nil }
+
+func (s *Server) handleReadActiveAuthorizationModel(ctx context.Context, _ *http.Request, pathParams map[string]string) (any, error) {
+	return s.ReadActiveAuthorizationModel(ctx, &commands.ReadActiveAuthorizationModelRequest{StoreID: pathParams["store_id"]})
+}
+
+func (s *Server) handleWriteActiveAuthorizationModel(ctx context.Context, r *http.Request, pathParams map[string]string) (any, error) {
+	req := &commands.WriteActiveAuthorizationModelRequest{}
+	if err := decodeHTTPBody(r, req); err != nil {
+		return nil, err
+	}
+	req.StoreID = pathParams["store_id"]
+	return s.WriteActiveAuthorizationModel(ctx, req)
+}
+
+func (s *Server) handleDeleteActiveAuthorizationModel(ctx context.Context, _ *http.Request, pathParams map[string]string) (any, error) {
+	return s.WriteActiveAuthorizationModel(ctx, &commands.WriteActiveAuthorizationModelRequest{StoreID: pathParams["store_id"]})
+}
+
+func (s *Server) handleRollbackActiveAuthorizationModel(ctx context.Context, _ *http.Request, pathParams map[string]string) (any, error) {
+	return s.RollbackActiveAuthorizationModel(ctx, &commands.RollbackActiveAuthorizationModelRequest{StoreID: pathParams["store_id"]})
+}
+
+func (s *Server) handlePermissionMatrix(ctx context.Context, w http.ResponseWriter, r *http.Request, pathParams map[string]string) error {
+	query := r.URL.Query()
+	pageSize, err := queryInt32(r, "page_size")
+	if err != nil {
+		return err
+	}
+	consistency, err := queryConsistency(r)
+	if err != nil {
+		return err
+	}
+
+	stream, err := newHTTPRowStream(w, r, []string{"user", "relation", "object"})
+	if err != nil {
+		return err
+	}
+
+	res, err := s.PermissionMatrix(ctx, &commands.PermissionMatrixRequest{
+		StoreID:              pathParams["store_id"],
+		AuthorizationModelID: query.Get("authorization_model_id"),
+		ObjectTypes:          query["object_type"],
+		Relations:            query["relation"],
+		UserFilters:          commands.ParseUserTypeFilters(query["user_filter"]),
+		PageSize:             pageSize,
+		ContinuationToken:    query.Get("continuation_token"),
+		Consistency:          consistency,
+	}, func(row *commands.PermissionMatrixRow) error {
+		return stream.Write(row, []string{row.User, row.Relation, row.Object})
+	})
+
+	return stream.Close(res.GetContinuationToken(), res.GetTruncated(), err)
+}
+
+func (s *Server) handleAccessReview(ctx context.Context, w http.ResponseWriter, r *http.Request, pathParams map[string]string) error {
+	query := r.URL.Query()
+
+	stream, err := newHTTPRowStream(w, r, []string{"change", "user", "relation", "object"})
+	if err != nil {
+		return err
+	}
+
+	err = s.AccessReview(ctx, &commands.AccessReviewRequest{
+		StoreID:              pathParams["store_id"],
+		AuthorizationModelID: query.Get("authorization_model_id"),
+		ObjectType:           query.Get("object_type"),
+		Relations:            query["relation"],
+		From:                 query.Get("from"),
+		To:                   query.Get("to"),
+	}, func(row *commands.AccessReviewRow) error {
+		return stream.Write(row, []string{row.Change, row.User, row.Relation, row.Object})
+	})
+
+	return stream.Close("", false, err)
+}
+
+// whatIfHTTPRequest is the body of a WhatIf request. Tuples are encoded the way the Write API encodes them.
+type whatIfHTTPRequest struct {
+	AuthorizationModelID string            `json:"authorization_model_id"`
+	Writes               []json.RawMessage `json:"writes"`
+	Deletes              []json.RawMessage `json:"deletes"`
+	ObjectTypes          []string          `json:"object_types"`
+}
+
+func (s *Server) handleWhatIf(ctx context.Context, r *http.Request, pathParams map[string]string) (any, error) {
+	body := &whatIfHTTPRequest{}
+	if err := decodeHTTPBody(r, body); err != nil {
+		return nil, err
+	}
+
+	req := &commands.WhatIfRequest{
+		StoreID:              pathParams["store_id"],
+		AuthorizationModelID: body.AuthorizationModelID,
+		ObjectTypes:          body.ObjectTypes,
+	}
+	for _, raw := range body.Writes {
+		tk := &openfgav1.TupleKey{}
+		if err := protojson.Unmarshal(raw, tk); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid request body: %s", err)
+		}
+		req.Writes = append(req.Writes, tk)
+	}
+	for _, raw := range body.Deletes {
+		tk := &openfgav1.TupleKeyWithoutCondition{}
+		if err := protojson.Unmarshal(raw, tk); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid request body: %s", err)
+		}
+		req.Deletes = append(req.Deletes, tk)
+	}
+
+	return s.WhatIf(ctx, req)
+}
+
+// listObjectsPageHTTPRequest holds the pagination parameters of a ListObjectsPage request. The other fields of
+// the body are those of a ListObjects request.
+type listObjectsPageHTTPRequest struct {
+	PageSize          int32  `json:"page_size"`
+	ContinuationToken string `json:"continuation_token"`
+}
+
+func (s *Server) handleListObjectsPage(ctx context.Context, r *http.Request, pathParams map[string]string) (any, error) {
+	body, err := io.ReadAll(http.MaxBytesReader(nil, r.Body, maxHTTPRequestBodySize))
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid request body: %s", err)
+	}
+
+	page := &listObjectsPageHTTPRequest{}
+	if err := json.Unmarshal(body, page); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid request body: %s", err)
+	}
+	req := &openfgav1.ListObjectsRequest{}
+	if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(body, req); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid request body: %s", err)
+	}
+	req.StoreId = pathParams["store_id"]
+
+	return s.ListObjectsPage(ctx, &commands.ListObjectsPageRequest{
+		ListObjectsRequest: req,
+		PageSize:           page.PageSize,
+		ContinuationToken:  page.ContinuationToken,
+	})
+}
+
+// writePreconditionsHTTPRequest holds the preconditions of a WriteWithPreconditions request. The other fields
+// of the body are those of a Write request.
+type writePreconditionsHTTPRequest struct {
+	Preconditions struct {
+		TuplesExist      []tupleKeyHTTP `json:"tuples_exist"`
+		TuplesNotExist   []tupleKeyHTTP `json:"tuples_not_exist"`
+		LatestChangeULID string         `json:"latest_change_ulid"`
+	} `json:"preconditions"`
+}
+
+type tupleKeyHTTP struct {
+	Object   string `json:"object"`
+	Relation string `json:"relation"`
+	User     string `json:"user"`
+}
+
+func tupleKeysFromHTTP(keys []tupleKeyHTTP) []*openfgav1.TupleKeyWithoutCondition {
+	tks := make([]*openfgav1.TupleKeyWithoutCondition, 0, len(keys))
+	for _, key := range keys {
+		tks = append(tks, &openfgav1.TupleKeyWithoutCondition{Object: key.Object, Relation: key.Relation, User: key.User})
+	}
+	return tks
+}
+
+func (s *Server) handleWriteWithPreconditions(ctx context.Context, r *http.Request, pathParams map[string]string) (any, error) {
+	body, err := io.ReadAll(http.MaxBytesReader(nil, r.Body, maxHTTPRequestBodySize))
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid request body: %s", err)
+	}
+
+	preconditions := &writePreconditionsHTTPRequest{}
+	if err := json.Unmarshal(body, preconditions); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid request body: %s", err)
+	}
+	req := &openfgav1.WriteRequest{}
+	if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(body, req); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid request body: %s", err)
+	}
+	req.StoreId = pathParams["store_id"]
+
+	res, err := s.WriteWithPreconditions(ctx, &commands.WriteWithPreconditionsRequest{
+		WriteRequest:     req,
+		TuplesExist:      tupleKeysFromHTTP(preconditions.Preconditions.TuplesExist),
+		TuplesNotExist:   tupleKeysFromHTTP(preconditions.Preconditions.TuplesNotExist),
+		LatestChangeULID: preconditions.Preconditions.LatestChangeULID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	encoded, err := protojson.Marshal(res)
+	if err != nil {
+		return nil, err
+	}
+	return json.RawMessage(encoded), nil
+}
+
+// listObjectsCandidatesHTTPRequest holds the candidate objects of a ListObjectsWithCandidates request. The
+// other fields of the body are those of a ListObjects request.
+type listObjectsCandidatesHTTPRequest struct {
+	ObjectIDs []string `json:"object_ids"`
+}
+
+func (s *Server) handleListObjectsWithCandidates(ctx context.Context, r *http.Request, pathParams map[string]string) (any, error) {
+	body, err := io.ReadAll(http.MaxBytesReader(nil, r.Body, maxHTTPRequestBodySize))
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid request body: %s", err)
+	}
+
+	candidates := &listObjectsCandidatesHTTPRequest{}
+	if err := json.Unmarshal(body, candidates); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid request body: %s", err)
+	}
+	req := &openfgav1.ListObjectsRequest{}
+	if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(body, req); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid request body: %s", err)
+	}
+	req.StoreId = pathParams["store_id"]
+
+	res, err := s.ListObjectsWithCandidates(ctx, &commands.ListObjectsCandidatesRequest{
+		ListObjectsRequest: req,
+		ObjectIDs:          candidates.ObjectIDs,
+	})
+	if err != nil {
+		return nil, err
+	}
+	encoded, err := protojson.Marshal(res)
+	if err != nil {
+		return nil, err
+	}
+	return json.RawMessage(encoded), nil
+}
+
+// checkInputsHTTPRequest holds the inputs of a request that are encoded the way the Check API encodes them.
+type checkInputsHTTPRequest struct {
+	ContextualTuples json.RawMessage `json:"contextual_tuples"`
+	Context          json.RawMessage `json:"context"`
+	Consistency      string          `json:"consistency"`
+}
+
+func (b *checkInputsHTTPRequest) decode() (*openfgav1.ContextualTupleKeys, *structpb.Struct, openfgav1.ConsistencyPreference, error) {
+	var contextualTuples *openfgav1.ContextualTupleKeys
+	if len(b.ContextualTuples) > 0 {
+		contextualTuples = &openfgav1.ContextualTupleKeys{}
+		if err := protojson.Unmarshal(b.ContextualTuples, contextualTuples); err != nil {
+			return nil, nil, 0, status.Errorf(codes.InvalidArgument, "invalid request body: %s", err)
+		}
+	}
+
+	var conditionContext *structpb.Struct
+	if len(b.Context) > 0 {
+		conditionContext = &structpb.Struct{}
+		if err := protojson.Unmarshal(b.Context, conditionContext); err != nil {
+			return nil, nil, 0, status.Errorf(codes.InvalidArgument, "invalid request body: %s", err)
+		}
+	}
+
+	consistency, err := parseConsistency(b.Consistency)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+
+	return contextualTuples, conditionContext, consistency, nil
+}
+
+// listRelationsHTTPRequest is the body of a ListRelations request.
+type listRelationsHTTPRequest struct {
+	AuthorizationModelID string   `json:"authorization_model_id"`
+	Object               string   `json:"object"`
+	User                 string   `json:"user"`
+	Relations            []string `json:"relations"`
+	checkInputsHTTPRequest
+}
+
+func (s *Server) handleListRelations(ctx context.Context, r *http.Request, pathParams map[string]string) (any, error) {
+	body := &listRelationsHTTPRequest{}
+	if err := decodeHTTPBody(r, body); err != nil {
+		return nil, err
+	}
+
+	contextualTuples, conditionContext, consistency, err := body.decode()
+	if err != nil {
+		return nil, err
+	}
+
+	return s.ListRelations(ctx, &commands.ListRelationsRequest{
+		StoreID:              pathParams["store_id"],
+		AuthorizationModelID: body.AuthorizationModelID,
+		Object:               body.Object,
+		User:                 body.User,
+		Relations:            body.Relations,
+		ContextualTuples:     contextualTuples,
+		Context:              conditionContext,
+		Consistency:          consistency,
+	})
+}
+
+// expandRecursiveHTTPRequest is the body of a recursive Expand request.
+type expandRecursiveHTTPRequest struct {
+	AuthorizationModelID string `json:"authorization_model_id"`
+	Object               string `json:"object"`
+	Relation             string `json:"relation"`
+	MaxDepth             uint32 `json:"max_depth"`
+	checkInputsHTTPRequest
+}
+
+func (s *Server) handleExpandRecursive(ctx context.Context, r *http.Request, pathParams map[string]string) (any, error) {
+	body := &expandRecursiveHTTPRequest{}
+	if err := decodeHTTPBody(r, body); err != nil {
+		return nil, err
+	}
+
+	contextualTuples, conditionContext, consistency, err := body.decode()
+	if err != nil {
+		return nil, err
+	}
+
+	return s.ExpandRecursive(ctx, &commands.RecursiveExpandRequest{
+		StoreID:              pathParams["store_id"],
+		AuthorizationModelID: body.AuthorizationModelID,
+		Object:               body.Object,
+		Relation:             body.Relation,
+		MaxDepth:             body.MaxDepth,
+		ContextualTuples:     contextualTuples.GetTupleKeys(),
+		Context:              conditionContext,
+		Consistency:          consistency,
+	})
+}
+
+// searchTuplesHTTPRequest is the body of a SearchTuples request.
+type searchTuplesHTTPRequest struct {
+	ObjectType        string `json:"object_type"`
+	ObjectIDPrefix    string `json:"object_id_prefix"`
+	Relation          string `json:"relation"`
+	UserType          string `json:"user_type"`
+	UserPrefix        string `json:"user_prefix"`
+	ConditionName     string `json:"condition_name"`
+	WrittenAfter      string `json:"written_after"`
+	WrittenBefore     string `json:"written_before"`
+	PageSize          int32  `json:"page_size"`
+	ContinuationToken string `json:"continuation_token"`
+	Consistency       string `json:"consistency"`
+}
+
+func (s *Server) handleSearchTuples(ctx context.Context, r *http.Request, pathParams map[string]string) (any, error) {
+	body := &searchTuplesHTTPRequest{}
+	if err := decodeHTTPBody(r, body); err != nil {
+		return nil, err
+	}
+
+	consistency, err := parseConsistency(body.Consistency)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := s.SearchTuples(ctx, &commands.SearchTuplesRequest{
+		StoreID:           pathParams["store_id"],
+		ObjectType:        body.ObjectType,
+		ObjectIDPrefix:    body.ObjectIDPrefix,
+		Relation:          body.Relation,
+		UserType:          body.UserType,
+		UserPrefix:        body.UserPrefix,
+		ConditionName:     body.ConditionName,
+		WrittenAfter:      body.WrittenAfter,
+		WrittenBefore:     body.WrittenBefore,
+		PageSize:          body.PageSize,
+		ContinuationToken: body.ContinuationToken,
+		Consistency:       consistency,
+	})
+	if err != nil {
+		return nil, err
+	}
+	encoded, err := protojson.Marshal(res)
+	if err != nil {
+		return nil, err
+	}
+	return json.RawMessage(encoded), nil
+}
+
+func (s *Server) handleGetStoreStats(ctx context.Context, _ *http.Request, pathParams map[string]string) (any, error) {
+	return s.GetStoreStats(ctx, &commands.GetStoreStatsRequest{StoreID: pathParams["store_id"]})
+}
+
+// streamedListUsersHTTPLine is one line of a StreamedListUsers response. Every line holds a user, except the
+// last one, which holds the metadata of the response.
+type streamedListUsersHTTPLine struct {
+	Result struct {
+		User     json.RawMessage                      `json:"user,omitempty"`
+		Metadata *listusers.StreamedListUsersMetadata `json:"metadata,omitempty"`
+	} `json:"result"`
+}
+
+func (s *Server) handleStreamedListUsers(ctx context.Context, w http.ResponseWriter, r *http.Request, pathParams map[string]string) error {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxHTTPRequestBodySize))
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid request body: %s", err)
+	}
+	req := &openfgav1.ListUsersRequest{}
+	if err := protojson.Unmarshal(body, req); err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid request body: %s", err)
+	}
+	req.StoreId = pathParams["store_id"]
+
+	stream := &httpRowStream{w: w, format: httpStreamFormatJSONLines}
+	res, err := s.StreamedListUsers(ctx, req, func(user *openfgav1.User) error {
+		encoded, err := protojson.Marshal(user)
+		if err != nil {
+			return err
+		}
+		line := &streamedListUsersHTTPLine{}
+		line.Result.User = encoded
+		return stream.Write(line, nil)
+	})
+	if err == nil {
+		line := &streamedListUsersHTTPLine{}
+		line.Result.Metadata = res
+		err = stream.Write(line, nil)
+	}
+
+	return stream.Close("", false, err)
+}
+
+// streamedBatchCheckHTTPLine is one line of a StreamedBatchCheck response. Every line holds the result of a
+// check, except the last one, which holds the metadata of the response.
+type streamedBatchCheckHTTPLine struct {
+	Result struct {
+		CorrelationID string                               `json:"correlation_id,omitempty"`
+		Check         json.RawMessage                      `json:"check,omitempty"`
+		Metadata      *commands.StreamedBatchCheckMetadata `json:"metadata,omitempty"`
+	} `json:"result"`
+}
+
+// handleStreamedBatchCheck reads the checks from the request body, as JSON Lines of BatchCheckItem, while the
+// results are written to the response. Only the checks whose results have not been sent yet, and the outcomes
+// of the last completed checks used to deduplicate the stream, are held in memory.
+func (s *Server) handleStreamedBatchCheck(ctx context.Context, w http.ResponseWriter, r *http.Request, pathParams map[string]string) error {
+	consistency, err := queryConsistency(r)
+	if err != nil {
+		return err
+	}
+
+	// HTTP/1.x responses stop the reads of the request body unless full duplex is enabled. HTTP/2 is
+	// always full duplex.
+	_ = http.NewResponseController(w).EnableFullDuplex()
+
+	decoder := json.NewDecoder(r.Body)
+	stream := &httpRowStream{w: w, format: httpStreamFormatJSONLines}
+	res, err := s.StreamedBatchCheck(ctx, &commands.StreamedBatchCheckParams{
+		StoreID:              pathParams["store_id"],
+		AuthorizationModelID: r.URL.Query().Get("authorization_model_id"),
+		Consistency:          consistency,
+		Next: func() (*openfgav1.BatchCheckItem, error) {
+			var raw json.RawMessage
+			if err := decoder.Decode(&raw); err != nil {
+				if errors.Is(err, io.EOF) {
+					return nil, err
+				}
+				return nil, status.Errorf(codes.InvalidArgument, "invalid request body: %s", err)
+			}
+			check := &openfgav1.BatchCheckItem{}
+			if err := protojson.Unmarshal(raw, check); err != nil {
+				return nil, status.Errorf(codes.InvalidArgument, "invalid request body: %s", err)
+			}
+			return check, nil
+		},
+	}, func(correlationID string, result *openfgav1.BatchCheckSingleResult) error {
+		encoded, err := protojson.Marshal(result)
+		if err != nil {
+			return err
+		}
+		line := &streamedBatchCheckHTTPLine{}
+		line.Result.CorrelationID = correlationID
+		line.Result.Check = encoded
+		if err := stream.Write(line, nil); err != nil {
+			return err
+		}
+		// Checks can take long, so each result is sent as soon as it is known.
+		stream.flush()
+		return nil
+	})
+	if err == nil {
+		line := &streamedBatchCheckHTTPLine{}
+		line.Result.Metadata = res
+		err = stream.Write(line, nil)
+	}
+
+	return stream.Close("", false, err)
+}
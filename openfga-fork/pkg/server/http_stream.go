@@ -0,0 +1,156 @@
+package server
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+)
+
+const (
+	httpStreamFormatJSONLines = "jsonl"
+	httpStreamFormatCSV       = "csv"
+
+	// httpContinuationTokenTrailer carries the token of the next page of a streamed report.
+	httpContinuationTokenTrailer = "Openfga-Continuation-Token"
+	// httpErrorTrailer is set when a streamed report fails after its first row has been sent.
+	httpErrorTrailer = "Openfga-Error"
+	// httpTruncatedTrailer is "true" when some rows of a streamed report may be missing.
+	httpTruncatedTrailer = "Openfga-Truncated"
+
+	// httpStreamFlushInterval is the number of rows written between two flushes of the response.
+	httpStreamFlushInterval = 100
+)
+
+// httpRowStream writes the rows of a report as JSON Lines or CSV, depending on the "format" query
+// parameter. Nothing is sent before the first row, so that errors detected earlier are still
+// rendered as regular error responses. The continuation token, and any error that happens once
+// rows have been sent, are delivered as HTTP trailers, as is whether the rows were truncated.
+type httpRowStream struct {
+	w         http.ResponseWriter
+	format    string
+	csvHeader []string
+	csv       *csv.Writer
+	encoder   *json.Encoder
+	started   bool
+	rows      int
+}
+
+func newHTTPRowStream(w http.ResponseWriter, r *http.Request, csvHeader []string) (*httpRowStream, error) {
+	format := r.URL.Query().Get("format")
+	switch format {
+	case "":
+		format = httpStreamFormatJSONLines
+	case httpStreamFormatJSONLines, httpStreamFormatCSV:
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "unsupported format '%s', must be one of '%s' or '%s'",
+			format, httpStreamFormatJSONLines, httpStreamFormatCSV)
+	}
+
+	return &httpRowStream{w: w, format: format, csvHeader: csvHeader}, nil
+}
+
+func (s *httpRowStream) start() {
+	if s.started {
+		return
+	}
+	s.started = true
+
+	s.w.Header().Add("Trailer", httpContinuationTokenTrailer)
+	s.w.Header().Add("Trailer", httpErrorTrailer)
+	s.w.Header().Add("Trailer", httpTruncatedTrailer)
+	if s.format == httpStreamFormatCSV {
+		s.w.Header().Set("Content-Type", "text/csv")
+		s.w.WriteHeader(http.StatusOK)
+		s.csv = csv.NewWriter(s.w)
+		_ = s.csv.Write(s.csvHeader)
+		return
+	}
+
+	s.w.Header().Set("Content-Type", "application/x-ndjson")
+	s.w.WriteHeader(http.StatusOK)
+	s.encoder = json.NewEncoder(s.w)
+}
+
+// Write sends one row. record is used for JSON Lines and csvRecord for CSV.
+func (s *httpRowStream) Write(record any, csvRecord []string) error {
+	s.start()
+
+	var err error
+	if s.csv != nil {
+		err = s.csv.Write(csvRecord)
+	} else {
+		err = s.encoder.Encode(record)
+	}
+	if err != nil {
+		return err
+	}
+
+	s.rows++
+	if s.rows%httpStreamFlushInterval == 0 {
+		s.flush()
+	}
+	return nil
+}
+
+// Close ends the stream, reporting err if the rows could not all be produced, and truncated if some
+// rows may be missing. If nothing has been sent yet, err is returned so that it is rendered as an
+// error response instead.
+func (s *httpRowStream) Close(continuationToken string, truncated bool, err error) error {
+	if err != nil && !s.started {
+		return err
+	}
+
+	s.start()
+	s.flush()
+	if err != nil {
+		s.w.Header().Set(httpErrorTrailer, err.Error())
+		return nil
+	}
+	s.w.Header().Set(httpContinuationTokenTrailer, continuationToken)
+	s.w.Header().Set(httpTruncatedTrailer, strconv.FormatBool(truncated))
+	return nil
+}
+
+func (s *httpRowStream) flush() {
+	if s.csv != nil {
+		s.csv.Flush()
+	}
+	if flusher, ok := s.w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func queryInt32(r *http.Request, name string) (int32, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return 0, nil
+	}
+	parsed, err := strconv.ParseInt(value, 10, 32)
+	if err != nil {
+		return 0, status.Errorf(codes.InvalidArgument, "invalid %s '%s'", name, value)
+	}
+	return int32(parsed), nil
+}
+
+func queryConsistency(r *http.Request) (openfgav1.ConsistencyPreference, error) {
+	return parseConsistency(r.URL.Query().Get("consistency"))
+}
+
+// parseConsistency returns the consistency preference named value, or UNSPECIFIED if value is empty.
+func parseConsistency(value string) (openfgav1.ConsistencyPreference, error) {
+	if value == "" {
+		return openfgav1.ConsistencyPreference_UNSPECIFIED, nil
+	}
+	preference, ok := openfgav1.ConsistencyPreference_value[value]
+	if !ok {
+		return 0, status.Error(codes.InvalidArgument, fmt.Sprintf("invalid consistency '%s'", value))
+	}
+	return openfgav1.ConsistencyPreference(preference), nil
+}
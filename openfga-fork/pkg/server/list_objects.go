@@ -14,17 +14,36 @@ import (
 	openfgav1 "github.com/openfga/api/proto/openfga/v1"
 
 	"github.com/openfga/openfga/internal/condition"
+	"github.com/openfga/openfga/internal/condition/contextprovider"
 	"github.com/openfga/openfga/internal/throttler/threshold"
 	"github.com/openfga/openfga/internal/utils"
 	"github.com/openfga/openfga/internal/utils/apimethod"
+	"github.com/openfga/openfga/pkg/decisionlog"
 	"github.com/openfga/openfga/pkg/middleware/validator"
 	"github.com/openfga/openfga/pkg/server/commands"
 	serverErrors "github.com/openfga/openfga/pkg/server/errors"
+	"github.com/openfga/openfga/pkg/slowlog"
 	"github.com/openfga/openfga/pkg/telemetry"
 	"github.com/openfga/openfga/pkg/typesystem"
 )
 
 func (s *Server) ListObjects(ctx context.Context, req *openfgav1.ListObjectsRequest) (*openfgav1.ListObjectsResponse, error) {
+	return s.listObjects(ctx, req, nil)
+}
+
+// ListObjectsWithCandidates lists the objects of req among the candidate objects of req. The reads of the
+// tuples are restricted to the candidates where the model allows it, so that the request scales with the
+// number of candidates instead of the number of objects in the store.
+func (s *Server) ListObjectsWithCandidates(ctx context.Context, req *commands.ListObjectsCandidatesRequest) (*openfgav1.ListObjectsResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return s.listObjects(ctx, req.ListObjectsRequest, req.ObjectIDs)
+}
+
+// listObjects lists the objects of req. If candidateObjectIDs is not nil, only the objects with these IDs are
+// listed.
+func (s *Server) listObjects(ctx context.Context, req *openfgav1.ListObjectsRequest, candidateObjectIDs []string) (*openfgav1.ListObjectsResponse, error) {
 	start := time.Now()
 
 	targetObjectType := req.GetType()
@@ -35,6 +54,7 @@ func (s *Server) ListObjects(ctx context.Context, req *openfgav1.ListObjectsRequ
 		attribute.String("relation", req.GetRelation()),
 		attribute.String("user", req.GetUser()),
 		attribute.String("consistency", req.GetConsistency().String()),
+		attribute.Int("candidate_count", len(candidateObjectIDs)),
 	))
 	defer span.End()
 
@@ -57,6 +77,8 @@ func (s *Server) ListObjects(ctx context.Context, req *openfgav1.ListObjectsRequ
 		return nil, err
 	}
 
+	req.Context = s.trustedConditionContext(ctx, req.GetContext())
+
 	storeID := req.GetStoreId()
 
 	typesys, err := s.resolveTypesystem(ctx, storeID, req.GetAuthorizationModelId())
@@ -64,16 +86,9 @@ func (s *Server) ListObjects(ctx context.Context, req *openfgav1.ListObjectsRequ
 		return nil, err
 	}
 
-	q, err := commands.NewListObjectsQueryWithShadowConfig(
-		s.datastore,
-		s.listObjectsCheckResolver,
-		commands.NewShadowListObjectsQueryConfig(
-			commands.WithShadowListObjectsQueryEnabled(s.shadowListObjectsQueryEnabled),
-			commands.WithShadowListObjectsQuerySamplePercentage(s.shadowListObjectsQuerySamplePercentage),
-			commands.WithShadowListObjectsQueryTimeout(s.shadowListObjectsQueryTimeout),
-			commands.WithShadowListObjectsQueryMaxDeltaItems(s.shadowListObjectsQueryMaxDeltaItems),
-			commands.WithShadowListObjectsQueryLogger(s.logger),
-		),
+	storeLabel, modelLabel := telemetry.StoreLabelFromContext(ctx, storeID), telemetry.ModelLabelFromContext(ctx, storeID, typesys.GetAuthorizationModelID())
+
+	opts := []commands.ListObjectsQueryOption{
 		commands.WithLogger(s.logger),
 		commands.WithListObjectsDeadline(s.listObjectsDeadline),
 		commands.WithListObjectsMaxResults(s.listObjectsMaxResults),
@@ -88,7 +103,24 @@ func (s *Server) ListObjects(ctx context.Context, req *openfgav1.ListObjectsRequ
 		commands.WithMaxConcurrentReads(s.maxConcurrentReadsForListObjects),
 		commands.WithListObjectsCache(s.sharedDatastoreResources, s.cacheSettings),
 		commands.WithListObjectsDatastoreThrottler(s.listObjectsDatastoreThrottleThreshold, s.listObjectsDatastoreThrottleDuration),
+		commands.WithListObjectsAdaptiveLimiter(s.adaptiveLimiter),
 		commands.WithListObjectsOptimizationsEnabled(s.IsExperimentallyEnabled(ExperimentalListObjectsOptimizations)),
+	}
+	if candidateObjectIDs != nil {
+		opts = append(opts, commands.WithListObjectsCandidateObjectIDs(candidateObjectIDs))
+	}
+
+	q, err := commands.NewListObjectsQueryWithShadowConfig(
+		s.datastore,
+		s.listObjectsCheckResolver,
+		commands.NewShadowListObjectsQueryConfig(
+			commands.WithShadowListObjectsQueryEnabled(s.shadowListObjectsQueryEnabled),
+			commands.WithShadowListObjectsQuerySamplePercentage(s.shadowListObjectsQuerySamplePercentage),
+			commands.WithShadowListObjectsQueryTimeout(s.shadowListObjectsQueryTimeout),
+			commands.WithShadowListObjectsQueryMaxDeltaItems(s.shadowListObjectsQueryMaxDeltaItems),
+			commands.WithShadowListObjectsQueryLogger(s.logger),
+		),
+		opts...,
 	)
 	if err != nil {
 		return nil, serverErrors.NewInternalError("", err)
@@ -107,6 +139,15 @@ func (s *Server) ListObjects(ctx context.Context, req *openfgav1.ListObjectsRequ
 			Consistency:          req.GetConsistency(),
 		},
 	)
+
+	s.logSlowRequest(ctx, apimethod.ListObjects.String(), req, time.Since(start), func() *slowlog.Entry {
+		var metadata *commands.ListObjectsResolutionMetadata
+		if result != nil {
+			metadata = &result.ResolutionMetadata
+		}
+		return listObjectsSlowLogEntry(storeID, typesys.GetAuthorizationModelID(), metadata, err)
+	})
+
 	if err != nil {
 		telemetry.TraceError(span, err)
 		if errors.Is(err, condition.ErrEvaluationFailed) {
@@ -122,6 +163,8 @@ func (s *Server) ListObjects(ctx context.Context, req *openfgav1.ListObjectsRequ
 	datastoreQueryCountHistogram.WithLabelValues(
 		s.serviceName,
 		methodName,
+		storeLabel,
+		modelLabel,
 	).Observe(datastoreQueryCount)
 
 	dispatchCount := float64(result.ResolutionMetadata.DispatchCounter.Load())
@@ -131,6 +174,8 @@ func (s *Server) ListObjects(ctx context.Context, req *openfgav1.ListObjectsRequ
 	dispatchCountHistogram.WithLabelValues(
 		s.serviceName,
 		methodName,
+		storeLabel,
+		modelLabel,
 	).Observe(dispatchCount)
 
 	requestDurationHistogram.WithLabelValues(
@@ -139,11 +184,13 @@ func (s *Server) ListObjects(ctx context.Context, req *openfgav1.ListObjectsRequ
 		utils.Bucketize(uint(datastoreQueryCount), s.requestDurationByQueryHistogramBuckets),
 		utils.Bucketize(uint(result.ResolutionMetadata.DispatchCounter.Load()), s.requestDurationByDispatchCountHistogramBuckets),
 		req.GetConsistency().String(),
+		storeLabel,
+		modelLabel,
 	).Observe(float64(time.Since(start).Milliseconds()))
 
 	wasRequestThrottled := result.ResolutionMetadata.WasThrottled.Load()
 	if wasRequestThrottled {
-		throttledRequestCounter.WithLabelValues(s.serviceName, methodName).Inc()
+		throttledRequestCounter.WithLabelValues(s.serviceName, methodName, storeLabel, modelLabel).Inc()
 	}
 
 	listObjectsOptimzationLabel := "non-weighted"
@@ -155,6 +202,19 @@ func (s *Server) ListObjects(ctx context.Context, req *openfgav1.ListObjectsRequ
 	checkCounter := float64(result.ResolutionMetadata.CheckCounter.Load())
 	grpc_ctxtags.Extract(ctx).Set(listObjectsCheckCountName, checkCounter)
 
+	s.logDecision(ctx, &decisionlog.Decision{
+		Method:               apimethod.ListObjects.String(),
+		StoreID:              storeID,
+		AuthorizationModelID: typesys.GetAuthorizationModelID(),
+		User:                 req.GetUser(),
+		Relation:             req.GetRelation(),
+		ObjectType:           targetObjectType,
+		Objects:              result.Objects,
+		ContextualTupleCount: len(req.GetContextualTuples().GetTupleKeys()),
+		DispatchCount:        result.ResolutionMetadata.DispatchCounter.Load(),
+		DatastoreQueryCount:  result.ResolutionMetadata.DatastoreQueryCount.Load(),
+	}, req.GetContext())
+
 	return &openfgav1.ListObjectsResponse{
 		Objects: result.Objects,
 	}, nil
@@ -192,6 +252,8 @@ func (s *Server) StreamedListObjects(req *openfgav1.StreamedListObjectsRequest,
 		return err
 	}
 
+	req.Context = s.trustedConditionContext(ctx, req.GetContext())
+
 	storeID := req.GetStoreId()
 
 	typesys, err := s.resolveTypesystem(ctx, storeID, req.GetAuthorizationModelId())
@@ -199,6 +261,8 @@ func (s *Server) StreamedListObjects(req *openfgav1.StreamedListObjectsRequest,
 		return err
 	}
 
+	storeLabel, modelLabel := telemetry.StoreLabelFromContext(ctx, storeID), telemetry.ModelLabelFromContext(ctx, storeID, typesys.GetAuthorizationModelID())
+
 	q, err := commands.NewListObjectsQueryWithShadowConfig(
 		s.datastore,
 		s.listObjectsCheckResolver,
@@ -221,6 +285,7 @@ func (s *Server) StreamedListObjects(req *openfgav1.StreamedListObjectsRequest,
 		commands.WithResolveNodeLimit(s.resolveNodeLimit),
 		commands.WithResolveNodeBreadthLimit(s.resolveNodeBreadthLimit),
 		commands.WithMaxConcurrentReads(s.maxConcurrentReadsForListObjects),
+		commands.WithListObjectsAdaptiveLimiter(s.adaptiveLimiter),
 	)
 	if err != nil {
 		return serverErrors.NewInternalError("", err)
@@ -233,6 +298,11 @@ func (s *Server) StreamedListObjects(req *openfgav1.StreamedListObjectsRequest,
 		req,
 		srv,
 	)
+
+	s.logSlowRequest(ctx, apimethod.StreamedListObjects.String(), req, time.Since(start), func() *slowlog.Entry {
+		return listObjectsSlowLogEntry(storeID, typesys.GetAuthorizationModelID(), resolutionMetadata, err)
+	})
+
 	if err != nil {
 		telemetry.TraceError(span, err)
 		return err
@@ -244,6 +314,8 @@ func (s *Server) StreamedListObjects(req *openfgav1.StreamedListObjectsRequest,
 	datastoreQueryCountHistogram.WithLabelValues(
 		s.serviceName,
 		methodName,
+		storeLabel,
+		modelLabel,
 	).Observe(datastoreQueryCount)
 
 	dispatchCount := float64(resolutionMetadata.DispatchCounter.Load())
@@ -253,6 +325,8 @@ func (s *Server) StreamedListObjects(req *openfgav1.StreamedListObjectsRequest,
 	dispatchCountHistogram.WithLabelValues(
 		s.serviceName,
 		methodName,
+		storeLabel,
+		modelLabel,
 	).Observe(dispatchCount)
 
 	requestDurationHistogram.WithLabelValues(
@@ -261,12 +335,135 @@ func (s *Server) StreamedListObjects(req *openfgav1.StreamedListObjectsRequest,
 		utils.Bucketize(uint(datastoreQueryCount), s.requestDurationByQueryHistogramBuckets),
 		utils.Bucketize(uint(resolutionMetadata.DispatchCounter.Load()), s.requestDurationByDispatchCountHistogramBuckets),
 		req.GetConsistency().String(),
+		storeLabel,
+		modelLabel,
 	).Observe(float64(time.Since(start).Milliseconds()))
 
 	wasRequestThrottled := resolutionMetadata.WasThrottled.Load()
 	if wasRequestThrottled {
-		throttledRequestCounter.WithLabelValues(s.serviceName, methodName).Inc()
+		throttledRequestCounter.WithLabelValues(s.serviceName, methodName, storeLabel, modelLabel).Inc()
 	}
 
 	return nil
 }
+
+// ListObjectsPage returns one page of the objects of a ListObjects request, sorted by object ID. The pages that
+// follow are requested with the continuation token of the response, and are resolved with the same model.
+func (s *Server) ListObjectsPage(ctx context.Context, req *commands.ListObjectsPageRequest) (*commands.ListObjectsPageResponse, error) {
+	ctx, span := tracer.Start(ctx, "ListObjectsPage", trace.WithAttributes(
+		attribute.String("store_id", req.GetStoreId()),
+		attribute.String("object_type", req.GetType()),
+		attribute.String("relation", req.GetRelation()),
+		attribute.String("user", req.GetUser()),
+		attribute.String("consistency", req.GetConsistency().String()),
+		attribute.Int("page_size", int(req.PageSize)),
+	))
+	defer span.End()
+
+	if err := req.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	ctx = telemetry.ContextWithRPCInfo(ctx, telemetry.RPCInfo{
+		Service: s.serviceName,
+		Method:  "listobjects",
+	})
+
+	err := s.checkAuthz(ctx, req.GetStoreId(), apimethod.ListObjects)
+	if err != nil {
+		return nil, err
+	}
+
+	// The trusted values are merged into the context by the query, after the continuation token is checked
+	// against the context sent by the caller.
+	req.TrustedContextFields = contextprovider.Fields(ctx, s.conditionContextProviders)
+
+	modelID := req.GetAuthorizationModelId()
+	if modelID == "" {
+		// The following pages are resolved with the model of the first page.
+		modelID, err = commands.ListObjectsPageAuthorizationModelID(req.ContinuationToken)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	typesys, err := s.resolveTypesystem(ctx, req.GetStoreId(), modelID)
+	if err != nil {
+		return nil, err
+	}
+
+	q, err := commands.NewListObjectsQuery(
+		s.datastore,
+		s.listObjectsCheckResolver,
+		commands.WithLogger(s.logger),
+		commands.WithListObjectsDeadline(s.listObjectsDeadline),
+		commands.WithDispatchThrottlerConfig(threshold.Config{
+			Throttler:    s.listObjectsDispatchThrottler,
+			Enabled:      s.listObjectsDispatchThrottlingEnabled,
+			Threshold:    s.listObjectsDispatchDefaultThreshold,
+			MaxThreshold: s.listObjectsDispatchThrottlingMaxThreshold,
+		}),
+		commands.WithResolveNodeLimit(s.resolveNodeLimit),
+		commands.WithResolveNodeBreadthLimit(s.resolveNodeBreadthLimit),
+		commands.WithMaxConcurrentReads(s.maxConcurrentReadsForListObjects),
+		commands.WithListObjectsCache(s.sharedDatastoreResources, s.cacheSettings),
+		commands.WithListObjectsDatastoreThrottler(s.listObjectsDatastoreThrottleThreshold, s.listObjectsDatastoreThrottleDuration),
+		commands.WithListObjectsAdaptiveLimiter(s.adaptiveLimiter),
+		commands.WithListObjectsOptimizationsEnabled(s.IsExperimentallyEnabled(ExperimentalListObjectsOptimizations)),
+	)
+	if err != nil {
+		return nil, serverErrors.NewInternalError("", err)
+	}
+
+	req.AuthorizationModelId = typesys.GetAuthorizationModelID() // the resolved model id
+
+	result, err := q.ExecutePage(typesystem.ContextWithTypesystem(ctx, typesys), req)
+	if err != nil {
+		telemetry.TraceError(span, err)
+		return nil, err
+	}
+
+	datastoreQueryCount := float64(result.ResolutionMetadata.DatastoreQueryCount.Load())
+	grpc_ctxtags.Extract(ctx).Set(datastoreQueryCountHistogramName, datastoreQueryCount)
+	span.SetAttributes(attribute.Float64(datastoreQueryCountHistogramName, datastoreQueryCount))
+
+	dispatchCount := float64(result.ResolutionMetadata.DispatchCounter.Load())
+	grpc_ctxtags.Extract(ctx).Set(dispatchCountHistogramName, dispatchCount)
+	span.SetAttributes(attribute.Float64(dispatchCountHistogramName, dispatchCount))
+
+	s.logDecision(ctx, &decisionlog.Decision{
+		Method:               apimethod.ListObjects.String(),
+		StoreID:              req.GetStoreId(),
+		AuthorizationModelID: typesys.GetAuthorizationModelID(),
+		User:                 req.GetUser(),
+		Relation:             req.GetRelation(),
+		ObjectType:           req.GetType(),
+		Objects:              result.Objects,
+		ContextualTupleCount: len(req.GetContextualTuples().GetTupleKeys()),
+		DispatchCount:        result.ResolutionMetadata.DispatchCounter.Load(),
+		DatastoreQueryCount:  result.ResolutionMetadata.DatastoreQueryCount.Load(),
+	}, req.GetContext())
+
+	return result, nil
+}
+
+// listObjectsSlowLogEntry returns the slow request log entry of a ListObjects or StreamedListObjects request. The
+// resolution metadata is nil if the request failed before it was resolved.
+func listObjectsSlowLogEntry(storeID, modelID string, metadata *commands.ListObjectsResolutionMetadata, err error) *slowlog.Entry {
+	entry := &slowlog.Entry{
+		StoreID:              storeID,
+		AuthorizationModelID: modelID,
+	}
+	if metadata != nil {
+		entry.DispatchCount = metadata.DispatchCounter.Load()
+		entry.DatastoreQueryCount = metadata.DatastoreQueryCount.Load()
+		entry.Resolvers = []string{"non-weighted"}
+		if metadata.WasWeightedGraphUsed.Load() {
+			entry.Resolvers = []string{"weighted"}
+		}
+	}
+	if err != nil {
+		entry.Error = err.Error()
+	}
+	return entry
+}
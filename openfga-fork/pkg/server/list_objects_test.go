@@ -0,0 +1,134 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+	language "github.com/openfga/language/pkg/go/transformer"
+
+	"github.com/openfga/openfga/pkg/server/commands"
+	"github.com/openfga/openfga/pkg/tuple"
+)
+
+func TestListObjectsPage(t *testing.T) {
+	ctx := context.Background()
+
+	s, _, storeID := newTestServer(t)
+
+	writeModel := func(dsl string) {
+		model := language.MustTransformDSLToProto(dsl)
+		_, err := s.WriteAuthorizationModel(ctx, &openfgav1.WriteAuthorizationModelRequest{
+			StoreId:         storeID,
+			SchemaVersion:   model.GetSchemaVersion(),
+			TypeDefinitions: model.GetTypeDefinitions(),
+		})
+		require.NoError(t, err)
+	}
+
+	writeModel(`
+		model
+			schema 1.1
+		type user
+		type document
+			relations
+				define viewer: [user]`)
+
+	_, err := s.Write(ctx, &openfgav1.WriteRequest{
+		StoreId: storeID,
+		Writes: &openfgav1.WriteRequestWrites{TupleKeys: []*openfgav1.TupleKey{
+			tuple.NewTupleKey("document:3", "viewer", "user:anne"),
+			tuple.NewTupleKey("document:1", "viewer", "user:anne"),
+			tuple.NewTupleKey("document:2", "viewer", "user:anne"),
+		}},
+	})
+	require.NoError(t, err)
+
+	mux := newTestHTTPMux(t, s)
+
+	readPage := func(body string) (int, *commands.ListObjectsPageResponse) {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/stores/"+storeID+"/list-objects/pages", strings.NewReader(body)))
+		var resp commands.ListObjectsPageResponse
+		if rec.Code == http.StatusOK {
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		}
+		return rec.Code, &resp
+	}
+
+	code, resp := readPage(`{"type": "document", "relation": "viewer", "user": "user:anne", "page_size": 2}`)
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, []string{"document:1", "document:2"}, resp.Objects)
+	require.NotEmpty(t, resp.ContinuationToken)
+
+	// The following pages are resolved with the model of the first page, even if a new model is written.
+	writeModel(`
+		model
+			schema 1.1
+		type user
+		type document
+			relations
+				define owner: [user]
+				define viewer: owner`)
+
+	code, resp = readPage(`{"type": "document", "relation": "viewer", "user": "user:anne", "page_size": 2, "continuation_token": "` + resp.ContinuationToken + `"}`)
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, []string{"document:3"}, resp.Objects)
+	require.Empty(t, resp.ContinuationToken)
+
+	code, _ = readPage(`{"type": "document", "relation": "viewer", "user": "user:anne", "page_size": 2000}`)
+	require.Equal(t, http.StatusBadRequest, code)
+
+	code, _ = readPage(`{"type": "document", "relation": "viewer", "user": "user:anne", "continuation_token": "invalid"}`)
+	require.Equal(t, http.StatusBadRequest, code)
+}
+
+func TestListObjectsWithCandidates(t *testing.T) {
+	ctx := context.Background()
+
+	s, _, storeID := newTestServer(t)
+
+	writeTestModel(t, s, storeID, `
+		model
+			schema 1.1
+		type user
+		type menu_item
+			relations
+				define viewer: [user]`)
+
+	_, err := s.Write(ctx, &openfgav1.WriteRequest{
+		StoreId: storeID,
+		Writes: &openfgav1.WriteRequestWrites{TupleKeys: []*openfgav1.TupleKey{
+			tuple.NewTupleKey("menu_item:1", "viewer", "user:anne"),
+			tuple.NewTupleKey("menu_item:2", "viewer", "user:anne"),
+			tuple.NewTupleKey("menu_item:3", "viewer", "user:anne"),
+		}},
+	})
+	require.NoError(t, err)
+
+	mux := newTestHTTPMux(t, s)
+
+	listObjects := func(body string) (int, *openfgav1.ListObjectsResponse) {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/stores/"+storeID+"/list-objects/candidates", strings.NewReader(body)))
+		resp := &openfgav1.ListObjectsResponse{}
+		if rec.Code == http.StatusOK {
+			require.NoError(t, protojson.Unmarshal(rec.Body.Bytes(), resp))
+		}
+		return rec.Code, resp
+	}
+
+	code, resp := listObjects(`{"type": "menu_item", "relation": "viewer", "user": "user:anne", "object_ids": ["2", "3", "4"]}`)
+	require.Equal(t, http.StatusOK, code)
+	require.ElementsMatch(t, []string{"menu_item:2", "menu_item:3"}, resp.GetObjects())
+
+	code, _ = listObjects(`{"type": "menu_item", "relation": "viewer", "user": "user:anne"}`)
+	require.Equal(t, http.StatusBadRequest, code)
+}
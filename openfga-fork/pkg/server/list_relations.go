@@ -0,0 +1,115 @@
+package server
+
+import (
+	"context"
+	"time"
+
+	grpc_ctxtags "github.com/grpc-ecosystem/go-grpc-middleware/tags"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/openfga/openfga/internal/utils"
+	"github.com/openfga/openfga/internal/utils/apimethod"
+	"github.com/openfga/openfga/pkg/server/commands"
+	"github.com/openfga/openfga/pkg/telemetry"
+)
+
+// ListRelations returns the relations that a user has with an object, among the requested relations or, if
+// none is requested, among all the relations of the type of the object. It is equivalent to one Check per
+// relation, with the tuples read once for all the relations.
+func (s *Server) ListRelations(ctx context.Context, req *commands.ListRelationsRequest) (*commands.ListRelationsResponse, error) {
+	const methodName = "listrelations"
+
+	start := time.Now()
+
+	ctx, span := tracer.Start(ctx, apimethod.ListRelations.String(), trace.WithAttributes(
+		attribute.String("store_id", req.StoreID),
+		attribute.String("object", req.Object),
+		attribute.String("user", req.User),
+		attribute.StringSlice("relations", req.Relations),
+		attribute.String("consistency", req.Consistency.String()),
+	))
+	defer span.End()
+
+	if err := req.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	ctx = telemetry.ContextWithRPCInfo(ctx, telemetry.RPCInfo{
+		Service: s.serviceName,
+		Method:  apimethod.ListRelations.String(),
+	})
+
+	err := s.checkAuthz(ctx, req.StoreID, apimethod.ListRelations)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Context = s.trustedConditionContext(ctx, req.Context)
+
+	typesys, err := s.resolveTypesystem(ctx, req.StoreID, req.AuthorizationModelID)
+	if err != nil {
+		return nil, err
+	}
+
+	storeLabel, modelLabel := telemetry.StoreLabelFromContext(ctx, req.StoreID), telemetry.ModelLabelFromContext(ctx, req.StoreID, typesys.GetAuthorizationModelID())
+
+	q := commands.NewListRelationsQuery(
+		s.datastore,
+		s.checkResolver,
+		typesys,
+		commands.WithListRelationsLogger(s.logger),
+		commands.WithListRelationsCache(s.sharedDatastoreResources, s.cacheSettings),
+		commands.WithListRelationsMaxConcurrentReads(s.maxConcurrentReadsForCheck),
+		commands.WithListRelationsMaxConcurrentChecks(s.maxConcurrentChecksPerBatch),
+		commands.WithListRelationsDatastoreThrottler(s.checkDatastoreThrottleThreshold, s.checkDatastoreThrottleDuration),
+		commands.WithListRelationsAdaptiveLimiter(s.adaptiveLimiter),
+	)
+
+	resp, err := q.Execute(ctx, req)
+	if err != nil {
+		telemetry.TraceError(span, err)
+		return nil, commands.CheckCommandErrorToServerError(err)
+	}
+
+	metadata := resp.ResolutionMetadata
+	span.SetAttributes(attribute.Int("skipped_relation_count", metadata.SkippedRelationCount))
+
+	queryCount := float64(metadata.DatastoreQueryCount)
+	grpc_ctxtags.Extract(ctx).Set(datastoreQueryCountHistogramName, queryCount)
+	span.SetAttributes(attribute.Float64(datastoreQueryCountHistogramName, queryCount))
+	datastoreQueryCountHistogram.WithLabelValues(
+		s.serviceName,
+		methodName,
+		storeLabel,
+		modelLabel,
+	).Observe(queryCount)
+
+	dispatchCount := float64(metadata.DispatchCount)
+	grpc_ctxtags.Extract(ctx).Set(dispatchCountHistogramName, dispatchCount)
+	span.SetAttributes(attribute.Float64(dispatchCountHistogramName, dispatchCount))
+	dispatchCountHistogram.WithLabelValues(
+		s.serviceName,
+		methodName,
+		storeLabel,
+		modelLabel,
+	).Observe(dispatchCount)
+
+	requestDurationHistogram.WithLabelValues(
+		s.serviceName,
+		methodName,
+		utils.Bucketize(uint(metadata.DatastoreQueryCount), s.requestDurationByQueryHistogramBuckets),
+		utils.Bucketize(uint(metadata.DispatchCount), s.requestDurationByDispatchCountHistogramBuckets),
+		req.Consistency.String(),
+		storeLabel,
+		modelLabel,
+	).Observe(float64(time.Since(start).Milliseconds()))
+
+	if metadata.WasThrottled {
+		throttledRequestCounter.WithLabelValues(s.serviceName, methodName, storeLabel, modelLabel).Inc()
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,85 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+
+	"github.com/openfga/openfga/pkg/server/commands"
+	"github.com/openfga/openfga/pkg/tuple"
+)
+
+func TestListRelations(t *testing.T) {
+	ctx := context.Background()
+
+	s, _, storeID := newTestServer(t)
+
+	writeTestModel(t, s, storeID, `
+		model
+			schema 1.1
+		type user
+		type document
+			relations
+				define owner: [user]
+				define editor: [user] or owner
+				define viewer: [user] or editor
+				define commenter: [user with in_office]
+		condition in_office(office: bool) {
+			office
+		}`)
+
+	_, err := s.Write(ctx, &openfgav1.WriteRequest{
+		StoreId: storeID,
+		Writes: &openfgav1.WriteRequestWrites{TupleKeys: []*openfgav1.TupleKey{
+			tuple.NewTupleKey("document:1", "editor", "user:anne"),
+			tuple.NewTupleKeyWithCondition("document:1", "commenter", "user:anne", "in_office", nil),
+		}},
+	})
+	require.NoError(t, err)
+
+	t.Run("server", func(t *testing.T) {
+		resp, err := s.ListRelations(ctx, &commands.ListRelationsRequest{
+			StoreID:   storeID,
+			Object:    "document:1",
+			User:      "user:anne",
+			Relations: []string{"owner", "editor", "viewer"},
+		})
+		require.NoError(t, err)
+		require.Equal(t, []string{"editor", "viewer"}, resp.Relations)
+	})
+
+	mux := newTestHTTPMux(t, s)
+
+	listRelations := func(body string) (int, []string) {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/stores/"+storeID+"/list-relations", strings.NewReader(body)))
+		var resp struct {
+			Relations []string `json:"relations"`
+		}
+		if rec.Code == http.StatusOK {
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		}
+		return rec.Code, resp.Relations
+	}
+
+	code, relations := listRelations(`{"object": "document:1", "user": "user:anne", "relations": ["commenter", "viewer", "owner"], "context": {"office": true}}`)
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, []string{"commenter", "viewer"}, relations)
+
+	code, relations = listRelations(`{"object": "document:1", "user": "user:bob", "context": {"office": false}, "contextual_tuples": {"tuple_keys": [{"object": "document:1", "relation": "owner", "user": "user:bob"}]}}`)
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, []string{"editor", "owner", "viewer"}, relations)
+
+	code, _ = listRelations(`{"object": "document:1", "user": "user:anne", "relations": ["unknown"]}`)
+	require.Equal(t, http.StatusBadRequest, code)
+
+	code, _ = listRelations(`{"object": "document", "user": "user:anne"}`)
+	require.Equal(t, http.StatusBadRequest, code)
+}
@@ -62,11 +62,15 @@ func (s *Server) ListUsers(
 		return nil, err
 	}
 
+	req.Context = s.trustedConditionContext(ctx, req.GetContext())
+
 	typesys, err := s.resolveTypesystem(ctx, req.GetStoreId(), req.GetAuthorizationModelId())
 	if err != nil {
 		return nil, err
 	}
 
+	storeLabel, modelLabel := telemetry.StoreLabelFromContext(ctx, req.GetStoreId()), telemetry.ModelLabelFromContext(ctx, req.GetStoreId(), typesys.GetAuthorizationModelID())
+
 	err = listusers.ValidateListUsersRequest(ctx, req, typesys)
 	if err != nil {
 		return nil, err
@@ -74,35 +78,12 @@ func (s *Server) ListUsers(
 
 	ctx = typesystem.ContextWithTypesystem(ctx, typesys)
 
-	listUsersQuery := listusers.NewListUsersQuery(s.datastore,
-		req.GetContextualTuples(),
-		listusers.WithResolveNodeLimit(s.resolveNodeLimit),
-		listusers.WithResolveNodeBreadthLimit(s.resolveNodeBreadthLimit),
-		listusers.WithListUsersQueryLogger(s.logger),
-		listusers.WithListUsersMaxResults(s.listUsersMaxResults),
-		listusers.WithListUsersDeadline(s.listUsersDeadline),
-		listusers.WithListUsersMaxConcurrentReads(s.maxConcurrentReadsForListUsers),
-		listusers.WithDispatchThrottlerConfig(threshold.Config{
-			Throttler:    s.listUsersDispatchThrottler,
-			Enabled:      s.listUsersDispatchThrottlingEnabled,
-			Threshold:    s.listUsersDispatchDefaultThreshold,
-			MaxThreshold: s.listUsersDispatchThrottlingMaxThreshold,
-		}),
-		listusers.WithListUsersDatastoreThrottler(s.listUsersDatastoreThrottleThreshold, s.listUsersDatastoreThrottleDuration),
-	)
+	listUsersQuery := listusers.NewListUsersQuery(s.datastore, req.GetContextualTuples(), s.listUsersQueryOptions()...)
 
 	resp, err := listUsersQuery.ListUsers(ctx, req)
 	if err != nil {
 		telemetry.TraceError(span, err)
-
-		switch {
-		case errors.Is(err, graph.ErrResolutionDepthExceeded):
-			return nil, serverErrors.ErrAuthorizationModelResolutionTooComplex
-		case errors.Is(err, condition.ErrEvaluationFailed):
-			return nil, serverErrors.ValidationError(err)
-		default:
-			return nil, serverErrors.HandleError("", err)
-		}
+		return nil, translateListUsersError(err)
 	}
 
 	datastoreQueryCount := float64(resp.Metadata.DatastoreQueryCount)
@@ -112,6 +93,8 @@ func (s *Server) ListUsers(
 	datastoreQueryCountHistogram.WithLabelValues(
 		s.serviceName,
 		methodName,
+		storeLabel,
+		modelLabel,
 	).Observe(datastoreQueryCount)
 
 	dispatchCount := float64(resp.Metadata.DispatchCounter.Load())
@@ -120,6 +103,8 @@ func (s *Server) ListUsers(
 	dispatchCountHistogram.WithLabelValues(
 		s.serviceName,
 		methodName,
+		storeLabel,
+		modelLabel,
 	).Observe(dispatchCount)
 
 	requestDurationHistogram.WithLabelValues(
@@ -128,11 +113,13 @@ func (s *Server) ListUsers(
 		utils.Bucketize(uint(datastoreQueryCount), s.requestDurationByQueryHistogramBuckets),
 		utils.Bucketize(uint(dispatchCount), s.requestDurationByDispatchCountHistogramBuckets),
 		req.GetConsistency().String(),
+		storeLabel,
+		modelLabel,
 	).Observe(float64(time.Since(start).Milliseconds()))
 
 	wasRequestThrottled := resp.GetMetadata().WasThrottled.Load()
 	if wasRequestThrottled {
-		throttledRequestCounter.WithLabelValues(s.serviceName, methodName).Inc()
+		throttledRequestCounter.WithLabelValues(s.serviceName, methodName, storeLabel, modelLabel).Inc()
 	}
 
 	return &openfgav1.ListUsersResponse{
@@ -140,6 +127,137 @@ func (s *Server) ListUsers(
 	}, nil
 }
 
+// StreamedListUsers sends to emit the users that have a relation with an object as they are found, each user
+// once. Unlike ListUsers, the number of users is not limited by listUsersMaxResults; the stream ends when all
+// the users have been found or at the ListUsers deadline, which the returned metadata reports.
+func (s *Server) StreamedListUsers(
+	ctx context.Context,
+	req *openfgav1.ListUsersRequest,
+	emit func(*openfgav1.User) error,
+) (*listusers.StreamedListUsersMetadata, error) {
+	start := time.Now()
+	ctx, span := tracer.Start(ctx, apimethod.StreamedListUsers.String(), trace.WithAttributes(
+		attribute.String("store_id", req.GetStoreId()),
+		attribute.String("object", tuple.BuildObject(req.GetObject().GetType(), req.GetObject().GetId())),
+		attribute.String("relation", req.GetRelation()),
+		attribute.String("user_filters", userFiltersToString(req.GetUserFilters())),
+		attribute.String("consistency", req.GetConsistency().String()),
+	))
+	defer span.End()
+
+	if err := req.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	const methodName = "streamedlistusers"
+
+	ctx = telemetry.ContextWithRPCInfo(ctx, telemetry.RPCInfo{
+		Service: s.serviceName,
+		Method:  methodName,
+	})
+
+	err := s.checkAuthz(ctx, req.GetStoreId(), apimethod.StreamedListUsers)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Context = s.trustedConditionContext(ctx, req.GetContext())
+
+	typesys, err := s.resolveTypesystem(ctx, req.GetStoreId(), req.GetAuthorizationModelId())
+	if err != nil {
+		return nil, err
+	}
+
+	storeLabel, modelLabel := telemetry.StoreLabelFromContext(ctx, req.GetStoreId()), telemetry.ModelLabelFromContext(ctx, req.GetStoreId(), typesys.GetAuthorizationModelID())
+
+	err = listusers.ValidateListUsersRequest(ctx, req, typesys)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx = typesystem.ContextWithTypesystem(ctx, typesys)
+
+	// Unlike ListUsers, the stream is not capped by listUsersMaxResults, so its datastore reads are throttled.
+	opts := append(s.listUsersQueryOptions(),
+		listusers.WithListUsersDatastoreThrottler(s.listUsersDatastoreThrottleThreshold, s.listUsersDatastoreThrottleDuration),
+	)
+	listUsersQuery := listusers.NewListUsersQuery(s.datastore, req.GetContextualTuples(), opts...)
+
+	metadata, err := listUsersQuery.StreamedListUsers(ctx, req, emit)
+	if err != nil {
+		telemetry.TraceError(span, err)
+		return nil, translateListUsersError(err)
+	}
+	span.SetAttributes(attribute.Bool("deadline_exceeded", metadata.DeadlineExceeded))
+
+	datastoreQueryCount := float64(metadata.DatastoreQueryCount)
+	grpc_ctxtags.Extract(ctx).Set(datastoreQueryCountHistogramName, datastoreQueryCount)
+	span.SetAttributes(attribute.Float64(datastoreQueryCountHistogramName, datastoreQueryCount))
+	datastoreQueryCountHistogram.WithLabelValues(
+		s.serviceName,
+		methodName,
+		storeLabel,
+		modelLabel,
+	).Observe(datastoreQueryCount)
+
+	dispatchCount := float64(metadata.DispatchCount)
+	grpc_ctxtags.Extract(ctx).Set(dispatchCountHistogramName, dispatchCount)
+	span.SetAttributes(attribute.Float64(dispatchCountHistogramName, dispatchCount))
+	dispatchCountHistogram.WithLabelValues(
+		s.serviceName,
+		methodName,
+		storeLabel,
+		modelLabel,
+	).Observe(dispatchCount)
+
+	requestDurationHistogram.WithLabelValues(
+		s.serviceName,
+		methodName,
+		utils.Bucketize(uint(datastoreQueryCount), s.requestDurationByQueryHistogramBuckets),
+		utils.Bucketize(uint(dispatchCount), s.requestDurationByDispatchCountHistogramBuckets),
+		req.GetConsistency().String(),
+		storeLabel,
+		modelLabel,
+	).Observe(float64(time.Since(start).Milliseconds()))
+
+	if metadata.WasThrottled {
+		throttledRequestCounter.WithLabelValues(s.serviceName, methodName, storeLabel, modelLabel).Inc()
+	}
+
+	return metadata, nil
+}
+
+// listUsersQueryOptions returns the options that apply the ListUsers limits, deadline and dispatch throttling
+// configured on the server.
+func (s *Server) listUsersQueryOptions() []listusers.ListUsersQueryOption {
+	return []listusers.ListUsersQueryOption{
+		listusers.WithResolveNodeLimit(s.resolveNodeLimit),
+		listusers.WithResolveNodeBreadthLimit(s.resolveNodeBreadthLimit),
+		listusers.WithListUsersQueryLogger(s.logger),
+		listusers.WithListUsersMaxResults(s.listUsersMaxResults),
+		listusers.WithListUsersDeadline(s.listUsersDeadline),
+		listusers.WithListUsersMaxConcurrentReads(s.maxConcurrentReadsForListUsers),
+		listusers.WithDispatchThrottlerConfig(threshold.Config{
+			Throttler:    s.listUsersDispatchThrottler,
+			Enabled:      s.listUsersDispatchThrottlingEnabled,
+			Threshold:    s.listUsersDispatchDefaultThreshold,
+			MaxThreshold: s.listUsersDispatchThrottlingMaxThreshold,
+		}),
+		listusers.WithListUsersAdaptiveLimiter(s.adaptiveLimiter),
+	}
+}
+
+func translateListUsersError(err error) error {
+	switch {
+	case errors.Is(err, graph.ErrResolutionDepthExceeded):
+		return serverErrors.ErrAuthorizationModelResolutionTooComplex
+	case errors.Is(err, condition.ErrEvaluationFailed):
+		return serverErrors.ValidationError(err)
+	default:
+		return serverErrors.HandleError("", err)
+	}
+}
+
 func userFiltersToString(filter []*openfgav1.UserTypeFilter) string {
 	var s strings.Builder
 	for _, f := range filter {
@@ -1,22 +1,38 @@
 package server
 
 import (
+	"bufio"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
 	"github.com/oklog/ulid/v2"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/goleak"
 	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/encoding/protojson"
 
 	openfgav1 "github.com/openfga/api/proto/openfga/v1"
 	parser "github.com/openfga/language/pkg/go/transformer"
 
+	"github.com/openfga/openfga/internal/authn"
 	mockstorage "github.com/openfga/openfga/internal/mocks"
+	"github.com/openfga/openfga/pkg/server/commands/listusers"
 	serverErrors "github.com/openfga/openfga/pkg/server/errors"
 	"github.com/openfga/openfga/pkg/storage"
 	"github.com/openfga/openfga/pkg/storage/memory"
@@ -197,6 +213,7 @@ func TestModelIdNotFound(t *testing.T) {
 	defer mockController.Finish()
 
 	mockDatastore := mockstorage.NewMockOpenFGADatastore(mockController)
+	mockDatastore.EXPECT().ReadActiveAuthorizationModelID(gomock.Any(), gomock.Any()).Return("", storage.ErrNotFound)
 	mockDatastore.EXPECT().FindLatestAuthorizationModel(gomock.Any(), gomock.Any()).Return(nil, storage.ErrNotFound)
 
 	server := MustNewServerWithOpts(
@@ -246,6 +263,7 @@ func TestExperimentalListUsers(t *testing.T) {
 	})
 
 	t.Run("list_users_returns_error_if_latest_model_not_found", func(t *testing.T) {
+		mockDatastore.EXPECT().ReadActiveAuthorizationModelID(gomock.Any(), gomock.Any()).Return("", storage.ErrNotFound)
 		mockDatastore.EXPECT().FindLatestAuthorizationModel(gomock.Any(), gomock.Any()).Return(nil, storage.ErrNotFound) // error demonstrates that main code path is reached
 
 		_, err := server.ListUsers(ctx, req)
@@ -279,6 +297,147 @@ func TestExperimentalListUsers(t *testing.T) {
 	})
 }
 
+func TestStreamedListUsers(t *testing.T) {
+	t.Cleanup(func() {
+		goleak.VerifyNone(t)
+	})
+
+	ds := memory.New()
+	s := MustNewServerWithOpts(WithDatastore(ds), WithListUsersMaxResults(1))
+	t.Cleanup(s.Close)
+
+	storeID, _ := test.BootstrapFGAStore(t, ds, `
+		model
+			schema 1.1
+		type user
+		type document
+			relations
+				define owner: [user]
+				define viewer: [user] or owner`,
+		[]string{
+			"document:1#owner@user:anne",
+			"document:1#viewer@user:anne",
+			"document:1#viewer@user:bob",
+		})
+
+	mux := runtime.NewServeMux()
+	require.NoError(t, s.RegisterHTTPHandlers(mux, authn.NoopAuthenticator{}))
+
+	streamUsers := func(body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/stores/"+storeID+"/streamed-list-users", strings.NewReader(body)))
+		return rec
+	}
+
+	rec := streamUsers(`{"object": {"type": "document", "id": "1"}, "relation": "viewer", "user_filters": [{"type": "user"}]}`)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, "application/x-ndjson", rec.Header().Get("Content-Type"))
+
+	var lines []map[string]map[string]json.RawMessage
+	scanner := bufio.NewScanner(rec.Body)
+	for scanner.Scan() {
+		var line map[string]map[string]json.RawMessage
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &line))
+		lines = append(lines, line)
+	}
+	require.Len(t, lines, 3)
+
+	var users []string
+	for _, line := range lines[:2] {
+		user := &openfgav1.User{}
+		require.NoError(t, protojson.Unmarshal(line["result"]["user"], user))
+		users = append(users, tuple.UserProtoToString(user))
+	}
+	require.ElementsMatch(t, []string{"user:anne", "user:bob"}, users)
+	metadata := &listusers.StreamedListUsersMetadata{}
+	require.NoError(t, json.Unmarshal(lines[2]["result"]["metadata"], metadata))
+	require.Equal(t, uint32(2), metadata.UserCount)
+	require.False(t, metadata.DeadlineExceeded)
+
+	rec = streamUsers(`{"object": {"type": "document", "id": "1"}, "relation": "unknown", "user_filters": [{"type": "user"}]}`)
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = streamUsers(`{"invalid": true}`)
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestStreamedListUsersGRPC(t *testing.T) {
+	t.Cleanup(func() {
+		goleak.VerifyNone(t)
+	})
+
+	ds := memory.New()
+	s := MustNewServerWithOpts(WithDatastore(ds))
+	t.Cleanup(s.Close)
+
+	storeID, _ := test.BootstrapFGAStore(t, ds, `
+		model
+			schema 1.1
+		type user
+		type document
+			relations
+				define viewer: [user]`,
+		[]string{
+			"document:1#viewer@user:anne",
+			"document:1#viewer@user:bob",
+		})
+
+	listener := bufconn.Listen(1024 * 1024)
+	grpcServer := grpc.NewServer()
+	RegisterStreamedListUsersServer(grpcServer, s)
+	go func() {
+		_ = grpcServer.Serve(listener)
+	}()
+	t.Cleanup(grpcServer.Stop)
+
+	conn, err := grpc.NewClient("passthrough://bufconn",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return listener.Dial() }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	streamUsers := func(req *openfgav1.ListUsersRequest) ([]string, metadata.MD, error) {
+		stream, err := conn.NewStream(context.Background(), &StreamedListUsersServiceDesc.Streams[0],
+			"/"+StreamedListUsersServiceDesc.ServiceName+"/StreamedListUsers")
+		require.NoError(t, err)
+		require.NoError(t, stream.SendMsg(req))
+		require.NoError(t, stream.CloseSend())
+
+		var users []string
+		for {
+			res := &openfgav1.ListUsersResponse{}
+			if err := stream.RecvMsg(res); err != nil {
+				if errors.Is(err, io.EOF) {
+					return users, stream.Trailer(), nil
+				}
+				return nil, nil, err
+			}
+			require.Len(t, res.GetUsers(), 1)
+			users = append(users, tuple.UserProtoToString(res.GetUsers()[0]))
+		}
+	}
+
+	users, trailer, err := streamUsers(&openfgav1.ListUsersRequest{
+		StoreId:     storeID,
+		Object:      &openfgav1.Object{Type: "document", Id: "1"},
+		Relation:    "viewer",
+		UserFilters: []*openfgav1.UserTypeFilter{{Type: "user"}},
+	})
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"user:anne", "user:bob"}, users)
+	require.Equal(t, []string{"2"}, trailer.Get(StreamedListUsersUserCountTrailer))
+	require.Equal(t, []string{"false"}, trailer.Get(StreamedListUsersDeadlineExceededTrailer))
+
+	_, _, err = streamUsers(&openfgav1.ListUsersRequest{
+		StoreId:     storeID,
+		Object:      &openfgav1.Object{Type: "document", Id: "1"},
+		Relation:    "unknown",
+		UserFilters: []*openfgav1.UserTypeFilter{{Type: "user"}},
+	})
+	require.ErrorContains(t, err, "relation 'document#unknown' not found")
+}
+
 func TestListUsers_ErrorCases(t *testing.T) {
 	t.Cleanup(func() {
 		goleak.VerifyNone(t)
@@ -0,0 +1,84 @@
+package server
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+
+	"github.com/openfga/openfga/internal/utils/apimethod"
+	"github.com/openfga/openfga/pkg/server/commands"
+	"github.com/openfga/openfga/pkg/server/commands/listusers"
+	"github.com/openfga/openfga/pkg/telemetry"
+	"github.com/openfga/openfga/pkg/typesystem"
+)
+
+// PermissionMatrix reports which users have which relations with the objects of the requested types.
+// Rows are passed to emit as they are resolved; the returned continuation token resumes the report.
+// Every (object, relation) cell is resolved with ListUsers, and so is subject to the ListUsers
+// deadline, result limit and throttling configured on the server. The response is truncated when
+// they cut the users of a cell.
+func (s *Server) PermissionMatrix(
+	ctx context.Context,
+	req *commands.PermissionMatrixRequest,
+	emit func(*commands.PermissionMatrixRow) error,
+) (*commands.PermissionMatrixResponse, error) {
+	ctx, span := tracer.Start(ctx, apimethod.PermissionMatrix.String(), trace.WithAttributes(
+		attribute.String("store_id", req.StoreID),
+		attribute.KeyValue{Key: authorizationModelIDKey, Value: attribute.StringValue(req.AuthorizationModelID)},
+		attribute.StringSlice("object_types", req.ObjectTypes),
+		attribute.StringSlice("relations", req.Relations),
+		attribute.String("user_filters", userFiltersToString(req.UserFilters)),
+	))
+	defer span.End()
+
+	if err := req.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	ctx = telemetry.ContextWithRPCInfo(ctx, telemetry.RPCInfo{
+		Service: s.serviceName,
+		Method:  apimethod.PermissionMatrix.String(),
+	})
+
+	err := s.checkAuthz(ctx, req.StoreID, apimethod.PermissionMatrix)
+	if err != nil {
+		return nil, err
+	}
+
+	typesys, err := s.resolveTypesystem(ctx, req.StoreID, req.AuthorizationModelID)
+	if err != nil {
+		return nil, err
+	}
+	span.SetAttributes(attribute.KeyValue{Key: authorizationModelIDKey, Value: attribute.StringValue(typesys.GetAuthorizationModelID())})
+
+	q := commands.NewPermissionMatrixQuery(s.datastore,
+		s.permissionMatrixListUsers,
+		commands.WithPermissionMatrixMaxObjects(s.permissionMatrixMaxObjects),
+	)
+
+	resp, err := q.Execute(typesystem.ContextWithTypesystem(ctx, typesys), typesys, req, emit)
+	if err != nil {
+		telemetry.TraceError(span, err)
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (s *Server) permissionMatrixListUsers(ctx context.Context, req *openfgav1.ListUsersRequest) (*openfgav1.ListUsersResponse, bool, error) {
+	typesys, _ := typesystem.TypesystemFromContext(ctx)
+	if err := listusers.ValidateListUsersRequest(ctx, req, typesys); err != nil {
+		return nil, false, err
+	}
+
+	resp, err := listusers.NewListUsersQuery(s.datastore, nil, s.listUsersQueryOptions()...).ListUsers(ctx, req)
+	if err != nil {
+		return nil, false, translateListUsersError(err)
+	}
+
+	return &openfgav1.ListUsersResponse{Users: resp.GetUsers()}, resp.Truncated(), nil
+}
@@ -0,0 +1,126 @@
+package server
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+
+	"github.com/openfga/openfga/pkg/server/commands"
+	"github.com/openfga/openfga/pkg/tuple"
+)
+
+func TestPermissionMatrix(t *testing.T) {
+	ctx := context.Background()
+
+	s, _, storeID := newTestServer(t)
+
+	modelID := writeTestModel(t, s, storeID, `
+		model
+			schema 1.1
+		type user
+		type group
+			relations
+				define member: [user]
+		type document
+			relations
+				define editor: [user]
+				define viewer: [user:*, group#member] or editor`)
+
+	_, err := s.Write(ctx, &openfgav1.WriteRequest{
+		StoreId:              storeID,
+		AuthorizationModelId: modelID,
+		Writes: &openfgav1.WriteRequestWrites{TupleKeys: []*openfgav1.TupleKey{
+			tuple.NewTupleKey("document:1", "editor", "user:anne"),
+			tuple.NewTupleKey("document:1", "viewer", "group:eng#member"),
+			tuple.NewTupleKey("document:2", "viewer", "user:*"),
+			tuple.NewTupleKey("group:eng", "member", "user:bob"),
+		}},
+	})
+	require.NoError(t, err)
+
+	t.Run("resolves_effective_permissions", func(t *testing.T) {
+		var rows []commands.PermissionMatrixRow
+		resp, err := s.PermissionMatrix(ctx, &commands.PermissionMatrixRequest{
+			StoreID:     storeID,
+			ObjectTypes: []string{"document"},
+			Relations:   []string{"viewer"},
+			UserFilters: commands.ParseUserTypeFilters([]string{"user"}),
+		}, func(row *commands.PermissionMatrixRow) error {
+			rows = append(rows, *row)
+			return nil
+		})
+		require.NoError(t, err)
+		require.Empty(t, resp.ContinuationToken)
+		require.Equal(t, []commands.PermissionMatrixRow{
+			{User: "user:anne", Relation: "viewer", Object: "document:1"},
+			{User: "user:bob", Relation: "viewer", Object: "document:1"},
+			{User: "user:*", Relation: "viewer", Object: "document:2"},
+		}, rows)
+	})
+
+	t.Run("unknown_object_type", func(t *testing.T) {
+		_, err := s.PermissionMatrix(ctx, &commands.PermissionMatrixRequest{
+			StoreID:     storeID,
+			ObjectTypes: []string{"folder"},
+		}, func(*commands.PermissionMatrixRow) error { return nil })
+		require.ErrorContains(t, err, "folder")
+	})
+
+	t.Run("http_csv_with_continuation_token", func(t *testing.T) {
+		mux := newTestHTTPMux(t, s)
+		httpServer := httptest.NewServer(mux)
+		t.Cleanup(httpServer.Close)
+
+		params := url.Values{
+			"object_type": {"document"},
+			"relation":    {"editor", "viewer"},
+			"user_filter": {"user"},
+			"page_size":   {"1"},
+			"format":      {"csv"},
+		}
+		resp, err := http.Get(httpServer.URL + "/stores/" + storeID + "/permission-matrix?" + params.Encode())
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.Equal(t, "text/csv", resp.Header.Get("Content-Type"))
+		require.Equal(t, "user,relation,object\nuser:anne,editor,document:1\n", string(body))
+		require.NotEmpty(t, resp.Trailer.Get(httpContinuationTokenTrailer))
+		require.Empty(t, resp.Trailer.Get(httpErrorTrailer))
+		require.Equal(t, "false", resp.Trailer.Get(httpTruncatedTrailer))
+
+		params.Set("continuation_token", resp.Trailer.Get(httpContinuationTokenTrailer))
+		params.Set("format", "jsonl")
+		params.Set("page_size", "10")
+		resp2, err := http.Get(httpServer.URL + "/stores/" + storeID + "/permission-matrix?" + params.Encode())
+		require.NoError(t, err)
+		defer resp2.Body.Close()
+
+		body, err = io.ReadAll(resp2.Body)
+		require.NoError(t, err)
+		require.Equal(t, "application/x-ndjson", resp2.Header.Get("Content-Type"))
+		require.Equal(t, `{"user":"user:anne","relation":"viewer","object":"document:1"}
+{"user":"user:bob","relation":"viewer","object":"document:1"}
+{"user":"user:*","relation":"viewer","object":"document:2"}
+`, string(body))
+		require.Empty(t, resp2.Trailer.Get(httpContinuationTokenTrailer))
+	})
+
+	t.Run("http_error_before_first_row", func(t *testing.T) {
+		mux := newTestHTTPMux(t, s)
+
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/stores/"+storeID+"/permission-matrix?format=xml&object_type=document", nil))
+		require.Equal(t, http.StatusBadRequest, rec.Code)
+		require.Contains(t, rec.Body.String(), "unsupported format 'xml'")
+	})
+}
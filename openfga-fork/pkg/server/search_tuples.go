@@ -0,0 +1,57 @@
+package server
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+
+	"github.com/openfga/openfga/internal/utils/apimethod"
+	"github.com/openfga/openfga/pkg/server/commands"
+	"github.com/openfga/openfga/pkg/telemetry"
+)
+
+// SearchTuples returns a page of the tuples of a store that match the filters of the request, in the order
+// they were written. It is Unimplemented if the datastore does not implement [storage.TupleSearchBackend].
+func (s *Server) SearchTuples(ctx context.Context, req *commands.SearchTuplesRequest) (*openfgav1.ReadResponse, error) {
+	ctx, span := tracer.Start(ctx, apimethod.SearchTuples.String(), trace.WithAttributes(
+		attribute.String("store_id", req.StoreID),
+		attribute.String("object_type", req.ObjectType),
+		attribute.String("object_id_prefix", req.ObjectIDPrefix),
+		attribute.String("relation", req.Relation),
+		attribute.String("user_type", req.UserType),
+		attribute.String("user_prefix", req.UserPrefix),
+		attribute.String("condition_name", req.ConditionName),
+		attribute.String("consistency", req.Consistency.String()),
+	))
+	defer span.End()
+
+	if err := req.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if s.tupleSearchBackend == nil {
+		return nil, status.Error(codes.Unimplemented, "the datastore does not support searching tuples")
+	}
+
+	ctx = telemetry.ContextWithRPCInfo(ctx, telemetry.RPCInfo{
+		Service: s.serviceName,
+		Method:  apimethod.SearchTuples.String(),
+	})
+
+	err := s.checkAuthz(ctx, req.StoreID, apimethod.SearchTuples)
+	if err != nil {
+		return nil, err
+	}
+
+	q := commands.NewSearchTuplesQuery(s.tupleSearchBackend,
+		commands.WithSearchTuplesQueryLogger(s.logger),
+		commands.WithSearchTuplesQueryEncoder(s.encoder),
+		commands.WithSearchTuplesQueryTokenSerializer(s.tokenSerializer),
+	)
+	return q.Execute(ctx, req)
+}
@@ -0,0 +1,84 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+
+	"github.com/openfga/openfga/pkg/server/commands"
+	"github.com/openfga/openfga/pkg/tuple"
+)
+
+func TestSearchTuples(t *testing.T) {
+	ctx := context.Background()
+
+	s, ds, storeID := newTestServer(t)
+
+	err := ds.Write(ctx, storeID, nil, []*openfgav1.TupleKey{
+		tuple.NewTupleKey("menu_item:report-1", "viewer", "user:anne"),
+		tuple.NewTupleKey("menu_item:report-2", "viewer", "group:eng#member"),
+		tuple.NewTupleKey("menu_item:settings", "viewer", "user:anne"),
+		tuple.NewTupleKeyWithCondition("menu_item:report-3", "viewer", "user:bob", "business_hours", nil),
+	})
+	require.NoError(t, err)
+
+	t.Run("server", func(t *testing.T) {
+		res, err := s.SearchTuples(ctx, &commands.SearchTuplesRequest{
+			StoreID:    storeID,
+			UserType:   "group",
+			Relation:   "viewer",
+			ObjectType: "menu_item",
+		})
+		require.NoError(t, err)
+		require.Len(t, res.GetTuples(), 1)
+		require.Equal(t, "group:eng#member", res.GetTuples()[0].GetKey().GetUser())
+
+		_, err = s.SearchTuples(ctx, &commands.SearchTuplesRequest{StoreID: storeID, ObjectIDPrefix: "report-"})
+		require.ErrorContains(t, err, "object_id_prefix requires object_type")
+	})
+
+	t.Run("http", func(t *testing.T) {
+		mux := newTestHTTPMux(t, s)
+
+		search := func(body string) (int, *openfgav1.ReadResponse) {
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/stores/"+storeID+"/tuples/search", strings.NewReader(body)))
+			res := &openfgav1.ReadResponse{}
+			if rec.Code == http.StatusOK {
+				require.NoError(t, protojson.Unmarshal(rec.Body.Bytes(), res))
+			}
+			return rec.Code, res
+		}
+
+		code, res := search(`{"object_type": "menu_item", "object_id_prefix": "report-", "page_size": 2, "consistency": "HIGHER_CONSISTENCY"}`)
+		require.Equal(t, http.StatusOK, code)
+		require.Len(t, res.GetTuples(), 2)
+		require.NotEmpty(t, res.GetContinuationToken())
+
+		code, res = search(`{"object_type": "menu_item", "object_id_prefix": "report-", "page_size": 2, "continuation_token": "` + res.GetContinuationToken() + `"}`)
+		require.Equal(t, http.StatusOK, code)
+		require.Len(t, res.GetTuples(), 1)
+		require.Empty(t, res.GetContinuationToken())
+
+		code, res = search(`{"condition_name": "business_hours", "written_after": "2000-01-01T00:00:00Z"}`)
+		require.Equal(t, http.StatusOK, code)
+		require.Len(t, res.GetTuples(), 1)
+		require.Equal(t, "menu_item:report-3", res.GetTuples()[0].GetKey().GetObject())
+
+		code, _ = search(`{"written_before": "yesterday"}`)
+		require.Equal(t, http.StatusBadRequest, code)
+
+		code, _ = search(`{"consistency": "SOMETIMES"}`)
+		require.Equal(t, http.StatusBadRequest, code)
+
+		code, _ = search(`{"object": "menu_item:report-1"}`)
+		require.Equal(t, http.StatusBadRequest, code)
+	})
+}
@@ -18,11 +18,14 @@ import (
 	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 	"golang.org/x/sync/singleflight"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
 
 	openfgav1 "github.com/openfga/api/proto/openfga/v1"
 
 	"github.com/openfga/openfga/internal/authz"
 	"github.com/openfga/openfga/internal/build"
+	"github.com/openfga/openfga/internal/condition/contextprovider"
 	"github.com/openfga/openfga/internal/graph"
 	"github.com/openfga/openfga/internal/planner"
 	"github.com/openfga/openfga/internal/shared"
@@ -30,11 +33,16 @@ import (
 	"github.com/openfga/openfga/internal/utils"
 	"github.com/openfga/openfga/internal/utils/apimethod"
 	"github.com/openfga/openfga/pkg/authclaims"
+	"github.com/openfga/openfga/pkg/decisionlog"
 	"github.com/openfga/openfga/pkg/encoder"
 	"github.com/openfga/openfga/pkg/gateway"
 	"github.com/openfga/openfga/pkg/logger"
+	"github.com/openfga/openfga/pkg/middleware/loadshed"
+	"github.com/openfga/openfga/pkg/middleware/ratelimit"
+	"github.com/openfga/openfga/pkg/server/commands"
 	serverconfig "github.com/openfga/openfga/pkg/server/config"
 	serverErrors "github.com/openfga/openfga/pkg/server/errors"
+	"github.com/openfga/openfga/pkg/slowlog"
 	"github.com/openfga/openfga/pkg/storage"
 	"github.com/openfga/openfga/pkg/storage/storagewrappers"
 	"github.com/openfga/openfga/pkg/telemetry"
@@ -47,6 +55,9 @@ const (
 	AuthorizationModelIDHeader = "Openfga-Authorization-Model-Id"
 	authorizationModelIDKey    = "authorization_model_id"
 
+	// storeStatsCacheSize is the maximum number of stores whose statistics are cached.
+	storeStatsCacheSize = 1000
+
 	ExperimentalCheckOptimizations       ExperimentalFeatureFlag = "enable-check-optimizations"
 	ExperimentalListObjectsOptimizations ExperimentalFeatureFlag = "enable-list-objects-optimizations"
 	ExperimentalAccessControlParams      ExperimentalFeatureFlag = "enable-access-control"
@@ -58,7 +69,7 @@ var tracer = otel.Tracer("openfga/pkg/server")
 var (
 	dispatchCountHistogramName = "dispatch_count"
 
-	dispatchCountHistogram = promauto.NewHistogramVec(prometheus.HistogramOpts{
+	dispatchCountHistogram = telemetry.StoreLabeled(promauto.NewHistogramVec(prometheus.HistogramOpts{
 		Namespace:                       build.ProjectName,
 		Name:                            dispatchCountHistogramName,
 		Help:                            "The number of dispatches required to resolve a query (e.g. Check).",
@@ -66,11 +77,11 @@ var (
 		NativeHistogramBucketFactor:     1.1,
 		NativeHistogramMaxBucketNumber:  100,
 		NativeHistogramMinResetDuration: time.Hour,
-	}, []string{"grpc_service", "grpc_method"})
+	}, []string{"grpc_service", "grpc_method", telemetry.StoreIDLabel, telemetry.ModelIDLabel}))
 
 	datastoreQueryCountHistogramName = "datastore_query_count"
 
-	datastoreQueryCountHistogram = promauto.NewHistogramVec(prometheus.HistogramOpts{
+	datastoreQueryCountHistogram = telemetry.StoreLabeled(promauto.NewHistogramVec(prometheus.HistogramOpts{
 		Namespace:                       build.ProjectName,
 		Name:                            datastoreQueryCountHistogramName,
 		Help:                            "The number of database queries required to resolve a query (e.g. Check, ListObjects or ListUsers).",
@@ -78,11 +89,11 @@ var (
 		NativeHistogramBucketFactor:     1.1,
 		NativeHistogramMaxBucketNumber:  100,
 		NativeHistogramMinResetDuration: time.Hour,
-	}, []string{"grpc_service", "grpc_method"})
+	}, []string{"grpc_service", "grpc_method", telemetry.StoreIDLabel, telemetry.ModelIDLabel}))
 
 	requestDurationHistogramName = "request_duration_ms"
 
-	requestDurationHistogram = promauto.NewHistogramVec(prometheus.HistogramOpts{
+	requestDurationHistogram = telemetry.StoreLabeled(promauto.NewHistogramVec(prometheus.HistogramOpts{
 		Namespace:                       build.ProjectName,
 		Name:                            requestDurationHistogramName,
 		Help:                            "The request duration (in ms) labeled by method and buckets of datastore query counts and number of dispatches. This allows for reporting percentiles based on the number of datastore queries and number of dispatches required to resolve the request.",
@@ -90,7 +101,7 @@ var (
 		NativeHistogramBucketFactor:     1.1,
 		NativeHistogramMaxBucketNumber:  100,
 		NativeHistogramMinResetDuration: time.Hour,
-	}, []string{"grpc_service", "grpc_method", "datastore_query_count", "dispatch_count", "consistency"})
+	}, []string{"grpc_service", "grpc_method", "datastore_query_count", "dispatch_count", "consistency", telemetry.StoreIDLabel, telemetry.ModelIDLabel}))
 
 	listObjectsOptimizationCounter = promauto.NewCounterVec(prometheus.CounterOpts{
 		Namespace: build.ProjectName,
@@ -100,11 +111,11 @@ var (
 
 	listObjectsCheckCountName = "check_count"
 
-	throttledRequestCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+	throttledRequestCounter = telemetry.StoreLabeled(promauto.NewCounterVec(prometheus.CounterOpts{
 		Namespace: build.ProjectName,
 		Name:      "throttled_requests_count",
 		Help:      "The total number of requests that have been throttled.",
-	}, []string{"grpc_service", "grpc_method"})
+	}, []string{"grpc_service", "grpc_method", telemetry.StoreIDLabel, telemetry.ModelIDLabel}))
 
 	checkResultCounterName = "check_result_count"
 	checkResultCounter     = promauto.NewCounterVec(prometheus.CounterOpts{
@@ -153,8 +164,11 @@ var (
 type Server struct {
 	openfgav1.UnimplementedOpenFGAServiceServer
 
-	logger                           logger.Logger
-	datastore                        storage.OpenFGADatastore
+	logger    logger.Logger
+	datastore storage.OpenFGADatastore
+	// tupleSearchBackend and storeStatsBackend are nil when the datastore does not implement them.
+	tupleSearchBackend               storage.TupleSearchBackend
+	storeStatsBackend                commands.StoreStatsBackend
 	tokenSerializer                  encoder.ContinuationTokenSerializer
 	encoder                          encoder.Encoder
 	transport                        gateway.Transport
@@ -165,6 +179,9 @@ type Server struct {
 	listObjectsMaxResults            uint32
 	listUsersDeadline                time.Duration
 	listUsersMaxResults              uint32
+	permissionMatrixMaxObjects       uint32
+	accessReviewMaxChanges           uint32
+	storeStatsCacheTTL               time.Duration
 	maxChecksPerBatchCheck           uint32
 	maxConcurrentChecksPerBatch      uint32
 	maxConcurrentReadsForListObjects uint32
@@ -172,6 +189,7 @@ type Server struct {
 	maxConcurrentReadsForListUsers   uint32
 	maxAuthorizationModelCacheSize   int
 	maxAuthorizationModelSizeInBytes int
+	conditionExtensionsEnabled       bool
 	experimentals                    []ExperimentalFeatureFlag
 	AccessControl                    serverconfig.AccessControlConfig
 	AuthnMethod                      string
@@ -185,6 +203,10 @@ type Server struct {
 	cacheSettings serverconfig.CacheSettings
 	// sharedDatastoreResources are created by the server
 	sharedDatastoreResources *shared.SharedDatastoreResources
+	// authorizationModelCache is the cache of the datastore of the server
+	authorizationModelCache storagewrappers.AuthorizationModelCache
+	// storeStatsCache is nil unless the store statistics are cached
+	storeStatsCache storage.InMemoryCache[*commands.GetStoreStatsResponse]
 
 	checkResolver       graph.CheckResolver
 	checkResolverCloser func()
@@ -233,8 +255,26 @@ type Server struct {
 	listUsersDatastoreThrottleThreshold   int
 	listUsersDatastoreThrottleDuration    time.Duration
 
+	// adaptiveLimiter bounds the datastore reads of Check, BatchCheck, ListObjects and ListUsers. If nil, they are
+	// only bounded by the per-request concurrency limits.
+	adaptiveLimiter *storagewrappers.AdaptiveLimiter
+
 	authorizer authz.AuthorizerInterface
 
+	conditionContextProviders []contextprovider.Provider
+
+	// rateLimiter limits the requests of the HTTP APIs that do not go through the gRPC interceptors.
+	rateLimiter *ratelimit.Limiter
+
+	// loadShedder queues or sheds the requests of the HTTP APIs that do not go through the gRPC interceptors.
+	loadShedder *loadshed.Shedder
+
+	// decisionLogger records the decisions of Check, BatchCheck and ListObjects. If nil, they are not recorded.
+	decisionLogger *decisionlog.Logger
+
+	// slowLog records the Check and ListObjects requests slower than their threshold. If nil, they are not recorded.
+	slowLog *slowlog.Log
+
 	ctx                           context.Context
 	contextPropagationToDatastore bool
 
@@ -359,6 +399,32 @@ func WithListUsersMaxResults(limit uint32) OpenFGAServiceV1Option {
 	}
 }
 
+// WithPermissionMatrixMaxObjects affects the permission matrix report only.
+// It sets the maximum number of objects of a single type that the report enumerates.
+// If it's zero, there is no limit.
+func WithPermissionMatrixMaxObjects(limit uint32) OpenFGAServiceV1Option {
+	return func(s *Server) {
+		s.permissionMatrixMaxObjects = limit
+	}
+}
+
+// WithAccessReviewMaxChanges affects the access review report only.
+// It sets the maximum number of changelog entries that the report reads to rebuild past tuples.
+// If it's zero, there is no limit.
+func WithAccessReviewMaxChanges(limit uint32) OpenFGAServiceV1Option {
+	return func(s *Server) {
+		s.accessReviewMaxChanges = limit
+	}
+}
+
+// WithStoreStatsCacheTTL affects the GetStoreStats API only.
+// It sets how long the statistics of a store are cached in memory. If it's zero, they are not cached.
+func WithStoreStatsCacheTTL(ttl time.Duration) OpenFGAServiceV1Option {
+	return func(s *Server) {
+		s.storeStatsCacheTTL = ttl
+	}
+}
+
 // WithMaxConcurrentReadsForListObjects sets a limit on the number of datastore reads that can be in flight for a given ListObjects call.
 // This number should be set depending on the RPS expected for Check and ListObjects APIs, the number of OpenFGA replicas running,
 // and the number of connections the datastore allows.
@@ -416,6 +482,47 @@ func WithAccessControlParams(enabled bool, storeID string, modelID string, authn
 	}
 }
 
+// WithConditionContextProviders sets the providers of trusted condition context values. Their values
+// override the values with the same key in the context sent with Check, BatchCheck, ListObjects and
+// ListUsers requests.
+func WithConditionContextProviders(providers ...contextprovider.Provider) OpenFGAServiceV1Option {
+	return func(s *Server) {
+		s.conditionContextProviders = providers
+	}
+}
+
+// WithRateLimiter sets the rate limiter of the HTTP APIs registered with RegisterHTTPHandlers. The gRPC
+// methods are limited by the interceptors of the ratelimit package. If nil, requests are not limited.
+func WithRateLimiter(limiter *ratelimit.Limiter) OpenFGAServiceV1Option {
+	return func(s *Server) {
+		s.rateLimiter = limiter
+	}
+}
+
+// WithLoadShedder sets the load shedder of the HTTP APIs registered with RegisterHTTPHandlers. The gRPC
+// methods are shed by the interceptors of the loadshed package. If nil, requests are not shed.
+func WithLoadShedder(shedder *loadshed.Shedder) OpenFGAServiceV1Option {
+	return func(s *Server) {
+		s.loadShedder = shedder
+	}
+}
+
+// WithDecisionLogger sets the logger of the decisions made by Check, BatchCheck and ListObjects.
+// If nil, decisions are not recorded.
+func WithDecisionLogger(logger *decisionlog.Logger) OpenFGAServiceV1Option {
+	return func(s *Server) {
+		s.decisionLogger = logger
+	}
+}
+
+// WithSlowLog sets the log of the Check, ListObjects and StreamedListObjects requests that take longer than the
+// threshold of their method. If nil, slow requests are not recorded.
+func WithSlowLog(log *slowlog.Log) OpenFGAServiceV1Option {
+	return func(s *Server) {
+		s.slowLog = log
+	}
+}
+
 // WithCheckQueryCacheEnabled enables caching of Check results for the Check and List objects APIs.
 // This cache is shared for all requests.
 // See also WithCheckCacheLimit and WithCheckQueryCacheTTL.
@@ -432,6 +539,22 @@ func WithCheckCacheLimit(limit uint32) OpenFGAServiceV1Option {
 	}
 }
 
+// WithCheckCacheBackend sets where the check cache is stored: serverconfig.CheckCacheBackendMemory
+// or serverconfig.CheckCacheBackendRedis. See also WithCheckCacheRedis.
+func WithCheckCacheBackend(backend string) OpenFGAServiceV1Option {
+	return func(s *Server) {
+		s.cacheSettings.CheckCacheBackend = backend
+	}
+}
+
+// WithCheckCacheRedis sets the Redis-compatible server that stores the check cache when its backend is
+// serverconfig.CheckCacheBackendRedis.
+func WithCheckCacheRedis(config serverconfig.CheckCacheRedisConfig) OpenFGAServiceV1Option {
+	return func(s *Server) {
+		s.cacheSettings.CheckCacheRedis = config
+	}
+}
+
 // WithCacheControllerEnabled enables cache invalidation of different cache entities.
 func WithCacheControllerEnabled(enabled bool) OpenFGAServiceV1Option {
 	return func(s *Server) {
@@ -522,6 +645,14 @@ func WithMaxAuthorizationModelSizeInBytes(size int) OpenFGAServiceV1Option {
 	}
 }
 
+// WithConditionExtensionsEnabled sets whether the conditions of authorization models can use the functions of
+// the condition extension library. Models that use them can only be written and evaluated while it is enabled.
+func WithConditionExtensionsEnabled(enabled bool) OpenFGAServiceV1Option {
+	return func(s *Server) {
+		s.conditionExtensionsEnabled = enabled
+	}
+}
+
 // WithDispatchThrottlingCheckResolverEnabled sets whether dispatch throttling is enabled for Check requests.
 // Enabling this feature will prioritize dispatched requests requiring less than the configured dispatch
 // threshold over requests whose dispatch count exceeds the configured threshold.
@@ -715,6 +846,14 @@ func WithListUsersDatabaseThrottle(threshold int, duration time.Duration) OpenFG
 	}
 }
 
+// WithDatastoreAdaptiveLimiter sets the limiter that adapts the number of concurrent datastore reads of all the
+// Check, BatchCheck, ListObjects and ListUsers requests to the latency and errors of the datastore.
+func WithDatastoreAdaptiveLimiter(limiter *storagewrappers.AdaptiveLimiter) OpenFGAServiceV1Option {
+	return func(s *Server) {
+		s.adaptiveLimiter = limiter
+	}
+}
+
 // WithShadowCheckResolverEnabled turns of shadow check resolver to allow result comparison.
 // Note that ShadowCheckResolver is a temporary feature and may be removed in future release.
 func WithShadowCheckResolverEnabled(enabled bool) OpenFGAServiceV1Option {
@@ -828,6 +967,9 @@ func NewServerWithOpts(opts ...OpenFGAServiceV1Option) (*Server, error) {
 		listObjectsMaxResults:            serverconfig.DefaultListObjectsMaxResults,
 		listUsersDeadline:                serverconfig.DefaultListUsersDeadline,
 		listUsersMaxResults:              serverconfig.DefaultListUsersMaxResults,
+		permissionMatrixMaxObjects:       serverconfig.DefaultPermissionMatrixMaxObjects,
+		accessReviewMaxChanges:           serverconfig.DefaultAccessReviewMaxChanges,
+		storeStatsCacheTTL:               serverconfig.DefaultStoreStatsCacheTTL,
 		maxChecksPerBatchCheck:           serverconfig.DefaultMaxChecksPerBatchCheck,
 		maxConcurrentChecksPerBatch:      serverconfig.DefaultMaxConcurrentChecksPerBatchCheck,
 		maxConcurrentReadsForCheck:       serverconfig.DefaultMaxConcurrentReadsForCheck,
@@ -939,16 +1081,22 @@ func NewServerWithOpts(opts ...OpenFGAServiceV1Option) (*Server, error) {
 		}
 	}
 
+	// The optional backends are asserted before the datastore is wrapped, since the wrappers do not forward them.
+	s.tupleSearchBackend, _ = s.datastore.(storage.TupleSearchBackend)
+	s.storeStatsBackend, _ = s.datastore.(commands.StoreStatsBackend)
+
 	if !s.contextPropagationToDatastore {
 		// Creates a new [storagewrappers.ContextTracerWrapper] that will execute datastore queries using
 		// a new background context with the current trace context.
 		s.datastore = storagewrappers.NewContextWrapper(s.datastore)
 	}
 
-	s.datastore, err = storagewrappers.NewCachedOpenFGADatastore(s.datastore, s.maxAuthorizationModelCacheSize)
+	cachedDatastore, err := storagewrappers.NewCachedOpenFGADatastore(s.datastore, s.maxAuthorizationModelCacheSize)
 	if err != nil {
 		return nil, err
 	}
+	s.datastore = cachedDatastore
+	s.authorizationModelCache = cachedDatastore
 
 	if s.shadowListObjectsQueryEnabled {
 		s.cacheSettings.ShadowCheckCacheEnabled = true
@@ -1029,11 +1177,22 @@ func NewServerWithOpts(opts ...OpenFGAServiceV1Option) (*Server, error) {
 		s.listUsersDispatchThrottler = throttler.NewConstantRateThrottler(s.listUsersDispatchThrottlingFrequency, "list_users_dispatch_throttle")
 	}
 
-	s.typesystemResolver, s.typesystemResolverStop, err = typesystem.MemoizedTypesystemResolverFunc(s.datastore)
+	s.typesystemResolver, s.typesystemResolverStop, err = typesystem.MemoizedTypesystemResolverFunc(
+		s.datastore,
+		typesystem.WithConditionExtensions(s.conditionExtensionsEnabled),
+	)
 	if err != nil {
 		return nil, err
 	}
 
+	if s.storeStatsCacheTTL > 0 {
+		s.storeStatsCache, err = storage.NewInMemoryLRUCache[*commands.GetStoreStatsResponse](
+			storage.WithMaxCacheSize[*commands.GetStoreStatsResponse](storeStatsCacheSize))
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	if s.IsAccessControlEnabled() {
 		s.authorizer = authz.NewAuthorizer(&authz.Config{StoreID: s.AccessControl.StoreID, ModelID: s.AccessControl.ModelID}, s, s.logger)
 	}
@@ -1057,6 +1216,10 @@ func (s *Server) Close() {
 		s.listUsersDispatchThrottler.Close()
 	}
 
+	if s.storeStatsCache != nil {
+		s.storeStatsCache.Stop()
+	}
+
 	s.sharedDatastoreResources.Close()
 	s.datastore.Close()
 }
@@ -1147,6 +1310,34 @@ func (s *Server) validateShadowListObjectsQueryEnabled() error {
 	return nil
 }
 
+// trustedConditionContext returns the condition context sent by the caller with the values of the
+// condition context providers added.
+func (s *Server) trustedConditionContext(ctx context.Context, callerContext *structpb.Struct) *structpb.Struct {
+	return contextprovider.Merge(ctx, s.conditionContextProviders, callerContext)
+}
+
+// logDecision records the decision if a decision logger is set. The condition context of the request is
+// recorded as well.
+func (s *Server) logDecision(ctx context.Context, decision *decisionlog.Decision, conditionContext *structpb.Struct) {
+	if s.decisionLogger == nil {
+		return
+	}
+
+	s.decisionLogger.LogWithConditionContext(ctx, decision, conditionContext)
+}
+
+// logSlowRequest records req in the slow request log if a slow request log is set and the request took longer
+// than the threshold of its method. The entry is only built for slow requests.
+func (s *Server) logSlowRequest(ctx context.Context, method string, req proto.Message, duration time.Duration, entry func() *slowlog.Entry) {
+	if s.slowLog == nil || !s.slowLog.Slow(method, duration) {
+		return
+	}
+
+	e := entry()
+	e.Method = method
+	s.slowLog.Record(ctx, req, duration, e)
+}
+
 // checkAuthz checks the authorization for calling an API method.
 func (s *Server) checkAuthz(ctx context.Context, storeID string, apiMethod apimethod.APIMethod, modules ...string) error {
 	if authclaims.SkipAuthzCheckFromContext(ctx) {
@@ -32,6 +32,7 @@ import (
 	"github.com/openfga/openfga/internal/cachecontroller"
 	"github.com/openfga/openfga/internal/graph"
 	mockstorage "github.com/openfga/openfga/internal/mocks"
+	"github.com/openfga/openfga/pkg/server/commands"
 	serverconfig "github.com/openfga/openfga/pkg/server/config"
 	serverErrors "github.com/openfga/openfga/pkg/server/errors"
 	"github.com/openfga/openfga/pkg/server/test"
@@ -1303,6 +1304,7 @@ func TestResolveAuthorizationModel(t *testing.T) {
 		defer mockController.Finish()
 
 		mockDatastore := mockstorage.NewMockOpenFGADatastore(mockController)
+		mockDatastore.EXPECT().ReadActiveAuthorizationModelID(gomock.Any(), store).Return("", storage.ErrNotFound)
 		mockDatastore.EXPECT().FindLatestAuthorizationModel(gomock.Any(), store).Return(nil, storage.ErrNotFound)
 
 		s := MustNewServerWithOpts(
@@ -1327,6 +1329,7 @@ func TestResolveAuthorizationModel(t *testing.T) {
 		defer mockController.Finish()
 
 		mockDatastore := mockstorage.NewMockOpenFGADatastore(mockController)
+		mockDatastore.EXPECT().ReadActiveAuthorizationModelID(gomock.Any(), store).Return("", storage.ErrNotFound)
 		mockDatastore.EXPECT().FindLatestAuthorizationModel(gomock.Any(), store).Return(
 			&openfgav1.AuthorizationModel{
 				Id:            modelID,
@@ -2523,3 +2526,28 @@ func TestBatchCheckWithCachedIterator(t *testing.T) {
 	require.NoError(t, err)
 	require.True(t, batchCheckResponse.GetResult()[fakeID].GetAllowed())
 }
+
+func TestOptionalDatastoreBackends(t *testing.T) {
+	t.Cleanup(func() {
+		goleak.VerifyNone(t)
+	})
+
+	ctx := context.Background()
+
+	ds := memory.New()
+	t.Cleanup(ds.Close)
+
+	// The embedded interface hides the optional backends of the memory datastore.
+	s := MustNewServerWithOpts(WithDatastore(struct{ storage.OpenFGADatastore }{ds}))
+	t.Cleanup(s.Close)
+
+	createStoreResp, err := s.CreateStore(ctx, &openfgav1.CreateStoreRequest{Name: "openfga-demo"})
+	require.NoError(t, err)
+	storeID := createStoreResp.GetId()
+
+	_, err = s.SearchTuples(ctx, &commands.SearchTuplesRequest{StoreID: storeID})
+	require.Equal(t, codes.Unimplemented, status.Code(err))
+
+	_, err = s.GetStoreStats(ctx, &commands.GetStoreStatsRequest{StoreID: storeID})
+	require.Equal(t, codes.Unimplemented, status.Code(err))
+}
@@ -0,0 +1,48 @@
+package server
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/openfga/openfga/internal/utils/apimethod"
+	"github.com/openfga/openfga/pkg/server/commands"
+	"github.com/openfga/openfga/pkg/telemetry"
+)
+
+// GetStoreStats returns the number of tuples of a store grouped by object type, relation and user type, the
+// size of its changelog and the number of its authorization models. See also WithStoreStatsCacheTTL. It is
+// Unimplemented if the datastore does not implement [storage.StoreStatsBackend].
+func (s *Server) GetStoreStats(ctx context.Context, req *commands.GetStoreStatsRequest) (*commands.GetStoreStatsResponse, error) {
+	ctx, span := tracer.Start(ctx, apimethod.GetStoreStats.String(), trace.WithAttributes(
+		attribute.String("store_id", req.StoreID),
+	))
+	defer span.End()
+
+	if err := req.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if s.storeStatsBackend == nil {
+		return nil, status.Error(codes.Unimplemented, "the datastore does not support store statistics")
+	}
+
+	ctx = telemetry.ContextWithRPCInfo(ctx, telemetry.RPCInfo{
+		Service: s.serviceName,
+		Method:  apimethod.GetStoreStats.String(),
+	})
+
+	err := s.checkAuthz(ctx, req.StoreID, apimethod.GetStoreStats)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := []commands.GetStoreStatsQueryOption{commands.WithGetStoreStatsQueryLogger(s.logger)}
+	if s.storeStatsCache != nil {
+		opts = append(opts, commands.WithGetStoreStatsQueryCache(s.storeStatsCache, s.storeStatsCacheTTL))
+	}
+	return commands.NewGetStoreStatsQuery(s.storeStatsBackend, opts...).Execute(ctx, req)
+}
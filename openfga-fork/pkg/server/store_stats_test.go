@@ -0,0 +1,63 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+	"github.com/stretchr/testify/require"
+
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+
+	"github.com/openfga/openfga/pkg/server/commands"
+	"github.com/openfga/openfga/pkg/tuple"
+)
+
+func TestGetStoreStats(t *testing.T) {
+	ctx := context.Background()
+
+	s, ds, storeID := newTestServer(t, WithStoreStatsCacheTTL(time.Hour))
+
+	err := ds.Write(ctx, storeID, nil, []*openfgav1.TupleKey{
+		tuple.NewTupleKey("document:1", "viewer", "user:anne"),
+		tuple.NewTupleKey("document:2", "viewer", "user:bob"),
+	})
+	require.NoError(t, err)
+
+	t.Run("server", func(t *testing.T) {
+		res, err := s.GetStoreStats(ctx, &commands.GetStoreStatsRequest{StoreID: storeID})
+		require.NoError(t, err)
+		require.Equal(t, int64(2), res.TupleCount)
+
+		_, err = s.GetStoreStats(ctx, &commands.GetStoreStatsRequest{StoreID: "invalid"})
+		require.ErrorContains(t, err, "invalid store_id")
+	})
+
+	t.Run("http", func(t *testing.T) {
+		mux := newTestHTTPMux(t, s)
+
+		get := func(storeID string) (int, *commands.GetStoreStatsResponse) {
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/stores/"+storeID+"/stats", nil))
+			res := &commands.GetStoreStatsResponse{}
+			if rec.Code == http.StatusOK {
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), res))
+			}
+			return rec.Code, res
+		}
+
+		code, res := get(storeID)
+		require.Equal(t, http.StatusOK, code)
+		require.Equal(t, []commands.StoreStatsTupleCount{
+			{ObjectType: "document", Relation: "viewer", UserType: "user", Count: 2},
+		}, res.TupleCounts)
+		require.Equal(t, int64(2), res.ChangelogCount)
+
+		code, _ = get(ulid.Make().String())
+		require.Equal(t, http.StatusNotFound, code)
+	})
+}
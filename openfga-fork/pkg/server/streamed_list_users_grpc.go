@@ -0,0 +1,68 @@
+package server
+
+import (
+	"context"
+	"strconv"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+
+	"github.com/openfga/openfga/pkg/server/commands/listusers"
+)
+
+const (
+	// StreamedListUsersUserCountTrailer, StreamedListUsersDeadlineExceededTrailer and
+	// StreamedListUsersThrottledTrailer are the trailers of a StreamedListUsers stream, which hold its metadata.
+	StreamedListUsersUserCountTrailer        = "openfga-user-count"
+	StreamedListUsersDeadlineExceededTrailer = "openfga-deadline-exceeded"
+	StreamedListUsersThrottledTrailer        = "openfga-throttled"
+)
+
+// StreamedListUsersServer is the server of the StreamedListUsers RPC.
+type StreamedListUsersServer interface {
+	StreamedListUsers(ctx context.Context, req *openfgav1.ListUsersRequest, emit func(*openfgav1.User) error) (*listusers.StreamedListUsersMetadata, error)
+}
+
+// StreamedListUsersServiceDesc describes the StreamedListUsers server-streaming RPC. The OpenFGA protobuf
+// service does not have it, so it is served by a service of its own, with the messages of ListUsers: the
+// request is a ListUsersRequest, and every response is a ListUsersResponse that holds one user. The metadata
+// of the stream is sent in its trailers once all the users have been sent.
+var StreamedListUsersServiceDesc = grpc.ServiceDesc{
+	ServiceName: "openfga.v1.StreamedListUsersService",
+	HandlerType: (*StreamedListUsersServer)(nil),
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamedListUsers",
+			Handler:       streamedListUsersHandler,
+			ServerStreams: true,
+		},
+	},
+}
+
+// RegisterStreamedListUsersServer registers the StreamedListUsers RPC of srv on registrar.
+func RegisterStreamedListUsersServer(registrar grpc.ServiceRegistrar, srv StreamedListUsersServer) {
+	registrar.RegisterService(&StreamedListUsersServiceDesc, srv)
+}
+
+func streamedListUsersHandler(srv any, stream grpc.ServerStream) error {
+	req := &openfgav1.ListUsersRequest{}
+	if err := stream.RecvMsg(req); err != nil {
+		return err
+	}
+
+	res, err := srv.(StreamedListUsersServer).StreamedListUsers(stream.Context(), req, func(user *openfgav1.User) error {
+		return stream.SendMsg(&openfgav1.ListUsersResponse{Users: []*openfgav1.User{user}})
+	})
+	if err != nil {
+		return err
+	}
+
+	stream.SetTrailer(metadata.Pairs(
+		StreamedListUsersUserCountTrailer, strconv.FormatUint(uint64(res.UserCount), 10),
+		StreamedListUsersDeadlineExceededTrailer, strconv.FormatBool(res.DeadlineExceeded),
+		StreamedListUsersThrottledTrailer, strconv.FormatBool(res.WasThrottled),
+	))
+	return nil
+}
@@ -0,0 +1,68 @@
+package server
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/openfga/openfga/internal/utils/apimethod"
+	"github.com/openfga/openfga/pkg/server/commands"
+	"github.com/openfga/openfga/pkg/telemetry"
+	"github.com/openfga/openfga/pkg/typesystem"
+)
+
+// WhatIf previews the (user, relation, object) permissions that a write would add and remove, without
+// writing anything. The analysis is bounded by the deadline and the result limit of the ListObjects API;
+// when either is reached, the permissions found so far are returned and the response is marked as truncated.
+func (s *Server) WhatIf(ctx context.Context, req *commands.WhatIfRequest) (*commands.WhatIfResponse, error) {
+	ctx, span := tracer.Start(ctx, apimethod.WhatIf.String(), trace.WithAttributes(
+		attribute.String("store_id", req.StoreID),
+		attribute.KeyValue{Key: authorizationModelIDKey, Value: attribute.StringValue(req.AuthorizationModelID)},
+		attribute.Int("writes", len(req.Writes)),
+		attribute.Int("deletes", len(req.Deletes)),
+		attribute.StringSlice("object_types", req.ObjectTypes),
+	))
+	defer span.End()
+
+	if err := req.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	ctx = telemetry.ContextWithRPCInfo(ctx, telemetry.RPCInfo{
+		Service: s.serviceName,
+		Method:  apimethod.WhatIf.String(),
+	})
+
+	err := s.checkAuthz(ctx, req.StoreID, apimethod.WhatIf)
+	if err != nil {
+		return nil, err
+	}
+
+	typesys, err := s.resolveTypesystem(ctx, req.StoreID, req.AuthorizationModelID)
+	if err != nil {
+		return nil, err
+	}
+	span.SetAttributes(attribute.KeyValue{Key: authorizationModelIDKey, Value: attribute.StringValue(typesys.GetAuthorizationModelID())})
+
+	checker := s.newUncachedChecker()
+	defer checker.Close()
+
+	q := commands.NewWhatIfQuery(s.datastore,
+		&accessReviewResolver{server: s, checker: checker},
+		commands.WithWhatIfMaxResults(s.listObjectsMaxResults),
+		commands.WithWhatIfDeadline(s.listObjectsDeadline),
+		commands.WithWhatIfResolveNodeLimit(s.resolveNodeLimit),
+		commands.WithWhatIfResolveNodeBreadthLimit(s.resolveNodeBreadthLimit),
+	)
+
+	resp, err := q.Execute(typesystem.ContextWithTypesystem(ctx, typesys), typesys, req)
+	if err != nil {
+		telemetry.TraceError(span, err)
+		return nil, err
+	}
+	span.SetAttributes(attribute.Bool("truncated", resp.Truncated))
+	return resp, nil
+}
@@ -0,0 +1,120 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+
+	"github.com/openfga/openfga/pkg/server/commands"
+	"github.com/openfga/openfga/pkg/tuple"
+)
+
+func TestWhatIf(t *testing.T) {
+	ctx := context.Background()
+
+	s, _, storeID := newTestServer(t)
+
+	writeTestModel(t, s, storeID, `
+		model
+			schema 1.1
+		type user
+		type group
+			relations
+				define member: [user]
+		type folder
+			relations
+				define viewer: [user, group#member]
+		type document
+			relations
+				define parent: [folder]
+				define blocked: [user]
+				define viewer: ([user] or viewer from parent) but not blocked`)
+
+	_, err := s.Write(ctx, &openfgav1.WriteRequest{
+		StoreId: storeID,
+		Writes: &openfgav1.WriteRequestWrites{TupleKeys: []*openfgav1.TupleKey{
+			tuple.NewTupleKey("group:eng", "member", "user:anne"),
+			tuple.NewTupleKey("group:eng", "member", "user:bob"),
+			tuple.NewTupleKey("folder:specs", "viewer", "group:eng#member"),
+			tuple.NewTupleKey("document:plan", "parent", "folder:specs"),
+			tuple.NewTupleKey("document:plan", "blocked", "user:bob"),
+		}},
+	})
+	require.NoError(t, err)
+
+	t.Run("group_grant", func(t *testing.T) {
+		// Granting the group access to a second folder, and moving the plan there, only adds access to the folder:
+		// the plan stays visible to the group, except to bob who is blocked.
+		resp, err := s.WhatIf(ctx, &commands.WhatIfRequest{
+			StoreID: storeID,
+			Writes: []*openfgav1.TupleKey{
+				tuple.NewTupleKey("folder:drafts", "viewer", "group:eng#member"),
+				tuple.NewTupleKey("document:plan", "parent", "folder:drafts"),
+			},
+			Deletes: []*openfgav1.TupleKeyWithoutCondition{
+				{Object: "document:plan", Relation: "parent", User: "folder:specs"},
+			},
+		})
+		require.NoError(t, err)
+		require.Equal(t, []*commands.WhatIfPermission{
+			{User: "folder:drafts", Relation: "parent", Object: "document:plan"},
+			{User: "group:eng#member", Relation: "viewer", Object: "folder:drafts"},
+			{User: "user:anne", Relation: "viewer", Object: "folder:drafts"},
+			{User: "user:bob", Relation: "viewer", Object: "folder:drafts"},
+		}, resp.Added)
+		require.Equal(t, []*commands.WhatIfPermission{
+			{User: "folder:specs", Relation: "parent", Object: "document:plan"},
+		}, resp.Removed)
+		require.False(t, resp.Truncated)
+	})
+
+	t.Run("remove_member", func(t *testing.T) {
+		resp, err := s.WhatIf(ctx, &commands.WhatIfRequest{
+			StoreID:     storeID,
+			Deletes:     []*openfgav1.TupleKeyWithoutCondition{{Object: "group:eng", Relation: "member", User: "user:anne"}},
+			ObjectTypes: []string{"document"},
+		})
+		require.NoError(t, err)
+		require.Empty(t, resp.Added)
+		require.Equal(t, []*commands.WhatIfPermission{
+			{User: "user:anne", Relation: "viewer", Object: "document:plan"},
+		}, resp.Removed)
+	})
+
+	t.Run("invalid_request", func(t *testing.T) {
+		_, err := s.WhatIf(ctx, &commands.WhatIfRequest{StoreID: storeID})
+		require.ErrorContains(t, err, "at least one write or delete is required")
+	})
+
+	t.Run("http", func(t *testing.T) {
+		mux := newTestHTTPMux(t, s)
+
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/stores/"+storeID+"/what-if", strings.NewReader(`{
+			"writes": [{"object": "document:plan", "relation": "blocked", "user": "user:anne"}],
+			"object_types": ["document"]
+		}`)))
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+		var resp commands.WhatIfResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		require.Equal(t, []*commands.WhatIfPermission{
+			{User: "user:anne", Relation: "blocked", Object: "document:plan"},
+		}, resp.Added)
+		require.Equal(t, []*commands.WhatIfPermission{
+			{User: "user:anne", Relation: "viewer", Object: "document:plan"},
+		}, resp.Removed)
+
+		rec = httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/stores/"+storeID+"/what-if", strings.NewReader(`{"writes": [{"object": 1}]}`)))
+		require.Equal(t, http.StatusBadRequest, rec.Code)
+		require.Contains(t, rec.Body.String(), "invalid request body")
+	})
+}
@@ -20,6 +20,21 @@ import (
 )
 
 func (s *Server) Write(ctx context.Context, req *openfgav1.WriteRequest) (*openfgav1.WriteResponse, error) {
+	return s.write(ctx, req, nil)
+}
+
+// WriteWithPreconditions is like Write, but the tuples are only deleted and written if the preconditions of
+// the request hold when the write is applied. Otherwise, it returns a FailedPrecondition error and changes
+// nothing.
+func (s *Server) WriteWithPreconditions(ctx context.Context, req *commands.WriteWithPreconditionsRequest) (*openfgav1.WriteResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return s.write(ctx, req.WriteRequest, req)
+}
+
+// write deletes and writes the tuples of req. If preconditions is not nil, its preconditions must hold.
+func (s *Server) write(ctx context.Context, req *openfgav1.WriteRequest, preconditions *commands.WriteWithPreconditionsRequest) (*openfgav1.WriteResponse, error) {
 	start := time.Now()
 
 	ctx, span := tracer.Start(ctx, apimethod.Write.String(), trace.WithAttributes(
@@ -54,12 +69,23 @@ func (s *Server) Write(ctx context.Context, req *openfgav1.WriteRequest) (*openf
 		s.datastore,
 		commands.WithWriteCmdLogger(s.logger),
 	)
-	resp, err := cmd.Execute(ctx, &openfgav1.WriteRequest{
+	resolvedReq := &openfgav1.WriteRequest{
 		StoreId:              storeID,
 		AuthorizationModelId: typesys.GetAuthorizationModelID(), // the resolved model id
 		Writes:               req.GetWrites(),
 		Deletes:              req.GetDeletes(),
-	})
+	}
+	var resp *openfgav1.WriteResponse
+	if preconditions != nil {
+		resp, err = cmd.ExecuteWithPreconditions(ctx, &commands.WriteWithPreconditionsRequest{
+			WriteRequest:     resolvedReq,
+			TuplesExist:      preconditions.TuplesExist,
+			TuplesNotExist:   preconditions.TuplesNotExist,
+			LatestChangeULID: preconditions.LatestChangeULID,
+		})
+	} else {
+		resp, err = cmd.Execute(ctx, resolvedReq)
+	}
 
 	// For now, we only measure the duration if it passes the authz step to make the comparison
 	// apple to apple.
@@ -0,0 +1,98 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+
+	"github.com/openfga/openfga/pkg/server/commands"
+	"github.com/openfga/openfga/pkg/tuple"
+)
+
+func TestWriteWithPreconditions(t *testing.T) {
+	ctx := context.Background()
+
+	s, _, storeID := newTestServer(t)
+
+	writeTestModel(t, s, storeID, `
+		model
+			schema 1.1
+		type user
+		type document
+			relations
+				define viewer: [user]`)
+
+	_, err := s.Write(ctx, &openfgav1.WriteRequest{
+		StoreId: storeID,
+		Writes: &openfgav1.WriteRequestWrites{TupleKeys: []*openfgav1.TupleKey{
+			tuple.NewTupleKey("document:1", "viewer", "user:anne"),
+		}},
+	})
+	require.NoError(t, err)
+
+	t.Run("server", func(t *testing.T) {
+		stats, err := s.GetStoreStats(ctx, &commands.GetStoreStatsRequest{StoreID: storeID})
+		require.NoError(t, err)
+
+		req := &commands.WriteWithPreconditionsRequest{
+			WriteRequest: &openfgav1.WriteRequest{
+				StoreId: storeID,
+				Writes: &openfgav1.WriteRequestWrites{TupleKeys: []*openfgav1.TupleKey{
+					tuple.NewTupleKey("document:1", "viewer", "user:bob"),
+				}},
+			},
+			LatestChangeULID: stats.LatestChangeULID,
+		}
+		_, err = s.WriteWithPreconditions(ctx, req)
+		require.NoError(t, err)
+
+		// The first write changed the latest change of the store.
+		req.Writes = &openfgav1.WriteRequestWrites{TupleKeys: []*openfgav1.TupleKey{
+			tuple.NewTupleKey("document:1", "viewer", "user:charlie"),
+		}}
+		_, err = s.WriteWithPreconditions(ctx, req)
+		require.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+		_, err = s.WriteWithPreconditions(ctx, &commands.WriteWithPreconditionsRequest{WriteRequest: req.WriteRequest})
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("http", func(t *testing.T) {
+		mux := newTestHTTPMux(t, s)
+
+		write := func(body string) int {
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/stores/"+storeID+"/write/preconditions", strings.NewReader(body)))
+			return rec.Code
+		}
+
+		code := write(`{
+			"writes": {"tuple_keys": [{"object": "document:2", "relation": "viewer", "user": "user:anne"}]},
+			"preconditions": {
+				"tuples_exist": [{"object": "document:1", "relation": "viewer", "user": "user:anne"}],
+				"tuples_not_exist": [{"object": "document:2", "relation": "viewer", "user": "user:anne"}]
+			}
+		}`)
+		require.Equal(t, http.StatusOK, code)
+
+		code = write(`{
+			"deletes": {"tuple_keys": [{"object": "document:1", "relation": "viewer", "user": "user:anne"}]},
+			"preconditions": {"tuples_not_exist": [{"object": "document:2", "relation": "viewer", "user": "user:anne"}]}
+		}`)
+		require.Equal(t, http.StatusPreconditionFailed, code)
+
+		code = write(`{"writes": {"tuple_keys": [{"object": "document:3", "relation": "viewer", "user": "user:anne"}]}}`)
+		require.Equal(t, http.StatusBadRequest, code)
+
+		code = write(`{"preconditions": {"latest_change_ulid": 1}}`)
+		require.Equal(t, http.StatusBadRequest, code)
+	})
+}
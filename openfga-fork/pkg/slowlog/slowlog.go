@@ -0,0 +1,280 @@
+// Package slowlog records the authorization requests that take longer than the threshold of their method in
+// a bounded in-memory ring buffer and in the logs.
+package slowlog
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	grpc_ctxtags "github.com/grpc-ecosystem/go-grpc-middleware/tags"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/openfga/openfga/internal/build"
+	"github.com/openfga/openfga/internal/redaction"
+	"github.com/openfga/openfga/pkg/logger"
+)
+
+const (
+	requestIDTag = "request_id"
+
+	contextualTuplesField protoreflect.Name = "contextual_tuples"
+	contextField          protoreflect.Name = "context"
+	tupleKeysField        protoreflect.Name = "tuple_keys"
+)
+
+var slowRequestCount = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: build.ProjectName,
+	Name:      "slow_request_count",
+	Help:      "The total number of requests that took longer than the slow request threshold of their method.",
+}, []string{"grpc_method"})
+
+// Entry is a slow request. For Check requests, Resolvers are the resolvers ("default", "weight2" or "recursive")
+// used to resolve the request, and PlannerDecisions the strategy selected by the planner for each of its plan
+// keys. For ListObjects requests, Resolvers is "weighted" or "non-weighted" depending on whether the weighted
+// graph was used. The contextual tuples of Request are replaced by their number, ContextualTupleCount, and
+// the values of its condition context by redaction.Placeholder, as in the decision log.
+type Entry struct {
+	Time                 time.Time         `json:"time"`
+	Method               string            `json:"method"`
+	StoreID              string            `json:"store_id"`
+	AuthorizationModelID string            `json:"authorization_model_id"`
+	RequestID            string            `json:"request_id,omitempty"`
+	DurationMs           int64             `json:"duration_ms"`
+	ThresholdMs          int64             `json:"threshold_ms"`
+	Request              json.RawMessage   `json:"request,omitempty"`
+	ContextualTupleCount int               `json:"contextual_tuple_count"`
+	DispatchCount        uint32            `json:"dispatch_count"`
+	DatastoreQueryCount  uint32            `json:"datastore_query_count"`
+	Resolvers            []string          `json:"resolvers,omitempty"`
+	PlannerDecisions     map[string]string `json:"planner_decisions,omitempty"`
+	Error                string            `json:"error,omitempty"`
+}
+
+// Log keeps the most recent slow requests.
+type Log struct {
+	threshold        time.Duration
+	methodThresholds map[string]time.Duration
+	logger           logger.Logger
+
+	mu      sync.Mutex
+	entries []*Entry
+	next    int
+	full    bool
+}
+
+type LogOption func(*Log)
+
+// WithThreshold sets the duration over which the requests of the methods without their own threshold are slow.
+// The default is one second.
+func WithThreshold(threshold time.Duration) LogOption {
+	return func(l *Log) {
+		l.threshold = threshold
+	}
+}
+
+// WithMethodThresholds overrides the threshold of some methods, e.g. Check.
+func WithMethodThresholds(thresholds map[string]time.Duration) LogOption {
+	return func(l *Log) {
+		l.methodThresholds = thresholds
+	}
+}
+
+// WithLogger sets the logger the slow requests are written to.
+func WithLogger(logger logger.Logger) LogOption {
+	return func(l *Log) {
+		l.logger = logger
+	}
+}
+
+// NewLog returns a Log that keeps the capacity most recent slow requests.
+func NewLog(capacity int, opts ...LogOption) *Log {
+	l := &Log{
+		threshold: time.Second,
+		logger:    logger.NewNoopLogger(),
+		entries:   make([]*Entry, capacity),
+	}
+
+	for _, opt := range opts {
+		opt(l)
+	}
+
+	return l
+}
+
+// Threshold returns the duration over which the requests of method are slow.
+func (l *Log) Threshold(method string) time.Duration {
+	if threshold, ok := l.methodThresholds[method]; ok {
+		return threshold
+	}
+	return l.threshold
+}
+
+// Slow reports whether a request of method that took duration is slow, so that the callers only build the
+// entries of the slow requests.
+func (l *Log) Slow(method string, duration time.Duration) bool {
+	return duration >= l.Threshold(method)
+}
+
+// Record logs the entry of req and adds it to the ring buffer. The time, duration, threshold, redacted request
+// and request ID of the entry are set from req, duration and ctx.
+func (l *Log) Record(ctx context.Context, req proto.Message, duration time.Duration, entry *Entry) {
+	entry.Time = time.Now().UTC()
+	entry.DurationMs = duration.Milliseconds()
+	entry.ThresholdMs = l.Threshold(entry.Method).Milliseconds()
+	if requestID, ok := grpc_ctxtags.Extract(ctx).Values()[requestIDTag].(string); ok {
+		entry.RequestID = requestID
+	}
+
+	redacted := proto.Clone(req)
+	entry.ContextualTupleCount = redact(redacted.ProtoReflect())
+
+	request, err := protojson.Marshal(redacted)
+	if err != nil {
+		l.logger.WarnWithContext(ctx, "failed to marshal slow request", zap.String("method", entry.Method), zap.Error(err))
+	} else {
+		entry.Request = request
+	}
+
+	slowRequestCount.WithLabelValues(entry.Method).Inc()
+
+	l.logger.WarnWithContext(ctx, "slow request",
+		zap.String("method", entry.Method),
+		zap.String("store_id", entry.StoreID),
+		zap.String("authorization_model_id", entry.AuthorizationModelID),
+		zap.Int64("duration_ms", entry.DurationMs),
+		zap.Int64("threshold_ms", entry.ThresholdMs),
+		zap.Uint32("dispatch_count", entry.DispatchCount),
+		zap.Uint32("datastore_query_count", entry.DatastoreQueryCount),
+		zap.Strings("resolvers", entry.Resolvers),
+		zap.Any("planner_decisions", entry.PlannerDecisions),
+		zap.ByteString("request", entry.Request),
+		zap.Int("contextual_tuple_count", entry.ContextualTupleCount),
+		zap.String("error", entry.Error),
+	)
+
+	if len(l.entries) == 0 {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.entries[l.next] = entry
+	l.next = (l.next + 1) % len(l.entries)
+	if l.next == 0 {
+		l.full = true
+	}
+}
+
+// redact clears the contextual tuples of m and of its nested messages, e.g. the checks of a BatchCheck request,
+// and replaces the values of their condition context by redaction.Placeholder. It returns the number of
+// contextual tuples cleared.
+func redact(m protoreflect.Message) int {
+	count := 0
+	var cleared []protoreflect.FieldDescriptor
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		switch {
+		case fd.Message() == nil || fd.IsMap():
+		case fd.Name() == contextualTuplesField:
+			count += contextualTupleCount(fd, v)
+			cleared = append(cleared, fd)
+		case fd.Name() == contextField && !fd.IsList():
+			if conditionContext, ok := v.Message().Interface().(*structpb.Struct); ok {
+				for key := range conditionContext.GetFields() {
+					conditionContext.Fields[key] = structpb.NewStringValue(redaction.Placeholder)
+				}
+			}
+		case fd.IsList():
+			list := v.List()
+			for i := 0; i < list.Len(); i++ {
+				count += redact(list.Get(i).Message())
+			}
+		default:
+			count += redact(v.Message())
+		}
+		return true
+	})
+
+	// The fields are cleared once the range is over, since m must not be modified while it is ranged over.
+	for _, fd := range cleared {
+		m.Clear(fd)
+	}
+	return count
+}
+
+// contextualTupleCount returns the number of contextual tuples in v, which is either a list of tuples, as in
+// ListUsers requests, or a message with a list of tuple keys, as in Check requests.
+func contextualTupleCount(fd protoreflect.FieldDescriptor, v protoreflect.Value) int {
+	if fd.IsList() {
+		return v.List().Len()
+	}
+	if tupleKeys := fd.Message().Fields().ByName(tupleKeysField); tupleKeys != nil && tupleKeys.IsList() {
+		return v.Message().Get(tupleKeys).List().Len()
+	}
+	return 0
+}
+
+// Entries returns the slow requests in the ring buffer, most recent first.
+func (l *Log) Entries() []*Entry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	count := l.next
+	if l.full {
+		count = len(l.entries)
+	}
+
+	entries := make([]*Entry, 0, count)
+	for i := 1; i <= count; i++ {
+		entries = append(entries, l.entries[(l.next-i+len(l.entries))%len(l.entries)])
+	}
+	return entries
+}
+
+// ServeHTTP writes the slow requests in the ring buffer as a JSON object, most recent first. The entries can be
+// filtered with the 'method' and 'store_id' query parameters, and their number bounded with 'limit'.
+func (l *Log) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	query := r.URL.Query()
+	limit := -1
+	if value := query.Get("limit"); value != "" {
+		var err error
+		if limit, err = strconv.Atoi(value); err != nil || limit < 0 {
+			http.Error(w, "'limit' must be a non-negative integer", http.StatusBadRequest)
+			return
+		}
+	}
+
+	entries := make([]*Entry, 0)
+	for _, entry := range l.Entries() {
+		if limit >= 0 && len(entries) >= limit {
+			break
+		}
+		if method := query.Get("method"); method != "" && entry.Method != method {
+			continue
+		}
+		if storeID := query.Get("store_id"); storeID != "" && entry.StoreID != storeID {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(struct {
+		Entries []*Entry `json:"entries"`
+	}{Entries: entries})
+}
@@ -0,0 +1,155 @@
+package slowlog
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+
+	"github.com/openfga/openfga/pkg/testutils"
+	"github.com/openfga/openfga/pkg/tuple"
+)
+
+func TestSlow(t *testing.T) {
+	l := NewLog(10, WithThreshold(time.Second), WithMethodThresholds(map[string]time.Duration{"Check": 100 * time.Millisecond}))
+
+	require.True(t, l.Slow("Check", 100*time.Millisecond))
+	require.False(t, l.Slow("Check", 99*time.Millisecond))
+	require.False(t, l.Slow("ListObjects", 100*time.Millisecond))
+	require.True(t, l.Slow("ListObjects", time.Second))
+}
+
+func TestRecord(t *testing.T) {
+	t.Run("sets_the_request_and_durations", func(t *testing.T) {
+		l := NewLog(10, WithThreshold(time.Second))
+
+		l.Record(context.Background(), &openfgav1.CheckRequest{
+			StoreId:  "01K3RZVNE3NJ4FYKK99QN013G2",
+			TupleKey: tuple.NewCheckRequestTupleKey("document:1", "viewer", "user:jon"),
+		}, 1500*time.Millisecond, &Entry{
+			Method:              "Check",
+			StoreID:             "01K3RZVNE3NJ4FYKK99QN013G2",
+			DispatchCount:       3,
+			DatastoreQueryCount: 5,
+			Resolvers:           []string{"recursive"},
+		})
+
+		entries := l.Entries()
+		require.Len(t, entries, 1)
+		require.False(t, entries[0].Time.IsZero())
+		require.Equal(t, int64(1500), entries[0].DurationMs)
+		require.Equal(t, int64(1000), entries[0].ThresholdMs)
+		require.Equal(t, uint32(3), entries[0].DispatchCount)
+
+		var request map[string]any
+		require.NoError(t, json.Unmarshal(entries[0].Request, &request))
+		require.Equal(t, "01K3RZVNE3NJ4FYKK99QN013G2", request["store_id"])
+	})
+
+	t.Run("redacts_the_contextual_tuples_and_context", func(t *testing.T) {
+		l := NewLog(10)
+
+		req := &openfgav1.BatchCheckRequest{
+			StoreId: "01K3RZVNE3NJ4FYKK99QN013G2",
+			Checks: []*openfgav1.BatchCheckItem{
+				{
+					TupleKey: tuple.NewCheckRequestTupleKey("document:1", "viewer", "user:jon"),
+					ContextualTuples: &openfgav1.ContextualTupleKeys{
+						TupleKeys: []*openfgav1.TupleKey{
+							tuple.NewTupleKey("document:1", "viewer", "user:jon"),
+							tuple.NewTupleKey("document:1", "viewer", "user:maria"),
+						},
+					},
+					Context: testutils.MustNewStruct(t, map[string]any{"ip": "10.0.0.1"}),
+				},
+			},
+		}
+		l.Record(context.Background(), req, time.Second, &Entry{Method: "BatchCheck"})
+
+		entries := l.Entries()
+		require.Len(t, entries, 1)
+		require.Equal(t, 2, entries[0].ContextualTupleCount)
+		require.NotContains(t, string(entries[0].Request), "contextual_tuples")
+		require.NotContains(t, string(entries[0].Request), "10.0.0.1")
+		require.Contains(t, string(entries[0].Request), "REDACTED")
+
+		// The request itself is not modified.
+		require.Len(t, req.GetChecks()[0].GetContextualTuples().GetTupleKeys(), 2)
+		require.Equal(t, "10.0.0.1", req.GetChecks()[0].GetContext().GetFields()["ip"].GetStringValue())
+	})
+
+	t.Run("keeps_the_most_recent_entries", func(t *testing.T) {
+		l := NewLog(2)
+
+		for _, method := range []string{"Check", "ListObjects", "StreamedListObjects"} {
+			l.Record(context.Background(), &openfgav1.CheckRequest{}, time.Second, &Entry{Method: method})
+		}
+
+		entries := l.Entries()
+		require.Len(t, entries, 2)
+		require.Equal(t, "StreamedListObjects", entries[0].Method)
+		require.Equal(t, "ListObjects", entries[1].Method)
+	})
+
+	t.Run("zero_capacity", func(t *testing.T) {
+		l := NewLog(0)
+
+		l.Record(context.Background(), &openfgav1.CheckRequest{}, time.Second, &Entry{Method: "Check"})
+
+		require.Empty(t, l.Entries())
+	})
+}
+
+func TestServeHTTP(t *testing.T) {
+	l := NewLog(10)
+	l.Record(context.Background(), &openfgav1.CheckRequest{}, time.Second, &Entry{Method: "Check", StoreID: "store1"})
+	l.Record(context.Background(), &openfgav1.ListObjectsRequest{}, time.Second, &Entry{Method: "ListObjects", StoreID: "store1"})
+	l.Record(context.Background(), &openfgav1.CheckRequest{}, time.Second, &Entry{Method: "Check", StoreID: "store2"})
+
+	get := func(t *testing.T, target string) (int, []*Entry) {
+		rec := httptest.NewRecorder()
+		l.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+		if rec.Code != http.StatusOK {
+			return rec.Code, nil
+		}
+
+		var body struct {
+			Entries []*Entry `json:"entries"`
+		}
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&body))
+		return rec.Code, body.Entries
+	}
+
+	t.Run("all", func(t *testing.T) {
+		_, entries := get(t, "/slowlog")
+		require.Len(t, entries, 3)
+		require.Equal(t, "store2", entries[0].StoreID)
+	})
+
+	t.Run("filtered", func(t *testing.T) {
+		_, entries := get(t, "/slowlog?method=Check&store_id=store1")
+		require.Len(t, entries, 1)
+		require.Equal(t, "Check", entries[0].Method)
+		require.Equal(t, "store1", entries[0].StoreID)
+	})
+
+	t.Run("limit", func(t *testing.T) {
+		_, entries := get(t, "/slowlog?limit=1")
+		require.Len(t, entries, 1)
+
+		code, _ := get(t, "/slowlog?limit=-1")
+		require.Equal(t, http.StatusBadRequest, code)
+	})
+
+	t.Run("method_not_allowed", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		l.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/slowlog", nil))
+		require.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+	})
+}
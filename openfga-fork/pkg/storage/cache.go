@@ -41,6 +41,7 @@ const (
 	iteratorCachePrefix        = "ic."
 	changelogCachePrefix       = "cc."
 	invalidIteratorCachePrefix = "iq."
+	invalidCheckCachePrefix    = "iqc."
 	defaultMaxCacheSize        = 10000
 	oneYear                    = time.Hour * 24 * 365
 
@@ -160,6 +161,16 @@ func (i InMemoryLRUCache[T]) Delete(key string) {
 	i.client.Delete(key)
 }
 
+// Len returns the number of entries in the cache, including the expired entries not removed yet.
+func (i InMemoryLRUCache[T]) Len() int {
+	return i.client.Len()
+}
+
+// Range calls f for each entry in the cache until f returns false.
+func (i InMemoryLRUCache[T]) Range(f func(key string, value T) bool) {
+	i.client.Range(f)
+}
+
 func (i InMemoryLRUCache[T]) Stop() {
 	i.stopOnce.Do(func() {
 		i.client.Close()
@@ -196,6 +207,12 @@ func GetInvalidIteratorCacheKey(storeID string) string {
 	return invalidIteratorCachePrefix + storeID
 }
 
+// GetInvalidCheckCacheKey returns the key of the entry whose timestamp invalidates the Check results cached
+// earlier for storeID.
+func GetInvalidCheckCacheKey(storeID string) string {
+	return invalidCheckCachePrefix + storeID
+}
+
 func GetInvalidIteratorByObjectRelationCacheKey(storeID, object, relation string) string {
 	return invalidIteratorCachePrefix + storeID + "-or/" + object + "#" + relation
 }
@@ -0,0 +1,188 @@
+package storage
+
+import (
+	"encoding"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// ErrCacheItemNotSerializable is returned by EncodeCacheItem when a value cannot be stored outside of the process.
+var ErrCacheItemNotSerializable = errors.New("cache item is not serializable")
+
+// SerializableCacheItem is a CacheItem that can be stored in a cache shared across processes.
+// Its type must be registered with RegisterCacheItem so that it can be decoded.
+type SerializableCacheItem interface {
+	CacheItem
+	encoding.BinaryMarshaler
+	encoding.BinaryUnmarshaler
+}
+
+var cacheItemTypes sync.Map // entity type -> func() SerializableCacheItem
+
+func init() {
+	RegisterCacheItem(func() SerializableCacheItem { return &ChangelogCacheEntry{} })
+	RegisterCacheItem(func() SerializableCacheItem { return &InvalidEntityCacheEntry{} })
+	RegisterCacheItem(func() SerializableCacheItem { return &TupleIteratorCacheEntry{} })
+}
+
+// RegisterCacheItem registers the type of the items returned by newItem, so that DecodeCacheItem
+// can decode them. Items are told apart by their CacheEntityType.
+func RegisterCacheItem(newItem func() SerializableCacheItem) {
+	cacheItemTypes.Store(newItem().CacheEntityType(), newItem)
+}
+
+// EncodeCacheItem serializes value, prefixed by its entity type. It returns ErrCacheItemNotSerializable
+// if value is not a SerializableCacheItem of a registered type.
+func EncodeCacheItem(value any) ([]byte, error) {
+	item, ok := value.(SerializableCacheItem)
+	if !ok {
+		return nil, ErrCacheItemNotSerializable
+	}
+	entityType := item.CacheEntityType()
+	if _, ok := cacheItemTypes.Load(entityType); !ok {
+		return nil, ErrCacheItemNotSerializable
+	}
+
+	payload, err := item.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	data := binary.AppendUvarint(nil, uint64(len(entityType)))
+	data = append(data, entityType...)
+	return append(data, payload...), nil
+}
+
+// DecodeCacheItem deserializes an item encoded by EncodeCacheItem.
+func DecodeCacheItem(data []byte) (CacheItem, error) {
+	n, read := binary.Uvarint(data)
+	if read <= 0 || uint64(len(data)-read) < n {
+		return nil, errors.New("malformed cache item")
+	}
+	entityType := string(data[read : read+int(n)])
+
+	newItem, ok := cacheItemTypes.Load(entityType)
+	if !ok {
+		return nil, fmt.Errorf("unknown cache item type '%s'", entityType)
+	}
+
+	item := newItem.(func() SerializableCacheItem)()
+	if err := item.UnmarshalBinary(data[read+int(n):]); err != nil {
+		return nil, err
+	}
+	return item, nil
+}
+
+var (
+	_ SerializableCacheItem = (*ChangelogCacheEntry)(nil)
+	_ SerializableCacheItem = (*InvalidEntityCacheEntry)(nil)
+	_ SerializableCacheItem = (*TupleIteratorCacheEntry)(nil)
+)
+
+func (c *ChangelogCacheEntry) MarshalBinary() ([]byte, error) {
+	return c.LastModified.MarshalBinary()
+}
+
+func (c *ChangelogCacheEntry) UnmarshalBinary(data []byte) error {
+	return c.LastModified.UnmarshalBinary(data)
+}
+
+func (i *InvalidEntityCacheEntry) MarshalBinary() ([]byte, error) {
+	return i.LastModified.MarshalBinary()
+}
+
+func (i *InvalidEntityCacheEntry) UnmarshalBinary(data []byte) error {
+	return i.LastModified.UnmarshalBinary(data)
+}
+
+// tupleRecordJSON is the serialized form of a TupleRecord. The condition context is encoded with protojson.
+type tupleRecordJSON struct {
+	Store            string          `json:"s,omitempty"`
+	ObjectType       string          `json:"ot,omitempty"`
+	ObjectID         string          `json:"oi,omitempty"`
+	Relation         string          `json:"r,omitempty"`
+	User             string          `json:"u,omitempty"`
+	UserObjectType   string          `json:"ut,omitempty"`
+	UserObjectID     string          `json:"ui,omitempty"`
+	UserRelation     string          `json:"ur,omitempty"`
+	ConditionName    string          `json:"cn,omitempty"`
+	ConditionContext json.RawMessage `json:"cc,omitempty"`
+	Ulid             string          `json:"id,omitempty"`
+	InsertedAt       time.Time       `json:"at"`
+}
+
+type tupleIteratorCacheEntryJSON struct {
+	Tuples       []*tupleRecordJSON `json:"tuples"`
+	LastModified time.Time          `json:"last_modified"`
+}
+
+func (t *TupleIteratorCacheEntry) MarshalBinary() ([]byte, error) {
+	entry := &tupleIteratorCacheEntryJSON{
+		Tuples:       make([]*tupleRecordJSON, 0, len(t.Tuples)),
+		LastModified: t.LastModified,
+	}
+	for _, r := range t.Tuples {
+		record := &tupleRecordJSON{
+			Store:          r.Store,
+			ObjectType:     r.ObjectType,
+			ObjectID:       r.ObjectID,
+			Relation:       r.Relation,
+			User:           r.User,
+			UserObjectType: r.UserObjectType,
+			UserObjectID:   r.UserObjectID,
+			UserRelation:   r.UserRelation,
+			ConditionName:  r.ConditionName,
+			Ulid:           r.Ulid,
+			InsertedAt:     r.InsertedAt,
+		}
+		if r.ConditionContext != nil {
+			context, err := protojson.Marshal(r.ConditionContext)
+			if err != nil {
+				return nil, err
+			}
+			record.ConditionContext = context
+		}
+		entry.Tuples = append(entry.Tuples, record)
+	}
+	return json.Marshal(entry)
+}
+
+func (t *TupleIteratorCacheEntry) UnmarshalBinary(data []byte) error {
+	entry := &tupleIteratorCacheEntryJSON{}
+	if err := json.Unmarshal(data, entry); err != nil {
+		return err
+	}
+
+	t.LastModified = entry.LastModified
+	t.Tuples = make([]*TupleRecord, 0, len(entry.Tuples))
+	for _, r := range entry.Tuples {
+		record := &TupleRecord{
+			Store:          r.Store,
+			ObjectType:     r.ObjectType,
+			ObjectID:       r.ObjectID,
+			Relation:       r.Relation,
+			User:           r.User,
+			UserObjectType: r.UserObjectType,
+			UserObjectID:   r.UserObjectID,
+			UserRelation:   r.UserRelation,
+			ConditionName:  r.ConditionName,
+			Ulid:           r.Ulid,
+			InsertedAt:     r.InsertedAt,
+		}
+		if len(r.ConditionContext) > 0 {
+			record.ConditionContext = &structpb.Struct{}
+			if err := protojson.Unmarshal(r.ConditionContext, record.ConditionContext); err != nil {
+				return err
+			}
+		}
+		t.Tuples = append(t.Tuples, record)
+	}
+	return nil
+}
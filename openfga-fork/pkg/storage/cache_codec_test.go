@@ -0,0 +1,72 @@
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/testing/protocmp"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func TestCacheItemCodec(t *testing.T) {
+	now := time.Now().UTC()
+	conditionContext, err := structpb.NewStruct(map[string]any{"ip": "10.0.0.1", "limits": []any{1, 2}})
+	require.NoError(t, err)
+
+	items := []CacheItem{
+		&ChangelogCacheEntry{LastModified: now},
+		&InvalidEntityCacheEntry{LastModified: now},
+		&TupleIteratorCacheEntry{LastModified: now, Tuples: []*TupleRecord{}},
+		&TupleIteratorCacheEntry{
+			LastModified: now,
+			Tuples: []*TupleRecord{
+				{
+					Store:          "store",
+					ObjectType:     "document",
+					ObjectID:       "1",
+					Relation:       "viewer",
+					UserObjectType: "user",
+					UserObjectID:   "anne",
+					Ulid:           "01ARZ3NDEKTSV4RRFFQ69G5FAV",
+					InsertedAt:     now,
+				},
+				{
+					ObjectType:       "document",
+					ObjectID:         "2",
+					Relation:         "viewer",
+					UserObjectType:   "group",
+					UserObjectID:     "eng",
+					UserRelation:     "member",
+					ConditionName:    "in_network",
+					ConditionContext: conditionContext,
+				},
+			},
+		},
+	}
+
+	for _, item := range items {
+		t.Run(item.CacheEntityType(), func(t *testing.T) {
+			data, err := EncodeCacheItem(item)
+			require.NoError(t, err)
+
+			decoded, err := DecodeCacheItem(data)
+			require.NoError(t, err)
+			require.Empty(t, cmp.Diff(item, decoded, protocmp.Transform()))
+		})
+	}
+
+	t.Run("not_serializable", func(t *testing.T) {
+		_, err := EncodeCacheItem("value")
+		require.ErrorIs(t, err, ErrCacheItemNotSerializable)
+	})
+
+	t.Run("malformed", func(t *testing.T) {
+		_, err := DecodeCacheItem([]byte{200})
+		require.ErrorContains(t, err, "malformed cache item")
+
+		_, err = DecodeCacheItem(append([]byte{7}, "unknown"...))
+		require.ErrorContains(t, err, "unknown cache item type 'unknown'")
+	})
+}
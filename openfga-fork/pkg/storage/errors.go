@@ -32,6 +32,9 @@ var (
 	// ErrTransactionalWriteFailed is returned when two writes attempt to write the same tuple at the same time.
 	ErrTransactionalWriteFailed = errors.New("transactional write failed due to conflict")
 
+	// ErrWritePreconditionFailed is returned when a precondition of a write does not hold.
+	ErrWritePreconditionFailed = errors.New("write precondition failed")
+
 	// ErrTransactionThrottled is returned when throttling is applied at the datastore level.
 	ErrTransactionThrottled = errors.New("transaction throttled")
 
@@ -76,3 +79,9 @@ func TupleConditionConflictError(tk tuple.TupleWithoutCondition) error {
 		tk.GetObject(),
 	)
 }
+
+// WritePreconditionFailedError returns an error wrapping ErrWritePreconditionFailed that describes the
+// precondition that does not hold.
+func WritePreconditionFailedError(format string, args ...any) error {
+	return fmt.Errorf("%w: %s", ErrWritePreconditionFailed, fmt.Sprintf(format, args...))
+}
@@ -134,7 +134,9 @@ type MemoryBackend struct {
 	// AuthorizationModelBackend
 	// map: store = > map: type definition id => type definition
 	authorizationModels map[string]map[string]*AuthorizationModelEntry // GUARDED_BY(mutexModels).
-	mutexModels         sync.RWMutex
+	// map: store => pinned authz model id
+	activeAuthorizationModels map[string]string // GUARDED_BY(mutexModels).
+	mutexModels               sync.RWMutex
 
 	// map: store id => store data
 	stores      map[string]*openfgav1.Store // GUARDED_BY(mutexStores).
@@ -145,8 +147,12 @@ type MemoryBackend struct {
 	mutexAssertions sync.RWMutex
 }
 
-// Ensures that [MemoryBackend] implements the [storage.OpenFGADatastore] interface.
-var _ storage.OpenFGADatastore = (*MemoryBackend)(nil)
+// Ensures that [MemoryBackend] implements the [storage.OpenFGADatastore] interface and its optional backends.
+var (
+	_ storage.OpenFGADatastore   = (*MemoryBackend)(nil)
+	_ storage.TupleSearchBackend = (*MemoryBackend)(nil)
+	_ storage.StoreStatsBackend  = (*MemoryBackend)(nil)
+)
 
 // AuthorizationModelEntry represents an entry in a storage system
 // that holds information about an authorization model.
@@ -163,6 +169,7 @@ func New(opts ...StorageOption) storage.OpenFGADatastore {
 		tuples:                        make(map[string][]*storage.TupleRecord, 0),
 		changes:                       make(map[string][]*tupleChangeRec, 0),
 		authorizationModels:           make(map[string]map[string]*AuthorizationModelEntry),
+		activeAuthorizationModels:     make(map[string]string),
 		stores:                        make(map[string]*openfgav1.Store, 0),
 		assertions:                    make(map[string][]*openfgav1.Assertion, 0),
 	}
@@ -212,6 +219,119 @@ func (s *MemoryBackend) ReadPage(ctx context.Context, store string, key *openfga
 	return it.ToArray(ctx)
 }
 
+// SearchTuples see [storage.TupleSearchBackend].SearchTuples.
+func (s *MemoryBackend) SearchTuples(ctx context.Context, store string, filter storage.SearchTuplesFilter, options storage.SearchTuplesOptions) ([]*openfgav1.Tuple, string, error) {
+	_, span := tracer.Start(ctx, "memory.SearchTuples")
+	defer span.End()
+
+	s.mutexTuples.RLock()
+	defer s.mutexTuples.RUnlock()
+
+	var matches []*storage.TupleRecord
+	for _, t := range s.tuples[store] {
+		if options.Pagination.From != "" && t.Ulid < options.Pagination.From {
+			continue
+		}
+		if searchMatch(t, filter) {
+			matches = append(matches, t)
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		return matches[i].Ulid < matches[j].Ulid
+	})
+
+	var contToken string
+	if options.Pagination.PageSize > 0 && len(matches) > options.Pagination.PageSize {
+		contToken = matches[options.Pagination.PageSize].Ulid
+		matches = matches[:options.Pagination.PageSize]
+	}
+
+	res := make([]*openfgav1.Tuple, 0, len(matches))
+	for _, t := range matches {
+		res = append(res, t.AsTuple())
+	}
+	return res, contToken, nil
+}
+
+// searchMatch returns true if t [*storage.TupleRecord] matches every non-empty field of the filter.
+func searchMatch(t *storage.TupleRecord, filter storage.SearchTuplesFilter) bool {
+	if filter.ObjectType != "" && t.ObjectType != filter.ObjectType {
+		return false
+	}
+	if !strings.HasPrefix(t.ObjectID, filter.ObjectIDPrefix) {
+		return false
+	}
+	if filter.Relation != "" && t.Relation != filter.Relation {
+		return false
+	}
+	if filter.UserType != "" && !strings.HasPrefix(t.User, filter.UserType+":") {
+		return false
+	}
+	if !strings.HasPrefix(t.User, filter.UserPrefix) {
+		return false
+	}
+	if filter.ConditionName != "" && t.ConditionName != filter.ConditionName {
+		return false
+	}
+	if filter.FromULID != "" && t.Ulid < filter.FromULID {
+		return false
+	}
+	if filter.ToULID != "" && t.Ulid >= filter.ToULID {
+		return false
+	}
+	return true
+}
+
+// ReadStoreStats see [storage.StoreStatsBackend].ReadStoreStats.
+func (s *MemoryBackend) ReadStoreStats(ctx context.Context, store string) (*storage.StoreStats, error) {
+	_, span := tracer.Start(ctx, "memory.ReadStoreStats")
+	defer span.End()
+
+	stats := &storage.StoreStats{}
+
+	s.mutexTuples.RLock()
+	counts := map[storage.TupleCount]int64{}
+	for _, t := range s.tuples[store] {
+		userType, _ := tupleUtils.SplitObject(t.User)
+		counts[storage.TupleCount{ObjectType: t.ObjectType, Relation: t.Relation, UserType: userType}]++
+	}
+	stats.LatestChangeULID = latestChangeULID(s.changes[store])
+	stats.ChangelogCount = int64(len(s.changes[store]))
+	s.mutexTuples.RUnlock()
+
+	for key, count := range counts {
+		key.Count = count
+		stats.TupleCounts = append(stats.TupleCounts, key)
+	}
+	sort.Slice(stats.TupleCounts, func(i, j int) bool {
+		a, b := stats.TupleCounts[i], stats.TupleCounts[j]
+		if a.ObjectType != b.ObjectType {
+			return a.ObjectType < b.ObjectType
+		}
+		if a.Relation != b.Relation {
+			return a.Relation < b.Relation
+		}
+		return a.UserType < b.UserType
+	})
+
+	s.mutexModels.RLock()
+	stats.AuthorizationModelCount = int64(len(s.authorizationModels[store]))
+	s.mutexModels.RUnlock()
+
+	return stats, nil
+}
+
+// latestChangeULID returns the highest ULID of changes, or an empty string if there are no changes.
+func latestChangeULID(changes []*tupleChangeRec) string {
+	var latest string
+	for _, change := range changes {
+		if id := change.Ulid.String(); id > latest {
+			latest = id
+		}
+	}
+	return latest
+}
+
 // ReadChanges see [storage.ChangelogBackend].ReadChanges.
 func (s *MemoryBackend) ReadChanges(ctx context.Context, store string, filter storage.ReadChangesFilter, options storage.ReadChangesOptions) ([]*openfgav1.TupleChange, string, error) {
 	_, span := tracer.Start(ctx, "memory.ReadChanges")
@@ -340,8 +460,16 @@ func (s *MemoryBackend) Write(ctx context.Context, store string, deletes storage
 	defer s.mutexTuples.Unlock()
 
 	now := timestamppb.Now()
+	options := storage.NewTupleWriteOptions(opts...)
 
-	duplicateDeletes, _, err := sanitizeTuplesWriteDelete(s.tuples[store], deletes, writes, storage.NewTupleWriteOptions(opts...))
+	err := options.Preconditions.Check(func(tk *openfgav1.TupleKeyWithoutCondition) bool {
+		return find(s.tuples[store], tupleUtils.TupleKeyWithoutConditionToTupleKey(tk)) != nil
+	}, latestChangeULID(s.changes[store]))
+	if err != nil {
+		return err
+	}
+
+	duplicateDeletes, _, err := sanitizeTuplesWriteDelete(s.tuples[store], deletes, writes, options)
 	if err != nil {
 		return err
 	}
@@ -696,6 +824,41 @@ func (s *MemoryBackend) FindLatestAuthorizationModel(ctx context.Context, store
 	return nsc, nil
 }
 
+// ReadActiveAuthorizationModelID see [storage.AuthorizationModelReadBackend].ReadActiveAuthorizationModelID.
+func (s *MemoryBackend) ReadActiveAuthorizationModelID(ctx context.Context, store string) (string, error) {
+	_, span := tracer.Start(ctx, "memory.ReadActiveAuthorizationModelID")
+	defer span.End()
+
+	s.mutexModels.RLock()
+	defer s.mutexModels.RUnlock()
+
+	modelID, ok := s.activeAuthorizationModels[store]
+	if !ok {
+		telemetry.TraceError(span, storage.ErrNotFound)
+		return "", storage.ErrNotFound
+	}
+
+	return modelID, nil
+}
+
+// WriteActiveAuthorizationModelID see [storage.ActiveAuthorizationModelWriteBackend].WriteActiveAuthorizationModelID.
+func (s *MemoryBackend) WriteActiveAuthorizationModelID(ctx context.Context, store string, modelID string) error {
+	_, span := tracer.Start(ctx, "memory.WriteActiveAuthorizationModelID")
+	defer span.End()
+
+	s.mutexModels.Lock()
+	defer s.mutexModels.Unlock()
+
+	if modelID == "" {
+		delete(s.activeAuthorizationModels, store)
+		return nil
+	}
+
+	s.activeAuthorizationModels[store] = modelID
+
+	return nil
+}
+
 // WriteAuthorizationModel see [storage.TypeDefinitionWriteBackend].WriteAuthorizationModel.
 func (s *MemoryBackend) WriteAuthorizationModel(ctx context.Context, store string, model *openfgav1.AuthorizationModel) error {
 	_, span := tracer.Start(ctx, "memory.WriteAuthorizationModel")
@@ -743,7 +906,7 @@ func (s *MemoryBackend) CreateStore(ctx context.Context, newStore *openfgav1.Sto
 	return s.stores[newStore.GetId()], nil
 }
 
-// DeleteStore removes a store from the [MemoryBackend].
+// DeleteStore removes a store, and its model pin, from the [MemoryBackend].
 func (s *MemoryBackend) DeleteStore(ctx context.Context, id string) error {
 	_, span := tracer.Start(ctx, "memory.DeleteStore")
 	defer span.End()
@@ -752,6 +915,11 @@ func (s *MemoryBackend) DeleteStore(ctx context.Context, id string) error {
 	defer s.mutexStores.Unlock()
 
 	delete(s.stores, id)
+
+	s.mutexModels.Lock()
+	defer s.mutexModels.Unlock()
+
+	delete(s.activeAuthorizationModels, id)
 	return nil
 }
 
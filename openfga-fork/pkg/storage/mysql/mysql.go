@@ -46,8 +46,12 @@ type Datastore struct {
 	versionReady           bool
 }
 
-// Ensures that Datastore implements the OpenFGADatastore interface.
-var _ storage.OpenFGADatastore = (*Datastore)(nil)
+// Ensures that Datastore implements the OpenFGADatastore interface and its optional backends.
+var (
+	_ storage.OpenFGADatastore   = (*Datastore)(nil)
+	_ storage.TupleSearchBackend = (*Datastore)(nil)
+	_ storage.StoreStatsBackend  = (*Datastore)(nil)
+)
 
 // New creates a new [Datastore] storage.
 func New(uri string, cfg *sqlcommon.Config) (*Datastore, error) {
@@ -158,6 +162,28 @@ func (s *Datastore) ReadPage(ctx context.Context, store string, tupleKey *openfg
 	return iter.ToArray(ctx, options.Pagination)
 }
 
+// SearchTuples see [storage.TupleSearchBackend].SearchTuples.
+func (s *Datastore) SearchTuples(ctx context.Context, store string, filter storage.SearchTuplesFilter, options storage.SearchTuplesOptions) ([]*openfgav1.Tuple, string, error) {
+	ctx, span := startTrace(ctx, "SearchTuples")
+	defer span.End()
+
+	sb := s.stbl.
+		Select(
+			"store", "object_type", "object_id", "relation",
+			"_user",
+			"condition_name", "condition_context", "ulid", "inserted_at",
+		).
+		From("tuple").
+		Where(sq.Eq{"store": store})
+	sb = sqlcommon.AddSearchTuplesFilter(sb, filter, options.Pagination)
+	sb = sb.Limit(uint64(options.Pagination.PageSize + 1)) // + 1 is used to determine whether to return a continuation token.
+
+	iter := sqlcommon.NewSQLTupleIterator(sb, HandleSQLError)
+	defer iter.Stop()
+
+	return iter.ToArray(ctx, options.Pagination)
+}
+
 func (s *Datastore) read(ctx context.Context, store string, tupleKey *openfgav1.TupleKey, options *storage.ReadPageOptions) (*sqlcommon.SQLTupleIterator, error) {
 	_, span := startTrace(ctx, "read")
 	defer span.End()
@@ -442,6 +468,44 @@ func (s *Datastore) FindLatestAuthorizationModel(ctx context.Context, store stri
 	return sqlcommon.FindLatestAuthorizationModel(ctx, s.dbInfo, store)
 }
 
+// ReadStoreStats see [storage.StoreStatsBackend].ReadStoreStats.
+func (s *Datastore) ReadStoreStats(ctx context.Context, store string) (*storage.StoreStats, error) {
+	ctx, span := startTrace(ctx, "ReadStoreStats")
+	defer span.End()
+
+	return sqlcommon.ReadStoreStats(ctx, s.dbInfo, store)
+}
+
+// ReadActiveAuthorizationModelID see [storage.AuthorizationModelReadBackend].ReadActiveAuthorizationModelID.
+func (s *Datastore) ReadActiveAuthorizationModelID(ctx context.Context, store string) (string, error) {
+	ctx, span := startTrace(ctx, "ReadActiveAuthorizationModelID")
+	defer span.End()
+
+	return sqlcommon.ReadActiveAuthorizationModelID(ctx, s.dbInfo, store)
+}
+
+// WriteActiveAuthorizationModelID see [storage.ActiveAuthorizationModelWriteBackend].WriteActiveAuthorizationModelID.
+func (s *Datastore) WriteActiveAuthorizationModelID(ctx context.Context, store string, modelID string) error {
+	ctx, span := startTrace(ctx, "WriteActiveAuthorizationModelID")
+	defer span.End()
+
+	if modelID == "" {
+		return sqlcommon.DeleteActiveAuthorizationModelID(ctx, s.dbInfo, store)
+	}
+
+	_, err := s.stbl.
+		Insert("active_authorization_model").
+		Columns("store", "authorization_model_id", "updated_at").
+		Values(store, modelID, sq.Expr("NOW()")).
+		Suffix("ON DUPLICATE KEY UPDATE authorization_model_id = ?, updated_at = NOW()", modelID).
+		ExecContext(ctx)
+	if err != nil {
+		return HandleSQLError(err)
+	}
+
+	return nil
+}
+
 // MaxTypesPerAuthorizationModel see [storage.TypeDefinitionWriteBackend].MaxTypesPerAuthorizationModel.
 func (s *Datastore) MaxTypesPerAuthorizationModel() int {
 	return s.maxTypesPerModelField
@@ -603,20 +667,43 @@ func (s *Datastore) ListStores(ctx context.Context, options storage.ListStoresOp
 	return stores, "", nil
 }
 
-// DeleteStore removes a store from storage.
+// DeleteStore removes a store from storage, together with its model pin.
 func (s *Datastore) DeleteStore(ctx context.Context, id string) error {
 	ctx, span := startTrace(ctx, "DeleteStore")
 	defer span.End()
 
-	_, err := s.stbl.
+	txn, err := s.db.BeginTx(ctx, &sql.TxOptions{})
+	if err != nil {
+		return HandleSQLError(err)
+	}
+	defer func() {
+		_ = txn.Rollback()
+	}()
+
+	_, err = s.stbl.
 		Update("store").
 		Set("deleted_at", sq.Expr("NOW()")).
 		Where(sq.Eq{"id": id}).
+		RunWith(txn).
 		ExecContext(ctx)
 	if err != nil {
 		return HandleSQLError(err)
 	}
 
+	_, err = s.stbl.
+		Delete("active_authorization_model").
+		Where(sq.Eq{"store": id}).
+		RunWith(txn).
+		ExecContext(ctx)
+	if err != nil {
+		return HandleSQLError(err)
+	}
+
+	err = txn.Commit()
+	if err != nil {
+		return HandleSQLError(err)
+	}
+
 	return nil
 }
 
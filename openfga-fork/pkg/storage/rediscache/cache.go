@@ -0,0 +1,396 @@
+// Package rediscache provides a [storage.InMemoryCache] shared by the replicas of a server through a
+// Redis-compatible server, with a local LRU cache in front of it, and the [Client] it uses to talk to
+// that server.
+package rediscache
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.uber.org/zap"
+
+	"github.com/openfga/openfga/internal/build"
+	"github.com/openfga/openfga/pkg/logger"
+	"github.com/openfga/openfga/pkg/storage"
+)
+
+const (
+	defaultKeyPrefix     = "openfga:"
+	defaultTimeout       = 100 * time.Millisecond
+	defaultLocalTTL      = 5 * time.Second
+	defaultLocalMaxItems = 10000
+	maxTTL               = time.Hour * 24 * 365
+
+	invalidationChannel = "invalidations"
+	maxResubscribeDelay = 5 * time.Second
+)
+
+var (
+	cacheRequestCount = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: build.ProjectName,
+		Name:      "redis_cache_request_count",
+		Help:      "The total number of Get calls on the Redis-backed cache, labeled by the tier that served them ('local', 'remote' or 'miss').",
+	}, []string{"tier"})
+
+	cacheErrorCount = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: build.ProjectName,
+		Name:      "redis_cache_error_count",
+		Help:      "The total number of failed calls to the Redis server, labeled by operation.",
+	}, []string{"operation"})
+)
+
+// Cache is a two-tier cache: entries are stored in a Redis-compatible server shared by all the replicas,
+// and recently used entries are also kept in a local LRU cache. Sets and deletes are broadcast to the other
+// replicas over Redis pub/sub, so that they drop their local copy of the entry. Local copies live at most
+// for the local TTL, which bounds staleness when a broadcast is missed.
+//
+// Values are stored in Redis with [storage.EncodeCacheItem]. Values that cannot be encoded are only cached
+// locally. Redis failures are reported as cache misses.
+type Cache[T any] struct {
+	client *client
+	local  *storage.InMemoryLRUCache[T]
+	logger logger.Logger
+
+	keyPrefix     string
+	localTTL      time.Duration
+	localMaxItems int64
+	timeout       time.Duration
+	password      string
+	db            int
+	tlsConfig     *tls.Config
+
+	// instanceID tells the invalidations sent by this cache apart from those of the other replicas.
+	instanceID string
+
+	stopOnce   sync.Once
+	done       chan struct{}
+	subscriber sync.WaitGroup
+	subMu      sync.Mutex
+	subConn    *conn
+}
+
+var _ storage.InMemoryCache[any] = (*Cache[any])(nil)
+
+type CacheOption[T any] func(*Cache[T])
+
+// WithPassword sets the password used to authenticate to the Redis server.
+func WithPassword[T any](password string) CacheOption[T] {
+	return func(c *Cache[T]) {
+		c.password = password
+	}
+}
+
+// WithDB sets the Redis database index.
+func WithDB[T any](db int) CacheOption[T] {
+	return func(c *Cache[T]) {
+		c.db = db
+	}
+}
+
+// WithTLSConfig sets the TLS configuration of the connections to the Redis server, e.g. to trust a private
+// certificate authority. It enables TLS whatever the scheme of the address.
+func WithTLSConfig[T any](config *tls.Config) CacheOption[T] {
+	return func(c *Cache[T]) {
+		c.tlsConfig = config
+	}
+}
+
+// WithKeyPrefix sets the prefix of the keys and of the invalidation channel, which lets several
+// caches share a Redis database. Replicas must use the same prefix to share entries.
+func WithKeyPrefix[T any](prefix string) CacheOption[T] {
+	return func(c *Cache[T]) {
+		c.keyPrefix = prefix
+	}
+}
+
+// WithLocalTTL sets the maximum time an entry is kept in the local cache. 0 disables the local cache
+// for the entries stored in Redis.
+func WithLocalTTL[T any](ttl time.Duration) CacheOption[T] {
+	return func(c *Cache[T]) {
+		c.localTTL = ttl
+	}
+}
+
+// WithLocalMaxItems sets the size limit (in items) of the local cache.
+func WithLocalMaxItems[T any](maxItems int64) CacheOption[T] {
+	return func(c *Cache[T]) {
+		c.localMaxItems = maxItems
+	}
+}
+
+// WithTimeout sets the timeout of every call to the Redis server.
+func WithTimeout[T any](timeout time.Duration) CacheOption[T] {
+	return func(c *Cache[T]) {
+		c.timeout = timeout
+	}
+}
+
+// WithLogger sets the logger used to report Redis failures.
+func WithLogger[T any](logger logger.Logger) CacheOption[T] {
+	return func(c *Cache[T]) {
+		c.logger = logger
+	}
+}
+
+// New returns a cache backed by the Redis-compatible server at addr (host:port, or rediss://host:port for
+// connections over TLS). The server does not need to be reachable: until it is, the cache only serves local
+// entries.
+func New[T any](addr string, opts ...CacheOption[T]) (*Cache[T], error) {
+	c := &Cache[T]{
+		logger:        logger.NewNoopLogger(),
+		keyPrefix:     defaultKeyPrefix,
+		localTTL:      defaultLocalTTL,
+		localMaxItems: defaultLocalMaxItems,
+		timeout:       defaultTimeout,
+		instanceID:    ulid.Make().String(),
+		done:          make(chan struct{}),
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	if addr == "" {
+		return nil, errors.New("the address of the Redis server is required")
+	}
+
+	var err error
+	c.client, err = newClient(addr, c.password, c.db, c.timeout)
+	if err != nil {
+		return nil, err
+	}
+	if c.tlsConfig != nil {
+		c.client.tlsConfig = c.tlsConfig
+	}
+	c.local, err = storage.NewInMemoryLRUCache([]storage.InMemoryLRUCacheOpt[T]{
+		storage.WithMaxCacheSize[T](c.localMaxItems),
+	}...)
+	if err != nil {
+		return nil, err
+	}
+
+	c.subscriber.Add(1)
+	go c.subscribe()
+
+	return c, nil
+}
+
+// Get see [storage.InMemoryCache.Get].
+func (c *Cache[T]) Get(key string) T {
+	var zero T
+	if value, ok := c.getLocal(key); ok {
+		cacheRequestCount.WithLabelValues("local").Inc()
+		return value
+	}
+
+	replies, err := c.client.do(context.Background(),
+		[]string{"GET", c.keyPrefix + key},
+		[]string{"PTTL", c.keyPrefix + key},
+	)
+	if err != nil {
+		c.reportError("get", key, err)
+		return zero
+	}
+
+	data, ok := replies[0].([]byte)
+	if !ok {
+		cacheRequestCount.WithLabelValues("miss").Inc()
+		return zero
+	}
+
+	item, err := storage.DecodeCacheItem(data)
+	if err != nil {
+		c.reportError("decode", key, err)
+		return zero
+	}
+	value, ok := item.(T)
+	if !ok {
+		cacheRequestCount.WithLabelValues("miss").Inc()
+		return zero
+	}
+
+	cacheRequestCount.WithLabelValues("remote").Inc()
+	ttl := c.localTTL
+	if remaining, ok := replies[1].(int64); ok && remaining >= 0 {
+		ttl = min(ttl, time.Duration(remaining)*time.Millisecond)
+	}
+	if ttl > 0 {
+		c.local.Set(key, value, ttl)
+	}
+	return value
+}
+
+// getLocal returns the local copy of the entry. InMemoryLRUCache returns the zero value on a miss.
+func (c *Cache[T]) getLocal(key string) (T, bool) {
+	value := c.local.Get(key)
+	return value, !reflect.ValueOf(&value).Elem().IsZero()
+}
+
+// Set see [storage.InMemoryCache.Set].
+// Note that ttl is truncated to one year, and that a ttl of 0 means one year. Negative ttl are noop.
+func (c *Cache[T]) Set(key string, value T, ttl time.Duration) {
+	if ttl < 0 {
+		return
+	}
+	if ttl == 0 || ttl >= maxTTL {
+		ttl = maxTTL
+	}
+
+	data, err := storage.EncodeCacheItem(value)
+	if err != nil {
+		if !errors.Is(err, storage.ErrCacheItemNotSerializable) {
+			c.reportError("encode", key, err)
+		}
+		c.local.Set(key, value, ttl)
+		return
+	}
+
+	if c.localTTL > 0 {
+		c.local.Set(key, value, min(ttl, c.localTTL))
+	}
+
+	_, err = c.client.do(context.Background(),
+		[]string{"SET", c.keyPrefix + key, string(data), "PX", strconv.FormatInt(max(ttl.Milliseconds(), 1), 10)},
+		c.invalidationCommand(key),
+	)
+	if err != nil {
+		c.reportError("set", key, err)
+	}
+}
+
+// Delete see [storage.InMemoryCache.Delete].
+func (c *Cache[T]) Delete(key string) {
+	c.local.Delete(key)
+
+	_, err := c.client.do(context.Background(),
+		[]string{"DEL", c.keyPrefix + key},
+		c.invalidationCommand(key),
+	)
+	if err != nil {
+		c.reportError("delete", key, err)
+	}
+}
+
+// Len returns the number of entries in the local cache. The entries stored in Redis are not counted.
+func (c *Cache[T]) Len() int {
+	return c.local.Len()
+}
+
+// Stop see [storage.InMemoryCache.Stop].
+func (c *Cache[T]) Stop() {
+	c.stopOnce.Do(func() {
+		close(c.done)
+		c.subMu.Lock()
+		if c.subConn != nil {
+			_ = c.subConn.netConn.Close()
+		}
+		c.subMu.Unlock()
+		c.subscriber.Wait()
+
+		c.client.close()
+		c.local.Stop()
+	})
+}
+
+// invalidationCommand returns the command that tells the other replicas to drop their local copy of key.
+func (c *Cache[T]) invalidationCommand(key string) []string {
+	return []string{"PUBLISH", c.keyPrefix + invalidationChannel, c.instanceID + " " + key}
+}
+
+// subscribe receives the invalidations of the other replicas until the cache is stopped,
+// and subscribes again whenever the connection is lost.
+func (c *Cache[T]) subscribe() {
+	defer c.subscriber.Done()
+
+	delay := c.timeout
+	for {
+		subscribed, err := c.receiveInvalidations()
+		select {
+		case <-c.done:
+			return
+		default:
+		}
+		c.reportError("subscribe", "", err)
+
+		// The connection was lost after a successful subscription, rather than the server being unreachable.
+		if subscribed {
+			delay = c.timeout
+		}
+
+		select {
+		case <-c.done:
+			return
+		case <-time.After(delay):
+		}
+		delay = min(2*delay, maxResubscribeDelay)
+	}
+}
+
+// receiveInvalidations subscribes to the invalidations and applies them until the connection fails. subscribed
+// tells whether the subscription succeeded.
+func (c *Cache[T]) receiveInvalidations() (subscribed bool, err error) {
+	cn, err := c.client.dial(context.Background())
+	if err != nil {
+		return false, err
+	}
+	defer cn.netConn.Close()
+
+	c.subMu.Lock()
+	select {
+	case <-c.done:
+		c.subMu.Unlock()
+		return false, nil
+	default:
+	}
+	c.subConn = cn
+	c.subMu.Unlock()
+
+	if _, err := c.client.pipeline(context.Background(), cn, []string{"SUBSCRIBE", c.keyPrefix + invalidationChannel}); err != nil {
+		return false, err
+	}
+	// Messages arrive whenever other replicas write.
+	if err := cn.netConn.SetDeadline(time.Time{}); err != nil {
+		return true, err
+	}
+
+	for {
+		reply, err := cn.readReply()
+		if err != nil {
+			return true, err
+		}
+		message, ok := reply.([]any)
+		if !ok || len(message) != 3 || !isBulk(message[0], "message") {
+			continue
+		}
+		payload, ok := message[2].([]byte)
+		if !ok {
+			continue
+		}
+		instanceID, key, ok := strings.Cut(string(payload), " ")
+		if ok && instanceID != c.instanceID {
+			c.local.Delete(key)
+		}
+	}
+}
+
+func isBulk(reply any, value string) bool {
+	b, ok := reply.([]byte)
+	return ok && string(b) == value
+}
+
+func (c *Cache[T]) reportError(operation, key string, err error) {
+	cacheErrorCount.WithLabelValues(operation).Inc()
+	c.logger.Debug("redis cache operation failed",
+		zap.String("operation", operation),
+		zap.String("key", key),
+		zap.Error(err))
+}
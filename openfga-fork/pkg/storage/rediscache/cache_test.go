@@ -0,0 +1,198 @@
+package rediscache
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+
+	"github.com/openfga/openfga/pkg/storage"
+)
+
+// localOnlyItem is a cache item that cannot be serialized.
+type localOnlyItem struct{}
+
+func (localOnlyItem) CacheEntityType() string { return "local_only" }
+
+func newTestCache(t *testing.T, addr string, opts ...CacheOption[any]) *Cache[any] {
+	t.Helper()
+	c, err := New(addr, append([]CacheOption[any]{WithTimeout[any](time.Second)}, opts...)...)
+	require.NoError(t, err)
+	t.Cleanup(c.Stop)
+	return c
+}
+
+// waitForSubscribers waits until n caches receive the invalidations sent on the default channel.
+func waitForSubscribers(t *testing.T, server *fakeRedis, n int) {
+	t.Helper()
+	require.Eventually(t, func() bool {
+		return server.subscriberCount(defaultKeyPrefix+invalidationChannel) == n
+	}, 5*time.Second, 5*time.Millisecond)
+}
+
+func TestCache(t *testing.T) {
+	t.Cleanup(func() {
+		goleak.VerifyNone(t)
+	})
+
+	server := newFakeRedis(t, "secret")
+	replicaA := newTestCache(t, server.addr(), WithPassword[any]("secret"))
+	replicaB := newTestCache(t, server.addr(), WithPassword[any]("secret"))
+	waitForSubscribers(t, server, 2)
+
+	t.Run("entries_are_shared", func(t *testing.T) {
+		lastModified := time.Now().UTC().Truncate(time.Millisecond)
+		replicaA.Set("cc.store", &storage.ChangelogCacheEntry{LastModified: lastModified}, time.Minute)
+
+		require.Equal(t, &storage.ChangelogCacheEntry{LastModified: lastModified}, replicaB.Get("cc.store"))
+		require.Nil(t, replicaB.Get("cc.other"))
+	})
+
+	t.Run("writes_invalidate_local_copies", func(t *testing.T) {
+		first := time.Now().UTC()
+		replicaA.Set("iq.store", &storage.InvalidEntityCacheEntry{LastModified: first}, time.Minute)
+		// replicaB keeps a local copy.
+		require.Equal(t, first, replicaB.Get("iq.store").(*storage.InvalidEntityCacheEntry).LastModified)
+
+		second := first.Add(time.Second)
+		replicaA.Set("iq.store", &storage.InvalidEntityCacheEntry{LastModified: second}, time.Minute)
+		require.Eventually(t, func() bool {
+			return replicaB.Get("iq.store").(*storage.InvalidEntityCacheEntry).LastModified.Equal(second)
+		}, 5*time.Second, 5*time.Millisecond)
+
+		replicaA.Delete("iq.store")
+		require.Eventually(t, func() bool {
+			return replicaB.Get("iq.store") == nil
+		}, 5*time.Second, 5*time.Millisecond)
+	})
+
+	t.Run("entries_expire", func(t *testing.T) {
+		replicaA.Set("cc.expiring", &storage.ChangelogCacheEntry{LastModified: time.Now()}, 50*time.Millisecond)
+		require.NotNil(t, replicaB.Get("cc.expiring"))
+		require.Eventually(t, func() bool {
+			return replicaA.Get("cc.expiring") == nil && replicaB.Get("cc.expiring") == nil
+		}, 5*time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("unserializable_entries_are_local", func(t *testing.T) {
+		replicaA.Set("local", localOnlyItem{}, time.Minute)
+		require.Equal(t, localOnlyItem{}, replicaA.Get("local"))
+		require.Nil(t, replicaB.Get("local"))
+	})
+
+	t.Run("key_prefix_separates_caches", func(t *testing.T) {
+		other := newTestCache(t, server.addr(), WithPassword[any]("secret"), WithKeyPrefix[any]("shadow:"))
+		replicaA.Set("cc.prefixed", &storage.ChangelogCacheEntry{LastModified: time.Now()}, time.Minute)
+		require.Nil(t, other.Get("cc.prefixed"))
+	})
+}
+
+func TestCacheWrongPassword(t *testing.T) {
+	server := newFakeRedis(t, "secret")
+	c := newTestCache(t, server.addr(), WithPassword[any]("wrong"))
+
+	c.Set("cc.store", &storage.ChangelogCacheEntry{LastModified: time.Now()}, time.Minute)
+	// The entry is only cached locally.
+	require.NotNil(t, c.Get("cc.store"))
+
+	other := newTestCache(t, server.addr(), WithPassword[any]("secret"))
+	require.Nil(t, other.Get("cc.store"))
+}
+
+func TestCacheServerUnavailable(t *testing.T) {
+	t.Cleanup(func() {
+		goleak.VerifyNone(t)
+	})
+
+	// Reserve an address where nothing listens.
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := listener.Addr().String()
+	require.NoError(t, listener.Close())
+
+	c := newTestCache(t, addr, WithTimeout[any](50*time.Millisecond))
+
+	require.Nil(t, c.Get("cc.store"))
+	c.Set("cc.store", &storage.ChangelogCacheEntry{LastModified: time.Now()}, time.Minute)
+	require.NotNil(t, c.Get("cc.store"))
+	c.Delete("cc.store")
+	require.Nil(t, c.Get("cc.store"))
+}
+
+func TestCacheResubscribes(t *testing.T) {
+	server := newFakeRedis(t, "")
+	replicaA := newTestCache(t, server.addr())
+	replicaB := newTestCache(t, server.addr())
+	waitForSubscribers(t, server, 2)
+
+	// Dropping the connections forces both caches to subscribe again.
+	server.mu.Lock()
+	for c := range server.conns {
+		_ = c.Close()
+	}
+	server.mu.Unlock()
+	require.Eventually(t, func() bool {
+		return server.subscriptionCount() == 4
+	}, 10*time.Second, 10*time.Millisecond)
+
+	replicaA.Set("cc.store", &storage.ChangelogCacheEntry{LastModified: time.Now()}, time.Minute)
+	require.NotNil(t, replicaB.Get("cc.store"))
+	replicaA.Delete("cc.store")
+	require.Eventually(t, func() bool {
+		return replicaB.Get("cc.store") == nil
+	}, 5*time.Second, 5*time.Millisecond)
+}
+
+func TestCacheTLS(t *testing.T) {
+	server, pool := newFakeRedisTLS(t, "secret")
+
+	replicas := make([]*Cache[any], 2)
+	for i := range replicas {
+		replicas[i] = newTestCache(t, "rediss://"+server.addr(),
+			WithPassword[any]("secret"),
+			WithTLSConfig[any](&tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}),
+		)
+	}
+	waitForSubscribers(t, server, 2)
+
+	replicas[0].Set("cc.store", &storage.ChangelogCacheEntry{LastModified: time.Now()}, time.Minute)
+	require.NotNil(t, replicas[1].Get("cc.store"))
+
+	// Without TLS, the server does not answer.
+	plain, err := NewClient(server.addr(), "secret", 0, 50*time.Millisecond)
+	require.NoError(t, err)
+	t.Cleanup(plain.Close)
+	_, err = plain.Do(context.Background(), "PING")
+	require.Error(t, err)
+}
+
+func TestParseAddr(t *testing.T) {
+	hostPort, tlsConfig, err := parseAddr("localhost:6379")
+	require.NoError(t, err)
+	require.Equal(t, "localhost:6379", hostPort)
+	require.Nil(t, tlsConfig)
+
+	hostPort, tlsConfig, err = parseAddr("redis://localhost:6379")
+	require.NoError(t, err)
+	require.Equal(t, "localhost:6379", hostPort)
+	require.Nil(t, tlsConfig)
+
+	hostPort, tlsConfig, err = parseAddr("rediss://cache.redis.cache.windows.net:6380")
+	require.NoError(t, err)
+	require.Equal(t, "cache.redis.cache.windows.net:6380", hostPort)
+	require.Equal(t, "cache.redis.cache.windows.net", tlsConfig.ServerName)
+
+	_, _, err = parseAddr("https://localhost:6379")
+	require.ErrorContains(t, err, "the scheme must be 'redis' or 'rediss'")
+	_, _, err = parseAddr("rediss://localhost")
+	require.Error(t, err)
+}
+
+func TestNewRequiresAddress(t *testing.T) {
+	_, err := New[any]("")
+	require.ErrorContains(t, err, "address of the Redis server is required")
+}
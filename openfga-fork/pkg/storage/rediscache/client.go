@@ -0,0 +1,329 @@
+package rediscache
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// redisError is an error reply of the server.
+type redisError string
+
+func (e redisError) Error() string {
+	return string(e)
+}
+
+// conn is a connection speaking the Redis serialization protocol (RESP2). Replies are decoded as
+// string (simple strings), int64 (integers), []byte or nil (bulk strings), []any (arrays) and
+// redisError (errors).
+type conn struct {
+	netConn net.Conn
+	r       *bufio.Reader
+	w       *bufio.Writer
+}
+
+func (c *conn) writeCommand(args ...string) error {
+	if _, err := fmt.Fprintf(c.w, "*%d\r\n", len(args)); err != nil {
+		return err
+	}
+	for _, arg := range args {
+		if _, err := fmt.Fprintf(c.w, "$%d\r\n%s\r\n", len(arg), arg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *conn) readLine() (string, error) {
+	line, err := c.r.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	if len(line) < 3 || line[len(line)-2] != '\r' {
+		return "", fmt.Errorf("malformed reply line %q", line)
+	}
+	return line[:len(line)-2], nil
+}
+
+func (c *conn) readReply() (any, error) {
+	line, err := c.readLine()
+	if err != nil {
+		return nil, err
+	}
+
+	switch line[0] {
+	case '+':
+		return line[1:], nil
+	case '-':
+		return redisError(line[1:]), nil
+	case ':':
+		return strconv.ParseInt(line[1:], 10, 64)
+	case '$':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, err
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		buf := make([]byte, n+2)
+		if _, err := io.ReadFull(c.r, buf); err != nil {
+			return nil, err
+		}
+		return buf[:n], nil
+	case '*':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, err
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		elements := make([]any, n)
+		for i := range elements {
+			if elements[i], err = c.readReply(); err != nil {
+				return nil, err
+			}
+		}
+		return elements, nil
+	default:
+		return nil, fmt.Errorf("unexpected reply type %q", line[0])
+	}
+}
+
+// client runs commands on a pool of connections to a Redis-compatible server.
+type client struct {
+	addr     string
+	password string
+	db       int
+	timeout  time.Duration
+	// tlsConfig is the TLS configuration of the connections, or nil if they are not encrypted.
+	tlsConfig *tls.Config
+
+	mu     sync.Mutex
+	idle   []*conn
+	closed bool
+}
+
+// maxIdleConns is the number of connections kept open between commands.
+const maxIdleConns = 16
+
+var errClientClosed = errors.New("redis client closed")
+
+func newClient(addr, password string, db int, timeout time.Duration) (*client, error) {
+	hostPort, tlsConfig, err := parseAddr(addr)
+	if err != nil {
+		return nil, err
+	}
+	return &client{addr: hostPort, password: password, db: db, timeout: timeout, tlsConfig: tlsConfig}, nil
+}
+
+// parseAddr returns the host:port of addr, which is host:port, redis://host:port or rediss://host:port, and
+// the TLS configuration of the connections, which are encrypted with the rediss scheme.
+func parseAddr(addr string) (string, *tls.Config, error) {
+	scheme, hostPort, ok := strings.Cut(addr, "://")
+	if !ok {
+		return addr, nil, nil
+	}
+
+	switch scheme {
+	case "redis":
+		return hostPort, nil, nil
+	case "rediss":
+		host, _, err := net.SplitHostPort(hostPort)
+		if err != nil {
+			return "", nil, fmt.Errorf("invalid Redis server address '%s': %w", addr, err)
+		}
+		return hostPort, &tls.Config{ServerName: host, MinVersion: tls.VersionTLS12}, nil
+	default:
+		return "", nil, fmt.Errorf("invalid Redis server address '%s': the scheme must be 'redis' or 'rediss'", addr)
+	}
+}
+
+// dial opens a connection, authenticated and bound to the configured database.
+func (c *client) dial(ctx context.Context) (*conn, error) {
+	dialer := &net.Dialer{Timeout: c.timeout}
+	var netConn net.Conn
+	var err error
+	if c.tlsConfig != nil {
+		netConn, err = (&tls.Dialer{NetDialer: dialer, Config: c.tlsConfig}).DialContext(ctx, "tcp", c.addr)
+	} else {
+		netConn, err = dialer.DialContext(ctx, "tcp", c.addr)
+	}
+	if err != nil {
+		return nil, err
+	}
+	cn := &conn{netConn: netConn, r: bufio.NewReader(netConn), w: bufio.NewWriter(netConn)}
+
+	var setup [][]string
+	if c.password != "" {
+		setup = append(setup, []string{"AUTH", c.password})
+	}
+	if c.db != 0 {
+		setup = append(setup, []string{"SELECT", strconv.Itoa(c.db)})
+	}
+	if len(setup) > 0 {
+		if _, err := c.pipeline(ctx, cn, setup...); err != nil {
+			_ = netConn.Close()
+			return nil, err
+		}
+	}
+	return cn, nil
+}
+
+// get returns an idle connection, or a new one. reused tells whether the connection was idle,
+// in which case the server may have closed it in the meantime.
+func (c *client) get(ctx context.Context) (cn *conn, reused bool, err error) {
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return nil, false, errClientClosed
+	}
+	if n := len(c.idle); n > 0 {
+		cn := c.idle[n-1]
+		c.idle = c.idle[:n-1]
+		c.mu.Unlock()
+		return cn, true, nil
+	}
+	c.mu.Unlock()
+	cn, err = c.dial(ctx)
+	return cn, false, err
+}
+
+func (c *client) put(cn *conn) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed || len(c.idle) >= maxIdleConns {
+		_ = cn.netConn.Close()
+		return
+	}
+	c.idle = append(c.idle, cn)
+}
+
+// pipeline sends the commands on cn and reads their replies. Error replies are returned as errors. The
+// commands are abandoned when ctx is done, in which case cn is left with unread replies.
+func (c *client) pipeline(ctx context.Context, cn *conn, commands ...[]string) ([]any, error) {
+	if err := cn.netConn.SetDeadline(time.Now().Add(c.timeout)); err != nil {
+		return nil, err
+	}
+	// Once ctx is done, the pending reads and writes fail at once.
+	stop := context.AfterFunc(ctx, func() {
+		_ = cn.netConn.SetDeadline(time.Now())
+	})
+	defer stop()
+
+	replies, err := c.roundTrip(cn, commands...)
+	if ctxErr := ctx.Err(); err != nil && ctxErr != nil {
+		return nil, ctxErr
+	}
+	return replies, err
+}
+
+func (c *client) roundTrip(cn *conn, commands ...[]string) ([]any, error) {
+	for _, args := range commands {
+		if err := cn.writeCommand(args...); err != nil {
+			return nil, err
+		}
+	}
+	if err := cn.w.Flush(); err != nil {
+		return nil, err
+	}
+
+	replies := make([]any, len(commands))
+	var replyErr error
+	for i := range replies {
+		reply, err := cn.readReply()
+		if err != nil {
+			return nil, err
+		}
+		if e, ok := reply.(redisError); ok && replyErr == nil {
+			replyErr = e
+		}
+		replies[i] = reply
+	}
+	return replies, replyErr
+}
+
+// do runs the commands in a single round trip and returns their replies. If an idle connection
+// turns out to be broken, the commands are sent again on a new connection.
+func (c *client) do(ctx context.Context, commands ...[]string) ([]any, error) {
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		cn, reused, err := c.get(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		replies, err := c.pipeline(ctx, cn, commands...)
+		var replyErr redisError
+		if err != nil && !errors.As(err, &replyErr) {
+			// The connection may hold unread replies.
+			_ = cn.netConn.Close()
+			if reused && ctx.Err() == nil {
+				continue
+			}
+			return nil, err
+		}
+		c.put(cn)
+		return replies, err
+	}
+}
+
+func (c *client) close() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.closed = true
+	for _, cn := range c.idle {
+		_ = cn.netConn.Close()
+	}
+	c.idle = nil
+}
+
+// Client runs commands on a Redis-compatible server over a pool of connections. It is safe for
+// concurrent use.
+type Client struct {
+	client *client
+}
+
+// NewClient returns a client of the Redis-compatible server at addr (host:port, or rediss://host:port for
+// connections over TLS). Connections are opened when commands are run, so the server does not need to be
+// reachable yet.
+func NewClient(addr, password string, db int, timeout time.Duration) (*Client, error) {
+	if addr == "" {
+		return nil, errors.New("the address of the Redis server is required")
+	}
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+	c, err := newClient(addr, password, db, timeout)
+	if err != nil {
+		return nil, err
+	}
+	return &Client{client: c}, nil
+}
+
+// Do runs the command and returns its reply, decoded as string (simple strings), int64 (integers),
+// []byte or nil (bulk strings) and []any (arrays). Error replies are returned as errors. The command is
+// abandoned when ctx is done.
+func (c *Client) Do(ctx context.Context, args ...string) (any, error) {
+	replies, err := c.client.do(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	return replies[0], nil
+}
+
+// Close closes the idle connections. Commands fail once the client is closed.
+func (c *Client) Close() {
+	c.client.close()
+}
@@ -0,0 +1,283 @@
+package rediscache
+
+import (
+	"bufio"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"fmt"
+	"io"
+	"math/big"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRedis is an in-process stand-in for a Redis server. It implements the subset of commands used by Cache.
+type fakeRedis struct {
+	listener net.Listener
+	password string
+
+	mu          sync.Mutex
+	values      map[string]fakeValue
+	subscribers map[string][]*fakeRedisConn
+	conns       map[*fakeRedisConn]struct{}
+	// subscriptions is the number of SUBSCRIBE commands served.
+	subscriptions int
+	wg            sync.WaitGroup
+}
+
+type fakeValue struct {
+	data      string
+	expiresAt time.Time
+}
+
+type fakeRedisConn struct {
+	net.Conn
+	mu sync.Mutex
+	w  *bufio.Writer
+}
+
+func (c *fakeRedisConn) write(format string, args ...any) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	fmt.Fprintf(c.w, format, args...)
+	_ = c.w.Flush()
+}
+
+func (c *fakeRedisConn) writeBulk(s string) {
+	c.write("$%d\r\n%s\r\n", len(s), s)
+}
+
+func newFakeRedis(t *testing.T, password string) *fakeRedis {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	return serveFakeRedis(t, password, listener)
+}
+
+// newFakeRedisTLS returns a fake server accepting TLS connections, and the pool trusting its certificate.
+func newFakeRedisTLS(t *testing.T, password string) (*fakeRedis, *x509.CertPool) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "fake-redis"},
+		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1)},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	pool := x509.NewCertPool()
+	pool.AddCert(cert)
+
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{
+		Certificates: []tls.Certificate{{Certificate: [][]byte{der}, PrivateKey: key}},
+		MinVersion:   tls.VersionTLS12,
+	})
+	require.NoError(t, err)
+	return serveFakeRedis(t, password, listener), pool
+}
+
+func serveFakeRedis(t *testing.T, password string, listener net.Listener) *fakeRedis {
+	f := &fakeRedis{
+		listener:    listener,
+		password:    password,
+		values:      make(map[string]fakeValue),
+		subscribers: make(map[string][]*fakeRedisConn),
+		conns:       make(map[*fakeRedisConn]struct{}),
+	}
+
+	f.wg.Add(1)
+	go func() {
+		defer f.wg.Done()
+		for {
+			netConn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			c := &fakeRedisConn{Conn: netConn, w: bufio.NewWriter(netConn)}
+			f.mu.Lock()
+			f.conns[c] = struct{}{}
+			f.mu.Unlock()
+
+			f.wg.Add(1)
+			go func() {
+				defer f.wg.Done()
+				f.serve(c)
+			}()
+		}
+	}()
+
+	t.Cleanup(f.close)
+	return f
+}
+
+func (f *fakeRedis) addr() string {
+	return f.listener.Addr().String()
+}
+
+// close stops the server and drops every connection.
+func (f *fakeRedis) close() {
+	_ = f.listener.Close()
+	f.mu.Lock()
+	for c := range f.conns {
+		_ = c.Close()
+	}
+	f.mu.Unlock()
+	f.wg.Wait()
+}
+
+func (f *fakeRedis) subscriberCount(channel string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.subscribers[channel])
+}
+
+func (f *fakeRedis) subscriptionCount() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.subscriptions
+}
+
+func (f *fakeRedis) serve(c *fakeRedisConn) {
+	defer func() {
+		f.mu.Lock()
+		delete(f.conns, c)
+		for channel, subscribers := range f.subscribers {
+			for i, s := range subscribers {
+				if s == c {
+					f.subscribers[channel] = append(subscribers[:i], subscribers[i+1:]...)
+					break
+				}
+			}
+		}
+		f.mu.Unlock()
+		_ = c.Close()
+	}()
+
+	r := bufio.NewReader(c)
+	authenticated := f.password == ""
+	for {
+		args, err := readCommand(r)
+		if err != nil {
+			return
+		}
+
+		command := strings.ToUpper(args[0])
+		if !authenticated && command != "AUTH" {
+			c.write("-NOAUTH Authentication required.\r\n")
+			continue
+		}
+
+		switch command {
+		case "AUTH":
+			if args[1] != f.password {
+				c.write("-WRONGPASS invalid password\r\n")
+				continue
+			}
+			authenticated = true
+			c.write("+OK\r\n")
+		case "SELECT":
+			c.write("+OK\r\n")
+		case "GET":
+			if v, ok := f.get(args[1]); ok {
+				c.writeBulk(v.data)
+			} else {
+				c.write("$-1\r\n")
+			}
+		case "PTTL":
+			if v, ok := f.get(args[1]); ok {
+				c.write(":%d\r\n", time.Until(v.expiresAt).Milliseconds())
+			} else {
+				c.write(":-2\r\n")
+			}
+		case "SET":
+			millis, _ := strconv.ParseInt(args[4], 10, 64)
+			f.mu.Lock()
+			f.values[args[1]] = fakeValue{data: args[2], expiresAt: time.Now().Add(time.Duration(millis) * time.Millisecond)}
+			f.mu.Unlock()
+			c.write("+OK\r\n")
+		case "DEL":
+			f.mu.Lock()
+			_, ok := f.values[args[1]]
+			delete(f.values, args[1])
+			f.mu.Unlock()
+			if ok {
+				c.write(":1\r\n")
+			} else {
+				c.write(":0\r\n")
+			}
+		case "PUBLISH":
+			f.mu.Lock()
+			subscribers := append([]*fakeRedisConn(nil), f.subscribers[args[1]]...)
+			f.mu.Unlock()
+			for _, s := range subscribers {
+				s.write("*3\r\n$7\r\nmessage\r\n$%d\r\n%s\r\n$%d\r\n%s\r\n", len(args[1]), args[1], len(args[2]), args[2])
+			}
+			c.write(":%d\r\n", len(subscribers))
+		case "SUBSCRIBE":
+			f.mu.Lock()
+			f.subscribers[args[1]] = append(f.subscribers[args[1]], c)
+			f.subscriptions++
+			f.mu.Unlock()
+			c.write("*3\r\n$9\r\nsubscribe\r\n$%d\r\n%s\r\n:1\r\n", len(args[1]), args[1])
+		default:
+			c.write("-ERR unknown command '%s'\r\n", args[0])
+		}
+	}
+}
+
+func (f *fakeRedis) get(key string) (fakeValue, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	v, ok := f.values[key]
+	if ok && time.Now().After(v.expiresAt) {
+		delete(f.values, key)
+		return fakeValue{}, false
+	}
+	return v, ok
+}
+
+func readCommand(r *bufio.Reader) ([]string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(line[1:]))
+	if err != nil {
+		return nil, err
+	}
+
+	args := make([]string, n)
+	for i := range args {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		size, err := strconv.Atoi(strings.TrimSpace(line[1:]))
+		if err != nil {
+			return nil, err
+		}
+		buf := make([]byte, size+2)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, err
+		}
+		args[i] = string(buf[:size])
+	}
+	return args, nil
+}
@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"sort"
 	"strconv"
 	"strings"
@@ -506,6 +507,58 @@ func (dbInfo *DBInfo) NowExpr() sq.Sqlizer {
 	}
 }
 
+// maxRowsPerStatement bounds the rows a statement of a write locks, deletes or inserts. It keeps the size of the
+// statements of large writes in check and, on SQL Server, keeps the locks of each statement well below the 5,000
+// that escalate them to a lock of the whole table.
+const maxRowsPerStatement = 500
+
+// maxStatementParams returns the maximum number of placeholders of a statement based on database dialect, less a
+// margin for the placeholders that are not part of a row, such as the store.
+func (dbInfo *DBInfo) maxStatementParams() int {
+	switch dbInfo.dialect {
+	case "mssql", "sqlserver":
+		return 2100 - 10
+	case "sqlite":
+		return 32766 - 10
+	default:
+		// MySQL and Postgres count the placeholders of a statement on 16 bits.
+		return 65535 - 10
+	}
+}
+
+// RowsPerStatement returns the number of rows of paramsPerRow placeholders each statement of a write holds, so
+// that writes of any size are split into statements the database accepts within a single transaction.
+func (dbInfo *DBInfo) RowsPerStatement(paramsPerRow int) int {
+	return max(1, min(maxRowsPerStatement, dbInfo.maxStatementParams()/paramsPerRow))
+}
+
+// WriteContextError returns the error of the context if it is done, rather than err. A write whose context is
+// done before it commits is rolled back as a whole, and err is then whatever the driver returned for the
+// statement or the commit it interrupted.
+func WriteContextError(ctx context.Context, err error) error {
+	if err == nil {
+		return nil
+	}
+	if ctxErr := ctx.Err(); ctxErr != nil && !errors.Is(err, ctxErr) {
+		return fmt.Errorf("write rolled back: %w: %s", ctxErr, err.Error())
+	}
+	return err
+}
+
+// userTypeExpr returns the SQL expression of the type of the user of a tuple based on database dialect.
+func (dbInfo *DBInfo) userTypeExpr() string {
+	switch dbInfo.dialect {
+	case "mssql", "sqlserver":
+		return "LEFT(_user, CHARINDEX(':', _user) - 1)"
+	case "mysql":
+		return "SUBSTRING_INDEX(_user, ':', 1)"
+	case "sqlite":
+		return "user_object_type"
+	default:
+		return "split_part(_user, ':', 1)"
+	}
+}
+
 // LockSuffix returns the appropriate SQL suffix for row locking based on database dialect.
 func (dbInfo *DBInfo) LockSuffix() string {
 	switch dbInfo.dialect {
@@ -641,7 +694,64 @@ func selectExistingRowsForWrite(ctx context.Context, dbInfo *DBInfo, store strin
 	return nil
 }
 
-// Write provides the common method for writing to database across sql storage.
+// LockWritePreconditions locks the row of the store when the preconditions of a write read more than the
+// rows of its tuples, so that the writes with such preconditions on a store run one after the other. It must
+// be called before the tuples are read: under READ COMMITTED, the rows locked FOR UPDATE by a precondition
+// that a tuple does not exist do not exist either, so that nothing else stops two writes from both seeing
+// the tuple missing. SQLite takes no lock, its write transactions are already serialized.
+func LockWritePreconditions(ctx context.Context, dbInfo *DBInfo, store string, preconditions storage.WritePreconditions, txn *sql.Tx) error {
+	if dbInfo.dialect == "sqlite" || (len(preconditions.TuplesNotExist) == 0 && preconditions.LatestChangeULID == "") {
+		return nil
+	}
+
+	lockBuilder := dbInfo.stbl.Select("id").From("store").Suffix(dbInfo.LockSuffix())
+	if dbInfo.dialect == "mssql" || dbInfo.dialect == "sqlserver" {
+		// SQL Server requires WITH hints immediately after table name, not as suffix
+		lockBuilder = dbInfo.stbl.Select("id").From("store " + dbInfo.LockSuffix())
+	}
+	rows, err := lockBuilder.Where(sq.Eq{"id": store}).RunWith(txn).QueryContext(ctx)
+	if err != nil {
+		return dbInfo.HandleSQLError(err)
+	}
+	if err := rows.Close(); err != nil {
+		return dbInfo.HandleSQLError(err)
+	}
+	return nil
+}
+
+// CheckWritePreconditions checks the preconditions of a write against the existing tuples read under the
+// locks of the write and, if needed, the latest change of the store. The lock of LockWritePreconditions must
+// have been taken first.
+func CheckWritePreconditions(ctx context.Context, dbInfo *DBInfo, store string, preconditions storage.WritePreconditions, txn *sql.Tx, existing map[string]*openfgav1.Tuple) error {
+	var latestChangeULID string
+	if preconditions.LatestChangeULID != "" {
+		err := dbInfo.stbl.
+			Select("COALESCE(MAX(ulid), '')").
+			From("changelog").
+			Where(sq.Eq{"store": store}).
+			RunWith(txn).
+			QueryRowContext(ctx).
+			Scan(&latestChangeULID)
+		if err != nil {
+			return dbInfo.HandleSQLError(err)
+		}
+	}
+	return preconditions.Check(func(tk *openfgav1.TupleKeyWithoutCondition) bool {
+		_, ok := existing[tupleUtils.TupleKeyToString(tk)]
+		return ok
+	}, latestChangeULID)
+}
+
+// The number of placeholders per row of the statements of a write.
+const (
+	tupleKeyParams     = 5
+	tupleRowParams     = 10
+	changelogRowParams = 10
+)
+
+// Write provides the common method for writing to database across sql storage. The deletes and writes are
+// applied in a single transaction whatever their number, split into statements of
+// [DBInfo.RowsPerStatement] rows.
 func Write(
 	ctx context.Context,
 	dbInfo *DBInfo,
@@ -650,6 +760,18 @@ func Write(
 	writes storage.Writes,
 	opts storage.TupleWriteOptions,
 	now time.Time,
+) error {
+	return WriteContextError(ctx, write(ctx, dbInfo, store, deletes, writes, opts, now))
+}
+
+func write(
+	ctx context.Context,
+	dbInfo *DBInfo,
+	store string,
+	deletes storage.Deletes,
+	writes storage.Writes,
+	opts storage.TupleWriteOptions,
+	now time.Time,
 ) error {
 	// 1. Begin Transaction ( Isolation Level = READ COMMITTED )
 	txn, err := dbInfo.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelReadCommitted})
@@ -659,20 +781,25 @@ func Write(
 	defer func() { _ = txn.Rollback() }()
 
 	// 2. Compile a SELECT … FOR UPDATE statement to read the tuples for writes and lock tuples for deletes
-	// Build a deduped, sorted list of keys to lock.
-	lockKeys := makeTupleLockKeys(deletes, writes)
+	// and preconditions. Build a deduped, sorted list of keys to lock.
+	lockKeys := makeTupleLockKeys(append(opts.Preconditions.TupleKeys(), deletes...), writes)
 	total := len(lockKeys)
 	if total == 0 {
 		// Nothing to do.
 		return nil
 	}
 
+	if err = LockWritePreconditions(ctx, dbInfo, store, opts.Preconditions, txn); err != nil {
+		return err
+	}
+
 	existing := make(map[string]*openfgav1.Tuple, total)
 
 	// 3. If list compiled in step 2 is not empty, execute SELECT … FOR UPDATE statement
 
-	for start := 0; start < total; start += storage.DefaultMaxTuplesPerWrite {
-		end := start + storage.DefaultMaxTuplesPerWrite
+	keysPerStatement := dbInfo.RowsPerStatement(tupleKeyParams)
+	for start := 0; start < total; start += keysPerStatement {
+		end := start + keysPerStatement
 		if end > total {
 			end = total
 		}
@@ -683,6 +810,10 @@ func Write(
 		}
 	}
 
+	if err = CheckWritePreconditions(ctx, dbInfo, store, opts.Preconditions, txn, existing); err != nil {
+		return err
+	}
+
 	changeLogItems := make([][]interface{}, 0, len(deletes)+len(writes))
 
 	// ensures increasingly unique values within a single thread
@@ -810,8 +941,9 @@ func Write(
 		})
 	}
 
-	for start, totalDeletes := 0, len(deleteConditions); start < totalDeletes; start += storage.DefaultMaxTuplesPerWrite {
-		end := start + storage.DefaultMaxTuplesPerWrite
+	deletesPerStatement := dbInfo.RowsPerStatement(tupleKeyParams)
+	for start, totalDeletes := 0, len(deleteConditions); start < totalDeletes; start += deletesPerStatement {
+		end := start + deletesPerStatement
 		if end > totalDeletes {
 			end = totalDeletes
 		}
@@ -837,8 +969,9 @@ func Write(
 		}
 	}
 
-	for start, totalWrites := 0, len(writeItems); start < totalWrites; start += storage.DefaultMaxTuplesPerWrite {
-		end := start + storage.DefaultMaxTuplesPerWrite
+	writesPerStatement := dbInfo.RowsPerStatement(tupleRowParams)
+	for start, totalWrites := 0, len(writeItems); start < totalWrites; start += writesPerStatement {
+		end := start + writesPerStatement
 		if end > totalWrites {
 			end = totalWrites
 		}
@@ -878,8 +1011,9 @@ func Write(
 	}
 
 	// 6. Execute INSERT changelog statements
-	for start, totalItems := 0, len(changeLogItems); start < totalItems; start += storage.DefaultMaxTuplesPerWrite {
-		end := start + storage.DefaultMaxTuplesPerWrite
+	changesPerStatement := dbInfo.RowsPerStatement(changelogRowParams)
+	for start, totalItems := 0, len(changeLogItems); start < totalItems; start += changesPerStatement {
+		end := start + changesPerStatement
 		if end > totalItems {
 			end = totalItems
 		}
@@ -1070,6 +1204,94 @@ func ReadAuthorizationModel(
 	return ret, nil
 }
 
+// ReadActiveAuthorizationModelID reads the ID of the model that the store is pinned to.
+func ReadActiveAuthorizationModelID(
+	ctx context.Context,
+	dbInfo *DBInfo,
+	store string,
+) (string, error) {
+	var modelID string
+	err := dbInfo.stbl.
+		Select("authorization_model_id").
+		From("active_authorization_model").
+		Where(sq.Eq{"store": store}).
+		QueryRowContext(ctx).
+		Scan(&modelID)
+	if err != nil {
+		return "", dbInfo.HandleSQLError(err)
+	}
+
+	return modelID, nil
+}
+
+// DeleteActiveAuthorizationModelID removes the model pin of the store, if there is one.
+func DeleteActiveAuthorizationModelID(
+	ctx context.Context,
+	dbInfo *DBInfo,
+	store string,
+) error {
+	_, err := dbInfo.stbl.
+		Delete("active_authorization_model").
+		Where(sq.Eq{"store": store}).
+		ExecContext(ctx)
+	if err != nil {
+		return dbInfo.HandleSQLError(err)
+	}
+
+	return nil
+}
+
+// ReadStoreStats counts the tuples of the store grouped by object type, relation and user type, the changes of
+// its changelog and its models, with one aggregate query each.
+func ReadStoreStats(ctx context.Context, dbInfo *DBInfo, store string) (*storage.StoreStats, error) {
+	userType := dbInfo.userTypeExpr()
+	rows, err := dbInfo.stbl.
+		Select("object_type", "relation", userType, "COUNT(*)").
+		From("tuple").
+		Where(sq.Eq{"store": store}).
+		GroupBy("object_type", "relation", userType).
+		OrderBy("object_type", "relation", userType).
+		QueryContext(ctx)
+	if err != nil {
+		return nil, dbInfo.HandleSQLError(err)
+	}
+	defer rows.Close()
+
+	stats := &storage.StoreStats{}
+	for rows.Next() {
+		var count storage.TupleCount
+		if err := rows.Scan(&count.ObjectType, &count.Relation, &count.UserType, &count.Count); err != nil {
+			return nil, dbInfo.HandleSQLError(err)
+		}
+		stats.TupleCounts = append(stats.TupleCounts, count)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, dbInfo.HandleSQLError(err)
+	}
+
+	err = dbInfo.stbl.
+		Select("COUNT(*)", "COALESCE(MAX(ulid), '')").
+		From("changelog").
+		Where(sq.Eq{"store": store}).
+		QueryRowContext(ctx).
+		Scan(&stats.ChangelogCount, &stats.LatestChangeULID)
+	if err != nil {
+		return nil, dbInfo.HandleSQLError(err)
+	}
+
+	err = dbInfo.stbl.
+		Select("COUNT(DISTINCT authorization_model_id)").
+		From("authorization_model").
+		Where(sq.Eq{"store": store}).
+		QueryRowContext(ctx).
+		Scan(&stats.AuthorizationModelCount)
+	if err != nil {
+		return nil, dbInfo.HandleSQLError(err)
+	}
+
+	return stats, nil
+}
+
 // IsReady returns true if connection to datastore is successful AND
 // (the datastore has the latest migration applied OR skipVersionCheck).
 func IsReady(ctx context.Context, skipVersionCheck bool, db *sql.DB) (storage.ReadinessStatus, error) {
@@ -1114,3 +1336,54 @@ func AddFromUlid(sb sq.SelectBuilder, fromUlid string, sortDescending bool) sq.S
 	}
 	return sb.Where(sq.Gt{"ulid": fromUlid})
 }
+
+// likeEscaper escapes the wildcards of a LIKE pattern, and the characters that some dialects treat as
+// wildcards, with the escape character of [LikePrefix].
+var likeEscaper = strings.NewReplacer("!", "!!", "%", "!%", "_", "!_", "[", "![")
+
+// LikePrefix returns a condition matching the values of column that start with prefix. Unlike a
+// function on the column, it can be answered by a range scan of an index on the column.
+func LikePrefix(column, prefix string) sq.Sqlizer {
+	return sq.Expr(column+" LIKE ? ESCAPE '!'", likeEscaper.Replace(prefix)+"%")
+}
+
+// AddSearchTuplesFilter adds the conditions of the filter, the pagination token and the ULID order to a
+// query of the tuple table. The caller is responsible for limiting the number of rows.
+// The user is read from the _user column.
+func AddSearchTuplesFilter(sb sq.SelectBuilder, filter storage.SearchTuplesFilter, pagination storage.PaginationOptions) sq.SelectBuilder {
+	if filter.ObjectType != "" {
+		sb = sb.Where(sq.Eq{"object_type": filter.ObjectType})
+	}
+	if filter.ObjectIDPrefix != "" {
+		sb = sb.Where(LikePrefix("object_id", filter.ObjectIDPrefix))
+	}
+	if filter.Relation != "" {
+		sb = sb.Where(sq.Eq{"relation": filter.Relation})
+	}
+	if filter.UserType != "" {
+		sb = sb.Where(LikePrefix("_user", filter.UserType+":"))
+	}
+	if filter.UserPrefix != "" {
+		sb = sb.Where(LikePrefix("_user", filter.UserPrefix))
+	}
+	return AddSearchTuplesRange(sb, filter, pagination)
+}
+
+// AddSearchTuplesRange adds the condition name, ULID range and pagination token conditions of the filter, and
+// the ULID order, to a query of the tuple table. It is shared by the dialects that store the user in other columns.
+func AddSearchTuplesRange(sb sq.SelectBuilder, filter storage.SearchTuplesFilter, pagination storage.PaginationOptions) sq.SelectBuilder {
+	sb = sb.OrderBy("ulid")
+	if filter.ConditionName != "" {
+		sb = sb.Where(sq.Eq{"condition_name": filter.ConditionName})
+	}
+	if filter.FromULID != "" {
+		sb = sb.Where(sq.GtOrEq{"ulid": filter.FromULID})
+	}
+	if filter.ToULID != "" {
+		sb = sb.Where(sq.Lt{"ulid": filter.ToULID})
+	}
+	if pagination.From != "" {
+		sb = sb.Where(sq.GtOrEq{"ulid": pagination.From})
+	}
+	return sb
+}
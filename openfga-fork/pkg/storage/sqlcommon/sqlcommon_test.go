@@ -0,0 +1,45 @@
+package sqlcommon
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRowsPerStatement(t *testing.T) {
+	tests := []struct {
+		dialect      string
+		paramsPerRow int
+		expected     int
+	}{
+		{dialect: "sqlserver", paramsPerRow: 5, expected: 418},
+		{dialect: "sqlserver", paramsPerRow: 10, expected: 209},
+		{dialect: "postgres", paramsPerRow: 10, expected: maxRowsPerStatement},
+		{dialect: "mysql", paramsPerRow: 10, expected: maxRowsPerStatement},
+		{dialect: "sqlite", paramsPerRow: 12, expected: maxRowsPerStatement},
+	}
+	for _, test := range tests {
+		dbInfo := NewDBInfo(nil, sq.StatementBuilder, nil, test.dialect)
+		rows := dbInfo.RowsPerStatement(test.paramsPerRow)
+		require.Equal(t, test.expected, rows, test.dialect)
+		require.LessOrEqual(t, rows*test.paramsPerRow, dbInfo.maxStatementParams())
+	}
+}
+
+func TestWriteContextError(t *testing.T) {
+	errStatement := errors.New("sql error: bad connection")
+
+	require.NoError(t, WriteContextError(context.Background(), nil))
+	require.Equal(t, errStatement, WriteContextError(context.Background(), errStatement))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := WriteContextError(ctx, errStatement)
+	require.ErrorIs(t, err, context.Canceled)
+	require.ErrorContains(t, err, "bad connection")
+
+	require.Equal(t, context.Canceled, WriteContextError(ctx, context.Canceled))
+}
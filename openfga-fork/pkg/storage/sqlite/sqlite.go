@@ -54,8 +54,12 @@ type Datastore struct {
 	versionReady           bool
 }
 
-// Ensures that SQLite implements the OpenFGADatastore interface.
-var _ storage.OpenFGADatastore = (*Datastore)(nil)
+// Ensures that SQLite implements the OpenFGADatastore interface and its optional backends.
+var (
+	_ storage.OpenFGADatastore   = (*Datastore)(nil)
+	_ storage.TupleSearchBackend = (*Datastore)(nil)
+	_ storage.StoreStatsBackend  = (*Datastore)(nil)
+)
 
 // PrepareDSN Prepare a raw DSN from config for use with SQLite, specifying defaults for journal mode and busy timeout.
 func PrepareDSN(uri string) (string, error) {
@@ -174,6 +178,86 @@ func (s *Datastore) ReadPage(ctx context.Context, store string, tupleKey *openfg
 	return iter.ToArray(ctx, options.Pagination)
 }
 
+// SearchTuples see [storage.TupleSearchBackend].SearchTuples.
+func (s *Datastore) SearchTuples(ctx context.Context, store string, filter storage.SearchTuplesFilter, options storage.SearchTuplesOptions) ([]*openfgav1.Tuple, string, error) {
+	ctx, span := startTrace(ctx, "SearchTuples")
+	defer span.End()
+
+	sb := s.stbl.
+		Select(
+			"store", "object_type", "object_id", "relation",
+			"user_object_type", "user_object_id", "user_relation",
+			"condition_name", "condition_context", "ulid", "inserted_at",
+		).
+		From("tuple").
+		Where(sq.Eq{"store": store})
+	if filter.ObjectType != "" {
+		sb = sb.Where(sq.Eq{"object_type": filter.ObjectType})
+	}
+	if filter.ObjectIDPrefix != "" {
+		sb = sb.Where(prefixRange("object_id", filter.ObjectIDPrefix))
+	}
+	if filter.Relation != "" {
+		sb = sb.Where(sq.Eq{"relation": filter.Relation})
+	}
+	if filter.UserType != "" {
+		sb = sb.Where(sq.Eq{"user_object_type": filter.UserType})
+	}
+	if filter.UserPrefix != "" {
+		sb = sb.Where(userPrefixCondition(filter.UserPrefix))
+	}
+	sb = sqlcommon.AddSearchTuplesRange(sb, filter, options.Pagination)
+	sb = sb.Limit(uint64(options.Pagination.PageSize + 1)) // + 1 is used to determine whether to return a continuation token.
+
+	iter := NewSQLTupleIterator(sb, HandleSQLError)
+	defer iter.Stop()
+
+	return iter.ToArray(ctx, options.Pagination)
+}
+
+// userPrefixCondition returns a condition matching the users that start with prefix. As the user is split in
+// columns, the complete parts of the prefix are compared for equality and only the last part is a prefix.
+func userPrefixCondition(prefix string) sq.Sqlizer {
+	userObjectType, rest, found := strings.Cut(prefix, ":")
+	if !found {
+		return prefixRange("user_object_type", userObjectType)
+	}
+	userObjectID, userRelation, found := strings.Cut(rest, "#")
+	if !found {
+		return sq.And{
+			sq.Eq{"user_object_type": userObjectType},
+			prefixRange("user_object_id", userObjectID),
+		}
+	}
+	return sq.And{
+		sq.Eq{"user_object_type": userObjectType},
+		sq.Eq{"user_object_id": userObjectID},
+		sq.NotEq{"user_relation": ""},
+		prefixRange("user_relation", userRelation),
+	}
+}
+
+// prefixRange returns a condition matching the values of column that start with prefix. SQLite's LIKE is
+// case-insensitive and can't use the indexes of the tuple table, so the prefix is matched with a range of the
+// binary collation instead.
+func prefixRange(column, prefix string) sq.Sqlizer {
+	if prefix == "" {
+		return sq.Expr("1 = 1")
+	}
+	upper := []byte(prefix)
+	for len(upper) > 0 && upper[len(upper)-1] == 0xff {
+		upper = upper[:len(upper)-1]
+	}
+	if len(upper) == 0 {
+		return sq.GtOrEq{column: prefix}
+	}
+	upper[len(upper)-1]++
+	return sq.And{
+		sq.GtOrEq{column: prefix},
+		sq.Lt{column: string(upper)},
+	}
+}
+
 func (s *Datastore) read(ctx context.Context, store string, tupleKey *openfgav1.TupleKey, options *storage.ReadPageOptions) (*SQLTupleIterator, error) {
 	_, span := startTrace(ctx, "read")
 	defer span.End()
@@ -230,7 +314,8 @@ func (s *Datastore) Write(
 	ctx, span := startTrace(ctx, "Write")
 	defer span.End()
 
-	return s.write(ctx, store, deletes, writes, storage.NewTupleWriteOptions(opts...), time.Now().UTC())
+	err := s.write(ctx, store, deletes, writes, storage.NewTupleWriteOptions(opts...), time.Now().UTC())
+	return sqlcommon.WriteContextError(ctx, err)
 }
 
 // tupleLockKey represents the composite key we lock on.
@@ -365,6 +450,13 @@ func (s *Datastore) selectExistingRowsForWrite(ctx context.Context, store string
 	return nil
 }
 
+// The number of placeholders per row of the statements of a write.
+const (
+	tupleKeyParams     = 7
+	tupleRowParams     = 12
+	changelogRowParams = 12
+)
+
 // Write provides the common method for writing to database across sql storage.
 func (s *Datastore) write(
 	ctx context.Context,
@@ -389,20 +481,25 @@ func (s *Datastore) write(
 	}()
 
 	// 2. Compile a SELECT … FOR UPDATE statement to read the tuples for writes and lock tuples for deletes
-	// Build a deduped, sorted list of keys to lock.
-	lockKeys := makeTupleLockKeys(deletes, writes)
+	// and preconditions. Build a deduped, sorted list of keys to lock.
+	lockKeys := makeTupleLockKeys(append(opts.Preconditions.TupleKeys(), deletes...), writes)
 	total := len(lockKeys)
 	if total == 0 {
 		// Nothing to do.
 		return nil
 	}
 
+	if err = sqlcommon.LockWritePreconditions(ctx, s.dbInfo, store, opts.Preconditions, txn); err != nil {
+		return err
+	}
+
 	existing := make(map[string]*openfgav1.Tuple, total)
 
 	// 3. If list compiled in step 2 is not empty, execute SELECT … FOR UPDATE statement
 
-	for start := 0; start < total; start += storage.DefaultMaxTuplesPerWrite {
-		end := start + storage.DefaultMaxTuplesPerWrite
+	keysPerStatement := s.dbInfo.RowsPerStatement(tupleKeyParams)
+	for start := 0; start < total; start += keysPerStatement {
+		end := start + keysPerStatement
 		if end > total {
 			end = total
 		}
@@ -413,6 +510,10 @@ func (s *Datastore) write(
 		}
 	}
 
+	if err = sqlcommon.CheckWritePreconditions(ctx, s.dbInfo, store, opts.Preconditions, txn, existing); err != nil {
+		return err
+	}
+
 	changeLogItems := make([][]interface{}, 0, len(deletes)+len(writes))
 
 	// ensures increasingly unique values within a single thread
@@ -550,8 +651,9 @@ func (s *Datastore) write(
 		})
 	}
 
-	for start, totalDeletes := 0, len(deleteConditions); start < totalDeletes; start += storage.DefaultMaxTuplesPerWrite {
-		end := start + storage.DefaultMaxTuplesPerWrite
+	deletesPerStatement := s.dbInfo.RowsPerStatement(tupleKeyParams)
+	for start, totalDeletes := 0, len(deleteConditions); start < totalDeletes; start += deletesPerStatement {
+		end := start + deletesPerStatement
 		if end > totalDeletes {
 			end = totalDeletes
 		}
@@ -577,8 +679,9 @@ func (s *Datastore) write(
 		}
 	}
 
-	for start, totalWrites := 0, len(writeItems); start < totalWrites; start += storage.DefaultMaxTuplesPerWrite {
-		end := start + storage.DefaultMaxTuplesPerWrite
+	writesPerStatement := s.dbInfo.RowsPerStatement(tupleRowParams)
+	for start, totalWrites := 0, len(writeItems); start < totalWrites; start += writesPerStatement {
+		end := start + writesPerStatement
 		if end > totalWrites {
 			end = totalWrites
 		}
@@ -620,8 +723,9 @@ func (s *Datastore) write(
 	}
 
 	// 6. Execute INSERT changelog statements
-	for start, totalItems := 0, len(changeLogItems); start < totalItems; start += storage.DefaultMaxTuplesPerWrite {
-		end := start + storage.DefaultMaxTuplesPerWrite
+	changesPerStatement := s.dbInfo.RowsPerStatement(changelogRowParams)
+	for start, totalItems := 0, len(changeLogItems); start < totalItems; start += changesPerStatement {
+		end := start + changesPerStatement
 		if end > totalItems {
 			end = totalItems
 		}
@@ -949,6 +1053,62 @@ func (s *Datastore) FindLatestAuthorizationModel(ctx context.Context, store stri
 	return constructAuthorizationModelFromSQLRows(rows)
 }
 
+// ReadStoreStats see [storage.StoreStatsBackend].ReadStoreStats.
+func (s *Datastore) ReadStoreStats(ctx context.Context, store string) (*storage.StoreStats, error) {
+	ctx, span := startTrace(ctx, "ReadStoreStats")
+	defer span.End()
+
+	return sqlcommon.ReadStoreStats(ctx, s.dbInfo, store)
+}
+
+// ReadActiveAuthorizationModelID see [storage.AuthorizationModelReadBackend].ReadActiveAuthorizationModelID.
+func (s *Datastore) ReadActiveAuthorizationModelID(ctx context.Context, store string) (string, error) {
+	ctx, span := startTrace(ctx, "ReadActiveAuthorizationModelID")
+	defer span.End()
+
+	var modelID string
+	err := s.stbl.
+		Select("authorization_model_id").
+		From("active_authorization_model").
+		Where(sq.Eq{"store": store}).
+		QueryRowContext(ctx).
+		Scan(&modelID)
+	if err != nil {
+		return "", HandleSQLError(err)
+	}
+
+	return modelID, nil
+}
+
+// WriteActiveAuthorizationModelID see [storage.ActiveAuthorizationModelWriteBackend].WriteActiveAuthorizationModelID.
+func (s *Datastore) WriteActiveAuthorizationModelID(ctx context.Context, store string, modelID string) error {
+	ctx, span := startTrace(ctx, "WriteActiveAuthorizationModelID")
+	defer span.End()
+
+	err := busyRetry(func() error {
+		if modelID == "" {
+			_, err := s.stbl.
+				Delete("active_authorization_model").
+				Where(sq.Eq{"store": store}).
+				ExecContext(ctx)
+			return err
+		}
+
+		_, err := s.stbl.
+			Insert("active_authorization_model").
+			Columns("store", "authorization_model_id", "updated_at").
+			Values(store, modelID, sq.Expr("datetime('subsec')")).
+			Suffix("ON CONFLICT (store) DO UPDATE SET authorization_model_id = ?, updated_at = datetime('subsec')", modelID).
+			ExecContext(ctx)
+		return err
+	})
+	if err != nil {
+		return HandleSQLError(err)
+	}
+
+	return nil
+}
+
 // MaxTypesPerAuthorizationModel see [storage.TypeDefinitionWriteBackend].MaxTypesPerAuthorizationModel.
 func (s *Datastore) MaxTypesPerAuthorizationModel() int {
 	return s.maxTypesPerModelField
@@ -1113,16 +1273,41 @@ func (s *Datastore) ListStores(ctx context.Context, options storage.ListStoresOp
 	return stores, "", nil
 }
 
-// DeleteStore removes a store from storage.
+// DeleteStore removes a store from storage, together with its model pin.
 func (s *Datastore) DeleteStore(ctx context.Context, id string) error {
 	ctx, span := startTrace(ctx, "DeleteStore")
 	defer span.End()
 
-	_, err := s.stbl.
-		Update("store").
-		Set("deleted_at", sq.Expr("datetime('subsec')")).
-		Where(sq.Eq{"id": id}).
-		ExecContext(ctx)
+	err := busyRetry(func() error {
+		txn, err := s.db.BeginTx(ctx, &sql.TxOptions{})
+		if err != nil {
+			return err
+		}
+		defer func() {
+			_ = txn.Rollback()
+		}()
+
+		_, err = s.stbl.
+			Update("store").
+			Set("deleted_at", sq.Expr("datetime('subsec')")).
+			Where(sq.Eq{"id": id}).
+			RunWith(txn).
+			ExecContext(ctx)
+		if err != nil {
+			return err
+		}
+
+		_, err = s.stbl.
+			Delete("active_authorization_model").
+			Where(sq.Eq{"store": id}).
+			RunWith(txn).
+			ExecContext(ctx)
+		if err != nil {
+			return err
+		}
+
+		return txn.Commit()
+	})
 	if err != nil {
 		return HandleSQLError(err)
 	}
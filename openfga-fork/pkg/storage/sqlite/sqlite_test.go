@@ -203,3 +203,23 @@ func TestReadPageEnsureOrder(t *testing.T) {
 	require.Equal(t, secondTuple, tuples[0].GetKey())
 	require.Equal(t, firstTuple, tuples[1].GetKey())
 }
+
+func TestWriteWithDoneContext(t *testing.T) {
+	testDatastore := storagefixtures.RunDatastoreTestContainer(t, "sqlite")
+
+	uri := testDatastore.GetConnectionURI(true)
+	ds, err := New(uri, sqlcommon.NewConfig())
+	require.NoError(t, err)
+	defer ds.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Nanosecond)
+	defer cancel()
+	<-ctx.Done()
+
+	tk := tuple.NewTupleKey("doc:1", "viewer", "user:anne")
+	err = ds.Write(ctx, "store", nil, []*openfgav1.TupleKey{tk})
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	_, err = ds.ReadUserTuple(context.Background(), "store", tk, storage.ReadUserTupleOptions{})
+	require.ErrorIs(t, err, storage.ErrNotFound)
+}
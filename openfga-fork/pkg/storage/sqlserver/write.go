@@ -122,6 +122,13 @@ func (s *Datastore) selectExistingRowsForWrite(ctx context.Context, store string
 	return nil
 }
 
+// The number of placeholders per row of the statements of a write.
+const (
+	tupleKeyParams     = 5
+	tupleRowParams     = 10
+	changelogRowParams = 10
+)
+
 // write provides the SQL Server-specific implementation that handles row constructor incompatibility.
 func (s *Datastore) write(
 	ctx context.Context,
@@ -139,19 +146,24 @@ func (s *Datastore) write(
 	defer func() { _ = txn.Rollback() }()
 
 	// 2. Compile a SELECT … WITH (UPDLOCK, ROWLOCK) statement to read the tuples for writes and lock tuples for deletes
-	// Build a deduped, sorted list of keys to lock.
-	lockKeys := makeTupleLockKeys(deletes, writes)
+	// and preconditions. Build a deduped, sorted list of keys to lock.
+	lockKeys := makeTupleLockKeys(append(opts.Preconditions.TupleKeys(), deletes...), writes)
 	total := len(lockKeys)
 	if total == 0 {
 		// Nothing to do.
 		return nil
 	}
 
+	if err = sqlcommon.LockWritePreconditions(ctx, s.dbInfo, store, opts.Preconditions, txn); err != nil {
+		return err
+	}
+
 	existing := make(map[string]*openfgav1.Tuple, total)
 
 	// 3. If list compiled in step 2 is not empty, execute SELECT … WITH (UPDLOCK, ROWLOCK) statement
-	for start := 0; start < total; start += storage.DefaultMaxTuplesPerWrite {
-		end := start + storage.DefaultMaxTuplesPerWrite
+	keysPerStatement := s.dbInfo.RowsPerStatement(tupleKeyParams)
+	for start := 0; start < total; start += keysPerStatement {
+		end := start + keysPerStatement
 		if end > total {
 			end = total
 		}
@@ -162,6 +174,10 @@ func (s *Datastore) write(
 		}
 	}
 
+	if err = sqlcommon.CheckWritePreconditions(ctx, s.dbInfo, store, opts.Preconditions, txn, existing); err != nil {
+		return err
+	}
+
 	changeLogItems := make([][]interface{}, 0, len(deletes)+len(writes))
 
 	// ensures increasingly unique values within a single thread
@@ -273,8 +289,9 @@ func (s *Datastore) write(
 	}
 
 	// Execute deletes
-	for start, totalDeletes := 0, len(deleteConditions); start < totalDeletes; start += storage.DefaultMaxTuplesPerWrite {
-		end := start + storage.DefaultMaxTuplesPerWrite
+	deletesPerStatement := s.dbInfo.RowsPerStatement(tupleKeyParams)
+	for start, totalDeletes := 0, len(deleteConditions); start < totalDeletes; start += deletesPerStatement {
+		end := start + deletesPerStatement
 		if end > totalDeletes {
 			end = totalDeletes
 		}
@@ -301,8 +318,9 @@ func (s *Datastore) write(
 	}
 
 	// Execute writes
-	for start, totalWrites := 0, len(writeItems); start < totalWrites; start += storage.DefaultMaxTuplesPerWrite {
-		end := start + storage.DefaultMaxTuplesPerWrite
+	writesPerStatement := s.dbInfo.RowsPerStatement(tupleRowParams)
+	for start, totalWrites := 0, len(writeItems); start < totalWrites; start += writesPerStatement {
+		end := start + writesPerStatement
 		if end > totalWrites {
 			end = totalWrites
 		}
@@ -342,8 +360,9 @@ func (s *Datastore) write(
 	}
 
 	// 6. Execute INSERT changelog statements
-	for start, totalItems := 0, len(changeLogItems); start < totalItems; start += storage.DefaultMaxTuplesPerWrite {
-		end := start + storage.DefaultMaxTuplesPerWrite
+	changesPerStatement := s.dbInfo.RowsPerStatement(changelogRowParams)
+	for start, totalItems := 0, len(changeLogItems); start < totalItems; start += changesPerStatement {
+		end := start + changesPerStatement
 		if end > totalItems {
 			end = totalItems
 		}
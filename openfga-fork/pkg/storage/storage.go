@@ -13,9 +13,9 @@ type ctxKey string
 
 const (
 	// DefaultMaxTuplesPerWrite specifies the default maximum number of tuples that can be written
-	// in a single write operation. This constant is used to limit the batch size in write operations
-	// to maintain performance and avoid overloading the system. The value is set to 100 tuples,
-	// which is a balance between efficiency and resource usage.
+	// in a single write operation. The value is set to 100 tuples, which is a balance between
+	// efficiency and resource usage. The SQL datastores split larger writes into several statements
+	// of the same transaction.
 	DefaultMaxTuplesPerWrite = 100
 
 	// DefaultMaxTypesPerAuthorizationModel defines the default upper limit on the number of distinct
@@ -106,6 +106,13 @@ type ReadPageOptions struct {
 	Consistency ConsistencyOptions
 }
 
+// SearchTuplesOptions represents the options that can
+// be used with the SearchTuples method.
+type SearchTuplesOptions struct {
+	Pagination  PaginationOptions
+	Consistency ConsistencyOptions
+}
+
 // ConsistencyOptions represents the options that can
 // be used for methods that accept a consistency preference.
 type ConsistencyOptions struct {
@@ -243,6 +250,51 @@ const (
 type TupleWriteOptions struct {
 	OnMissingDelete   OnMissingDelete
 	OnDuplicateInsert OnDuplicateInsert
+	Preconditions     WritePreconditions
+}
+
+// WritePreconditions are the conditions that must hold for a write to be applied. They are checked in the
+// transaction of the write, before any tuple is deleted or written. If one of them does not hold, the write
+// returns an error wrapping ErrWritePreconditionFailed and changes nothing.
+type WritePreconditions struct {
+	// TuplesExist must all exist, whatever their condition.
+	TuplesExist []*openfgav1.TupleKeyWithoutCondition
+	// TuplesNotExist must not exist, whatever their condition.
+	TuplesNotExist []*openfgav1.TupleKeyWithoutCondition
+	// LatestChangeULID, if not empty, must be the ULID of the latest change in the changelog of the store.
+	// The writes that check it are serialized per store, but they are not serialized with the writes that
+	// do not.
+	LatestChangeULID string
+}
+
+// TupleKeys returns the tuples of TuplesExist and TuplesNotExist.
+func (p WritePreconditions) TupleKeys() []*openfgav1.TupleKeyWithoutCondition {
+	keys := make([]*openfgav1.TupleKeyWithoutCondition, 0, len(p.TuplesExist)+len(p.TuplesNotExist))
+	keys = append(keys, p.TuplesExist...)
+	return append(keys, p.TuplesNotExist...)
+}
+
+// Check returns an error wrapping ErrWritePreconditionFailed for the first precondition that does not hold.
+// exists reports whether a tuple exists, and latestChangeULID is the ULID of the latest change of the store.
+// latestChangeULID is only used if LatestChangeULID is set.
+func (p WritePreconditions) Check(exists func(tk *openfgav1.TupleKeyWithoutCondition) bool, latestChangeULID string) error {
+	for _, tk := range p.TuplesExist {
+		if !exists(tk) {
+			return WritePreconditionFailedError("tuple does not exist: user: '%s', relation: '%s', object: '%s'",
+				tk.GetUser(), tk.GetRelation(), tk.GetObject())
+		}
+	}
+	for _, tk := range p.TuplesNotExist {
+		if exists(tk) {
+			return WritePreconditionFailedError("tuple exists: user: '%s', relation: '%s', object: '%s'",
+				tk.GetUser(), tk.GetRelation(), tk.GetObject())
+		}
+	}
+	if p.LatestChangeULID != "" && p.LatestChangeULID != latestChangeULID {
+		return WritePreconditionFailedError("the latest change of the store is '%s', not '%s'",
+			latestChangeULID, p.LatestChangeULID)
+	}
+	return nil
 }
 
 type TupleWriteOption func(*TupleWriteOptions)
@@ -259,6 +311,13 @@ func WithOnDuplicateInsert(onDuplicateInsert OnDuplicateInsert) TupleWriteOption
 	}
 }
 
+// WithPreconditions sets the preconditions of the write. See WritePreconditions.
+func WithPreconditions(preconditions WritePreconditions) TupleWriteOption {
+	return func(opts *TupleWriteOptions) {
+		opts.Preconditions = preconditions
+	}
+}
+
 func NewTupleWriteOptions(opts ...TupleWriteOption) TupleWriteOptions {
 	res := TupleWriteOptions{
 		OnMissingDelete:   OnMissingDeleteError,
@@ -309,6 +368,63 @@ type ReadUsersetTuplesFilter struct {
 	AllowedUserTypeRestrictions []*openfgav1.RelationReference // Optional.
 }
 
+// SearchTuplesFilter specifies the filter options that will be used
+// to constrain the [TupleSearchBackend.SearchTuples] query. Empty fields are ignored.
+type SearchTuplesFilter struct {
+	ObjectType string
+	// ObjectIDPrefix matches the object IDs that start with it.
+	ObjectIDPrefix string
+	Relation       string
+	// UserType matches the users of the type, including the wildcard and the usersets of the type.
+	UserType string
+	// UserPrefix matches the users that start with it, e.g. 'user:a' or 'group:eng#'.
+	UserPrefix    string
+	ConditionName string
+	// FromULID is the inclusive lower bound of the ULIDs of the tuples.
+	FromULID string
+	// ToULID is the exclusive upper bound of the ULIDs of the tuples.
+	ToULID string
+}
+
+// TupleSearchBackend provides a read interface for searching the tuples of a store
+// with filters that are not supported by [RelationshipTupleReader]. A datastore may
+// implement it in addition to [OpenFGADatastore]; the server answers SearchTuples
+// with Unimplemented if it does not.
+type TupleSearchBackend interface {
+	// SearchTuples returns a page of the tuples of the store that match every field of the filter, in
+	// ascending order of ULID. PageSize will always be greater than zero.
+	// In addition to the tuples, it returns a continuation token, which is the ULID of the first tuple
+	// of the next page, or empty if there are no more tuples. When passed as the pagination From,
+	// the search starts at the tuple with that ULID.
+	SearchTuples(ctx context.Context, store string, filter SearchTuplesFilter, options SearchTuplesOptions) ([]*openfgav1.Tuple, string, error)
+}
+
+// TupleCount is the number of tuples of a store with the same object type, relation and user type.
+type TupleCount struct {
+	ObjectType string
+	Relation   string
+	UserType   string
+	Count      int64
+}
+
+// StoreStats holds aggregate statistics of the rows of a store.
+type StoreStats struct {
+	// TupleCounts are sorted by object type, relation and user type.
+	TupleCounts             []TupleCount
+	ChangelogCount          int64
+	AuthorizationModelCount int64
+	// LatestChangeULID is the ULID of the latest change of the changelog, or empty if there are no changes.
+	LatestChangeULID string
+}
+
+// StoreStatsBackend provides aggregate statistics of a store. Like [TupleSearchBackend],
+// it is optional, and GetStoreStats is Unimplemented for the datastores without it.
+type StoreStatsBackend interface {
+	// ReadStoreStats returns the statistics of the store. The statistics of a store without rows are zero,
+	// it does not return ErrNotFound.
+	ReadStoreStats(ctx context.Context, store string) (*StoreStats, error)
+}
+
 // AuthorizationModelReadBackend provides a read interface for managing type definitions.
 type AuthorizationModelReadBackend interface {
 	// ReadAuthorizationModel reads the model corresponding to store and model ID.
@@ -322,6 +438,10 @@ type AuthorizationModelReadBackend interface {
 	// FindLatestAuthorizationModel returns the last model for the store.
 	// If none were ever written, it must return ErrNotFound.
 	FindLatestAuthorizationModel(ctx context.Context, store string) (*openfgav1.AuthorizationModel, error)
+
+	// ReadActiveAuthorizationModelID returns the ID of the model that the store is pinned to.
+	// If the store is not pinned to a model, it must return ErrNotFound.
+	ReadActiveAuthorizationModelID(ctx context.Context, store string) (string, error)
 }
 
 // TypeDefinitionWriteBackend provides a write interface for managing typed definition.
@@ -334,10 +454,19 @@ type TypeDefinitionWriteBackend interface {
 	WriteAuthorizationModel(ctx context.Context, store string, model *openfgav1.AuthorizationModel) error
 }
 
+// ActiveAuthorizationModelWriteBackend provides a write interface for pinning a store to one of its models.
+type ActiveAuthorizationModelWriteBackend interface {
+	// WriteActiveAuthorizationModelID pins the store to the given model ID, replacing any previous pin.
+	// An empty model ID removes the pin, so that the latest model is used again.
+	// It does not validate that the model exists.
+	WriteActiveAuthorizationModelID(ctx context.Context, store string, modelID string) error
+}
+
 // AuthorizationModelBackend provides an read/write interface for managing models and their type definitions.
 type AuthorizationModelBackend interface {
 	AuthorizationModelReadBackend
 	TypeDefinitionWriteBackend
+	ActiveAuthorizationModelWriteBackend
 }
 
 type StoresBackend interface {
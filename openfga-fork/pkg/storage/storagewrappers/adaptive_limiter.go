@@ -0,0 +1,287 @@
+package storagewrappers
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+
+	"github.com/openfga/openfga/internal/build"
+	"github.com/openfga/openfga/pkg/storage"
+)
+
+const (
+	defaultAdaptiveLimiterLatencyThreshold = 100 * time.Millisecond
+	defaultAdaptiveLimiterBackoffRatio     = 0.9
+
+	// latencyWeight is the weight of each read in the moving average of the read latency.
+	latencyWeight = 0.1
+	// latencyHalfLife is the time after which the moving average of the read latency is halved when there are
+	// no reads, so that it recovers when the load shedder stops all the reads that would update it.
+	latencyHalfLife = time.Second
+
+	decreaseReasonLatency = "latency"
+	decreaseReasonError   = "error"
+)
+
+var (
+	adaptiveConcurrencyLimitGauge = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: build.ProjectName,
+		Name:      "datastore_adaptive_concurrency_limit",
+		Help:      "The number of concurrent datastore reads currently allowed by the adaptive concurrency limiter.",
+	})
+
+	adaptiveConcurrencyInFlightGauge = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: build.ProjectName,
+		Name:      "datastore_adaptive_concurrency_in_flight",
+		Help:      "The number of datastore reads currently admitted by the adaptive concurrency limiter.",
+	})
+
+	adaptiveConcurrencyDecreaseCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: build.ProjectName,
+		Name:      "datastore_adaptive_concurrency_decrease_count",
+		Help:      "The total number of times the adaptive concurrency limiter decreased its limit, by reason ('latency' or 'error').",
+	}, []string{"reason"})
+
+	adaptiveConcurrencyDelayMsHistogram = promauto.NewHistogram(prometheus.HistogramOpts{
+		Namespace:                       build.ProjectName,
+		Name:                            "datastore_adaptive_concurrency_delay_ms",
+		Help:                            "Time spent waiting for the adaptive concurrency limiter to admit a datastore read",
+		Buckets:                         []float64{1, 3, 5, 10, 25, 50, 100, 1000, 5000}, // Milliseconds. Upper bound is config.UpstreamTimeout.
+		NativeHistogramBucketFactor:     1.1,
+		NativeHistogramMaxBucketNumber:  100,
+		NativeHistogramMinResetDuration: time.Hour,
+	})
+)
+
+// AdaptiveLimiter bounds the number of concurrent datastore reads of all the requests served by a server. The
+// bound moves between a floor and a ceiling following an AIMD (additive increase, multiplicative decrease)
+// policy: every read slower than the latency threshold or failing with a datastore error multiplies the
+// bound by the backoff ratio, at most once per latency threshold, and every other read that happens while
+// at least half the bound is in use grows it by 1/bound, i.e. by one for each bound reads.
+//
+// A nil *AdaptiveLimiter admits every read.
+type AdaptiveLimiter struct {
+	minLimit         float64
+	maxLimit         float64
+	latencyThreshold time.Duration
+	backoffRatio     float64
+
+	mu           sync.Mutex
+	limit        float64
+	inFlight     int
+	waiting      int
+	released     chan struct{}
+	lastDecrease time.Time
+
+	// latency is the exponentially weighted moving average of the read latency, in nanoseconds, as of
+	// latencyUpdated, in Unix nanoseconds.
+	latency        atomic.Int64
+	latencyUpdated atomic.Int64
+}
+
+type AdaptiveLimiterOption func(*AdaptiveLimiter)
+
+// WithAdaptiveLimiterLatencyThreshold sets the datastore read latency over which the limit decreases.
+// The default is 100ms.
+func WithAdaptiveLimiterLatencyThreshold(threshold time.Duration) AdaptiveLimiterOption {
+	return func(l *AdaptiveLimiter) {
+		l.latencyThreshold = threshold
+	}
+}
+
+// WithAdaptiveLimiterBackoffRatio sets the ratio, between 0 and 1, the limit is multiplied by when it decreases.
+// The default is 0.9.
+func WithAdaptiveLimiterBackoffRatio(ratio float64) AdaptiveLimiterOption {
+	return func(l *AdaptiveLimiter) {
+		l.backoffRatio = ratio
+	}
+}
+
+// NewAdaptiveLimiter returns an AdaptiveLimiter whose limit starts at maxLimit and never goes below minLimit.
+func NewAdaptiveLimiter(minLimit, maxLimit int, opts ...AdaptiveLimiterOption) *AdaptiveLimiter {
+	l := &AdaptiveLimiter{
+		minLimit:         float64(minLimit),
+		maxLimit:         float64(maxLimit),
+		latencyThreshold: defaultAdaptiveLimiterLatencyThreshold,
+		backoffRatio:     defaultAdaptiveLimiterBackoffRatio,
+		limit:            float64(maxLimit),
+		released:         make(chan struct{}),
+	}
+
+	for _, opt := range opts {
+		opt(l)
+	}
+
+	adaptiveConcurrencyLimitGauge.Set(l.limit)
+	return l
+}
+
+// Limit returns the number of concurrent reads currently allowed.
+func (l *AdaptiveLimiter) Limit() int {
+	if l == nil {
+		return 0
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return int(l.limit)
+}
+
+// Latency returns the moving average of the latency of the reads, which weighs recent reads the most. It
+// decays over time, halving every latencyHalfLife without reads.
+func (l *AdaptiveLimiter) Latency() time.Duration {
+	if l == nil {
+		return 0
+	}
+	return l.decayedLatency(time.Now())
+}
+
+func (l *AdaptiveLimiter) decayedLatency(now time.Time) time.Duration {
+	average := time.Duration(l.latency.Load())
+	elapsed := now.Sub(time.Unix(0, l.latencyUpdated.Load()))
+	if average == 0 || elapsed <= 0 {
+		return average
+	}
+	return time.Duration(float64(average) * math.Exp2(-float64(elapsed)/float64(latencyHalfLife)))
+}
+
+// Acquire blocks until a read is admitted or ctx is done. Every successful call must be followed by a call to
+// Release.
+func (l *AdaptiveLimiter) Acquire(ctx context.Context) error {
+	if l == nil {
+		return nil
+	}
+
+	start := time.Now()
+	l.mu.Lock()
+	for l.inFlight >= int(l.limit) {
+		l.waiting++
+		released := l.released
+		l.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			l.mu.Lock()
+			l.waiting--
+			l.mu.Unlock()
+			return ctx.Err()
+		case <-released:
+		}
+
+		l.mu.Lock()
+		l.waiting--
+	}
+	l.inFlight++
+	adaptiveConcurrencyInFlightGauge.Set(float64(l.inFlight))
+	l.mu.Unlock()
+
+	if d := time.Since(start); d > concurrentTimeWaitingThreshold {
+		adaptiveConcurrencyDelayMsHistogram.Observe(float64(d.Milliseconds()))
+	}
+	return nil
+}
+
+// Release ends a read admitted by Acquire that took latency and returned err, and adjusts the limit.
+func (l *AdaptiveLimiter) Release(latency time.Duration, err error) {
+	if l == nil {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	inUse := l.inFlight
+	l.inFlight--
+	adaptiveConcurrencyInFlightGauge.Set(float64(l.inFlight))
+
+	now := time.Now()
+	average := l.decayedLatency(now)
+	l.latency.Store(int64(average + time.Duration(latencyWeight*float64(latency-average))))
+	l.latencyUpdated.Store(now.UnixNano())
+
+	switch {
+	case isDatastoreError(err):
+		l.decrease(decreaseReasonError)
+	case latency > l.latencyThreshold:
+		l.decrease(decreaseReasonLatency)
+	case float64(inUse) >= l.limit/2:
+		l.limit = min(l.maxLimit, l.limit+1/l.limit)
+		adaptiveConcurrencyLimitGauge.Set(l.limit)
+	}
+
+	if l.waiting > 0 {
+		close(l.released)
+		l.released = make(chan struct{})
+	}
+}
+
+// decrease backs off the limit, unless it already did within the last latency threshold so that a burst of
+// slow reads started under the same conditions only counts once.
+func (l *AdaptiveLimiter) decrease(reason string) {
+	now := time.Now()
+	if now.Sub(l.lastDecrease) < l.latencyThreshold {
+		return
+	}
+	l.lastDecrease = now
+
+	l.limit = max(l.minLimit, l.limit*l.backoffRatio)
+	adaptiveConcurrencyLimitGauge.Set(l.limit)
+	adaptiveConcurrencyDecreaseCounter.WithLabelValues(reason).Inc()
+}
+
+// isDatastoreError reports whether err signals a datastore in trouble, as opposed to an empty result or a
+// request that was canceled or timed out.
+func isDatastoreError(err error) bool {
+	return err != nil &&
+		!errors.Is(err, storage.ErrNotFound) &&
+		!errors.Is(err, storage.ErrIteratorDone) &&
+		!errors.Is(err, context.Canceled) &&
+		!errors.Is(err, context.DeadlineExceeded)
+}
+
+// adaptiveLimiterIterator runs the first read of an iterator under the adaptive limiter. The datastores
+// query lazily, when the first tuple is requested, so that is the read that reaches the datastore.
+type adaptiveLimiterIterator struct {
+	storage.TupleIterator
+	limiter *AdaptiveLimiter
+	started atomic.Bool
+}
+
+var _ storage.TupleIterator = (*adaptiveLimiterIterator)(nil)
+
+func newAdaptiveLimiterIterator(iter storage.TupleIterator, limiter *AdaptiveLimiter) storage.TupleIterator {
+	if limiter == nil {
+		return iter
+	}
+	return &adaptiveLimiterIterator{TupleIterator: iter, limiter: limiter}
+}
+
+func (i *adaptiveLimiterIterator) Next(ctx context.Context) (*openfgav1.Tuple, error) {
+	return i.read(ctx, i.TupleIterator.Next)
+}
+
+func (i *adaptiveLimiterIterator) Head(ctx context.Context) (*openfgav1.Tuple, error) {
+	return i.read(ctx, i.TupleIterator.Head)
+}
+
+func (i *adaptiveLimiterIterator) read(ctx context.Context, next func(context.Context) (*openfgav1.Tuple, error)) (*openfgav1.Tuple, error) {
+	if !i.started.CompareAndSwap(false, true) {
+		return next(ctx)
+	}
+
+	if err := i.limiter.Acquire(ctx); err != nil {
+		return nil, err
+	}
+	start := time.Now()
+	t, err := next(ctx)
+	i.limiter.Release(time.Since(start), err)
+	return t, err
+}
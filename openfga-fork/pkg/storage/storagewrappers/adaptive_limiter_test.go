@@ -0,0 +1,146 @@
+package storagewrappers
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+	"github.com/stretchr/testify/require"
+
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+
+	"github.com/openfga/openfga/internal/utils/apimethod"
+	"github.com/openfga/openfga/pkg/storage"
+	"github.com/openfga/openfga/pkg/storage/memory"
+	"github.com/openfga/openfga/pkg/tuple"
+)
+
+func TestAdaptiveLimiter(t *testing.T) {
+	t.Run("decreases_on_slow_reads_down_to_the_floor", func(t *testing.T) {
+		l := NewAdaptiveLimiter(2, 10, WithAdaptiveLimiterLatencyThreshold(time.Nanosecond), WithAdaptiveLimiterBackoffRatio(0.5))
+		require.Equal(t, 10, l.Limit())
+
+		for range 5 {
+			require.NoError(t, l.Acquire(context.Background()))
+			l.Release(time.Second, nil)
+			time.Sleep(time.Millisecond)
+		}
+		require.Equal(t, 2, l.Limit())
+	})
+
+	t.Run("decreases_on_datastore_errors_only", func(t *testing.T) {
+		l := NewAdaptiveLimiter(1, 10, WithAdaptiveLimiterLatencyThreshold(time.Nanosecond), WithAdaptiveLimiterBackoffRatio(0.5))
+
+		for _, err := range []error{storage.ErrNotFound, storage.ErrIteratorDone, context.Canceled} {
+			require.NoError(t, l.Acquire(context.Background()))
+			l.Release(0, err)
+		}
+		require.Equal(t, 10, l.Limit())
+
+		require.NoError(t, l.Acquire(context.Background()))
+		l.Release(0, errors.New("connection reset"))
+		require.Equal(t, 5, l.Limit())
+	})
+
+	t.Run("decreases_once_per_latency_threshold", func(t *testing.T) {
+		l := NewAdaptiveLimiter(1, 10, WithAdaptiveLimiterLatencyThreshold(time.Hour), WithAdaptiveLimiterBackoffRatio(0.5))
+
+		for range 3 {
+			require.NoError(t, l.Acquire(context.Background()))
+			l.Release(2*time.Hour, nil)
+		}
+		require.Equal(t, 5, l.Limit())
+	})
+
+	t.Run("increases_up_to_the_ceiling_when_in_use", func(t *testing.T) {
+		l := NewAdaptiveLimiter(1, 4, WithAdaptiveLimiterLatencyThreshold(time.Nanosecond), WithAdaptiveLimiterBackoffRatio(0.5))
+		require.NoError(t, l.Acquire(context.Background()))
+		l.Release(time.Second, nil)
+		require.Equal(t, 2, l.Limit())
+
+		// Two concurrent reads use at least half the limit until it reaches the ceiling.
+		for range 100 {
+			require.NoError(t, l.Acquire(context.Background()))
+			require.NoError(t, l.Acquire(context.Background()))
+			l.Release(0, nil)
+			l.Release(0, nil)
+		}
+		require.Equal(t, 4, l.Limit())
+	})
+
+	t.Run("blocks_over_the_limit", func(t *testing.T) {
+		l := NewAdaptiveLimiter(1, 1)
+		require.NoError(t, l.Acquire(context.Background()))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		require.ErrorIs(t, l.Acquire(ctx), context.DeadlineExceeded)
+
+		acquired := make(chan error)
+		go func() {
+			acquired <- l.Acquire(context.Background())
+		}()
+		l.Release(0, nil)
+		require.NoError(t, <-acquired)
+		l.Release(0, nil)
+	})
+
+	t.Run("latency_decays_without_reads", func(t *testing.T) {
+		l := NewAdaptiveLimiter(1, 10)
+		require.NoError(t, l.Acquire(context.Background()))
+		l.Release(time.Second, nil)
+
+		latency := l.Latency()
+		require.Positive(t, latency)
+
+		updated := time.Unix(0, l.latencyUpdated.Load())
+		require.InDelta(t, float64(latency)/2, float64(l.decayedLatency(updated.Add(latencyHalfLife))), float64(time.Millisecond))
+		require.Less(t, l.decayedLatency(updated.Add(20*latencyHalfLife)), time.Millisecond)
+	})
+
+	t.Run("nil_admits_every_read", func(t *testing.T) {
+		var l *AdaptiveLimiter
+		require.NoError(t, l.Acquire(context.Background()))
+		l.Release(time.Hour, errors.New("error"))
+		require.Equal(t, 0, l.Limit())
+	})
+}
+
+func TestBoundedTupleReaderWithAdaptiveLimiter(t *testing.T) {
+	store := ulid.Make().String()
+	ds := memory.New()
+	t.Cleanup(ds.Close)
+
+	err := ds.Write(context.Background(), store, nil, []*openfgav1.TupleKey{
+		tuple.NewTupleKey("obj:1", "viewer", "user:anne"),
+	})
+	require.NoError(t, err)
+
+	l := NewAdaptiveLimiter(1, 1)
+	reader := NewBoundedTupleReader(ds, &Operation{Method: apimethod.Check, Concurrency: 10, AdaptiveLimiter: l})
+
+	iter, err := reader.Read(context.Background(), store, tuple.NewTupleKey("obj:1", "viewer", ""), storage.ReadOptions{})
+	require.NoError(t, err)
+	defer iter.Stop()
+
+	// The limiter is only held during the first read of the iterator, so that reading the user tuple does not wait.
+	_, err = reader.ReadUserTuple(context.Background(), store, tuple.NewTupleKey("obj:1", "viewer", "user:anne"), storage.ReadUserTupleOptions{})
+	require.NoError(t, err)
+
+	// Hold the only slot: the first read of the iterator waits for it.
+	require.NoError(t, l.Acquire(context.Background()))
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = iter.Next(ctx)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	l.Release(0, nil)
+
+	// Subsequent reads of the iterator are not limited.
+	require.NoError(t, l.Acquire(context.Background()))
+	defer l.Release(0, nil)
+	tk, err := iter.Next(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "user:anne", tk.GetKey().GetUser())
+}
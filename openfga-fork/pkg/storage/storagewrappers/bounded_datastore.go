@@ -15,6 +15,7 @@ import (
 	"github.com/openfga/openfga/internal/build"
 	"github.com/openfga/openfga/pkg/storage"
 	"github.com/openfga/openfga/pkg/storage/storagewrappers/storagewrappersutil"
+	"github.com/openfga/openfga/pkg/telemetry"
 )
 
 const timeWaitingAttribute = "datastore_time_waiting"
@@ -33,7 +34,7 @@ var (
 	_ storage.RelationshipTupleReader = (*BoundedTupleReader)(nil)
 	_ StorageInstrumentation          = (*BoundedTupleReader)(nil)
 
-	concurrentReadDelayMsHistogram = promauto.NewHistogramVec(prometheus.HistogramOpts{
+	concurrentReadDelayMsHistogram = telemetry.StoreLabeled(promauto.NewHistogramVec(prometheus.HistogramOpts{
 		Namespace:                       build.ProjectName,
 		Name:                            "datastore_bounded_read_delay_ms",
 		Help:                            "Time spent waiting for any relevant Tuple read calls to the datastore",
@@ -41,9 +42,9 @@ var (
 		NativeHistogramBucketFactor:     1.1,
 		NativeHistogramMaxBucketNumber:  100,
 		NativeHistogramMinResetDuration: time.Hour,
-	}, []string{"operation", "method"})
+	}, []string{"operation", "method", telemetry.StoreIDLabel}))
 
-	throttledReadDelayMsHistogram = promauto.NewHistogramVec(prometheus.HistogramOpts{
+	throttledReadDelayMsHistogram = telemetry.StoreLabeled(promauto.NewHistogramVec(prometheus.HistogramOpts{
 		Namespace:                       build.ProjectName,
 		Name:                            "datastore_throttled_read_delay_ms",
 		Help:                            "Time spent waiting for any relevant Tuple read calls to the datastore",
@@ -51,7 +52,7 @@ var (
 		NativeHistogramBucketFactor:     1.1,
 		NativeHistogramMaxBucketNumber:  100,
 		NativeHistogramMinResetDuration: time.Hour,
-	}, []string{"operation", "method"})
+	}, []string{"operation", "method", telemetry.StoreIDLabel}))
 )
 
 type BoundedTupleReader struct {
@@ -63,11 +64,14 @@ type BoundedTupleReader struct {
 	threshold    int
 	throttleTime time.Duration
 	throttled    atomic.Bool
+
+	adaptiveLimiter *AdaptiveLimiter
 }
 
 // NewBoundedTupleReader returns a wrapper over a datastore that makes sure that there are, at most,
 // "concurrency" concurrent calls to Read, ReadUserTuple and ReadUsersetTuples.
 // Consumers can then rest assured that one client will not hoard all the database connections available.
+// If the operation has an AdaptiveLimiter, the reads are also bounded by it.
 func NewBoundedTupleReader(wrapped storage.RelationshipTupleReader, op *Operation) *BoundedTupleReader {
 	return &BoundedTupleReader{
 		RelationshipTupleReader: wrapped,
@@ -77,6 +81,8 @@ func NewBoundedTupleReader(wrapped storage.RelationshipTupleReader, op *Operatio
 		method:       string(op.Method),
 		threshold:    op.ThrottleThreshold,
 		throttleTime: op.ThrottleDuration,
+
+		adaptiveLimiter: op.AdaptiveLimiter,
 	}
 }
 
@@ -94,24 +100,31 @@ func (b *BoundedTupleReader) ReadUserTuple(
 	tupleKey *openfgav1.TupleKey,
 	options storage.ReadUserTupleOptions,
 ) (*openfgav1.Tuple, error) {
-	err := b.bound(ctx, storagewrappersutil.OperationReadUserTuple)
+	err := b.bound(ctx, store, storagewrappersutil.OperationReadUserTuple)
 	if err != nil {
 		return nil, err
 	}
 
 	defer b.done()
-	return b.RelationshipTupleReader.ReadUserTuple(ctx, store, tupleKey, options)
+
+	if err := b.adaptiveLimiter.Acquire(ctx); err != nil {
+		return nil, err
+	}
+	start := time.Now()
+	t, err := b.RelationshipTupleReader.ReadUserTuple(ctx, store, tupleKey, options)
+	b.adaptiveLimiter.Release(time.Since(start), err)
+	return t, err
 }
 
 // Read the set of tuples associated with `store` and `TupleKey`, which may be nil or partially filled.
 func (b *BoundedTupleReader) Read(ctx context.Context, store string, tupleKey *openfgav1.TupleKey, options storage.ReadOptions) (storage.TupleIterator, error) {
-	err := b.bound(ctx, storagewrappersutil.OperationRead)
+	err := b.bound(ctx, store, storagewrappersutil.OperationRead)
 	if err != nil {
 		return nil, err
 	}
 
 	defer b.done()
-	return b.RelationshipTupleReader.Read(ctx, store, tupleKey, options)
+	return b.limitIterator(b.RelationshipTupleReader.Read(ctx, store, tupleKey, options))
 }
 
 // ReadUsersetTuples returns all userset tuples for a specified object and relation.
@@ -121,13 +134,13 @@ func (b *BoundedTupleReader) ReadUsersetTuples(
 	filter storage.ReadUsersetTuplesFilter,
 	options storage.ReadUsersetTuplesOptions,
 ) (storage.TupleIterator, error) {
-	err := b.bound(ctx, storagewrappersutil.OperationReadUsersetTuples)
+	err := b.bound(ctx, store, storagewrappersutil.OperationReadUsersetTuples)
 	if err != nil {
 		return nil, err
 	}
 
 	defer b.done()
-	return b.RelationshipTupleReader.ReadUsersetTuples(ctx, store, filter, options)
+	return b.limitIterator(b.RelationshipTupleReader.ReadUsersetTuples(ctx, store, filter, options))
 }
 
 // ReadStartingWithUser performs a reverse read of relationship tuples starting at one or
@@ -138,18 +151,26 @@ func (b *BoundedTupleReader) ReadStartingWithUser(
 	filter storage.ReadStartingWithUserFilter,
 	options storage.ReadStartingWithUserOptions,
 ) (storage.TupleIterator, error) {
-	err := b.bound(ctx, storagewrappersutil.OperationReadStartingWithUser)
+	err := b.bound(ctx, store, storagewrappersutil.OperationReadStartingWithUser)
 	if err != nil {
 		return nil, err
 	}
 
 	defer b.done()
 
-	return b.RelationshipTupleReader.ReadStartingWithUser(ctx, store, filter, options)
+	return b.limitIterator(b.RelationshipTupleReader.ReadStartingWithUser(ctx, store, filter, options))
+}
+
+// limitIterator bounds the first read of iter, which is the one that queries the datastore, by the adaptive limiter.
+func (b *BoundedTupleReader) limitIterator(iter storage.TupleIterator, err error) (storage.TupleIterator, error) {
+	if err != nil {
+		return nil, err
+	}
+	return newAdaptiveLimiterIterator(iter, b.adaptiveLimiter), nil
 }
 
-func (b *BoundedTupleReader) instrument(ctx context.Context, op string, d time.Duration, vec *prometheus.HistogramVec) {
-	vec.WithLabelValues(op, b.method).Observe(float64(d))
+func (b *BoundedTupleReader) instrument(ctx context.Context, store, op string, d time.Duration, vec *prometheus.HistogramVec) {
+	vec.WithLabelValues(op, b.method, telemetry.StoreLabelFromContext(ctx, store)).Observe(float64(d))
 
 	span := trace.SpanFromContext(ctx)
 	span.SetAttributes(attribute.Int64(timeWaitingAttribute, d.Milliseconds()))
@@ -157,14 +178,14 @@ func (b *BoundedTupleReader) instrument(ctx context.Context, op string, d time.D
 
 // bound will only allow the request to have a maximum number of concurrent access to the downstream datastore.
 // After a threshold of accesses has been granted, an artificial amount of latency will be added to the access.
-func (b *BoundedTupleReader) bound(ctx context.Context, op string) error {
+func (b *BoundedTupleReader) bound(ctx context.Context, store, op string) error {
 	startTime := time.Now()
 	if err := b.waitForLimiter(ctx); err != nil {
 		return err
 	}
 
 	if c := time.Since(startTime); c > concurrentTimeWaitingThreshold {
-		b.instrument(ctx, op, c, concurrentReadDelayMsHistogram)
+		b.instrument(ctx, store, op, c, concurrentReadDelayMsHistogram)
 	}
 
 	reads := b.increaseReads()
@@ -177,7 +198,7 @@ func (b *BoundedTupleReader) bound(ctx context.Context, op string) error {
 		case <-time.After(b.throttleTime):
 			break
 		}
-		b.instrument(ctx, op, time.Since(startTime), throttledReadDelayMsHistogram)
+		b.instrument(ctx, store, op, time.Since(startTime), throttledReadDelayMsHistogram)
 	}
 	return nil
 }
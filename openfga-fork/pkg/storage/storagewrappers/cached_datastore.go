@@ -21,6 +21,7 @@ import (
 	"github.com/openfga/openfga/pkg/logger"
 	"github.com/openfga/openfga/pkg/storage"
 	"github.com/openfga/openfga/pkg/storage/storagewrappers/storagewrappersutil"
+	"github.com/openfga/openfga/pkg/telemetry"
 	"github.com/openfga/openfga/pkg/tuple"
 )
 
@@ -29,25 +30,25 @@ var (
 
 	_ storage.RelationshipTupleReader = (*CachedDatastore)(nil)
 
-	tuplesCacheTotalCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+	tuplesCacheTotalCounter = telemetry.StoreLabeled(promauto.NewCounterVec(prometheus.CounterOpts{
 		Namespace: build.ProjectName,
 		Name:      "tuples_cache_total_count",
 		Help:      "The total number of created cached iterator instances.",
-	}, []string{"operation", "method"})
+	}, []string{"operation", "method", telemetry.StoreIDLabel}))
 
-	tuplesCacheHitCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+	tuplesCacheHitCounter = telemetry.StoreLabeled(promauto.NewCounterVec(prometheus.CounterOpts{
 		Namespace: build.ProjectName,
 		Name:      "tuples_cache_hit_count",
 		Help:      "The total number of cache hits from cached iterator instances.",
-	}, []string{"operation", "method"})
+	}, []string{"operation", "method", telemetry.StoreIDLabel}))
 
-	tuplesCacheDiscardCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+	tuplesCacheDiscardCounter = telemetry.StoreLabeled(promauto.NewCounterVec(prometheus.CounterOpts{
 		Namespace: build.ProjectName,
 		Name:      "tuples_cache_discard_count",
 		Help:      "The total number of discards from cached iterator instances.",
-	}, []string{"operation", "method"})
+	}, []string{"operation", "method", telemetry.StoreIDLabel}))
 
-	tuplesCacheSizeHistogram = promauto.NewHistogramVec(prometheus.HistogramOpts{
+	tuplesCacheSizeHistogram = telemetry.StoreLabeled(promauto.NewHistogramVec(prometheus.HistogramOpts{
 		Namespace:                       build.ProjectName,
 		Name:                            "tuples_cache_size",
 		Help:                            "The number of tuples cached.",
@@ -55,7 +56,7 @@ var (
 		NativeHistogramBucketFactor:     1.1,
 		NativeHistogramMaxBucketNumber:  100,
 		NativeHistogramMinResetDuration: time.Hour,
-	}, []string{"operation", "method"})
+	}, []string{"operation", "method", telemetry.StoreIDLabel}))
 
 	currentIteratorCacheCount = promauto.NewGaugeVec(prometheus.GaugeOpts{
 		Namespace: build.ProjectName,
@@ -346,11 +347,12 @@ func (c *CachedDatastore) newCachedIterator(
 ) (storage.TupleIterator, error) {
 	span := trace.SpanFromContext(ctx)
 	span.SetAttributes(attribute.String("cache_key", cacheKey))
-	tuplesCacheTotalCounter.WithLabelValues(operation, c.method).Inc()
+	storeLabel := telemetry.StoreLabelFromContext(ctx, store)
+	tuplesCacheTotalCounter.WithLabelValues(operation, c.method, storeLabel).Inc()
 
 	invalidStoreKey := storage.GetInvalidIteratorCacheKey(store)
 	if cacheEntry, ok := findInCache(c.cache, cacheKey, invalidStoreKey, invalidEntityKeys); ok {
-		tuplesCacheHitCounter.WithLabelValues(operation, c.method).Inc()
+		tuplesCacheHitCounter.WithLabelValues(operation, c.method, storeLabel).Inc()
 		span.SetAttributes(attribute.Bool("cached", true))
 
 		staticIter := storage.NewStaticIterator[*storage.TupleRecord](cacheEntry.Tuples)
@@ -372,11 +374,12 @@ func (c *CachedDatastore) newCachedIterator(
 
 	currentIteratorCacheCount.WithLabelValues("false").Inc()
 	return &cachedIterator{
-		ctx:       c.ctx,
-		iter:      iter,
-		store:     store,
-		operation: operation,
-		method:    c.method,
+		ctx:        c.ctx,
+		iter:       iter,
+		store:      store,
+		storeLabel: storeLabel,
+		operation:  operation,
+		method:     c.method,
 		// set an initial fraction capacity to balance constant reallocation and memory usage
 		tuples:            make([]*openfgav1.Tuple, 0, c.maxResultSize/2),
 		cacheKey:          cacheKey,
@@ -400,6 +403,7 @@ type cachedIterator struct {
 	ctx               context.Context
 	iter              storage.TupleIterator
 	store             string
+	storeLabel        string
 	operation         string
 	method            string
 	cacheKey          string
@@ -465,7 +469,7 @@ func (c *cachedIterator) Next(ctx context.Context) (*openfgav1.Tuple, error) {
 	if c.tuples != nil {
 		c.tuples = append(c.tuples, t)
 		if len(c.tuples) >= c.maxResultSize {
-			tuplesCacheDiscardCounter.WithLabelValues(c.operation, c.method).Inc()
+			tuplesCacheDiscardCounter.WithLabelValues(c.operation, c.method, c.storeLabel).Inc()
 			c.tuples = nil // don't store results that are incomplete
 		}
 	}
@@ -610,7 +614,7 @@ func (c *cachedIterator) addToBuffer(t *openfgav1.Tuple) bool {
 	c.records = append(c.records, record)
 
 	if len(c.records) >= c.maxResultSize {
-		tuplesCacheDiscardCounter.WithLabelValues(c.operation, c.method).Inc()
+		tuplesCacheDiscardCounter.WithLabelValues(c.operation, c.method, c.storeLabel).Inc()
 		c.tuples = nil
 		c.records = nil
 	}
@@ -640,5 +644,5 @@ func (c *cachedIterator) flush() {
 	for _, k := range c.invalidEntityKeys {
 		c.cache.Delete(k)
 	}
-	tuplesCacheSizeHistogram.WithLabelValues(c.operation, c.method).Observe(float64(len(records)))
+	tuplesCacheSizeHistogram.WithLabelValues(c.operation, c.method, c.storeLabel).Observe(float64(len(records)))
 }
@@ -2,7 +2,10 @@ package storagewrappers
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sort"
+	"strings"
 	"time"
 
 	"golang.org/x/sync/singleflight"
@@ -14,11 +17,35 @@ import (
 
 const ttl = time.Hour * 168
 
+// activeModelIDTTL bounds how long a model pin written by another server may be served from the cache. Pins
+// written or deleted through the cache are evicted right away.
+const activeModelIDTTL = 10 * time.Second
+
 var (
 	_ storage.OpenFGADatastore = (*cachedOpenFGADatastore)(nil)
 	_ storage.CacheItem        = (*cachedAuthorizationModel)(nil)
+	_ AuthorizationModelCache  = (*cachedOpenFGADatastore)(nil)
 )
 
+// AuthorizationModelCache is implemented by the datastores returned by NewCachedOpenFGADatastore.
+type AuthorizationModelCache interface {
+	// CachedAuthorizationModels returns the cached models of storeID, or of every store if storeID is empty,
+	// sorted by store and model ID.
+	CachedAuthorizationModels(storeID string) []AuthorizationModelCacheEntry
+	// FlushAuthorizationModels removes the cached models and model pin of storeID and returns how many models
+	// were removed.
+	FlushAuthorizationModels(storeID string) int
+	// CachedAuthorizationModelCount returns the number of cached models.
+	CachedAuthorizationModelCount() int
+}
+
+// AuthorizationModelCacheEntry identifies a cached authorization model.
+type AuthorizationModelCacheEntry struct {
+	StoreID       string `json:"store_id"`
+	ModelID       string `json:"authorization_model_id"`
+	SchemaVersion string `json:"schema_version"`
+}
+
 type cachedAuthorizationModel struct {
 	*openfgav1.AuthorizationModel
 }
@@ -27,23 +54,37 @@ func (c *cachedAuthorizationModel) CacheEntityType() string {
 	return "authz_model"
 }
 
+// cachedActiveAuthorizationModelID is the model pin of a store. The model ID is empty if the store is not pinned.
+type cachedActiveAuthorizationModelID struct {
+	modelID string
+}
+
 type cachedOpenFGADatastore struct {
 	storage.OpenFGADatastore
-	lookupGroup singleflight.Group
-	cache       storage.InMemoryCache[*cachedAuthorizationModel]
+	lookupGroup    singleflight.Group
+	cache          *storage.InMemoryLRUCache[*cachedAuthorizationModel]
+	activeModelIDs *storage.InMemoryLRUCache[*cachedActiveAuthorizationModelID]
 }
 
 // NewCachedOpenFGADatastore returns a wrapper over a datastore that caches up to maxSize
 // [*openfgav1.AuthorizationModel] on every call to storage.ReadAuthorizationModel.
 // It caches with unlimited TTL because models are immutable. It uses LRU for eviction.
+// The model pins of up to maxSize stores are cached too, for activeModelIDTTL since they are mutable.
 func NewCachedOpenFGADatastore(inner storage.OpenFGADatastore, maxSize int) (*cachedOpenFGADatastore, error) {
 	cache, err := storage.NewInMemoryLRUCache[*cachedAuthorizationModel](storage.WithMaxCacheSize[*cachedAuthorizationModel](int64(maxSize)))
 	if err != nil {
 		return nil, err
 	}
+	activeModelIDs, err := storage.NewInMemoryLRUCache[*cachedActiveAuthorizationModelID](
+		storage.WithMaxCacheSize[*cachedActiveAuthorizationModelID](int64(maxSize)))
+	if err != nil {
+		cache.Stop()
+		return nil, err
+	}
 	return &cachedOpenFGADatastore{
 		OpenFGADatastore: inner,
-		cache:            *cache,
+		cache:            cache,
+		activeModelIDs:   activeModelIDs,
 	}, nil
 }
 
@@ -77,8 +118,92 @@ func (c *cachedOpenFGADatastore) FindLatestAuthorizationModel(ctx context.Contex
 	return v.(*openfgav1.AuthorizationModel), nil
 }
 
+// ReadActiveAuthorizationModelID see [storage.AuthorizationModelReadBackend].ReadActiveAuthorizationModelID.
+// Both the pin and its absence are cached, so that the requests that omit the model ID do not read the pin
+// every time.
+func (c *cachedOpenFGADatastore) ReadActiveAuthorizationModelID(ctx context.Context, storeID string) (string, error) {
+	if cachedEntry := c.activeModelIDs.Get(storeID); cachedEntry != nil {
+		if cachedEntry.modelID == "" {
+			return "", storage.ErrNotFound
+		}
+		return cachedEntry.modelID, nil
+	}
+
+	v, err, _ := c.lookupGroup.Do("ReadActiveAuthorizationModelID:"+storeID, func() (interface{}, error) {
+		modelID, err := c.OpenFGADatastore.ReadActiveAuthorizationModelID(ctx, storeID)
+		if err != nil && !errors.Is(err, storage.ErrNotFound) {
+			return "", err
+		}
+		c.activeModelIDs.Set(storeID, &cachedActiveAuthorizationModelID{modelID: modelID}, activeModelIDTTL)
+		return modelID, err
+	})
+	if err != nil {
+		return "", err
+	}
+	return v.(string), nil
+}
+
+// WriteActiveAuthorizationModelID see [storage.ActiveAuthorizationModelWriteBackend].WriteActiveAuthorizationModelID.
+func (c *cachedOpenFGADatastore) WriteActiveAuthorizationModelID(ctx context.Context, storeID string, modelID string) error {
+	defer c.activeModelIDs.Delete(storeID)
+	return c.OpenFGADatastore.WriteActiveAuthorizationModelID(ctx, storeID, modelID)
+}
+
+// DeleteStore see [storage.StoresBackend].DeleteStore. It also evicts the model pin of the store.
+func (c *cachedOpenFGADatastore) DeleteStore(ctx context.Context, id string) error {
+	defer c.activeModelIDs.Delete(id)
+	return c.OpenFGADatastore.DeleteStore(ctx, id)
+}
+
+// CachedAuthorizationModels see [AuthorizationModelCache].CachedAuthorizationModels.
+func (c *cachedOpenFGADatastore) CachedAuthorizationModels(storeID string) []AuthorizationModelCacheEntry {
+	entries := make([]AuthorizationModelCacheEntry, 0)
+	c.cache.Range(func(key string, value *cachedAuthorizationModel) bool {
+		entryStoreID, modelID, _ := strings.Cut(key, ":")
+		if storeID == "" || entryStoreID == storeID {
+			entries = append(entries, AuthorizationModelCacheEntry{
+				StoreID:       entryStoreID,
+				ModelID:       modelID,
+				SchemaVersion: value.GetSchemaVersion(),
+			})
+		}
+		return true
+	})
+
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].StoreID != entries[j].StoreID {
+			return entries[i].StoreID < entries[j].StoreID
+		}
+		return entries[i].ModelID < entries[j].ModelID
+	})
+	return entries
+}
+
+// FlushAuthorizationModels see [AuthorizationModelCache].FlushAuthorizationModels.
+func (c *cachedOpenFGADatastore) FlushAuthorizationModels(storeID string) int {
+	var keys []string
+	c.cache.Range(func(key string, _ *cachedAuthorizationModel) bool {
+		if strings.HasPrefix(key, storeID+":") {
+			keys = append(keys, key)
+		}
+		return true
+	})
+
+	for _, key := range keys {
+		c.cache.Delete(key)
+	}
+	c.activeModelIDs.Delete(storeID)
+	return len(keys)
+}
+
+// CachedAuthorizationModelCount see [AuthorizationModelCache].CachedAuthorizationModelCount.
+func (c *cachedOpenFGADatastore) CachedAuthorizationModelCount() int {
+	return c.cache.Len()
+}
+
 // Close closes the datastore and cleans up any residual resources.
 func (c *cachedOpenFGADatastore) Close() {
 	c.cache.Stop()
+	c.activeModelIDs.Stop()
 	c.OpenFGADatastore.Close()
 }
@@ -15,6 +15,8 @@ import (
 	openfgav1 "github.com/openfga/api/proto/openfga/v1"
 
 	"github.com/openfga/openfga/internal/mocks"
+	"github.com/openfga/openfga/pkg/storage"
+	"github.com/openfga/openfga/pkg/storage/memory"
 	"github.com/openfga/openfga/pkg/typesystem"
 )
 
@@ -126,3 +128,78 @@ func TestSingleFlightFindLatestAuthorizationModel(t *testing.T) {
 	err = wg.Wait()
 	require.NoError(t, err)
 }
+
+func TestFlushAuthorizationModels(t *testing.T) {
+	ctx := context.Background()
+	ds := memory.New()
+	cachingBackend, err := NewCachedOpenFGADatastore(ds, 5)
+	require.NoError(t, err)
+	t.Cleanup(cachingBackend.Close)
+
+	storeID, otherStoreID := ulid.Make().String(), ulid.Make().String()
+	var modelIDs []string
+	for _, store := range []string{storeID, storeID, otherStoreID} {
+		model := &openfgav1.AuthorizationModel{
+			Id:              ulid.Make().String(),
+			SchemaVersion:   typesystem.SchemaVersion1_1,
+			TypeDefinitions: []*openfgav1.TypeDefinition{{Type: "user"}},
+		}
+		require.NoError(t, cachingBackend.WriteAuthorizationModel(ctx, store, model))
+		_, err := cachingBackend.ReadAuthorizationModel(ctx, store, model.GetId())
+		require.NoError(t, err)
+		modelIDs = append(modelIDs, model.GetId())
+	}
+
+	require.Equal(t, 3, cachingBackend.CachedAuthorizationModelCount())
+	require.Len(t, cachingBackend.CachedAuthorizationModels(""), 3)
+	require.Equal(t, []AuthorizationModelCacheEntry{
+		{StoreID: otherStoreID, ModelID: modelIDs[2], SchemaVersion: typesystem.SchemaVersion1_1},
+	}, cachingBackend.CachedAuthorizationModels(otherStoreID))
+
+	require.Equal(t, 2, cachingBackend.FlushAuthorizationModels(storeID))
+	require.Empty(t, cachingBackend.CachedAuthorizationModels(storeID))
+	require.Len(t, cachingBackend.CachedAuthorizationModels(otherStoreID), 1)
+}
+
+func TestReadActiveAuthorizationModelID(t *testing.T) {
+	ctx := context.Background()
+	t.Cleanup(func() {
+		goleak.VerifyNone(t)
+	})
+	mockController := gomock.NewController(t)
+
+	mockDatastore := mocks.NewMockOpenFGADatastore(mockController)
+	cachingBackend, err := NewCachedOpenFGADatastore(mockDatastore, 5)
+	require.NoError(t, err)
+	t.Cleanup(cachingBackend.Close)
+
+	storeID := ulid.Make().String()
+	modelID := ulid.Make().String()
+	gomock.InOrder(
+		mockDatastore.EXPECT().ReadActiveAuthorizationModelID(gomock.Any(), storeID).Times(1).Return("", storage.ErrNotFound),
+		mockDatastore.EXPECT().WriteActiveAuthorizationModelID(gomock.Any(), storeID, modelID).Times(1).Return(nil),
+		mockDatastore.EXPECT().ReadActiveAuthorizationModelID(gomock.Any(), storeID).Times(1).Return(modelID, nil),
+		mockDatastore.EXPECT().DeleteStore(gomock.Any(), storeID).Times(1).Return(nil),
+		mockDatastore.EXPECT().ReadActiveAuthorizationModelID(gomock.Any(), storeID).Times(1).Return("", storage.ErrNotFound),
+		mockDatastore.EXPECT().Close().Times(1),
+	)
+
+	// The absence of a pin is cached.
+	for i := 0; i < 2; i++ {
+		_, err = cachingBackend.ReadActiveAuthorizationModelID(ctx, storeID)
+		require.ErrorIs(t, err, storage.ErrNotFound)
+	}
+
+	// Pinning evicts the cached pin.
+	require.NoError(t, cachingBackend.WriteActiveAuthorizationModelID(ctx, storeID, modelID))
+	for i := 0; i < 2; i++ {
+		got, err := cachingBackend.ReadActiveAuthorizationModelID(ctx, storeID)
+		require.NoError(t, err)
+		require.Equal(t, modelID, got)
+	}
+
+	// Deleting the store evicts it too.
+	require.NoError(t, cachingBackend.DeleteStore(ctx, storeID))
+	_, err = cachingBackend.ReadActiveAuthorizationModelID(ctx, storeID)
+	require.ErrorIs(t, err, storage.ErrNotFound)
+}
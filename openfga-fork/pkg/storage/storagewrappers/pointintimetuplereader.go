@@ -0,0 +1,243 @@
+package storagewrappers
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+
+	"github.com/openfga/openfga/pkg/storage"
+	"github.com/openfga/openfga/pkg/tuple"
+)
+
+// NewPointInTimeTupleReader returns a [storage.RelationshipTupleReader] that reads the tuples of ds as they
+// were at an earlier point in time. The changed map holds every tuple that was written or deleted since then,
+// keyed by [tuple.TupleKeyToString]: a non-nil value is the tuple that existed at that point in time and a nil
+// value means the tuple did not exist. Tuples that are not in the map are read from ds unchanged.
+func NewPointInTimeTupleReader(
+	ds storage.RelationshipTupleReader,
+	changed map[string]*openfgav1.TupleKey,
+) *PointInTimeTupleReader {
+	var existing []*openfgav1.TupleKey
+	for _, tk := range changed {
+		if tk != nil {
+			existing = append(existing, tk)
+		}
+	}
+
+	slices.SortFunc(existing, func(a *openfgav1.TupleKey, b *openfgav1.TupleKey) int {
+		if c := strings.Compare(a.GetObject(), b.GetObject()); c != 0 {
+			return c
+		}
+		return strings.Compare(tuple.TupleKeyToString(a), tuple.TupleKeyToString(b))
+	})
+
+	return &PointInTimeTupleReader{
+		RelationshipTupleReader:   ds,
+		changed:                   changed,
+		existingOrderedByObjectID: existing,
+	}
+}
+
+type PointInTimeTupleReader struct {
+	storage.RelationshipTupleReader
+	changed                   map[string]*openfgav1.TupleKey
+	existingOrderedByObjectID []*openfgav1.TupleKey
+}
+
+var _ storage.RelationshipTupleReader = (*PointInTimeTupleReader)(nil)
+
+// existing returns the tuples that existed at the point in time but have changed since, and that match filter.
+func (p *PointInTimeTupleReader) existing(filter func(*openfgav1.TupleKey) bool) []*openfgav1.Tuple {
+	var tuples []*openfgav1.Tuple
+	for _, tk := range p.existingOrderedByObjectID {
+		if filter(tk) {
+			tuples = append(tuples, &openfgav1.Tuple{Key: tk})
+		}
+	}
+	return tuples
+}
+
+// unchanged wraps iter to drop the tuples that have changed since the point in time.
+func (p *PointInTimeTupleReader) unchanged(iter storage.TupleIterator) storage.TupleIterator {
+	return &unchangedTupleIterator{iter: iter, changed: p.changed}
+}
+
+// matchesReadFilter reports whether t matches the partially filled filter of a Read. The object
+// of the filter may be a type only, e.g. "document:".
+func matchesReadFilter(t *openfgav1.TupleKey, filter *openfgav1.TupleKey) bool {
+	if filter.GetObject() != "" {
+		objectType, objectID := tuple.SplitObject(filter.GetObject())
+		if objectID == "" && tuple.GetType(t.GetObject()) != objectType {
+			return false
+		}
+		if objectID != "" && t.GetObject() != filter.GetObject() {
+			return false
+		}
+	}
+	return (filter.GetRelation() == "" || t.GetRelation() == filter.GetRelation()) &&
+		(filter.GetUser() == "" || t.GetUser() == filter.GetUser())
+}
+
+// Read see [storage.RelationshipTupleReader.Read].
+func (p *PointInTimeTupleReader) Read(
+	ctx context.Context,
+	storeID string,
+	tk *openfgav1.TupleKey,
+	options storage.ReadOptions,
+) (storage.TupleIterator, error) {
+	iter, err := p.RelationshipTupleReader.Read(ctx, storeID, tk, options)
+	if err != nil {
+		return nil, err
+	}
+
+	existing := p.existing(func(t *openfgav1.TupleKey) bool {
+		return matchesReadFilter(t, tk)
+	})
+
+	return storage.NewCombinedIterator(storage.NewStaticTupleIterator(existing), p.unchanged(iter)), nil
+}
+
+// ReadPage see [storage.RelationshipTupleReader.ReadPage].
+// The tuples that existed at the point in time but have changed since are only returned on the first page.
+func (p *PointInTimeTupleReader) ReadPage(ctx context.Context, store string, tk *openfgav1.TupleKey, options storage.ReadPageOptions) ([]*openfgav1.Tuple, string, error) {
+	tuples, contToken, err := p.RelationshipTupleReader.ReadPage(ctx, store, tk, options)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var page []*openfgav1.Tuple
+	if options.Pagination.From == "" {
+		page = p.existing(func(t *openfgav1.TupleKey) bool {
+			return matchesReadFilter(t, tk)
+		})
+	}
+	for _, t := range tuples {
+		if _, ok := p.changed[tuple.TupleKeyToString(t.GetKey())]; !ok {
+			page = append(page, t)
+		}
+	}
+
+	return page, contToken, nil
+}
+
+// ReadUserTuple see [storage.RelationshipTupleReader.ReadUserTuple].
+func (p *PointInTimeTupleReader) ReadUserTuple(
+	ctx context.Context,
+	store string,
+	tk *openfgav1.TupleKey,
+	options storage.ReadUserTupleOptions,
+) (*openfgav1.Tuple, error) {
+	if existing, ok := p.changed[tuple.TupleKeyToString(tk)]; ok {
+		if existing == nil {
+			return nil, storage.ErrNotFound
+		}
+		return &openfgav1.Tuple{Key: existing}, nil
+	}
+
+	return p.RelationshipTupleReader.ReadUserTuple(ctx, store, tk, options)
+}
+
+// ReadUsersetTuples see [storage.RelationshipTupleReader.ReadUsersetTuples].
+func (p *PointInTimeTupleReader) ReadUsersetTuples(
+	ctx context.Context,
+	store string,
+	filter storage.ReadUsersetTuplesFilter,
+	options storage.ReadUsersetTuplesOptions,
+) (storage.TupleIterator, error) {
+	iter, err := p.RelationshipTupleReader.ReadUsersetTuples(ctx, store, filter, options)
+	if err != nil {
+		return nil, err
+	}
+
+	existing := p.existing(func(t *openfgav1.TupleKey) bool {
+		if t.GetObject() != filter.Object || t.GetRelation() != filter.Relation {
+			return false
+		}
+		if len(filter.AllowedUserTypeRestrictions) == 0 {
+			return tuple.GetUserTypeFromUser(t.GetUser()) == tuple.UserSet
+		}
+		return tupleMatchesAllowedUserTypeRestrictions(&openfgav1.Tuple{Key: t}, filter.AllowedUserTypeRestrictions)
+	})
+
+	return storage.NewCombinedIterator(storage.NewStaticTupleIterator(existing), p.unchanged(iter)), nil
+}
+
+// ReadStartingWithUser see [storage.RelationshipTupleReader.ReadStartingWithUser].
+func (p *PointInTimeTupleReader) ReadStartingWithUser(
+	ctx context.Context,
+	store string,
+	filter storage.ReadStartingWithUserFilter,
+	options storage.ReadStartingWithUserOptions,
+) (storage.TupleIterator, error) {
+	iter, err := p.RelationshipTupleReader.ReadStartingWithUser(ctx, store, filter, options)
+	if err != nil {
+		return nil, err
+	}
+
+	var userFilters []string
+	for _, u := range filter.UserFilter {
+		uf := u.GetObject()
+		if u.GetRelation() != "" {
+			uf = tuple.ToObjectRelationString(uf, u.GetRelation())
+		}
+		userFilters = append(userFilters, uf)
+	}
+
+	existing := p.existing(func(t *openfgav1.TupleKey) bool {
+		objectType, objectID := tuple.SplitObject(t.GetObject())
+		return objectType == filter.ObjectType &&
+			t.GetRelation() == filter.Relation &&
+			slices.Contains(userFilters, t.GetUser()) &&
+			(filter.ObjectIDs == nil || filter.ObjectIDs.Exists(objectID))
+	})
+
+	if options.WithResultsSortedAscending {
+		return storage.NewOrderedCombinedIterator(storage.ObjectMapper(), storage.NewStaticTupleIterator(existing), p.unchanged(iter)), nil
+	}
+
+	return storage.NewCombinedIterator(storage.NewStaticTupleIterator(existing), p.unchanged(iter)), nil
+}
+
+// unchangedTupleIterator is a [storage.TupleIterator] that skips the tuples in changed.
+type unchangedTupleIterator struct {
+	iter    storage.TupleIterator
+	changed map[string]*openfgav1.TupleKey
+}
+
+var _ storage.TupleIterator = (*unchangedTupleIterator)(nil)
+
+// Next see [storage.Iterator.Next].
+func (u *unchangedTupleIterator) Next(ctx context.Context) (*openfgav1.Tuple, error) {
+	for {
+		t, err := u.iter.Next(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := u.changed[tuple.TupleKeyToString(t.GetKey())]; !ok {
+			return t, nil
+		}
+	}
+}
+
+// Head see [storage.Iterator.Head].
+func (u *unchangedTupleIterator) Head(ctx context.Context) (*openfgav1.Tuple, error) {
+	for {
+		t, err := u.iter.Head(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := u.changed[tuple.TupleKeyToString(t.GetKey())]; !ok {
+			return t, nil
+		}
+		if _, err := u.iter.Next(ctx); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// Stop see [storage.Iterator.Stop].
+func (u *unchangedTupleIterator) Stop() {
+	u.iter.Stop()
+}
@@ -0,0 +1,98 @@
+package storagewrappers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/oklog/ulid/v2"
+	"github.com/stretchr/testify/require"
+
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+
+	"github.com/openfga/openfga/pkg/storage"
+	"github.com/openfga/openfga/pkg/storage/memory"
+	"github.com/openfga/openfga/pkg/tuple"
+)
+
+func TestPointInTimeTupleReader(t *testing.T) {
+	ctx := context.Background()
+	storeID := ulid.Make().String()
+
+	ds := memory.New()
+	t.Cleanup(ds.Close)
+
+	// Current state: document:1 is viewable by bob and group:eng, document:2 by anne.
+	err := ds.Write(ctx, storeID, nil, tuple.MustParseTupleStrings(
+		"document:1#viewer@user:bob",
+		"document:1#viewer@group:eng#member",
+		"document:2#viewer@user:anne",
+	))
+	require.NoError(t, err)
+
+	// At the point in time, anne viewed document:1 instead of bob, and document:2 had no viewers.
+	reader := NewPointInTimeTupleReader(ds, map[string]*openfgav1.TupleKey{
+		"document:1#viewer@user:bob":  nil,
+		"document:1#viewer@user:anne": tuple.NewTupleKey("document:1", "viewer", "user:anne"),
+		"document:2#viewer@user:anne": nil,
+	})
+
+	readAll := func(t *testing.T, iter storage.TupleIterator, err error) []string {
+		require.NoError(t, err)
+		defer iter.Stop()
+
+		var tuples []string
+		for {
+			tup, err := iter.Next(ctx)
+			if err != nil {
+				require.ErrorIs(t, err, storage.ErrIteratorDone)
+				return tuples
+			}
+			tuples = append(tuples, tuple.TupleKeyToString(tup.GetKey()))
+		}
+	}
+
+	t.Run("read", func(t *testing.T) {
+		iter, err := reader.Read(ctx, storeID, tuple.NewTupleKey("document:", "", ""), storage.ReadOptions{})
+		require.ElementsMatch(t, []string{
+			"document:1#viewer@user:anne",
+			"document:1#viewer@group:eng#member",
+		}, readAll(t, iter, err))
+	})
+
+	t.Run("read_page", func(t *testing.T) {
+		tuples, _, err := reader.ReadPage(ctx, storeID, tuple.NewTupleKey("document:1", "", ""), storage.ReadPageOptions{
+			Pagination: storage.NewPaginationOptions(10, ""),
+		})
+		require.NoError(t, err)
+		require.Len(t, tuples, 2)
+	})
+
+	t.Run("read_user_tuple", func(t *testing.T) {
+		tup, err := reader.ReadUserTuple(ctx, storeID, tuple.NewTupleKey("document:1", "viewer", "user:anne"), storage.ReadUserTupleOptions{})
+		require.NoError(t, err)
+		require.Equal(t, "user:anne", tup.GetKey().GetUser())
+
+		_, err = reader.ReadUserTuple(ctx, storeID, tuple.NewTupleKey("document:1", "viewer", "user:bob"), storage.ReadUserTupleOptions{})
+		require.ErrorIs(t, err, storage.ErrNotFound)
+
+		_, err = reader.ReadUserTuple(ctx, storeID, tuple.NewTupleKey("document:1", "viewer", "group:eng#member"), storage.ReadUserTupleOptions{})
+		require.NoError(t, err)
+	})
+
+	t.Run("read_userset_tuples", func(t *testing.T) {
+		iter, err := reader.ReadUsersetTuples(ctx, storeID, storage.ReadUsersetTuplesFilter{
+			Object:   "document:1",
+			Relation: "viewer",
+		}, storage.ReadUsersetTuplesOptions{})
+		require.Equal(t, []string{"document:1#viewer@group:eng#member"}, readAll(t, iter, err))
+	})
+
+	t.Run("read_starting_with_user", func(t *testing.T) {
+		iter, err := reader.ReadStartingWithUser(ctx, storeID, storage.ReadStartingWithUserFilter{
+			ObjectType: "document",
+			Relation:   "viewer",
+			UserFilter: []*openfgav1.ObjectRelation{{Object: "user:anne"}, {Object: "user:bob"}},
+		}, storage.ReadStartingWithUserOptions{WithResultsSortedAscending: true})
+		require.Equal(t, []string{"document:1#viewer@user:anne"}, readAll(t, iter, err))
+	})
+}
@@ -19,6 +19,8 @@ type Operation struct {
 	Concurrency       uint32
 	ThrottleThreshold int
 	ThrottleDuration  time.Duration
+	// AdaptiveLimiter, if set, bounds the reads of all the requests sharing it.
+	AdaptiveLimiter *AdaptiveLimiter
 }
 
 // RequestStorageWrapper uses the decorator pattern to wrap a RelationshipTupleReader with various functionalities,
@@ -32,6 +34,9 @@ type DataResourceConfiguration struct {
 	Resources      *shared.SharedDatastoreResources
 	CacheSettings  config.CacheSettings
 	UseShadowCache bool
+	// RequestSharedIterators, if set, shares the iterators of the request among its own reads when the
+	// iterators are not already shared across requests.
+	RequestSharedIterators *sharediterator.Storage
 }
 
 var _ StorageInstrumentation = (*RequestStorageWrapper)(nil)
@@ -80,6 +85,9 @@ func NewRequestStorageWrapperWithCache(
 		tupleReader = sharediterator.NewSharedIteratorDatastore(tupleReader, dataResourceConfiguration.Resources.SharedIteratorStorage,
 			sharediterator.WithSharedIteratorDatastoreLogger(dataResourceConfiguration.Resources.Logger),
 			sharediterator.WithMethod(string(op.Method)))
+	} else if dataResourceConfiguration.RequestSharedIterators != nil {
+		tupleReader = sharediterator.NewSharedIteratorDatastore(tupleReader, dataResourceConfiguration.RequestSharedIterators,
+			sharediterator.WithMethod(string(op.Method)))
 	}
 	combinedTupleReader := NewCombinedTupleReader(tupleReader, requestContextualTuples) // to read the contextual tuples
 
@@ -168,6 +168,11 @@ func NewSharedIteratorDatastoreStorage(opts ...DatastoreStorageOpt) *Storage {
 	return newStorage
 }
 
+// Len returns the number of shared iterators in the storage.
+func (s *Storage) Len() int {
+	return int(s.ctr.Load())
+}
+
 type IteratorDatastoreOpt func(*IteratorDatastore)
 
 // WithSharedIteratorDatastoreLogger sets the logger for the IteratorDatastore.
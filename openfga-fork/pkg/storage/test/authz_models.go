@@ -287,3 +287,67 @@ func FindLatestAuthorizationModelTest(t *testing.T, datastore storage.OpenFGADat
 		}
 	})
 }
+
+func ActiveAuthorizationModelTest(t *testing.T, datastore storage.OpenFGADatastore) {
+	ctx := context.Background()
+
+	t.Run("read_active_authorization_model_id_should_return_not_found_when_not_pinned", func(t *testing.T) {
+		_, err := datastore.ReadActiveAuthorizationModelID(ctx, ulid.Make().String())
+		require.ErrorIs(t, err, storage.ErrNotFound)
+	})
+
+	t.Run("write_active_authorization_model_id_should_pin_and_replace", func(t *testing.T) {
+		store := ulid.Make().String()
+		modelOne := ulid.Make().String()
+		modelTwo := ulid.Make().String()
+
+		err := datastore.WriteActiveAuthorizationModelID(ctx, store, modelOne)
+		require.NoError(t, err)
+
+		got, err := datastore.ReadActiveAuthorizationModelID(ctx, store)
+		require.NoError(t, err)
+		require.Equal(t, modelOne, got)
+
+		err = datastore.WriteActiveAuthorizationModelID(ctx, store, modelTwo)
+		require.NoError(t, err)
+
+		got, err = datastore.ReadActiveAuthorizationModelID(ctx, store)
+		require.NoError(t, err)
+		require.Equal(t, modelTwo, got)
+
+		// Pins are per store.
+		_, err = datastore.ReadActiveAuthorizationModelID(ctx, ulid.Make().String())
+		require.ErrorIs(t, err, storage.ErrNotFound)
+	})
+
+	t.Run("write_empty_active_authorization_model_id_should_remove_pin", func(t *testing.T) {
+		store := ulid.Make().String()
+
+		err := datastore.WriteActiveAuthorizationModelID(ctx, store, ulid.Make().String())
+		require.NoError(t, err)
+
+		err = datastore.WriteActiveAuthorizationModelID(ctx, store, "")
+		require.NoError(t, err)
+
+		_, err = datastore.ReadActiveAuthorizationModelID(ctx, store)
+		require.ErrorIs(t, err, storage.ErrNotFound)
+
+		// Removing a pin that does not exist is a no-op.
+		err = datastore.WriteActiveAuthorizationModelID(ctx, store, "")
+		require.NoError(t, err)
+	})
+
+	t.Run("delete_store_should_remove_pin", func(t *testing.T) {
+		store, err := datastore.CreateStore(ctx, &openfgav1.Store{Id: ulid.Make().String(), Name: "pinned"})
+		require.NoError(t, err)
+
+		err = datastore.WriteActiveAuthorizationModelID(ctx, store.GetId(), ulid.Make().String())
+		require.NoError(t, err)
+
+		err = datastore.DeleteStore(ctx, store.GetId())
+		require.NoError(t, err)
+
+		_, err = datastore.ReadActiveAuthorizationModelID(ctx, store.GetId())
+		require.ErrorIs(t, err, storage.ErrNotFound)
+	})
+}
@@ -0,0 +1,81 @@
+package test
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/oklog/ulid/v2"
+	"github.com/stretchr/testify/require"
+
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+
+	"github.com/openfga/openfga/pkg/storage"
+	"github.com/openfga/openfga/pkg/tuple"
+)
+
+// LargeWriteTest checks that writes of more tuples than a statement holds are applied atomically.
+func LargeWriteTest(t *testing.T, datastore storage.OpenFGADatastore) {
+	statsBackend, ok := datastore.(storage.StoreStatsBackend)
+	if !ok {
+		t.Skip("the datastore does not implement storage.StoreStatsBackend")
+	}
+	ctx := context.Background()
+
+	const count = 2500
+
+	tupleKeys := func(relation string) []*openfgav1.TupleKey {
+		tks := make([]*openfgav1.TupleKey, 0, count)
+		for i := 0; i < count; i++ {
+			tks = append(tks, tuple.NewTupleKey("menu_item:"+strconv.Itoa(i), relation, "role:admin#assignee"))
+		}
+		return tks
+	}
+	stats := func(storeID string) (tuples, changes int64) {
+		stats, err := statsBackend.ReadStoreStats(ctx, storeID)
+		require.NoError(t, err)
+		for _, c := range stats.TupleCounts {
+			tuples += c.Count
+		}
+		return tuples, stats.ChangelogCount
+	}
+
+	t.Run("reassign_in_one_write", func(t *testing.T) {
+		storeID := ulid.Make().String()
+
+		err := datastore.Write(ctx, storeID, nil, tupleKeys("viewer"))
+		require.NoError(t, err)
+
+		var deletes []*openfgav1.TupleKeyWithoutCondition
+		for _, tk := range tupleKeys("viewer") {
+			deletes = append(deletes, tuple.TupleKeyToTupleKeyWithoutCondition(tk))
+		}
+		err = datastore.Write(ctx, storeID, deletes, tupleKeys("editor"))
+		require.NoError(t, err)
+
+		tuples, changes := stats(storeID)
+		require.Equal(t, int64(count), tuples)
+		require.Equal(t, int64(3*count), changes)
+
+		_, err = datastore.ReadUserTuple(ctx, storeID, tuple.NewTupleKey("menu_item:0", "viewer", "role:admin#assignee"), storage.ReadUserTupleOptions{})
+		require.ErrorIs(t, err, storage.ErrNotFound)
+		_, err = datastore.ReadUserTuple(ctx, storeID, tuple.NewTupleKey("menu_item:"+strconv.Itoa(count-1), "editor", "role:admin#assignee"), storage.ReadUserTupleOptions{})
+		require.NoError(t, err)
+	})
+
+	t.Run("rolled_back_as_a_whole", func(t *testing.T) {
+		storeID := ulid.Make().String()
+
+		writes := tupleKeys("viewer")
+		err := datastore.Write(ctx, storeID, nil, writes[count-1:])
+		require.NoError(t, err)
+
+		// The last tuple already exists, after all the others were inserted.
+		err = datastore.Write(ctx, storeID, nil, writes)
+		require.ErrorIs(t, err, storage.ErrInvalidWriteInput)
+
+		tuples, changes := stats(storeID)
+		require.Equal(t, int64(1), tuples)
+		require.Equal(t, int64(1), changes)
+	})
+}
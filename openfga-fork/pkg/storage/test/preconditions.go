@@ -0,0 +1,165 @@
+package test
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/oklog/ulid/v2"
+	"github.com/stretchr/testify/require"
+
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+
+	"github.com/openfga/openfga/pkg/storage"
+	"github.com/openfga/openfga/pkg/tuple"
+)
+
+func WritePreconditionsTest(t *testing.T, datastore storage.OpenFGADatastore) {
+	statsBackend, ok := datastore.(storage.StoreStatsBackend)
+	if !ok {
+		t.Skip("the datastore does not implement storage.StoreStatsBackend")
+	}
+	ctx := context.Background()
+
+	anne := tuple.NewTupleKey("role:admin", "assignee", "user:anne")
+	bob := tuple.NewTupleKey("role:admin", "assignee", "user:bob")
+	charlie := tuple.NewTupleKeyWithCondition("role:admin", "assignee", "user:charlie", "business_hours", nil)
+
+	setup := func(t *testing.T) (string, string) {
+		storeID := ulid.Make().String()
+		_, err := datastore.CreateStore(ctx, &openfgav1.Store{Id: storeID, Name: "store"})
+		require.NoError(t, err)
+		err = datastore.Write(ctx, storeID, nil, []*openfgav1.TupleKey{anne, charlie})
+		require.NoError(t, err)
+		stats, err := statsBackend.ReadStoreStats(ctx, storeID)
+		require.NoError(t, err)
+		return storeID, stats.LatestChangeULID
+	}
+
+	requireChangelogSize := func(t *testing.T, storeID string, expected int64) {
+		stats, err := statsBackend.ReadStoreStats(ctx, storeID)
+		require.NoError(t, err)
+		require.Equal(t, expected, stats.ChangelogCount)
+	}
+
+	withoutCondition := tuple.TupleKeyToTupleKeyWithoutCondition
+
+	t.Run("preconditions_hold", func(t *testing.T) {
+		storeID, latest := setup(t)
+
+		err := datastore.Write(ctx, storeID, []*openfgav1.TupleKeyWithoutCondition{withoutCondition(anne)}, []*openfgav1.TupleKey{bob},
+			storage.WithPreconditions(storage.WritePreconditions{
+				TuplesExist:      []*openfgav1.TupleKeyWithoutCondition{withoutCondition(anne), withoutCondition(tuple.NewTupleKey("role:admin", "assignee", "user:charlie"))},
+				TuplesNotExist:   []*openfgav1.TupleKeyWithoutCondition{withoutCondition(bob)},
+				LatestChangeULID: latest,
+			}))
+		require.NoError(t, err)
+		requireChangelogSize(t, storeID, 4)
+	})
+
+	t.Run("tuple_does_not_exist", func(t *testing.T) {
+		storeID, _ := setup(t)
+
+		err := datastore.Write(ctx, storeID, nil, []*openfgav1.TupleKey{bob},
+			storage.WithPreconditions(storage.WritePreconditions{
+				TuplesExist: []*openfgav1.TupleKeyWithoutCondition{withoutCondition(tuple.NewTupleKey("role:admin", "assignee", "user:dave"))},
+			}))
+		require.ErrorIs(t, err, storage.ErrWritePreconditionFailed)
+		require.ErrorContains(t, err, "tuple does not exist: user: 'user:dave'")
+		requireChangelogSize(t, storeID, 2)
+	})
+
+	t.Run("tuple_exists", func(t *testing.T) {
+		storeID, _ := setup(t)
+
+		err := datastore.Write(ctx, storeID, nil, []*openfgav1.TupleKey{bob},
+			storage.WithPreconditions(storage.WritePreconditions{
+				TuplesNotExist: []*openfgav1.TupleKeyWithoutCondition{withoutCondition(anne)},
+			}))
+		require.ErrorIs(t, err, storage.ErrWritePreconditionFailed)
+		require.ErrorContains(t, err, "tuple exists: user: 'user:anne'")
+		requireChangelogSize(t, storeID, 2)
+	})
+
+	t.Run("latest_change_differs", func(t *testing.T) {
+		storeID, latest := setup(t)
+
+		err := datastore.Write(ctx, storeID, nil, []*openfgav1.TupleKey{bob})
+		require.NoError(t, err)
+
+		err = datastore.Write(ctx, storeID, []*openfgav1.TupleKeyWithoutCondition{withoutCondition(anne)}, nil,
+			storage.WithPreconditions(storage.WritePreconditions{LatestChangeULID: latest}))
+		require.ErrorIs(t, err, storage.ErrWritePreconditionFailed)
+		requireChangelogSize(t, storeID, 3)
+	})
+
+	t.Run("latest_change_of_empty_store", func(t *testing.T) {
+		err := datastore.Write(ctx, ulid.Make().String(), nil, []*openfgav1.TupleKey{bob},
+			storage.WithPreconditions(storage.WritePreconditions{LatestChangeULID: ulid.Make().String()}))
+		require.ErrorIs(t, err, storage.ErrWritePreconditionFailed)
+	})
+
+	t.Run("concurrent_writes_with_the_same_latest_change", func(t *testing.T) {
+		storeID, latest := setup(t)
+
+		const writers = 5
+		errs := make([]error, writers)
+		var wg sync.WaitGroup
+		for i := range writers {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				errs[i] = datastore.Write(ctx, storeID, nil, []*openfgav1.TupleKey{
+					tuple.NewTupleKey("role:admin", "assignee", "user:"+ulid.Make().String()),
+				}, storage.WithPreconditions(storage.WritePreconditions{LatestChangeULID: latest}))
+			}()
+		}
+		wg.Wait()
+
+		var succeeded int
+		for _, err := range errs {
+			if err == nil {
+				succeeded++
+			}
+		}
+		require.Equal(t, 1, succeeded)
+		requireChangelogSize(t, storeID, 3)
+	})
+
+	t.Run("concurrent_writes_of_tuples_that_must_not_exist", func(t *testing.T) {
+		storeID, _ := setup(t)
+
+		// Each writer writes its own tuple provided that none of the tuples of the others exist, so that at
+		// most one of them can succeed.
+		const writers = 5
+		tuples := make([]*openfgav1.TupleKeyWithoutCondition, writers)
+		for i := range tuples {
+			tuples[i] = withoutCondition(tuple.NewTupleKey("role:admin", "assignee", "user:"+ulid.Make().String()))
+		}
+		errs := make([]error, writers)
+		var wg sync.WaitGroup
+		for i := range writers {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				others := make([]*openfgav1.TupleKeyWithoutCondition, 0, writers-1)
+				others = append(others, tuples[:i]...)
+				others = append(others, tuples[i+1:]...)
+				errs[i] = datastore.Write(ctx, storeID, nil, []*openfgav1.TupleKey{tuple.TupleKeyWithoutConditionToTupleKey(tuples[i])},
+					storage.WithPreconditions(storage.WritePreconditions{TuplesNotExist: others}))
+			}()
+		}
+		wg.Wait()
+
+		var succeeded int
+		for _, err := range errs {
+			if err == nil {
+				succeeded++
+			} else {
+				require.ErrorIs(t, err, storage.ErrWritePreconditionFailed)
+			}
+		}
+		require.Equal(t, 1, succeeded)
+		requireChangelogSize(t, storeID, 3)
+	})
+}
@@ -0,0 +1,172 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+	"github.com/stretchr/testify/require"
+
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+
+	"github.com/openfga/openfga/pkg/storage"
+	"github.com/openfga/openfga/pkg/tuple"
+)
+
+func SearchTuplesTest(t *testing.T, datastore storage.OpenFGADatastore) {
+	searchBackend, ok := datastore.(storage.TupleSearchBackend)
+	if !ok {
+		t.Skip("the datastore does not implement storage.TupleSearchBackend")
+	}
+	ctx := context.Background()
+	storeID := ulid.Make().String()
+
+	err := datastore.Write(ctx, storeID, nil, []*openfgav1.TupleKey{
+		tuple.NewTupleKey("document:report-1", "viewer", "user:anne"),
+		tuple.NewTupleKey("document:report-2", "viewer", "group:eng#member"),
+		tuple.NewTupleKey("document:report_x", "viewer", "user:bob"),
+		tuple.NewTupleKey("document:Report-3", "viewer", "user:bob"),
+		tuple.NewTupleKeyWithCondition("document:other", "viewer", "user:anne", "business_hours", nil),
+		tuple.NewTupleKey("folder:report-1", "viewer", "user:annette"),
+		tuple.NewTupleKey("group:eng", "member", "user:anne"),
+	})
+	require.NoError(t, err)
+
+	// The ULIDs of the tuples are generated from the time of the write.
+	time.Sleep(2 * time.Millisecond)
+	boundary := ulid.MustNew(ulid.Timestamp(time.Now()), nil).String()
+	time.Sleep(2 * time.Millisecond)
+
+	err = datastore.Write(ctx, storeID, nil, []*openfgav1.TupleKey{
+		tuple.NewTupleKeyWithCondition("document:report-4", "editor", "user:charlie", "business_hours", nil),
+		tuple.NewTupleKey("document:report-5", "viewer", "user:*"),
+	})
+	require.NoError(t, err)
+
+	err = datastore.Write(ctx, ulid.Make().String(), nil, []*openfgav1.TupleKey{
+		tuple.NewTupleKey("document:report-1", "viewer", "user:anne"),
+	})
+	require.NoError(t, err)
+
+	search := func(t *testing.T, filter storage.SearchTuplesFilter, pageSize int) []string {
+		t.Helper()
+		var keys []string
+		var contToken string
+		for {
+			tuples, next, err := searchBackend.SearchTuples(ctx, storeID, filter, storage.SearchTuplesOptions{
+				Pagination: storage.NewPaginationOptions(int32(pageSize), contToken),
+			})
+			require.NoError(t, err)
+			require.LessOrEqual(t, len(tuples), pageSize)
+			for _, tk := range tuples {
+				keys = append(keys, tuple.TupleKeyToString(tk.GetKey()))
+			}
+			if next == "" {
+				return keys
+			}
+			require.Len(t, tuples, pageSize)
+			contToken = next
+		}
+	}
+
+	tests := []struct {
+		name     string
+		filter   storage.SearchTuplesFilter
+		expected []string
+	}{
+		{
+			name:   "object_id_prefix",
+			filter: storage.SearchTuplesFilter{ObjectType: "document", ObjectIDPrefix: "report-"},
+			expected: []string{
+				"document:report-1#viewer@user:anne",
+				"document:report-2#viewer@group:eng#member",
+				"document:report-4#editor@user:charlie",
+				"document:report-5#viewer@user:*",
+			},
+		},
+		{
+			name:     "object_id_prefix_with_wildcard_characters",
+			filter:   storage.SearchTuplesFilter{ObjectType: "document", ObjectIDPrefix: "report_"},
+			expected: []string{"document:report_x#viewer@user:bob"},
+		},
+		{
+			name:   "object_id_prefix_without_object_type",
+			filter: storage.SearchTuplesFilter{ObjectIDPrefix: "report-1", Relation: "viewer"},
+			expected: []string{
+				"document:report-1#viewer@user:anne",
+				"folder:report-1#viewer@user:annette",
+			},
+		},
+		{
+			name:     "user_type",
+			filter:   storage.SearchTuplesFilter{ObjectType: "document", UserType: "group"},
+			expected: []string{"document:report-2#viewer@group:eng#member"},
+		},
+		{
+			name:   "user_prefix",
+			filter: storage.SearchTuplesFilter{UserPrefix: "user:ann"},
+			expected: []string{
+				"document:report-1#viewer@user:anne",
+				"document:other#viewer@user:anne",
+				"folder:report-1#viewer@user:annette",
+				"group:eng#member@user:anne",
+			},
+		},
+		{
+			name:     "userset_prefix",
+			filter:   storage.SearchTuplesFilter{UserPrefix: "group:eng#"},
+			expected: []string{"document:report-2#viewer@group:eng#member"},
+		},
+		{
+			name:     "user_type_prefix",
+			filter:   storage.SearchTuplesFilter{UserPrefix: "gr"},
+			expected: []string{"document:report-2#viewer@group:eng#member"},
+		},
+		{
+			name:   "condition_name",
+			filter: storage.SearchTuplesFilter{ConditionName: "business_hours"},
+			expected: []string{
+				"document:other#viewer@user:anne",
+				"document:report-4#editor@user:charlie",
+			},
+		},
+		{
+			name:   "from_ulid",
+			filter: storage.SearchTuplesFilter{FromULID: boundary},
+			expected: []string{
+				"document:report-4#editor@user:charlie",
+				"document:report-5#viewer@user:*",
+			},
+		},
+		{
+			name:     "to_ulid",
+			filter:   storage.SearchTuplesFilter{ObjectType: "document", ObjectIDPrefix: "report-", ToULID: boundary},
+			expected: []string{"document:report-1#viewer@user:anne", "document:report-2#viewer@group:eng#member"},
+		},
+		{
+			name:     "no_match",
+			filter:   storage.SearchTuplesFilter{ObjectType: "document", ConditionName: "unknown"},
+			expected: nil,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.ElementsMatch(t, test.expected, search(t, test.filter, 100))
+		})
+	}
+
+	t.Run("pages_in_ulid_order", func(t *testing.T) {
+		all := search(t, storage.SearchTuplesFilter{}, 100)
+		require.Len(t, all, 9)
+		require.Equal(t, all, search(t, storage.SearchTuplesFilter{}, 2))
+		require.Equal(t, all, search(t, storage.SearchTuplesFilter{}, 1))
+
+		// The tuples written in the second write come last.
+		require.ElementsMatch(t, []string{
+			"document:report-4#editor@user:charlie",
+			"document:report-5#viewer@user:*",
+		}, all[7:])
+	})
+}
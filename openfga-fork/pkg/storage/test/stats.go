@@ -0,0 +1,82 @@
+package test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/oklog/ulid/v2"
+	"github.com/stretchr/testify/require"
+
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+
+	"github.com/openfga/openfga/pkg/storage"
+	"github.com/openfga/openfga/pkg/tuple"
+	"github.com/openfga/openfga/pkg/typesystem"
+)
+
+func ReadStoreStatsTest(t *testing.T, datastore storage.OpenFGADatastore) {
+	statsBackend, ok := datastore.(storage.StoreStatsBackend)
+	if !ok {
+		t.Skip("the datastore does not implement storage.StoreStatsBackend")
+	}
+	ctx := context.Background()
+
+	t.Run("empty_store", func(t *testing.T) {
+		stats, err := statsBackend.ReadStoreStats(ctx, ulid.Make().String())
+		require.NoError(t, err)
+		require.Equal(t, &storage.StoreStats{}, stats)
+	})
+
+	t.Run("counts_tuples_changes_and_models", func(t *testing.T) {
+		storeID := ulid.Make().String()
+
+		for _, types := range [][]string{{"user", "document"}, {"user", "group", "document"}} {
+			model := &openfgav1.AuthorizationModel{
+				Id:            ulid.Make().String(),
+				SchemaVersion: typesystem.SchemaVersion1_1,
+			}
+			for _, typeName := range types {
+				model.TypeDefinitions = append(model.TypeDefinitions, &openfgav1.TypeDefinition{Type: typeName})
+			}
+			err := datastore.WriteAuthorizationModel(ctx, storeID, model)
+			require.NoError(t, err)
+		}
+
+		err := datastore.Write(ctx, storeID, nil, []*openfgav1.TupleKey{
+			tuple.NewTupleKey("document:1", "viewer", "user:anne"),
+			tuple.NewTupleKey("document:2", "viewer", "user:anne"),
+			tuple.NewTupleKey("document:2", "viewer", "user:*"),
+			tuple.NewTupleKey("document:1", "viewer", "group:eng#member"),
+			tuple.NewTupleKey("document:1", "editor", "user:bob"),
+			tuple.NewTupleKey("group:eng", "member", "user:bob"),
+		})
+		require.NoError(t, err)
+		err = datastore.Write(ctx, storeID, []*openfgav1.TupleKeyWithoutCondition{
+			tuple.TupleKeyToTupleKeyWithoutCondition(tuple.NewTupleKey("document:1", "editor", "user:bob")),
+		}, nil)
+		require.NoError(t, err)
+
+		err = datastore.Write(ctx, ulid.Make().String(), nil, []*openfgav1.TupleKey{
+			tuple.NewTupleKey("document:1", "viewer", "user:anne"),
+		})
+		require.NoError(t, err)
+
+		stats, err := statsBackend.ReadStoreStats(ctx, storeID)
+		require.NoError(t, err)
+		require.Equal(t, []storage.TupleCount{
+			{ObjectType: "document", Relation: "viewer", UserType: "group", Count: 1},
+			{ObjectType: "document", Relation: "viewer", UserType: "user", Count: 3},
+			{ObjectType: "group", Relation: "member", UserType: "user", Count: 1},
+		}, stats.TupleCounts)
+		require.Equal(t, int64(7), stats.ChangelogCount)
+		require.Equal(t, int64(2), stats.AuthorizationModelCount)
+
+		// The continuation token of a descending read starts at the latest change.
+		_, latest, err := datastore.ReadChanges(ctx, storeID, storage.ReadChangesFilter{}, storage.ReadChangesOptions{
+			Pagination: storage.NewPaginationOptions(1, ""),
+			SortDesc:   true,
+		})
+		require.NoError(t, err)
+		require.Equal(t, latest, stats.LatestChangeULID)
+	})
+}
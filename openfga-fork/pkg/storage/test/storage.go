@@ -38,11 +38,16 @@ func RunAllTests(t *testing.T, ds storage.OpenFGADatastore) {
 	t.Run("TestReadChanges", func(t *testing.T) { ReadChangesTest(t, ds) })
 	t.Run("TestReadStartingWithUser", func(t *testing.T) { ReadStartingWithUserTest(t, ds) })
 	t.Run("TestReadAndReadPages", func(t *testing.T) { ReadAndReadPageTest(t, ds) })
+	t.Run("TestSearchTuples", func(t *testing.T) { SearchTuplesTest(t, ds) })
+	t.Run("TestReadStoreStats", func(t *testing.T) { ReadStoreStatsTest(t, ds) })
+	t.Run("TestWritePreconditions", func(t *testing.T) { WritePreconditionsTest(t, ds) })
+	t.Run("TestLargeWrite", func(t *testing.T) { LargeWriteTest(t, ds) })
 
 	// Authorization models.
 	t.Run("TestWriteAndReadAuthorizationModel", func(t *testing.T) { WriteAndReadAuthorizationModelTest(t, ds) })
 	t.Run("TestReadAuthorizationModels", func(t *testing.T) { ReadAuthorizationModelsTest(t, ds) })
 	t.Run("TestFindLatestAuthorizationModel", func(t *testing.T) { FindLatestAuthorizationModelTest(t, ds) })
+	t.Run("TestActiveAuthorizationModel", func(t *testing.T) { ActiveAuthorizationModelTest(t, ds) })
 
 	// Assertions.
 	t.Run("TestWriteAndReadAssertions", func(t *testing.T) { AssertionsTest(t, ds) })
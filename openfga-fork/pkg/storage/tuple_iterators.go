@@ -497,7 +497,11 @@ IterateOverPending:
 			// If on every call to Head() we discarded, we would need to iterate twice over pending:
 			// one time to find the minIdx, and one time to move the corresponding iterators.
 			for c.mapper(head) == c.mapper(c.lastYielded) {
-				head, err = iter.Next(ctx)
+				// Next returns the duplicate, the following value is the new head.
+				_, err = iter.Next(ctx)
+				if err == nil {
+					head, err = iter.Head(ctx)
+				}
 				if err != nil {
 					if errors.Is(err, ErrIteratorDone) {
 						iter.Stop()
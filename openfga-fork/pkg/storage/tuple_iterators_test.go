@@ -375,6 +375,23 @@ func TestOrderedCombinedIterator(t *testing.T) {
 					{Key: tuple.NewTupleKey("document:1", "2", "user:b")},
 				},
 			},
+			`removes_duplicates_across_iterators_keeps_following_entry`: {
+				iter1: NewStaticTupleIterator([]*openfgav1.Tuple{
+					{Key: tuple.NewTupleKey("document:1", "2", "user:b")},
+				}),
+				iter2: NewStaticTupleIterator([]*openfgav1.Tuple{
+					{Key: tuple.NewTupleKey("document:1", "2", "user:a")},
+					{Key: tuple.NewTupleKey("document:1", "2", "user:b")},
+					{Key: tuple.NewTupleKey("document:1", "2", "user:c")},
+					{Key: tuple.NewTupleKey("document:1", "2", "user:d")},
+				}),
+				expected: []*openfgav1.Tuple{
+					{Key: tuple.NewTupleKey("document:1", "2", "user:a")},
+					{Key: tuple.NewTupleKey("document:1", "2", "user:b")},
+					{Key: tuple.NewTupleKey("document:1", "2", "user:c")},
+					{Key: tuple.NewTupleKey("document:1", "2", "user:d")},
+				},
+			},
 			`non_overlapping_elements_returns_all`: {
 				iter1: NewStaticTupleIterator([]*openfgav1.Tuple{
 					{Key: tuple.NewTupleKey("document:1", "2", "user:a")},
@@ -0,0 +1,130 @@
+package telemetry
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	otelprometheus "go.opentelemetry.io/contrib/bridges/prometheus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/resource"
+)
+
+const (
+	// MetricsProtocolGRPC exports the metrics with OTLP over gRPC.
+	MetricsProtocolGRPC = "grpc"
+	// MetricsProtocolHTTP exports the metrics with OTLP over HTTP, using protobuf payloads.
+	MetricsProtocolHTTP = "http"
+)
+
+type MeterOption func(m *customMeter)
+
+// WithMetricsOTLPEndpoint sets the host:port of the collector the metrics are exported to.
+func WithMetricsOTLPEndpoint(endpoint string) MeterOption {
+	return func(m *customMeter) {
+		m.endpoint = endpoint
+	}
+}
+
+// WithMetricsOTLPProtocol sets the protocol used to export the metrics, MetricsProtocolGRPC or MetricsProtocolHTTP.
+func WithMetricsOTLPProtocol(protocol string) MeterOption {
+	return func(m *customMeter) {
+		m.protocol = protocol
+	}
+}
+
+// WithMetricsOTLPInsecure disables TLS for the connection to the collector.
+func WithMetricsOTLPInsecure() MeterOption {
+	return func(m *customMeter) {
+		m.insecure = true
+	}
+}
+
+// WithMetricsExportInterval sets how often the metrics are exported.
+func WithMetricsExportInterval(interval time.Duration) MeterOption {
+	return func(m *customMeter) {
+		m.interval = interval
+	}
+}
+
+// WithMetricsAttributes sets the resource attributes of the exported metrics.
+func WithMetricsAttributes(attrs ...attribute.KeyValue) MeterOption {
+	return func(m *customMeter) {
+		m.attributes = attrs
+	}
+}
+
+// WithMetricsGatherer sets the Prometheus gatherer the metrics are read from. The default is
+// prometheus.DefaultGatherer, where the metrics of the server are registered.
+func WithMetricsGatherer(gatherer prometheus.Gatherer) MeterOption {
+	return func(m *customMeter) {
+		m.gatherer = gatherer
+	}
+}
+
+type customMeter struct {
+	endpoint   string
+	protocol   string
+	insecure   bool
+	interval   time.Duration
+	attributes []attribute.KeyValue
+	gatherer   prometheus.Gatherer
+}
+
+// NewMeterProvider returns a MeterProvider that periodically pushes the metrics registered with Prometheus to
+// an OTLP collector, so that they can be collected where scraping the '/metrics' endpoint is not possible.
+// The metrics keep their Prometheus names. The provider must be shut down to export the last metrics.
+func NewMeterProvider(ctx context.Context, opts ...MeterOption) (*sdkmetric.MeterProvider, error) {
+	meter := &customMeter{
+		protocol:   MetricsProtocolGRPC,
+		interval:   time.Minute,
+		attributes: []attribute.KeyValue{},
+		gatherer:   prometheus.DefaultGatherer,
+	}
+
+	for _, opt := range opts {
+		opt(meter)
+	}
+
+	res, err := resource.Merge(
+		resource.Default(),
+		resource.NewSchemaless(meter.attributes...))
+	if err != nil {
+		return nil, err
+	}
+
+	var exp sdkmetric.Exporter
+	switch meter.protocol {
+	case MetricsProtocolGRPC:
+		options := []otlpmetricgrpc.Option{otlpmetricgrpc.WithEndpoint(meter.endpoint)}
+		if meter.insecure {
+			options = append(options, otlpmetricgrpc.WithInsecure())
+		}
+		exp, err = otlpmetricgrpc.New(ctx, options...)
+	case MetricsProtocolHTTP:
+		options := []otlpmetrichttp.Option{otlpmetrichttp.WithEndpoint(meter.endpoint)}
+		if meter.insecure {
+			options = append(options, otlpmetrichttp.WithInsecure())
+		}
+		exp, err = otlpmetrichttp.New(ctx, options...)
+	default:
+		return nil, fmt.Errorf("unknown otlp metrics protocol '%s'", meter.protocol)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the otlp metrics exporter: %w", err)
+	}
+
+	reader := sdkmetric.NewPeriodicReader(exp,
+		sdkmetric.WithInterval(meter.interval),
+		sdkmetric.WithProducer(otelprometheus.NewMetricProducer(otelprometheus.WithGatherer(meter.gatherer))),
+	)
+
+	return sdkmetric.NewMeterProvider(
+		sdkmetric.WithResource(res),
+		sdkmetric.WithReader(reader),
+	), nil
+}
@@ -0,0 +1,157 @@
+package telemetry
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	collectormetricspb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	metricspb "go.opentelemetry.io/proto/otlp/metrics/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+)
+
+// collector is a stand-in for an OTLP collector that keeps the metrics it receives.
+type collector struct {
+	collectormetricspb.UnimplementedMetricsServiceServer
+
+	mu       sync.Mutex
+	requests []*collectormetricspb.ExportMetricsServiceRequest
+}
+
+func (c *collector) Export(_ context.Context, req *collectormetricspb.ExportMetricsServiceRequest) (*collectormetricspb.ExportMetricsServiceResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.requests = append(c.requests, req)
+	return &collectormetricspb.ExportMetricsServiceResponse{}, nil
+}
+
+func (c *collector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	req := &collectormetricspb.ExportMetricsServiceRequest{}
+	if err := proto.Unmarshal(body, req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	_, _ = c.Export(r.Context(), req)
+	w.Header().Set("Content-Type", "application/x-protobuf")
+	w.WriteHeader(http.StatusOK)
+}
+
+// resourceMetrics returns the resource attributes and the metrics, by name, of the requests received.
+func (c *collector) resourceMetrics() (map[string]string, map[string]*metricspb.Metric) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	attributes := map[string]string{}
+	metrics := map[string]*metricspb.Metric{}
+	for _, req := range c.requests {
+		for _, rm := range req.GetResourceMetrics() {
+			for _, attr := range rm.GetResource().GetAttributes() {
+				attributes[attr.GetKey()] = attr.GetValue().GetStringValue()
+			}
+			for _, sm := range rm.GetScopeMetrics() {
+				for _, metric := range sm.GetMetrics() {
+					metrics[metric.GetName()] = metric
+				}
+			}
+		}
+	}
+	return attributes, metrics
+}
+
+func newTestRegistry(t *testing.T) *prometheus.Registry {
+	t.Helper()
+
+	registry := prometheus.NewRegistry()
+	histogram := prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "openfga",
+		Name:      "dispatch_count",
+		Buckets:   []float64{1, 5, 20},
+	}, []string{"grpc_service", "grpc_method"})
+	registry.MustRegister(histogram)
+	histogram.WithLabelValues("openfga.v1.OpenFGAService", "check").Observe(3)
+
+	return registry
+}
+
+func requireExportedMetrics(t *testing.T, c *collector) {
+	t.Helper()
+
+	attributes, metrics := c.resourceMetrics()
+	require.Equal(t, "openfga-test", attributes["service.name"])
+
+	require.Contains(t, metrics, "openfga_dispatch_count")
+	points := metrics["openfga_dispatch_count"].GetHistogram().GetDataPoints()
+	require.Len(t, points, 1)
+	require.Equal(t, uint64(1), points[0].GetCount())
+	require.InDelta(t, 3, points[0].GetSum(), 0)
+}
+
+func TestNewMeterProvider(t *testing.T) {
+	t.Run("grpc", func(t *testing.T) {
+		c := &collector{}
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+
+		grpcServer := grpc.NewServer()
+		collectormetricspb.RegisterMetricsServiceServer(grpcServer, c)
+		go func() {
+			_ = grpcServer.Serve(listener)
+		}()
+		t.Cleanup(grpcServer.Stop)
+
+		mp, err := NewMeterProvider(context.Background(),
+			WithMetricsOTLPEndpoint(listener.Addr().String()),
+			WithMetricsOTLPProtocol(MetricsProtocolGRPC),
+			WithMetricsOTLPInsecure(),
+			WithMetricsAttributes(attribute.String("service.name", "openfga-test")),
+			WithMetricsGatherer(newTestRegistry(t)),
+		)
+		require.NoError(t, err)
+
+		require.NoError(t, mp.ForceFlush(context.Background()))
+		require.NoError(t, mp.Shutdown(context.Background()))
+
+		requireExportedMetrics(t, c)
+	})
+
+	t.Run("http", func(t *testing.T) {
+		c := &collector{}
+		server := httptest.NewServer(c)
+		t.Cleanup(server.Close)
+
+		mp, err := NewMeterProvider(context.Background(),
+			WithMetricsOTLPEndpoint(strings.TrimPrefix(server.URL, "http://")),
+			WithMetricsOTLPProtocol(MetricsProtocolHTTP),
+			WithMetricsOTLPInsecure(),
+			WithMetricsAttributes(attribute.String("service.name", "openfga-test")),
+			WithMetricsGatherer(newTestRegistry(t)),
+		)
+		require.NoError(t, err)
+
+		require.NoError(t, mp.ForceFlush(context.Background()))
+		require.NoError(t, mp.Shutdown(context.Background()))
+
+		requireExportedMetrics(t, c)
+	})
+
+	t.Run("unknown_protocol", func(t *testing.T) {
+		_, err := NewMeterProvider(context.Background(), WithMetricsOTLPProtocol("udp"))
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,469 @@
+package telemetry
+
+import (
+	"cmp"
+	"container/heap"
+	"context"
+	"maps"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// StoreIDLabel is the label of the metrics that holds the store ID of the request.
+	StoreIDLabel = "store_id"
+	// ModelIDLabel is the label of the metrics that holds the authorization model ID of the request.
+	ModelIDLabel = "model_id"
+
+	// OtherLabelValue replaces the store and model IDs that are over the cardinality limit.
+	OtherLabelValue = "other"
+)
+
+// storeLabeler is the StoreLabeler used by StoreLabel and ModelLabel. If nil, the labels are empty.
+var storeLabeler atomic.Pointer[StoreLabeler]
+
+// SetStoreLabeler sets the StoreLabeler of the store and model labels of the metrics of the process. If
+// nil, the labels are empty, which Prometheus treats as if the labels were missing.
+func SetStoreLabeler(labeler *StoreLabeler) {
+	storeLabeler.Store(labeler)
+}
+
+// StoreLabel returns the value of the store_id label of storeID, see [StoreLabeler.StoreLabel].
+func StoreLabel(storeID string) string {
+	labeler := storeLabeler.Load()
+	if labeler == nil {
+		return ""
+	}
+	return labeler.StoreLabel(storeID)
+}
+
+// ModelLabel returns the value of the model_id label of modelID in storeID, see [StoreLabeler.ModelLabel].
+func ModelLabel(storeID, modelID string) string {
+	labeler := storeLabeler.Load()
+	if labeler == nil {
+		return ""
+	}
+	return labeler.ModelLabel(storeID, modelID)
+}
+
+// storeLabeledMetrics are the metrics whose series are deleted when their store or model is not labeled with
+// its ID anymore.
+var storeLabeledMetrics struct {
+	mu      sync.Mutex
+	metrics []storeLabeledMetric
+}
+
+type storeLabeledMetric interface {
+	DeletePartialMatch(labels prometheus.Labels) int
+}
+
+// StoreLabeled registers metric as labeled with StoreIDLabel or ModelIDLabel, so that the series of a store or
+// a model are deleted once it is folded into OtherLabelValue, and returns it.
+func StoreLabeled[M storeLabeledMetric](metric M) M {
+	storeLabeledMetrics.mu.Lock()
+	defer storeLabeledMetrics.mu.Unlock()
+
+	storeLabeledMetrics.metrics = append(storeLabeledMetrics.metrics, metric)
+	return metric
+}
+
+// deleteSeries deletes the series of the registered metrics whose label is value.
+func deleteSeries(label, value string) {
+	storeLabeledMetrics.mu.Lock()
+	defer storeLabeledMetrics.mu.Unlock()
+
+	for _, metric := range storeLabeledMetrics.metrics {
+		metric.DeletePartialMatch(prometheus.Labels{label: value})
+	}
+}
+
+type requestLabelsContextKey struct{}
+
+// RequestLabels resolves the store and model labels of the metrics of a request once, the first time they are
+// needed, and counts the request towards the ranking of its store and model.
+type RequestLabels struct {
+	mu      sync.Mutex
+	storeID string
+	store   string
+	modelID string
+	model   string
+	// modelResolved is true once the model label is resolved.
+	modelResolved bool
+}
+
+// ContextWithRequestLabels returns a context holding new RequestLabels, that StoreLabelFromContext and
+// ModelLabelFromContext resolve.
+func ContextWithRequestLabels(ctx context.Context) (context.Context, *RequestLabels) {
+	labels := &RequestLabels{}
+	return context.WithValue(ctx, requestLabelsContextKey{}, labels), labels
+}
+
+// StoreLabel returns the store label of the request, counting the request of storeID if it is the first
+// store of the request. The label of another store is looked up without counting it.
+func (r *RequestLabels) StoreLabel(storeID string) string {
+	labeler := storeLabeler.Load()
+	if labeler == nil || storeID == "" {
+		return ""
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.storeID == "" {
+		r.storeID, r.store = storeID, labeler.countStore(storeID)
+	}
+	if storeID != r.storeID {
+		return labeler.StoreLabel(storeID)
+	}
+	return r.store
+}
+
+// ModelLabel returns the model label of the request, counting the request of modelID if it is the first
+// model of the request. The label of another model is looked up without counting it.
+func (r *RequestLabels) ModelLabel(storeID, modelID string) string {
+	labeler := storeLabeler.Load()
+	if labeler == nil {
+		return ""
+	}
+	storeLabel := r.StoreLabel(storeID)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.modelResolved && modelID != "" {
+		r.modelID, r.model, r.modelResolved = modelID, labeler.countModel(storeLabel, modelID), true
+	}
+	if modelID != r.modelID {
+		return labeler.ModelLabel(storeID, modelID)
+	}
+	return r.model
+}
+
+// ResolvedModelLabel returns the model label of the request if it has been resolved.
+func (r *RequestLabels) ResolvedModelLabel() (string, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.model, r.modelResolved
+}
+
+// StoreLabelFromContext returns the value of the store_id label of storeID for the request of ctx. Without
+// RequestLabels in ctx, the label is looked up, see [StoreLabel].
+func StoreLabelFromContext(ctx context.Context, storeID string) string {
+	if labels, ok := ctx.Value(requestLabelsContextKey{}).(*RequestLabels); ok {
+		return labels.StoreLabel(storeID)
+	}
+	return StoreLabel(storeID)
+}
+
+// ModelLabelFromContext returns the value of the model_id label of modelID in storeID for the request of ctx.
+// Without RequestLabels in ctx, the label is looked up, see [ModelLabel].
+func ModelLabelFromContext(ctx context.Context, storeID, modelID string) string {
+	if labels, ok := ctx.Value(requestLabelsContextKey{}).(*RequestLabels); ok {
+		return labels.ModelLabel(storeID, modelID)
+	}
+	return ModelLabel(storeID, modelID)
+}
+
+// StoreLabeler bounds the cardinality of the store and model labels of the metrics. The allowed stores are
+// always labeled with their ID. Otherwise, the stores with the most requests are labeled with their ID up to
+// a maximum number, and the rest are folded into OtherLabelValue. The stores are ranked again every ranking
+// interval, so that a store that becomes busy is labeled with its ID from then on, and the series of the
+// stores that are not labeled with their ID anymore are deleted. Model IDs are bounded the same way.
+//
+// Only the requests counted with RequestLabels take part in the ranking. Looking up a label does not lock.
+type StoreLabeler struct {
+	allowedStores map[string]struct{}
+	stores        *rankedLabels
+
+	modelLabels bool
+	models      *rankedLabels
+}
+
+type StoreLabelerOption func(l *StoreLabeler)
+
+// WithAllowedStores sets the stores that are always labeled with their ID. They do not count towards the
+// maximum number of stores.
+func WithAllowedStores(storeIDs ...string) StoreLabelerOption {
+	return func(l *StoreLabeler) {
+		for _, storeID := range storeIDs {
+			l.allowedStores[storeID] = struct{}{}
+		}
+	}
+}
+
+// WithMaxStores sets the number of stores, besides the allowed ones, labeled with their ID.
+func WithMaxStores(maxStores int) StoreLabelerOption {
+	return func(l *StoreLabeler) {
+		l.stores.limit = maxStores
+	}
+}
+
+// WithModelLabels labels the metrics with the authorization model ID of up to maxModels models. By
+// default, the model label is empty.
+func WithModelLabels(maxModels int) StoreLabelerOption {
+	return func(l *StoreLabeler) {
+		l.modelLabels = true
+		l.models.limit = maxModels
+	}
+}
+
+// WithRankingInterval sets how often the stores and models labeled with their ID are chosen again among
+// those with the most requests. The default is one minute.
+func WithRankingInterval(interval time.Duration) StoreLabelerOption {
+	return func(l *StoreLabeler) {
+		l.stores.interval = interval
+		l.models.interval = interval
+	}
+}
+
+// NewStoreLabeler returns a StoreLabeler. Without options, all the stores are folded into OtherLabelValue.
+func NewStoreLabeler(opts ...StoreLabelerOption) *StoreLabeler {
+	l := &StoreLabeler{
+		allowedStores: map[string]struct{}{},
+		stores:        newRankedLabels(StoreIDLabel),
+		models:        newRankedLabels(ModelIDLabel),
+	}
+
+	for _, opt := range opts {
+		opt(l)
+	}
+
+	return l
+}
+
+// StoreLabel returns storeID if it is allowed or among the stores with the most requests, and
+// OtherLabelValue otherwise. An empty store ID, e.g. of ListStores, stays empty.
+func (l *StoreLabeler) StoreLabel(storeID string) string {
+	return l.storeLabel(storeID, l.stores.lookup)
+}
+
+// countStore counts a request of storeID and returns its store label.
+func (l *StoreLabeler) countStore(storeID string) string {
+	return l.storeLabel(storeID, l.stores.count)
+}
+
+func (l *StoreLabeler) storeLabel(storeID string, admit func(string) bool) string {
+	if storeID == "" {
+		return ""
+	}
+	if _, ok := l.allowedStores[storeID]; ok {
+		return storeID
+	}
+	if admit(storeID) {
+		return storeID
+	}
+	return OtherLabelValue
+}
+
+// ModelLabel returns modelID if model labels are enabled, the store is labeled with its ID and the model is
+// among the models with the most requests. It returns OtherLabelValue for the models of the stores folded
+// into OtherLabelValue and for the models over the limit, and an empty label if model labels are disabled or
+// the model ID is empty, e.g. when the latest model of the store is used.
+func (l *StoreLabeler) ModelLabel(storeID, modelID string) string {
+	return l.modelLabel(l.StoreLabel(storeID), modelID, l.models.lookup)
+}
+
+// countModel counts a request of modelID, in the store labeled storeLabel, and returns its model label.
+func (l *StoreLabeler) countModel(storeLabel, modelID string) string {
+	return l.modelLabel(storeLabel, modelID, l.models.count)
+}
+
+func (l *StoreLabeler) modelLabel(storeLabel, modelID string, admit func(string) bool) string {
+	if !l.modelLabels || modelID == "" {
+		return ""
+	}
+	if storeLabel == OtherLabelValue {
+		return OtherLabelValue
+	}
+	if admit(modelID) {
+		return modelID
+	}
+	return OtherLabelValue
+}
+
+// rankedLabelsSketchFactor is the number of values counted per labeled value. The counts of the values with
+// more than 1/(rankedLabelsSketchFactor*limit) of the requests are always tracked.
+const rankedLabelsSketchFactor = 10
+
+// rankedLabels labels up to limit values with the most requests. The requests of each value are counted with
+// a space-saving sketch, and every interval, the labeled values are replaced by the top values of the sketch,
+// whose counts are then halved so that the ranking follows the recent requests. The series of the values
+// that are not labeled anymore are deleted. Between two rankings, the values that are not labeled are
+// labeled while fewer than limit are, e.g. the first values seen.
+type rankedLabels struct {
+	// label is the label of the metrics that holds the values.
+	label    string
+	limit    int
+	interval time.Duration
+	now      func() time.Time
+
+	// labeled is replaced, never modified, so that it is read without locking.
+	labeled atomic.Pointer[map[string]struct{}]
+
+	mu     sync.Mutex
+	ranked time.Time
+	sketch *spaceSaving
+}
+
+func newRankedLabels(label string) *rankedLabels {
+	r := &rankedLabels{
+		label:    label,
+		interval: time.Minute,
+		now:      time.Now,
+	}
+	r.labeled.Store(&map[string]struct{}{})
+	return r
+}
+
+// lookup reports whether value is labeled, labeling it if fewer than limit values are.
+func (r *rankedLabels) lookup(value string) bool {
+	labeled := *r.labeled.Load()
+	if _, ok := labeled[value]; ok {
+		return true
+	}
+	if len(labeled) >= r.limit {
+		return false
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	labeled = *r.labeled.Load()
+	if _, ok := labeled[value]; ok {
+		return true
+	}
+	if len(labeled) >= r.limit {
+		return false
+	}
+	added := maps.Clone(labeled)
+	added[value] = struct{}{}
+	r.labeled.Store(&added)
+	return true
+}
+
+// count counts a request of value, ranks the values again if the interval has elapsed, and reports whether
+// value is labeled.
+func (r *rankedLabels) count(value string) bool {
+	if r.limit <= 0 {
+		return false
+	}
+
+	r.mu.Lock()
+	if r.sketch == nil {
+		r.sketch = newSpaceSaving(rankedLabelsSketchFactor * r.limit)
+		r.ranked = r.now()
+	}
+	r.sketch.add(value)
+
+	var dropped []string
+	if now := r.now(); now.Sub(r.ranked) >= r.interval {
+		top := r.sketch.top(r.limit)
+		for previous := range *r.labeled.Load() {
+			if _, ok := top[previous]; !ok {
+				dropped = append(dropped, previous)
+			}
+		}
+		r.labeled.Store(&top)
+		r.sketch.halve()
+		r.ranked = now
+	}
+	r.mu.Unlock()
+
+	for _, value := range dropped {
+		deleteSeries(r.label, value)
+	}
+
+	return r.lookup(value)
+}
+
+// spaceSaving counts the occurrences of the most frequent values with the space-saving algorithm. It holds at
+// most capacity values: a new value replaces the least counted one and inherits its count, so that counts are
+// overestimated by at most the count of the replaced value.
+type spaceSaving struct {
+	capacity int
+	counts   map[string]*valueCount
+	heap     valueCountHeap
+}
+
+type valueCount struct {
+	value string
+	count uint64
+	index int
+}
+
+func newSpaceSaving(capacity int) *spaceSaving {
+	return &spaceSaving{capacity: capacity, counts: make(map[string]*valueCount, capacity)}
+}
+
+func (s *spaceSaving) add(value string) {
+	if c, ok := s.counts[value]; ok {
+		c.count++
+		heap.Fix(&s.heap, c.index)
+		return
+	}
+
+	if len(s.heap) < s.capacity {
+		c := &valueCount{value: value, count: 1}
+		s.counts[value] = c
+		heap.Push(&s.heap, c)
+		return
+	}
+
+	c := s.heap[0]
+	delete(s.counts, c.value)
+	c.value = value
+	c.count++
+	s.counts[value] = c
+	heap.Fix(&s.heap, 0)
+}
+
+// top returns the n values with the highest counts.
+func (s *spaceSaving) top(n int) map[string]struct{} {
+	sorted := slices.Clone(s.heap)
+	slices.SortFunc(sorted, func(a, b *valueCount) int {
+		return cmp.Compare(b.count, a.count)
+	})
+
+	top := make(map[string]struct{}, n)
+	for _, c := range sorted[:min(n, len(sorted))] {
+		top[c.value] = struct{}{}
+	}
+	return top
+}
+
+// halve halves all the counts, which keeps the order of the heap.
+func (s *spaceSaving) halve() {
+	for _, c := range s.heap {
+		c.count /= 2
+	}
+}
+
+// valueCountHeap is a min-heap of counts, see [heap.Interface].
+type valueCountHeap []*valueCount
+
+func (h valueCountHeap) Len() int           { return len(h) }
+func (h valueCountHeap) Less(i, j int) bool { return h[i].count < h[j].count }
+
+func (h valueCountHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *valueCountHeap) Push(x any) {
+	c := x.(*valueCount)
+	c.index = len(*h)
+	*h = append(*h, c)
+}
+
+func (h *valueCountHeap) Pop() any {
+	old := *h
+	c := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return c
+}
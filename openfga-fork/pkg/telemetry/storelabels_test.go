@@ -0,0 +1,169 @@
+package telemetry
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStoreLabeler(t *testing.T) {
+	t.Run("without_labeler", func(t *testing.T) {
+		SetStoreLabeler(nil)
+
+		require.Empty(t, StoreLabel("store"))
+		require.Empty(t, ModelLabel("store", "model"))
+	})
+
+	t.Run("with_labeler", func(t *testing.T) {
+		SetStoreLabeler(NewStoreLabeler(WithMaxStores(1)))
+		t.Cleanup(func() {
+			SetStoreLabeler(nil)
+		})
+
+		require.Equal(t, "store", StoreLabel("store"))
+		require.Equal(t, OtherLabelValue, StoreLabel("another"))
+	})
+
+	t.Run("allowed_stores_do_not_count_towards_the_limit", func(t *testing.T) {
+		l := NewStoreLabeler(WithAllowedStores("allowed1", "allowed2"), WithMaxStores(1))
+
+		require.Equal(t, "allowed1", l.StoreLabel("allowed1"))
+		require.Equal(t, "first", l.StoreLabel("first"))
+		require.Equal(t, "allowed2", l.StoreLabel("allowed2"))
+		require.Equal(t, OtherLabelValue, l.StoreLabel("second"))
+		require.Equal(t, "first", l.StoreLabel("first"))
+	})
+
+	t.Run("no_stores_are_admitted_without_options", func(t *testing.T) {
+		l := NewStoreLabeler()
+
+		require.Equal(t, OtherLabelValue, l.StoreLabel("store"))
+		require.Empty(t, l.StoreLabel(""))
+	})
+
+	t.Run("model_labels_disabled", func(t *testing.T) {
+		l := NewStoreLabeler(WithMaxStores(1))
+
+		require.Empty(t, l.ModelLabel("store", "model"))
+	})
+
+	t.Run("model_labels", func(t *testing.T) {
+		l := NewStoreLabeler(WithMaxStores(1), WithModelLabels(1))
+
+		require.Equal(t, "model1", l.ModelLabel("store", "model1"))
+		require.Equal(t, OtherLabelValue, l.ModelLabel("store", "model2"))
+		require.Equal(t, OtherLabelValue, l.ModelLabel("another", "model1"))
+		require.Empty(t, l.ModelLabel("store", ""))
+	})
+
+	t.Run("busiest_stores_are_labeled_after_the_ranking_interval", func(t *testing.T) {
+		l := NewStoreLabeler(WithMaxStores(1), WithRankingInterval(time.Minute))
+		now := time.Now()
+		l.stores.now = func() time.Time { return now }
+
+		require.Equal(t, "first", l.countStore("first"))
+		for i := 0; i < 10; i++ {
+			require.Equal(t, OtherLabelValue, l.countStore("busy"))
+		}
+
+		now = now.Add(time.Minute)
+		require.Equal(t, "busy", l.countStore("busy"))
+		require.Equal(t, OtherLabelValue, l.StoreLabel("first"))
+
+		// The counts are halved at every ranking, so the store that is busy now takes over.
+		for i := 0; i < 20; i++ {
+			l.countStore("first")
+		}
+		now = now.Add(time.Minute)
+		require.Equal(t, "first", l.countStore("first"))
+		require.Equal(t, OtherLabelValue, l.StoreLabel("busy"))
+	})
+
+	t.Run("series_of_dropped_stores_are_deleted", func(t *testing.T) {
+		l := NewStoreLabeler(WithMaxStores(1), WithRankingInterval(time.Minute))
+		now := time.Now()
+		l.stores.now = func() time.Time { return now }
+
+		counter := StoreLabeled(prometheus.NewCounterVec(prometheus.CounterOpts{Name: "test_store_requests"}, []string{StoreIDLabel}))
+		counter.WithLabelValues(l.countStore("first")).Inc()
+		for i := 0; i < 2; i++ {
+			counter.WithLabelValues(l.countStore("busy")).Inc()
+		}
+		require.Equal(t, 2, testutil.CollectAndCount(counter))
+
+		now = now.Add(time.Minute)
+		counter.WithLabelValues(l.countStore("busy")).Inc()
+		require.Equal(t, 2, testutil.CollectAndCount(counter))
+		require.Zero(t, testutil.ToFloat64(counter.WithLabelValues("first")))
+		require.InDelta(t, 1, testutil.ToFloat64(counter.WithLabelValues("busy")), 0)
+	})
+
+	t.Run("requests_counted_once", func(t *testing.T) {
+		l := NewStoreLabeler(WithMaxStores(1), WithModelLabels(1))
+		SetStoreLabeler(l)
+		t.Cleanup(func() {
+			SetStoreLabeler(nil)
+		})
+
+		ctx, labels := ContextWithRequestLabels(context.Background())
+		for i := 0; i < 3; i++ {
+			require.Equal(t, "store", StoreLabelFromContext(ctx, "store"))
+			require.Equal(t, "model", ModelLabelFromContext(ctx, "store", "model"))
+		}
+		require.Equal(t, OtherLabelValue, StoreLabelFromContext(ctx, "another"))
+		model, ok := labels.ResolvedModelLabel()
+		require.True(t, ok)
+		require.Equal(t, "model", model)
+
+		require.Equal(t, uint64(1), l.stores.sketch.counts["store"].count)
+		require.Equal(t, uint64(1), l.models.sketch.counts["model"].count)
+		require.Nil(t, l.stores.sketch.counts["another"])
+
+		require.Empty(t, StoreLabelFromContext(ctx, ""))
+		require.Equal(t, "store", StoreLabelFromContext(context.Background(), "store"))
+	})
+
+	t.Run("concurrent_stores_respect_the_limit", func(t *testing.T) {
+		l := NewStoreLabeler(WithMaxStores(10))
+
+		var wg sync.WaitGroup
+		for i := 0; i < 100; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				l.StoreLabel(fmt.Sprintf("store%d", i))
+			}()
+		}
+		wg.Wait()
+
+		admitted := 0
+		for i := 0; i < 100; i++ {
+			if l.StoreLabel(fmt.Sprintf("store%d", i)) != OtherLabelValue {
+				admitted++
+			}
+		}
+		require.Equal(t, 10, admitted)
+	})
+}
+
+func TestSpaceSaving(t *testing.T) {
+	s := newSpaceSaving(2)
+	for _, value := range []string{"a", "a", "a", "b", "c", "c", "c", "c"} {
+		s.add(value)
+	}
+
+	// c replaced b, the least counted value, and inherited its count.
+	require.Equal(t, map[string]struct{}{"a": {}, "c": {}}, s.top(2))
+	require.Equal(t, map[string]struct{}{"c": {}}, s.top(1))
+	require.Equal(t, uint64(5), s.counts["c"].count)
+
+	s.halve()
+	require.Equal(t, uint64(2), s.counts["c"].count)
+	require.Equal(t, uint64(1), s.counts["a"].count)
+}
@@ -29,9 +29,12 @@ type TypesystemResolverFunc func(ctx context.Context, storeID, modelID string) (
 // If given a model ID: validates the model ID, and tries to fetch it from the cache.
 // If not found in the cache, fetches from the datastore, validates it, stores in cache, and returns it.
 //
-// If not given a model ID: fetches the latest model ID from the datastore, then sees if the model ID is in the cache.
+// If not given a model ID: fetches the model ID the store is pinned to or, if the store is not pinned,
+// the latest model ID from the datastore, then sees if the model ID is in the cache.
 // If it is, returns it. Else, validates it and returns it.
-func MemoizedTypesystemResolverFunc(datastore storage.AuthorizationModelReadBackend) (TypesystemResolverFunc, func(), error) {
+//
+// The typesystems are created with opts.
+func MemoizedTypesystemResolverFunc(datastore storage.AuthorizationModelReadBackend, opts ...Option) (TypesystemResolverFunc, func(), error) {
 	lookupGroup := singleflight.Group{}
 
 	// cache holds models that have already been validated.
@@ -59,6 +62,13 @@ func MemoizedTypesystemResolverFunc(datastore storage.AuthorizationModelReadBack
 
 		var model *openfgav1.AuthorizationModel
 		var key string
+		if modelID == "" {
+			modelID, err = datastore.ReadActiveAuthorizationModelID(ctx, storeID)
+			if err != nil && !errors.Is(err, storage.ErrNotFound) {
+				return nil, fmt.Errorf("failed to ReadActiveAuthorizationModelID: %w", err)
+			}
+		}
+
 		if modelID == "" {
 			v, err, _ := lookupGroup.Do("FindLatestAuthorizationModel:"+storeID, func() (interface{}, error) {
 				return datastore.FindLatestAuthorizationModel(ctx, storeID)
@@ -96,7 +106,7 @@ func MemoizedTypesystemResolverFunc(datastore storage.AuthorizationModelReadBack
 			model = v.(*openfgav1.AuthorizationModel)
 		}
 
-		typesys, err := NewAndValidate(ctx, model)
+		typesys, err := NewAndValidate(ctx, model, opts...)
 		if err != nil {
 			return nil, fmt.Errorf("%w: %v", ErrInvalidModel, err)
 		}
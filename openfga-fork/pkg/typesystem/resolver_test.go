@@ -43,6 +43,9 @@ func TestMemoizedTypesystemResolverFunc(t *testing.T) {
 		defer mockController.Finish()
 
 		mockDatastore := mockstorage.NewMockAuthorizationModelReadBackend(mockController)
+		mockDatastore.EXPECT().ReadActiveAuthorizationModelID(gomock.Any(), store).
+			Return("", storage.ErrNotFound).
+			AnyTimes()
 		mockDatastore.EXPECT().FindLatestAuthorizationModel(gomock.Any(), store).
 			Return(nil, storage.ErrNotFound).
 			Times(1)
@@ -67,6 +70,9 @@ func TestMemoizedTypesystemResolverFunc(t *testing.T) {
 
 		mockDatastore := mockstorage.NewMockAuthorizationModelReadBackend(mockController)
 
+		mockDatastore.EXPECT().ReadActiveAuthorizationModelID(gomock.Any(), store).
+			Return("", storage.ErrNotFound).
+			AnyTimes()
 		mockDatastore.EXPECT().FindLatestAuthorizationModel(gomock.Any(), store).
 			Return(model, nil).
 			Times(1)
@@ -80,6 +86,54 @@ func TestMemoizedTypesystemResolverFunc(t *testing.T) {
 		require.Equal(t, model.GetId(), typesys.GetAuthorizationModelID())
 	})
 
+	t.Run("empty_model_id_returns_active_model", func(t *testing.T) {
+		store := ulid.Make().String()
+		model := testutils.MustTransformDSLToProtoWithID(`
+			model
+				schema 1.1
+
+			type user`)
+		mockController := gomock.NewController(t)
+		defer mockController.Finish()
+
+		mockDatastore := mockstorage.NewMockAuthorizationModelReadBackend(mockController)
+
+		mockDatastore.EXPECT().ReadActiveAuthorizationModelID(gomock.Any(), store).
+			Return(model.GetId(), nil).
+			Times(1)
+		mockDatastore.EXPECT().ReadAuthorizationModel(gomock.Any(), store, model.GetId()).
+			Return(model, nil).
+			Times(1)
+		mockDatastore.EXPECT().FindLatestAuthorizationModel(gomock.Any(), store).Times(0)
+
+		resolver, resolverStop, err := MemoizedTypesystemResolverFunc(mockDatastore)
+		require.NoError(t, err)
+		defer resolverStop()
+
+		typesys, err := resolver(context.Background(), store, "")
+		require.NoError(t, err)
+		require.Equal(t, model.GetId(), typesys.GetAuthorizationModelID())
+	})
+
+	t.Run("empty_model_id_and_active_model_lookup_fails_returns_error", func(t *testing.T) {
+		store := ulid.Make().String()
+
+		mockController := gomock.NewController(t)
+		defer mockController.Finish()
+
+		mockDatastore := mockstorage.NewMockAuthorizationModelReadBackend(mockController)
+		mockDatastore.EXPECT().ReadActiveAuthorizationModelID(gomock.Any(), store).
+			Return("", context.DeadlineExceeded).
+			Times(1)
+
+		resolver, resolverStop, err := MemoizedTypesystemResolverFunc(mockDatastore)
+		require.NoError(t, err)
+		defer resolverStop()
+
+		_, err = resolver(context.Background(), store, "")
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
 	t.Run("model_not_found", func(t *testing.T) {
 		store := ulid.Make().String()
 		modelID := ulid.Make().String()
@@ -143,6 +197,9 @@ func TestMemoizedTypesystemResolverFunc(t *testing.T) {
 
 		mockDatastore := mockstorage.NewMockAuthorizationModelReadBackend(mockController)
 
+		mockDatastore.EXPECT().ReadActiveAuthorizationModelID(gomock.Any(), store).
+			Return("", storage.ErrNotFound).
+			AnyTimes()
 		mockDatastore.EXPECT().FindLatestAuthorizationModel(gomock.Any(), store).
 			Return(model, nil).
 			Times(1)
@@ -200,6 +257,9 @@ func TestMemoizedTypesystemResolverFunc(t *testing.T) {
 
 		mockDatastore := mockstorage.NewMockAuthorizationModelReadBackend(mockController)
 
+		mockDatastore.EXPECT().ReadActiveAuthorizationModelID(gomock.Any(), store).
+			Return("", storage.ErrNotFound).
+			AnyTimes()
 		mockDatastore.EXPECT().FindLatestAuthorizationModel(gomock.Any(), store).
 			Return(model, nil).
 			Times(2)
@@ -241,6 +301,9 @@ func TestMemoizedTypesystemResolverFunc(t *testing.T) {
 		defer resolverStop()
 
 		// first read returns modelOne
+		mockDatastore.EXPECT().ReadActiveAuthorizationModelID(gomock.Any(), store).
+			Return("", storage.ErrNotFound).
+			AnyTimes()
 		mockDatastore.EXPECT().FindLatestAuthorizationModel(gomock.Any(), store).
 			Return(modelOne, nil).
 			Times(1)
@@ -184,9 +184,30 @@ type TypeSystem struct {
 	authzWeightedGraph      *graph.WeightedAuthorizationModelGraph
 }
 
+// Option configures the *TypeSystem created by New and NewAndValidate.
+type Option func(*options)
+
+type options struct {
+	conditionExtensions bool
+}
+
+// WithConditionExtensions makes the functions of the condition extension library, such as inBusinessHours and
+// regexMatch, available to the conditions of the model if enabled is true. Models whose conditions use them
+// are invalid otherwise.
+func WithConditionExtensions(enabled bool) Option {
+	return func(o *options) {
+		o.conditionExtensions = enabled
+	}
+}
+
 // New creates a *TypeSystem from an *openfgav1.AuthorizationModel.
 // It assumes that the input model is valid. If you need to run validations, use NewAndValidate.
-func New(model *openfgav1.AuthorizationModel) (*TypeSystem, error) {
+func New(model *openfgav1.AuthorizationModel, opts ...Option) (*TypeSystem, error) {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+
 	tds := make(map[string]*openfgav1.TypeDefinition, len(model.GetTypeDefinitions()))
 	relations := make(map[string]map[string]*openfgav1.Relation, len(model.GetTypeDefinitions()))
 	ttuRelations := make(map[string]map[string][]*openfgav1.TupleToUserset, len(model.GetTypeDefinitions()))
@@ -221,6 +242,9 @@ func New(model *openfgav1.AuthorizationModel) (*TypeSystem, error) {
 			WithTrackEvaluationCost().
 			WithMaxEvaluationCost(config.MaxConditionEvaluationCost()).
 			WithInterruptCheckFrequency(config.DefaultInterruptCheckFrequency)
+		if o.conditionExtensions {
+			uncompiledConditions[name].WithExtensions()
+		}
 	}
 	authorizationModelGraph, err := graph.NewAuthorizationModelGraph(model)
 	if err != nil {
@@ -1115,11 +1139,11 @@ func hasEntrypoints(
 //     a) For a type (e.g. user) this means checking that this type is in the *TypeSystem
 //     b) For a type#relation this means checking that this type with this relation is in the *TypeSystem
 //  4. Check that a relation is assignable if and only if it has a non-zero list of types
-func NewAndValidate(ctx context.Context, model *openfgav1.AuthorizationModel) (*TypeSystem, error) {
+func NewAndValidate(ctx context.Context, model *openfgav1.AuthorizationModel, opts ...Option) (*TypeSystem, error) {
 	_, span := tracer.Start(ctx, "typesystem.NewAndValidate")
 	defer span.End()
 
-	t, err := New(model)
+	t, err := New(model, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -5954,6 +5954,52 @@ func TestConditions(t *testing.T) {
 	}
 }
 
+func TestConditionExtensions(t *testing.T) {
+	model := &openfgav1.AuthorizationModel{
+		SchemaVersion: SchemaVersion1_1,
+		TypeDefinitions: []*openfgav1.TypeDefinition{
+			{
+				Type: "user",
+			},
+			{
+				Type: "document",
+				Relations: map[string]*openfgav1.Userset{
+					"viewer": This(),
+				},
+				Metadata: &openfgav1.Metadata{
+					Relations: map[string]*openfgav1.RelationMetadata{
+						"viewer": {
+							DirectlyRelatedUserTypes: []*openfgav1.RelationReference{
+								ConditionedRelationReference(DirectRelationReference("user", ""), "from_office"),
+							},
+						},
+					},
+				},
+			},
+		},
+		Conditions: map[string]*openfgav1.Condition{
+			"from_office": {
+				Name:       "from_office",
+				Expression: `user_ip.inAnyCIDR(["10.0.0.0/8"])`,
+				Parameters: map[string]*openfgav1.ConditionParamTypeRef{
+					"user_ip": {
+						TypeName: openfgav1.ConditionParamTypeRef_TYPE_NAME_IPADDRESS,
+					},
+				},
+			},
+		},
+	}
+
+	_, err := NewAndValidate(context.Background(), model)
+	require.ErrorContains(t, err, "inAnyCIDR")
+
+	_, err = NewAndValidate(context.Background(), model, WithConditionExtensions(false))
+	require.ErrorContains(t, err, "inAnyCIDR")
+
+	_, err = NewAndValidate(context.Background(), model, WithConditionExtensions(true))
+	require.NoError(t, err)
+}
+
 func TestHasTypeInfo(t *testing.T) {
 	tests := []struct {
 		name       string
@@ -252,3 +252,43 @@ func (t *TypeSystem) ConstructUnionUserset(node *graph.WeightedAuthorizationMode
 				Child: usersets,
 			}}}, nil
 }
+
+// RelationDependsOn reports whether evaluating relation `relation` of `objectType` may read the
+// relationship tuples of relation `tupleRelation` of `tupleObjectType`, either because the relation
+// is reachable from `objectType#relation` or because it is the tupleset of a reachable tuple to userset.
+// If the weighted graph could not be built for the model it conservatively returns true.
+func (t *TypeSystem) RelationDependsOn(objectType, relation, tupleObjectType, tupleRelation string) bool {
+	if t.authzWeightedGraph == nil {
+		return true
+	}
+
+	target := tuple.ToObjectRelationString(tupleObjectType, tupleRelation)
+	start, ok := t.authzWeightedGraph.GetNodeByID(tuple.ToObjectRelationString(objectType, relation))
+	if !ok {
+		return false
+	}
+
+	visited := map[string]struct{}{start.GetUniqueLabel(): {}}
+	pending := []*graph.WeightedAuthorizationModelNode{start}
+	for len(pending) > 0 {
+		node := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		if node.GetUniqueLabel() == target {
+			return true
+		}
+
+		edges, _ := t.authzWeightedGraph.GetEdgesFromNode(node)
+		for _, edge := range edges {
+			if edge.GetEdgeType() == graph.TTUEdge && edge.GetTuplesetRelation() == target {
+				return true
+			}
+			if _, ok := visited[edge.GetTo().GetUniqueLabel()]; ok {
+				continue
+			}
+			visited[edge.GetTo().GetUniqueLabel()] = struct{}{}
+			pending = append(pending, edge.GetTo())
+		}
+	}
+
+	return false
+}
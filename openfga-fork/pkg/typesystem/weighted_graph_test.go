@@ -1079,3 +1079,50 @@ func TestConstructUserset(t *testing.T) {
 		require.Error(t, err)
 	})
 }
+
+func TestRelationDependsOn(t *testing.T) {
+	model := `
+		model
+			schema 1.1
+		type user
+		type group
+			relations
+				define member: [user, group#member]
+		type folder
+			relations
+				define viewer: [user, group#member]
+		type document
+			relations
+				define parent: [folder]
+				define blocked: [user]
+				define owner: [user]
+				define editor: [user] or owner
+				define viewer: ([user] or editor or viewer from parent) but not blocked
+				define auditor: [user]
+	`
+	typeSystem, err := New(testutils.MustTransformDSLToProtoWithID(model))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name                           string
+		objectType, relation           string
+		tupleObjectType, tupleRelation string
+		expected                       bool
+	}{
+		{"same_relation", "document", "viewer", "document", "viewer", true},
+		{"computed_relation", "document", "viewer", "document", "owner", true},
+		{"excluded_relation", "document", "viewer", "document", "blocked", true},
+		{"tupleset_relation", "document", "viewer", "document", "parent", true},
+		{"relation_through_tupleset", "document", "viewer", "folder", "viewer", true},
+		{"userset_through_tupleset", "document", "viewer", "group", "member", true},
+		{"unrelated_relation", "document", "viewer", "document", "auditor", false},
+		{"reverse_direction", "document", "owner", "document", "viewer", false},
+		{"unrelated_type", "group", "member", "document", "viewer", false},
+		{"unknown_relation", "document", "unknown", "document", "viewer", false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.Equal(t, test.expected, typeSystem.RelationDependsOn(test.objectType, test.relation, test.tupleObjectType, test.tupleRelation))
+		})
+	}
+}